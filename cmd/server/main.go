@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	_ "time/tzdata" // user timezones must resolve even on hosts without a zoneinfo database
 
 	"github.com/nas03/scholar-ai/backend/global"
 	"github.com/nas03/scholar-ai/backend/internal/initialize"
@@ -24,6 +25,10 @@ import (
 
 // @schemes   http https
 
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+
 func main() {
 	// Bootstrap all services
 	if err := initialize.Bootstrap(); err != nil {
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/initialize"
+)
+
+// Worker process: consumes the background job queues, runs the reminder
+// notification scanner and sweeps expired uploads. Safe to run several instances side by side.
+func main() {
+	if err := initialize.Bootstrap(); err != nil {
+		if global.Log != nil {
+			global.Log.Sugar().Fatalw("Failed to bootstrap worker", "error", err)
+		}
+		log.Fatal("Failed to bootstrap worker:", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	client := initialize.InitQueueClient()
+	mux := initialize.InitJobHandlers(client)
+
+	var wg sync.WaitGroup
+	for _, w := range initialize.InitQueueWorkers(client, mux) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.Run(ctx)
+		}()
+	}
+
+	if notificationWorker := initialize.InitNotificationWorker(); notificationWorker != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			notificationWorker.Run(ctx)
+		}()
+	}
+
+	uploadSweepWorker := initialize.InitUploadSweepWorker(client)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		uploadSweepWorker.Run(ctx)
+	}()
+
+	<-ctx.Done()
+	global.Log.Info("Shutting down worker, waiting for in-flight jobs")
+	wg.Wait()
+}
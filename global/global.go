@@ -1,7 +1,10 @@
 package global
 
 import (
+	"github.com/nas03/scholar-ai/backend/pkg/ai"
+	"github.com/nas03/scholar-ai/backend/pkg/billing"
 	"github.com/nas03/scholar-ai/backend/pkg/setting"
+	"github.com/nas03/scholar-ai/backend/pkg/storage"
 	"github.com/redis/go-redis/v9"
 	"github.com/resend/resend-go/v2"
 	"go.uber.org/zap"
@@ -9,9 +12,12 @@ import (
 )
 
 var (
-	Config setting.Config
-	Mdb    *gorm.DB
-	Log    *zap.Logger
-	Mail   *resend.Client
-	Redis  *redis.Client
+	Config  setting.Config
+	Mdb     *gorm.DB
+	Log     *zap.Logger
+	Mail    *resend.Client
+	Redis   *redis.Client
+	Storage storage.Storage
+	AI      ai.Provider
+	Billing billing.Provider
 )
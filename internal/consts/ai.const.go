@@ -0,0 +1,27 @@
+package consts
+
+var (
+	// AIProvider names the supported LLM backends in the `ai.provider` setting
+	AIProvider = struct {
+		OPENAI    string
+		ANTHROPIC string
+		FAKE      string
+	}{
+		OPENAI:    "openai",
+		ANTHROPIC: "anthropic",
+		FAKE:      "fake",
+	}
+
+	// AI_LANGUAGES maps the accepted output language codes of AI features to the name used in prompts
+	AI_LANGUAGES = map[string]string{
+		"en": "English",
+		"vi": "Vietnamese",
+		"fr": "French",
+		"de": "German",
+		"es": "Spanish",
+		"ja": "Japanese",
+		"ko": "Korean",
+		"zh": "Simplified Chinese",
+	}
+	AI_DEFAULT_LANGUAGE = "en"
+)
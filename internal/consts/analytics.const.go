@@ -0,0 +1,8 @@
+package consts
+
+var (
+	// DEFAULT_REQUIRED_CREDITS is the degree requirement of users who did not set their own
+	DEFAULT_REQUIRED_CREDITS = 120
+	// COURSE_PASSING_GPA is the lowest course grade on the 4-point scale that earns its credits
+	COURSE_PASSING_GPA = 1.0
+)
@@ -0,0 +1,7 @@
+package consts
+
+const (
+	// ASSESSMENT_WEIGHT_TOLERANCE is how far the weights of a course's
+	// assessments may miss 100, so thirds like 33.33 still add up
+	ASSESSMENT_WEIGHT_TOLERANCE = 0.05
+)
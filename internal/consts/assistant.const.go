@@ -0,0 +1,30 @@
+package consts
+
+var (
+	// AssistantRole mirrors the `role` column of the assistant_messages table
+	AssistantRole = struct {
+		USER      string
+		ASSISTANT string
+	}{
+		USER:      "user",
+		ASSISTANT: "assistant",
+	}
+
+	EMBEDDING_BATCH_SIZE = 64 // chunks per embeddings request
+
+	ASSISTANT_TOP_K             = 6
+	ASSISTANT_MIN_SCORE         = 0.2 // cosine similarity below which a chunk is not relevant
+	ASSISTANT_HISTORY_MESSAGES  = 10  // earlier messages sent along with a question
+	ASSISTANT_MAX_OUTPUT_TOKENS = 1024
+	ASSISTANT_TITLE_RUNES       = 60 // conversations without a title are named after their first question
+	ASSISTANT_SNIPPET_RUNES     = 200
+
+	ASSISTANT_PROMPT = `You are a study assistant helping a student with their own course material.
+Answer using the numbered sources below, which are excerpts from the student's notes and files.
+Cite every fact you take from a source with its number in square brackets, e.g. [1] or [2][3].
+If the sources do not contain the answer, say so, then answer from general knowledge without citations and make clear that you did.
+Answer in the language of the question. Be concise and accurate.
+
+Sources:
+%s`
+)
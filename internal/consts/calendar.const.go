@@ -0,0 +1,10 @@
+package consts
+
+var (
+	CALENDAR_PROD_ID      = "-//Scholar AI//Timetable//EN"
+	CALENDAR_NAME         = "Scholar AI"
+	CALENDAR_UID_DOMAIN   = "scholar-ai" // suffix of event UIDs so re-imports update instead of duplicating
+	CALENDAR_TOKEN_BYTES  = 32           // random bytes in a feed token, hex encoded in the URL
+	CALENDAR_FEED_SUFFIX  = ".ics"
+	CALENDAR_CONTENT_TYPE = "text/calendar; charset=utf-8"
+)
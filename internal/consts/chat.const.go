@@ -0,0 +1,40 @@
+package consts
+
+import "time"
+
+var (
+	// ChatEventType names the events exchanged over a group chat connection
+	ChatEventType = struct {
+		MESSAGE        string
+		TYPING         string
+		PRESENCE       string
+		ERROR          string
+		MEMBER_REMOVED string
+	}{
+		MESSAGE:        "message",
+		TYPING:         "typing",
+		PRESENCE:       "presence",
+		ERROR:          "error",
+		MEMBER_REMOVED: "member_removed",
+	}
+
+	CHAT_MESSAGE_MAX_LENGTH = 4000 // characters
+	CHAT_PAGE_DEFAULT_SIZE  = 50
+	CHAT_PAGE_MAX_SIZE      = 100
+
+	CHAT_HEARTBEAT = 25 * time.Second // ping interval; a client silent for two of them is dropped
+	// CHAT_PRESENCE_TTL is how long a connection counts as online without a
+	// refresh, so instances that die do not leave members online forever
+	CHAT_PRESENCE_TTL     = 90 * time.Second
+	CHAT_PRESENCE_REFRESH = 30 * time.Second
+	// CHAT_TYPING_INTERVAL throttles the typing events one connection relays
+	CHAT_TYPING_INTERVAL = time.Second
+	// CHAT_SEND_BUFFER is how many events may queue for a slow connection before it is dropped
+	CHAT_SEND_BUFFER = 64
+)
+
+const (
+	// WebSocket close codes sent when the server ends a chat connection
+	CHAT_CLOSE_REMOVED     = 4403 // the member left, was removed, or the group was deleted
+	CHAT_CLOSE_UNAVAILABLE = 1012 // the relay was lost; the client should reconnect
+)
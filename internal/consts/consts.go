@@ -23,4 +23,10 @@ var (
 	REDIS_DEFAULT_EXPIRATION = 60 * time.Minute // 1 hour
 
 	REFRESH_TOKEN_COOKIE = "REFRESH_TOKEN"
+
+	DATE_LAYOUT  = "2006-01-02" // calendar dates in requests and DATE columns
+	CLOCK_LAYOUT = "15:04"      // wall-clock times in requests
 )
+
+// UserIDContextKey is the gin context key the auth middleware stores the user ID under
+const UserIDContextKey = "userID"
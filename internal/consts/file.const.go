@@ -0,0 +1,59 @@
+package consts
+
+import "time"
+
+var (
+	// FileStatus mirrors the `status` column of the files table
+	FileStatus = struct {
+		PENDING int8 // presigned upload issued, not confirmed yet
+		READY   int8
+	}{
+		PENDING: 0,
+		READY:   1,
+	}
+
+	// FileExtractionStatus mirrors the `extraction_status` column of the files table
+	FileExtractionStatus = struct {
+		PENDING     int8
+		PROCESSING  int8
+		DONE        int8
+		FAILED      int8
+		UNSUPPORTED int8 // no text layer can be read, e.g. images
+	}{
+		PENDING:     0,
+		PROCESSING:  1,
+		DONE:        2,
+		FAILED:      3,
+		UNSUPPORTED: 4,
+	}
+
+	// UserTier mirrors the `tier` column of the users table and the code of the user's plan
+	UserTier = struct {
+		FREE    string
+		STUDENT string
+		PREMIUM string
+	}{
+		FREE:    "free",
+		STUDENT: "student",
+		PREMIUM: "premium",
+	}
+
+	// STORAGE_DEFAULT_QUOTAS applies when no quotas are configured (bytes per tier)
+	STORAGE_DEFAULT_QUOTAS = map[string]int64{
+		"free":    500 << 20,
+		"student": 10 << 30,
+		"premium": 50 << 30,
+	}
+	STORAGE_DEFAULT_MAX_FILE_SIZE  int64 = 50 << 20
+	STORAGE_DEFAULT_PRESIGN_EXPIRY       = 15 * time.Minute
+	STORAGE_DEFAULT_LOCAL_ROOT           = "./storage"
+	STORAGE_BLOB_PATH                    = "/api/v1/storage" // route serving local presigned URLs
+	STORAGE_STAGING_PREFIX               = "staging/"        // presigned uploads land here until CompleteUpload accepts them
+	STORAGE_UPLOAD_GRACE                 = time.Hour         // an expired presigned upload can still be completed this long
+	STORAGE_SWEEP_INTERVAL               = 10 * time.Minute  // how often the worker removes expired presigned uploads
+	STORAGE_SWEEP_BATCH                  = 500               // expired uploads removed per sweep
+
+	EXTRACT_CHUNK_RUNES   = 2000 // upper bound of a text chunk
+	EXTRACT_CHUNK_OVERLAP = 200  // runes repeated from the end of the previous chunk
+	EXTRACT_ERROR_LENGTH  = 1000
+)
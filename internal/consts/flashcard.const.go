@@ -0,0 +1,37 @@
+package consts
+
+var (
+	// FlashcardGenerationStatus mirrors the `status` column of the flashcard_generations table
+	FlashcardGenerationStatus = struct {
+		PENDING    int8
+		PROCESSING int8
+		DONE       int8
+		FAILED     int8
+	}{
+		PENDING:    0,
+		PROCESSING: 1,
+		DONE:       2,
+		FAILED:     3,
+	}
+
+	FLASHCARD_DEFAULT_COUNT     = 10
+	FLASHCARD_DUE_LIMIT         = 50   // cards returned by GET /flashcards/due by default
+	FLASHCARD_BATCH_TOKENS      = 3000 // source text per generation call
+	FLASHCARD_MAX_BATCHES       = 8    // longer sources are sampled evenly
+	FLASHCARD_MAX_OUTPUT_TOKENS = 2048
+	FLASHCARD_OUTPUT_ATTEMPTS   = 2
+	FLASHCARD_FRONT_MAX_RUNES   = 1000
+	FLASHCARD_BACK_MAX_RUNES    = 4000
+	FLASHCARD_ERROR_LENGTH      = 1000
+
+	// FLASHCARD_PROMPT_VERSION is stored with every generation; bump it whenever the prompt below changes
+	FLASHCARD_PROMPT_VERSION = "flashcards-v1"
+
+	FLASHCARD_PROMPT = `You write study flashcards from a student's course material.
+Write in %s.
+Create exactly %d flashcards from the excerpts below. Each excerpt starts with its chunk number in square brackets.
+Respond with a JSON object of the form {"cards": [{"front": "...", "back": "...", "chunk": 0}]}.
+"front": one clear question or term. "back": a concise, correct answer of at most three sentences.
+"chunk": the number of the excerpt the card is based on.
+Cover the most important facts, definitions and relationships; do not repeat cards. Use only information from the excerpts.`
+)
@@ -0,0 +1,46 @@
+package consts
+
+import "time"
+
+var (
+	// JobType names the background jobs handled by the worker
+	JobType = struct {
+		FILE_EXTRACT       string
+		NOTE_SUMMARIZE     string
+		FLASHCARD_GENERATE string
+		QUIZ_GENERATE      string
+		QUIZ_GRADE         string
+		EMBEDDING_INDEX    string
+		BILLING_DOWNGRADE  string
+	}{
+		FILE_EXTRACT:       "file.extract",
+		NOTE_SUMMARIZE:     "note.summarize",
+		FLASHCARD_GENERATE: "flashcard.generate",
+		QUIZ_GENERATE:      "quiz.generate",
+		QUIZ_GRADE:         "quiz.grade",
+		EMBEDDING_INDEX:    "embedding.index",
+		BILLING_DOWNGRADE:  "billing.downgrade",
+	}
+
+	// JobProgressTopic names what a progress stream follows; the topic of
+	// one row is "<kind>:<id>"
+	JobProgressTopic = struct {
+		FILE                 string
+		SUMMARY              string
+		FLASHCARD_GENERATION string
+		QUIZ                 string
+	}{
+		FILE:                 "file",
+		SUMMARY:              "summary",
+		FLASHCARD_GENERATION: "flashcard_generation",
+		QUIZ:                 "quiz",
+	}
+
+	JOB_PROGRESS_MAX_EVENTS int64 = 200              // events kept per topic
+	JOB_PROGRESS_TTL              = time.Hour        // a topic's events expire this long after the last one
+	JOB_PROGRESS_BLOCK            = 5 * time.Second  // how long one read waits for new events
+	JOB_PROGRESS_MAX_STREAM       = 10 * time.Minute // streams are closed after this; clients resume with Last-Event-ID
+
+	SSE_HEARTBEAT_INTERVAL = 15 * time.Second
+	SSE_EVENT_PROGRESS     = "progress"
+)
@@ -3,3 +3,12 @@ package consts
 const (
 	OTP_VERIFICATION_MAIL = 1
 )
+
+const (
+	REMINDER_NOTIFICATION_MAIL = 2
+)
+
+// REMINDER_NOTIFICATION_FALLBACK_HTML is used when the reminder mail template is missing from the mail table
+const REMINDER_NOTIFICATION_FALLBACK_HTML = `<p>Hi {{.username}},</p>
+<p><strong>{{.title}}</strong>{{.course}} is due on {{.due_at}} UTC ({{.time_left}} left).</p>
+<p>Good luck! - ScholarAI</p>`
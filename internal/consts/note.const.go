@@ -0,0 +1,48 @@
+package consts
+
+import "time"
+
+var (
+	NOTE_MAX_CONTENT_BYTES = 1 << 20 // largest accepted editor document
+	NOTE_MAX_REVISIONS     = 100     // oldest revisions beyond this are pruned
+	NOTE_MAX_TAGS          = 20
+	NOTE_MAX_TAG_LENGTH    = 50
+)
+
+var (
+	// NoteCollabEventType names the events sent over a collaborative editing connection
+	NoteCollabEventType = struct {
+		INIT     string
+		OP       string
+		SNAPSHOT string
+		ERROR    string
+	}{
+		INIT:     "init",
+		OP:       "op",
+		SNAPSHOT: "snapshot",
+		ERROR:    "error",
+	}
+
+	// NOTE_COLLAB_SNAPSHOT_INTERVAL is how often the edits of a session are
+	// saved into the note as a new revision
+	NOTE_COLLAB_SNAPSHOT_INTERVAL = 10 * time.Second
+	// NOTE_COLLAB_CONNECTION_TTL is how long a connection counts as open
+	// without a refresh, so instances that die do not hold sessions open
+	NOTE_COLLAB_CONNECTION_TTL     = 90 * time.Second
+	NOTE_COLLAB_CONNECTION_REFRESH = 30 * time.Second
+	// NOTE_COLLAB_SESSION_TTL drops a session nobody touched for this long
+	NOTE_COLLAB_SESSION_TTL = 24 * time.Hour
+	// NOTE_COLLAB_SUBMIT_ATTEMPTS bounds the retries when other instances
+	// keep appending first
+	NOTE_COLLAB_SUBMIT_ATTEMPTS = 5
+	// NOTE_COLLAB_SEND_BUFFER is how many events may queue for a connection
+	// before it is dropped as too slow; typing produces them in bursts
+	NOTE_COLLAB_SEND_BUFFER = 256
+)
+
+const (
+	// WebSocket close codes sent when the server ends a collaborative editing connection
+	NOTE_COLLAB_CLOSE_FORBIDDEN   = 4403 // the user lost access to the note
+	NOTE_COLLAB_CLOSE_RESYNC      = 4409 // the session moved on without this connection; reconnect for a fresh copy
+	NOTE_COLLAB_CLOSE_UNAVAILABLE = 1012 // the relay was lost; the client should reconnect
+)
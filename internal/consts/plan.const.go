@@ -0,0 +1,54 @@
+package consts
+
+import "time"
+
+var (
+	// SubscriptionStatus mirrors the `status` column of the subscriptions table
+	// and the statuses reported by billing providers
+	SubscriptionStatus = struct {
+		TRIALING string
+		ACTIVE   string
+		PAST_DUE string
+		CANCELED string
+	}{
+		TRIALING: "trialing",
+		ACTIVE:   "active",
+		PAST_DUE: "past_due",
+		CANCELED: "canceled",
+	}
+
+	// BillingProvider names the supported payment backends in the `billing.provider` setting
+	BillingProvider = struct {
+		STRIPE string
+		FAKE   string
+	}{
+		STRIPE: "stripe",
+		FAKE:   "fake",
+	}
+
+	// UsageOperation names the metered AI operations in the usage ledger
+	UsageOperation = struct {
+		NOTE_SUMMARY       string
+		FLASHCARD_GENERATE string
+		QUIZ_GENERATE      string
+		QUIZ_GRADE         string
+		ASSISTANT_MESSAGE  string
+		EMBEDDING_INDEX    string
+	}{
+		NOTE_SUMMARY:       "note.summarize",
+		FLASHCARD_GENERATE: "flashcard.generate",
+		QUIZ_GENERATE:      "quiz.generate",
+		QUIZ_GRADE:         "quiz.grade",
+		ASSISTANT_MESSAGE:  "assistant.message",
+		EMBEDDING_INDEX:    "embedding.index",
+	}
+
+	// USAGE_BACKGROUND_OPERATIONS run on their own whenever notes and files change.
+	// They count towards the plan's tokens but not its operations.
+	USAGE_BACKGROUND_OPERATIONS = []string{UsageOperation.EMBEDDING_INDEX}
+)
+
+const (
+	BILLING_DEFAULT_GRACE_PERIOD = 7 * 24 * time.Hour // a past-due subscription keeps its plan this long
+	BILLING_MAX_WEBHOOK_SIZE     = 1 << 20            // bytes
+)
@@ -0,0 +1,30 @@
+package consts
+
+var (
+	// StudyPlanStatus mirrors the `status` column of the study_plans table
+	StudyPlanStatus = struct {
+		DRAFT      int8
+		ACCEPTED   int8
+		SUPERSEDED int8
+	}{
+		DRAFT:      0,
+		ACCEPTED:   1,
+		SUPERSEDED: 2,
+	}
+
+	// Defaults used for users who never saved study preferences
+	STUDY_DEFAULT_BLOCK_MINUTES     = 50
+	STUDY_DEFAULT_BREAK_MINUTES     = 10
+	STUDY_DEFAULT_MAX_DAILY_MINUTES = 240
+	STUDY_DEFAULT_WEEKDAY_WINDOW    = [2]string{"18:00:00", "22:00:00"} // Monday to Friday
+	STUDY_DEFAULT_WEEKEND_WINDOW    = [2]string{"09:00:00", "17:00:00"} // Saturday and Sunday
+
+	STUDY_PLAN_DEFAULT_HORIZON_DAYS = 28
+	STUDY_PLAN_MAX_HORIZON_DAYS     = 90
+	STUDY_MIN_BLOCK_MINUTES         = 15
+	STUDY_MAX_BLOCK_MINUTES         = 180
+	STUDY_MAX_WINDOWS               = 28
+
+	COURSE_MIN_DIFFICULTY int8 = 1
+	COURSE_MAX_DIFFICULTY int8 = 5
+)
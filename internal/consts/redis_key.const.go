@@ -4,3 +4,26 @@ const (
 	// otp for email verification (%s: user's email)
 	REDIS_KEY_URS_OTP_PREFIX = "usr:%s:otp" //
 )
+
+const (
+	// stream of progress events of a background job (%s: progress topic, e.g. summary:12)
+	REDIS_KEY_JOB_PROGRESS = "job:%s:progress"
+)
+
+const (
+	// pub/sub channel fanning a group's chat events out to every API instance (%d: group id)
+	REDIS_KEY_CHAT_CHANNEL = "chat:group:%d"
+	// sorted set of the group's open chat connections scored by expiry (%d: group id)
+	REDIS_KEY_CHAT_PRESENCE = "chat:group:%d:presence"
+)
+
+const (
+	// collaborative editing session of a note: snapshot revision, latest revision and snapshot document (%d: note id)
+	REDIS_KEY_NOTE_COLLAB_STATE = "note:%d:collab"
+	// operations applied after the session's snapshot, oldest first (%d: note id)
+	REDIS_KEY_NOTE_COLLAB_OPS = "note:%d:collab:ops"
+	// sorted set of the session's open connections scored by expiry (%d: note id)
+	REDIS_KEY_NOTE_COLLAB_CONNECTIONS = "note:%d:collab:connections"
+	// pub/sub channel fanning the session's events out to every API instance (%d: note id)
+	REDIS_KEY_NOTE_COLLAB_CHANNEL = "note:%d:collab:events"
+)
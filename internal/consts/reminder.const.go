@@ -0,0 +1,52 @@
+package consts
+
+import "time"
+
+var (
+	// ReminderType mirrors the `type` column of the reminders table
+	ReminderType = struct {
+		COURSE     int8
+		ASSIGNMENT int8
+		EXAM       int8
+	}{
+		COURSE:     0,
+		ASSIGNMENT: 1,
+		EXAM:       2,
+	}
+
+	// ReminderStatus mirrors the `status` column of the reminders table
+	ReminderStatus = struct {
+		PENDING   int8
+		COMPLETED int8
+		OVERDUE   int8
+	}{
+		PENDING:   0,
+		COMPLETED: 1,
+		OVERDUE:   2,
+	}
+)
+
+var (
+	// NotificationStatus mirrors the `status` column of the reminder_notifications table
+	NotificationStatus = struct {
+		PENDING int8
+		SENDING int8
+		SENT    int8
+		FAILED  int8
+		SKIPPED int8
+	}{
+		PENDING: 0,
+		SENDING: 1,
+		SENT:    2,
+		FAILED:  3,
+		SKIPPED: 4,
+	}
+
+	// Defaults used when the notification config section is left empty
+	NOTIFICATION_DEFAULT_OFFSETS       = []int{7 * 24 * 60, 3 * 24 * 60, 24 * 60} // 1 week, 3 days, 1 day
+	NOTIFICATION_MAX_OFFSET            = 30 * 24 * time.Hour                      // longest offset a user may configure
+	NOTIFICATION_DEFAULT_SCAN_INTERVAL = 60 * time.Second
+	NOTIFICATION_DEFAULT_CLAIM_TIMEOUT = 5 * time.Minute
+	NOTIFICATION_DEFAULT_MAX_ATTEMPTS  = 5
+	NOTIFICATION_DEFAULT_BATCH_SIZE    = 100
+)
@@ -0,0 +1,21 @@
+package consts
+
+var (
+	// SearchType names the kinds of content returned by search
+	SearchType = struct {
+		NOTE      string
+		FILE      string
+		FLASHCARD string
+		COURSE    string
+		REMINDER  string
+	}{
+		NOTE:      "note",
+		FILE:      "file",
+		FLASHCARD: "flashcard",
+		COURSE:    "course",
+		REMINDER:  "reminder",
+	}
+
+	SEARCH_DEFAULT_PAGE_SIZE = 20
+	SEARCH_SNIPPET_RUNES     = 200 // length of the highlighted excerpt of each hit
+)
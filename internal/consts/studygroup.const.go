@@ -0,0 +1,30 @@
+package consts
+
+var (
+	// StudyGroupRole mirrors the `role` column of the study_group_members table;
+	// higher roles include the permissions of lower ones
+	StudyGroupRole = struct {
+		MEMBER int8
+		ADMIN  int8
+		OWNER  int8
+	}{
+		MEMBER: 0,
+		ADMIN:  1,
+		OWNER:  2,
+	}
+
+	STUDY_GROUP_MAX_MEMBERS         = 50
+	STUDY_GROUP_TOKEN_BYTES         = 32 // random bytes in an invitation token, hex encoded in the join link
+	STUDY_GROUP_INVITE_DEFAULT_DAYS = 7  // days an invitation stays valid unless set
+	STUDY_GROUP_INVITE_MAX_DAYS     = 30
+)
+
+const (
+	STUDY_GROUP_INVITATION_MAIL = 3
+)
+
+// STUDY_GROUP_INVITATION_FALLBACK_HTML is used when the invitation mail template is missing from the mail table
+const STUDY_GROUP_INVITATION_FALLBACK_HTML = `<p>Hi,</p>
+<p>{{.inviter}} invited you to the study group <strong>{{.group}}</strong> ({{.course}}).</p>
+<p><a href="{{.join_url}}">Join the group</a> before {{.expires_at}} UTC.</p>
+<p>- ScholarAI</p>`
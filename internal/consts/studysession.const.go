@@ -0,0 +1,16 @@
+package consts
+
+import "time"
+
+var (
+	// STUDY_SESSION_MAX_DURATION caps a session, so a timer left running
+	// overnight does not count as a day of study
+	STUDY_SESSION_MAX_DURATION = 12 * time.Hour
+
+	STUDY_TIME_DEFAULT_RANGE_DAYS = 56 // eight weeks
+	STUDY_TIME_MAX_RANGE_DAYS     = 366
+
+	// STUDY_CORRELATION_MIN_COURSES is how many graded courses a study time
+	// and grade correlation needs to mean anything
+	STUDY_CORRELATION_MIN_COURSES = 3
+)
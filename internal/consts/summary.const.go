@@ -0,0 +1,39 @@
+package consts
+
+var (
+	// SummaryStatus mirrors the `status` column of the note_summaries table
+	SummaryStatus = struct {
+		PENDING    int8
+		PROCESSING int8
+		DONE       int8
+		FAILED     int8
+	}{
+		PENDING:    0,
+		PROCESSING: 1,
+		DONE:       2,
+		FAILED:     3,
+	}
+
+	SUMMARY_CHUNK_TOKENS      = 3000 // note text per map call; partial summaries are merged in batches of the same size
+	SUMMARY_CHUNK_OVERLAP     = 200  // runes repeated between consecutive parts
+	SUMMARY_MAX_OUTPUT_TOKENS = 1024
+	SUMMARY_ERROR_LENGTH      = 1000
+	SUMMARY_OUTPUT_ATTEMPTS   = 2 // requests per call before an invalid reply fails the summary
+
+	// SUMMARY_PROMPT_VERSION is stored with every summary; bump it whenever the prompts below change
+	SUMMARY_PROMPT_VERSION = "summary-v1"
+
+	SUMMARY_MAP_PROMPT = `You summarize one part of a student's lecture notes.
+Write in %s.
+Respond with a JSON object of the form {"bullets": ["..."], "key_concepts": [{"term": "...", "explanation": "..."}]}.
+"bullets": at most 8 short, factual points covering the main ideas of this part, in the order they appear.
+"key_concepts": at most 6 terms a student must know, each explained in one sentence.
+Use only information from the notes.`
+
+	SUMMARY_REDUCE_PROMPT = `You merge partial summaries of one set of lecture notes into a single summary.
+Write in %s.
+Respond with a JSON object of the form {"bullets": ["..."], "key_concepts": [{"term": "...", "explanation": "..."}]}.
+"bullets": at most 10 short points, merging duplicates and keeping the order of the lecture.
+"key_concepts": at most 8 of the most important terms, each explained in one sentence.
+Use only information from the partial summaries.`
+)
@@ -0,0 +1,33 @@
+package consts
+
+var (
+	// ClassSessionRecurrence mirrors the `recurrence` column of the class_sessions table
+	ClassSessionRecurrence = struct {
+		WEEKLY   int8
+		BIWEEKLY int8
+	}{
+		WEEKLY:   0,
+		BIWEEKLY: 1,
+	}
+
+	// TimetableImportAction describes what an import does with a course or session
+	TimetableImportAction = struct {
+		EXISTING  string
+		CREATE    string
+		UPDATE    string
+		UNCHANGED string
+		DELETE    string
+	}{
+		EXISTING:  "existing",
+		CREATE:    "create",
+		UPDATE:    "update",
+		UNCHANGED: "unchanged",
+		DELETE:    "delete",
+	}
+
+	TIMETABLE_MAX_RANGE_DAYS         = 366     // longest range the weekly timetable endpoint expands
+	TIMETABLE_CONFLICT_HORIZON       = 366     // days checked for clashes between open-ended sessions
+	TIMETABLE_IMPORT_MAX_BYTES int64 = 2 << 20 // largest accepted .ics upload
+
+	TIMETABLE_IMPORT_DEFAULT_SOURCE = "calendar" // source of imported files that name neither calendar nor producer
+)
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type AnalyticsController struct {
+	analyticsService services.IAnalyticsService
+}
+
+func NewAnalyticsController(analyticsService services.IAnalyticsService) *AnalyticsController {
+	return &AnalyticsController{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetGPATrend godoc
+// @Summary      Get the GPA trend
+// @Description  Credit-weighted GPA per semester and cumulative up to it, oldest semester first. Courses without a grade are left out.
+// @Tags         analytics
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.ResponseData  "GPA per semester"
+// @Router       /analytics/gpa-trend [get]
+func (c *AnalyticsController) GetGPATrend(ctx *gin.Context) {
+	semesters, code := c.analyticsService.GetGPATrend(ctx, ctx.GetString(consts.UserIDContextKey))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, semesters)
+}
+
+// GetPerformance godoc
+// @Summary      Get performance per tag and subject
+// @Description  GPA, credits and best/worst grade of the courses sharing a tag, and of those sharing a subject (the letters the course code starts with)
+// @Tags         analytics
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.ResponseData  "Performance breakdown"
+// @Router       /analytics/performance [get]
+func (c *AnalyticsController) GetPerformance(ctx *gin.Context) {
+	breakdown, code := c.analyticsService.GetPerformance(ctx, ctx.GetString(consts.UserIDContextKey))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, breakdown)
+}
+
+// GetCreditProgress godoc
+// @Summary      Get credits earned vs required
+// @Description  Credits earned, in progress, failed and missing a grade, against the degree requirement (120 unless set)
+// @Tags         analytics
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.ResponseData  "Credit progress"
+// @Router       /analytics/credits [get]
+func (c *AnalyticsController) GetCreditProgress(ctx *gin.Context) {
+	progress, code := c.analyticsService.GetCreditProgress(ctx, ctx.GetString(consts.UserIDContextKey))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, progress)
+}
+
+// UpdateRequiredCredits godoc
+// @Summary      Set the required credits
+// @Description  Set the credits the user's degree requires; null restores the default
+// @Tags         analytics
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.UpdateRequiredCreditsRequest  true  "Required credits"
+// @Success      200      {object}  response.ResponseData                "Credit progress"
+// @Router       /analytics/credits [put]
+func (c *AnalyticsController) UpdateRequiredCredits(ctx *gin.Context) {
+	var payload models.UpdateRequiredCreditsRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	progress, code := c.analyticsService.UpdateRequiredCredits(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, progress)
+}
+
+// GetForecasts godoc
+// @Summary      Forecast final course grades
+// @Description  Projected final score and grade of every course without a final grade, from its scored assessments, or its scored, weighted exams when it has no assessments. The low-high range holds the final score with 80% confidence if the rest of the course goes like the graded part; min and max assume 0 or 100 on everything left.
+// @Tags         analytics
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.ResponseData  "Grade forecasts"
+// @Router       /analytics/forecasts [get]
+func (c *AnalyticsController) GetForecasts(ctx *gin.Context) {
+	forecasts, code := c.analyticsService.GetForecasts(ctx, ctx.GetString(consts.UserIDContextKey))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, forecasts)
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type AssessmentController struct {
+	assessmentService services.IAssessmentService
+}
+
+func NewAssessmentController(assessmentService services.IAssessmentService) *AssessmentController {
+	return &AssessmentController{
+		assessmentService: assessmentService,
+	}
+}
+
+// GetAssessments godoc
+// @Summary      Get a course's assessments
+// @Description  The course's graded components with the grade so far and, for each step of the 4-point scale, the average score needed on the ungraded components to reach it
+// @Tags         assessments
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int                    true  "Course ID"
+// @Success      200  {object}  response.ResponseData  "Assessments and grade summary"
+// @Failure      200  {object}  response.ResponseData  "Error response (course not found)"
+// @Router       /courses/{id}/assessments [get]
+func (c *AssessmentController) GetAssessments(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid course id")
+		return
+	}
+
+	summary, code := c.assessmentService.GetAssessments(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, summary)
+}
+
+// ReplaceAssessments godoc
+// @Summary      Set a course's assessments
+// @Description  Replace all assessments of the course. Weights must add up to 100; assessments sent with an id are updated, those left out are deleted and an empty list removes them all. Once every assessment is scored the final grade sets the course's GPA.
+// @Tags         assessments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                               true  "Course ID"
+// @Param        request  body      models.ReplaceAssessmentsRequest  true  "Assessments"
+// @Success      200      {object}  response.ResponseData             "Assessments and grade summary"
+// @Failure      200      {object}  response.ResponseData             "Error response (weights do not add up to 100)"
+// @Router       /courses/{id}/assessments [put]
+func (c *AssessmentController) ReplaceAssessments(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid course id")
+		return
+	}
+
+	var payload models.ReplaceAssessmentsRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	summary, code := c.assessmentService.ReplaceAssessments(ctx, ctx.GetString(consts.UserIDContextKey), id, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, summary)
+}
+
+// RecordScore godoc
+// @Summary      Record an assessment score
+// @Description  Set the score obtained on an assessment, at most its max score; null clears it
+// @Tags         assessments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id             path      int                                  true  "Course ID"
+// @Param        assessment_id  path      int                                  true  "Assessment ID"
+// @Param        request        body      models.RecordAssessmentScoreRequest  true  "Score"
+// @Success      200            {object}  response.ResponseData                "Assessments and grade summary"
+// @Failure      200            {object}  response.ResponseData                "Error response (assessment not found)"
+// @Router       /courses/{id}/assessments/{assessment_id}/score [put]
+func (c *AssessmentController) RecordScore(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid course id")
+		return
+	}
+	assessmentID, err := strconv.Atoi(ctx.Param("assessment_id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid assessment id")
+		return
+	}
+
+	var payload models.RecordAssessmentScoreRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	summary, code := c.assessmentService.RecordScore(ctx, ctx.GetString(consts.UserIDContextKey), id, assessmentID, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, summary)
+}
@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type AssistantController struct {
+	assistantService services.IAssistantService
+}
+
+func NewAssistantController(assistantService services.IAssistantService) *AssistantController {
+	return &AssistantController{
+		assistantService: assistantService,
+	}
+}
+
+// CreateConversation godoc
+// @Summary      Start an assistant conversation
+// @Description  Start a conversation with the study assistant. With a course_id, answers only draw on that course's notes and files.
+// @Tags         assistant
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.CreateConversationRequest  true  "Optional course and title"
+// @Success      200      {object}  response.ResponseData             "New conversation"
+// @Failure      200      {object}  response.ResponseData             "Error response (course not found)"
+// @Router       /assistant/conversations [post]
+func (c *AssistantController) CreateConversation(ctx *gin.Context) {
+	var payload models.CreateConversationRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	conversation, code := c.assistantService.CreateConversation(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, conversation)
+}
+
+// ListConversations godoc
+// @Summary      List assistant conversations
+// @Tags         assistant
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.ResponseData  "List of conversations, most recently active first"
+// @Router       /assistant/conversations [get]
+func (c *AssistantController) ListConversations(ctx *gin.Context) {
+	conversations, code := c.assistantService.ListConversations(ctx, ctx.GetString(consts.UserIDContextKey))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, conversations)
+}
+
+// GetConversation godoc
+// @Summary      Get an assistant conversation
+// @Description  Get the conversation with its messages, oldest first
+// @Tags         assistant
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Conversation ID"
+// @Success      200  {object}  response.ResponseData  "Conversation with messages"
+// @Failure      200  {object}  response.ResponseData  "Error response (conversation not found)"
+// @Router       /assistant/conversations/{id} [get]
+func (c *AssistantController) GetConversation(ctx *gin.Context) {
+	id, ok := conversationID(ctx)
+	if !ok {
+		return
+	}
+
+	conversation, code := c.assistantService.GetConversation(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, conversation)
+}
+
+// DeleteConversation godoc
+// @Summary      Delete an assistant conversation
+// @Tags         assistant
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Conversation ID"
+// @Success      200  {object}  response.ResponseData  "Conversation deleted"
+// @Failure      200  {object}  response.ResponseData  "Error response (conversation not found)"
+// @Router       /assistant/conversations/{id} [delete]
+func (c *AssistantController) DeleteConversation(ctx *gin.Context) {
+	id, ok := conversationID(ctx)
+	if !ok {
+		return
+	}
+
+	code := c.assistantService.DeleteConversation(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, nil)
+}
+
+// SendMessage godoc
+// @Summary      Ask the study assistant
+// @Description  Answer a question from the user's notes and files with numbered citations. With Accept: text/event-stream the answer is streamed as "delta" events followed by a "done" event carrying the stored reply, or an "error" event with the response code. Disconnecting stops generation.
+// @Tags         assistant
+// @Accept       json
+// @Produce      json
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        id       path      int                         true  "Conversation ID"
+// @Param        request  body      models.SendMessageRequest   true  "Question"
+// @Success      200      {object}  response.ResponseData       "Question, answer and citations"
+// @Failure      200      {object}  response.ResponseData       "Error response (conversation not found, reply failed)"
+// @Router       /assistant/conversations/{id}/messages [post]
+func (c *AssistantController) SendMessage(ctx *gin.Context) {
+	id, ok := conversationID(ctx)
+	if !ok {
+		return
+	}
+	var payload models.SendMessageRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+	userID := ctx.GetString(consts.UserIDContextKey)
+
+	if !strings.Contains(ctx.GetHeader("Accept"), "text/event-stream") {
+		reply, code := c.assistantService.SendMessage(ctx, userID, id, &payload, nil)
+		if code != response.CodeSuccess {
+			response.ErrorResponse(ctx, code, "")
+			return
+		}
+		response.SuccessResponse(ctx, code, reply)
+		return
+	}
+
+	_ = response.StreamSSE(ctx, consts.SSE_HEARTBEAT_INTERVAL, func(streamCtx context.Context, stream *response.SSEStream) error {
+		// A client that disconnects cancels generation
+		reply, code := c.assistantService.SendMessage(streamCtx, userID, id, &payload, func(delta string) error {
+			return stream.Send(response.SSEEvent{Event: "delta", Data: gin.H{"text": delta}})
+		})
+		if code != response.CodeSuccess {
+			return stream.Error(code)
+		}
+		return stream.Send(response.SSEEvent{Event: "done", Data: reply})
+	})
+}
+
+func conversationID(ctx *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid conversation id")
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type BillingController struct {
+	billingService services.IBillingService
+}
+
+func NewBillingController(billingService services.IBillingService) *BillingController {
+	return &BillingController{
+		billingService: billingService,
+	}
+}
+
+// CreateCheckout godoc
+// @Summary      Start a checkout
+// @Description  Create a hosted checkout session of the billing provider for a paid plan and redirect the user to its URL. The plan changes once the provider confirms the payment; poll GET /billing/subscription to see it. First-time subscribers may get a trial.
+// @Tags         billing
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.CreateCheckoutRequest  true  "Plan to subscribe to"
+// @Success      200      {object}  response.ResponseData         "Checkout session"
+// @Failure      200      {object}  response.ResponseData         "Error response (plan not purchasable, already subscribed, checkout failed)"
+// @Router       /billing/checkout [post]
+func (c *BillingController) CreateCheckout(ctx *gin.Context) {
+	var payload models.CreateCheckoutRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	session, code := c.billingService.CreateCheckout(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, session)
+}
+
+// GetSubscription godoc
+// @Summary      Get the current subscription
+// @Description  Status (trialing, active, past_due or canceled), current period and, for past-due or canceled subscriptions, when the plan drops to free. Content is null for users who never subscribed.
+// @Tags         billing
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.ResponseData  "Subscription"
+// @Router       /billing/subscription [get]
+func (c *BillingController) GetSubscription(ctx *gin.Context) {
+	subscription, code := c.billingService.GetSubscription(ctx, ctx.GetString(consts.UserIDContextKey))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, subscription)
+}
+
+// HandleWebhook godoc
+// @Summary      Receive billing provider webhooks
+// @Description  Called by the billing provider, authorized by its signature header. Responds with plain HTTP statuses: providers retry anything but 2xx, so only a failure to apply a valid event is a 5xx.
+// @Tags         billing
+// @Accept       json
+// @Success      200  "Event applied, or already applied"
+// @Failure      400  "Invalid signature or payload"
+// @Failure      413  "Payload too large"
+// @Failure      500  "Event could not be applied, retry"
+// @Router       /billing/webhook [post]
+func (c *BillingController) HandleWebhook(ctx *gin.Context) {
+	payload, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, consts.BILLING_MAX_WEBHOOK_SIZE))
+	if err != nil {
+		ctx.Status(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	switch c.billingService.HandleWebhook(ctx, payload, ctx.Request.Header) {
+	case response.CodeSuccess:
+		ctx.Status(http.StatusOK)
+	case response.CodeInvalidWebhook:
+		ctx.Status(http.StatusBadRequest)
+	default:
+		ctx.Status(http.StatusInternalServerError)
+	}
+}
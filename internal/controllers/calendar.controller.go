@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type CalendarController struct {
+	calendarService services.ICalendarService
+}
+
+func NewCalendarController(calendarService services.ICalendarService) *CalendarController {
+	return &CalendarController{
+		calendarService: calendarService,
+	}
+}
+
+// GetFeed godoc
+// @Summary      Get the calendar subscription URL
+// @Description  Return the secret iCalendar URL for Google/Apple Calendar. The feed is created on first request.
+// @Tags         calendar
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.ResponseData  "Subscription URL and timezone"
+// @Router       /calendar/feed [get]
+func (c *CalendarController) GetFeed(ctx *gin.Context) {
+	feed, code := c.calendarService.GetFeed(ctx, ctx.GetString(consts.UserIDContextKey))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, withRequestOrigin(ctx, feed))
+}
+
+// RotateFeedToken godoc
+// @Summary      Rotate the calendar subscription URL
+// @Description  Issue a new secret token. Calendar apps subscribed with the old URL stop receiving updates.
+// @Tags         calendar
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.ResponseData  "New subscription URL"
+// @Router       /calendar/feed/rotate [post]
+func (c *CalendarController) RotateFeedToken(ctx *gin.Context) {
+	feed, code := c.calendarService.RotateFeedToken(ctx, ctx.GetString(consts.UserIDContextKey))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, withRequestOrigin(ctx, feed))
+}
+
+// UpdateTimezone godoc
+// @Summary      Set the user's timezone
+// @Description  Class session times and reminder deadlines are interpreted and exported in this IANA timezone. Pending reminder notifications are rescheduled for the moved deadlines.
+// @Tags         calendar
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.UpdateTimezoneRequest  true  "Timezone"
+// @Success      200      {object}  response.ResponseData         "Subscription URL and timezone"
+// @Failure      200      {object}  response.ResponseData         "Error response (invalid timezone)"
+// @Router       /calendar/timezone [put]
+func (c *CalendarController) UpdateTimezone(ctx *gin.Context) {
+	var payload models.UpdateTimezoneRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	feed, code := c.calendarService.UpdateTimezone(ctx, ctx.GetString(consts.UserIDContextKey), payload.Timezone)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, withRequestOrigin(ctx, feed))
+}
+
+// ExportCalendar godoc
+// @Summary      Download the calendar
+// @Description  Download class sessions, reminders and exams as an .ics file.
+// @Tags         calendar
+// @Produce      text/calendar
+// @Security     BearerAuth
+// @Success      200  {string}  string  "iCalendar document"
+// @Router       /calendar/export.ics [get]
+func (c *CalendarController) ExportCalendar(ctx *gin.Context) {
+	body, code := c.calendarService.ExportCalendar(ctx, ctx.GetString(consts.UserIDContextKey))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="scholar-ai.ics"`)
+	ctx.Data(http.StatusOK, consts.CALENDAR_CONTENT_TYPE, body)
+}
+
+// GetFeedCalendar godoc
+// @Summary      Calendar subscription feed
+// @Description  Public iCalendar feed polled by calendar apps. Authenticated by the secret token in the URL instead of a JWT.
+// @Tags         calendar
+// @Produce      text/calendar
+// @Param        token  path      string  true  "Feed token followed by .ics"
+// @Success      200    {string}  string  "iCalendar document"
+// @Failure      404    {string}  string  "Unknown or rotated token"
+// @Router       /calendar/{token} [get]
+func (c *CalendarController) GetFeedCalendar(ctx *gin.Context) {
+	token, ok := strings.CutSuffix(ctx.Param("token"), consts.CALENDAR_FEED_SUFFIX)
+	if !ok || token == "" {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+
+	body, code := c.calendarService.ExportFeed(ctx, token)
+	switch code {
+	case response.CodeSuccess:
+		ctx.Header("Content-Disposition", `inline; filename="scholar-ai.ics"`)
+		ctx.Header("Cache-Control", "private, max-age=300")
+		ctx.Data(http.StatusOK, consts.CALENDAR_CONTENT_TYPE, body)
+	case response.CodeCalendarFeedNotFound, response.CodeUserNotFound:
+		// Calendar apps only understand HTTP status codes, not the JSON envelope
+		ctx.Status(http.StatusNotFound)
+	default:
+		ctx.Status(http.StatusServiceUnavailable)
+	}
+}
+
+// withRequestOrigin turns a relative feed URL into an absolute one using the
+// request host when no public base URL is configured
+func withRequestOrigin(ctx *gin.Context, feed *models.CalendarFeedResponse) *models.CalendarFeedResponse {
+	feed.URL = absoluteURL(ctx, feed.URL)
+	return feed
+}
+
+// absoluteURL prefixes a path with the scheme and host the request came in on
+func absoluteURL(ctx *gin.Context, path string) string {
+	if !strings.HasPrefix(path, "/") {
+		return path
+	}
+
+	scheme := "http"
+	if ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + ctx.Request.Host + path
+}
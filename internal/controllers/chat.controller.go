@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type ChatController struct {
+	chatService services.IChatService
+}
+
+func NewChatController(chatService services.IChatService) *ChatController {
+	return &ChatController{
+		chatService: chatService,
+	}
+}
+
+// ListMessages godoc
+// @Summary      List chat messages
+// @Description  The group's messages newest first. Pass next_before as before to page back through older ones.
+// @Tags         study-groups
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int                    true   "Study group ID"
+// @Param        before  query     int                    false  "Only messages with a smaller id"
+// @Param        limit   query     int                    false  "Page size (default 50, max 100)"
+// @Success      200     {object}  response.ResponseData  "Page of messages"
+// @Failure      200     {object}  response.ResponseData  "Error response (study group not found)"
+// @Router       /study-groups/{id}/messages [get]
+func (c *ChatController) ListMessages(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+	var query models.ChatMessageQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	page, code := c.chatService.ListMessages(ctx, ctx.GetString(consts.UserIDContextKey), id, &query)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, page)
+}
+
+// SendMessage godoc
+// @Summary      Send a chat message
+// @Description  Post a message without a chat connection; connected members receive it as a message event
+// @Tags         study-groups
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                            true  "Study group ID"
+// @Param        request  body      models.SendChatMessageRequest  true  "Message"
+// @Success      200      {object}  response.ResponseData          "Stored message"
+// @Failure      200      {object}  response.ResponseData          "Error response (study group not found, message invalid)"
+// @Router       /study-groups/{id}/messages [post]
+func (c *ChatController) SendMessage(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+	var payload models.SendChatMessageRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	message, code := c.chatService.SendMessage(ctx, ctx.GetString(consts.UserIDContextKey), id, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, message)
+}
+
+// Connect godoc
+// @Summary      Open the group chat
+// @Description  Upgrades to a WebSocket. Browsers pass the access token as the token parameter. Send {"type":"message","body":"...","client_id":"..."} or {"type":"typing","typing":true}; receive message, typing, presence, member_removed and error events (models.ChatEvent). The connection is closed with code 4403 once the user is no longer a member.
+// @Tags         study-groups
+// @Security     BearerAuth
+// @Param        id     path   int     true   "Study group ID"
+// @Param        token  query  string  false  "Access token, when the Authorization header cannot be set"
+// @Success      101    "Switching protocols"
+// @Failure      200    {object}  response.ResponseData  "Error response (study group not found)"
+// @Router       /study-groups/{id}/chat [get]
+func (c *ChatController) Connect(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+	userID := ctx.GetString(consts.UserIDContextKey)
+
+	// Refuse before upgrading, so the client gets a normal error response
+	if code := c.chatService.CheckAccess(ctx, userID, id); code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+
+	_ = response.ServeWebSocket(ctx, consts.CHAT_HEARTBEAT, consts.CHAT_SEND_BUFFER, func(wsCtx context.Context, ws *response.WebSocketConn) error {
+		sendError := func(code int, clientID string) {
+			_ = ws.Send(models.ChatEvent{Type: consts.ChatEventType.ERROR, GroupID: id, ClientID: clientID, Code: code, Error: response.GetMessageByCode(code)})
+		}
+
+		leave, code := c.chatService.Connect(wsCtx, &services.ChatClient{
+			GroupID: id,
+			UserID:  userID,
+			Send:    func(event models.ChatEvent) error { return ws.Send(event) },
+			Kick:    ws.Close,
+		})
+		if code != response.CodeSuccess {
+			sendError(code, "")
+			ws.Close(consts.CHAT_CLOSE_UNAVAILABLE, "chat unavailable")
+			return nil
+		}
+		defer leave()
+
+		var lastTyping time.Time
+		for {
+			var frame models.ChatClientFrame
+			if err := ws.Receive(&frame); err != nil {
+				if errors.Is(err, response.ErrWebSocketFrame) {
+					sendError(response.CodeChatFrameInvalid, "")
+					continue
+				}
+				return nil
+			}
+
+			switch frame.Type {
+			case consts.ChatEventType.MESSAGE:
+				payload := models.SendChatMessageRequest{Body: frame.Body, ClientID: frame.ClientID}
+				if _, code := c.chatService.SendMessage(wsCtx, userID, id, &payload); code != response.CodeSuccess {
+					sendError(code, frame.ClientID)
+				}
+			case consts.ChatEventType.TYPING:
+				// Stopping is always relayed, starting at most once per interval
+				if frame.Typing && time.Since(lastTyping) < consts.CHAT_TYPING_INTERVAL {
+					continue
+				}
+				if frame.Typing {
+					lastTyping = time.Now()
+				}
+				c.chatService.SetTyping(wsCtx, userID, id, frame.Typing)
+			default:
+				sendError(response.CodeChatFrameInvalid, frame.ClientID)
+			}
+		}
+	})
+}
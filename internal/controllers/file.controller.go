@@ -0,0 +1,304 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type FileController struct {
+	fileService     services.IFileService
+	progressService services.IProgressService
+}
+
+func NewFileController(fileService services.IFileService, progressService services.IProgressService) *FileController {
+	return &FileController{
+		fileService:     fileService,
+		progressService: progressService,
+	}
+}
+
+// UploadFile godoc
+// @Summary      Upload a file
+// @Description  Upload a PDF, Office document, image or text file through the API. The type is detected from the content; executables and files whose content does not match their extension are rejected.
+// @Tags         files
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file       formData  file  true   "File to upload"
+// @Param        course_id  formData  int   false  "Course the file belongs to"
+// @Success      200        {object}  response.ResponseData  "Uploaded file"
+// @Failure      200        {object}  response.ResponseData  "Error response (type not allowed, too large, quota exceeded, etc.)"
+// @Router       /files [post]
+func (c *FileController) UploadFile(ctx *gin.Context) {
+	var payload models.UploadFileRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	file, code := c.fileService.UploadFile(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, file)
+}
+
+// CreateUpload godoc
+// @Summary      Start a direct upload
+// @Description  Reserve quota and get a presigned URL to PUT the file body to object storage, with the returned headers. Confirm with POST /files/{id}/complete once the upload has finished.
+// @Tags         files
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.CreateFileUploadRequest  true  "File name and size"
+// @Success      200      {object}  response.ResponseData           "Pending file and upload instructions"
+// @Failure      200      {object}  response.ResponseData           "Error response (type not allowed, too large, quota exceeded, etc.)"
+// @Router       /files/uploads [post]
+func (c *FileController) CreateUpload(ctx *gin.Context) {
+	var payload models.CreateFileUploadRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	upload, code := c.fileService.CreateUpload(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	upload.UploadURL = absoluteURL(ctx, upload.UploadURL)
+	response.SuccessResponse(ctx, code, upload)
+}
+
+// CompleteUpload godoc
+// @Summary      Complete a direct upload
+// @Description  Verify the uploaded object's size and content type and mark the file ready. Rejected uploads are deleted. Each upload ID completes once; uploads that are not completed within an hour of the URL expiring are deleted.
+// @Tags         files
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                               true  "File ID"
+// @Param        request  body      models.CompleteFileUploadRequest  true  "Upload ID returned when the upload was started"
+// @Success      200      {object}  response.ResponseData             "Ready file"
+// @Failure      200      {object}  response.ResponseData             "Error response (unknown or completed upload, not uploaded yet, type not allowed, quota exceeded, etc.)"
+// @Router       /files/{id}/complete [post]
+func (c *FileController) CompleteUpload(ctx *gin.Context) {
+	id, ok := fileID(ctx)
+	if !ok {
+		return
+	}
+
+	var payload models.CompleteFileUploadRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	file, code := c.fileService.CompleteUpload(ctx, ctx.GetString(consts.UserIDContextKey), id, payload.UploadID)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, file)
+}
+
+// ListFiles godoc
+// @Summary      List uploaded files
+// @Tags         files
+// @Produce      json
+// @Security     BearerAuth
+// @Param        course_id  query     int  false  "Filter by course"
+// @Success      200        {object}  response.ResponseData  "List of files"
+// @Router       /files [get]
+func (c *FileController) ListFiles(ctx *gin.Context) {
+	var query models.FileQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	files, code := c.fileService.ListFiles(ctx, ctx.GetString(consts.UserIDContextKey), &query)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, files)
+}
+
+// GetUsage godoc
+// @Summary      Get storage usage
+// @Description  Bytes used by the user's files against the quota of their tier
+// @Tags         files
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.ResponseData  "Storage usage"
+// @Router       /files/usage [get]
+func (c *FileController) GetUsage(ctx *gin.Context) {
+	usage, code := c.fileService.GetUsage(ctx, ctx.GetString(consts.UserIDContextKey))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, usage)
+}
+
+// GetFile godoc
+// @Summary      Get a file
+// @Tags         files
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "File ID"
+// @Success      200  {object}  response.ResponseData  "File metadata"
+// @Failure      200  {object}  response.ResponseData  "Error response (file not found)"
+// @Router       /files/{id} [get]
+func (c *FileController) GetFile(ctx *gin.Context) {
+	id, ok := fileID(ctx)
+	if !ok {
+		return
+	}
+
+	file, code := c.fileService.GetFile(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, file)
+}
+
+// GetDownloadURL godoc
+// @Summary      Get a download URL
+// @Description  Returns a short-lived presigned URL that downloads the file under its original name
+// @Tags         files
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "File ID"
+// @Success      200  {object}  response.ResponseData  "Presigned URL and expiry"
+// @Failure      200  {object}  response.ResponseData  "Error response (file not found or not ready)"
+// @Router       /files/{id}/download [get]
+func (c *FileController) GetDownloadURL(ctx *gin.Context) {
+	id, ok := fileID(ctx)
+	if !ok {
+		return
+	}
+
+	download, code := c.fileService.GetDownloadURL(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	download.URL = absoluteURL(ctx, download.URL)
+	response.SuccessResponse(ctx, code, download)
+}
+
+// DeleteFile godoc
+// @Summary      Delete a file
+// @Tags         files
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "File ID"
+// @Success      200  {object}  response.ResponseData  "File deleted"
+// @Failure      200  {object}  response.ResponseData  "Error response (file not found)"
+// @Router       /files/{id} [delete]
+func (c *FileController) DeleteFile(ctx *gin.Context) {
+	id, ok := fileID(ctx)
+	if !ok {
+		return
+	}
+
+	code := c.fileService.DeleteFile(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, nil)
+}
+
+// RequestExtraction godoc
+// @Summary      Re-run text extraction
+// @Description  Queue the file's text extraction again. Progress is reported in extraction_status (0 pending, 1 processing, 2 done, 3 failed, 4 unsupported) and extraction_error.
+// @Tags         files
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "File ID"
+// @Success      200  {object}  response.ResponseData  "File with its extraction status"
+// @Failure      200  {object}  response.ResponseData  "Error response (file not found or not ready)"
+// @Router       /files/{id}/extract [post]
+func (c *FileController) RequestExtraction(ctx *gin.Context) {
+	id, ok := fileID(ctx)
+	if !ok {
+		return
+	}
+
+	file, code := c.fileService.RequestExtraction(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, file)
+}
+
+// StreamExtractionProgress godoc
+// @Summary      Follow text extraction
+// @Description  Stream the file's extraction progress as Server-Sent Events named "progress" until one has final set; status is the extraction_status. Reconnecting with Last-Event-ID resumes after that event; once extraction has finished a reconnect gets 204.
+// @Tags         files
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        id             path      int     true   "File ID"
+// @Param        Last-Event-ID  header    string  false  "ID of the last event received"
+// @Success      200            {object}  models.JobProgress     "Progress events"
+// @Failure      200            {object}  response.ResponseData  "Error response (file not found)"
+// @Router       /files/{id}/extract/events [get]
+func (c *FileController) StreamExtractionProgress(ctx *gin.Context) {
+	id, ok := fileID(ctx)
+	if !ok {
+		return
+	}
+
+	file, code := c.fileService.GetFile(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	status := file.ExtractionStatus
+	final := status == consts.FileExtractionStatus.DONE || status == consts.FileExtractionStatus.FAILED || status == consts.FileExtractionStatus.UNSUPPORTED
+	streamProgress(ctx, c.progressService, consts.JobProgressTopic.FILE, file.ID, jobState(status, final, file.ExtractionError))
+}
+
+// ListChunks godoc
+// @Summary      List extracted text chunks
+// @Description  The file's extracted text in reading order, split into page-aware chunks
+// @Tags         files
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "File ID"
+// @Success      200  {object}  response.ResponseData  "List of chunks"
+// @Failure      200  {object}  response.ResponseData  "Error response (file not found)"
+// @Router       /files/{id}/chunks [get]
+func (c *FileController) ListChunks(ctx *gin.Context) {
+	id, ok := fileID(ctx)
+	if !ok {
+		return
+	}
+
+	chunks, code := c.fileService.ListChunks(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, chunks)
+}
+
+func fileID(ctx *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid file id")
+		return 0, false
+	}
+	return id, true
+}
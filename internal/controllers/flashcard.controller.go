@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type FlashcardController struct {
+	flashcardService services.IFlashcardService
+	progressService  services.IProgressService
+}
+
+func NewFlashcardController(flashcardService services.IFlashcardService, progressService services.IProgressService) *FlashcardController {
+	return &FlashcardController{
+		flashcardService: flashcardService,
+		progressService:  progressService,
+	}
+}
+
+// GenerateFlashcards godoc
+// @Summary      Generate flashcards
+// @Description  Queue AI generation of flashcards from a note or an extracted file. Returns the generation with status pending; poll GET /flashcards/generations/{id}, or follow GET /flashcards/generations/{id}/events, until the status is done (2) or failed (3), then list its cards with GET /flashcards?generation_id={id}.
+// @Tags         flashcards
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.GenerateFlashcardsRequest  true  "Source and number of cards"
+// @Success      200      {object}  response.ResponseData             "Pending generation"
+// @Failure      200      {object}  response.ResponseData             "Error response (invalid source, empty source, unsupported language)"
+// @Router       /flashcards/generations [post]
+func (c *FlashcardController) GenerateFlashcards(ctx *gin.Context) {
+	var payload models.GenerateFlashcardsRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	generation, code := c.flashcardService.GenerateFlashcards(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, generation)
+}
+
+// GetGeneration godoc
+// @Summary      Get a flashcard generation
+// @Description  Status is 0 pending, 1 processing, 2 done or 3 failed; created_count is set once done
+// @Tags         flashcards
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Generation ID"
+// @Success      200  {object}  response.ResponseData  "Generation"
+// @Failure      200  {object}  response.ResponseData  "Error response (generation not found)"
+// @Router       /flashcards/generations/{id} [get]
+func (c *FlashcardController) GetGeneration(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid generation id")
+		return
+	}
+
+	generation, code := c.flashcardService.GetGeneration(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, generation)
+}
+
+// StreamGenerationProgress godoc
+// @Summary      Follow a flashcard generation
+// @Description  Stream the generation's progress as Server-Sent Events named "progress" until one has final set. Reconnecting with Last-Event-ID resumes after that event; once the generation has finished a reconnect gets 204.
+// @Tags         flashcards
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        id             path      int     true   "Generation ID"
+// @Param        Last-Event-ID  header    string  false  "ID of the last event received"
+// @Success      200            {object}  models.JobProgress     "Progress events"
+// @Failure      200            {object}  response.ResponseData  "Error response (generation not found)"
+// @Router       /flashcards/generations/{id}/events [get]
+func (c *FlashcardController) StreamGenerationProgress(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid generation id")
+		return
+	}
+
+	generation, code := c.flashcardService.GetGeneration(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	final := generation.Status == consts.FlashcardGenerationStatus.DONE || generation.Status == consts.FlashcardGenerationStatus.FAILED
+	streamProgress(ctx, c.progressService, consts.JobProgressTopic.FLASHCARD_GENERATION, generation.ID, jobState(generation.Status, final, generation.Error))
+}
+
+// CreateFlashcard godoc
+// @Summary      Create a flashcard
+// @Description  Add a hand-written flashcard. It is due for review today.
+// @Tags         flashcards
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.CreateFlashcardRequest  true  "Flashcard"
+// @Success      200      {object}  response.ResponseData          "Created flashcard"
+// @Failure      200      {object}  response.ResponseData          "Error response (course not found)"
+// @Router       /flashcards [post]
+func (c *FlashcardController) CreateFlashcard(ctx *gin.Context) {
+	var payload models.CreateFlashcardRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	card, code := c.flashcardService.CreateFlashcard(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, card)
+}
+
+// ListFlashcards godoc
+// @Summary      List flashcards
+// @Tags         flashcards
+// @Produce      json
+// @Security     BearerAuth
+// @Param        course_id      query     int  false  "Filter by course"
+// @Param        note_id        query     int  false  "Filter by source note"
+// @Param        file_id        query     int  false  "Filter by source file"
+// @Param        generation_id  query     int  false  "Filter by generation"
+// @Success      200            {object}  response.ResponseData  "List of flashcards"
+// @Router       /flashcards [get]
+func (c *FlashcardController) ListFlashcards(ctx *gin.Context) {
+	var query models.FlashcardQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	cards, code := c.flashcardService.ListFlashcards(ctx, ctx.GetString(consts.UserIDContextKey), &query)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, cards)
+}
+
+// ListDueFlashcards godoc
+// @Summary      List due flashcards
+// @Description  List the flashcards due today or earlier in the user's timezone, most overdue first
+// @Tags         flashcards
+// @Produce      json
+// @Security     BearerAuth
+// @Param        course_id  query     int  false  "Filter by course"
+// @Param        limit      query     int  false  "Maximum cards returned (default 50, max 200)"
+// @Success      200        {object}  response.ResponseData  "Due flashcards"
+// @Router       /flashcards/due [get]
+func (c *FlashcardController) ListDueFlashcards(ctx *gin.Context) {
+	var query models.DueFlashcardQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	due, code := c.flashcardService.ListDueFlashcards(ctx, ctx.GetString(consts.UserIDContextKey), &query)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, due)
+}
+
+// GetFlashcard godoc
+// @Summary      Get a flashcard
+// @Tags         flashcards
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Flashcard ID"
+// @Success      200  {object}  response.ResponseData  "Flashcard"
+// @Failure      200  {object}  response.ResponseData  "Error response (flashcard not found)"
+// @Router       /flashcards/{id} [get]
+func (c *FlashcardController) GetFlashcard(ctx *gin.Context) {
+	id, ok := flashcardID(ctx)
+	if !ok {
+		return
+	}
+
+	card, code := c.flashcardService.GetFlashcard(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, card)
+}
+
+// UpdateFlashcard godoc
+// @Summary      Update a flashcard
+// @Description  Edit the card's sides or course. Its review schedule is kept.
+// @Tags         flashcards
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                            true  "Flashcard ID"
+// @Param        request  body      models.UpdateFlashcardRequest  true  "Fields to update"
+// @Success      200      {object}  response.ResponseData          "Updated flashcard"
+// @Failure      200      {object}  response.ResponseData          "Error response (flashcard or course not found)"
+// @Router       /flashcards/{id} [put]
+func (c *FlashcardController) UpdateFlashcard(ctx *gin.Context) {
+	id, ok := flashcardID(ctx)
+	if !ok {
+		return
+	}
+	var payload models.UpdateFlashcardRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	card, code := c.flashcardService.UpdateFlashcard(ctx, ctx.GetString(consts.UserIDContextKey), id, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, card)
+}
+
+// DeleteFlashcard godoc
+// @Summary      Delete a flashcard
+// @Tags         flashcards
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Flashcard ID"
+// @Success      200  {object}  response.ResponseData  "Flashcard deleted"
+// @Failure      200  {object}  response.ResponseData  "Error response (flashcard not found)"
+// @Router       /flashcards/{id} [delete]
+func (c *FlashcardController) DeleteFlashcard(ctx *gin.Context) {
+	id, ok := flashcardID(ctx)
+	if !ok {
+		return
+	}
+
+	code := c.flashcardService.DeleteFlashcard(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, nil)
+}
+
+// ReviewFlashcard godoc
+// @Summary      Review a flashcard
+// @Description  Grade a recall from 0 (blackout) to 5 (perfect). The SM-2 algorithm updates the ease factor and interval; grades below 3 restart the card at a one day interval.
+// @Tags         flashcards
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                            true  "Flashcard ID"
+// @Param        request  body      models.ReviewFlashcardRequest  true  "Grade"
+// @Success      200      {object}  response.ResponseData          "Rescheduled flashcard"
+// @Failure      200      {object}  response.ResponseData          "Error response (flashcard not found)"
+// @Router       /flashcards/{id}/review [post]
+func (c *FlashcardController) ReviewFlashcard(ctx *gin.Context) {
+	id, ok := flashcardID(ctx)
+	if !ok {
+		return
+	}
+	var payload models.ReviewFlashcardRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	card, code := c.flashcardService.ReviewFlashcard(ctx, ctx.GetString(consts.UserIDContextKey), id, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, card)
+}
+
+func flashcardID(ctx *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid flashcard id")
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,285 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type NoteController struct {
+	noteService services.INoteService
+}
+
+func NewNoteController(noteService services.INoteService) *NoteController {
+	return &NoteController{
+		noteService: noteService,
+	}
+}
+
+// CreateNote godoc
+// @Summary      Create a lecture note
+// @Description  Create a note for a course and lecture date. Content is an editor JSON document (root type "doc"); it is validated and rendered to sanitized HTML. The note starts at version 1.
+// @Tags         notes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.CreateNoteRequest  true  "Note data"
+// @Success      200      {object}  response.ResponseData     "Created note"
+// @Header       200      {string}  ETag                      "Current version of the note, for If-Match"
+// @Failure      200      {object}  response.ResponseData     "Error response (invalid content, too many tags, course not found, etc.)"
+// @Router       /notes [post]
+func (c *NoteController) CreateNote(ctx *gin.Context) {
+	var payload models.CreateNoteRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	note, code := c.noteService.CreateNote(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	noteResponse(ctx, note, code)
+}
+
+// ListNotes godoc
+// @Summary      List lecture notes
+// @Description  List the user's notes, newest lecture first, optionally filtered by course, tag and lecture date range
+// @Tags         notes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        course_id  query     int     false  "Filter by course"
+// @Param        tag        query     string  false  "Filter by tag name"
+// @Param        from       query     string  false  "Lecture date on or after (YYYY-MM-DD)"
+// @Param        to         query     string  false  "Lecture date on or before (YYYY-MM-DD)"
+// @Success      200        {object}  response.ResponseData  "List of notes"
+// @Failure      200        {object}  response.ResponseData  "Error response (invalid date)"
+// @Router       /notes [get]
+func (c *NoteController) ListNotes(ctx *gin.Context) {
+	var query models.NoteQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	notes, code := c.noteService.ListNotes(ctx, ctx.GetString(consts.UserIDContextKey), &query)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, notes)
+}
+
+// GetNote godoc
+// @Summary      Get a lecture note
+// @Tags         notes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Note ID"
+// @Success      200  {object}  response.ResponseData  "Note with course and tags"
+// @Header       200  {string}  ETag                   "Current version of the note, for If-Match"
+// @Failure      200  {object}  response.ResponseData  "Error response (note not found)"
+// @Router       /notes/{id} [get]
+func (c *NoteController) GetNote(ctx *gin.Context) {
+	id, ok := noteID(ctx)
+	if !ok {
+		return
+	}
+
+	note, code := c.noteService.GetNote(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	noteResponse(ctx, note, code)
+}
+
+// UpdateNote godoc
+// @Summary      Update a lecture note
+// @Description  Update any subset of fields. Changing the title or content creates a new revision; tags, when present, replace the existing ones. Send the ETag from the last read in If-Match to fail with 409 instead of overwriting someone else's change. The content cannot be replaced while the note is open for collaborative editing (409).
+// @Tags         notes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int                       true   "Note ID"
+// @Param        If-Match  header    string                    false  "ETag the change is based on"
+// @Param        request   body      models.UpdateNoteRequest  true   "Fields to update"
+// @Success      200       {object}  response.ResponseData     "Updated note"
+// @Header       200       {string}  ETag                      "New version of the note"
+// @Failure      200       {object}  response.ResponseData     "Error response (note not found, invalid content, etc.)"
+// @Failure      409       {object}  response.ResponseData     "Note changed since the If-Match ETag, or is being edited together"
+// @Router       /notes/{id} [put]
+func (c *NoteController) UpdateNote(ctx *gin.Context) {
+	id, ok := noteID(ctx)
+	if !ok {
+		return
+	}
+
+	var payload models.UpdateNoteRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	note, code := c.noteService.UpdateNote(ctx, ctx.GetString(consts.UserIDContextKey), id, ctx.GetHeader("If-Match"), &payload)
+	noteResponse(ctx, note, code)
+}
+
+// DeleteNote godoc
+// @Summary      Delete a lecture note
+// @Description  Delete a note together with its revision history
+// @Tags         notes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Note ID"
+// @Success      200  {object}  response.ResponseData  "Note deleted"
+// @Failure      200  {object}  response.ResponseData  "Error response (note not found)"
+// @Router       /notes/{id} [delete]
+func (c *NoteController) DeleteNote(ctx *gin.Context) {
+	id, ok := noteID(ctx)
+	if !ok {
+		return
+	}
+
+	code := c.noteService.DeleteNote(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, nil)
+}
+
+// ListRevisions godoc
+// @Summary      List note revisions
+// @Description  List the revision history of a note, newest first, without content
+// @Tags         notes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Note ID"
+// @Success      200  {object}  response.ResponseData  "List of revisions"
+// @Failure      200  {object}  response.ResponseData  "Error response (note not found)"
+// @Router       /notes/{id}/revisions [get]
+func (c *NoteController) ListRevisions(ctx *gin.Context) {
+	id, ok := noteID(ctx)
+	if !ok {
+		return
+	}
+
+	revisions, code := c.noteService.ListRevisions(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, revisions)
+}
+
+// GetRevision godoc
+// @Summary      Get a note revision
+// @Tags         notes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int  true  "Note ID"
+// @Param        version  path      int  true  "Revision version"
+// @Success      200      {object}  response.ResponseData  "Revision with content"
+// @Failure      200      {object}  response.ResponseData  "Error response (note or revision not found)"
+// @Router       /notes/{id}/revisions/{version} [get]
+func (c *NoteController) GetRevision(ctx *gin.Context) {
+	id, ok := noteID(ctx)
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid revision version")
+		return
+	}
+
+	revision, code := c.noteService.GetRevision(ctx, ctx.GetString(consts.UserIDContextKey), id, version)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, revision)
+}
+
+// DiffRevisions godoc
+// @Summary      Diff two note revisions
+// @Description  Line-based diff of the plain text of two revisions. Defaults to the latest revision against the one before it.
+// @Tags         notes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int  true   "Note ID"
+// @Param        from  query     int  false  "Older version (defaults to to-1)"
+// @Param        to    query     int  false  "Newer version (defaults to the latest)"
+// @Success      200   {object}  response.ResponseData  "Diff lines with added/removed counts"
+// @Failure      200   {object}  response.ResponseData  "Error response (note or revision not found)"
+// @Router       /notes/{id}/diff [get]
+func (c *NoteController) DiffRevisions(ctx *gin.Context) {
+	id, ok := noteID(ctx)
+	if !ok {
+		return
+	}
+
+	var query models.NoteDiffQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	result, code := c.noteService.DiffRevisions(ctx, ctx.GetString(consts.UserIDContextKey), id, &query)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, result)
+}
+
+// RestoreRevision godoc
+// @Summary      Restore a note revision
+// @Description  Copy the title and content of an older revision into the note as a new version. If-Match works as for updates.
+// @Tags         notes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int     true   "Note ID"
+// @Param        version   path      int     true   "Revision version to restore"
+// @Param        If-Match  header    string  false  "ETag the restore is based on"
+// @Success      200       {object}  response.ResponseData  "Updated note"
+// @Header       200       {string}  ETag                   "New version of the note"
+// @Failure      200       {object}  response.ResponseData  "Error response (note or revision not found)"
+// @Failure      409       {object}  response.ResponseData  "Note changed since the If-Match ETag, or is being edited together"
+// @Router       /notes/{id}/revisions/{version}/restore [post]
+func (c *NoteController) RestoreRevision(ctx *gin.Context) {
+	id, ok := noteID(ctx)
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid revision version")
+		return
+	}
+
+	note, code := c.noteService.RestoreRevision(ctx, ctx.GetString(consts.UserIDContextKey), id, version, ctx.GetHeader("If-Match"))
+	noteResponse(ctx, note, code)
+}
+
+// noteResponse answers with the note and its ETag. Conflicts use HTTP 409 so
+// clients and proxies treat a failed If-Match as such.
+func noteResponse(ctx *gin.Context, note *models.Note, code int) {
+	switch code {
+	case response.CodeSuccess:
+		ctx.Header("ETag", note.ETag())
+		response.SuccessResponse(ctx, code, note)
+	case response.CodeNoteVersionConflict, response.CodeNoteCollabActive:
+		response.ErrorResponseStatus(ctx, http.StatusConflict, code, "")
+	default:
+		response.ErrorResponse(ctx, code, "")
+	}
+}
+
+func noteID(ctx *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid note id")
+		return 0, false
+	}
+	return id, true
+}
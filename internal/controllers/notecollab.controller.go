@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type NoteCollabController struct {
+	noteCollabService services.INoteCollabService
+}
+
+func NewNoteCollabController(noteCollabService services.INoteCollabService) *NoteCollabController {
+	return &NoteCollabController{
+		noteCollabService: noteCollabService,
+	}
+}
+
+// Connect godoc
+// @Summary      Edit a note together
+// @Description  Upgrades to a WebSocket for collaborative editing of a note the user wrote or that is shared into one of their study groups. Browsers pass the access token as the token parameter. The first event is init with the document and its revision. The document is edited in its linear form: one token per character ({"kind":"char","text":"a","marks":[...]}), per atom node such as an image ({"kind":"leaf","type":"image","attrs":{...}}), and an open and a close token around every other node; the doc root is not part of it. Send {"type":"op","rev":<revision the edit was made on>,"ops":[{"retain":n},{"insert":[tokens]},{"delete":n}]} covering the whole document; the server transforms it past edits the client had not seen. Every applied edit comes back as an op event with its new revision, and the sender's own carries its conn_id as the acknowledgement. Edits are saved into the note as a new version every few seconds (snapshot event). A rejected edit gets an error event; the connection is closed with 4409 when the client has to reload the document and with 4403 once the user loses access to the note.
+// @Tags         notes
+// @Security     BearerAuth
+// @Param        id     path   int     true   "Note ID"
+// @Param        token  query  string  false  "Access token, when the Authorization header cannot be set"
+// @Success      101    "Switching protocols"
+// @Failure      200    {object}  response.ResponseData  "Error response (note not found)"
+// @Router       /notes/{id}/collab [get]
+func (c *NoteCollabController) Connect(ctx *gin.Context) {
+	id, ok := noteID(ctx)
+	if !ok {
+		return
+	}
+	userID := ctx.GetString(consts.UserIDContextKey)
+
+	// Refuse before upgrading, so the client gets a normal error response
+	if _, code := c.noteCollabService.CheckAccess(ctx, userID, id); code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+
+	_ = response.ServeWebSocket(ctx, response.DefaultWebSocketHeartbeat, consts.NOTE_COLLAB_SEND_BUFFER, func(wsCtx context.Context, ws *response.WebSocketConn) error {
+		sendError := func(code, rev int) {
+			_ = ws.Send(models.NoteCollabEvent{Type: consts.NoteCollabEventType.ERROR, NoteID: id, Rev: rev, Code: code, Error: response.GetMessageByCode(code)})
+		}
+
+		client := &services.NoteCollabClient{
+			NoteID: id,
+			UserID: userID,
+			Send:   func(event models.NoteCollabEvent) error { return ws.Send(event) },
+			Kick:   ws.Close,
+		}
+		leave, code := c.noteCollabService.Connect(wsCtx, client)
+		if code != response.CodeSuccess {
+			sendError(code, 0)
+			ws.Close(consts.NOTE_COLLAB_CLOSE_UNAVAILABLE, "editing unavailable")
+			return nil
+		}
+		defer leave()
+
+		for {
+			var frame models.NoteCollabFrame
+			if err := ws.Receive(&frame); err != nil {
+				if errors.Is(err, response.ErrWebSocketFrame) {
+					sendError(response.CodeNoteCollabInvalidOp, 0)
+					continue
+				}
+				return nil
+			}
+			if frame.Type != consts.NoteCollabEventType.OP {
+				sendError(response.CodeNoteCollabInvalidOp, frame.Rev)
+				continue
+			}
+
+			switch code := c.noteCollabService.Submit(wsCtx, client, frame.Rev, frame.Ops); code {
+			case response.CodeSuccess:
+			case response.CodeNoteCollabResync:
+				sendError(code, frame.Rev)
+				ws.Close(consts.NOTE_COLLAB_CLOSE_RESYNC, "document moved on")
+			default:
+				sendError(code, frame.Rev)
+			}
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type NotificationController struct {
+	notificationService services.INotificationService
+}
+
+func NewNotificationController(notificationService services.INotificationService) *NotificationController {
+	return &NotificationController{
+		notificationService: notificationService,
+	}
+}
+
+// GetSettings godoc
+// @Summary      Get notification settings
+// @Description  Returns the reminder notification offsets (minutes before the deadline) and channel preferences. Defaults apply until the user saves their own.
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.ResponseData  "Notification settings"
+// @Router       /notifications/settings [get]
+func (c *NotificationController) GetSettings(ctx *gin.Context) {
+	settings, code := c.notificationService.GetSettings(ctx, ctx.GetString(consts.UserIDContextKey))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, settings)
+}
+
+// UpdateSettings godoc
+// @Summary      Update notification settings
+// @Description  Replace the reminder notification offsets. Already scheduled but unsent notifications are rebuilt with the new offsets.
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.UpdateNotificationSettingRequest  true  "Notification settings"
+// @Success      200      {object}  response.ResponseData                    "Updated settings"
+// @Failure      200      {object}  response.ResponseData                    "Error response (invalid offsets)"
+// @Router       /notifications/settings [put]
+func (c *NotificationController) UpdateSettings(ctx *gin.Context) {
+	var payload models.UpdateNotificationSettingRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	settings, code := c.notificationService.UpdateSettings(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, settings)
+}
+
+// ListNotifications godoc
+// @Summary      List reminder notifications
+// @Description  Returns the user's most recent scheduled, sent and skipped reminder notifications.
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit  query     int  false  "Maximum number of notifications (default 50, max 100)"
+// @Success      200    {object}  response.ResponseData  "List of notifications"
+// @Router       /notifications [get]
+func (c *NotificationController) ListNotifications(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+
+	notifications, code := c.notificationService.ListNotifications(ctx, ctx.GetString(consts.UserIDContextKey), limit)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, notifications)
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type PlanController struct {
+	quotaService services.IQuotaService
+}
+
+func NewPlanController(quotaService services.IQuotaService) *PlanController {
+	return &PlanController{
+		quotaService: quotaService,
+	}
+}
+
+// ListPlans godoc
+// @Summary      List plans
+// @Description  Subscription plans with their monthly price and limits; a null limit is unlimited
+// @Tags         plans
+// @Produce      json
+// @Success      200  {object}  response.ResponseData  "List of plans, cheapest first"
+// @Router       /plans [get]
+func (c *PlanController) ListPlans(ctx *gin.Context) {
+	plans, code := c.quotaService.ListPlans(ctx)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, plans)
+}
+
+// GetUsage godoc
+// @Summary      Get usage of the current billing period
+// @Description  AI operations and tokens used in the billing period, per operation and against the plan's limits, plus courses and storage. The period is the subscription's current period, or the calendar month for free users.
+// @Tags         plans
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.ResponseData  "Usage summary"
+// @Router       /users/me/usage [get]
+func (c *PlanController) GetUsage(ctx *gin.Context) {
+	usage, code := c.quotaService.GetUsage(ctx, ctx.GetString(consts.UserIDContextKey))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, usage)
+}
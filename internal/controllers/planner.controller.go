@@ -0,0 +1,285 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type PlannerController struct {
+	plannerService services.IPlannerService
+}
+
+func NewPlannerController(plannerService services.IPlannerService) *PlannerController {
+	return &PlannerController{
+		plannerService: plannerService,
+	}
+}
+
+// GetPreferences godoc
+// @Summary      Get study preferences
+// @Description  Weekly windows of free time and block settings used to plan study. Users who never saved preferences get weekday evenings and weekend days.
+// @Tags         planner
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.ResponseData  "Study preferences"
+// @Router       /planner/preferences [get]
+func (c *PlannerController) GetPreferences(ctx *gin.Context) {
+	preference, code := c.plannerService.GetPreferences(ctx, ctx.GetString(consts.UserIDContextKey))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, preference)
+}
+
+// UpdatePreferences godoc
+// @Summary      Update study preferences
+// @Description  Replace the weekly study windows; omitted block settings keep their current values
+// @Tags         planner
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.UpdateStudyPreferenceRequest  true  "Study preferences"
+// @Success      200      {object}  response.ResponseData                "Updated study preferences"
+// @Failure      200      {object}  response.ResponseData                "Error response (invalid study window)"
+// @Router       /planner/preferences [put]
+func (c *PlannerController) UpdatePreferences(ctx *gin.Context) {
+	var payload models.UpdateStudyPreferenceRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	preference, code := c.plannerService.UpdatePreferences(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, preference)
+}
+
+// SetCourseDifficulty godoc
+// @Summary      Rate a course's difficulty
+// @Description  Difficulty from 1 (easy) to 5 (hard); harder courses get more study time. Null clears the rating.
+// @Tags         planner
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                                true  "Course ID"
+// @Param        request  body      models.SetCourseDifficultyRequest  true  "Difficulty"
+// @Success      200      {object}  response.ResponseData              "Updated course"
+// @Failure      200      {object}  response.ResponseData              "Error response (course not found)"
+// @Router       /planner/courses/{id}/difficulty [put]
+func (c *PlannerController) SetCourseDifficulty(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid course id")
+		return
+	}
+
+	var payload models.SetCourseDifficultyRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	course, code := c.plannerService.SetCourseDifficulty(ctx, ctx.GetString(consts.UserIDContextKey), id, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, course)
+}
+
+// GeneratePlan godoc
+// @Summary      Generate a study plan
+// @Description  Schedule study blocks for the exams and assignments due within the horizon into the user's study windows, avoiding classes. Study time per deadline grows with course difficulty, credits and weight and shrinks with a good current grade. The new plan is a draft and replaces the previous draft.
+// @Tags         planner
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.GenerateStudyPlanRequest  false  "Planning horizon"
+// @Success      200      {object}  response.ResponseData            "Draft plan with its blocks and the study that did not fit"
+// @Failure      200      {object}  response.ResponseData            "Error response (nothing to plan)"
+// @Router       /planner/plans [post]
+func (c *PlannerController) GeneratePlan(ctx *gin.Context) {
+	var payload models.GenerateStudyPlanRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&payload); err != nil {
+			response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+			return
+		}
+	}
+
+	plan, code := c.plannerService.GeneratePlan(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, plan)
+}
+
+// ListPlans godoc
+// @Summary      List study plans
+// @Description  The user's study plans without their blocks, newest first
+// @Tags         planner
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.ResponseData  "List of study plans"
+// @Router       /planner/plans [get]
+func (c *PlannerController) ListPlans(ctx *gin.Context) {
+	plans, code := c.plannerService.ListPlans(ctx, ctx.GetString(consts.UserIDContextKey))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, plans)
+}
+
+// GetPlan godoc
+// @Summary      Get a study plan
+// @Description  The plan with its blocks. Outdated is true when deadlines, classes or preferences changed since it was generated, so it should be regenerated.
+// @Tags         planner
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Plan ID"
+// @Success      200  {object}  response.ResponseData  "Study plan"
+// @Failure      200  {object}  response.ResponseData  "Error response (plan not found)"
+// @Router       /planner/plans/{id} [get]
+func (c *PlannerController) GetPlan(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid plan id")
+		return
+	}
+
+	plan, code := c.plannerService.GetPlan(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, plan)
+}
+
+// AcceptPlan godoc
+// @Summary      Accept a study plan
+// @Description  Make the draft the user's current plan, superseding the previously accepted plan
+// @Tags         planner
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Plan ID"
+// @Success      200  {object}  response.ResponseData  "Accepted plan"
+// @Failure      200  {object}  response.ResponseData  "Error response (plan not found or superseded)"
+// @Router       /planner/plans/{id}/accept [post]
+func (c *PlannerController) AcceptPlan(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid plan id")
+		return
+	}
+
+	plan, code := c.plannerService.AcceptPlan(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, plan)
+}
+
+// RegeneratePlan godoc
+// @Summary      Regenerate a study plan
+// @Description  Plan again from now over the same horizon after deadlines or classes changed. Upcoming blocks the user moved are kept. The result is a new draft.
+// @Tags         planner
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Plan ID"
+// @Success      200  {object}  response.ResponseData  "New draft plan"
+// @Failure      200  {object}  response.ResponseData  "Error response (plan not found or superseded, nothing to plan)"
+// @Router       /planner/plans/{id}/regenerate [post]
+func (c *PlannerController) RegeneratePlan(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid plan id")
+		return
+	}
+
+	plan, code := c.plannerService.RegeneratePlan(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, plan)
+}
+
+// AdjustBlock godoc
+// @Summary      Move a study block
+// @Description  Reschedule a block of a draft or accepted plan. It may not overlap a class or another block, and it is kept when the plan is regenerated.
+// @Tags         planner
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int                             true  "Plan ID"
+// @Param        block_id  path      int                             true  "Block ID"
+// @Param        request   body      models.AdjustStudyBlockRequest  true  "New time"
+// @Success      200       {object}  response.ResponseData           "Updated block"
+// @Failure      200       {object}  response.ResponseData           "Error response (block not found, invalid time, conflict)"
+// @Router       /planner/plans/{id}/blocks/{block_id} [patch]
+func (c *PlannerController) AdjustBlock(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid plan id")
+		return
+	}
+	blockID, err := strconv.Atoi(ctx.Param("block_id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid block id")
+		return
+	}
+
+	var payload models.AdjustStudyBlockRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	block, code := c.plannerService.AdjustBlock(ctx, ctx.GetString(consts.UserIDContextKey), id, blockID, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, block)
+}
+
+// DeleteBlock godoc
+// @Summary      Delete a study block
+// @Tags         planner
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int  true  "Plan ID"
+// @Param        block_id  path      int  true  "Block ID"
+// @Success      200       {object}  response.ResponseData  "Block deleted"
+// @Failure      200       {object}  response.ResponseData  "Error response (block not found)"
+// @Router       /planner/plans/{id}/blocks/{block_id} [delete]
+func (c *PlannerController) DeleteBlock(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid plan id")
+		return
+	}
+	blockID, err := strconv.Atoi(ctx.Param("block_id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid block id")
+		return
+	}
+
+	if code := c.plannerService.DeleteBlock(ctx, ctx.GetString(consts.UserIDContextKey), id, blockID); code == response.CodeSuccess {
+		response.SuccessResponse(ctx, code, nil)
+	} else {
+		response.ErrorResponse(ctx, code, "")
+	}
+}
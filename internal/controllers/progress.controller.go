@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+// streamProgress streams the progress events of a background job as
+// Server-Sent Events. current is the state of the row the job works on.
+func streamProgress(ctx *gin.Context, progressService services.IProgressService, kind string, id int, current models.JobProgress) {
+	lastEventID := response.LastEventID(ctx)
+	if current.Final && lastEventID != "" {
+		// A reconnecting EventSource stops retrying on 204
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	_ = response.StreamSSE(ctx, consts.SSE_HEARTBEAT_INTERVAL, func(streamCtx context.Context, stream *response.SSEStream) error {
+		code := progressService.Follow(streamCtx, kind, id, current, lastEventID, func(event models.JobProgress) error {
+			return stream.Send(response.SSEEvent{ID: event.ID, Event: consts.SSE_EVENT_PROGRESS, Data: event})
+		})
+		if code != response.CodeSuccess {
+			return stream.Error(code)
+		}
+		return nil
+	})
+}
+
+// jobState describes a row's job status as a progress event
+func jobState(status int8, final bool, message sql.NullString) models.JobProgress {
+	return models.JobProgress{Status: status, Error: message.String, Final: final}
+}
@@ -0,0 +1,292 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type QuizController struct {
+	quizService     services.IQuizService
+	progressService services.IProgressService
+}
+
+func NewQuizController(quizService services.IQuizService, progressService services.IProgressService) *QuizController {
+	return &QuizController{
+		quizService:     quizService,
+		progressService: progressService,
+	}
+}
+
+// CreateQuiz godoc
+// @Summary      Generate a quiz
+// @Description  Queue AI generation of a practice quiz from the course's notes. Returns the quiz with status pending; poll GET /quizzes/{id}, or follow GET /quizzes/{id}/events, until the status is done (2) or failed (3).
+// @Tags         quizzes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.CreateQuizRequest  true  "Course, question count, difficulty and types"
+// @Success      200      {object}  response.ResponseData     "Pending quiz"
+// @Failure      200      {object}  response.ResponseData     "Error response (course not found, no notes, invalid difficulty, type or language)"
+// @Router       /quizzes [post]
+func (c *QuizController) CreateQuiz(ctx *gin.Context) {
+	var payload models.CreateQuizRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	quiz, code := c.quizService.CreateQuiz(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, quiz)
+}
+
+// ListQuizzes godoc
+// @Summary      List quizzes
+// @Tags         quizzes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        course_id  query     int  false  "Filter by course"
+// @Success      200        {object}  response.ResponseData  "List of quizzes"
+// @Router       /quizzes [get]
+func (c *QuizController) ListQuizzes(ctx *gin.Context) {
+	var query models.QuizQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	quizzes, code := c.quizService.ListQuizzes(ctx, ctx.GetString(consts.UserIDContextKey), &query)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, quizzes)
+}
+
+// GetQuiz godoc
+// @Summary      Get a quiz
+// @Description  Get the quiz and its questions without the answers, which are shown on graded attempts
+// @Tags         quizzes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Quiz ID"
+// @Success      200  {object}  response.ResponseData  "Quiz with questions"
+// @Failure      200  {object}  response.ResponseData  "Error response (quiz not found)"
+// @Router       /quizzes/{id} [get]
+func (c *QuizController) GetQuiz(ctx *gin.Context) {
+	id, ok := quizID(ctx)
+	if !ok {
+		return
+	}
+
+	quiz, code := c.quizService.GetQuiz(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, quiz)
+}
+
+// StreamQuizProgress godoc
+// @Summary      Follow quiz generation
+// @Description  Stream the quiz's generation progress as Server-Sent Events named "progress" until one has final set. Reconnecting with Last-Event-ID resumes after that event; once generation has finished a reconnect gets 204.
+// @Tags         quizzes
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        id             path      int     true   "Quiz ID"
+// @Param        Last-Event-ID  header    string  false  "ID of the last event received"
+// @Success      200            {object}  models.JobProgress     "Progress events"
+// @Failure      200            {object}  response.ResponseData  "Error response (quiz not found)"
+// @Router       /quizzes/{id}/events [get]
+func (c *QuizController) StreamQuizProgress(ctx *gin.Context) {
+	id, ok := quizID(ctx)
+	if !ok {
+		return
+	}
+
+	quiz, code := c.quizService.GetQuiz(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	final := quiz.Status == consts.QuizStatus.DONE || quiz.Status == consts.QuizStatus.FAILED
+	streamProgress(ctx, c.progressService, consts.JobProgressTopic.QUIZ, quiz.ID, jobState(quiz.Status, final, quiz.Error))
+}
+
+// DeleteQuiz godoc
+// @Summary      Delete a quiz
+// @Description  Delete the quiz with its questions and attempts
+// @Tags         quizzes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Quiz ID"
+// @Success      200  {object}  response.ResponseData  "Quiz deleted"
+// @Failure      200  {object}  response.ResponseData  "Error response (quiz not found)"
+// @Router       /quizzes/{id} [delete]
+func (c *QuizController) DeleteQuiz(ctx *gin.Context) {
+	id, ok := quizID(ctx)
+	if !ok {
+		return
+	}
+
+	code := c.quizService.DeleteQuiz(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, nil)
+}
+
+// GetStats godoc
+// @Summary      Get quiz statistics
+// @Description  Per-question answer counts, correct rate, mean score and, for multiple choice and true/false questions, how often each response was given. Only graded attempts count.
+// @Tags         quizzes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Quiz ID"
+// @Success      200  {object}  response.ResponseData  "Quiz statistics"
+// @Failure      200  {object}  response.ResponseData  "Error response (quiz not found)"
+// @Router       /quizzes/{id}/stats [get]
+func (c *QuizController) GetStats(ctx *gin.Context) {
+	id, ok := quizID(ctx)
+	if !ok {
+		return
+	}
+
+	stats, code := c.quizService.GetStats(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, stats)
+}
+
+// StartAttempt godoc
+// @Summary      Start a quiz attempt
+// @Tags         quizzes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Quiz ID"
+// @Success      200  {object}  response.ResponseData  "New attempt"
+// @Failure      200  {object}  response.ResponseData  "Error response (quiz not found or not ready)"
+// @Router       /quizzes/{id}/attempts [post]
+func (c *QuizController) StartAttempt(ctx *gin.Context) {
+	id, ok := quizID(ctx)
+	if !ok {
+		return
+	}
+
+	attempt, code := c.quizService.StartAttempt(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, attempt)
+}
+
+// ListAttempts godoc
+// @Summary      List quiz attempts
+// @Tags         quizzes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Quiz ID"
+// @Success      200  {object}  response.ResponseData  "List of attempts, newest first"
+// @Failure      200  {object}  response.ResponseData  "Error response (quiz not found)"
+// @Router       /quizzes/{id}/attempts [get]
+func (c *QuizController) ListAttempts(ctx *gin.Context) {
+	id, ok := quizID(ctx)
+	if !ok {
+		return
+	}
+
+	attempts, code := c.quizService.ListAttempts(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, attempts)
+}
+
+// GetAttempt godoc
+// @Summary      Get a quiz attempt
+// @Description  Get the attempt with every question and its answer. Status is 0 in progress, 1 grading, 2 graded or 3 failed; solutions are included once graded.
+// @Tags         quizzes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      int  true  "Quiz ID"
+// @Param        attempt    path      int  true  "Attempt ID"
+// @Success      200        {object}  response.ResponseData  "Attempt"
+// @Failure      200        {object}  response.ResponseData  "Error response (attempt not found)"
+// @Router       /quizzes/{id}/attempts/{attempt} [get]
+func (c *QuizController) GetAttempt(ctx *gin.Context) {
+	id, ok := quizID(ctx)
+	if !ok {
+		return
+	}
+	attemptID, err := strconv.Atoi(ctx.Param("attempt"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid attempt id")
+		return
+	}
+
+	attempt, code := c.quizService.GetAttempt(ctx, ctx.GetString(consts.UserIDContextKey), id, attemptID)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, attempt)
+}
+
+// SubmitAttempt godoc
+// @Summary      Submit a quiz attempt
+// @Description  Submit the answers: the option index for mcq, "true" or "false" for true_false and free text for short_answer. Multiple choice and true/false answers are graded immediately; short answers are graded by AI against their rubric while the attempt's status is grading (1), which takes one AI operation of the plan.
+// @Tags         quizzes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      int                              true  "Quiz ID"
+// @Param        attempt    path      int                              true  "Attempt ID"
+// @Param        request    body      models.SubmitQuizAttemptRequest  true  "Answers"
+// @Success      200        {object}  response.ResponseData            "Graded or grading attempt"
+// @Failure      200        {object}  response.ResponseData            "Error response (attempt not found, already submitted, unknown question, AI quota exceeded)"
+// @Router       /quizzes/{id}/attempts/{attempt}/submit [post]
+func (c *QuizController) SubmitAttempt(ctx *gin.Context) {
+	id, ok := quizID(ctx)
+	if !ok {
+		return
+	}
+	attemptID, err := strconv.Atoi(ctx.Param("attempt"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid attempt id")
+		return
+	}
+	var payload models.SubmitQuizAttemptRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	attempt, code := c.quizService.SubmitAttempt(ctx, ctx.GetString(consts.UserIDContextKey), id, attemptID, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, attempt)
+}
+
+func quizID(ctx *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid quiz id")
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type ReminderController struct {
+	reminderService services.IReminderService
+}
+
+func NewReminderController(reminderService services.IReminderService) *ReminderController {
+	return &ReminderController{
+		reminderService: reminderService,
+	}
+}
+
+// CreateReminder godoc
+// @Summary      Create a reminder
+// @Description  Create a course reminder, assignment or exam. Location, weight and score are only accepted for exams (type=2).
+// @Tags         reminders
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.CreateReminderRequest  true  "Reminder data"
+// @Success      200      {object}  response.ResponseData         "Created reminder"
+// @Failure      200      {object}  response.ResponseData         "Error response (invalid due date, course not found, etc.)"
+// @Router       /reminders [post]
+func (c *ReminderController) CreateReminder(ctx *gin.Context) {
+	var payload models.CreateReminderRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	reminder, code := c.reminderService.CreateReminder(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, reminder)
+}
+
+// ListReminders godoc
+// @Summary      List reminders
+// @Description  List the current user's reminders ordered by deadline. Pending reminders past their deadline are reported as overdue.
+// @Tags         reminders
+// @Produce      json
+// @Security     BearerAuth
+// @Param        course_id  query     int     false  "Filter by course"
+// @Param        type       query     int     false  "Filter by type (0=course, 1=assignment, 2=exam)"
+// @Param        status     query     int     false  "Filter by status (0=pending, 1=completed, 2=overdue)"
+// @Param        from       query     string  false  "Due on or after (YYYY-MM-DD)"
+// @Param        to         query     string  false  "Due on or before (YYYY-MM-DD)"
+// @Success      200        {object}  response.ResponseData  "List of reminders"
+// @Router       /reminders [get]
+func (c *ReminderController) ListReminders(ctx *gin.Context) {
+	var query models.ReminderQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	reminders, code := c.reminderService.ListReminders(ctx, ctx.GetString(consts.UserIDContextKey), &query)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, reminders)
+}
+
+// GetReminder godoc
+// @Summary      Get a reminder
+// @Tags         reminders
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Reminder ID"
+// @Success      200  {object}  response.ResponseData  "Reminder"
+// @Failure      200  {object}  response.ResponseData  "Error response (reminder not found)"
+// @Router       /reminders/{id} [get]
+func (c *ReminderController) GetReminder(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid reminder id")
+		return
+	}
+
+	reminder, code := c.reminderService.GetReminder(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, reminder)
+}
+
+// UpdateReminder godoc
+// @Summary      Update a reminder
+// @Description  Partially update a reminder. Moving the deadline re-evaluates pending/overdue status.
+// @Tags         reminders
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                           true  "Reminder ID"
+// @Param        request  body      models.UpdateReminderRequest  true  "Fields to update"
+// @Success      200      {object}  response.ResponseData         "Updated reminder"
+// @Failure      200      {object}  response.ResponseData         "Error response (reminder not found, invalid due date, etc.)"
+// @Router       /reminders/{id} [put]
+func (c *ReminderController) UpdateReminder(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid reminder id")
+		return
+	}
+
+	var payload models.UpdateReminderRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	reminder, code := c.reminderService.UpdateReminder(ctx, ctx.GetString(consts.UserIDContextKey), id, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, reminder)
+}
+
+// UpdateReminderStatus godoc
+// @Summary      Complete or reopen a reminder
+// @Description  Set status to 1 to complete a pending/overdue reminder, or 0 to reopen a completed one.
+// @Tags         reminders
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                                 true  "Reminder ID"
+// @Param        request  body      models.UpdateReminderStatusRequest  true  "New status"
+// @Success      200      {object}  response.ResponseData               "Updated reminder"
+// @Failure      200      {object}  response.ResponseData               "Error response (invalid transition, etc.)"
+// @Router       /reminders/{id}/status [patch]
+func (c *ReminderController) UpdateReminderStatus(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid reminder id")
+		return
+	}
+
+	var payload models.UpdateReminderStatusRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	reminder, code := c.reminderService.UpdateReminderStatus(ctx, ctx.GetString(consts.UserIDContextKey), id, payload.Status)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, reminder)
+}
+
+// DeleteReminder godoc
+// @Summary      Delete a reminder
+// @Tags         reminders
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Reminder ID"
+// @Success      200  {object}  response.ResponseData  "Reminder deleted"
+// @Failure      200  {object}  response.ResponseData  "Error response (reminder not found)"
+// @Router       /reminders/{id} [delete]
+func (c *ReminderController) DeleteReminder(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid reminder id")
+		return
+	}
+
+	if code := c.reminderService.DeleteReminder(ctx, ctx.GetString(consts.UserIDContextKey), id); code == response.CodeSuccess {
+		response.SuccessResponse(ctx, code, nil)
+	} else {
+		response.ErrorResponse(ctx, code, "")
+	}
+}
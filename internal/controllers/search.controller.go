@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type SearchController struct {
+	searchService services.ISearchService
+}
+
+func NewSearchController(searchService services.ISearchService) *SearchController {
+	return &SearchController{
+		searchService: searchService,
+	}
+}
+
+// Search godoc
+// @Summary      Search notes, files, flashcards, courses and reminders
+// @Description  Full-text search across the user's note titles and content, extracted file text, flashcard fronts and backs, course names and lecturers, and reminder titles and descriptions. Every word must match, as a prefix. Hits are ordered by relevance and carry an HTML snippet with matches wrapped in <mark>. A file appears once, with the snippet and page range of its best matching chunk. Date filters apply to lecture, due, upload and flashcard creation dates and exclude courses.
+// @Tags         search
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q          query     string  true   "Search query"
+// @Param        types      query     string  false  "Comma-separated types to include (note, file, flashcard, course, reminder)"
+// @Param        course_id  query     int     false  "Filter by course"
+// @Param        tag        query     string  false  "Filter by tag name (files, flashcards and reminders match their course's tags)"
+// @Param        from       query     string  false  "Date on or after (YYYY-MM-DD)"
+// @Param        to         query     string  false  "Date on or before (YYYY-MM-DD)"
+// @Param        page       query     int     false  "Page number, starting at 1"
+// @Param        page_size  query     int     false  "Hits per page (default 20, max 50)"
+// @Success      200        {object}  response.ResponseData  "Search results"
+// @Failure      200        {object}  response.ResponseData  "Error response (empty query, invalid date or type)"
+// @Router       /search [get]
+func (c *SearchController) Search(ctx *gin.Context) {
+	var query models.SearchQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	result, code := c.searchService.Search(ctx, ctx.GetString(consts.UserIDContextKey), &query)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, result)
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// StorageController serves the presigned URLs of the local storage backend.
+// Requests are authorized by the URL signature, so responses are plain HTTP
+// statuses like an object store would send rather than the JSON envelope.
+type StorageController struct {
+	store   *storage.LocalStorage
+	maxSize int64
+}
+
+func NewStorageController(store *storage.LocalStorage, maxSize int64) *StorageController {
+	return &StorageController{
+		store:   store,
+		maxSize: maxSize,
+	}
+}
+
+// PutObject godoc
+// @Summary      Upload to a presigned URL
+// @Description  Target of presigned upload URLs issued by POST /files/uploads when the local storage backend is used. Only staging keys accept uploads.
+// @Tags         files
+// @Accept       octet-stream
+// @Param        key  path  string  true  "Object key"
+// @Success      200
+// @Failure      403  "Invalid or expired signature, or not a staging key"
+// @Failure      413  "File too large"
+// @Router       /storage/{key} [put]
+func (c *StorageController) PutObject(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+	query := ctx.Request.URL.Query()
+	// Stored files are written by the server only; clients upload to staging
+	if !strings.HasPrefix(key, consts.STORAGE_STAGING_PREFIX) {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+	if err := c.store.Verify(http.MethodPut, key, query); err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+	if contentType := query.Get("content_type"); contentType != "" && ctx.ContentType() != contentType {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+	if ctx.Request.ContentLength > c.maxSize {
+		ctx.Status(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, c.maxSize)
+	if err := c.store.Put(ctx, key, body, ctx.Request.ContentLength, ctx.ContentType()); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		global.Log.Error("Error storing object", zap.Error(err), zap.String("key", key))
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+// GetObject godoc
+// @Summary      Download from a presigned URL
+// @Description  Target of presigned download URLs issued by GET /files/{id}/download when the local storage backend is used
+// @Tags         files
+// @Produce      octet-stream
+// @Param        key  path  string  true  "Object key"
+// @Success      200
+// @Failure      403  "Invalid or expired signature"
+// @Failure      404  "Object not found"
+// @Router       /storage/{key} [get]
+func (c *StorageController) GetObject(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+	query := ctx.Request.URL.Query()
+	if err := c.store.Verify(http.MethodGet, key, query); err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	object, err := c.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		global.Log.Error("Error reading object", zap.Error(err), zap.String("key", key))
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	defer object.Close()
+
+	contentType := query.Get("content_type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	if filename := query.Get("filename"); filename != "" {
+		ctx.Header("Content-Disposition", storage.ContentDisposition("attachment", filename))
+	}
+
+	// Local objects are files, which lets ServeContent answer range requests
+	if seeker, ok := object.(io.ReadSeeker); ok {
+		http.ServeContent(ctx.Writer, ctx.Request, "", time.Time{}, seeker)
+		return
+	}
+	ctx.Status(http.StatusOK)
+	io.Copy(ctx.Writer, object)
+}
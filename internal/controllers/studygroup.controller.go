@@ -0,0 +1,460 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type StudyGroupController struct {
+	studyGroupService services.IStudyGroupService
+}
+
+func NewStudyGroupController(studyGroupService services.IStudyGroupService) *StudyGroupController {
+	return &StudyGroupController{
+		studyGroupService: studyGroupService,
+	}
+}
+
+// CreateGroup godoc
+// @Summary      Create a study group
+// @Description  Create a group for one of the user's courses, with the user as owner. Notes of courses with the same code can be shared into it.
+// @Tags         study-groups
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.CreateStudyGroupRequest  true  "Study group"
+// @Success      200      {object}  response.ResponseData           "Created group with its members"
+// @Failure      200      {object}  response.ResponseData           "Error response (course not found)"
+// @Router       /study-groups [post]
+func (c *StudyGroupController) CreateGroup(ctx *gin.Context) {
+	var payload models.CreateStudyGroupRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	group, code := c.studyGroupService.CreateGroup(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, group)
+}
+
+// ListGroups godoc
+// @Summary      List study groups
+// @Description  The groups the user belongs to with their role, newest first
+// @Tags         study-groups
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.ResponseData  "Study groups"
+// @Router       /study-groups [get]
+func (c *StudyGroupController) ListGroups(ctx *gin.Context) {
+	groups, code := c.studyGroupService.ListGroups(ctx, ctx.GetString(consts.UserIDContextKey))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, groups)
+}
+
+// GetGroup godoc
+// @Summary      Get a study group
+// @Tags         study-groups
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int                    true  "Study group ID"
+// @Success      200  {object}  response.ResponseData  "Group with its members"
+// @Failure      200  {object}  response.ResponseData  "Error response (study group not found)"
+// @Router       /study-groups/{id} [get]
+func (c *StudyGroupController) GetGroup(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+
+	group, code := c.studyGroupService.GetGroup(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, group)
+}
+
+// UpdateGroup godoc
+// @Summary      Update a study group
+// @Description  Rename or describe the group; admins and the owner only
+// @Tags         study-groups
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                             true  "Study group ID"
+// @Param        request  body      models.UpdateStudyGroupRequest  true  "Fields to update"
+// @Success      200      {object}  response.ResponseData           "Updated group"
+// @Failure      200      {object}  response.ResponseData           "Error response (forbidden)"
+// @Router       /study-groups/{id} [patch]
+func (c *StudyGroupController) UpdateGroup(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+
+	var payload models.UpdateStudyGroupRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	group, code := c.studyGroupService.UpdateGroup(ctx, ctx.GetString(consts.UserIDContextKey), id, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, group)
+}
+
+// DeleteGroup godoc
+// @Summary      Delete a study group
+// @Description  Delete the group with its memberships, invitations and shared notes (the notes themselves stay with their authors); owner only
+// @Tags         study-groups
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int                    true  "Study group ID"
+// @Success      200  {object}  response.ResponseData  "Study group deleted"
+// @Failure      200  {object}  response.ResponseData  "Error response (forbidden)"
+// @Router       /study-groups/{id} [delete]
+func (c *StudyGroupController) DeleteGroup(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+
+	if code := c.studyGroupService.DeleteGroup(ctx, ctx.GetString(consts.UserIDContextKey), id); code == response.CodeSuccess {
+		response.SuccessResponse(ctx, code, nil)
+	} else {
+		response.ErrorResponse(ctx, code, "")
+	}
+}
+
+// CreateInvitation godoc
+// @Summary      Invite to a study group
+// @Description  Email an invitation that only that address can accept once, or create a join link anyone holding it can use when the email is omitted. Both expire after expires_in_days (7 by default); admins and the owner only.
+// @Tags         study-groups
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                             true  "Study group ID"
+// @Param        request  body      models.CreateInvitationRequest  true  "Invitation"
+// @Success      200      {object}  response.ResponseData           "Invitation with its join URL"
+// @Failure      200      {object}  response.ResponseData           "Error response (forbidden)"
+// @Router       /study-groups/{id}/invitations [post]
+func (c *StudyGroupController) CreateInvitation(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+
+	var payload models.CreateInvitationRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	invitation, code := c.studyGroupService.CreateInvitation(ctx, ctx.GetString(consts.UserIDContextKey), id, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, invitation)
+}
+
+// ListInvitations godoc
+// @Summary      List pending invitations
+// @Description  Invitations and join links that have not expired or been used; admins and the owner only
+// @Tags         study-groups
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int                    true  "Study group ID"
+// @Success      200  {object}  response.ResponseData  "Pending invitations"
+// @Failure      200  {object}  response.ResponseData  "Error response (forbidden)"
+// @Router       /study-groups/{id}/invitations [get]
+func (c *StudyGroupController) ListInvitations(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+
+	invitations, code := c.studyGroupService.ListInvitations(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, invitations)
+}
+
+// RevokeInvitation godoc
+// @Summary      Revoke an invitation
+// @Tags         study-groups
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id             path      int                    true  "Study group ID"
+// @Param        invitation_id  path      int                    true  "Invitation ID"
+// @Success      200            {object}  response.ResponseData  "Invitation revoked"
+// @Failure      200            {object}  response.ResponseData  "Error response (invitation not found)"
+// @Router       /study-groups/{id}/invitations/{invitation_id} [delete]
+func (c *StudyGroupController) RevokeInvitation(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+	invitationID, err := strconv.Atoi(ctx.Param("invitation_id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid invitation id")
+		return
+	}
+
+	if code := c.studyGroupService.RevokeInvitation(ctx, ctx.GetString(consts.UserIDContextKey), id, invitationID); code == response.CodeSuccess {
+		response.SuccessResponse(ctx, code, nil)
+	} else {
+		response.ErrorResponse(ctx, code, "")
+	}
+}
+
+// Join godoc
+// @Summary      Join a study group
+// @Description  Join with the token of an invitation or join link. Email invitations only work for the account with that email.
+// @Tags         study-groups
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.JoinStudyGroupRequest  true  "Invitation token"
+// @Success      200      {object}  response.ResponseData         "Joined group with its members"
+// @Failure      200      {object}  response.ResponseData         "Error response (invalid or expired invitation)"
+// @Router       /study-groups/join [post]
+func (c *StudyGroupController) Join(ctx *gin.Context) {
+	var payload models.JoinStudyGroupRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	group, code := c.studyGroupService.Join(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, group)
+}
+
+// Leave godoc
+// @Summary      Leave a study group
+// @Description  Leave the group; the notes the user shared are removed from it. The owner has to hand over the group or delete it instead.
+// @Tags         study-groups
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int                    true  "Study group ID"
+// @Success      200  {object}  response.ResponseData  "Left the group"
+// @Failure      200  {object}  response.ResponseData  "Error response (owner cannot leave)"
+// @Router       /study-groups/{id}/leave [post]
+func (c *StudyGroupController) Leave(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+
+	if code := c.studyGroupService.Leave(ctx, ctx.GetString(consts.UserIDContextKey), id); code == response.CodeSuccess {
+		response.SuccessResponse(ctx, code, nil)
+	} else {
+		response.ErrorResponse(ctx, code, "")
+	}
+}
+
+// RemoveMember godoc
+// @Summary      Remove a member
+// @Description  Kick a member with a lower role than yours: admins remove members, the owner also removes admins. Their shared notes are removed from the group.
+// @Tags         study-groups
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                    true  "Study group ID"
+// @Param        user_id  path      string                 true  "Member's user ID"
+// @Success      200      {object}  response.ResponseData  "Member removed"
+// @Failure      200      {object}  response.ResponseData  "Error response (forbidden)"
+// @Router       /study-groups/{id}/members/{user_id} [delete]
+func (c *StudyGroupController) RemoveMember(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+
+	if code := c.studyGroupService.RemoveMember(ctx, ctx.GetString(consts.UserIDContextKey), id, ctx.Param("user_id")); code == response.CodeSuccess {
+		response.SuccessResponse(ctx, code, nil)
+	} else {
+		response.ErrorResponse(ctx, code, "")
+	}
+}
+
+// UpdateMemberRole godoc
+// @Summary      Change a member's role
+// @Description  Set a member's role (0 member, 1 admin, 2 owner); owner only. Making someone owner hands over the group and leaves you admin.
+// @Tags         study-groups
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                             true  "Study group ID"
+// @Param        user_id  path      string                          true  "Member's user ID"
+// @Param        request  body      models.UpdateMemberRoleRequest  true  "Role"
+// @Success      200      {object}  response.ResponseData           "Group with its members"
+// @Failure      200      {object}  response.ResponseData           "Error response (member not found)"
+// @Router       /study-groups/{id}/members/{user_id}/role [put]
+func (c *StudyGroupController) UpdateMemberRole(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+
+	var payload models.UpdateMemberRoleRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	group, code := c.studyGroupService.UpdateMemberRole(ctx, ctx.GetString(consts.UserIDContextKey), id, ctx.Param("user_id"), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, group)
+}
+
+// ShareNote godoc
+// @Summary      Share a note into a study group
+// @Description  Share one of your notes of the group's course with the members
+// @Tags         study-groups
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                      true  "Study group ID"
+// @Param        request  body      models.ShareNoteRequest  true  "Note"
+// @Success      200      {object}  response.ResponseData    "Notes shared in the group"
+// @Failure      200      {object}  response.ResponseData    "Error response (note of another course)"
+// @Router       /study-groups/{id}/notes [post]
+func (c *StudyGroupController) ShareNote(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+
+	var payload models.ShareNoteRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	notes, code := c.studyGroupService.ShareNote(ctx, ctx.GetString(consts.UserIDContextKey), id, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, notes)
+}
+
+// ListSharedNotes godoc
+// @Summary      List shared notes
+// @Description  Notes the members shared into the group, most recently shared first, without their content
+// @Tags         study-groups
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int                    true  "Study group ID"
+// @Success      200  {object}  response.ResponseData  "Shared notes"
+// @Failure      200  {object}  response.ResponseData  "Error response (study group not found)"
+// @Router       /study-groups/{id}/notes [get]
+func (c *StudyGroupController) ListSharedNotes(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+
+	notes, code := c.studyGroupService.ListSharedNotes(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, notes)
+}
+
+// GetSharedNote godoc
+// @Summary      Get a shared note
+// @Description  Read a note shared into the group, with its content
+// @Tags         study-groups
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                    true  "Study group ID"
+// @Param        note_id  path      int                    true  "Note ID"
+// @Success      200      {object}  response.ResponseData  "Shared note"
+// @Failure      200      {object}  response.ResponseData  "Error response (note not shared in the group)"
+// @Router       /study-groups/{id}/notes/{note_id} [get]
+func (c *StudyGroupController) GetSharedNote(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+	noteID, err := strconv.Atoi(ctx.Param("note_id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid note id")
+		return
+	}
+
+	note, code := c.studyGroupService.GetSharedNote(ctx, ctx.GetString(consts.UserIDContextKey), id, noteID)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, note)
+}
+
+// UnshareNote godoc
+// @Summary      Unshare a note
+// @Description  Remove a note from the group; its author, admins and the owner can
+// @Tags         study-groups
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                    true  "Study group ID"
+// @Param        note_id  path      int                    true  "Note ID"
+// @Success      200      {object}  response.ResponseData  "Note unshared"
+// @Failure      200      {object}  response.ResponseData  "Error response (forbidden)"
+// @Router       /study-groups/{id}/notes/{note_id} [delete]
+func (c *StudyGroupController) UnshareNote(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid study group id")
+		return
+	}
+	noteID, err := strconv.Atoi(ctx.Param("note_id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid note id")
+		return
+	}
+
+	if code := c.studyGroupService.UnshareNote(ctx, ctx.GetString(consts.UserIDContextKey), id, noteID); code == response.CodeSuccess {
+		response.SuccessResponse(ctx, code, nil)
+	} else {
+		response.ErrorResponse(ctx, code, "")
+	}
+}
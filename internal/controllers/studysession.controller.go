@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type StudySessionController struct {
+	studySessionService services.IStudySessionService
+}
+
+func NewStudySessionController(studySessionService services.IStudySessionService) *StudySessionController {
+	return &StudySessionController{
+		studySessionService: studySessionService,
+	}
+}
+
+// StartSession godoc
+// @Summary      Start a study session
+// @Description  Start the timer for studying a course, optionally from one of its notes. Only one session can run at a time.
+// @Tags         study-sessions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.StartStudySessionRequest  true  "Course and note studied"
+// @Success      200      {object}  response.ResponseData            "Running session"
+// @Failure      200      {object}  response.ResponseData            "Error response (course or note not found, another session running)"
+// @Router       /study-sessions/start [post]
+func (c *StudySessionController) StartSession(ctx *gin.Context) {
+	var payload models.StartStudySessionRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	session, code := c.studySessionService.StartSession(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, session)
+}
+
+// StopSession godoc
+// @Summary      Stop a study session
+// @Description  Stop a running session and record the pomodoros completed. Sessions count at most 12 hours.
+// @Tags         study-sessions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                             true   "Session ID"
+// @Param        request  body      models.StopStudySessionRequest  false  "Pomodoro count"
+// @Success      200      {object}  response.ResponseData           "Finished session"
+// @Failure      200      {object}  response.ResponseData           "Error response (session not found or already stopped)"
+// @Router       /study-sessions/{id}/stop [post]
+func (c *StudySessionController) StopSession(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid session id")
+		return
+	}
+
+	var payload models.StopStudySessionRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&payload); err != nil {
+			response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+			return
+		}
+	}
+
+	session, code := c.studySessionService.StopSession(ctx, ctx.GetString(consts.UserIDContextKey), id, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, session)
+}
+
+// LogSession godoc
+// @Summary      Log a study session
+// @Description  Record a finished session after the fact. It may not be in the future, overlap another session or last over 12 hours.
+// @Tags         study-sessions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.LogStudySessionRequest  true  "Finished session"
+// @Success      200      {object}  response.ResponseData          "Logged session"
+// @Failure      200      {object}  response.ResponseData          "Error response (invalid time, overlap, course or note not found)"
+// @Router       /study-sessions [post]
+func (c *StudySessionController) LogSession(ctx *gin.Context) {
+	var payload models.LogStudySessionRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	session, code := c.studySessionService.LogSession(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, session)
+}
+
+// ListSessions godoc
+// @Summary      List study sessions
+// @Description  The user's study sessions, newest first. Dates are in the user's timezone.
+// @Tags         study-sessions
+// @Produce      json
+// @Security     BearerAuth
+// @Param        course_id  query     int     false  "Filter by course"
+// @Param        from       query     string  false  "Started on or after (YYYY-MM-DD)"
+// @Param        to         query     string  false  "Started on or before (YYYY-MM-DD)"
+// @Success      200        {object}  response.ResponseData  "List of study sessions"
+// @Router       /study-sessions [get]
+func (c *StudySessionController) ListSessions(ctx *gin.Context) {
+	var query models.StudySessionQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	sessions, code := c.studySessionService.ListSessions(ctx, ctx.GetString(consts.UserIDContextKey), &query)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, sessions)
+}
+
+// DeleteSession godoc
+// @Summary      Delete a study session
+// @Tags         study-sessions
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Session ID"
+// @Success      200  {object}  response.ResponseData  "Session deleted"
+// @Failure      200  {object}  response.ResponseData  "Error response (session not found)"
+// @Router       /study-sessions/{id} [delete]
+func (c *StudySessionController) DeleteSession(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid session id")
+		return
+	}
+
+	if code := c.studySessionService.DeleteSession(ctx, ctx.GetString(consts.UserIDContextKey), id); code == response.CodeSuccess {
+		response.SuccessResponse(ctx, code, nil)
+	} else {
+		response.ErrorResponse(ctx, code, "")
+	}
+}
+
+// GetStudyTime godoc
+// @Summary      Get study time analytics
+// @Description  Study time per course and week next to the time accepted study plans scheduled, the current and longest streak of study days, and how study time per graded course correlates with its GPA. Weeks start on Monday in the user's timezone; the range defaults to the last eight weeks.
+// @Tags         analytics
+// @Produce      json
+// @Security     BearerAuth
+// @Param        from  query     string  false  "First day (YYYY-MM-DD)"
+// @Param        to    query     string  false  "Last day (YYYY-MM-DD), defaults to today"
+// @Success      200   {object}  response.ResponseData  "Study time analytics"
+// @Failure      200   {object}  response.ResponseData  "Error response (invalid range)"
+// @Router       /analytics/study-time [get]
+func (c *StudySessionController) GetStudyTime(ctx *gin.Context) {
+	var query models.StudyTimeQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	analytics, code := c.studySessionService.GetStudyTime(ctx, ctx.GetString(consts.UserIDContextKey), &query)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, analytics)
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type SummaryController struct {
+	summaryService  services.ISummaryService
+	progressService services.IProgressService
+}
+
+func NewSummaryController(summaryService services.ISummaryService, progressService services.IProgressService) *SummaryController {
+	return &SummaryController{
+		summaryService:  summaryService,
+		progressService: progressService,
+	}
+}
+
+// RequestSummary godoc
+// @Summary      Summarize a lecture note
+// @Description  Queue an AI summary of the note as bullet points and key concepts. Returns the new summary version with status pending; poll GET /notes/{id}/summaries/{version}, or follow its /events stream, until the status is done (2) or failed (3). While a summary of the same note version and language is still running, that one is returned instead.
+// @Tags         notes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                          true   "Note ID"
+// @Param        request  body      models.CreateSummaryRequest  false  "Output language"
+// @Success      200      {object}  response.ResponseData        "Pending summary"
+// @Failure      200      {object}  response.ResponseData        "Error response (note not found, empty note, unsupported language)"
+// @Router       /notes/{id}/summaries [post]
+func (c *SummaryController) RequestSummary(ctx *gin.Context) {
+	id, ok := noteID(ctx)
+	if !ok {
+		return
+	}
+	var payload models.CreateSummaryRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&payload); err != nil {
+			response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+			return
+		}
+	}
+
+	summary, code := c.summaryService.RequestSummary(ctx, ctx.GetString(consts.UserIDContextKey), id, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, summary)
+}
+
+// ListSummaries godoc
+// @Summary      List note summaries
+// @Description  List every summary version of the note, newest first, including pending and failed ones
+// @Tags         notes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Note ID"
+// @Success      200  {object}  response.ResponseData  "List of summaries"
+// @Failure      200  {object}  response.ResponseData  "Error response (note not found)"
+// @Router       /notes/{id}/summaries [get]
+func (c *SummaryController) ListSummaries(ctx *gin.Context) {
+	id, ok := noteID(ctx)
+	if !ok {
+		return
+	}
+
+	summaries, code := c.summaryService.ListSummaries(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, summaries)
+}
+
+// GetSummary godoc
+// @Summary      Get a note summary
+// @Description  Get one summary version. Status is 0 pending, 1 processing, 2 done or 3 failed; bullets and key concepts are set once done.
+// @Tags         notes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int  true  "Note ID"
+// @Param        version  path      int  true  "Summary version"
+// @Success      200      {object}  response.ResponseData  "Summary"
+// @Failure      200      {object}  response.ResponseData  "Error response (note or summary not found)"
+// @Router       /notes/{id}/summaries/{version} [get]
+func (c *SummaryController) GetSummary(ctx *gin.Context) {
+	id, ok := noteID(ctx)
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid summary version")
+		return
+	}
+
+	summary, code := c.summaryService.GetSummary(ctx, ctx.GetString(consts.UserIDContextKey), id, version)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, summary)
+}
+
+// StreamSummaryProgress godoc
+// @Summary      Follow a note summary
+// @Description  Stream the summary's progress as Server-Sent Events named "progress" until one has final set. Reconnecting with Last-Event-ID resumes after that event; once the summary has finished a reconnect gets 204.
+// @Tags         notes
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        id             path      int     true   "Note ID"
+// @Param        version        path      int     true   "Summary version"
+// @Param        Last-Event-ID  header    string  false  "ID of the last event received"
+// @Success      200            {object}  models.JobProgress     "Progress events"
+// @Failure      200            {object}  response.ResponseData  "Error response (note or summary not found)"
+// @Router       /notes/{id}/summaries/{version}/events [get]
+func (c *SummaryController) StreamSummaryProgress(ctx *gin.Context) {
+	id, ok := noteID(ctx)
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid summary version")
+		return
+	}
+
+	summary, code := c.summaryService.GetSummary(ctx, ctx.GetString(consts.UserIDContextKey), id, version)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	final := summary.Status == consts.SummaryStatus.DONE || summary.Status == consts.SummaryStatus.FAILED
+	streamProgress(ctx, c.progressService, consts.JobProgressTopic.SUMMARY, summary.ID, jobState(summary.Status, final, summary.Error))
+}
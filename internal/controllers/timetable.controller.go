@@ -0,0 +1,316 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type TimetableController struct {
+	timetableService services.ITimetableService
+	importService    services.ITimetableImportService
+}
+
+func NewTimetableController(timetableService services.ITimetableService, importService services.ITimetableImportService) *TimetableController {
+	return &TimetableController{
+		timetableService: timetableService,
+		importService:    importService,
+	}
+}
+
+// GetTimetable godoc
+// @Summary      Get the timetable for a date range
+// @Description  Expand weekly and bi-weekly class sessions into dated occurrences between from and to (inclusive, at most 366 days). Cancelled dates are left out.
+// @Tags         timetable
+// @Produce      json
+// @Security     BearerAuth
+// @Param        from  query     string  true  "Range start (YYYY-MM-DD)"
+// @Param        to    query     string  true  "Range end (YYYY-MM-DD)"
+// @Success      200   {object}  response.ResponseData  "Occurrences ordered by date and start time"
+// @Failure      200   {object}  response.ResponseData  "Error response (invalid range)"
+// @Router       /timetable [get]
+func (c *TimetableController) GetTimetable(ctx *gin.Context) {
+	var query models.TimetableQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	entries, code := c.timetableService.GetTimetable(ctx, ctx.GetString(consts.UserIDContextKey), &query)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, entries)
+}
+
+// CreateSession godoc
+// @Summary      Create a class session
+// @Description  Add a recurring class session for a course. Sessions overlapping another session are rejected unless allow_conflicts is set, in which case the clashes are returned as warnings.
+// @Tags         timetable
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      models.CreateClassSessionRequest  true  "Session data"
+// @Success      200      {object}  response.ResponseData             "Created session and conflict warnings"
+// @Failure      200      {object}  response.ResponseData             "Error response (invalid time, conflict, course not found, etc.)"
+// @Router       /timetable/sessions [post]
+func (c *TimetableController) CreateSession(ctx *gin.Context) {
+	var payload models.CreateClassSessionRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	result, code := c.timetableService.CreateSession(ctx, ctx.GetString(consts.UserIDContextKey), &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, conflictMessage(code, result))
+		return
+	}
+	response.SuccessResponse(ctx, code, result)
+}
+
+// ListSessions godoc
+// @Summary      List class sessions
+// @Tags         timetable
+// @Produce      json
+// @Security     BearerAuth
+// @Param        course_id  query     int  false  "Filter by course"
+// @Success      200        {object}  response.ResponseData  "List of sessions"
+// @Router       /timetable/sessions [get]
+func (c *TimetableController) ListSessions(ctx *gin.Context) {
+	var query models.ClassSessionQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	sessions, code := c.timetableService.ListSessions(ctx, ctx.GetString(consts.UserIDContextKey), query.CourseID)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, sessions)
+}
+
+// GetSession godoc
+// @Summary      Get a class session
+// @Tags         timetable
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Session ID"
+// @Success      200  {object}  response.ResponseData  "Session with its cancelled dates"
+// @Failure      200  {object}  response.ResponseData  "Error response (session not found)"
+// @Router       /timetable/sessions/{id} [get]
+func (c *TimetableController) GetSession(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid session id")
+		return
+	}
+
+	session, code := c.timetableService.GetSession(ctx, ctx.GetString(consts.UserIDContextKey), id)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, session)
+}
+
+// UpdateSession godoc
+// @Summary      Update a class session
+// @Description  Partially update a session. The new schedule is checked for conflicts like on create; an empty end_date makes the series open-ended.
+// @Tags         timetable
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                               true  "Session ID"
+// @Param        request  body      models.UpdateClassSessionRequest  true  "Fields to update"
+// @Success      200      {object}  response.ResponseData             "Updated session and conflict warnings"
+// @Failure      200      {object}  response.ResponseData             "Error response (session not found, conflict, etc.)"
+// @Router       /timetable/sessions/{id} [put]
+func (c *TimetableController) UpdateSession(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid session id")
+		return
+	}
+
+	var payload models.UpdateClassSessionRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	result, code := c.timetableService.UpdateSession(ctx, ctx.GetString(consts.UserIDContextKey), id, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, conflictMessage(code, result))
+		return
+	}
+	response.SuccessResponse(ctx, code, result)
+}
+
+// DeleteSession godoc
+// @Summary      Delete a class session
+// @Tags         timetable
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Session ID"
+// @Success      200  {object}  response.ResponseData  "Session deleted"
+// @Failure      200  {object}  response.ResponseData  "Error response (session not found)"
+// @Router       /timetable/sessions/{id} [delete]
+func (c *TimetableController) DeleteSession(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid session id")
+		return
+	}
+
+	if code := c.timetableService.DeleteSession(ctx, ctx.GetString(consts.UserIDContextKey), id); code == response.CodeSuccess {
+		response.SuccessResponse(ctx, code, nil)
+	} else {
+		response.ErrorResponse(ctx, code, "")
+	}
+}
+
+// AddException godoc
+// @Summary      Cancel one occurrence
+// @Description  Mark a single date of a session as cancelled (e.g. a public holiday). The date must be a scheduled occurrence.
+// @Tags         timetable
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                                        true  "Session ID"
+// @Param        request  body      models.CreateClassSessionExceptionRequest  true  "Cancelled date"
+// @Success      200      {object}  response.ResponseData                      "Updated session"
+// @Failure      200      {object}  response.ResponseData                      "Error response (not an occurrence, etc.)"
+// @Router       /timetable/sessions/{id}/exceptions [post]
+func (c *TimetableController) AddException(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid session id")
+		return
+	}
+
+	var payload models.CreateClassSessionExceptionRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	session, code := c.timetableService.AddException(ctx, ctx.GetString(consts.UserIDContextKey), id, &payload)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, session)
+}
+
+// RemoveException godoc
+// @Summary      Restore a cancelled occurrence
+// @Tags         timetable
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int     true  "Session ID"
+// @Param        date  path      string  true  "Cancelled date (YYYY-MM-DD)"
+// @Success      200   {object}  response.ResponseData  "Updated session"
+// @Failure      200   {object}  response.ResponseData  "Error response (date was not cancelled, etc.)"
+// @Router       /timetable/sessions/{id}/exceptions/{date} [delete]
+func (c *TimetableController) RemoveException(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, "invalid session id")
+		return
+	}
+
+	session, code := c.timetableService.RemoveException(ctx, ctx.GetString(consts.UserIDContextKey), id, ctx.Param("date"))
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, "")
+		return
+	}
+	response.SuccessResponse(ctx, code, session)
+}
+
+// ImportTimetable godoc
+// @Summary      Import a timetable from an iCalendar file
+// @Description  Map VEVENTs (with weekly/bi-weekly RRULEs) to courses and class sessions. Unknown course codes create courses in the given or detected semester. Without commit=true only a preview is returned. Re-importing a source (the calendar's name unless given) updates its sessions matched by event UID instead of duplicating them and removes its sessions whose events left the file; sessions imported from other sources are kept. Sessions that clash are only saved with allow_conflicts=true.
+// @Tags         timetable
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file             formData  file    true   "iCalendar (.ics) file"
+// @Param        source           formData  string  false  "Timetable the file replaces, defaults to the calendar's name"
+// @Param        semester_id      formData  int     false  "Semester for newly created courses"
+// @Param        commit           formData  bool    false  "Save the import instead of previewing it"
+// @Param        allow_conflicts  formData  bool    false  "Save the import even when sessions clash"
+// @Success      200              {object}  response.ResponseData  "Import preview or result"
+// @Failure      200              {object}  response.ResponseData  "Error response (invalid file, no semester, clashing sessions, etc.)"
+// @Router       /timetable/import [post]
+func (c *TimetableController) ImportTimetable(ctx *gin.Context) {
+	var payload models.TimetableImportRequest
+	if err := ctx.ShouldBind(&payload); err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+	if payload.File.Size > consts.TIMETABLE_IMPORT_MAX_BYTES {
+		response.ErrorResponse(ctx, response.CodeTimetableImportTooLarge, "")
+		return
+	}
+
+	file, err := payload.File.Open()
+	if err != nil {
+		response.ErrorResponse(ctx, response.CodeInvalidParams, err.Error())
+		return
+	}
+	defer file.Close()
+
+	result, code := c.importService.ImportTimetable(ctx, ctx.GetString(consts.UserIDContextKey), file, payload.Source, payload.SemesterID, payload.Commit, payload.AllowConflicts)
+	if code != response.CodeSuccess {
+		response.ErrorResponse(ctx, code, importConflictMessage(code, result))
+		return
+	}
+	response.SuccessResponse(ctx, code, result)
+}
+
+// conflictMessage spells out the clashing sessions when a save was rejected for conflicts
+func conflictMessage(code int, result *models.ClassSessionResponse) string {
+	if code != response.CodeClassSessionConflict || result == nil || len(result.Conflicts) == 0 {
+		return ""
+	}
+
+	clashes := make([]string, 0, len(result.Conflicts))
+	for _, conflict := range result.Conflicts {
+		clashes = append(clashes, fmt.Sprintf("session %d (%s) on %s %s-%s",
+			conflict.SessionID, conflict.CourseName, conflict.Date, conflict.StartTime, conflict.EndTime))
+	}
+	return response.GetMessageByCode(code) + ": " + strings.Join(clashes, "; ")
+}
+
+// importConflictMessage spells out the clashing sessions when an import was rejected for conflicts
+func importConflictMessage(code int, result *models.TimetableImportResult) string {
+	if code != response.CodeClassSessionConflict || result == nil {
+		return ""
+	}
+
+	var clashes []string
+	for _, session := range result.Sessions {
+		for _, conflict := range session.Conflicts {
+			other := fmt.Sprintf("session %d", conflict.SessionID)
+			if conflict.SessionID == 0 {
+				other = "event " + conflict.UID
+			}
+			clashes = append(clashes, fmt.Sprintf("event %s clashes with %s (%s) on %s %s-%s",
+				session.UID, other, conflict.CourseName, conflict.Date, conflict.StartTime, conflict.EndTime))
+		}
+	}
+	if len(clashes) == 0 {
+		return ""
+	}
+	return response.GetMessageByCode(code) + ": " + strings.Join(clashes, "; ")
+}
@@ -0,0 +1,74 @@
+package initialize
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/pkg/ai"
+	"go.uber.org/zap"
+)
+
+// InitAI sets up the configured LLM provider. Without configuration the
+// deterministic fake provider is used, so AI features work offline.
+func InitAI() {
+	cfg := global.Config.AI
+
+	chatName := strings.ToLower(cfg.Provider)
+	if chatName == "" {
+		chatName = consts.AIProvider.FAKE
+	}
+	embeddingName := strings.ToLower(cfg.EmbeddingProvider)
+	if embeddingName == "" {
+		embeddingName = chatName
+	}
+
+	provider, err := newAIProvider(chatName)
+	if err != nil {
+		global.Log.Error("Failed to initialize AI provider", zap.Error(err), zap.String("provider", chatName))
+		return
+	}
+	if embeddingName != chatName {
+		embedder, err := newAIProvider(embeddingName)
+		if err != nil {
+			global.Log.Error("Failed to initialize AI embedding provider", zap.Error(err), zap.String("provider", embeddingName))
+			return
+		}
+		provider = ai.WithEmbeddings(provider, embedder)
+	}
+
+	global.AI = provider
+	global.Log.Info("AI provider initialized", zap.String("provider", chatName), zap.String("embeddings", embeddingName))
+}
+
+func newAIProvider(name string) (ai.Provider, error) {
+	cfg := global.Config.AI
+	opts := ai.Options{
+		Timeout:    time.Duration(cfg.Timeout) * time.Second,
+		MaxRetries: cfg.MaxRetries,
+	}
+
+	switch name {
+	case consts.AIProvider.OPENAI:
+		return ai.NewOpenAIProvider(ai.OpenAIConfig{
+			BaseURL:        cfg.OpenAI.BaseURL,
+			APIKey:         cfg.OpenAI.APIKey,
+			Organization:   cfg.OpenAI.Organization,
+			ChatModel:      cfg.OpenAI.ChatModel,
+			EmbeddingModel: cfg.OpenAI.EmbeddingModel,
+			Options:        opts,
+		})
+	case consts.AIProvider.ANTHROPIC:
+		return ai.NewAnthropicProvider(ai.AnthropicConfig{
+			BaseURL:   cfg.Anthropic.BaseURL,
+			APIKey:    cfg.Anthropic.APIKey,
+			ChatModel: cfg.Anthropic.ChatModel,
+			Options:   opts,
+		})
+	case consts.AIProvider.FAKE:
+		return ai.NewFakeProvider(), nil
+	}
+	return nil, fmt.Errorf("unknown AI provider %q", name)
+}
@@ -0,0 +1,35 @@
+package initialize
+
+import (
+	"strings"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/pkg/billing"
+	"go.uber.org/zap"
+)
+
+// InitBilling sets up the configured payment provider. Without configuration
+// the fake provider is used, which takes no payments.
+func InitBilling() {
+	cfg := global.Config.Billing
+
+	switch strings.ToLower(cfg.Provider) {
+	case consts.BillingProvider.STRIPE:
+		provider, err := billing.NewStripeProvider(billing.StripeConfig{
+			BaseURL:       cfg.Stripe.BaseURL,
+			SecretKey:     cfg.Stripe.SecretKey,
+			WebhookSecret: cfg.WebhookSecret,
+			Prices:        cfg.Stripe.Prices,
+		})
+		if err != nil {
+			global.Log.Error("Failed to initialize billing provider", zap.Error(err), zap.String("provider", consts.BillingProvider.STRIPE))
+			return
+		}
+		global.Billing = provider
+
+	default:
+		global.Billing = billing.NewFakeProvider(cfg.WebhookSecret)
+	}
+	global.Log.Info("Billing provider initialized", zap.String("provider", global.Billing.Name()))
+}
@@ -53,6 +53,8 @@ func InitGorm() {
 	// Configure GORM
 	gormConfig := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
+		// Report driver errors such as duplicate keys as gorm.ErrDuplicatedKey
+		TranslateError: true,
 	}
 
 	// Open database connection
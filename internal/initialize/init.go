@@ -13,6 +13,9 @@ func Bootstrap() error {
 	InitGorm()
 	InitMailClient()
 	InitRedis()
+	InitStorage()
+	InitAI()
+	InitBilling()
 
 	return nil
 }
@@ -0,0 +1,87 @@
+package initialize
+
+import (
+	"context"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/queue"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// InitQueueClient creates a job queue client on the global Redis connection
+func InitQueueClient() *queue.Client {
+	cfg := global.Config.Queue
+	return queue.NewClient(global.Redis, queue.Options{
+		VisibilityTimeout: time.Duration(cfg.VisibilityTimeout) * time.Second,
+		BackoffBase:       time.Duration(cfg.BackoffBase) * time.Second,
+		BackoffMax:        time.Duration(cfg.BackoffMax) * time.Second,
+	})
+}
+
+// InitJobHandlers registers every background job handler
+func InitJobHandlers(client *queue.Client) *queue.Mux {
+	mux := queue.NewMux()
+	progressStore := repositories.NewRedisProgressStore(global.Redis)
+	usageRepo := repositories.NewUsageRepository(global.Mdb)
+	quotaService := services.NewQuotaService(repositories.NewPlanRepository(global.Mdb), usageRepo, repositories.NewUserRepository(global.Mdb),
+		repositories.NewCourseRepository(global.Mdb), repositories.NewFileRepository(global.Mdb))
+
+	extractionService := services.NewExtractionService(repositories.NewFileRepository(global.Mdb), global.Storage, client, progressStore)
+	queue.Register(mux, consts.JobType.FILE_EXTRACT, func(ctx context.Context, job *queue.Job, payload models.FileExtractPayload) error {
+		return extractionService.ExtractFile(ctx, payload)
+	})
+
+	summarizationService := services.NewSummarizationService(repositories.NewSummaryRepository(global.Mdb), repositories.NewNoteRepository(global.Mdb), global.AI, progressStore, usageRepo)
+	queue.Register(mux, consts.JobType.NOTE_SUMMARIZE, func(ctx context.Context, job *queue.Job, payload models.NoteSummarizePayload) error {
+		return summarizationService.SummarizeNote(ctx, payload)
+	})
+
+	flashcardGenerationService := services.NewFlashcardGenerationService(repositories.NewFlashcardRepository(global.Mdb), repositories.NewNoteRepository(global.Mdb),
+		repositories.NewFileRepository(global.Mdb), repositories.NewUserRepository(global.Mdb), global.AI, progressStore, usageRepo)
+	queue.Register(mux, consts.JobType.FLASHCARD_GENERATE, func(ctx context.Context, job *queue.Job, payload models.FlashcardGeneratePayload) error {
+		return flashcardGenerationService.GenerateFlashcards(ctx, payload)
+	})
+
+	quizGenerationService := services.NewQuizGenerationService(repositories.NewQuizRepository(global.Mdb), repositories.NewNoteRepository(global.Mdb), global.AI, progressStore, usageRepo)
+	queue.Register(mux, consts.JobType.QUIZ_GENERATE, func(ctx context.Context, job *queue.Job, payload models.QuizGeneratePayload) error {
+		return quizGenerationService.GenerateQuiz(ctx, payload)
+	})
+
+	quizGradingService := services.NewQuizGradingService(repositories.NewQuizRepository(global.Mdb), global.AI, usageRepo)
+	queue.Register(mux, consts.JobType.QUIZ_GRADE, func(ctx context.Context, job *queue.Job, payload models.QuizGradePayload) error {
+		return quizGradingService.GradeAttempt(ctx, payload)
+	})
+
+	embeddingService := services.NewEmbeddingService(repositories.NewMySQLVectorStore(global.Mdb), repositories.NewNoteRepository(global.Mdb),
+		repositories.NewFileRepository(global.Mdb), usageRepo, quotaService, global.AI)
+	queue.Register(mux, consts.JobType.EMBEDDING_INDEX, func(ctx context.Context, job *queue.Job, payload models.EmbeddingIndexPayload) error {
+		return embeddingService.IndexSource(ctx, payload)
+	})
+
+	billingService := services.NewBillingService(repositories.NewBillingRepository(global.Mdb), repositories.NewPlanRepository(global.Mdb),
+		repositories.NewUserRepository(global.Mdb), global.Billing, client)
+	queue.Register(mux, consts.JobType.BILLING_DOWNGRADE, func(ctx context.Context, job *queue.Job, payload models.BillingDowngradePayload) error {
+		return billingService.DowngradeExpired(ctx, payload)
+	})
+
+	return mux
+}
+
+// InitQueueWorkers creates one worker per configured queue
+func InitQueueWorkers(client *queue.Client, mux *queue.Mux) []*queue.Worker {
+	queues := global.Config.Queue.Queues
+	if len(queues) == 0 {
+		queues = map[string]int{queue.DefaultQueue: 5}
+	}
+
+	pollInterval := time.Duration(global.Config.Queue.PollInterval) * time.Second
+	workers := make([]*queue.Worker, 0, len(queues))
+	for name, concurrency := range queues {
+		workers = append(workers, queue.NewWorker(client, mux, name, concurrency, pollInterval))
+	}
+	return workers
+}
@@ -41,11 +41,33 @@ func InitRouter() *gin.Engine {
 	r.Use(middleware.SecurityHeaders())
 	r.Use(middleware.LoggerMiddleware()) // Simple, proven logging from fidecwalletserver
 
+	// Routes enqueue background work for the worker process
+	queueClient := InitQueueClient()
+
 	// Setup API routes
 	apiV1 := r.Group("/api/v1")
 	{
 		// Register user routes
 		router.SetupUserRoutes(apiV1)
+		router.SetupReminderRoutes(apiV1)
+		router.SetupNotificationRoutes(apiV1)
+		router.SetupTimetableRoutes(apiV1)
+		router.SetupCalendarRoutes(apiV1)
+		router.SetupNoteRoutes(apiV1, queueClient)
+		router.SetupFileRoutes(apiV1, queueClient)
+		router.SetupSearchRoutes(apiV1)
+		router.SetupFlashcardRoutes(apiV1, queueClient)
+		router.SetupQuizRoutes(apiV1, queueClient)
+		router.SetupAssistantRoutes(apiV1)
+		router.SetupPlanRoutes(apiV1)
+		router.SetupBillingRoutes(apiV1, queueClient)
+		router.SetupPlannerRoutes(apiV1)
+		router.SetupStudySessionRoutes(apiV1)
+		router.SetupAnalyticsRoutes(apiV1)
+		router.SetupAssessmentRoutes(apiV1)
+		router.SetupStudyGroupRoutes(apiV1)
+		router.SetupChatRoutes(apiV1)
+		router.SetupNoteCollabRoutes(apiV1, queueClient)
 
 		// Add other route groups here as needed
 		// router.SetupProductRoutes(apiV1)
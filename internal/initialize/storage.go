@@ -0,0 +1,47 @@
+package initialize
+
+import (
+	"strings"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// InitStorage sets up the configured file storage backend
+func InitStorage() {
+	cfg := global.Config.Storage
+
+	switch strings.ToLower(cfg.Backend) {
+	case "s3":
+		store, err := storage.NewS3Storage(storage.S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			PathStyle: cfg.S3.PathStyle,
+		})
+		if err != nil {
+			global.Log.Error("Failed to initialize S3 storage", zap.Error(err))
+			return
+		}
+		global.Storage = store
+		global.Log.Info("File storage initialized", zap.String("backend", "s3"), zap.String("bucket", cfg.S3.Bucket))
+
+	default:
+		root := cfg.LocalRoot
+		if root == "" {
+			root = consts.STORAGE_DEFAULT_LOCAL_ROOT
+		}
+		baseURL := strings.TrimRight(cfg.PublicBaseURL, "/") + consts.STORAGE_BLOB_PATH
+		store, err := storage.NewLocalStorage(root, baseURL, cfg.SigningSecret)
+		if err != nil {
+			global.Log.Error("Failed to initialize local storage", zap.Error(err))
+			return
+		}
+		global.Storage = store
+		global.Log.Info("File storage initialized", zap.String("backend", "local"), zap.String("root", root))
+	}
+}
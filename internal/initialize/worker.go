@@ -0,0 +1,47 @@
+package initialize
+
+import (
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/queue"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/internal/worker"
+)
+
+// InitNotificationWorker wires the reminder notification worker, or returns nil when disabled
+func InitNotificationWorker() *worker.NotificationWorker {
+	if !global.Config.Notification.Enabled {
+		return nil
+	}
+
+	interval := consts.NOTIFICATION_DEFAULT_SCAN_INTERVAL
+	if global.Config.Notification.ScanInterval > 0 {
+		interval = time.Duration(global.Config.Notification.ScanInterval) * time.Second
+	}
+
+	notifier := services.NewReminderNotifier(
+		repositories.NewReminderRepository(global.Mdb),
+		repositories.NewNotificationRepository(global.Mdb),
+		repositories.NewUserRepository(global.Mdb),
+		repositories.NewCourseRepository(global.Mdb),
+		repositories.NewMailRepository(global.Mdb),
+		helper.NewMailHelper(),
+	)
+	return worker.NewNotificationWorker(notifier, interval)
+}
+
+// InitUploadSweepWorker wires the worker that removes expired presigned uploads
+func InitUploadSweepWorker(client *queue.Client) *worker.UploadSweepWorker {
+	fileService := services.NewFileService(
+		repositories.NewFileRepository(global.Mdb),
+		repositories.NewUserRepository(global.Mdb),
+		repositories.NewCourseRepository(global.Mdb),
+		global.Storage,
+		client,
+	)
+	return worker.NewUploadSweepWorker(fileService, consts.STORAGE_SWEEP_INTERVAL)
+}
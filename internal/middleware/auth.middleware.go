@@ -6,12 +6,16 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
 	"github.com/nas03/scholar-ai/backend/internal/helper"
 	"github.com/nas03/scholar-ai/backend/pkg/response"
 )
 
 type IAuthMiddleware interface {
 	Auth() gin.HandlerFunc
+	// WebSocketAuth also accepts the token in the `token` query parameter,
+	// since browsers cannot set headers on a WebSocket handshake
+	WebSocketAuth() gin.HandlerFunc
 }
 
 type AuthMiddleware struct {
@@ -31,22 +35,42 @@ func (m *AuthMiddleware) Auth() gin.HandlerFunc {
 			return
 		}
 
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-		claims, err := m.jwtHelper.ValidateAuthToken(ctx, token)
-		if err != nil {
-			// Decide whether token is expired or otherwise invalid
-			if errors.Is(err, jwt.ErrTokenExpired) {
-				response.ErrorResponse(ctx, response.CodeTokenExpired, "")
-			} else {
-				response.ErrorResponse(ctx, response.CodeTokenInvalid, err.Error())
-			}
+		m.authenticate(ctx, strings.TrimPrefix(authHeader, "Bearer "))
+	}
+}
+
+func (m *AuthMiddleware) WebSocketAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		if token == "" {
+			token = ctx.Query("token")
+		}
+		if token == "" {
+			response.ErrorResponse(ctx, response.CodeTokenInvalid, "missing Authorization header or token parameter")
 			ctx.Abort()
 			return
 		}
 
-		// attach user info to context for handlers
-		ctx.Set("userID", claims.UserID)
+		m.authenticate(ctx, token)
+	}
+}
 
-		ctx.Next()
+// authenticate validates the token and attaches the user to the context
+func (m *AuthMiddleware) authenticate(ctx *gin.Context, token string) {
+	claims, err := m.jwtHelper.ValidateAuthToken(ctx, token)
+	if err != nil {
+		// Decide whether token is expired or otherwise invalid
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			response.ErrorResponse(ctx, response.CodeTokenExpired, "")
+		} else {
+			response.ErrorResponse(ctx, response.CodeTokenInvalid, err.Error())
+		}
+		ctx.Abort()
+		return
 	}
+
+	// attach user info to context for handlers
+	ctx.Set(consts.UserIDContextKey, claims.UserID)
+
+	ctx.Next()
 }
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+)
+
+type IQuotaMiddleware interface {
+	// AIOperation rejects requests once the plan's AI usage for the billing period is used up
+	AIOperation() gin.HandlerFunc
+}
+
+type QuotaMiddleware struct {
+	quotaService services.IQuotaService
+}
+
+func NewQuotaMiddleware(quotaService services.IQuotaService) IQuotaMiddleware {
+	return &QuotaMiddleware{quotaService: quotaService}
+}
+
+// AIOperation must run after Auth, which provides the user ID. It only turns away
+// users who are out of quota early; the service starting the work reserves the operation.
+func (m *QuotaMiddleware) AIOperation() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if code := m.quotaService.CheckAIOperation(ctx, ctx.GetString(consts.UserIDContextKey)); code != response.CodeSuccess {
+			response.ErrorResponse(ctx, code, "")
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// SemesterGPA is the credit-weighted GPA of the user's courses in a semester.
+// Courses without a grade (GPA 0) are left out of both averages.
+type SemesterGPA struct {
+	SemesterID    int       `json:"semester_id"`
+	Name          string    `json:"name"`
+	StartDate     time.Time `json:"start_date"`
+	EndDate       time.Time `json:"end_date"`
+	Courses       int64     `json:"courses"`
+	Credits       int64     `json:"credits"`
+	GPA           *float64  `json:"gpa"`            // null until a course is graded
+	CumulativeGPA *float64  `json:"cumulative_gpa"` // over this and all earlier semesters
+}
+
+// GroupPerformance is the performance of the courses sharing a tag or a subject
+type GroupPerformance struct {
+	Key           string   `json:"key"` // tag name or subject code, e.g. "CS" for CS101
+	TagID         *int     `json:"tag_id,omitempty"`
+	Courses       int64    `json:"courses"`
+	GradedCourses int64    `json:"graded_courses"`
+	Credits       int64    `json:"credits"`
+	GPA           *float64 `json:"gpa"` // credit-weighted over graded courses
+	MinGPA        *float64 `json:"min_gpa"`
+	MaxGPA        *float64 `json:"max_gpa"`
+}
+
+type PerformanceBreakdown struct {
+	Tags     []GroupPerformance `json:"tags"`
+	Subjects []GroupPerformance `json:"subjects"`
+}
+
+// CreditProgress compares the credits of the user's courses with the degree requirement
+type CreditProgress struct {
+	Required   int     `json:"required"`
+	Earned     int64   `json:"earned"`      // graded at or above the passing grade
+	InProgress int64   `json:"in_progress"` // ungraded, semester not over
+	Failed     int64   `json:"failed"`      // graded below the passing grade
+	Ungraded   int64   `json:"ungraded"`    // semester over but no grade entered
+	Remaining  int64   `json:"remaining"`
+	Percent    float64 `json:"percent"` // earned share of the requirement
+}
+
+type UpdateRequiredCreditsRequest struct {
+	RequiredCredits *int `json:"required_credits" binding:"omitempty,min=1,max=1000"` // null restores the default
+}
+
+// CourseExamResults aggregates the graded assessments, or else the scored exams,
+// of a course without a final grade
+type CourseExamResults struct {
+	CourseID     int
+	CourseCode   string
+	CourseName   string
+	Credits      int
+	GradedWeight float64 // percent of the final grade
+	MeanScore    float64 // weight-averaged score in percent
+	ScoreStdDev  float64
+	Results      int
+}
+
+// CourseForecast projects a course's final grade from its graded assessments or exams.
+// Scores are percentages; the GPA fields convert them to the 4-point scale.
+type CourseForecast struct {
+	CourseID       int     `json:"course_id"`
+	CourseCode     string  `json:"course_code"`
+	CourseName     string  `json:"course_name"`
+	Credits        int     `json:"credits"`
+	GradedWeight   float64 `json:"graded_weight"`
+	CurrentScore   float64 `json:"current_score"` // average of the graded part
+	PredictedScore float64 `json:"predicted_score"`
+	LowScore       float64 `json:"low_score"`
+	HighScore      float64 `json:"high_score"`
+	MinScore       float64 `json:"min_score"` // scoring 0 on everything left
+	MaxScore       float64 `json:"max_score"` // scoring 100 on everything left
+	PredictedGPA   float64 `json:"predicted_gpa"`
+	LowGPA         float64 `json:"low_gpa"`
+	HighGPA        float64 `json:"high_gpa"`
+	Confidence     float64 `json:"confidence"` // probability the low-high range holds the final score
+}
@@ -0,0 +1,44 @@
+package models
+
+// AssessmentInput is one assessment of a course in a replace request
+type AssessmentInput struct {
+	ID       *int     `json:"id"` // an existing assessment of the course, omitted to add one
+	Name     string   `json:"name" binding:"required,max=255"`
+	Weight   float64  `json:"weight" binding:"gt=0,lte=100"` // percent of the final grade
+	MaxScore float64  `json:"max_score" binding:"gt=0"`
+	Score    *float64 `json:"score" binding:"omitempty,gte=0"` // obtained, at most max_score
+	DueDate  *string  `json:"due_date"`                        // YYYY-MM-DD
+}
+
+// ReplaceAssessmentsRequest sets all assessments of a course at once, so the
+// weights can be checked to add up to 100. Assessments left out are deleted.
+type ReplaceAssessmentsRequest struct {
+	Assessments []AssessmentInput `json:"assessments" binding:"max=50,dive"`
+}
+
+type RecordAssessmentScoreRequest struct {
+	Score *float64 `json:"score" binding:"omitempty,gte=0"` // null clears the score
+}
+
+// AssessmentGradeTarget is what the ungraded assessments need for the course
+// to end on a step of the 4-point scale
+type AssessmentGradeTarget struct {
+	GPA        float64  `json:"gpa"`
+	MinPercent float64  `json:"min_percent"` // lowest final grade earning the GPA
+	Needed     *float64 `json:"needed"`      // average percent needed on the ungraded assessments, null when out of reach
+	Secured    bool     `json:"secured"`     // reached whatever the ungraded assessments score
+}
+
+// AssessmentSummary is a course's assessments with the grade they add up to.
+// Grades are percentages of the final grade.
+type AssessmentSummary struct {
+	CourseID        int                     `json:"course_id"`
+	Assessments     []Assessment            `json:"assessments"`
+	GradedWeight    float64                 `json:"graded_weight"`
+	RemainingWeight float64                 `json:"remaining_weight"`
+	CurrentGrade    *float64                `json:"current_grade"` // weighted average of the graded assessments
+	SecuredGrade    float64                 `json:"secured_grade"` // earned so far, as if the rest scored 0
+	FinalGrade      *float64                `json:"final_grade"`   // once every assessment is graded
+	GPA             float64                 `json:"gpa"`           // the course's GPA
+	Targets         []AssessmentGradeTarget `json:"targets"`
+}
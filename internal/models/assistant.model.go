@@ -0,0 +1,55 @@
+package models
+
+type CreateConversationRequest struct {
+	CourseID *int   `json:"course_id"` // limits retrieval to one course
+	Title    string `json:"title" binding:"max=255"`
+}
+
+type SendMessageRequest struct {
+	Content string `json:"content" binding:"required,max=4000"`
+}
+
+// EmbeddingIndexPayload is the payload of a job (re)embedding a note or a file; exactly one ID is set
+type EmbeddingIndexPayload struct {
+	NoteID *int   `json:"note_id,omitempty"`
+	FileID *int   `json:"file_id,omitempty"`
+	UserID string `json:"user_id"`
+}
+
+// VectorQuery finds the chunks closest to Vector among the user's embeddings of Model
+type VectorQuery struct {
+	UserID   string
+	CourseID *int
+	Model    string
+	Vector   []float32
+	TopK     int
+	MinScore float64
+}
+
+// VectorMatch is a retrieved chunk with its cosine similarity and the title of its note or file
+type VectorMatch struct {
+	Embedding
+	Score float64
+	Title string
+}
+
+// Citation points from an assistant reply back to a source chunk. Number is
+// the [n] marker used in the reply.
+type Citation struct {
+	Number     int     `json:"number"`
+	NoteID     *int    `json:"note_id,omitempty"`
+	FileID     *int    `json:"file_id,omitempty"`
+	Title      string  `json:"title"`
+	ChunkIndex int     `json:"chunk_index"`
+	PageStart  *int    `json:"page_start,omitempty"`
+	PageEnd    *int    `json:"page_end,omitempty"`
+	Snippet    string  `json:"snippet"`
+	Score      float64 `json:"score"`
+}
+
+// AssistantReply is the stored question and answer of one exchange
+type AssistantReply struct {
+	Question  AssistantMessage `json:"question"`
+	Answer    AssistantMessage `json:"answer"`
+	Citations []Citation       `json:"citations"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type CreateCheckoutRequest struct {
+	PlanCode string `json:"plan_code" binding:"required"`
+}
+
+// CheckoutResponse points at the provider's hosted payment page
+type CheckoutResponse struct {
+	SessionID string    `json:"session_id"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// BillingDowngradePayload moves the user to the free plan once the grace
+// period of their subscription is over
+type BillingDowngradePayload struct {
+	SubscriptionID int    `json:"subscription_id"`
+	UserID         string `json:"user_id"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type UpdateTimezoneRequest struct {
+	Timezone string `json:"timezone" binding:"required"` // IANA name, e.g. Europe/Berlin
+}
+
+// CalendarFeedResponse describes the user's iCalendar subscription
+type CalendarFeedResponse struct {
+	URL       string    `json:"url"` // secret URL calendar apps poll without a JWT
+	Timezone  string    `json:"timezone"`
+	RotatedAt time.Time `json:"rotated_at"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// ChatMessageQuery pages backwards through a group's messages
+type ChatMessageQuery struct {
+	Before int `form:"before" binding:"omitempty,min=1"`        // only messages with a smaller id; omit for the latest
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"` // defaults to 50
+}
+
+type SendChatMessageRequest struct {
+	Body     string `json:"body" binding:"required"`
+	ClientID string `json:"client_id" binding:"omitempty,max=64"` // echoed back so the sender can match its pending message
+}
+
+type ChatMessage struct {
+	ID        int       `json:"id"`
+	GroupID   int       `json:"group_id"`
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ChatMessagePage lists messages newest first
+type ChatMessagePage struct {
+	Messages   []ChatMessage `json:"messages"`
+	NextBefore *int          `json:"next_before"` // pass as before to load older messages; null at the start of the chat
+}
+
+// ChatClientFrame is a frame a client sends over the chat connection:
+// {"type":"message","body":"hi","client_id":"c1"} or {"type":"typing","typing":true}
+type ChatClientFrame struct {
+	Type     string `json:"type"`
+	Body     string `json:"body,omitempty"`
+	ClientID string `json:"client_id,omitempty"`
+	Typing   bool   `json:"typing,omitempty"`
+}
+
+// ChatEvent is sent to the clients of a group chat; which fields are set
+// depends on the type, see consts.ChatEventType
+type ChatEvent struct {
+	Type     string       `json:"type"`
+	GroupID  int          `json:"group_id"`
+	Message  *ChatMessage `json:"message,omitempty"`
+	ClientID string       `json:"client_id,omitempty"`
+	UserID   string       `json:"user_id,omitempty"` // who is typing, or the member removed; empty when the whole group is gone
+	Typing   *bool        `json:"typing,omitempty"`
+	Online   []string     `json:"online,omitempty"` // ids of the members connected to the chat
+	Code     int          `json:"code,omitempty"`
+	Error    string       `json:"error,omitempty"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+type UploadFileRequest struct {
+	File     *multipart.FileHeader `form:"file" binding:"required" swaggerignore:"true"`
+	CourseID *int                  `form:"course_id"`
+}
+
+// CreateFileUploadRequest announces a direct upload to object storage
+type CreateFileUploadRequest struct {
+	Filename string `json:"filename" binding:"required,max=255"`
+	Size     int64  `json:"size" binding:"required,gt=0"` // bytes
+	CourseID *int   `json:"course_id"`
+}
+
+// CompleteFileUploadRequest confirms the direct upload announced by CreateFileUploadRequest
+type CompleteFileUploadRequest struct {
+	UploadID string `json:"upload_id" binding:"required,len=32"`
+}
+
+// FileUploadResponse tells the client where and how to PUT the file body.
+// The upload must be confirmed with POST /files/{id}/complete afterwards.
+type FileUploadResponse struct {
+	File      *File             `json:"file"`
+	UploadID  string            `json:"upload_id"` // confirms the upload; valid once
+	UploadURL string            `json:"upload_url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"` // headers the upload request must carry
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+type FileDownloadResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type FileQuery struct {
+	CourseID *int `form:"course_id"`
+}
+
+type StorageUsage struct {
+	Tier       string `json:"tier"`
+	UsedBytes  int64  `json:"used_bytes"`
+	QuotaBytes int64  `json:"quota_bytes"`
+	MaxFile    int64  `json:"max_file_bytes"`
+}
+
+// FileExtractPayload is the payload of a file text extraction job
+type FileExtractPayload struct {
+	FileID int    `json:"file_id"`
+	UserID string `json:"user_id"`
+}
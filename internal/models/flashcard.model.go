@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+type CreateFlashcardRequest struct {
+	CourseID *int   `json:"course_id"`
+	Front    string `json:"front" binding:"required,max=1000"`
+	Back     string `json:"back" binding:"required,max=4000"`
+}
+
+type UpdateFlashcardRequest struct {
+	CourseID *int    `json:"course_id"`
+	Front    *string `json:"front" binding:"omitempty,min=1,max=1000"`
+	Back     *string `json:"back" binding:"omitempty,min=1,max=4000"`
+}
+
+type FlashcardQuery struct {
+	CourseID     *int `form:"course_id"`
+	NoteID       *int `form:"note_id"`
+	FileID       *int `form:"file_id"`
+	GenerationID *int `form:"generation_id"`
+}
+
+type DueFlashcardQuery struct {
+	CourseID *int `form:"course_id"`
+	Limit    int  `form:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+// DueFlashcards are the cards to review on the user's current date
+type DueFlashcards struct {
+	Date  string      `json:"date"`  // today in the user's timezone, YYYY-MM-DD
+	Total int64       `json:"total"` // all due cards, Cards holds at most the limit
+	Cards []Flashcard `json:"cards"`
+}
+
+type ReviewFlashcardRequest struct {
+	Grade *int `json:"grade" binding:"required,min=0,max=5"` // SM-2 grade, 0 (blackout) to 5 (perfect recall)
+}
+
+type GenerateFlashcardsRequest struct {
+	NoteID   *int   `json:"note_id"` // exactly one of note_id and file_id
+	FileID   *int   `json:"file_id"`
+	Count    int    `json:"count" binding:"omitempty,min=1,max=50"` // defaults to 10
+	Language string `json:"language"`                               // en, vi, fr, de, es, ja, ko or zh; defaults to en
+}
+
+// FlashcardGeneratePayload is the payload of a flashcard generation job
+type FlashcardGeneratePayload struct {
+	GenerationID  int    `json:"generation_id"`
+	UserID        string `json:"user_id"`
+	ReservationID int    `json:"reservation_id,omitempty"` // usage reservation the job completes or releases
+}
+
+type FlashcardFilter struct {
+	UserID       string
+	CourseID     *int
+	NoteID       *int
+	FileID       *int
+	GenerationID *int
+}
+
+type DueFlashcardFilter struct {
+	UserID   string
+	CourseID *int
+	Today    time.Time
+	Limit    int
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
@@ -18,13 +19,17 @@ type User struct {
 	Email           string         `gorm:"uniqueIndex;not null;size:255" json:"email"`
 	Password        string         `gorm:"type:text;not null" json:"-"` // Never expose password in JSON
 	PhoneNumber     sql.NullString `gorm:"size:10" json:"phone_number,omitempty"`
-	AccountStatus   int8           `gorm:"not null;default:0" json:"account_status"`    // account status (0=inactive, 1=active)
-	IsEmailVerified int8           `gorm:"not null;default:0" json:"is_email_verified"` // email verification (0=unverified, 1=verified)
-	IsPhoneVerified int8           `gorm:"not null;default:0" json:"is_phone_verified"` // phone verification (0=unverified, 1=verified)
+	AccountStatus   int8           `gorm:"not null;default:0" json:"account_status"`       // account status (0=inactive, 1=active)
+	IsEmailVerified int8           `gorm:"not null;default:0" json:"is_email_verified"`    // email verification (0=unverified, 1=verified)
+	IsPhoneVerified int8           `gorm:"not null;default:0" json:"is_phone_verified"`    // phone verification (0=unverified, 1=verified)
+	Timezone        string         `gorm:"not null;size:64;default:'UTC'" json:"timezone"` // IANA zone used for class times and calendar export
+	Tier            string         `gorm:"not null;size:20;default:'free'" json:"tier"`    // subscription tier, selects quotas
+	RequiredCredits *int           `json:"required_credits"`                               // credits the user's degree requires, null uses the default
 	TableCommon
 
 	// Relationships (one-to-many)
-	Courses []Course `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"courses,omitempty"`
+	Courses   []Course   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"courses,omitempty"`
+	Reminders []Reminder `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"reminders,omitempty"`
 }
 
 func (User) TableName() string {
@@ -34,12 +39,13 @@ func (User) TableName() string {
 type Course struct {
 	ID          int            `gorm:"primaryKey;autoIncrement" json:"id"`
 	CourseID    string         `gorm:"not null;index;size:255" json:"course_id"` // Course identifier (e.g., "CS101")
-	CourseName  string         `gorm:"not null;size:255" json:"course_name"`
+	CourseName  string         `gorm:"not null;size:255;index:idx_courses_fulltext,class:FULLTEXT" json:"course_name"`
 	UserID      string         `gorm:"not null;index;type:char(36)" json:"user_id"`
 	Description sql.NullString `gorm:"type:text" json:"description,omitempty"`
-	Lecturers   string         `gorm:"type:text;not null" json:"lecturers"` // Comma-separated lecturer names
+	Lecturers   string         `gorm:"type:text;not null;index:idx_courses_fulltext,class:FULLTEXT" json:"lecturers"` // Comma-separated lecturer names
 	Credits     int            `gorm:"not null" json:"credits"`
 	GPA         float32        `gorm:"not null;default:0" json:"gpa"`
+	Difficulty  *int8          `json:"difficulty"` // user-rated from 1 (easy) to 5 (hard), weights study planning
 	SemesterID  int            `gorm:"not null;index" json:"semester_id"`
 	TableCommon
 
@@ -76,6 +82,7 @@ type Tag struct {
 
 	// Relationships (many-to-many)
 	Courses []Course `gorm:"many2many:course_tags;" json:"courses,omitempty"`
+	Notes   []Note   `gorm:"many2many:note_tags;" json:"notes,omitempty"`
 }
 
 func (Tag) TableName() string {
@@ -92,6 +99,252 @@ func (CourseTag) TableName() string {
 	return "course_tags"
 }
 
+// Note is a lecture note. Content holds the editor's JSON document; ContentHTML
+// and ContentText are server-side renderings kept for display and search.
+type Note struct {
+	ID          int             `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID      string          `gorm:"not null;index;type:char(36)" json:"user_id"`
+	CourseID    int             `gorm:"not null;index" json:"course_id"`
+	LectureDate time.Time       `gorm:"type:date;not null;index" json:"lecture_date"`
+	Title       string          `gorm:"not null;size:255;index:idx_notes_fulltext,class:FULLTEXT" json:"title"`
+	Content     json.RawMessage `gorm:"type:json;not null" json:"content" swaggertype:"object"`
+	ContentHTML string          `gorm:"type:longtext;not null" json:"content_html"`
+	ContentText string          `gorm:"type:longtext;not null;index:idx_notes_fulltext,class:FULLTEXT" json:"-"`
+	Version     int             `gorm:"not null;default:1" json:"version"` // latest revision number
+	TableCommon
+
+	// Relationships
+	Course    *Course        `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"course,omitempty"`
+	Tags      []Tag          `gorm:"many2many:note_tags;" json:"tags"`
+	Revisions []NoteRevision `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE" json:"-"`
+	Summaries []NoteSummary  `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (Note) TableName() string {
+	return "notes"
+}
+
+// NoteTag is the explicit join table for the many2many relationship.
+type NoteTag struct {
+	NoteID int `gorm:"primaryKey;index"`
+	TagID  int `gorm:"primaryKey;index"`
+}
+
+func (NoteTag) TableName() string {
+	return "note_tags"
+}
+
+// NoteRevision is an immutable snapshot of a note, written on every change
+type NoteRevision struct {
+	ID           int             `gorm:"primaryKey;autoIncrement" json:"id"`
+	NoteID       int             `gorm:"not null;uniqueIndex:idx_note_revisions_note_version" json:"note_id"`
+	Version      int             `gorm:"not null;uniqueIndex:idx_note_revisions_note_version" json:"version"`
+	Title        string          `gorm:"not null;size:255" json:"title"`
+	Content      json.RawMessage `gorm:"type:json;not null" json:"content,omitempty" swaggertype:"object"`
+	ContentText  string          `gorm:"type:longtext;not null" json:"-"`
+	RestoredFrom sql.NullInt64   `json:"restored_from,omitempty"` // version this revision was restored from
+	TableCommon
+}
+
+func (NoteRevision) TableName() string {
+	return "note_revisions"
+}
+
+// NoteSummary is one AI-generated summary of a note. Every request creates a
+// new version; Provider, Model and PromptVersion record how it was produced.
+type NoteSummary struct {
+	ID            int             `gorm:"primaryKey;autoIncrement" json:"id"`
+	NoteID        int             `gorm:"not null;uniqueIndex:idx_note_summaries_note_version" json:"note_id"`
+	UserID        string          `gorm:"not null;index;type:char(36)" json:"user_id"`
+	Version       int             `gorm:"not null;uniqueIndex:idx_note_summaries_note_version" json:"version"`
+	NoteVersion   int             `gorm:"not null" json:"note_version"` // note revision that was summarized
+	Language      string          `gorm:"not null;size:16" json:"language"`
+	Status        int8            `gorm:"not null;default:0" json:"status"` // see consts.SummaryStatus
+	Error         sql.NullString  `gorm:"size:1000" json:"error"`
+	Bullets       json.RawMessage `gorm:"type:json" json:"bullets" swaggertype:"array,string"`
+	KeyConcepts   json.RawMessage `gorm:"type:json" json:"key_concepts" swaggertype:"array,object"`
+	Provider      string          `gorm:"not null;size:32;default:''" json:"provider"`
+	Model         string          `gorm:"not null;size:128;default:''" json:"model"`
+	PromptVersion string          `gorm:"not null;size:32" json:"prompt_version"`
+	ChunkCount    int             `gorm:"not null;default:0" json:"chunk_count"` // parts summarized separately before merging
+	InputTokens   int             `gorm:"not null;default:0" json:"input_tokens"`
+	OutputTokens  int             `gorm:"not null;default:0" json:"output_tokens"`
+	CompletedAt   sql.NullTime    `json:"completed_at"`
+	TableCommon
+}
+
+func (NoteSummary) TableName() string {
+	return "note_summaries"
+}
+
+// File is an uploaded object. While Status is pending the upload has only been
+// announced and Size is the size the client declared.
+type File struct {
+	ID         int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     string         `gorm:"not null;index;type:char(36)" json:"user_id"`
+	CourseID   sql.NullInt64  `gorm:"index" json:"course_id"`
+	StorageKey string         `gorm:"not null;size:512;uniqueIndex" json:"-"`
+	Filename   string         `gorm:"not null;size:255" json:"filename"`
+	MimeType   string         `gorm:"not null;size:127" json:"mime_type"`
+	Size       int64          `gorm:"not null" json:"size"`
+	Checksum   sql.NullString `gorm:"type:char(64)" json:"checksum"` // hex SHA-256 of the content
+	Status     int8           `gorm:"not null;default:0;index" json:"status"`
+
+	// Presigned upload: its staging key derives from UploadID, which completion must
+	// present. Both are kept until the sweep removes the staging key after expiry.
+	UploadID        sql.NullString `gorm:"type:char(32);uniqueIndex" json:"-"`
+	UploadExpiresAt sql.NullTime   `gorm:"index" json:"-"`
+
+	// Text extraction progress, see consts.FileExtractionStatus
+	ExtractionStatus int8           `gorm:"not null;default:0" json:"extraction_status"`
+	ExtractionError  sql.NullString `gorm:"size:1000" json:"extraction_error"`
+	PageCount        int            `gorm:"not null;default:0" json:"page_count"`
+	ExtractedAt      sql.NullTime   `json:"extracted_at"`
+	TableCommon
+
+	// Relationships
+	Course *Course     `gorm:"foreignKey:CourseID;constraint:OnDelete:SET NULL" json:"course,omitempty"`
+	Chunks []FileChunk `gorm:"foreignKey:FileID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (File) TableName() string {
+	return "files"
+}
+
+// FileChunk is a piece of a file's extracted text, sized for search and AI prompts
+type FileChunk struct {
+	ID         int           `gorm:"primaryKey;autoIncrement" json:"id"`
+	FileID     int           `gorm:"not null;uniqueIndex:idx_file_chunks_file_index" json:"file_id"`
+	UserID     string        `gorm:"not null;index;type:char(36)" json:"user_id"`
+	CourseID   sql.NullInt64 `gorm:"index" json:"course_id"`
+	ChunkIndex int           `gorm:"not null;uniqueIndex:idx_file_chunks_file_index" json:"chunk_index"`
+	PageStart  int           `gorm:"not null" json:"page_start"`
+	PageEnd    int           `gorm:"not null" json:"page_end"`
+	Content    string        `gorm:"type:text;not null;index:idx_file_chunks_fulltext,class:FULLTEXT" json:"content"`
+	TableCommon
+
+	// Relationships
+	Course *Course `gorm:"foreignKey:CourseID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+func (FileChunk) TableName() string {
+	return "file_chunks"
+}
+
+// Reminder covers course reminders, assignments and exams.
+// Location and Weight are only meaningful for exams.
+type Reminder struct {
+	ID          int             `gorm:"primaryKey;autoIncrement" json:"id"`
+	Title       string          `gorm:"type:text;not null;index:idx_reminders_fulltext,class:FULLTEXT" json:"title"`
+	Description string          `gorm:"type:text;not null;index:idx_reminders_fulltext,class:FULLTEXT" json:"description"`
+	DueDate     time.Time       `gorm:"type:date;not null;index" json:"due_date"`
+	DueTime     string          `gorm:"type:time;not null" json:"due_time"` // HH:MM:SS
+	UserID      string          `gorm:"not null;index;type:char(36)" json:"user_id"`
+	CourseID    *int            `gorm:"index" json:"course_id,omitempty"`
+	Type        int8            `gorm:"not null;default:0" json:"type"`         // reminder type (0=course, 1=assignment, 2=exam)
+	Status      int8            `gorm:"not null;default:0;index" json:"status"` // reminder status (0=pending, 1=completed, 2=overdue)
+	Location    sql.NullString  `gorm:"size:255" json:"location,omitempty"`
+	Weight      sql.NullFloat64 `json:"weight,omitempty"` // percentage of the final grade
+	Score       sql.NullFloat64 `json:"score,omitempty"`  // percentage obtained on the exam, once graded
+	CompletedAt sql.NullTime    `json:"completed_at,omitempty"`
+	TableCommon
+
+	// Relationships
+	Course *Course `gorm:"foreignKey:CourseID;constraint:OnDelete:SET NULL" json:"course,omitempty"`
+}
+
+func (Reminder) TableName() string {
+	return "reminders"
+}
+
+// ClassSession is a recurring timetable slot of a course.
+// Occurrences start on the first matching weekday on or after StartDate.
+type ClassSession struct {
+	ID           int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID       string         `gorm:"not null;index;uniqueIndex:idx_class_sessions_user_source_external_uid,priority:1;type:char(36)" json:"user_id"`
+	CourseID     int            `gorm:"not null;index" json:"course_id"`
+	Weekday      int8           `gorm:"not null" json:"weekday"` // day of week (0=Sunday ... 6=Saturday)
+	StartTime    string         `gorm:"type:time;not null" json:"start_time"`
+	EndTime      string         `gorm:"type:time;not null" json:"end_time"`
+	Location     sql.NullString `gorm:"size:255" json:"location,omitempty"`
+	Recurrence   int8           `gorm:"not null;default:0" json:"recurrence"` // recurrence (0=weekly, 1=bi-weekly)
+	StartDate    time.Time      `gorm:"type:date;not null" json:"start_date"`
+	EndDate      sql.NullTime   `gorm:"type:date" json:"end_date,omitempty"`                                                                                            // open-ended when null
+	ExternalUID  sql.NullString `gorm:"size:255;uniqueIndex:idx_class_sessions_user_source_external_uid,priority:3" json:"external_uid,omitempty"`                      // iCalendar UID of imported sessions
+	ImportSource string         `gorm:"size:255;not null;default:'';uniqueIndex:idx_class_sessions_user_source_external_uid,priority:2" json:"import_source,omitempty"` // calendar imported sessions came from; UIDs are unique within it
+	TableCommon
+
+	// Relationships
+	Course     *Course                 `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"course,omitempty"`
+	Exceptions []ClassSessionException `gorm:"foreignKey:SessionID;constraint:OnDelete:CASCADE" json:"exceptions,omitempty"`
+}
+
+func (ClassSession) TableName() string {
+	return "class_sessions"
+}
+
+// ClassSessionException cancels a single occurrence of a class session
+type ClassSessionException struct {
+	ID        int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	SessionID int            `gorm:"not null;uniqueIndex:idx_class_session_exceptions_session_date" json:"session_id"`
+	Date      time.Time      `gorm:"type:date;not null;uniqueIndex:idx_class_session_exceptions_session_date" json:"date"`
+	Reason    sql.NullString `gorm:"size:255" json:"reason,omitempty"`
+	TableCommon
+}
+
+func (ClassSessionException) TableName() string {
+	return "class_session_exceptions"
+}
+
+// CalendarFeed holds the secret token of a user's iCalendar subscription URL.
+// Rotating the token invalidates every previously shared URL.
+type CalendarFeed struct {
+	UserID string `gorm:"primaryKey;type:char(36)" json:"user_id"`
+	Token  string `gorm:"not null;uniqueIndex;size:64" json:"-"`
+	TableCommon
+}
+
+func (CalendarFeed) TableName() string {
+	return "calendar_feeds"
+}
+
+// NotificationSetting stores a user's reminder notification preferences.
+// Users without a row fall back to the configured default offsets.
+type NotificationSetting struct {
+	UserID       string `gorm:"primaryKey;type:char(36)" json:"user_id"`
+	Offsets      string `gorm:"not null;size:255" json:"offsets"`        // comma-separated minutes before the deadline
+	EmailEnabled int8   `gorm:"not null;default:1" json:"email_enabled"` // email channel (0=disabled, 1=enabled)
+	TableCommon
+}
+
+func (NotificationSetting) TableName() string {
+	return "notification_settings"
+}
+
+// ReminderNotification is a single scheduled notification for a reminder.
+// The unique (reminder_id, offset_minutes) pair prevents duplicate sends.
+type ReminderNotification struct {
+	ID            int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	ReminderID    int            `gorm:"not null;uniqueIndex:idx_reminder_notifications_reminder_offset_deadline" json:"reminder_id"`
+	UserID        string         `gorm:"not null;index;type:char(36)" json:"user_id"`
+	OffsetMinutes int            `gorm:"not null;uniqueIndex:idx_reminder_notifications_reminder_offset_deadline" json:"offset_minutes"`
+	Deadline      time.Time      `gorm:"not null;uniqueIndex:idx_reminder_notifications_reminder_offset_deadline" json:"deadline"` // deadline the notification was scheduled for
+	ScheduledAt   time.Time      `gorm:"not null;index" json:"scheduled_at"`
+	Status        int8           `gorm:"not null;default:0;index" json:"status"` // (0=pending, 1=sending, 2=sent, 3=failed, 4=skipped)
+	Attempts      int            `gorm:"not null;default:0" json:"attempts"`
+	ClaimedAt     sql.NullTime   `json:"claimed_at,omitempty"`
+	SentAt        sql.NullTime   `json:"sent_at,omitempty"`
+	LastError     sql.NullString `gorm:"type:text" json:"last_error,omitempty"`
+	TableCommon
+
+	// Relationships
+	Reminder *Reminder `gorm:"foreignKey:ReminderID;constraint:OnDelete:CASCADE" json:"reminder,omitempty"`
+}
+
+func (ReminderNotification) TableName() string {
+	return "reminder_notifications"
+}
+
 type Mail struct {
 	ID      int    `gorm:"primaryKey;autoIncrement" json:"id"`
 	Subject string `gorm:"not null;type:text" json:"subject"`
@@ -104,3 +357,522 @@ type Mail struct {
 func (Mail) TableName() string {
 	return "mail"
 }
+
+// FlashcardGeneration is a request to generate flashcards from a note or an
+// uploaded file, processed in the background
+type FlashcardGeneration struct {
+	ID            int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID        string         `gorm:"not null;index;type:char(36)" json:"user_id"`
+	NoteID        *int           `gorm:"index" json:"note_id,omitempty"`
+	FileID        *int           `gorm:"index" json:"file_id,omitempty"`
+	Language      string         `gorm:"not null;size:16" json:"language"`
+	Count         int            `gorm:"not null" json:"count"`                   // cards requested
+	CreatedCount  int            `gorm:"not null;default:0" json:"created_count"` // cards actually created
+	Status        int8           `gorm:"not null;default:0" json:"status"`        // see consts.FlashcardGenerationStatus
+	Error         sql.NullString `gorm:"size:1000" json:"error"`
+	Provider      string         `gorm:"not null;size:32;default:''" json:"provider"`
+	Model         string         `gorm:"not null;size:128;default:''" json:"model"`
+	PromptVersion string         `gorm:"not null;size:32" json:"prompt_version"`
+	InputTokens   int            `gorm:"not null;default:0" json:"input_tokens"`
+	OutputTokens  int            `gorm:"not null;default:0" json:"output_tokens"`
+	CompletedAt   sql.NullTime   `json:"completed_at"`
+	TableCommon
+
+	// Relationships
+	Note *Note `gorm:"foreignKey:NoteID;constraint:OnDelete:SET NULL" json:"-"`
+	File *File `gorm:"foreignKey:FileID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+func (FlashcardGeneration) TableName() string {
+	return "flashcard_generations"
+}
+
+// Flashcard is a question/answer card scheduled with SM-2. Generated cards keep
+// a reference to the chunk of the note or file they were made from.
+type Flashcard struct {
+	ID           int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID       string `gorm:"not null;type:char(36);index:idx_flashcards_user_due" json:"user_id"`
+	CourseID     *int   `gorm:"index" json:"course_id,omitempty"`
+	GenerationID *int   `gorm:"index" json:"generation_id,omitempty"`
+	NoteID       *int   `gorm:"index" json:"note_id,omitempty"`
+	FileID       *int   `gorm:"index" json:"file_id,omitempty"`
+	ChunkIndex   *int   `json:"chunk_index,omitempty"` // chunk of the source the card was made from
+	PageStart    *int   `json:"page_start,omitempty"`  // pages of the chunk, for file sources
+	PageEnd      *int   `json:"page_end,omitempty"`
+	Front        string `gorm:"type:text;not null;index:idx_flashcards_fulltext,class:FULLTEXT" json:"front"`
+	Back         string `gorm:"type:text;not null;index:idx_flashcards_fulltext,class:FULLTEXT" json:"back"`
+
+	// SM-2 scheduling state
+	EaseFactor     float64      `gorm:"not null;default:2.5" json:"ease_factor"`
+	IntervalDays   int          `gorm:"not null;default:0" json:"interval_days"`
+	Repetitions    int          `gorm:"not null;default:0" json:"repetitions"` // successful reviews in a row
+	Lapses         int          `gorm:"not null;default:0" json:"lapses"`
+	DueDate        time.Time    `gorm:"type:date;not null;index:idx_flashcards_user_due" json:"due_date"`
+	LastReviewedAt sql.NullTime `json:"last_reviewed_at"`
+	TableCommon
+
+	// Relationships
+	Course     *Course              `gorm:"foreignKey:CourseID;constraint:OnDelete:SET NULL" json:"-"`
+	Generation *FlashcardGeneration `gorm:"foreignKey:GenerationID;constraint:OnDelete:SET NULL" json:"-"`
+	Note       *Note                `gorm:"foreignKey:NoteID;constraint:OnDelete:SET NULL" json:"-"`
+	File       *File                `gorm:"foreignKey:FileID;constraint:OnDelete:SET NULL" json:"-"`
+	Reviews    []FlashcardReview    `gorm:"foreignKey:FlashcardID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (Flashcard) TableName() string {
+	return "flashcards"
+}
+
+// FlashcardReview records one graded review and the schedule it produced
+type FlashcardReview struct {
+	ID           int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	FlashcardID  int       `gorm:"not null;index" json:"flashcard_id"`
+	UserID       string    `gorm:"not null;index;type:char(36)" json:"user_id"`
+	Grade        int8      `gorm:"not null" json:"grade"` // 0 (blackout) to 5 (perfect recall)
+	EaseFactor   float64   `gorm:"not null" json:"ease_factor"`
+	IntervalDays int       `gorm:"not null" json:"interval_days"`
+	DueDate      time.Time `gorm:"type:date;not null" json:"due_date"`
+	TableCommon
+}
+
+func (FlashcardReview) TableName() string {
+	return "flashcard_reviews"
+}
+
+// Quiz is a set of practice questions generated from a course's notes.
+// Questions are written by the worker while Status is pending or processing.
+type Quiz struct {
+	ID            int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID        string         `gorm:"not null;index;type:char(36)" json:"user_id"`
+	CourseID      int            `gorm:"not null;index" json:"course_id"`
+	Title         string         `gorm:"not null;size:255" json:"title"`
+	Difficulty    string         `gorm:"not null;size:16" json:"difficulty"` // see consts.QuizDifficulty
+	Types         string         `gorm:"not null;size:64" json:"types"`      // comma separated consts.QuizQuestionType values
+	Language      string         `gorm:"not null;size:16" json:"language"`
+	Count         int            `gorm:"not null" json:"count"`            // questions requested
+	Status        int8           `gorm:"not null;default:0" json:"status"` // see consts.QuizStatus
+	Error         sql.NullString `gorm:"size:1000" json:"error"`
+	Provider      string         `gorm:"not null;size:32;default:''" json:"provider"`
+	Model         string         `gorm:"not null;size:128;default:''" json:"model"`
+	PromptVersion string         `gorm:"not null;size:32" json:"prompt_version"`
+	InputTokens   int            `gorm:"not null;default:0" json:"input_tokens"`
+	OutputTokens  int            `gorm:"not null;default:0" json:"output_tokens"`
+	CompletedAt   sql.NullTime   `json:"completed_at"`
+	TableCommon
+
+	// Relationships
+	Course    *Course        `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"-"`
+	Questions []QuizQuestion `gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE" json:"-"`
+	Attempts  []QuizAttempt  `gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (Quiz) TableName() string {
+	return "quizzes"
+}
+
+// QuizQuestion holds the answer key of one question. Only the fields of its
+// type are set: Options and AnswerIndex for mcq, AnswerBool for true_false,
+// ReferenceAnswer and Rubric for short_answer.
+type QuizQuestion struct {
+	ID              int             `gorm:"primaryKey;autoIncrement" json:"id"`
+	QuizID          int             `gorm:"not null;uniqueIndex:idx_quiz_questions_quiz_position" json:"quiz_id"`
+	Position        int             `gorm:"not null;uniqueIndex:idx_quiz_questions_quiz_position" json:"position"`
+	Type            string          `gorm:"not null;size:16" json:"type"`
+	Difficulty      string          `gorm:"not null;size:16" json:"difficulty"`
+	Prompt          string          `gorm:"type:text;not null" json:"prompt"`
+	Options         json.RawMessage `gorm:"type:json" json:"options,omitempty" swaggertype:"array,string"`
+	AnswerIndex     *int            `json:"answer_index,omitempty"`
+	AnswerBool      *bool           `json:"answer_bool,omitempty"`
+	ReferenceAnswer string          `gorm:"type:text;not null" json:"reference_answer,omitempty"`
+	Rubric          json.RawMessage `gorm:"type:json" json:"rubric,omitempty" swaggertype:"array,object"`
+	Explanation     string          `gorm:"type:text;not null" json:"explanation"`
+	NoteID          *int            `gorm:"index" json:"note_id,omitempty"` // note the question was written from
+	TableCommon
+
+	// Relationships
+	Note *Note `gorm:"foreignKey:NoteID;constraint:OnDelete:SET NULL" json:"-"`
+}
+
+func (QuizQuestion) TableName() string {
+	return "quiz_questions"
+}
+
+// QuizAttempt is one run through a quiz. Score sums the answers' scores out
+// of MaxScore, one point per question.
+type QuizAttempt struct {
+	ID          int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	QuizID      int            `gorm:"not null;index" json:"quiz_id"`
+	UserID      string         `gorm:"not null;index;type:char(36)" json:"user_id"`
+	Status      int8           `gorm:"not null;default:0" json:"status"` // see consts.QuizAttemptStatus
+	Score       float64        `gorm:"not null;default:0" json:"score"`
+	MaxScore    int            `gorm:"not null;default:0" json:"max_score"`
+	Error       sql.NullString `gorm:"size:1000" json:"error"`
+	SubmittedAt sql.NullTime   `json:"submitted_at"`
+	GradedAt    sql.NullTime   `json:"graded_at"`
+	TableCommon
+
+	// Relationships
+	Answers []QuizAnswer `gorm:"foreignKey:AttemptID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (QuizAttempt) TableName() string {
+	return "quiz_attempts"
+}
+
+// QuizAnswer is the response to one question of an attempt. Score is the
+// fraction of the question's point earned and stays null until graded.
+type QuizAnswer struct {
+	ID         int             `gorm:"primaryKey;autoIncrement" json:"id"`
+	AttemptID  int             `gorm:"not null;uniqueIndex:idx_quiz_answers_attempt_question" json:"attempt_id"`
+	QuestionID int             `gorm:"not null;uniqueIndex:idx_quiz_answers_attempt_question;index" json:"question_id"`
+	UserID     string          `gorm:"not null;index;type:char(36)" json:"user_id"`
+	Response   string          `gorm:"type:text;not null" json:"response"`
+	IsCorrect  bool            `gorm:"not null;default:false" json:"is_correct"`
+	Score      sql.NullFloat64 `json:"score"`
+	Feedback   string          `gorm:"type:text;not null" json:"feedback"`
+	GradedBy   string          `gorm:"not null;size:16;default:''" json:"graded_by"` // see consts.QuizGrader
+	TableCommon
+
+	// Relationships
+	Question *QuizQuestion `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (QuizAnswer) TableName() string {
+	return "quiz_answers"
+}
+
+// Embedding is the vector of one chunk of a note or file; exactly one of
+// NoteID and FileID is set. Vectors are unit length, packed by vector.Encode.
+type Embedding struct {
+	ID         int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     string `gorm:"not null;type:char(36);index:idx_embeddings_user_model" json:"user_id"`
+	CourseID   *int   `gorm:"index" json:"course_id,omitempty"`
+	NoteID     *int   `gorm:"index" json:"note_id,omitempty"`
+	FileID     *int   `gorm:"index" json:"file_id,omitempty"`
+	ChunkIndex int    `gorm:"not null" json:"chunk_index"`
+	PageStart  *int   `json:"page_start,omitempty"`
+	PageEnd    *int   `json:"page_end,omitempty"`
+	Content    string `gorm:"type:text;not null" json:"content"`
+	Model      string `gorm:"not null;size:128;index:idx_embeddings_user_model" json:"model"` // vectors of different models are not comparable
+	Dimensions int    `gorm:"not null" json:"dimensions"`
+	Vector     []byte `gorm:"type:blob;not null" json:"-"`
+	TableCommon
+
+	// Relationships
+	Course *Course `gorm:"foreignKey:CourseID;constraint:OnDelete:SET NULL" json:"-"`
+	Note   *Note   `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE" json:"-"`
+	File   *File   `gorm:"foreignKey:FileID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (Embedding) TableName() string {
+	return "embeddings"
+}
+
+// AssistantConversation is a chat with the study assistant. With a CourseID
+// only that course's material is searched, otherwise all of the user's.
+type AssistantConversation struct {
+	ID       int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID   string `gorm:"not null;index;type:char(36)" json:"user_id"`
+	CourseID *int   `gorm:"index" json:"course_id,omitempty"`
+	Title    string `gorm:"not null;size:255" json:"title"`
+	TableCommon
+
+	// Relationships
+	Course   *Course            `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"-"`
+	Messages []AssistantMessage `gorm:"foreignKey:ConversationID;constraint:OnDelete:CASCADE" json:"messages,omitempty"`
+}
+
+func (AssistantConversation) TableName() string {
+	return "assistant_conversations"
+}
+
+// AssistantMessage is one turn of a conversation. Assistant replies carry the
+// sources they cited.
+type AssistantMessage struct {
+	ID             int             `gorm:"primaryKey;autoIncrement" json:"id"`
+	ConversationID int             `gorm:"not null;index" json:"conversation_id"`
+	UserID         string          `gorm:"not null;index;type:char(36)" json:"user_id"`
+	Role           string          `gorm:"not null;size:16" json:"role"` // user or assistant
+	Content        string          `gorm:"type:longtext;not null" json:"content"`
+	Citations      json.RawMessage `gorm:"type:json" json:"citations,omitempty" swaggertype:"array,object"`
+	Provider       string          `gorm:"not null;size:32;default:''" json:"provider,omitempty"`
+	Model          string          `gorm:"not null;size:128;default:''" json:"model,omitempty"`
+	InputTokens    int             `gorm:"not null;default:0" json:"input_tokens"`
+	OutputTokens   int             `gorm:"not null;default:0" json:"output_tokens"`
+	TableCommon
+}
+
+func (AssistantMessage) TableName() string {
+	return "assistant_messages"
+}
+
+// Plan is a subscription tier and the limits that come with it. Nil limits
+// are unlimited; operations and tokens are counted per billing period.
+type Plan struct {
+	Code         string `gorm:"primaryKey;size:20" json:"code"` // users.tier holds the code of the user's plan
+	Name         string `gorm:"not null;size:64" json:"name"`
+	PriceCents   int    `gorm:"not null;default:0" json:"price_cents"` // per month
+	Currency     string `gorm:"not null;size:3;default:'USD'" json:"currency"`
+	MaxCourses   *int   `json:"max_courses"`
+	AIOperations *int   `json:"ai_operations"`
+	AITokens     *int64 `json:"ai_tokens"`
+	TableCommon
+}
+
+func (Plan) TableName() string {
+	return "plans"
+}
+
+// Subscription is the user's paid plan. Usage is metered per current period;
+// users without one are metered per calendar month. The row follows the
+// billing provider's webhooks; users.tier is the plan it currently grants.
+type Subscription struct {
+	ID                     int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID                 string     `gorm:"not null;uniqueIndex;type:char(36)" json:"user_id"`
+	PlanCode               string     `gorm:"not null;size:20;index" json:"plan_code"`
+	Status                 string     `gorm:"not null;size:20" json:"status"` // see consts.SubscriptionStatus
+	CurrentPeriodStart     time.Time  `gorm:"not null" json:"current_period_start"`
+	CurrentPeriodEnd       time.Time  `gorm:"not null" json:"current_period_end"`
+	CanceledAt             *time.Time `json:"canceled_at,omitempty"`
+	Provider               string     `gorm:"not null;size:16;default:'';index:idx_subscriptions_provider_subscription,unique" json:"-"`
+	ProviderSubscriptionID *string    `gorm:"size:64;index:idx_subscriptions_provider_subscription,unique" json:"-"` // nil for subscriptions granted by hand
+	ProviderCustomerID     string     `gorm:"not null;size:64;default:''" json:"-"`
+	TrialEndsAt            *time.Time `json:"trial_ends_at,omitempty"`
+	CancelAtPeriodEnd      bool       `gorm:"not null;default:false" json:"cancel_at_period_end"`
+	GraceEndsAt            *time.Time `json:"grace_ends_at,omitempty"` // when a past-due or canceled subscription drops to the free plan
+	LastEventAt            *time.Time `json:"-"`                       // time of the newest webhook applied, older ones are ignored
+	TableCommon
+
+	// Relationships
+	Plan *Plan `gorm:"foreignKey:PlanCode;constraint:OnDelete:RESTRICT" json:"plan,omitempty"`
+}
+
+func (Subscription) TableName() string {
+	return "subscriptions"
+}
+
+// UsageRecord is one AI operation in the usage ledger. Operations are
+// reserved before they start, so they count against the plan while they run,
+// and completed with the tokens they used once they succeed.
+type UsageRecord struct {
+	ID           int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID       string    `gorm:"not null;type:char(36);index:idx_usage_records_user_created" json:"user_id"`
+	Operation    string    `gorm:"not null;size:32" json:"operation"`            // see consts.UsageOperation
+	Reference    string    `gorm:"not null;size:64;default:''" json:"reference"` // what was produced, e.g. summary:12
+	Provider     string    `gorm:"not null;size:32;default:''" json:"provider"`
+	Model        string    `gorm:"not null;size:128;default:''" json:"model"`
+	InputTokens  int       `gorm:"not null;default:0" json:"input_tokens"`
+	OutputTokens int       `gorm:"not null;default:0" json:"output_tokens"`
+	Reserved     bool      `gorm:"not null;default:false" json:"reserved"` // still running
+	CreatedAt    time.Time `gorm:"index:idx_usage_records_user_created" json:"created_at"`
+}
+
+func (UsageRecord) TableName() string {
+	return "usage_records"
+}
+
+// BillingEvent is a webhook received from the billing provider. The unique
+// event ID makes redelivered webhooks no-ops.
+type BillingEvent struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Provider  string    `gorm:"not null;size:16;uniqueIndex:idx_billing_events_provider_event" json:"provider"`
+	EventID   string    `gorm:"not null;size:128;uniqueIndex:idx_billing_events_provider_event" json:"event_id"`
+	Type      string    `gorm:"not null;size:64" json:"type"`
+	UserID    *string   `gorm:"type:char(36);index" json:"user_id"` // nil when the event concerns no known user
+	Payload   string    `gorm:"type:json;not null" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (BillingEvent) TableName() string {
+	return "billing_events"
+}
+
+// StudyPreference is when a user wants to study. Windows is a JSON array of
+// weekly spans; users without a row get the defaults in consts.
+type StudyPreference struct {
+	UserID          string          `gorm:"primaryKey;type:char(36)" json:"user_id"`
+	Windows         json.RawMessage `gorm:"type:json;not null" json:"windows" swaggertype:"array,object"`
+	BlockMinutes    int             `gorm:"not null;default:50" json:"block_minutes"`
+	BreakMinutes    int             `gorm:"not null;default:10" json:"break_minutes"`
+	MaxDailyMinutes int             `gorm:"not null;default:240" json:"max_daily_minutes"`
+	TableCommon
+}
+
+func (StudyPreference) TableName() string {
+	return "study_preferences"
+}
+
+// StudyPlan is one generated study schedule. InputHash fingerprints the
+// deadlines, classes and preferences it was built from, so a plan whose
+// inputs changed can be flagged for regeneration.
+type StudyPlan struct {
+	ID         int             `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     string          `gorm:"not null;index;type:char(36)" json:"user_id"`
+	Status     int8            `gorm:"not null;default:0" json:"status"` // see consts.StudyPlanStatus
+	StartsAt   time.Time       `gorm:"not null" json:"starts_at"`
+	EndsAt     time.Time       `gorm:"not null" json:"ends_at"`
+	InputHash  string          `gorm:"not null;type:char(64)" json:"-"`
+	Shortfalls json.RawMessage `gorm:"type:json" json:"shortfalls" swaggertype:"array,object"` // study that did not fit, per reminder
+	AcceptedAt sql.NullTime    `json:"accepted_at"`
+	TableCommon
+
+	// Relationships
+	Blocks []StudyBlock `gorm:"foreignKey:PlanID;constraint:OnDelete:CASCADE" json:"blocks,omitempty"`
+}
+
+func (StudyPlan) TableName() string {
+	return "study_plans"
+}
+
+// StudyBlock is a scheduled span of study for a course, preparing for a
+// reminder's exam or assignment. Adjusted blocks were moved by the user and
+// are kept when the plan is regenerated.
+type StudyBlock struct {
+	ID         int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	PlanID     int       `gorm:"not null;index" json:"plan_id"`
+	UserID     string    `gorm:"not null;index;type:char(36)" json:"user_id"`
+	CourseID   int       `gorm:"not null;index" json:"course_id"`
+	ReminderID *int      `gorm:"index" json:"reminder_id"`
+	StartAt    time.Time `gorm:"not null" json:"start_at"`
+	EndAt      time.Time `gorm:"not null" json:"end_at"`
+	Adjusted   bool      `gorm:"not null;default:false" json:"adjusted"`
+	TableCommon
+
+	// Relationships
+	Course   *Course   `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"course,omitempty"`
+	Reminder *Reminder `gorm:"foreignKey:ReminderID;constraint:OnDelete:SET NULL" json:"reminder,omitempty"`
+}
+
+func (StudyBlock) TableName() string {
+	return "study_blocks"
+}
+
+// StudySession is time the user spent studying a course. EndedAt is null
+// while the session runs; DurationSeconds is set when it stops.
+type StudySession struct {
+	ID              int          `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID          string       `gorm:"not null;index:idx_study_sessions_user_started,priority:1;type:char(36)" json:"user_id"`
+	CourseID        int          `gorm:"not null;index" json:"course_id"`
+	NoteID          *int         `gorm:"index" json:"note_id"`
+	StartedAt       time.Time    `gorm:"not null;index:idx_study_sessions_user_started,priority:2" json:"started_at"`
+	EndedAt         sql.NullTime `json:"ended_at"`
+	DurationSeconds int          `gorm:"not null;default:0" json:"duration_seconds"`
+	Pomodoros       int          `gorm:"not null;default:0" json:"pomodoros"`
+	TableCommon
+
+	// Relationships
+	Course *Course `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"course,omitempty"`
+	Note   *Note   `gorm:"foreignKey:NoteID;constraint:OnDelete:SET NULL" json:"note,omitempty"`
+}
+
+func (StudySession) TableName() string {
+	return "study_sessions"
+}
+
+// Assessment is a graded component of a course, such as a midterm, the final
+// exam or an assignment. The weights of a course's assessments add up to 100;
+// once all are scored they set the course's GPA.
+type Assessment struct {
+	ID       int             `gorm:"primaryKey;autoIncrement" json:"id"`
+	CourseID int             `gorm:"not null;index" json:"course_id"`
+	UserID   string          `gorm:"not null;index;type:char(36)" json:"user_id"`
+	Name     string          `gorm:"not null;size:255" json:"name"`
+	Weight   float64         `gorm:"not null" json:"weight"` // percent of the final grade
+	MaxScore float64         `gorm:"not null" json:"max_score"`
+	Score    sql.NullFloat64 `json:"score"` // obtained, out of MaxScore; null until graded
+	DueDate  sql.NullTime    `gorm:"type:date" json:"due_date"`
+	TableCommon
+
+	// Relationships
+	Course *Course `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"course,omitempty"`
+}
+
+func (Assessment) TableName() string {
+	return "assessments"
+}
+
+// StudyGroup gathers students taking the same course. Members join through
+// an invitation and can share their notes of the course into the group.
+type StudyGroup struct {
+	ID          int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string `gorm:"not null;size:255" json:"name"`
+	Description string `gorm:"type:text;not null" json:"description"`
+	CourseCode  string `gorm:"not null;size:255;index" json:"course_code"` // normalized, e.g. "CS101"; shared notes come from courses with this code
+	CourseName  string `gorm:"not null;size:255" json:"course_name"`
+	OwnerID     string `gorm:"not null;index;type:char(36)" json:"owner_id"`
+	TableCommon
+
+	// Relationships
+	Members []StudyGroupMember `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE" json:"members,omitempty"`
+}
+
+func (StudyGroup) TableName() string {
+	return "study_groups"
+}
+
+type StudyGroupMember struct {
+	GroupID  int       `gorm:"primaryKey" json:"group_id"`
+	UserID   string    `gorm:"primaryKey;index;type:char(36)" json:"user_id"`
+	Role     int8      `gorm:"not null;default:0" json:"role"` // see consts.StudyGroupRole
+	JoinedAt time.Time `gorm:"not null" json:"joined_at"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (StudyGroupMember) TableName() string {
+	return "study_group_members"
+}
+
+// StudyGroupInvitation lets someone join a group until it expires. Email
+// invitations are bound to one address and used once; join links without an
+// email can be used by anyone holding them.
+type StudyGroupInvitation struct {
+	ID         int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	GroupID    int            `gorm:"not null;index" json:"group_id"`
+	Email      sql.NullString `gorm:"size:255" json:"email"`
+	Token      string         `gorm:"uniqueIndex;not null;type:char(64)" json:"-"`
+	InvitedBy  string         `gorm:"not null;type:char(36)" json:"invited_by"`
+	ExpiresAt  time.Time      `gorm:"not null" json:"expires_at"`
+	AcceptedAt sql.NullTime   `json:"accepted_at"`
+	TableCommon
+
+	// Relationships
+	Group *StudyGroup `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (StudyGroupInvitation) TableName() string {
+	return "study_group_invitations"
+}
+
+// StudyGroupNote is a note its author shared into a group
+type StudyGroupNote struct {
+	GroupID  int       `gorm:"primaryKey" json:"group_id"`
+	NoteID   int       `gorm:"primaryKey;index" json:"note_id"`
+	SharedBy string    `gorm:"not null;index;type:char(36)" json:"shared_by"`
+	SharedAt time.Time `gorm:"not null" json:"shared_at"`
+
+	// Relationships
+	Group *StudyGroup `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE" json:"-"`
+	Note  *Note       `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (StudyGroupNote) TableName() string {
+	return "study_group_notes"
+}
+
+type StudyGroupMessage struct {
+	ID      int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	GroupID int    `gorm:"not null;index" json:"group_id"`
+	UserID  string `gorm:"not null;index;type:char(36)" json:"user_id"`
+	Body    string `gorm:"type:text;not null" json:"body"`
+	TableCommon
+
+	// Relationships
+	Group *StudyGroup `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE" json:"-"`
+	User  *User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (StudyGroupMessage) TableName() string {
+	return "study_group_messages"
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/pkg/diff"
+)
+
+type CreateNoteRequest struct {
+	CourseID    int             `json:"course_id" binding:"required"`
+	LectureDate string          `json:"lecture_date" binding:"required"` // YYYY-MM-DD
+	Title       string          `json:"title" binding:"required,max=255"`
+	Content     json.RawMessage `json:"content" binding:"required" swaggertype:"object"` // editor JSON document with a "doc" root
+	Tags        []string        `json:"tags"`
+}
+
+type UpdateNoteRequest struct {
+	CourseID    *int            `json:"course_id"`
+	LectureDate *string         `json:"lecture_date"`
+	Title       *string         `json:"title" binding:"omitempty,max=255"`
+	Content     json.RawMessage `json:"content" swaggertype:"object"`
+	Tags        *[]string       `json:"tags"` // replaces all tags when present
+}
+
+type NoteQuery struct {
+	CourseID *int   `form:"course_id"`
+	Tag      string `form:"tag"`
+	From     string `form:"from"` // lecture date on or after (YYYY-MM-DD)
+	To       string `form:"to"`   // lecture date on or before (YYYY-MM-DD)
+}
+
+type NoteFilter struct {
+	UserID   string
+	CourseID *int
+	Tag      string
+	From     *time.Time
+	To       *time.Time
+}
+
+type NoteDiffQuery struct {
+	From int `form:"from"` // defaults to the revision before To
+	To   int `form:"to"`   // defaults to the latest revision
+}
+
+// NoteDiff compares the plain text of two revisions line by line
+type NoteDiff struct {
+	From      int         `json:"from"`
+	To        int         `json:"to"`
+	FromTitle string      `json:"from_title"`
+	ToTitle   string      `json:"to_title"`
+	Added     int         `json:"added"`
+	Removed   int         `json:"removed"`
+	Lines     []diff.Line `json:"lines"`
+}
+
+// ETag identifies the stored state of a note for If-Match. The version
+// changes with the title and content, the update time with everything else.
+func (n *Note) ETag() string {
+	return fmt.Sprintf(`"%d-%d"`, n.Version, n.UpdatedAt.UnixMilli())
+}
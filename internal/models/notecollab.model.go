@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/nas03/scholar-ai/backend/pkg/ot"
+	"github.com/nas03/scholar-ai/backend/pkg/richtext"
+)
+
+// NoteOperation edits the linear form of a note's content (see richtext.Token)
+type NoteOperation = ot.Operation[richtext.Token]
+
+// NoteCollabState is the shared state of a collaborative editing session
+type NoteCollabState struct {
+	Base int             // revision of the last snapshot
+	Rev  int             // latest revision
+	Doc  json.RawMessage // normalized document at Base, as saved in the note
+}
+
+// NoteCollabFrame is a frame a client sends over the editing connection:
+// {"type":"op","rev":12,"ops":[{"retain":5},{"insert":[{"kind":"char","text":"a"}]},{"retain":40}]}
+type NoteCollabFrame struct {
+	Type string        `json:"type"`
+	Rev  int           `json:"rev"` // revision the operation was made on
+	Ops  NoteOperation `json:"ops"`
+}
+
+// NoteCollabEvent is sent to the clients of an editing session; which fields
+// are set depends on the type, see consts.NoteCollabEventType
+type NoteCollabEvent struct {
+	Type    string          `json:"type"`
+	NoteID  int             `json:"note_id"`
+	Rev     int             `json:"rev"`
+	Doc     json.RawMessage `json:"doc,omitempty" swaggertype:"object"` // init: the document at rev
+	ConnID  string          `json:"conn_id,omitempty"`                  // init: this connection; op: the sending one, which takes it as its ack
+	UserID  string          `json:"user_id,omitempty"`
+	Ops     NoteOperation   `json:"ops,omitempty"`
+	Version int             `json:"version,omitempty"` // snapshot: the note version it was saved as
+	Code    int             `json:"code,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+type UpdateNotificationSettingRequest struct {
+	OffsetsMinutes []int `json:"offsets_minutes" binding:"required,max=10,dive,gte=1,lte=43200"`
+	EmailEnabled   *bool `json:"email_enabled" binding:"required"`
+}
+
+type NotificationSettingResponse struct {
+	OffsetsMinutes []int `json:"offsets_minutes"`
+	EmailEnabled   bool  `json:"email_enabled"`
+}
+
+type ReminderNotificationMail struct {
+	Username string `json:"username"`
+	Title    string `json:"title"`
+	Course   string `json:"course"`
+	DueAt    string `json:"due_at"`
+	TimeLeft string `json:"time_left"`
+}
+
+// OffsetsMinutes parses the comma-separated offsets column, ignoring malformed entries
+func (s *NotificationSetting) OffsetsMinutes() []int {
+	offsets := []int{}
+	for _, part := range strings.Split(s.Offsets, ",") {
+		if offset, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && offset > 0 {
+			offsets = append(offsets, offset)
+		}
+	}
+	return offsets
+}
+
+// FormatOffsets serializes offsets for the notification_settings.offsets column
+func FormatOffsets(offsets []int) string {
+	parts := make([]string, 0, len(offsets))
+	for _, offset := range offsets {
+		parts = append(parts, strconv.Itoa(offset))
+	}
+	return strings.Join(parts, ",")
+}
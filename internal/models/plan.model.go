@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// UsageCounter is the amount of a metered resource used against the plan's
+// limit; a nil Limit is unlimited
+type UsageCounter struct {
+	Used  int64  `json:"used"`
+	Limit *int64 `json:"limit"`
+}
+
+// Exceeded reports whether nothing more may be used
+func (c UsageCounter) Exceeded() bool {
+	return c.Limit != nil && c.Used >= *c.Limit
+}
+
+// OperationUsage is the ledger total of one operation in a billing period
+type OperationUsage struct {
+	Operation    string `json:"operation"`
+	Count        int64  `json:"count"`
+	InputTokens  int64  `json:"input_tokens"`
+	OutputTokens int64  `json:"output_tokens"`
+}
+
+// UsageSummary is the user's usage in the current billing period
+type UsageSummary struct {
+	Plan         Plan             `json:"plan"`
+	PeriodStart  time.Time        `json:"period_start"`
+	PeriodEnd    time.Time        `json:"period_end"`
+	AIOperations UsageCounter     `json:"ai_operations"`
+	AITokens     UsageCounter     `json:"ai_tokens"`
+	Courses      UsageCounter     `json:"courses"`
+	StorageBytes UsageCounter     `json:"storage_bytes"`
+	Operations   []OperationUsage `json:"operations"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// StudyWindow is a weekly span of time the user is free to study
+type StudyWindow struct {
+	Weekday   int8   `json:"weekday" binding:"min=0,max=6"` // day of week (0=Sunday ... 6=Saturday)
+	StartTime string `json:"start_time" binding:"required"` // HH:MM
+	EndTime   string `json:"end_time" binding:"required"`   // HH:MM
+}
+
+type UpdateStudyPreferenceRequest struct {
+	Windows         []StudyWindow `json:"windows" binding:"required,max=28,dive"`
+	BlockMinutes    *int          `json:"block_minutes" binding:"omitempty,min=15,max=180"`
+	BreakMinutes    *int          `json:"break_minutes" binding:"omitempty,min=0,max=60"`
+	MaxDailyMinutes *int          `json:"max_daily_minutes" binding:"omitempty,min=0,max=1440"` // 0 means unlimited
+}
+
+type StudyPreferenceResponse struct {
+	Windows         []StudyWindow `json:"windows"`
+	BlockMinutes    int           `json:"block_minutes"`
+	BreakMinutes    int           `json:"break_minutes"`
+	MaxDailyMinutes int           `json:"max_daily_minutes"`
+}
+
+type SetCourseDifficultyRequest struct {
+	Difficulty *int8 `json:"difficulty" binding:"omitempty,min=1,max=5"` // null clears the rating
+}
+
+type GenerateStudyPlanRequest struct {
+	HorizonDays int `json:"horizon_days" binding:"omitempty,min=1,max=90"` // days ahead to plan, defaults to 28
+}
+
+// AdjustStudyBlockRequest moves a study block; times are RFC 3339
+type AdjustStudyBlockRequest struct {
+	StartAt time.Time `json:"start_at" binding:"required"`
+	EndAt   time.Time `json:"end_at" binding:"required"`
+}
+
+// StudyPlanShortfall is study a reminder needs that did not fit into the
+// user's free time before it is due
+type StudyPlanShortfall struct {
+	ReminderID int `json:"reminder_id"`
+	Minutes    int `json:"minutes"`
+}
+
+// StudyPlanDetail reports whether the plan's deadlines, classes or
+// preferences changed since it was generated
+type StudyPlanDetail struct {
+	StudyPlan
+	Outdated bool `json:"outdated"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// JobProgress is one progress event of a background job. Status is the
+// status column of the row the job works on.
+type JobProgress struct {
+	ID     string `json:"-"`               // stream entry ID, sent as the SSE event ID
+	Status int8   `json:"status"`          // e.g. 1 processing, 2 done, 3 failed
+	Stage  string `json:"stage,omitempty"` // e.g. "summarize" or "merge"
+	Done   int    `json:"done,omitempty"`
+	Total  int    `json:"total,omitempty"`
+	Error  string `json:"error,omitempty"`
+	Final  bool   `json:"final"` // no more events follow
+}
@@ -0,0 +1,117 @@
+package models
+
+type CreateQuizRequest struct {
+	CourseID   int      `json:"course_id" binding:"required"`
+	Title      string   `json:"title" binding:"max=255"`                // defaults to the course name
+	Count      int      `json:"count" binding:"omitempty,min=1,max=30"` // defaults to 10
+	Difficulty string   `json:"difficulty"`                             // easy, medium or hard; defaults to medium
+	Types      []string `json:"types"`                                  // mcq, true_false, short_answer; defaults to all three
+	Language   string   `json:"language"`                               // en, vi, fr, de, es, ja, ko or zh; defaults to en
+}
+
+type QuizQuery struct {
+	CourseID *int `form:"course_id"`
+}
+
+// QuizGeneratePayload is the payload of a quiz generation job
+type QuizGeneratePayload struct {
+	QuizID        int    `json:"quiz_id"`
+	UserID        string `json:"user_id"`
+	ReservationID int    `json:"reservation_id,omitempty"` // usage reservation the job completes or releases
+}
+
+// QuizGradePayload is the payload of a job grading an attempt's short answers
+type QuizGradePayload struct {
+	AttemptID     int    `json:"attempt_id"`
+	UserID        string `json:"user_id"`
+	ReservationID int    `json:"reservation_id,omitempty"` // usage reservation the job completes or releases
+}
+
+// RubricCriterion is one point-bearing item of a short answer rubric
+type RubricCriterion struct {
+	Criterion string `json:"criterion"`
+	Points    int    `json:"points"`
+}
+
+// QuizQuestionView is a question without its answer key
+type QuizQuestionView struct {
+	ID         int      `json:"id"`
+	Position   int      `json:"position"`
+	Type       string   `json:"type"`
+	Difficulty string   `json:"difficulty"`
+	Prompt     string   `json:"prompt"`
+	Options    []string `json:"options,omitempty"`
+}
+
+// QuizDetail is a quiz with its questions, ready to practice
+type QuizDetail struct {
+	Quiz
+	Questions []QuizQuestionView `json:"questions"`
+}
+
+// QuizSolution is the answer key of a question, shown once an attempt is graded
+type QuizSolution struct {
+	AnswerIndex     *int              `json:"answer_index,omitempty"`
+	AnswerBool      *bool             `json:"answer_bool,omitempty"`
+	ReferenceAnswer string            `json:"reference_answer,omitempty"`
+	Rubric          []RubricCriterion `json:"rubric,omitempty"`
+	Explanation     string            `json:"explanation"`
+}
+
+type SubmitQuizAttemptRequest struct {
+	Answers []QuizAnswerInput `json:"answers" binding:"dive"`
+}
+
+// QuizAnswerInput answers one question: the option index for mcq, "true" or
+// "false" for true_false and free text for short_answer. Unanswered questions score zero.
+type QuizAnswerInput struct {
+	QuestionID int    `json:"question_id" binding:"required"`
+	Response   string `json:"response" binding:"max=4000"`
+}
+
+// QuizAttemptItem is a question of an attempt with the user's answer and,
+// once graded, the solution
+type QuizAttemptItem struct {
+	Question QuizQuestionView `json:"question"`
+	Answer   *QuizAnswer      `json:"answer,omitempty"`
+	Solution *QuizSolution    `json:"solution,omitempty"`
+}
+
+type QuizAttemptResult struct {
+	QuizAttempt
+	Items []QuizAttemptItem `json:"items"`
+}
+
+// QuizQuestionAggregate is a per-question row aggregated over graded answers
+type QuizQuestionAggregate struct {
+	QuestionID   int
+	Answered     int64
+	Correct      int64
+	AverageScore float64
+}
+
+// QuizResponseCount counts how often a response was given to a question
+type QuizResponseCount struct {
+	QuestionID int
+	Response   string
+	Count      int64
+}
+
+type QuizQuestionStat struct {
+	QuestionID   int              `json:"question_id"`
+	Position     int              `json:"position"`
+	Type         string           `json:"type"`
+	Prompt       string           `json:"prompt"`
+	Answered     int64            `json:"answered"`
+	Correct      int64            `json:"correct"`
+	CorrectRate  float64          `json:"correct_rate"`        // 0 to 1
+	AverageScore float64          `json:"average_score"`       // 0 to 1
+	Responses    map[string]int64 `json:"responses,omitempty"` // mcq option index or true/false to count
+}
+
+type QuizStats struct {
+	QuizID       int                `json:"quiz_id"`
+	Attempts     int64              `json:"attempts"`      // graded attempts
+	AverageScore float64            `json:"average_score"` // mean attempt score out of the question count
+	Questions    []QuizQuestionStat `json:"questions"`
+}
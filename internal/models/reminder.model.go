@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+type CreateReminderRequest struct {
+	Title       string   `json:"title" binding:"required"`
+	Description string   `json:"description"`
+	DueDate     string   `json:"due_date" binding:"required"` // YYYY-MM-DD
+	DueTime     string   `json:"due_time" binding:"required"` // HH:MM
+	CourseID    *int     `json:"course_id"`
+	Type        int8     `json:"type" binding:"oneof=0 1 2"`
+	Location    *string  `json:"location"`
+	Weight      *float64 `json:"weight" binding:"omitempty,gte=0,lte=100"`
+	Score       *float64 `json:"score" binding:"omitempty,gte=0,lte=100"` // percent obtained, exams only
+}
+
+type UpdateReminderRequest struct {
+	Title       *string  `json:"title"`
+	Description *string  `json:"description"`
+	DueDate     *string  `json:"due_date"`
+	DueTime     *string  `json:"due_time"`
+	CourseID    *int     `json:"course_id"`
+	Type        *int8    `json:"type" binding:"omitempty,oneof=0 1 2"`
+	Location    *string  `json:"location"`
+	Weight      *float64 `json:"weight" binding:"omitempty,gte=0,lte=100"`
+	Score       *float64 `json:"score" binding:"omitempty,gte=0,lte=100"` // percent obtained, exams only
+}
+
+type UpdateReminderStatusRequest struct {
+	Status int8 `json:"status" binding:"oneof=0 1"` // only pending/completed can be set manually
+}
+
+// ReminderQuery holds the query string filters for listing reminders
+type ReminderQuery struct {
+	CourseID *int   `form:"course_id"`
+	Type     *int8  `form:"type" binding:"omitempty,oneof=0 1 2"`
+	Status   *int8  `form:"status" binding:"omitempty,oneof=0 1 2"`
+	From     string `form:"from"` // YYYY-MM-DD, inclusive
+	To       string `form:"to"`   // YYYY-MM-DD, inclusive
+}
+
+// ReminderFilter is the parsed form of ReminderQuery used by the repository
+type ReminderFilter struct {
+	UserID   string
+	CourseID *int
+	Type     *int8
+	Status   *int8
+	From     *time.Time
+	To       *time.Time
+}
+
+// Deadline combines the stored due date and due time into a single instant.
+// Both are the user's wall clock, so loc must be the owner's timezone.
+func (r *Reminder) Deadline(loc *time.Location) time.Time {
+	clock, err := time.Parse(time.TimeOnly, r.DueTime)
+	if err != nil {
+		clock = time.Time{}
+	}
+	return time.Date(r.DueDate.Year(), r.DueDate.Month(), r.DueDate.Day(),
+		clock.Hour(), clock.Minute(), clock.Second(), 0, loc)
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type SearchQuery struct {
+	Q        string `form:"q" binding:"required,max=200"`
+	Types    string `form:"types"` // comma-separated subset of note,file,flashcard,course,reminder
+	CourseID *int   `form:"course_id"`
+	Tag      string `form:"tag"`  // item's own tag; files, flashcards and reminders match their course's tags
+	From     string `form:"from"` // YYYY-MM-DD, compared with lecture, due, upload or creation dates
+	To       string `form:"to"`   // YYYY-MM-DD, inclusive
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=50"`
+}
+
+// SearchFilter is a parsed SearchQuery handed to the search engine
+type SearchFilter struct {
+	UserID   string
+	Terms    []string
+	Types    []string
+	CourseID *int
+	Tag      string
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+	Offset   int
+}
+
+// SearchRow is a raw match as returned by the search engine
+type SearchRow struct {
+	Type      string
+	ID        int
+	Title     string
+	Body      string
+	Score     float64
+	CourseID  sql.NullInt64
+	Date      sql.NullTime
+	PageStart sql.NullInt64
+	PageEnd   sql.NullInt64
+}
+
+type SearchHit struct {
+	Type      string     `json:"type"`
+	ID        int        `json:"id"` // file hits carry the file ID and the file's best matching chunk
+	Title     string     `json:"title"`
+	Snippet   string     `json:"snippet"` // HTML-escaped excerpt with matches wrapped in <mark>
+	Score     float64    `json:"score"`
+	CourseID  *int       `json:"course_id,omitempty"`
+	Date      *time.Time `json:"date,omitempty"`
+	PageStart *int       `json:"page_start,omitempty"`
+	PageEnd   *int       `json:"page_end,omitempty"`
+}
+
+type SearchResponse struct {
+	Query    string           `json:"query"`
+	Total    int64            `json:"total"`
+	Counts   map[string]int64 `json:"counts"` // matches per type, before pagination
+	Page     int              `json:"page"`
+	PageSize int              `json:"page_size"`
+	Hits     []SearchHit      `json:"hits"`
+}
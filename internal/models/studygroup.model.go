@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+type CreateStudyGroupRequest struct {
+	CourseID    int    `json:"course_id" binding:"required"` // one of the user's courses, sets the group's course
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description"`
+}
+
+type UpdateStudyGroupRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=255"`
+	Description *string `json:"description"`
+}
+
+// CreateInvitationRequest invites one email address, or creates a join link
+// anyone holding it can use when the email is omitted
+type CreateInvitationRequest struct {
+	Email         *string `json:"email" binding:"omitempty,email"`
+	ExpiresInDays int     `json:"expires_in_days" binding:"omitempty,min=1,max=30"` // defaults to 7
+}
+
+type JoinStudyGroupRequest struct {
+	Token string `json:"token" binding:"required,len=64"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role *int8 `json:"role" binding:"required,oneof=0 1 2"` // making someone owner hands over the group and leaves you admin
+}
+
+type ShareNoteRequest struct {
+	NoteID int `json:"note_id" binding:"required"`
+}
+
+// StudyGroupSummary is a group the user belongs to
+type StudyGroupSummary struct {
+	StudyGroup
+	Role        int8  `json:"role"` // the user's role, see consts.StudyGroupRole
+	Members     int64 `json:"member_count"`
+	SharedNotes int64 `json:"shared_note_count"`
+}
+
+type StudyGroupMemberView struct {
+	UserID      string    `json:"user_id"`
+	Username    string    `json:"username"`
+	Role        int8      `json:"role"`
+	JoinedAt    time.Time `json:"joined_at"`
+	SharedNotes int64     `json:"shared_note_count"`
+}
+
+type StudyGroupDetail struct {
+	StudyGroup
+	Role    int8                   `json:"role"` // the user's role
+	Members []StudyGroupMemberView `json:"members"`
+}
+
+type StudyGroupInvitationResponse struct {
+	StudyGroupInvitation
+	JoinURL   string `json:"join_url"`
+	EmailSent bool   `json:"email_sent"` // false for join links, or when the mail could not be delivered
+}
+
+// SharedNote lists a note shared into a group without its content
+type SharedNote struct {
+	NoteID      int       `json:"note_id"`
+	Title       string    `json:"title"`
+	LectureDate time.Time `json:"lecture_date"`
+	AuthorID    string    `json:"author_id"`
+	AuthorName  string    `json:"author_name"`
+	SharedAt    time.Time `json:"shared_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type SharedNoteDetail struct {
+	Note
+	AuthorName string    `json:"author_name"`
+	SharedAt   time.Time `json:"shared_at"`
+}
+
+type StudyGroupInvitationMail struct {
+	Inviter   string `json:"inviter"`
+	Group     string `json:"group"`
+	Course    string `json:"course"`
+	JoinURL   string `json:"join_url"`
+	ExpiresAt string `json:"expires_at"`
+}
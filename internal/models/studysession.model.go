@@ -0,0 +1,95 @@
+package models
+
+import "time"
+
+type StartStudySessionRequest struct {
+	CourseID int  `json:"course_id" binding:"required"`
+	NoteID   *int `json:"note_id"` // a note of the course being studied
+}
+
+type StopStudySessionRequest struct {
+	Pomodoros *int `json:"pomodoros" binding:"omitempty,min=0,max=100"` // completed pomodoros, keeps the current count when omitted
+}
+
+// LogStudySessionRequest records a finished session after the fact; times are RFC 3339
+type LogStudySessionRequest struct {
+	CourseID  int       `json:"course_id" binding:"required"`
+	NoteID    *int      `json:"note_id"`
+	StartedAt time.Time `json:"started_at" binding:"required"`
+	EndedAt   time.Time `json:"ended_at" binding:"required"`
+	Pomodoros int       `json:"pomodoros" binding:"min=0,max=100"`
+}
+
+// StudySessionQuery holds the query string filters for listing study sessions
+type StudySessionQuery struct {
+	CourseID *int   `form:"course_id"`
+	From     string `form:"from"` // YYYY-MM-DD, inclusive
+	To       string `form:"to"`   // YYYY-MM-DD, inclusive
+}
+
+// StudySessionFilter is the parsed form of StudySessionQuery used by the repository
+type StudySessionFilter struct {
+	UserID   string
+	CourseID *int
+	From     *time.Time
+	To       *time.Time // exclusive
+}
+
+// StudyTimeQuery is the date range of the study time analytics, in the
+// user's timezone. It defaults to the last eight weeks.
+type StudyTimeQuery struct {
+	From string `form:"from"` // YYYY-MM-DD, inclusive
+	To   string `form:"to"`   // YYYY-MM-DD, inclusive
+}
+
+// CourseWeekStudyTime is the study time of a course in one week, next to the
+// time the user's accepted study plans had scheduled for it
+type CourseWeekStudyTime struct {
+	WeekStart      time.Time `json:"week_start"` // Monday of the week
+	CourseID       int       `json:"course_id"`
+	CourseCode     string    `json:"course_code"`
+	CourseName     string    `json:"course_name"`
+	StudyMinutes   int64     `json:"study_minutes"`
+	PlannedMinutes int64     `json:"planned_minutes"`
+	Sessions       int64     `json:"sessions"`
+	Pomodoros      int64     `json:"pomodoros"`
+}
+
+// StudyStreak is a run of consecutive days with study
+type StudyStreak struct {
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	Days      int       `json:"days"`
+}
+
+type StudyStreakSummary struct {
+	Current int          `json:"current"` // days, still alive when the user studied today or yesterday
+	Longest *StudyStreak `json:"longest"`
+}
+
+// CourseGradeStudyTime is a graded course's study time over the range
+type CourseGradeStudyTime struct {
+	CourseID     int     `json:"course_id"`
+	CourseCode   string  `json:"course_code"`
+	CourseName   string  `json:"course_name"`
+	GPA          float64 `json:"gpa"`
+	StudyMinutes int64   `json:"study_minutes"`
+}
+
+// StudyGradeCorrelation is the Pearson correlation between the study time
+// and the grade of graded courses; null with too few courses or no spread
+type StudyGradeCorrelation struct {
+	Courses     int64    `json:"courses"`
+	Coefficient *float64 `json:"coefficient"`
+}
+
+type StudyTimeAnalytics struct {
+	From           string                 `json:"from"`
+	To             string                 `json:"to"`
+	StudyMinutes   int64                  `json:"study_minutes"`
+	PlannedMinutes int64                  `json:"planned_minutes"`
+	Weeks          []CourseWeekStudyTime  `json:"weeks"`
+	Streak         StudyStreakSummary     `json:"streak"`
+	Grades         []CourseGradeStudyTime `json:"grades"`
+	Correlation    StudyGradeCorrelation  `json:"correlation"`
+}
@@ -0,0 +1,23 @@
+package models
+
+type CreateSummaryRequest struct {
+	Language string `json:"language"` // output language: en, vi, fr, de, es, ja, ko or zh; defaults to en
+}
+
+// NoteSummarizePayload is the payload of a note summarization job
+type NoteSummarizePayload struct {
+	SummaryID     int    `json:"summary_id"`
+	UserID        string `json:"user_id"`
+	ReservationID int    `json:"reservation_id,omitempty"` // usage reservation the job completes or releases
+}
+
+// SummaryContent is the structured output expected from the model
+type SummaryContent struct {
+	Bullets     []string     `json:"bullets"`
+	KeyConcepts []KeyConcept `json:"key_concepts"`
+}
+
+type KeyConcept struct {
+	Term        string `json:"term"`
+	Explanation string `json:"explanation"`
+}
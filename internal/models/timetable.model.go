@@ -0,0 +1,221 @@
+package models
+
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+)
+
+type CreateClassSessionRequest struct {
+	CourseID       int     `json:"course_id" binding:"required"`
+	Weekday        int8    `json:"weekday" binding:"min=0,max=6"`
+	StartTime      string  `json:"start_time" binding:"required"` // HH:MM
+	EndTime        string  `json:"end_time" binding:"required"`   // HH:MM
+	Location       *string `json:"location"`
+	Recurrence     int8    `json:"recurrence" binding:"oneof=0 1"`
+	StartDate      string  `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate        *string `json:"end_date"`                      // YYYY-MM-DD, open-ended when omitted
+	AllowConflicts bool    `json:"allow_conflicts"`               // save even when it overlaps other sessions
+}
+
+type UpdateClassSessionRequest struct {
+	Weekday        *int8   `json:"weekday" binding:"omitempty,min=0,max=6"`
+	StartTime      *string `json:"start_time"`
+	EndTime        *string `json:"end_time"`
+	Location       *string `json:"location"`
+	Recurrence     *int8   `json:"recurrence" binding:"omitempty,oneof=0 1"`
+	StartDate      *string `json:"start_date"`
+	EndDate        *string `json:"end_date"` // empty string clears the end date
+	AllowConflicts bool    `json:"allow_conflicts"`
+}
+
+type CreateClassSessionExceptionRequest struct {
+	Date   string  `json:"date" binding:"required"` // YYYY-MM-DD
+	Reason *string `json:"reason"`
+}
+
+type ClassSessionQuery struct {
+	CourseID *int `form:"course_id"`
+}
+
+type TimetableQuery struct {
+	From string `form:"from" binding:"required"` // YYYY-MM-DD, inclusive
+	To   string `form:"to" binding:"required"`   // YYYY-MM-DD, inclusive
+}
+
+// SessionConflict describes a clash between two class sessions
+type SessionConflict struct {
+	SessionID  int    `json:"session_id"`
+	UID        string `json:"uid,omitempty"` // imported event of a session not saved yet
+	CourseID   int    `json:"course_id"`
+	CourseName string `json:"course_name"`
+	Date       string `json:"date"` // first date both sessions meet
+	StartTime  string `json:"start_time"`
+	EndTime    string `json:"end_time"`
+}
+
+type ClassSessionResponse struct {
+	Session   *ClassSession     `json:"session"`
+	Conflicts []SessionConflict `json:"conflicts"` // only non-empty when allow_conflicts was set
+}
+
+// TimetableEntry is a single expanded occurrence of a class session
+type TimetableEntry struct {
+	SessionID  int    `json:"session_id"`
+	CourseID   int    `json:"course_id"`
+	CourseCode string `json:"course_code"`
+	CourseName string `json:"course_name"`
+	Date       string `json:"date"`
+	Weekday    int8   `json:"weekday"`
+	StartTime  string `json:"start_time"`
+	EndTime    string `json:"end_time"`
+	Location   string `json:"location,omitempty"`
+}
+
+// dateOnly truncates t to midnight UTC of its calendar date
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// FirstOccurrence is the first date on or after StartDate that falls on Weekday
+func (s *ClassSession) FirstOccurrence() time.Time {
+	start := dateOnly(s.StartDate)
+	shift := (int(s.Weekday) - int(start.Weekday()) + 7) % 7
+	return start.AddDate(0, 0, shift)
+}
+
+// lastDate returns the inclusive end of the series, or ok=false when open-ended
+func (s *ClassSession) lastDate() (time.Time, bool) {
+	if !s.EndDate.Valid {
+		return time.Time{}, false
+	}
+	return dateOnly(s.EndDate.Time), true
+}
+
+// IsScheduledOn reports whether the recurrence rule lands on date, ignoring exceptions
+func (s *ClassSession) IsScheduledOn(date time.Time) bool {
+	date = dateOnly(date)
+	first := s.FirstOccurrence()
+	if date.Before(first) || int8(date.Weekday()) != s.Weekday {
+		return false
+	}
+	if last, ok := s.lastDate(); ok && date.After(last) {
+		return false
+	}
+
+	if s.Recurrence == consts.ClassSessionRecurrence.BIWEEKLY {
+		weeks := int(date.Sub(first).Hours()/24) / 7
+		return weeks%2 == 0
+	}
+	return true
+}
+
+// OccursOn reports whether the session takes place on date, honouring cancelled dates
+func (s *ClassSession) OccursOn(date time.Time) bool {
+	if !s.IsScheduledOn(date) {
+		return false
+	}
+	date = dateOnly(date)
+	for _, exception := range s.Exceptions {
+		if dateOnly(exception.Date).Equal(date) {
+			return false
+		}
+	}
+	return true
+}
+
+// Occurrences lists the dates in [from, to] on which the session takes place
+func (s *ClassSession) Occurrences(from, to time.Time) []time.Time {
+	from, to = dateOnly(from), dateOnly(to)
+	if first := s.FirstOccurrence(); from.Before(first) {
+		from = first
+	}
+	if last, ok := s.lastDate(); ok && to.After(last) {
+		to = last
+	}
+
+	// Jump to the first matching weekday, then walk week by week
+	date := from.AddDate(0, 0, (int(s.Weekday)-int(from.Weekday())+7)%7)
+	var dates []time.Time
+	for ; !date.After(to); date = date.AddDate(0, 0, 7) {
+		if s.OccursOn(date) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+// FirstClashWith returns the first date on which both sessions take place at
+// overlapping times. Open-ended series are compared over horizonDays.
+func (s *ClassSession) FirstClashWith(other *ClassSession, horizonDays int) (time.Time, bool) {
+	if s.Weekday != other.Weekday || !(s.StartTime < other.EndTime && other.StartTime < s.EndTime) {
+		return time.Time{}, false
+	}
+
+	from := s.FirstOccurrence()
+	if otherFirst := other.FirstOccurrence(); otherFirst.After(from) {
+		from = otherFirst
+	}
+	to := from.AddDate(0, 0, horizonDays)
+	if last, ok := s.lastDate(); ok && last.Before(to) {
+		to = last
+	}
+	if last, ok := other.lastDate(); ok && last.Before(to) {
+		to = last
+	}
+
+	for _, date := range s.Occurrences(from, to) {
+		if other.OccursOn(date) {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+type TimetableImportRequest struct {
+	File           *multipart.FileHeader `form:"file" binding:"required"`
+	SemesterID     *int                  `form:"semester_id"`              // semester of newly created courses, detected from the dates when omitted
+	Commit         bool                  `form:"commit"`                   // false only previews the import
+	AllowConflicts bool                  `form:"allow_conflicts"`          // save even when imported sessions clash
+	Source         string                `form:"source" binding:"max=255"` // timetable the file replaces, the calendar's name when omitted
+}
+
+// TimetableImportResult describes what an import did, or would do when previewing
+type TimetableImportResult struct {
+	Committed bool                     `json:"committed"`
+	Source    string                   `json:"source"` // sessions of other sources are left alone
+	Courses   []TimetableImportCourse  `json:"courses"`
+	Sessions  []TimetableImportSession `json:"sessions"`
+	Skipped   []TimetableImportSkipped `json:"skipped"`
+}
+
+type TimetableImportCourse struct {
+	ID         int    `json:"id,omitempty"` // zero for courses not created yet
+	CourseCode string `json:"course_code"`
+	CourseName string `json:"course_name"`
+	Action     string `json:"action"` // existing or create
+}
+
+type TimetableImportSession struct {
+	SessionID  int               `json:"session_id,omitempty"` // zero for sessions not created yet
+	UID        string            `json:"uid"`
+	CourseCode string            `json:"course_code"`
+	Weekday    int8              `json:"weekday"`
+	StartTime  string            `json:"start_time"`
+	EndTime    string            `json:"end_time"`
+	Location   string            `json:"location,omitempty"`
+	Recurrence int8              `json:"recurrence"`
+	StartDate  string            `json:"start_date"`
+	EndDate    string            `json:"end_date,omitempty"`
+	Exceptions []string          `json:"exceptions,omitempty"`
+	Conflicts  []SessionConflict `json:"conflicts,omitempty"`
+	Action     string            `json:"action"` // create, update, unchanged or delete
+}
+
+// TimetableImportSkipped is an event that cannot be represented as a class session
+type TimetableImportSkipped struct {
+	UID     string `json:"uid"`
+	Summary string `json:"summary"`
+	Reason  string `json:"reason"`
+}
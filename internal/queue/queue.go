@@ -0,0 +1,430 @@
+// Package queue is a small Redis-backed background job queue.
+//
+// Each named queue uses four keys: a ready list, a delayed sorted set (score = run at),
+// an in-flight sorted set (score = visibility deadline) and a dead-letter list. Job
+// bodies live in a hash per job. All keys of a queue share the {queue} hash tag, so
+// the scripts touching them run on one Redis Cluster slot. Jobs are delivered at least
+// once: a job whose worker crashes or exceeds its visibility timeout is moved back to
+// the ready list, or dead-lettered once it has used up its attempts, and the late
+// worker's Ack or Fail is refused.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	DefaultQueue             = "default"
+	DefaultMaxAttempts       = 5
+	DefaultVisibilityTimeout = 5 * time.Minute
+	DefaultBackoffBase       = 5 * time.Second
+	DefaultBackoffMax        = 30 * time.Minute
+
+	keyPrefix = "queue:"
+)
+
+// errVisibilityTimeout is the last error of a job dead-lettered after its final delivery expired
+const errVisibilityTimeout = "visibility timeout expired"
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	// ErrLeaseLost is returned by Ack, Fail and Extend when the job's visibility timeout
+	// expired and it was handed to another worker in the meantime
+	ErrLeaseLost = errors.New("job lease lost")
+)
+
+// Job is a unit of background work
+type Job struct {
+	ID          string          `json:"id"`
+	Queue       string          `json:"queue"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+
+	// lease identifies the delivery; only its holder may finish the job
+	lease string
+}
+
+// EnqueueOption customizes a single Enqueue call
+type EnqueueOption func(*enqueueOptions)
+
+type enqueueOptions struct {
+	queue       string
+	delay       time.Duration
+	maxAttempts int
+	jobID       string
+}
+
+// OnQueue routes the job to the named queue instead of DefaultQueue
+func OnQueue(name string) EnqueueOption {
+	return func(o *enqueueOptions) { o.queue = name }
+}
+
+// WithDelay makes the job available only after d has elapsed
+func WithDelay(d time.Duration) EnqueueOption {
+	return func(o *enqueueOptions) { o.delay = d }
+}
+
+// WithMaxAttempts overrides how many times the job is tried before it is dead-lettered
+func WithMaxAttempts(n int) EnqueueOption {
+	return func(o *enqueueOptions) { o.maxAttempts = n }
+}
+
+// WithJobID sets the job ID, e.g. to correlate it with a database row
+func WithJobID(id string) EnqueueOption {
+	return func(o *enqueueOptions) { o.jobID = id }
+}
+
+// Options configures retry behaviour of a Client
+type Options struct {
+	VisibilityTimeout time.Duration
+	BackoffBase       time.Duration
+	BackoffMax        time.Duration
+}
+
+// Client enqueues and manages jobs on a Redis instance
+type Client struct {
+	rdb  *redis.Client
+	opts Options
+	now  func() time.Time
+}
+
+// NewClient creates a queue client on the given Redis connection (normally global.Redis)
+func NewClient(rdb *redis.Client, opts Options) *Client {
+	if opts.VisibilityTimeout <= 0 {
+		opts.VisibilityTimeout = DefaultVisibilityTimeout
+	}
+	if opts.BackoffBase <= 0 {
+		opts.BackoffBase = DefaultBackoffBase
+	}
+	if opts.BackoffMax <= 0 {
+		opts.BackoffMax = DefaultBackoffMax
+	}
+	return &Client{rdb: rdb, opts: opts, now: time.Now}
+}
+
+// SetClock replaces the clock used for scheduling; intended for tests
+func (c *Client) SetClock(now func() time.Time) {
+	c.now = now
+}
+
+// VisibilityTimeout is how long a dequeued job stays invisible before redelivery
+func (c *Client) VisibilityTimeout() time.Duration {
+	return c.opts.VisibilityTimeout
+}
+
+func queueKey(queue string) string     { return keyPrefix + "{" + queue + "}:" }
+func readyKey(queue string) string     { return queueKey(queue) + "ready" }
+func delayedKey(queue string) string   { return queueKey(queue) + "delayed" }
+func inflightKey(queue string) string  { return queueKey(queue) + "inflight" }
+func deadKey(queue string) string      { return queueKey(queue) + "dead" }
+func jobKeyPrefix(queue string) string { return queueKey(queue) + "job:" }
+func jobKey(queue, id string) string   { return jobKeyPrefix(queue) + id }
+
+// Enqueue stores a job of the given type with its JSON-encoded payload
+func (c *Client) Enqueue(ctx context.Context, jobType string, payload any, options ...EnqueueOption) (*Job, error) {
+	opts := enqueueOptions{queue: DefaultQueue, maxAttempts: DefaultMaxAttempts}
+	for _, option := range options {
+		option(&opts)
+	}
+	if opts.jobID == "" {
+		opts.jobID = uuid.NewString()
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode payload for %s: %w", jobType, err)
+	}
+
+	job := &Job{
+		ID:          opts.jobID,
+		Queue:       opts.queue,
+		Type:        jobType,
+		Payload:     body,
+		MaxAttempts: opts.maxAttempts,
+		CreatedAt:   c.now().UTC(),
+	}
+
+	pipe := c.rdb.TxPipeline()
+	pipe.HSet(ctx, jobKey(job.Queue, job.ID), map[string]any{
+		"queue":        job.Queue,
+		"type":         job.Type,
+		"payload":      string(job.Payload),
+		"attempts":     0,
+		"max_attempts": job.MaxAttempts,
+		"created_at":   job.CreatedAt.UnixMilli(),
+	})
+	if opts.delay > 0 {
+		pipe.ZAdd(ctx, delayedKey(job.Queue), redis.Z{Score: float64(c.now().Add(opts.delay).UnixMilli()), Member: job.ID})
+	} else {
+		pipe.LPush(ctx, readyKey(job.Queue), job.ID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("enqueue %s: %w", jobType, err)
+	}
+	return job, nil
+}
+
+// dequeueScript promotes due delayed jobs, reclaims in-flight jobs whose visibility
+// timeout expired (revoking their lease), then pops one ready job, marks it in flight and stores the lease of
+// the delivery. An expired delivery counts as an attempt: a reclaimed job that has used
+// up its attempts is dead-lettered rather than redelivered, so a job that crashes its
+// worker cannot loop forever. Running it as a single script keeps concurrent workers from ever
+// receiving the same delivery. Job hashes are addressed through the queue's key prefix
+// (ARGV[3]); they share its hash tag, so they live on the slot of KEYS.
+var dequeueScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[1], 'LIMIT', 0, 100)
+for _, id in ipairs(due) do
+	redis.call('ZREM', KEYS[2], id)
+	redis.call('LPUSH', KEYS[1], id)
+end
+local expired = redis.call('ZRANGEBYSCORE', KEYS[3], '-inf', ARGV[1], 'LIMIT', 0, 100)
+for _, id in ipairs(expired) do
+	redis.call('ZREM', KEYS[3], id)
+	redis.call('HDEL', ARGV[3] .. id, 'lease')
+	local counts = redis.call('HMGET', ARGV[3] .. id, 'attempts', 'max_attempts')
+	local attempts, maxAttempts = tonumber(counts[1]), tonumber(counts[2])
+	if attempts and maxAttempts and attempts >= maxAttempts then
+		redis.call('HSET', ARGV[3] .. id, 'last_error', ARGV[5])
+		redis.call('LPUSH', KEYS[4], id)
+	else
+		redis.call('RPUSH', KEYS[1], id)
+	end
+end
+local id = redis.call('RPOP', KEYS[1])
+if not id then
+	return false
+end
+redis.call('ZADD', KEYS[3], ARGV[2], id)
+redis.call('HINCRBY', ARGV[3] .. id, 'attempts', 1)
+redis.call('HSET', ARGV[3] .. id, 'lease', ARGV[4])
+return id
+`)
+
+// ackScript deletes an in-flight job, provided the caller still holds its lease
+var ackScript = redis.NewScript(`
+if redis.call('HGET', KEYS[2], 'lease') ~= ARGV[2] then
+	return 0
+end
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('DEL', KEYS[2])
+return 1
+`)
+
+// failScript records the error of an in-flight job held under the caller's lease and
+// moves it to the dead-letter list (ARGV[4] = 1) or schedules its retry at ARGV[5]
+var failScript = redis.NewScript(`
+if redis.call('HGET', KEYS[2], 'lease') ~= ARGV[2] then
+	return 0
+end
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('HSET', KEYS[2], 'last_error', ARGV[3])
+redis.call('HDEL', KEYS[2], 'lease')
+if ARGV[4] == '1' then
+	redis.call('LPUSH', KEYS[4], ARGV[1])
+else
+	redis.call('ZADD', KEYS[3], ARGV[5], ARGV[1])
+end
+return 1
+`)
+
+// extendScript pushes the visibility deadline of an in-flight job held under the caller's lease
+var extendScript = redis.NewScript(`
+if redis.call('HGET', KEYS[2], 'lease') ~= ARGV[2] then
+	return 0
+end
+redis.call('ZADD', KEYS[1], 'XX', ARGV[3], ARGV[1])
+return 1
+`)
+
+// Dequeue returns the next job of the queue, or nil when none is ready.
+// The job must be finished with Ack or Fail before its visibility timeout.
+func (c *Client) Dequeue(ctx context.Context, queue string) (*Job, error) {
+	now := c.now()
+	lease := uuid.NewString()
+	id, err := dequeueScript.Run(ctx, c.rdb,
+		[]string{readyKey(queue), delayedKey(queue), inflightKey(queue), deadKey(queue)},
+		now.UnixMilli(), now.Add(c.opts.VisibilityTimeout).UnixMilli(), jobKeyPrefix(queue), lease, errVisibilityTimeout,
+	).Text()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("dequeue %s: %w", queue, err)
+	}
+
+	job, err := c.GetJob(ctx, queue, id)
+	if errors.Is(err, ErrJobNotFound) {
+		// Body is gone (e.g. deleted while queued); drop the orphaned id
+		c.rdb.ZRem(ctx, inflightKey(queue), id)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	job.lease = lease
+	return job, nil
+}
+
+// GetJob loads a job of the queue by ID
+func (c *Client) GetJob(ctx context.Context, queue, id string) (*Job, error) {
+	fields, err := c.rdb.HGetAll(ctx, jobKey(queue, id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, ErrJobNotFound
+	}
+
+	attempts, _ := strconv.Atoi(fields["attempts"])
+	maxAttempts, _ := strconv.Atoi(fields["max_attempts"])
+	createdAt, _ := strconv.ParseInt(fields["created_at"], 10, 64)
+	return &Job{
+		ID:          id,
+		Queue:       fields["queue"],
+		Type:        fields["type"],
+		Payload:     json.RawMessage(fields["payload"]),
+		Attempts:    attempts,
+		MaxAttempts: maxAttempts,
+		LastError:   fields["last_error"],
+		CreatedAt:   time.UnixMilli(createdAt).UTC(),
+	}, nil
+}
+
+// Ack marks the job as done and deletes it.
+// Returns ErrLeaseLost when the delivery is no longer the job's current one.
+func (c *Client) Ack(ctx context.Context, job *Job) error {
+	acked, err := ackScript.Run(ctx, c.rdb,
+		[]string{inflightKey(job.Queue), jobKey(job.Queue, job.ID)},
+		job.ID, job.lease,
+	).Int()
+	if err != nil {
+		return err
+	}
+	if acked == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Fail records the error and either schedules a retry with exponential backoff or,
+// once MaxAttempts is reached, moves the job to the dead-letter list.
+// It reports whether the job was dead-lettered, and returns ErrLeaseLost when the
+// delivery is no longer the job's current one.
+func (c *Client) Fail(ctx context.Context, job *Job, cause error) (bool, error) {
+	job.LastError = cause.Error()
+	dead := job.Attempts >= job.MaxAttempts
+	runAt := c.now().Add(c.Backoff(job.Attempts))
+
+	deadFlag := 0
+	if dead {
+		deadFlag = 1
+	}
+	failed, err := failScript.Run(ctx, c.rdb,
+		[]string{inflightKey(job.Queue), jobKey(job.Queue, job.ID), delayedKey(job.Queue), deadKey(job.Queue)},
+		job.ID, job.lease, job.LastError, deadFlag, runAt.UnixMilli(),
+	).Int()
+	if err != nil {
+		return false, err
+	}
+	if failed == 0 {
+		return false, ErrLeaseLost
+	}
+	return dead, nil
+}
+
+// Backoff returns the retry delay after the given attempt: base * 2^(attempt-1), capped
+func (c *Client) Backoff(attempt int) time.Duration {
+	delay := c.opts.BackoffBase
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= c.opts.BackoffMax {
+			return c.opts.BackoffMax
+		}
+	}
+	return delay
+}
+
+// Extend pushes the visibility deadline of an in-flight job, for handlers that run long
+func (c *Client) Extend(ctx context.Context, job *Job, d time.Duration) error {
+	extended, err := extendScript.Run(ctx, c.rdb,
+		[]string{inflightKey(job.Queue), jobKey(job.Queue, job.ID)},
+		job.ID, job.lease, c.now().Add(d).UnixMilli(),
+	).Int()
+	if err != nil {
+		return err
+	}
+	if extended == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Stats is a snapshot of a queue's sizes
+type Stats struct {
+	Ready    int64 `json:"ready"`
+	Delayed  int64 `json:"delayed"`
+	InFlight int64 `json:"in_flight"`
+	Dead     int64 `json:"dead"`
+}
+
+func (c *Client) Stats(ctx context.Context, queue string) (*Stats, error) {
+	pipe := c.rdb.Pipeline()
+	ready := pipe.LLen(ctx, readyKey(queue))
+	delayed := pipe.ZCard(ctx, delayedKey(queue))
+	inflight := pipe.ZCard(ctx, inflightKey(queue))
+	dead := pipe.LLen(ctx, deadKey(queue))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	return &Stats{Ready: ready.Val(), Delayed: delayed.Val(), InFlight: inflight.Val(), Dead: dead.Val()}, nil
+}
+
+// DeadJobs lists up to limit dead-lettered jobs, newest first
+func (c *Client) DeadJobs(ctx context.Context, queue string, limit int64) ([]*Job, error) {
+	ids, err := c.rdb.LRange(ctx, deadKey(queue), 0, limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*Job, 0, len(ids))
+	for _, id := range ids {
+		job, err := c.GetJob(ctx, queue, id)
+		if errors.Is(err, ErrJobNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// RetryDead moves a dead-lettered job back to the ready list with a fresh attempt budget
+func (c *Client) RetryDead(ctx context.Context, queue, id string) error {
+	removed, err := c.rdb.LRem(ctx, deadKey(queue), 1, id).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrJobNotFound
+	}
+
+	pipe := c.rdb.TxPipeline()
+	pipe.HSet(ctx, jobKey(queue, id), "attempts", 0)
+	pipe.LPush(ctx, readyKey(queue), id)
+	_, err = pipe.Exec(ctx)
+	return err
+}
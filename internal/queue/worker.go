@@ -0,0 +1,166 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"go.uber.org/zap"
+)
+
+// ErrSkipRetry can be wrapped by a handler error to dead-letter the job immediately,
+// for failures that retrying cannot fix (bad payload, deleted resource, ...)
+var ErrSkipRetry = errors.New("skip retry")
+
+// HandlerFunc processes one job. Returning an error schedules a retry.
+type HandlerFunc func(ctx context.Context, job *Job) error
+
+// Mux routes jobs to handlers by job type
+type Mux struct {
+	handlers map[string]HandlerFunc
+}
+
+func NewMux() *Mux {
+	return &Mux{handlers: map[string]HandlerFunc{}}
+}
+
+// Handle registers a raw handler for a job type
+func (m *Mux) Handle(jobType string, handler HandlerFunc) {
+	m.handlers[jobType] = handler
+}
+
+// Register adds a typed handler whose payload is decoded from JSON before the call.
+// Payloads that fail to decode are dead-lettered without retrying.
+func Register[T any](m *Mux, jobType string, handler func(ctx context.Context, job *Job, payload T) error) {
+	m.Handle(jobType, func(ctx context.Context, job *Job) error {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return fmt.Errorf("%w: decode %s payload: %v", ErrSkipRetry, jobType, err)
+		}
+		return handler(ctx, job, payload)
+	})
+}
+
+func (m *Mux) dispatch(ctx context.Context, job *Job) (err error) {
+	handler, ok := m.handlers[job.Type]
+	if !ok {
+		return fmt.Errorf("%w: no handler registered for job type %q", ErrSkipRetry, job.Type)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panic: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// Worker consumes one queue with a bounded number of concurrent handlers
+type Worker struct {
+	client       *Client
+	mux          *Mux
+	queue        string
+	concurrency  int
+	pollInterval time.Duration
+}
+
+func NewWorker(client *Client, mux *Mux, queue string, concurrency int, pollInterval time.Duration) *Worker {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+	return &Worker{
+		client:       client,
+		mux:          mux,
+		queue:        queue,
+		concurrency:  concurrency,
+		pollInterval: pollInterval,
+	}
+}
+
+// Run blocks until ctx is cancelled and every in-flight job has finished
+func (w *Worker) Run(ctx context.Context) {
+	global.Log.Info("Queue worker started", zap.String("queue", w.queue), zap.Int("concurrency", w.concurrency))
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx)
+		}()
+	}
+	wg.Wait()
+
+	global.Log.Info("Queue worker stopped", zap.String("queue", w.queue))
+}
+
+func (w *Worker) loop(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := w.ProcessOne(ctx)
+		if err != nil {
+			global.Log.Error("Queue worker error", zap.String("queue", w.queue), zap.Error(err))
+		}
+		if processed {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(w.pollInterval):
+		}
+	}
+}
+
+// ProcessOne dequeues and handles a single job. It reports whether a job was found.
+func (w *Worker) ProcessOne(ctx context.Context) (bool, error) {
+	job, err := w.client.Dequeue(ctx, w.queue)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	// Handlers get a fresh context so shutdown lets them finish, bounded by the
+	// visibility timeout after which the job would be redelivered anyway
+	handlerCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), w.client.VisibilityTimeout())
+	defer cancel()
+
+	start := time.Now()
+	if handleErr := w.mux.dispatch(handlerCtx, job); handleErr != nil {
+		if errors.Is(handleErr, ErrSkipRetry) {
+			job.Attempts = job.MaxAttempts
+		}
+
+		dead, err := w.client.Fail(context.WithoutCancel(ctx), job, handleErr)
+		if errors.Is(err, ErrLeaseLost) {
+			global.Log.Warn("Job failed after its lease expired, leaving it to the new delivery", zap.String("queue", job.Queue), zap.String("type", job.Type), zap.String("jobID", job.ID), zap.Error(handleErr))
+			return true, nil
+		}
+		if err != nil {
+			return true, fmt.Errorf("record failure of job %s: %w", job.ID, err)
+		}
+		global.Log.Warn("Job failed",
+			zap.String("queue", job.Queue),
+			zap.String("type", job.Type),
+			zap.String("jobID", job.ID),
+			zap.Int("attempts", job.Attempts),
+			zap.Bool("dead_lettered", dead),
+			zap.Error(handleErr),
+		)
+		return true, nil
+	}
+
+	if err := w.client.Ack(context.WithoutCancel(ctx), job); errors.Is(err, ErrLeaseLost) {
+		global.Log.Warn("Job finished after its lease expired, it may run again", zap.String("queue", job.Queue), zap.String("type", job.Type), zap.String("jobID", job.ID), zap.Duration("took", time.Since(start)))
+		return true, nil
+	} else if err != nil {
+		return true, fmt.Errorf("ack job %s: %w", job.ID, err)
+	}
+	global.Log.Debug("Job done", zap.String("queue", job.Queue), zap.String("type", job.Type), zap.String("jobID", job.ID), zap.Duration("took", time.Since(start)))
+	return true, nil
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// IAnalyticsRepository aggregates the user's courses for the performance
+// analytics. Courses with GPA 0 have no grade yet and are left out of averages.
+type IAnalyticsRepository interface {
+	// SemesterGPA returns the GPA of every semester the user has courses in, oldest first
+	SemesterGPA(ctx context.Context, userID string) ([]models.SemesterGPA, error)
+	TagPerformance(ctx context.Context, userID string) ([]models.GroupPerformance, error)
+	// SubjectPerformance groups courses by the letters their code starts with
+	SubjectPerformance(ctx context.Context, userID string) ([]models.GroupPerformance, error)
+	// CreditProgress sorts the credits of the user's courses by grade and semester end, as of today
+	CreditProgress(ctx context.Context, userID string, passingGPA float64, today time.Time) (*models.CreditProgress, error)
+	// CourseExamResults summarizes the graded assessments of courses without a final grade,
+	// falling back to scored, weighted exams for courses without assessments
+	CourseExamResults(ctx context.Context, userID string) ([]models.CourseExamResults, error)
+}
+
+type AnalyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsRepository creates a new analytics repository with the given database connection.
+func NewAnalyticsRepository(db *gorm.DB) IAnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+func (r *AnalyticsRepository) SemesterGPA(ctx context.Context, userID string) ([]models.SemesterGPA, error) {
+	query := `SELECT t.semester_id, t.name, t.start_date, t.end_date, t.courses, t.credits,
+			t.grade_points / NULLIF(t.graded_credits, 0) AS gpa,
+			SUM(t.grade_points) OVER w / NULLIF(SUM(t.graded_credits) OVER w, 0) AS cumulative_gpa
+		FROM (
+			SELECT s.id AS semester_id, s.name, s.start_date, s.end_date, COUNT(*) AS courses, SUM(c.credits) AS credits,
+				SUM(CASE WHEN c.gpa > 0 THEN c.gpa * c.credits ELSE 0 END) AS grade_points,
+				SUM(CASE WHEN c.gpa > 0 THEN c.credits ELSE 0 END) AS graded_credits
+			FROM courses c
+			JOIN semesters s ON s.id = c.semester_id
+			WHERE c.user_id = ?
+			GROUP BY s.id, s.name, s.start_date, s.end_date
+		) AS t
+		WINDOW w AS (ORDER BY t.start_date, t.semester_id)
+		ORDER BY t.start_date ASC, t.semester_id ASC`
+
+	var semesters []models.SemesterGPA
+	err := r.db.WithContext(ctx).Raw(query, userID).Scan(&semesters).Error
+	if err != nil {
+		return nil, err
+	}
+	return semesters, nil
+}
+
+// groupPerformance aggregates a group of courses into models.GroupPerformance
+const groupPerformance = `COUNT(*) AS courses,
+	SUM(CASE WHEN c.gpa > 0 THEN 1 ELSE 0 END) AS graded_courses,
+	SUM(c.credits) AS credits,
+	SUM(CASE WHEN c.gpa > 0 THEN c.gpa * c.credits ELSE 0 END) / NULLIF(SUM(CASE WHEN c.gpa > 0 THEN c.credits ELSE 0 END), 0) AS gpa,
+	MIN(NULLIF(c.gpa, 0)) AS min_gpa,
+	MAX(NULLIF(c.gpa, 0)) AS max_gpa`
+
+func (r *AnalyticsRepository) TagPerformance(ctx context.Context, userID string) ([]models.GroupPerformance, error) {
+	query := `SELECT t.id AS tag_id, t.name AS ` + "`key`" + `, ` + groupPerformance + `
+		FROM courses c
+		JOIN course_tags ct ON ct.course_id = c.id
+		JOIN tags t ON t.id = ct.tag_id
+		WHERE c.user_id = ?
+		GROUP BY t.id, t.name
+		ORDER BY t.name ASC`
+
+	var tags []models.GroupPerformance
+	err := r.db.WithContext(ctx).Raw(query, userID).Scan(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *AnalyticsRepository) SubjectPerformance(ctx context.Context, userID string) ([]models.GroupPerformance, error) {
+	// Codes without leading letters form a subject of their own
+	query := `SELECT UPPER(COALESCE(REGEXP_SUBSTR(c.course_id, '^[A-Za-z]+'), c.course_id)) AS ` + "`key`" + `, ` + groupPerformance + `
+		FROM courses c
+		WHERE c.user_id = ?
+		GROUP BY ` + "`key`" + `
+		ORDER BY ` + "`key`" + ` ASC`
+
+	var subjects []models.GroupPerformance
+	err := r.db.WithContext(ctx).Raw(query, userID).Scan(&subjects).Error
+	if err != nil {
+		return nil, err
+	}
+	return subjects, nil
+}
+
+func (r *AnalyticsRepository) CreditProgress(ctx context.Context, userID string, passingGPA float64, today time.Time) (*models.CreditProgress, error) {
+	query := `SELECT
+			COALESCE(SUM(CASE WHEN c.gpa >= ? THEN c.credits END), 0) AS earned,
+			COALESCE(SUM(CASE WHEN c.gpa = 0 AND s.end_date >= ? THEN c.credits END), 0) AS in_progress,
+			COALESCE(SUM(CASE WHEN c.gpa > 0 AND c.gpa < ? THEN c.credits END), 0) AS failed,
+			COALESCE(SUM(CASE WHEN c.gpa = 0 AND s.end_date < ? THEN c.credits END), 0) AS ungraded
+		FROM courses c
+		JOIN semesters s ON s.id = c.semester_id
+		WHERE c.user_id = ?`
+
+	date := today.Format(consts.DATE_LAYOUT)
+	var progress models.CreditProgress
+	err := r.db.WithContext(ctx).Raw(query, passingGPA, date, passingGPA, date, userID).Scan(&progress).Error
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+func (r *AnalyticsRepository) CourseExamResults(ctx context.Context, userID string) ([]models.CourseExamResults, error) {
+	// Scored assessments, or the scored exam reminders of courses without
+	// assessments. Weighted variance as E[x²] - E[x]², floored at 0 against rounding.
+	query := `SELECT c.id AS course_id, c.course_id AS course_code, c.course_name, c.credits,
+			SUM(g.weight) AS graded_weight,
+			SUM(g.weight * g.score) / SUM(g.weight) AS mean_score,
+			SQRT(GREATEST(SUM(g.weight * g.score * g.score) / SUM(g.weight) - POW(SUM(g.weight * g.score) / SUM(g.weight), 2), 0)) AS score_std_dev,
+			COUNT(*) AS results
+		FROM courses c
+		JOIN (
+			SELECT a.course_id, a.weight, a.score * 100 / a.max_score AS score
+			FROM assessments a
+			WHERE a.user_id = ? AND a.score IS NOT NULL
+			UNION ALL
+			SELECT r.course_id, r.weight, r.score
+			FROM reminders r
+			WHERE r.user_id = ? AND r.type = ? AND r.weight > 0 AND r.score IS NOT NULL
+				AND NOT EXISTS (SELECT 1 FROM assessments a WHERE a.course_id = r.course_id)
+		) AS g ON g.course_id = c.id
+		WHERE c.user_id = ? AND c.gpa = 0
+		GROUP BY c.id, c.course_id, c.course_name, c.credits
+		ORDER BY c.course_id ASC`
+
+	var results []models.CourseExamResults
+	err := r.db.WithContext(ctx).Raw(query, userID, userID, consts.ReminderType.EXAM, userID).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
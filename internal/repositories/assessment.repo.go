@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type IAssessmentRepository interface {
+	// ListAssessments returns the course's assessments by due date, undated ones last
+	ListAssessments(ctx context.Context, courseID int, userID string) ([]models.Assessment, error)
+	GetAssessmentByID(ctx context.Context, id, courseID int, userID string) (*models.Assessment, error)
+	CreateAssessments(ctx context.Context, assessments []models.Assessment) error
+	UpdateAssessment(ctx context.Context, id int, userID string, updates map[string]any) error
+	// DeleteAssessments removes the course's assessments other than keepIDs
+	DeleteAssessments(ctx context.Context, courseID int, userID string, keepIDs []int) error
+
+	WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+	WithTx(tx *gorm.DB) IAssessmentRepository
+}
+
+type AssessmentRepository struct {
+	db *gorm.DB
+}
+
+// NewAssessmentRepository creates a new assessment repository with the given database connection.
+func NewAssessmentRepository(db *gorm.DB) IAssessmentRepository {
+	return &AssessmentRepository{db: db}
+}
+
+// WithTx creates a new instance of the repository with a transaction
+func (r *AssessmentRepository) WithTx(tx *gorm.DB) IAssessmentRepository {
+	return &AssessmentRepository{db: tx}
+}
+
+func (r *AssessmentRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+func (r *AssessmentRepository) ListAssessments(ctx context.Context, courseID int, userID string) ([]models.Assessment, error) {
+	var assessments []models.Assessment
+	err := r.db.WithContext(ctx).
+		Where("course_id = ? AND user_id = ?", courseID, userID).
+		Order("due_date IS NULL, due_date ASC, id ASC").
+		Find(&assessments).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return assessments, nil
+}
+
+func (r *AssessmentRepository) GetAssessmentByID(ctx context.Context, id, courseID int, userID string) (*models.Assessment, error) {
+	var assessment models.Assessment
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND course_id = ? AND user_id = ?", id, courseID, userID).
+		First(&assessment).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &assessment, nil
+}
+
+func (r *AssessmentRepository) CreateAssessments(ctx context.Context, assessments []models.Assessment) error {
+	if len(assessments) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Omit("Course").Create(&assessments).Error
+}
+
+func (r *AssessmentRepository) UpdateAssessment(ctx context.Context, id int, userID string, updates map[string]any) error {
+	return r.db.WithContext(ctx).Model(&models.Assessment{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(updates).Error
+}
+
+func (r *AssessmentRepository) DeleteAssessments(ctx context.Context, courseID int, userID string, keepIDs []int) error {
+	query := r.db.WithContext(ctx).Where("course_id = ? AND user_id = ?", courseID, userID)
+	if len(keepIDs) > 0 {
+		query = query.Where("id NOT IN ?", keepIDs)
+	}
+	return query.Delete(&models.Assessment{}).Error
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type IAssistantRepository interface {
+	CreateConversation(ctx context.Context, conversation *models.AssistantConversation) error
+	GetConversationByID(ctx context.Context, id int, userID string) (*models.AssistantConversation, error)
+	// ListConversations returns the user's conversations, most recently active first
+	ListConversations(ctx context.Context, userID string) ([]models.AssistantConversation, error)
+	UpdateConversation(ctx context.Context, id int, userID string, updates map[string]any) error
+	DeleteConversation(ctx context.Context, id int, userID string) error
+
+	CreateMessage(ctx context.Context, message *models.AssistantMessage) error
+	// ListMessages returns the conversation's messages oldest first; a positive
+	// limit keeps only the latest ones
+	ListMessages(ctx context.Context, conversationID, limit int) ([]models.AssistantMessage, error)
+}
+
+type AssistantRepository struct {
+	db *gorm.DB
+}
+
+// NewAssistantRepository creates a new assistant repository with the given database connection.
+func NewAssistantRepository(db *gorm.DB) IAssistantRepository {
+	return &AssistantRepository{db: db}
+}
+
+func (r *AssistantRepository) CreateConversation(ctx context.Context, conversation *models.AssistantConversation) error {
+	return r.db.WithContext(ctx).Create(conversation).Error
+}
+
+func (r *AssistantRepository) GetConversationByID(ctx context.Context, id int, userID string) (*models.AssistantConversation, error) {
+	var conversation models.AssistantConversation
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&conversation).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+func (r *AssistantRepository) ListConversations(ctx context.Context, userID string) ([]models.AssistantConversation, error) {
+	var conversations []models.AssistantConversation
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("updated_at DESC, id DESC").
+		Find(&conversations).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return conversations, nil
+}
+
+// UpdateConversation applies the given column updates; updated_at is always
+// bumped so that active conversations sort first.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *AssistantRepository) UpdateConversation(ctx context.Context, id int, userID string, updates map[string]any) error {
+	// Remove fields that shouldn't be updated directly
+	delete(updates, "id")
+	delete(updates, "user_id")
+	delete(updates, "created_at")
+
+	return r.db.WithContext(ctx).Model(&models.AssistantConversation{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(updates).Error
+}
+
+// DeleteConversation removes a conversation and its messages.
+// Returns gorm.ErrRecordNotFound when the conversation does not belong to the user
+func (r *AssistantRepository) DeleteConversation(ctx context.Context, id int, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&models.AssistantConversation{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *AssistantRepository) CreateMessage(ctx context.Context, message *models.AssistantMessage) error {
+	return r.db.WithContext(ctx).Create(message).Error
+}
+
+func (r *AssistantRepository) ListMessages(ctx context.Context, conversationID, limit int) ([]models.AssistantMessage, error) {
+	query := r.db.WithContext(ctx).
+		Where("conversation_id = ?", conversationID).
+		Order("id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var messages []models.AssistantMessage
+	if err := query.Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	slices.Reverse(messages)
+	return messages, nil
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IBillingRepository interface {
+	// CreateEvent records a webhook; a redelivered event fails with gorm.ErrDuplicatedKey
+	CreateEvent(ctx context.Context, event *models.BillingEvent) error
+
+	// Subscriptions are read FOR UPDATE so concurrent webhooks of one user apply in turn
+	GetSubscriptionByUserID(ctx context.Context, userID string) (*models.Subscription, error)
+	GetSubscriptionByProviderID(ctx context.Context, provider, providerSubscriptionID string) (*models.Subscription, error)
+	// SaveSubscription inserts the subscription or updates every column of an existing one
+	SaveSubscription(ctx context.Context, subscription *models.Subscription) error
+
+	WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+	WithTx(tx *gorm.DB) IBillingRepository
+}
+
+type BillingRepository struct {
+	db *gorm.DB
+}
+
+// NewBillingRepository creates a new billing repository with the given database connection.
+func NewBillingRepository(db *gorm.DB) IBillingRepository {
+	return &BillingRepository{db: db}
+}
+
+// WithTx creates a new instance of the repository with a transaction
+func (r *BillingRepository) WithTx(tx *gorm.DB) IBillingRepository {
+	return &BillingRepository{db: tx}
+}
+
+func (r *BillingRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+func (r *BillingRepository) CreateEvent(ctx context.Context, event *models.BillingEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *BillingRepository) GetSubscriptionByUserID(ctx context.Context, userID string) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("user_id = ?", userID).
+		First(&subscription).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *BillingRepository) GetSubscriptionByProviderID(ctx context.Context, provider, providerSubscriptionID string) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("provider = ? AND provider_subscription_id = ?", provider, providerSubscriptionID).
+		First(&subscription).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *BillingRepository) SaveSubscription(ctx context.Context, subscription *models.Subscription) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(subscription).Error
+}
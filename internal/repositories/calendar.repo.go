@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ICalendarRepository interface {
+	GetFeedByUserID(ctx context.Context, userID string) (*models.CalendarFeed, error)
+	GetFeedByToken(ctx context.Context, token string) (*models.CalendarFeed, error)
+	// UpsertFeed stores the user's feed token, replacing (and so revoking) any previous one
+	UpsertFeed(ctx context.Context, feed *models.CalendarFeed) error
+}
+
+type CalendarRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarRepository creates a new calendar feed repository with the given database connection.
+func NewCalendarRepository(db *gorm.DB) ICalendarRepository {
+	return &CalendarRepository{db: db}
+}
+
+// GetFeedByUserID retrieves the user's subscription feed.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *CalendarRepository) GetFeedByUserID(ctx context.Context, userID string) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&feed).Error
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// GetFeedByToken resolves a subscription URL token to its feed.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *CalendarRepository) GetFeedByToken(ctx context.Context, token string) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := r.db.WithContext(ctx).Where("token = ?", token).First(&feed).Error
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (r *CalendarRepository) UpsertFeed(ctx context.Context, feed *models.CalendarFeed) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token", "updated_at"}),
+	}).Create(feed).Error
+}
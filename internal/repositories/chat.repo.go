@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type IChatRepository interface {
+	CreateMessage(ctx context.Context, message *models.StudyGroupMessage) error
+	// ListMessages returns up to limit messages of the group with an id below
+	// before (all when before is 0), newest first
+	ListMessages(ctx context.Context, groupID, before, limit int) ([]models.ChatMessage, error)
+}
+
+type ChatRepository struct {
+	db *gorm.DB
+}
+
+// NewChatRepository creates a new chat repository with the given database connection.
+func NewChatRepository(db *gorm.DB) IChatRepository {
+	return &ChatRepository{db: db}
+}
+
+func (r *ChatRepository) CreateMessage(ctx context.Context, message *models.StudyGroupMessage) error {
+	return r.db.WithContext(ctx).Omit("Group", "User").Create(message).Error
+}
+
+func (r *ChatRepository) ListMessages(ctx context.Context, groupID, before, limit int) ([]models.ChatMessage, error) {
+	query := `SELECT m.id, m.group_id, m.user_id, u.username, m.body, m.created_at
+		FROM study_group_messages m
+		JOIN users u ON u.user_id = m.user_id
+		WHERE m.group_id = ?`
+	args := []any{groupID}
+	if before > 0 {
+		query += " AND m.id < ?"
+		args = append(args, before)
+	}
+	query += " ORDER BY m.id DESC LIMIT ?"
+	args = append(args, limit)
+
+	var messages []models.ChatMessage
+	err := r.db.WithContext(ctx).Raw(query, args...).Scan(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// IChatBroker relays chat events between API instances and tracks which
+// members are connected to a group's chat on any of them
+type IChatBroker interface {
+	// Publish delivers the event to every instance subscribed to the group
+	Publish(ctx context.Context, groupID int, event models.ChatEvent) error
+	// Subscribe streams the group's events until ctx is done, then closes the channel
+	Subscribe(ctx context.Context, groupID int) (<-chan models.ChatEvent, error)
+
+	// SetOnline marks a connection of the user online until the given time;
+	// call it again before then to stay online
+	SetOnline(ctx context.Context, groupID int, userID, connID string, until time.Time) error
+	SetOffline(ctx context.Context, groupID int, userID, connID string) error
+	// Online returns the ids of the users with a connection online at now, sorted
+	Online(ctx context.Context, groupID int, now time.Time) ([]string, error)
+}
+
+// RedisChatBroker fans events out over Redis pub/sub and keeps presence in
+// a sorted set of "<user id>:<connection id>" scored by expiry, so the
+// connections of an instance that died drop out on their own.
+type RedisChatBroker struct {
+	rdb *redis.Client
+}
+
+// NewRedisChatBroker creates a chat broker on the given Redis connection (normally global.Redis)
+func NewRedisChatBroker(rdb *redis.Client) IChatBroker {
+	return &RedisChatBroker{rdb: rdb}
+}
+
+func (b *RedisChatBroker) Publish(ctx context.Context, groupID int, event models.ChatEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return b.rdb.Publish(ctx, fmt.Sprintf(consts.REDIS_KEY_CHAT_CHANNEL, groupID), data).Err()
+}
+
+func (b *RedisChatBroker) Subscribe(ctx context.Context, groupID int) (<-chan models.ChatEvent, error) {
+	pubsub := b.rdb.Subscribe(ctx, fmt.Sprintf(consts.REDIS_KEY_CHAT_CHANNEL, groupID))
+	// Wait for the subscription so no event published after this returns is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, err
+	}
+
+	events := make(chan models.ChatEvent)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				var event models.ChatEvent
+				if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+					continue // not published by Publish
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+func (b *RedisChatBroker) SetOnline(ctx context.Context, groupID int, userID, connID string, until time.Time) error {
+	key := fmt.Sprintf(consts.REDIS_KEY_CHAT_PRESENCE, groupID)
+	pipe := b.rdb.TxPipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(until.Unix()), Member: userID + ":" + connID})
+	pipe.ExpireAt(ctx, key, until)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (b *RedisChatBroker) SetOffline(ctx context.Context, groupID int, userID, connID string) error {
+	return b.rdb.ZRem(ctx, fmt.Sprintf(consts.REDIS_KEY_CHAT_PRESENCE, groupID), userID+":"+connID).Err()
+}
+
+func (b *RedisChatBroker) Online(ctx context.Context, groupID int, now time.Time) ([]string, error) {
+	key := fmt.Sprintf(consts.REDIS_KEY_CHAT_PRESENCE, groupID)
+	pipe := b.rdb.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(now.Unix(), 10))
+	members := pipe.ZRange(ctx, key, 0, -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	users := []string{}
+	for _, member := range members.Val() {
+		userID, _, _ := strings.Cut(member, ":")
+		if !seen[userID] {
+			seen[userID] = true
+			users = append(users, userID)
+		}
+	}
+	sort.Strings(users)
+	return users, nil
+}
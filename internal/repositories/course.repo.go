@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type ICourseRepository interface {
+	GetCourseByID(ctx context.Context, id int, userID string) (*models.Course, error)
+	ListCourses(ctx context.Context, userID string) ([]models.Course, error)
+	CountCourses(ctx context.Context, userID string) (int64, error)
+	CreateCourse(ctx context.Context, course *models.Course) error
+	UpdateCourse(ctx context.Context, id int, userID string, updates map[string]any) error
+
+	WithTx(tx *gorm.DB) ICourseRepository
+}
+
+type CourseRepository struct {
+	db *gorm.DB
+}
+
+// NewCourseRepository creates a new course repository with the given database connection.
+func NewCourseRepository(db *gorm.DB) ICourseRepository {
+	return &CourseRepository{db: db}
+}
+
+// WithTx creates a new instance of the repository with a transaction
+func (r *CourseRepository) WithTx(tx *gorm.DB) ICourseRepository {
+	return &CourseRepository{db: tx}
+}
+
+// GetCourseByID retrieves a course owned by the given user.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *CourseRepository) GetCourseByID(ctx context.Context, id int, userID string) (*models.Course, error) {
+	var course models.Course
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&course).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+// ListCourses returns all courses owned by the user
+func (r *CourseRepository) ListCourses(ctx context.Context, userID string) ([]models.Course, error) {
+	var courses []models.Course
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("course_id ASC").
+		Find(&courses).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return courses, nil
+}
+
+// CountCourses returns how many courses the user has
+func (r *CourseRepository) CountCourses(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Course{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// CreateCourse inserts a new course.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *CourseRepository) CreateCourse(ctx context.Context, course *models.Course) error {
+	return r.db.WithContext(ctx).Omit("Semester", "Tags").Create(course).Error
+}
+
+// UpdateCourse updates course fields scoped to the owning user.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *CourseRepository) UpdateCourse(ctx context.Context, id int, userID string, updates map[string]any) error {
+	return r.db.WithContext(ctx).Model(&models.Course{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(updates).Error
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type IFileRepository interface {
+	CreateFile(ctx context.Context, file *models.File) error
+	GetFileByID(ctx context.Context, id int, userID string) (*models.File, error)
+	ListFiles(ctx context.Context, userID string, courseID *int) ([]models.File, error)
+	UpdateFile(ctx context.Context, id int, userID string, updates map[string]any) error
+	DeleteFile(ctx context.Context, id int, userID string) error
+
+	// UsedBytes sums the user's ready files plus pending uploads announced after pendingSince
+	UsedBytes(ctx context.Context, userID string, pendingSince time.Time) (int64, error)
+	// ListExpiredUploads returns files whose presigned upload expired before the given time, oldest first
+	ListExpiredUploads(ctx context.Context, before time.Time, limit int) ([]models.File, error)
+
+	// Extracted text
+	ReplaceChunks(ctx context.Context, fileID int, chunks []models.FileChunk) error
+	ListChunks(ctx context.Context, fileID int) ([]models.FileChunk, error)
+
+	WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+	WithTx(tx *gorm.DB) IFileRepository
+}
+
+type FileRepository struct {
+	db *gorm.DB
+}
+
+// NewFileRepository creates a new file repository with the given database connection.
+func NewFileRepository(db *gorm.DB) IFileRepository {
+	return &FileRepository{db: db}
+}
+
+// WithTx creates a new instance of the repository with a transaction
+func (r *FileRepository) WithTx(tx *gorm.DB) IFileRepository {
+	return &FileRepository{db: tx}
+}
+
+// CreateFile inserts a file row.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *FileRepository) CreateFile(ctx context.Context, file *models.File) error {
+	return r.db.WithContext(ctx).Omit("Course", "Chunks").Create(file).Error
+}
+
+// GetFileByID retrieves a file owned by the user.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *FileRepository) GetFileByID(ctx context.Context, id int, userID string) (*models.File, error) {
+	var file models.File
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&file).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// ListFiles returns the user's uploaded files, newest first
+func (r *FileRepository) ListFiles(ctx context.Context, userID string, courseID *int) ([]models.File, error) {
+	query := r.db.WithContext(ctx).
+		Where("user_id = ? AND status = ?", userID, consts.FileStatus.READY)
+
+	if courseID != nil {
+		query = query.Where("course_id = ?", *courseID)
+	}
+
+	var files []models.File
+	err := query.Order("created_at DESC, id DESC").Find(&files).Error
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// UpdateFile updates file fields scoped to the owning user.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *FileRepository) UpdateFile(ctx context.Context, id int, userID string, updates map[string]any) error {
+	// Remove fields that shouldn't be updated directly
+	delete(updates, "id")
+	delete(updates, "user_id")
+	delete(updates, "storage_key")
+	delete(updates, "created_at")
+
+	return r.db.WithContext(ctx).Model(&models.File{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(updates).Error
+}
+
+// DeleteFile removes a file row. The stored object is deleted by the service.
+// Returns gorm.ErrRecordNotFound when the file does not belong to the user
+func (r *FileRepository) DeleteFile(ctx context.Context, id int, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&models.File{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *FileRepository) UsedBytes(ctx context.Context, userID string, pendingSince time.Time) (int64, error) {
+	var used int64
+	err := r.db.WithContext(ctx).Model(&models.File{}).
+		Select("COALESCE(SUM(size), 0)").
+		Where("user_id = ?", userID).
+		Where("status = ? OR (status = ? AND created_at >= ?)", consts.FileStatus.READY, consts.FileStatus.PENDING, pendingSince).
+		Scan(&used).Error
+
+	return used, err
+}
+
+func (r *FileRepository) ListExpiredUploads(ctx context.Context, before time.Time, limit int) ([]models.File, error) {
+	var files []models.File
+	err := r.db.WithContext(ctx).
+		Where("upload_expires_at < ?", before).
+		Order("upload_expires_at ASC, id ASC").
+		Limit(limit).
+		Find(&files).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// ReplaceChunks deletes the file's chunks and inserts the given ones.
+// Call inside WithTransaction so a failed insert does not lose the old text.
+func (r *FileRepository) ReplaceChunks(ctx context.Context, fileID int, chunks []models.FileChunk) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Where("file_id = ?", fileID).Delete(&models.FileChunk{}).Error; err != nil {
+		return err
+	}
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	for i := range chunks {
+		chunks[i].FileID = fileID
+	}
+	return db.Omit("Course").CreateInBatches(&chunks, 100).Error
+}
+
+// ListChunks returns the file's chunks in reading order
+func (r *FileRepository) ListChunks(ctx context.Context, fileID int) ([]models.FileChunk, error) {
+	var chunks []models.FileChunk
+	err := r.db.WithContext(ctx).
+		Where("file_id = ?", fileID).
+		Order("chunk_index ASC").
+		Find(&chunks).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// WithTransaction executes a function within a database transaction
+func (r *FileRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(tx)
+	})
+}
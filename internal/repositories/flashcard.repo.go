@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type IFlashcardRepository interface {
+	CreateFlashcard(ctx context.Context, card *models.Flashcard) error
+	CreateFlashcards(ctx context.Context, cards []models.Flashcard) error
+	GetFlashcardByID(ctx context.Context, id int, userID string) (*models.Flashcard, error)
+	ListFlashcards(ctx context.Context, filter models.FlashcardFilter) ([]models.Flashcard, error)
+	// ListDueFlashcards returns cards due on or before filter.Today, most overdue first
+	ListDueFlashcards(ctx context.Context, filter models.DueFlashcardFilter) ([]models.Flashcard, error)
+	CountDueFlashcards(ctx context.Context, filter models.DueFlashcardFilter) (int64, error)
+	UpdateFlashcard(ctx context.Context, id int, userID string, updates map[string]any) error
+	DeleteFlashcard(ctx context.Context, id int, userID string) error
+	CreateReview(ctx context.Context, review *models.FlashcardReview) error
+
+	CreateGeneration(ctx context.Context, generation *models.FlashcardGeneration) error
+	GetGenerationByID(ctx context.Context, id int, userID string) (*models.FlashcardGeneration, error)
+	UpdateGeneration(ctx context.Context, id int, updates map[string]any) error
+
+	WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+	WithTx(tx *gorm.DB) IFlashcardRepository
+}
+
+type FlashcardRepository struct {
+	db *gorm.DB
+}
+
+// NewFlashcardRepository creates a new flashcard repository with the given database connection.
+func NewFlashcardRepository(db *gorm.DB) IFlashcardRepository {
+	return &FlashcardRepository{db: db}
+}
+
+// WithTx creates a new instance of the repository with a transaction
+func (r *FlashcardRepository) WithTx(tx *gorm.DB) IFlashcardRepository {
+	return &FlashcardRepository{db: tx}
+}
+
+// WithTransaction runs fn inside a database transaction
+func (r *FlashcardRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+// CreateFlashcard inserts a single card.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *FlashcardRepository) CreateFlashcard(ctx context.Context, card *models.Flashcard) error {
+	return r.db.WithContext(ctx).Create(card).Error
+}
+
+// CreateFlashcards inserts generated cards in one statement
+func (r *FlashcardRepository) CreateFlashcards(ctx context.Context, cards []models.Flashcard) error {
+	if len(cards) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&cards).Error
+}
+
+func (r *FlashcardRepository) GetFlashcardByID(ctx context.Context, id int, userID string) (*models.Flashcard, error) {
+	var card models.Flashcard
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&card).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// ListFlashcards returns the user's cards, newest first
+func (r *FlashcardRepository) ListFlashcards(ctx context.Context, filter models.FlashcardFilter) ([]models.Flashcard, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", filter.UserID)
+	if filter.CourseID != nil {
+		query = query.Where("course_id = ?", *filter.CourseID)
+	}
+	if filter.NoteID != nil {
+		query = query.Where("note_id = ?", *filter.NoteID)
+	}
+	if filter.FileID != nil {
+		query = query.Where("file_id = ?", *filter.FileID)
+	}
+	if filter.GenerationID != nil {
+		query = query.Where("generation_id = ?", *filter.GenerationID)
+	}
+
+	var cards []models.Flashcard
+	if err := query.Order("created_at DESC, id DESC").Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+func (r *FlashcardRepository) dueQuery(ctx context.Context, filter models.DueFlashcardFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).
+		Model(&models.Flashcard{}).
+		Where("user_id = ? AND due_date <= ?", filter.UserID, filter.Today.Format(consts.DATE_LAYOUT))
+	if filter.CourseID != nil {
+		query = query.Where("course_id = ?", *filter.CourseID)
+	}
+	return query
+}
+
+func (r *FlashcardRepository) ListDueFlashcards(ctx context.Context, filter models.DueFlashcardFilter) ([]models.Flashcard, error) {
+	var cards []models.Flashcard
+	err := r.dueQuery(ctx, filter).
+		Order("due_date ASC, id ASC").
+		Limit(filter.Limit).
+		Find(&cards).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+func (r *FlashcardRepository) CountDueFlashcards(ctx context.Context, filter models.DueFlashcardFilter) (int64, error) {
+	var count int64
+	if err := r.dueQuery(ctx, filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// UpdateFlashcard applies the given column updates.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *FlashcardRepository) UpdateFlashcard(ctx context.Context, id int, userID string, updates map[string]any) error {
+	// Remove fields that shouldn't be updated directly
+	delete(updates, "id")
+	delete(updates, "user_id")
+	delete(updates, "created_at")
+
+	return r.db.WithContext(ctx).Model(&models.Flashcard{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(updates).Error
+}
+
+// DeleteFlashcard removes a card and its review history.
+// Returns gorm.ErrRecordNotFound when the card does not belong to the user
+func (r *FlashcardRepository) DeleteFlashcard(ctx context.Context, id int, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&models.Flashcard{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *FlashcardRepository) CreateReview(ctx context.Context, review *models.FlashcardReview) error {
+	return r.db.WithContext(ctx).Create(review).Error
+}
+
+func (r *FlashcardRepository) CreateGeneration(ctx context.Context, generation *models.FlashcardGeneration) error {
+	return r.db.WithContext(ctx).Create(generation).Error
+}
+
+func (r *FlashcardRepository) GetGenerationByID(ctx context.Context, id int, userID string) (*models.FlashcardGeneration, error) {
+	var generation models.FlashcardGeneration
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&generation).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &generation, nil
+}
+
+// UpdateGeneration applies the given column updates.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *FlashcardRepository) UpdateGeneration(ctx context.Context, id int, updates map[string]any) error {
+	// Remove fields that shouldn't be updated directly
+	delete(updates, "id")
+	delete(updates, "user_id")
+	delete(updates, "created_at")
+
+	return r.db.WithContext(ctx).Model(&models.FlashcardGeneration{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
@@ -22,7 +22,7 @@ func NewMailRepository(db *gorm.DB) IMailRepository {
 
 func (r *MailRepository) GetMailTemplate(ctx context.Context, id int) (*models.Mail, error) {
 	var mailTemplate models.Mail
-	err := r.db.WithContext(ctx).Select("id, subject, header, body, footer").First(&mailTemplate).Error
+	err := r.db.WithContext(ctx).Select("id, subject, header, body, footer").Where("id = ?", id).First(&mailTemplate).Error
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,203 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type INoteRepository interface {
+	CreateNote(ctx context.Context, note *models.Note) error
+	GetNoteByID(ctx context.Context, id int, userID string) (*models.Note, error)
+	// LockNote reads the note and locks its row until the transaction ends
+	LockNote(ctx context.Context, id int, userID string) (*models.Note, error)
+	ListNotes(ctx context.Context, filter models.NoteFilter) ([]models.Note, error)
+	UpdateNote(ctx context.Context, id int, userID string, updates map[string]any) error
+	DeleteNote(ctx context.Context, id int, userID string) error
+	ReplaceTags(ctx context.Context, note *models.Note, tags []models.Tag) error
+
+	// Revisions
+	CreateRevision(ctx context.Context, revision *models.NoteRevision) error
+	ListRevisions(ctx context.Context, noteID int) ([]models.NoteRevision, error)
+	GetRevision(ctx context.Context, noteID, version int) (*models.NoteRevision, error)
+	// PruneRevisions keeps only the newest keep revisions of a note
+	PruneRevisions(ctx context.Context, noteID, keep int) error
+
+	WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+	WithTx(tx *gorm.DB) INoteRepository
+}
+
+type NoteRepository struct {
+	db *gorm.DB
+}
+
+// NewNoteRepository creates a new note repository with the given database connection.
+func NewNoteRepository(db *gorm.DB) INoteRepository {
+	return &NoteRepository{db: db}
+}
+
+// WithTx creates a new instance of the repository with a transaction
+func (r *NoteRepository) WithTx(tx *gorm.DB) INoteRepository {
+	return &NoteRepository{db: tx}
+}
+
+// CreateNote inserts a note together with its tag links.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *NoteRepository) CreateNote(ctx context.Context, note *models.Note) error {
+	return r.db.WithContext(ctx).Omit("Course", "Revisions", "Tags.*").Create(note).Error
+}
+
+// GetNoteByID retrieves a note owned by the user, with its course and tags.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *NoteRepository) GetNoteByID(ctx context.Context, id int, userID string) (*models.Note, error) {
+	var note models.Note
+	err := r.db.WithContext(ctx).
+		Preload("Course").
+		Preload("Tags").
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&note).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+func (r *NoteRepository) LockNote(ctx context.Context, id int, userID string) (*models.Note, error) {
+	var note models.Note
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&note).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// ListNotes returns the user's notes, newest lecture first
+func (r *NoteRepository) ListNotes(ctx context.Context, filter models.NoteFilter) ([]models.Note, error) {
+	query := r.db.WithContext(ctx).
+		Preload("Tags").
+		Where("notes.user_id = ?", filter.UserID)
+
+	if filter.CourseID != nil {
+		query = query.Where("notes.course_id = ?", *filter.CourseID)
+	}
+	if filter.Tag != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM note_tags JOIN tags ON tags.id = note_tags.tag_id WHERE note_tags.note_id = notes.id AND tags.name = ?)", filter.Tag)
+	}
+	if filter.From != nil {
+		query = query.Where("notes.lecture_date >= ?", filter.From.Format(consts.DATE_LAYOUT))
+	}
+	if filter.To != nil {
+		query = query.Where("notes.lecture_date <= ?", filter.To.Format(consts.DATE_LAYOUT))
+	}
+
+	var notes []models.Note
+	err := query.Order("notes.lecture_date DESC, notes.id DESC").Find(&notes).Error
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// UpdateNote updates note fields scoped to the owning user.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *NoteRepository) UpdateNote(ctx context.Context, id int, userID string, updates map[string]any) error {
+	// Remove fields that shouldn't be updated directly
+	delete(updates, "id")
+	delete(updates, "user_id")
+	delete(updates, "created_at")
+
+	return r.db.WithContext(ctx).Model(&models.Note{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(updates).Error
+}
+
+// DeleteNote removes a note and, through the FKs, its revisions and tag links.
+// Returns gorm.ErrRecordNotFound when the note does not belong to the user
+func (r *NoteRepository) DeleteNote(ctx context.Context, id int, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&models.Note{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ReplaceTags sets the note's tags to exactly the given (already persisted) tags
+func (r *NoteRepository) ReplaceTags(ctx context.Context, note *models.Note, tags []models.Tag) error {
+	return r.db.WithContext(ctx).Model(note).Omit("Tags.*").Association("Tags").Replace(tags)
+}
+
+// CreateRevision inserts a revision snapshot.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *NoteRepository) CreateRevision(ctx context.Context, revision *models.NoteRevision) error {
+	return r.db.WithContext(ctx).Create(revision).Error
+}
+
+// ListRevisions returns revision metadata, newest first, without the content
+func (r *NoteRepository) ListRevisions(ctx context.Context, noteID int) ([]models.NoteRevision, error) {
+	var revisions []models.NoteRevision
+	err := r.db.WithContext(ctx).
+		Select("id, note_id, version, title, restored_from, created_at, updated_at").
+		Where("note_id = ?", noteID).
+		Order("version DESC").
+		Find(&revisions).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// GetRevision retrieves one revision with its content.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *NoteRepository) GetRevision(ctx context.Context, noteID, version int) (*models.NoteRevision, error) {
+	var revision models.NoteRevision
+	err := r.db.WithContext(ctx).
+		Where("note_id = ? AND version = ?", noteID, version).
+		First(&revision).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *NoteRepository) PruneRevisions(ctx context.Context, noteID, keep int) error {
+	var cutoff models.NoteRevision
+	err := r.db.WithContext(ctx).
+		Select("version").
+		Where("note_id = ?", noteID).
+		Order("version DESC").
+		Offset(keep).
+		First(&cutoff).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).
+		Where("note_id = ? AND version <= ?", noteID, cutoff.Version).
+		Delete(&models.NoteRevision{}).Error
+}
+
+// WithTransaction executes a function within a database transaction
+func (r *NoteRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(tx)
+	})
+}
@@ -0,0 +1,269 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// INoteCollabStore holds the collaborative editing sessions of notes where
+// every API instance sees them. A session is a snapshot of the document plus
+// the operations applied since; revisions count operations.
+type INoteCollabStore interface {
+	// Open starts a session from the note's normalized content unless one
+	// holding the same content is running, and returns the running session.
+	// A session whose snapshot no longer matches the note (it was edited
+	// without the session) is replaced once nobody else is connected to it;
+	// the replacement starts at a revision the old session never reached.
+	Open(ctx context.Context, noteID int, doc []byte, now time.Time) (*models.NoteCollabState, error)
+	// State returns the running session, or nil when there is none
+	State(ctx context.Context, noteID int) (*models.NoteCollabState, error)
+	// Ops returns the running session together with the operations after its
+	// snapshot, oldest first; the state is nil when there is no session
+	Ops(ctx context.Context, noteID int) (*models.NoteCollabState, []models.NoteCollabEvent, error)
+	// Append records the operation if event.Rev is the next revision, and
+	// publishes it. It returns false when another operation got there first.
+	Append(ctx context.Context, noteID int, event models.NoteCollabEvent) (bool, error)
+	// Snapshot replaces the session's snapshot taken at base with a newer one,
+	// dropping the operations it covers. It returns false when base is no
+	// longer the latest snapshot.
+	Snapshot(ctx context.Context, noteID, base int, state models.NoteCollabState) (bool, error)
+	// Close ends the session when no connection is open and the snapshot is
+	// up to date, and reports whether it did
+	Close(ctx context.Context, noteID int, now time.Time) (bool, error)
+
+	// Publish delivers an event to every instance subscribed to the note
+	Publish(ctx context.Context, noteID int, event models.NoteCollabEvent) error
+	// Subscribe streams the note's events until ctx is done, then closes the channel
+	Subscribe(ctx context.Context, noteID int) (<-chan models.NoteCollabEvent, error)
+
+	// SetOnline marks a connection open until the given time
+	SetOnline(ctx context.Context, noteID int, connID string, until time.Time) error
+	SetOffline(ctx context.Context, noteID int, connID string) error
+	// Active tells whether any connection is open at now
+	Active(ctx context.Context, noteID int, now time.Time) (bool, error)
+}
+
+// The scripts keep each check and its write atomic across instances
+var (
+	// KEYS: state, ops, connections; ARGV: doc, now, ttl seconds
+	noteCollabOpenScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	if redis.call('HGET', KEYS[1], 'doc') == ARGV[1] then
+		return 0
+	end
+	redis.call('ZREMRANGEBYSCORE', KEYS[3], '-inf', '(' .. ARGV[2])
+	if redis.call('ZCARD', KEYS[3]) > 1 then
+		return 0
+	end
+end
+local rev = tonumber(redis.call('HGET', KEYS[1], 'rev') or '-1') + 1
+redis.call('DEL', KEYS[2])
+redis.call('HSET', KEYS[1], 'base', rev, 'rev', rev, 'doc', ARGV[1])
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+return 1`)
+
+	// KEYS: state, ops, channel; ARGV: rev, event, ttl seconds
+	noteCollabAppendScript = redis.NewScript(`
+local rev = redis.call('HGET', KEYS[1], 'rev')
+if not rev or tonumber(rev) + 1 ~= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('RPUSH', KEYS[2], ARGV[2])
+redis.call('HSET', KEYS[1], 'rev', ARGV[1])
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+redis.call('EXPIRE', KEYS[2], ARGV[3])
+redis.call('PUBLISH', KEYS[3], ARGV[2])
+return 1`)
+
+	// KEYS: state, ops; ARGV: base, new base, doc
+	noteCollabSnapshotScript = redis.NewScript(`
+local base = redis.call('HGET', KEYS[1], 'base')
+if not base or tonumber(base) ~= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('LTRIM', KEYS[2], tonumber(ARGV[2]) - tonumber(ARGV[1]), -1)
+redis.call('HSET', KEYS[1], 'base', ARGV[2], 'doc', ARGV[3])
+return 1`)
+
+	// KEYS: state, ops, connections; ARGV: now
+	noteCollabCloseScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[3], '-inf', '(' .. ARGV[1])
+if redis.call('ZCARD', KEYS[3]) > 0 then
+	return 0
+end
+if redis.call('HGET', KEYS[1], 'base') ~= redis.call('HGET', KEYS[1], 'rev') then
+	return 0
+end
+redis.call('DEL', KEYS[1], KEYS[2], KEYS[3])
+return 1`)
+)
+
+type RedisNoteCollabStore struct {
+	rdb *redis.Client
+}
+
+// NewRedisNoteCollabStore creates a collaborative editing store on the given Redis connection (normally global.Redis)
+func NewRedisNoteCollabStore(rdb *redis.Client) INoteCollabStore {
+	return &RedisNoteCollabStore{rdb: rdb}
+}
+
+func (s *RedisNoteCollabStore) Open(ctx context.Context, noteID int, doc []byte, now time.Time) (*models.NoteCollabState, error) {
+	keys := []string{
+		fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_STATE, noteID),
+		fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_OPS, noteID),
+		fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_CONNECTIONS, noteID),
+	}
+	ttl := int(consts.NOTE_COLLAB_SESSION_TTL.Seconds())
+	if err := noteCollabOpenScript.Run(ctx, s.rdb, keys, doc, now.Unix(), ttl).Err(); err != nil {
+		return nil, err
+	}
+	return s.State(ctx, noteID)
+}
+
+func (s *RedisNoteCollabStore) State(ctx context.Context, noteID int) (*models.NoteCollabState, error) {
+	fields, err := s.rdb.HGetAll(ctx, fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_STATE, noteID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollabState(noteID, fields)
+}
+
+func (s *RedisNoteCollabStore) Ops(ctx context.Context, noteID int) (*models.NoteCollabState, []models.NoteCollabEvent, error) {
+	pipe := s.rdb.TxPipeline()
+	fields := pipe.HGetAll(ctx, fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_STATE, noteID))
+	entries := pipe.LRange(ctx, fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_OPS, noteID), 0, -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	state, err := decodeCollabState(noteID, fields.Val())
+	if err != nil || state == nil {
+		return nil, nil, err
+	}
+	events := make([]models.NoteCollabEvent, 0, len(entries.Val()))
+	for _, entry := range entries.Val() {
+		var event models.NoteCollabEvent
+		if err := json.Unmarshal([]byte(entry), &event); err != nil {
+			return nil, nil, fmt.Errorf("decode collab operation of note %d: %w", noteID, err)
+		}
+		events = append(events, event)
+	}
+	return state, events, nil
+}
+
+func (s *RedisNoteCollabStore) Append(ctx context.Context, noteID int, event models.NoteCollabEvent) (bool, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return false, err
+	}
+	keys := []string{
+		fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_STATE, noteID),
+		fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_OPS, noteID),
+		fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_CHANNEL, noteID),
+	}
+	ttl := int(consts.NOTE_COLLAB_SESSION_TTL.Seconds())
+	return noteCollabAppendScript.Run(ctx, s.rdb, keys, event.Rev, data, ttl).Bool()
+}
+
+func (s *RedisNoteCollabStore) Snapshot(ctx context.Context, noteID, base int, state models.NoteCollabState) (bool, error) {
+	keys := []string{
+		fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_STATE, noteID),
+		fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_OPS, noteID),
+	}
+	return noteCollabSnapshotScript.Run(ctx, s.rdb, keys, base, state.Base, []byte(state.Doc)).Bool()
+}
+
+func (s *RedisNoteCollabStore) Close(ctx context.Context, noteID int, now time.Time) (bool, error) {
+	keys := []string{
+		fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_STATE, noteID),
+		fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_OPS, noteID),
+		fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_CONNECTIONS, noteID),
+	}
+	return noteCollabCloseScript.Run(ctx, s.rdb, keys, now.Unix()).Bool()
+}
+
+func (s *RedisNoteCollabStore) Publish(ctx context.Context, noteID int, event models.NoteCollabEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return s.rdb.Publish(ctx, fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_CHANNEL, noteID), data).Err()
+}
+
+func (s *RedisNoteCollabStore) Subscribe(ctx context.Context, noteID int) (<-chan models.NoteCollabEvent, error) {
+	pubsub := s.rdb.Subscribe(ctx, fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_CHANNEL, noteID))
+	// Wait for the subscription so no event published after this returns is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, err
+	}
+
+	events := make(chan models.NoteCollabEvent)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				var event models.NoteCollabEvent
+				if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+					continue // not published by Append or Publish
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+func (s *RedisNoteCollabStore) SetOnline(ctx context.Context, noteID int, connID string, until time.Time) error {
+	key := fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_CONNECTIONS, noteID)
+	pipe := s.rdb.TxPipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(until.Unix()), Member: connID})
+	pipe.ExpireAt(ctx, key, until)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *RedisNoteCollabStore) SetOffline(ctx context.Context, noteID int, connID string) error {
+	return s.rdb.ZRem(ctx, fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_CONNECTIONS, noteID), connID).Err()
+}
+
+func (s *RedisNoteCollabStore) Active(ctx context.Context, noteID int, now time.Time) (bool, error) {
+	count, err := s.rdb.ZCount(ctx, fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_CONNECTIONS, noteID), strconv.FormatInt(now.Unix(), 10), "+inf").Result()
+	return count > 0, err
+}
+
+func decodeCollabState(noteID int, fields map[string]string) (*models.NoteCollabState, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	var err error
+	state := &models.NoteCollabState{Doc: json.RawMessage(fields["doc"])}
+	if state.Base, err = strconv.Atoi(fields["base"]); err != nil {
+		return nil, fmt.Errorf("decode collab base of note %d: %w", noteID, err)
+	}
+	if state.Rev, err = strconv.Atoi(fields["rev"]); err != nil {
+		return nil, fmt.Errorf("decode collab rev of note %d: %w", noteID, err)
+	}
+	return state, nil
+}
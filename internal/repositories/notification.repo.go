@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type INotificationRepository interface {
+	// Settings
+	GetSetting(ctx context.Context, userID string) (*models.NotificationSetting, error)
+	GetSettings(ctx context.Context, userIDs []string) ([]models.NotificationSetting, error)
+	UpsertSetting(ctx context.Context, setting *models.NotificationSetting) error
+
+	// Scheduled notifications
+	EnqueueNotifications(ctx context.Context, notifications []models.ReminderNotification) error
+	ClaimDueNotifications(ctx context.Context, now, staleBefore time.Time, limit int) ([]models.ReminderNotification, error)
+	MarkNotificationSent(ctx context.Context, id int, sentAt time.Time) error
+	MarkNotificationSkipped(ctx context.Context, id int, reason string) error
+	MarkNotificationFailed(ctx context.Context, id int, reason string, retryAt *time.Time) error
+	ListNotifications(ctx context.Context, userID string, limit int) ([]models.ReminderNotification, error)
+
+	// DeleteByReminder drops a reminder's pending and failed notifications so they are rebuilt after its deadline moves.
+	// Sent ones are kept, which stops a deadline moved back from mailing the same offset twice.
+	DeleteByReminder(ctx context.Context, reminderID int) error
+	// DeleteUnsentByUser drops a user's pending and failed notifications so they are rebuilt with new offsets
+	DeleteUnsentByUser(ctx context.Context, userID string) error
+
+	WithTx(tx *gorm.DB) INotificationRepository
+}
+
+// unsentNotificationStatuses are the states a notification can still be sent from, by the
+// worker or a retry, and that are dropped when the notification has to be rebuilt
+var unsentNotificationStatuses = []int8{consts.NotificationStatus.PENDING, consts.NotificationStatus.FAILED}
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new notification repository with the given database connection.
+func NewNotificationRepository(db *gorm.DB) INotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// WithTx creates a new instance of the repository with a transaction
+func (r *NotificationRepository) WithTx(tx *gorm.DB) INotificationRepository {
+	return &NotificationRepository{db: tx}
+}
+
+// GetSetting retrieves the user's notification preferences.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *NotificationRepository) GetSetting(ctx context.Context, userID string) (*models.NotificationSetting, error) {
+	var setting models.NotificationSetting
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&setting).Error
+	if err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// GetSettings retrieves the preferences of several users; users without a row are omitted
+func (r *NotificationRepository) GetSettings(ctx context.Context, userIDs []string) ([]models.NotificationSetting, error) {
+	var settings []models.NotificationSetting
+	if len(userIDs) == 0 {
+		return settings, nil
+	}
+
+	err := r.db.WithContext(ctx).Where("user_id IN ?", userIDs).Find(&settings).Error
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpsertSetting creates or replaces the user's notification preferences
+func (r *NotificationRepository) UpsertSetting(ctx context.Context, setting *models.NotificationSetting) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"offsets", "email_enabled", "updated_at"}),
+	}).Create(setting).Error
+}
+
+// EnqueueNotifications inserts notifications, silently ignoring ones that already exist
+// for the same reminder, offset and deadline so repeated scans never duplicate a send.
+func (r *NotificationRepository) EnqueueNotifications(ctx context.Context, notifications []models.ReminderNotification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&notifications).Error
+}
+
+// ClaimDueNotifications locks up to limit due notifications and marks them as sending.
+// Notifications left in sending since before staleBefore are reclaimed, which recovers
+// work from a worker that crashed mid-send. SKIP LOCKED lets several workers claim in parallel.
+func (r *NotificationRepository) ClaimDueNotifications(ctx context.Context, now, staleBefore time.Time, limit int) ([]models.ReminderNotification, error) {
+	var notifications []models.ReminderNotification
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+			Where("(status = ? AND scheduled_at <= ?) OR (status = ? AND claimed_at < ?)",
+				consts.NotificationStatus.PENDING, now,
+				consts.NotificationStatus.SENDING, staleBefore).
+			Order("scheduled_at ASC").
+			Limit(limit).
+			Find(&notifications).Error
+		if err != nil || len(notifications) == 0 {
+			return err
+		}
+
+		ids := make([]int, 0, len(notifications))
+		for i := range notifications {
+			ids = append(ids, notifications[i].ID)
+			notifications[i].Status = consts.NotificationStatus.SENDING
+			notifications[i].Attempts++
+		}
+
+		return tx.Model(&models.ReminderNotification{}).
+			Where("id IN ?", ids).
+			Updates(map[string]any{
+				"status":     consts.NotificationStatus.SENDING,
+				"claimed_at": now,
+				"attempts":   gorm.Expr("attempts + 1"),
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *NotificationRepository) MarkNotificationSent(ctx context.Context, id int, sentAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.ReminderNotification{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":     consts.NotificationStatus.SENT,
+			"sent_at":    sentAt,
+			"last_error": sql.NullString{},
+		}).Error
+}
+
+func (r *NotificationRepository) MarkNotificationSkipped(ctx context.Context, id int, reason string) error {
+	return r.db.WithContext(ctx).Model(&models.ReminderNotification{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":     consts.NotificationStatus.SKIPPED,
+			"last_error": sql.NullString{String: reason, Valid: reason != ""},
+		}).Error
+}
+
+// MarkNotificationFailed records a failed send. A non-nil retryAt puts the notification
+// back in the queue at that time; otherwise it is marked permanently failed.
+func (r *NotificationRepository) MarkNotificationFailed(ctx context.Context, id int, reason string, retryAt *time.Time) error {
+	updates := map[string]any{
+		"status":     consts.NotificationStatus.FAILED,
+		"last_error": sql.NullString{String: reason, Valid: true},
+	}
+	if retryAt != nil {
+		updates["status"] = consts.NotificationStatus.PENDING
+		updates["scheduled_at"] = *retryAt
+	}
+
+	return r.db.WithContext(ctx).Model(&models.ReminderNotification{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// ListNotifications returns the user's most recent notifications
+func (r *NotificationRepository) ListNotifications(ctx context.Context, userID string, limit int) ([]models.ReminderNotification, error) {
+	var notifications []models.ReminderNotification
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("scheduled_at DESC").
+		Limit(limit).
+		Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *NotificationRepository) DeleteByReminder(ctx context.Context, reminderID int) error {
+	return r.db.WithContext(ctx).
+		Where("reminder_id = ? AND status IN ?", reminderID, unsentNotificationStatuses).
+		Delete(&models.ReminderNotification{}).Error
+}
+
+func (r *NotificationRepository) DeleteUnsentByUser(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND status IN ?", userID, unsentNotificationStatuses).
+		Delete(&models.ReminderNotification{}).Error
+}
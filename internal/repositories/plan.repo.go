@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type IPlanRepository interface {
+	GetPlan(ctx context.Context, code string) (*models.Plan, error)
+	// ListPlans returns every plan, cheapest first
+	ListPlans(ctx context.Context) ([]models.Plan, error)
+	GetSubscriptionByUserID(ctx context.Context, userID string) (*models.Subscription, error)
+}
+
+type PlanRepository struct {
+	db *gorm.DB
+}
+
+// NewPlanRepository creates a new plan repository with the given database connection.
+func NewPlanRepository(db *gorm.DB) IPlanRepository {
+	return &PlanRepository{db: db}
+}
+
+func (r *PlanRepository) GetPlan(ctx context.Context, code string) (*models.Plan, error) {
+	var plan models.Plan
+	err := r.db.WithContext(ctx).
+		Where("code = ?", code).
+		First(&plan).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *PlanRepository) ListPlans(ctx context.Context) ([]models.Plan, error) {
+	var plans []models.Plan
+	err := r.db.WithContext(ctx).
+		Order("price_cents ASC, code ASC").
+		Find(&plans).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+func (r *PlanRepository) GetSubscriptionByUserID(ctx context.Context, userID string) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(&subscription).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
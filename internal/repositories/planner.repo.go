@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IPlannerRepository interface {
+	GetPreference(ctx context.Context, userID string) (*models.StudyPreference, error)
+	UpsertPreference(ctx context.Context, preference *models.StudyPreference) error
+
+	// CreatePlan inserts the plan together with its blocks
+	CreatePlan(ctx context.Context, plan *models.StudyPlan) error
+	// GetPlanByID loads the plan with its blocks in chronological order
+	GetPlanByID(ctx context.Context, id int, userID string) (*models.StudyPlan, error)
+	ListPlans(ctx context.Context, userID string) ([]models.StudyPlan, error)
+	UpdatePlan(ctx context.Context, id int, userID string, updates map[string]any) error
+	// SupersedePlans marks the user's plans in the given statuses, other than exceptID, as superseded
+	SupersedePlans(ctx context.Context, userID string, statuses []int8, exceptID int) error
+
+	GetBlockByID(ctx context.Context, id, planID int, userID string) (*models.StudyBlock, error)
+	UpdateBlock(ctx context.Context, id int, userID string, updates map[string]any) error
+	DeleteBlock(ctx context.Context, id, planID int, userID string) error
+
+	WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+	WithTx(tx *gorm.DB) IPlannerRepository
+}
+
+type PlannerRepository struct {
+	db *gorm.DB
+}
+
+// NewPlannerRepository creates a new planner repository with the given database connection.
+func NewPlannerRepository(db *gorm.DB) IPlannerRepository {
+	return &PlannerRepository{db: db}
+}
+
+// WithTx creates a new instance of the repository with a transaction
+func (r *PlannerRepository) WithTx(tx *gorm.DB) IPlannerRepository {
+	return &PlannerRepository{db: tx}
+}
+
+func (r *PlannerRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+func (r *PlannerRepository) GetPreference(ctx context.Context, userID string) (*models.StudyPreference, error) {
+	var preference models.StudyPreference
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(&preference).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+func (r *PlannerRepository) UpsertPreference(ctx context.Context, preference *models.StudyPreference) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"windows", "block_minutes", "break_minutes", "max_daily_minutes", "updated_at"}),
+	}).Create(preference).Error
+}
+
+func (r *PlannerRepository) CreatePlan(ctx context.Context, plan *models.StudyPlan) error {
+	return r.db.WithContext(ctx).Omit("Blocks.Course", "Blocks.Reminder").Create(plan).Error
+}
+
+func (r *PlannerRepository) GetPlanByID(ctx context.Context, id int, userID string) (*models.StudyPlan, error) {
+	var plan models.StudyPlan
+	err := r.db.WithContext(ctx).
+		Preload("Blocks", func(db *gorm.DB) *gorm.DB { return db.Order("start_at ASC") }).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&plan).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// ListPlans returns the user's plans without blocks, newest first
+func (r *PlannerRepository) ListPlans(ctx context.Context, userID string) ([]models.StudyPlan, error) {
+	var plans []models.StudyPlan
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Find(&plans).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+func (r *PlannerRepository) UpdatePlan(ctx context.Context, id int, userID string, updates map[string]any) error {
+	return r.db.WithContext(ctx).Model(&models.StudyPlan{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(updates).Error
+}
+
+func (r *PlannerRepository) SupersedePlans(ctx context.Context, userID string, statuses []int8, exceptID int) error {
+	return r.db.WithContext(ctx).Model(&models.StudyPlan{}).
+		Where("user_id = ? AND status IN ? AND id <> ?", userID, statuses, exceptID).
+		Update("status", consts.StudyPlanStatus.SUPERSEDED).Error
+}
+
+func (r *PlannerRepository) GetBlockByID(ctx context.Context, id, planID int, userID string) (*models.StudyBlock, error) {
+	var block models.StudyBlock
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND plan_id = ? AND user_id = ?", id, planID, userID).
+		First(&block).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+func (r *PlannerRepository) UpdateBlock(ctx context.Context, id int, userID string, updates map[string]any) error {
+	return r.db.WithContext(ctx).Model(&models.StudyBlock{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(updates).Error
+}
+
+// DeleteBlock removes a block of the plan.
+// Returns gorm.ErrRecordNotFound when the block does not belong to the user's plan
+func (r *PlannerRepository) DeleteBlock(ctx context.Context, id, planID int, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND plan_id = ? AND user_id = ?", id, planID, userID).
+		Delete(&models.StudyBlock{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
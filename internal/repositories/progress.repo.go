@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// IProgressStore keeps the progress events of background jobs so clients
+// can follow a job and resume where they left off
+type IProgressStore interface {
+	// Publish appends an event to the topic
+	Publish(ctx context.Context, topic string, event models.JobProgress) error
+	// Read returns the events after afterID, oldest first, waiting up to
+	// block for one to arrive. An empty afterID reads from the start.
+	Read(ctx context.Context, topic, afterID string, block time.Duration) ([]models.JobProgress, error)
+	// Clear drops the topic's events, e.g. those of an earlier run of the job
+	Clear(ctx context.Context, topic string) error
+}
+
+// progressReadCount is how many events one read returns at most
+const progressReadCount = 100
+
+// RedisProgressStore keeps each topic in a capped Redis stream; the stream
+// entry IDs double as event IDs.
+type RedisProgressStore struct {
+	rdb *redis.Client
+}
+
+// NewRedisProgressStore creates a progress store on the given Redis connection (normally global.Redis)
+func NewRedisProgressStore(rdb *redis.Client) IProgressStore {
+	return &RedisProgressStore{rdb: rdb}
+}
+
+func (s *RedisProgressStore) Publish(ctx context.Context, topic string, event models.JobProgress) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf(consts.REDIS_KEY_JOB_PROGRESS, topic)
+	pipe := s.rdb.TxPipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: consts.JOB_PROGRESS_MAX_EVENTS,
+		Approx: true,
+		Values: map[string]any{"data": data},
+	})
+	pipe.Expire(ctx, key, consts.JOB_PROGRESS_TTL)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (s *RedisProgressStore) Read(ctx context.Context, topic, afterID string, block time.Duration) ([]models.JobProgress, error) {
+	if afterID == "" {
+		afterID = "0"
+	}
+	streams, err := s.rdb.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{fmt.Sprintf(consts.REDIS_KEY_JOB_PROGRESS, topic), afterID},
+		Count:   progressReadCount,
+		Block:   block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var events []models.JobProgress
+	for _, stream := range streams {
+		for _, message := range stream.Messages {
+			var event models.JobProgress
+			data, _ := message.Values["data"].(string)
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				return nil, fmt.Errorf("decode progress event %s: %w", message.ID, err)
+			}
+			event.ID = message.ID
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (s *RedisProgressStore) Clear(ctx context.Context, topic string) error {
+	return s.rdb.Del(ctx, fmt.Sprintf(consts.REDIS_KEY_JOB_PROGRESS, topic)).Err()
+}
@@ -0,0 +1,270 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type IQuizRepository interface {
+	CreateQuiz(ctx context.Context, quiz *models.Quiz) error
+	GetQuizByID(ctx context.Context, id int, userID string) (*models.Quiz, error)
+	ListQuizzes(ctx context.Context, userID string, courseID *int) ([]models.Quiz, error)
+	UpdateQuiz(ctx context.Context, id int, updates map[string]any) error
+	DeleteQuiz(ctx context.Context, id int, userID string) error
+
+	CreateQuestions(ctx context.Context, questions []models.QuizQuestion) error
+	// ListQuestions returns the quiz's questions in order
+	ListQuestions(ctx context.Context, quizID int) ([]models.QuizQuestion, error)
+
+	CreateAttempt(ctx context.Context, attempt *models.QuizAttempt) error
+	GetAttemptByID(ctx context.Context, id int, userID string) (*models.QuizAttempt, error)
+	ListAttempts(ctx context.Context, quizID int, userID string) ([]models.QuizAttempt, error)
+	UpdateAttempt(ctx context.Context, id int, updates map[string]any) error
+
+	CreateAnswers(ctx context.Context, answers []models.QuizAnswer) error
+	ListAnswers(ctx context.Context, attemptID int) ([]models.QuizAnswer, error)
+	UpdateAnswer(ctx context.Context, id int, updates map[string]any) error
+
+	// Statistics over the answers of graded attempts
+	CountGradedAttempts(ctx context.Context, quizID int) (int64, float64, error)
+	AggregateQuestions(ctx context.Context, quizID int) ([]models.QuizQuestionAggregate, error)
+	CountResponses(ctx context.Context, quizID int) ([]models.QuizResponseCount, error)
+
+	WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+	WithTx(tx *gorm.DB) IQuizRepository
+}
+
+type QuizRepository struct {
+	db *gorm.DB
+}
+
+// NewQuizRepository creates a new quiz repository with the given database connection.
+func NewQuizRepository(db *gorm.DB) IQuizRepository {
+	return &QuizRepository{db: db}
+}
+
+// WithTx creates a new instance of the repository with a transaction
+func (r *QuizRepository) WithTx(tx *gorm.DB) IQuizRepository {
+	return &QuizRepository{db: tx}
+}
+
+// WithTransaction runs fn inside a database transaction
+func (r *QuizRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+func (r *QuizRepository) CreateQuiz(ctx context.Context, quiz *models.Quiz) error {
+	return r.db.WithContext(ctx).Create(quiz).Error
+}
+
+func (r *QuizRepository) GetQuizByID(ctx context.Context, id int, userID string) (*models.Quiz, error) {
+	var quiz models.Quiz
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&quiz).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &quiz, nil
+}
+
+// ListQuizzes returns the user's quizzes, newest first
+func (r *QuizRepository) ListQuizzes(ctx context.Context, userID string, courseID *int) ([]models.Quiz, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if courseID != nil {
+		query = query.Where("course_id = ?", *courseID)
+	}
+
+	var quizzes []models.Quiz
+	if err := query.Order("created_at DESC, id DESC").Find(&quizzes).Error; err != nil {
+		return nil, err
+	}
+	return quizzes, nil
+}
+
+// UpdateQuiz applies the given column updates.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *QuizRepository) UpdateQuiz(ctx context.Context, id int, updates map[string]any) error {
+	// Remove fields that shouldn't be updated directly
+	delete(updates, "id")
+	delete(updates, "user_id")
+	delete(updates, "created_at")
+
+	return r.db.WithContext(ctx).Model(&models.Quiz{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// DeleteQuiz removes a quiz with its questions and attempts.
+// Returns gorm.ErrRecordNotFound when the quiz does not belong to the user
+func (r *QuizRepository) DeleteQuiz(ctx context.Context, id int, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&models.Quiz{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *QuizRepository) CreateQuestions(ctx context.Context, questions []models.QuizQuestion) error {
+	if len(questions) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&questions).Error
+}
+
+func (r *QuizRepository) ListQuestions(ctx context.Context, quizID int) ([]models.QuizQuestion, error) {
+	var questions []models.QuizQuestion
+	err := r.db.WithContext(ctx).
+		Where("quiz_id = ?", quizID).
+		Order("position ASC").
+		Find(&questions).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+func (r *QuizRepository) CreateAttempt(ctx context.Context, attempt *models.QuizAttempt) error {
+	return r.db.WithContext(ctx).Create(attempt).Error
+}
+
+func (r *QuizRepository) GetAttemptByID(ctx context.Context, id int, userID string) (*models.QuizAttempt, error) {
+	var attempt models.QuizAttempt
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&attempt).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// ListAttempts returns the user's attempts at a quiz, newest first
+func (r *QuizRepository) ListAttempts(ctx context.Context, quizID int, userID string) ([]models.QuizAttempt, error) {
+	var attempts []models.QuizAttempt
+	err := r.db.WithContext(ctx).
+		Where("quiz_id = ? AND user_id = ?", quizID, userID).
+		Order("created_at DESC, id DESC").
+		Find(&attempts).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+// UpdateAttempt applies the given column updates.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *QuizRepository) UpdateAttempt(ctx context.Context, id int, updates map[string]any) error {
+	// Remove fields that shouldn't be updated directly
+	delete(updates, "id")
+	delete(updates, "user_id")
+	delete(updates, "created_at")
+
+	return r.db.WithContext(ctx).Model(&models.QuizAttempt{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+func (r *QuizRepository) CreateAnswers(ctx context.Context, answers []models.QuizAnswer) error {
+	if len(answers) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&answers).Error
+}
+
+func (r *QuizRepository) ListAnswers(ctx context.Context, attemptID int) ([]models.QuizAnswer, error) {
+	var answers []models.QuizAnswer
+	err := r.db.WithContext(ctx).
+		Where("attempt_id = ?", attemptID).
+		Order("id ASC").
+		Find(&answers).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return answers, nil
+}
+
+// UpdateAnswer applies the given column updates.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *QuizRepository) UpdateAnswer(ctx context.Context, id int, updates map[string]any) error {
+	// Remove fields that shouldn't be updated directly
+	delete(updates, "id")
+	delete(updates, "user_id")
+	delete(updates, "created_at")
+
+	return r.db.WithContext(ctx).Model(&models.QuizAnswer{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// CountGradedAttempts returns the number of graded attempts and their mean score
+func (r *QuizRepository) CountGradedAttempts(ctx context.Context, quizID int) (int64, float64, error) {
+	var row struct {
+		Attempts     int64
+		AverageScore float64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.QuizAttempt{}).
+		Select("COUNT(*) AS attempts, COALESCE(AVG(score), 0) AS average_score").
+		Where("quiz_id = ? AND status = ?", quizID, consts.QuizAttemptStatus.GRADED).
+		Scan(&row).Error
+
+	if err != nil {
+		return 0, 0, err
+	}
+	return row.Attempts, row.AverageScore, nil
+}
+
+func (r *QuizRepository) gradedAnswers(ctx context.Context, quizID int) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Table("quiz_answers").
+		Joins("JOIN quiz_attempts ON quiz_attempts.id = quiz_answers.attempt_id").
+		Where("quiz_attempts.quiz_id = ? AND quiz_attempts.status = ?", quizID, consts.QuizAttemptStatus.GRADED)
+}
+
+// AggregateQuestions returns answer counts and mean scores per question.
+// Questions nobody answered yet have no row.
+func (r *QuizRepository) AggregateQuestions(ctx context.Context, quizID int) ([]models.QuizQuestionAggregate, error) {
+	var rows []models.QuizQuestionAggregate
+	err := r.gradedAnswers(ctx, quizID).
+		Select("quiz_answers.question_id, COUNT(*) AS answered, " +
+			"SUM(CASE WHEN quiz_answers.is_correct THEN 1 ELSE 0 END) AS correct, " +
+			"COALESCE(AVG(quiz_answers.score), 0) AS average_score").
+		Group("quiz_answers.question_id").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// CountResponses counts the responses given to mcq and true/false questions
+func (r *QuizRepository) CountResponses(ctx context.Context, quizID int) ([]models.QuizResponseCount, error) {
+	var rows []models.QuizResponseCount
+	err := r.gradedAnswers(ctx, quizID).
+		Joins("JOIN quiz_questions ON quiz_questions.id = quiz_answers.question_id").
+		Select("quiz_answers.question_id, quiz_answers.response, COUNT(*) AS count").
+		Where("quiz_questions.type IN ? AND quiz_answers.response <> ''", []string{consts.QuizQuestionType.MCQ, consts.QuizQuestionType.TRUE_FALSE}).
+		Group("quiz_answers.question_id, quiz_answers.response").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
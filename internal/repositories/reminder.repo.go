@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type IReminderRepository interface {
+	CreateReminder(ctx context.Context, reminder *models.Reminder) error
+	GetReminderByID(ctx context.Context, id int, userID string) (*models.Reminder, error)
+	ListReminders(ctx context.Context, filter models.ReminderFilter) ([]models.Reminder, error)
+	UpdateReminder(ctx context.Context, id int, userID string, updates map[string]any) error
+	DeleteReminder(ctx context.Context, id int, userID string) error
+
+	// ListPendingRemindersDueBetween returns pending reminders of every user whose deadline,
+	// read in the user's timezone, is in (from, to]
+	ListPendingRemindersDueBetween(ctx context.Context, from, to time.Time) ([]models.Reminder, error)
+
+	// MarkOverdueReminders flips pending reminders whose deadline, read in the user's timezone, has passed to overdue.
+	// An empty userID applies the update to every user.
+	MarkOverdueReminders(ctx context.Context, userID string, now time.Time) (int64, error)
+	// RefreshReminderStatuses re-derives pending and overdue from the user's deadlines read in loc,
+	// after the user's timezone changed
+	RefreshReminderStatuses(ctx context.Context, userID string, loc *time.Location, now time.Time) error
+
+	WithTx(tx *gorm.DB) IReminderRepository
+}
+
+type ReminderRepository struct {
+	db *gorm.DB
+}
+
+// NewReminderRepository creates a new reminder repository with the given database connection.
+func NewReminderRepository(db *gorm.DB) IReminderRepository {
+	return &ReminderRepository{db: db}
+}
+
+// WithTx creates a new instance of the repository with a transaction
+func (r *ReminderRepository) WithTx(tx *gorm.DB) IReminderRepository {
+	return &ReminderRepository{db: tx}
+}
+
+// CreateReminder inserts a new reminder.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *ReminderRepository) CreateReminder(ctx context.Context, reminder *models.Reminder) error {
+	return r.db.WithContext(ctx).Create(reminder).Error
+}
+
+// GetReminderByID retrieves a reminder owned by the given user.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *ReminderRepository) GetReminderByID(ctx context.Context, id int, userID string) (*models.Reminder, error) {
+	var reminder models.Reminder
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&reminder).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &reminder, nil
+}
+
+// ListReminders returns the user's reminders matching the filter, ordered by deadline.
+func (r *ReminderRepository) ListReminders(ctx context.Context, filter models.ReminderFilter) ([]models.Reminder, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", filter.UserID)
+
+	if filter.CourseID != nil {
+		query = query.Where("course_id = ?", *filter.CourseID)
+	}
+	if filter.Type != nil {
+		query = query.Where("type = ?", *filter.Type)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("due_date >= ?", filter.From.Format(consts.DATE_LAYOUT))
+	}
+	if filter.To != nil {
+		query = query.Where("due_date <= ?", filter.To.Format(consts.DATE_LAYOUT))
+	}
+
+	var reminders []models.Reminder
+	err := query.Order("due_date ASC, due_time ASC").Find(&reminders).Error
+	if err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+// UpdateReminder updates reminder fields scoped to the owning user.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *ReminderRepository) UpdateReminder(ctx context.Context, id int, userID string, updates map[string]any) error {
+	// Remove fields that shouldn't be updated directly
+	delete(updates, "id")
+	delete(updates, "user_id")
+	delete(updates, "created_at")
+
+	result := r.db.WithContext(ctx).Model(&models.Reminder{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(updates)
+
+	return result.Error
+}
+
+// DeleteReminder removes a reminder.
+// Returns gorm.ErrRecordNotFound when the reminder does not belong to the user
+func (r *ReminderRepository) DeleteReminder(ctx context.Context, id int, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&models.Reminder{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *ReminderRepository) ListPendingRemindersDueBetween(ctx context.Context, from, to time.Time) ([]models.Reminder, error) {
+	timezones, err := r.userTimezones(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var reminders []models.Reminder
+	for _, timezone := range timezones {
+		loc := timezoneLocation(timezone)
+		localFrom, localTo := from.In(loc), to.In(loc)
+
+		var batch []models.Reminder
+		err := r.db.WithContext(ctx).
+			Where("status = ?", consts.ReminderStatus.PENDING).
+			Where("user_id IN (?)", r.usersInTimezone(ctx, timezone)).
+			Where("due_date BETWEEN ? AND ?", localFrom.Format(consts.DATE_LAYOUT), localTo.Format(consts.DATE_LAYOUT)).
+			Where("TIMESTAMP(due_date, due_time) > ? AND TIMESTAMP(due_date, due_time) <= ?", localFrom.Format(time.DateTime), localTo.Format(time.DateTime)).
+			Order("due_date ASC, due_time ASC").
+			Find(&batch).Error
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, batch...)
+	}
+	return reminders, nil
+}
+
+func (r *ReminderRepository) MarkOverdueReminders(ctx context.Context, userID string, now time.Time) (int64, error) {
+	timezones, err := r.userTimezones(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var marked int64
+	for _, timezone := range timezones {
+		query := r.db.WithContext(ctx).Model(&models.Reminder{}).
+			Where("status = ?", consts.ReminderStatus.PENDING).
+			Where("user_id IN (?)", r.usersInTimezone(ctx, timezone)).
+			Where("TIMESTAMP(due_date, due_time) < ?", now.In(timezoneLocation(timezone)).Format(time.DateTime))
+
+		if userID != "" {
+			query = query.Where("user_id = ?", userID)
+		}
+
+		result := query.Update("status", consts.ReminderStatus.OVERDUE)
+		if result.Error != nil {
+			return marked, result.Error
+		}
+		marked += result.RowsAffected
+	}
+	return marked, nil
+}
+
+func (r *ReminderRepository) RefreshReminderStatuses(ctx context.Context, userID string, loc *time.Location, now time.Time) error {
+	local := now.In(loc).Format(time.DateTime)
+	err := r.db.WithContext(ctx).Model(&models.Reminder{}).
+		Where("user_id = ? AND status = ?", userID, consts.ReminderStatus.PENDING).
+		Where("TIMESTAMP(due_date, due_time) < ?", local).
+		Update("status", consts.ReminderStatus.OVERDUE).Error
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Model(&models.Reminder{}).
+		Where("user_id = ? AND status = ?", userID, consts.ReminderStatus.OVERDUE).
+		Where("TIMESTAMP(due_date, due_time) >= ?", local).
+		Update("status", consts.ReminderStatus.PENDING).Error
+}
+
+// userTimezones lists the distinct timezones of the users owning pending reminders.
+// Due dates and times are the owner's wall clock, so deadlines are compared one timezone at a time.
+func (r *ReminderRepository) userTimezones(ctx context.Context, userID string) ([]string, error) {
+	query := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("user_id IN (?)", r.db.Model(&models.Reminder{}).Select("user_id").Where("status = ?", consts.ReminderStatus.PENDING))
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var timezones []string
+	if err := query.Distinct().Pluck("timezone", &timezones).Error; err != nil {
+		return nil, err
+	}
+	return timezones, nil
+}
+
+func (r *ReminderRepository) usersInTimezone(ctx context.Context, timezone string) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&models.User{}).Select("user_id").Where("timezone = ?", timezone)
+}
+
+// timezoneLocation resolves a stored IANA name, falling back to UTC like the services do
+func timezoneLocation(timezone string) *time.Location {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/pkg/search"
+	"gorm.io/gorm"
+)
+
+// ISearchRepository ranks a user's notes, extracted file text, flashcards,
+// courses and reminders against a query. Files are ranked by their best
+// matching chunk and returned once. The MySQL implementation uses FULLTEXT
+// indexes; a dedicated engine can be swapped in by implementing this interface.
+type ISearchRepository interface {
+	Search(ctx context.Context, filter models.SearchFilter) ([]models.SearchRow, error)
+	// Count returns the number of matches per type, ignoring Limit and Offset
+	Count(ctx context.Context, filter models.SearchFilter) (map[string]int64, error)
+}
+
+type MySQLSearchRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLSearchRepository creates a FULLTEXT search engine on the given database connection.
+func NewMySQLSearchRepository(db *gorm.DB) ISearchRepository {
+	return &MySQLSearchRepository{db: db}
+}
+
+// Search returns matches of all requested types ordered by relevance.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *MySQLSearchRepository) Search(ctx context.Context, filter models.SearchFilter) ([]models.SearchRow, error) {
+	union, args := searchUnion(filter)
+	if union == "" {
+		return []models.SearchRow{}, nil
+	}
+
+	var rows []models.SearchRow
+	err := r.db.WithContext(ctx).
+		Raw("SELECT * FROM ("+union+") AS hits ORDER BY score DESC, date DESC, id DESC LIMIT ? OFFSET ?", append(args, filter.Limit, filter.Offset)...).
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *MySQLSearchRepository) Count(ctx context.Context, filter models.SearchFilter) (map[string]int64, error) {
+	union, args := searchUnion(filter)
+	counts := map[string]int64{}
+	if union == "" {
+		return counts, nil
+	}
+
+	var rows []struct {
+		Type  string
+		Total int64
+	}
+	err := r.db.WithContext(ctx).
+		Raw("SELECT type, COUNT(*) AS total FROM ("+union+") AS hits GROUP BY type", args...).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.Type] = row.Total
+	}
+	return counts, nil
+}
+
+// searchUnion builds one SELECT per requested type with identical columns:
+// type, id, title, body, score, course_id, date, page_start, page_end
+func searchUnion(filter models.SearchFilter) (string, []any) {
+	against := search.BooleanQuery(filter.Terms)
+	var parts []string
+	var args []any
+
+	wants := func(kind string) bool {
+		return len(filter.Types) == 0 || slices.Contains(filter.Types, kind)
+	}
+	tagExists := func(joinTable, joinColumn, owner string) string {
+		return "EXISTS (SELECT 1 FROM " + joinTable + " jt JOIN tags t ON t.id = jt.tag_id WHERE jt." + joinColumn + " = " + owner + " AND t.name = ?)"
+	}
+
+	if wants(consts.SearchType.NOTE) {
+		match := "MATCH(n.title, n.content_text) AGAINST (? IN BOOLEAN MODE)"
+		where := []string{"n.user_id = ?", match}
+		whereArgs := []any{filter.UserID, against}
+		if filter.CourseID != nil {
+			where, whereArgs = append(where, "n.course_id = ?"), append(whereArgs, *filter.CourseID)
+		}
+		if filter.Tag != "" {
+			where, whereArgs = append(where, tagExists("note_tags", "note_id", "n.id")), append(whereArgs, filter.Tag)
+		}
+		where, whereArgs = dateRange(where, whereArgs, "n.lecture_date", filter)
+
+		parts = append(parts, "SELECT 'note' AS type, n.id AS id, n.title AS title, n.content_text AS body, "+match+" AS score, "+
+			"n.course_id AS course_id, CAST(n.lecture_date AS DATETIME) AS date, NULL AS page_start, NULL AS page_end "+
+			"FROM notes n WHERE "+strings.Join(where, " AND "))
+		args = append(append(args, against), whereArgs...)
+	}
+
+	// One row per file: its best scoring chunk, whose score is the file's MAX(score)
+	if wants(consts.SearchType.FILE) {
+		match := "MATCH(fc.content) AGAINST (? IN BOOLEAN MODE)"
+		where := []string{"fc.user_id = ?", match}
+		whereArgs := []any{filter.UserID, against}
+		if filter.CourseID != nil {
+			where, whereArgs = append(where, "fc.course_id = ?"), append(whereArgs, *filter.CourseID)
+		}
+		if filter.Tag != "" {
+			where, whereArgs = append(where, tagExists("course_tags", "course_id", "fc.course_id")), append(whereArgs, filter.Tag)
+		}
+		where, whereArgs = dateRange(where, whereArgs, "DATE(f.created_at)", filter)
+
+		parts = append(parts, "SELECT 'file' AS type, id, title, body, score, course_id, date, page_start, page_end FROM ("+
+			"SELECT f.id AS id, f.filename AS title, fc.content AS body, "+match+" AS score, "+
+			"fc.course_id AS course_id, f.created_at AS date, fc.page_start AS page_start, fc.page_end AS page_end, "+
+			"ROW_NUMBER() OVER (PARTITION BY fc.file_id ORDER BY "+match+" DESC, fc.chunk_index ASC) AS chunk_rank "+
+			"FROM file_chunks fc JOIN files f ON f.id = fc.file_id WHERE "+strings.Join(where, " AND ")+
+			") AS chunks WHERE chunk_rank = 1")
+		args = append(append(args, against, against), whereArgs...)
+	}
+
+	if wants(consts.SearchType.FLASHCARD) {
+		match := "MATCH(fl.front, fl.back) AGAINST (? IN BOOLEAN MODE)"
+		where := []string{"fl.user_id = ?", match}
+		whereArgs := []any{filter.UserID, against}
+		if filter.CourseID != nil {
+			where, whereArgs = append(where, "fl.course_id = ?"), append(whereArgs, *filter.CourseID)
+		}
+		if filter.Tag != "" {
+			where, whereArgs = append(where, tagExists("course_tags", "course_id", "fl.course_id")), append(whereArgs, filter.Tag)
+		}
+		where, whereArgs = dateRange(where, whereArgs, "DATE(fl.created_at)", filter)
+
+		parts = append(parts, "SELECT 'flashcard' AS type, fl.id AS id, fl.front AS title, CONCAT(fl.front, ' · ', fl.back) AS body, "+match+" AS score, "+
+			"fl.course_id AS course_id, fl.created_at AS date, fl.page_start AS page_start, fl.page_end AS page_end "+
+			"FROM flashcards fl WHERE "+strings.Join(where, " AND "))
+		args = append(append(args, against), whereArgs...)
+	}
+
+	// Courses have no date, so a date filter leaves them out
+	if wants(consts.SearchType.COURSE) && filter.From == nil && filter.To == nil {
+		match := "MATCH(c.course_name, c.lecturers) AGAINST (? IN BOOLEAN MODE)"
+		where := []string{"c.user_id = ?", match}
+		whereArgs := []any{filter.UserID, against}
+		if filter.CourseID != nil {
+			where, whereArgs = append(where, "c.id = ?"), append(whereArgs, *filter.CourseID)
+		}
+		if filter.Tag != "" {
+			where, whereArgs = append(where, tagExists("course_tags", "course_id", "c.id")), append(whereArgs, filter.Tag)
+		}
+
+		parts = append(parts, "SELECT 'course' AS type, c.id AS id, c.course_name AS title, CONCAT(c.course_id, ' · ', c.lecturers) AS body, "+match+" AS score, "+
+			"c.id AS course_id, NULL AS date, NULL AS page_start, NULL AS page_end "+
+			"FROM courses c WHERE "+strings.Join(where, " AND "))
+		args = append(append(args, against), whereArgs...)
+	}
+
+	if wants(consts.SearchType.REMINDER) {
+		match := "MATCH(r.title, r.description) AGAINST (? IN BOOLEAN MODE)"
+		where := []string{"r.user_id = ?", match}
+		whereArgs := []any{filter.UserID, against}
+		if filter.CourseID != nil {
+			where, whereArgs = append(where, "r.course_id = ?"), append(whereArgs, *filter.CourseID)
+		}
+		if filter.Tag != "" {
+			where, whereArgs = append(where, tagExists("course_tags", "course_id", "r.course_id")), append(whereArgs, filter.Tag)
+		}
+		where, whereArgs = dateRange(where, whereArgs, "r.due_date", filter)
+
+		parts = append(parts, "SELECT 'reminder' AS type, r.id AS id, r.title AS title, r.description AS body, "+match+" AS score, "+
+			"r.course_id AS course_id, CAST(r.due_date AS DATETIME) AS date, NULL AS page_start, NULL AS page_end "+
+			"FROM reminders r WHERE "+strings.Join(where, " AND "))
+		args = append(append(args, against), whereArgs...)
+	}
+
+	return strings.Join(parts, " UNION ALL "), args
+}
+
+func dateRange(where []string, args []any, column string, filter models.SearchFilter) ([]string, []any) {
+	if filter.From != nil {
+		where, args = append(where, column+" >= ?"), append(args, filter.From.Format(consts.DATE_LAYOUT))
+	}
+	if filter.To != nil {
+		where, args = append(where, column+" <= ?"), append(args, filter.To.Format(consts.DATE_LAYOUT))
+	}
+	return where, args
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type ISemesterRepository interface {
+	GetSemesterByID(ctx context.Context, id int) (*models.Semester, error)
+	// FindSemesterByDate returns the semester whose date range contains date
+	FindSemesterByDate(ctx context.Context, date time.Time) (*models.Semester, error)
+}
+
+type SemesterRepository struct {
+	db *gorm.DB
+}
+
+// NewSemesterRepository creates a new semester repository with the given database connection.
+func NewSemesterRepository(db *gorm.DB) ISemesterRepository {
+	return &SemesterRepository{db: db}
+}
+
+// GetSemesterByID retrieves a semester by ID.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *SemesterRepository) GetSemesterByID(ctx context.Context, id int) (*models.Semester, error) {
+	var semester models.Semester
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&semester).Error
+	if err != nil {
+		return nil, err
+	}
+	return &semester, nil
+}
+
+// FindSemesterByDate picks the latest-starting semester containing date.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *SemesterRepository) FindSemesterByDate(ctx context.Context, date time.Time) (*models.Semester, error) {
+	var semester models.Semester
+	err := r.db.WithContext(ctx).
+		Where("start_date <= ? AND end_date >= ?", date, date).
+		Order("start_date DESC").
+		First(&semester).Error
+	if err != nil {
+		return nil, err
+	}
+	return &semester, nil
+}
@@ -0,0 +1,293 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type IStudyGroupRepository interface {
+	// CreateGroup inserts the group together with its members
+	CreateGroup(ctx context.Context, group *models.StudyGroup) error
+	GetGroupByID(ctx context.Context, id int) (*models.StudyGroup, error)
+	// ListGroupsByUser returns the groups the user belongs to, newest first
+	ListGroupsByUser(ctx context.Context, userID string) ([]models.StudyGroupSummary, error)
+	UpdateGroup(ctx context.Context, id int, updates map[string]any) error
+	DeleteGroup(ctx context.Context, id int) error
+
+	GetMember(ctx context.Context, groupID int, userID string) (*models.StudyGroupMember, error)
+	// ListMembers returns the members by role, then by join time
+	ListMembers(ctx context.Context, groupID int) ([]models.StudyGroupMemberView, error)
+	CountMembers(ctx context.Context, groupID int) (int64, error)
+	AddMember(ctx context.Context, member *models.StudyGroupMember) error
+	UpdateMemberRole(ctx context.Context, groupID int, userID string, role int8) error
+	RemoveMember(ctx context.Context, groupID int, userID string) error
+
+	CreateInvitation(ctx context.Context, invitation *models.StudyGroupInvitation) error
+	GetInvitationByToken(ctx context.Context, token string) (*models.StudyGroupInvitation, error)
+	// ListPendingInvitations returns the invitations that can still be used as of now
+	ListPendingInvitations(ctx context.Context, groupID int, now time.Time) ([]models.StudyGroupInvitation, error)
+	UpdateInvitation(ctx context.Context, id int, updates map[string]any) error
+	DeleteInvitation(ctx context.Context, id, groupID int) error
+
+	ShareNote(ctx context.Context, shared *models.StudyGroupNote) error
+	// GetSharedNote loads a shared note with its content
+	GetSharedNote(ctx context.Context, groupID, noteID int) (*models.StudyGroupNote, error)
+	// ListSharedNotes returns the group's notes, most recently shared first
+	ListSharedNotes(ctx context.Context, groupID int) ([]models.SharedNote, error)
+	// FindSharedNote returns the note if it is shared into any group the user belongs to
+	FindSharedNote(ctx context.Context, noteID int, userID string) (*models.Note, error)
+	UnshareNote(ctx context.Context, groupID, noteID int) error
+	// UnshareNotesBy removes every note the user shared into the group
+	UnshareNotesBy(ctx context.Context, groupID int, userID string) error
+
+	WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+	WithTx(tx *gorm.DB) IStudyGroupRepository
+}
+
+type StudyGroupRepository struct {
+	db *gorm.DB
+}
+
+// NewStudyGroupRepository creates a new study group repository with the given database connection.
+func NewStudyGroupRepository(db *gorm.DB) IStudyGroupRepository {
+	return &StudyGroupRepository{db: db}
+}
+
+// WithTx creates a new instance of the repository with a transaction
+func (r *StudyGroupRepository) WithTx(tx *gorm.DB) IStudyGroupRepository {
+	return &StudyGroupRepository{db: tx}
+}
+
+func (r *StudyGroupRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+func (r *StudyGroupRepository) CreateGroup(ctx context.Context, group *models.StudyGroup) error {
+	return r.db.WithContext(ctx).Omit("Members.User").Create(group).Error
+}
+
+func (r *StudyGroupRepository) GetGroupByID(ctx context.Context, id int) (*models.StudyGroup, error) {
+	var group models.StudyGroup
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&group).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *StudyGroupRepository) ListGroupsByUser(ctx context.Context, userID string) ([]models.StudyGroupSummary, error) {
+	query := `SELECT g.*, m.role,
+			(SELECT COUNT(*) FROM study_group_members gm WHERE gm.group_id = g.id) AS members,
+			(SELECT COUNT(*) FROM study_group_notes gn WHERE gn.group_id = g.id) AS shared_notes
+		FROM study_groups g
+		JOIN study_group_members m ON m.group_id = g.id
+		WHERE m.user_id = ?
+		ORDER BY g.id DESC`
+
+	var groups []models.StudyGroupSummary
+	err := r.db.WithContext(ctx).Raw(query, userID).Scan(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (r *StudyGroupRepository) UpdateGroup(ctx context.Context, id int, updates map[string]any) error {
+	return r.db.WithContext(ctx).Model(&models.StudyGroup{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// DeleteGroup removes the group; members, invitations and shared notes go with it
+func (r *StudyGroupRepository) DeleteGroup(ctx context.Context, id int) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.StudyGroup{}).Error
+}
+
+func (r *StudyGroupRepository) GetMember(ctx context.Context, groupID int, userID string) (*models.StudyGroupMember, error) {
+	var member models.StudyGroupMember
+	err := r.db.WithContext(ctx).
+		Where("group_id = ? AND user_id = ?", groupID, userID).
+		First(&member).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *StudyGroupRepository) ListMembers(ctx context.Context, groupID int) ([]models.StudyGroupMemberView, error) {
+	query := `SELECT m.user_id, u.username, m.role, m.joined_at,
+			(SELECT COUNT(*) FROM study_group_notes gn WHERE gn.group_id = m.group_id AND gn.shared_by = m.user_id) AS shared_notes
+		FROM study_group_members m
+		JOIN users u ON u.user_id = m.user_id
+		WHERE m.group_id = ?
+		ORDER BY m.role DESC, m.joined_at ASC`
+
+	var members []models.StudyGroupMemberView
+	err := r.db.WithContext(ctx).Raw(query, groupID).Scan(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *StudyGroupRepository) CountMembers(ctx context.Context, groupID int) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.StudyGroupMember{}).
+		Where("group_id = ?", groupID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *StudyGroupRepository) AddMember(ctx context.Context, member *models.StudyGroupMember) error {
+	return r.db.WithContext(ctx).Omit("User").Create(member).Error
+}
+
+func (r *StudyGroupRepository) UpdateMemberRole(ctx context.Context, groupID int, userID string, role int8) error {
+	return r.db.WithContext(ctx).Model(&models.StudyGroupMember{}).
+		Where("group_id = ? AND user_id = ?", groupID, userID).
+		Update("role", role).Error
+}
+
+// RemoveMember deletes the membership.
+// Returns gorm.ErrRecordNotFound when the user is not a member
+func (r *StudyGroupRepository) RemoveMember(ctx context.Context, groupID int, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("group_id = ? AND user_id = ?", groupID, userID).
+		Delete(&models.StudyGroupMember{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *StudyGroupRepository) CreateInvitation(ctx context.Context, invitation *models.StudyGroupInvitation) error {
+	return r.db.WithContext(ctx).Omit("Group").Create(invitation).Error
+}
+
+func (r *StudyGroupRepository) GetInvitationByToken(ctx context.Context, token string) (*models.StudyGroupInvitation, error) {
+	var invitation models.StudyGroupInvitation
+	err := r.db.WithContext(ctx).
+		Where("token = ?", token).
+		First(&invitation).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *StudyGroupRepository) ListPendingInvitations(ctx context.Context, groupID int, now time.Time) ([]models.StudyGroupInvitation, error) {
+	var invitations []models.StudyGroupInvitation
+	err := r.db.WithContext(ctx).
+		Where("group_id = ? AND accepted_at IS NULL AND expires_at > ?", groupID, now).
+		Order("id DESC").
+		Find(&invitations).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (r *StudyGroupRepository) UpdateInvitation(ctx context.Context, id int, updates map[string]any) error {
+	return r.db.WithContext(ctx).Model(&models.StudyGroupInvitation{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// DeleteInvitation revokes an invitation of the group.
+// Returns gorm.ErrRecordNotFound when the group has no such invitation
+func (r *StudyGroupRepository) DeleteInvitation(ctx context.Context, id, groupID int) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND group_id = ?", id, groupID).
+		Delete(&models.StudyGroupInvitation{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *StudyGroupRepository) ShareNote(ctx context.Context, shared *models.StudyGroupNote) error {
+	return r.db.WithContext(ctx).Omit("Group", "Note").Create(shared).Error
+}
+
+func (r *StudyGroupRepository) GetSharedNote(ctx context.Context, groupID, noteID int) (*models.StudyGroupNote, error) {
+	var shared models.StudyGroupNote
+	err := r.db.WithContext(ctx).
+		Preload("Note.Tags").
+		Where("group_id = ? AND note_id = ?", groupID, noteID).
+		First(&shared).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &shared, nil
+}
+
+func (r *StudyGroupRepository) ListSharedNotes(ctx context.Context, groupID int) ([]models.SharedNote, error) {
+	query := `SELECT n.id AS note_id, n.title, n.lecture_date, n.user_id AS author_id, u.username AS author_name,
+			gn.shared_at, n.updated_at
+		FROM study_group_notes gn
+		JOIN notes n ON n.id = gn.note_id
+		JOIN users u ON u.user_id = n.user_id
+		WHERE gn.group_id = ?
+		ORDER BY gn.shared_at DESC, n.id DESC`
+
+	var notes []models.SharedNote
+	err := r.db.WithContext(ctx).Raw(query, groupID).Scan(&notes).Error
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (r *StudyGroupRepository) FindSharedNote(ctx context.Context, noteID int, userID string) (*models.Note, error) {
+	var note models.Note
+	err := r.db.WithContext(ctx).
+		Where("id = ?", noteID).
+		Where("EXISTS (SELECT 1 FROM study_group_notes gn JOIN study_group_members m ON m.group_id = gn.group_id WHERE gn.note_id = notes.id AND m.user_id = ?)", userID).
+		First(&note).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// UnshareNote removes a note from the group.
+// Returns gorm.ErrRecordNotFound when the note is not shared in the group
+func (r *StudyGroupRepository) UnshareNote(ctx context.Context, groupID, noteID int) error {
+	result := r.db.WithContext(ctx).
+		Where("group_id = ? AND note_id = ?", groupID, noteID).
+		Delete(&models.StudyGroupNote{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *StudyGroupRepository) UnshareNotesBy(ctx context.Context, groupID int, userID string) error {
+	return r.db.WithContext(ctx).
+		Where("group_id = ? AND shared_by = ?", groupID, userID).
+		Delete(&models.StudyGroupNote{}).Error
+}
@@ -0,0 +1,237 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IStudySessionRepository interface {
+	CreateSession(ctx context.Context, session *models.StudySession) error
+	GetSessionByID(ctx context.Context, id int, userID string) (*models.StudySession, error)
+	// GetRunningSession returns the user's session that has not stopped yet, FOR UPDATE
+	GetRunningSession(ctx context.Context, userID string) (*models.StudySession, error)
+	// ListSessions returns the user's sessions matching the filter, newest first
+	ListSessions(ctx context.Context, filter models.StudySessionFilter) ([]models.StudySession, error)
+	// CountOverlapping counts the user's sessions, running ones included, that overlap [from, to)
+	CountOverlapping(ctx context.Context, userID string, from, to time.Time) (int64, error)
+	UpdateSession(ctx context.Context, id int, userID string, updates map[string]any) error
+	DeleteSession(ctx context.Context, id int, userID string) error
+
+	// The analytics below aggregate finished sessions started in [from, to).
+	// offset is the user's UTC offset ("+07:00") used to bucket them into local days.
+
+	// WeeklyStudyTime totals study and planned study per course and week. Blocks of
+	// an accepted plan count until a later accepted plan takes over their time.
+	WeeklyStudyTime(ctx context.Context, userID, offset string, from, to time.Time) ([]models.CourseWeekStudyTime, error)
+	// StudyStreaks returns every run of consecutive local days with study, latest first
+	StudyStreaks(ctx context.Context, userID, offset string) ([]models.StudyStreak, error)
+	// CourseGradeStudyTime lists the study time of the user's graded courses
+	CourseGradeStudyTime(ctx context.Context, userID string, from, to time.Time) ([]models.CourseGradeStudyTime, error)
+	// StudyGradeCorrelation correlates the rows of CourseGradeStudyTime
+	StudyGradeCorrelation(ctx context.Context, userID string, from, to time.Time) (*models.StudyGradeCorrelation, error)
+
+	WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+	WithTx(tx *gorm.DB) IStudySessionRepository
+}
+
+type StudySessionRepository struct {
+	db *gorm.DB
+}
+
+// NewStudySessionRepository creates a new study session repository with the given database connection.
+func NewStudySessionRepository(db *gorm.DB) IStudySessionRepository {
+	return &StudySessionRepository{db: db}
+}
+
+// WithTx creates a new instance of the repository with a transaction
+func (r *StudySessionRepository) WithTx(tx *gorm.DB) IStudySessionRepository {
+	return &StudySessionRepository{db: tx}
+}
+
+func (r *StudySessionRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(fn)
+}
+
+func (r *StudySessionRepository) CreateSession(ctx context.Context, session *models.StudySession) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(session).Error
+}
+
+func (r *StudySessionRepository) GetSessionByID(ctx context.Context, id int, userID string) (*models.StudySession, error) {
+	var session models.StudySession
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&session).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *StudySessionRepository) GetRunningSession(ctx context.Context, userID string) (*models.StudySession, error) {
+	var session models.StudySession
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("user_id = ? AND ended_at IS NULL", userID).
+		First(&session).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *StudySessionRepository) ListSessions(ctx context.Context, filter models.StudySessionFilter) ([]models.StudySession, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", filter.UserID)
+
+	if filter.CourseID != nil {
+		query = query.Where("course_id = ?", *filter.CourseID)
+	}
+	if filter.From != nil {
+		query = query.Where("started_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("started_at < ?", *filter.To)
+	}
+
+	var sessions []models.StudySession
+	err := query.Order("started_at DESC").Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *StudySessionRepository) CountOverlapping(ctx context.Context, userID string, from, to time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.StudySession{}).
+		Where("user_id = ? AND started_at < ? AND (ended_at IS NULL OR ended_at > ?)", userID, to, from).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *StudySessionRepository) UpdateSession(ctx context.Context, id int, userID string, updates map[string]any) error {
+	return r.db.WithContext(ctx).Model(&models.StudySession{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(updates).Error
+}
+
+// DeleteSession removes a study session.
+// Returns gorm.ErrRecordNotFound when the session does not belong to the user
+func (r *StudySessionRepository) DeleteSession(ctx context.Context, id int, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&models.StudySession{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *StudySessionRepository) WeeklyStudyTime(ctx context.Context, userID, offset string, from, to time.Time) ([]models.CourseWeekStudyTime, error) {
+	// Monday of the local week of a UTC timestamp column
+	weekStart := func(column string) string {
+		local := "CONVERT_TZ(" + column + ", '+00:00', ?)"
+		return "DATE_SUB(DATE(" + local + "), INTERVAL WEEKDAY(" + local + ") DAY)"
+	}
+
+	query := `SELECT t.week_start, t.course_id, c.course_id AS course_code, c.course_name,
+			SUM(t.study_seconds) DIV 60 AS study_minutes, SUM(t.planned_seconds) DIV 60 AS planned_minutes,
+			SUM(t.sessions) AS sessions, SUM(t.pomodoros) AS pomodoros
+		FROM (
+			SELECT ` + weekStart("s.started_at") + ` AS week_start, s.course_id,
+				s.duration_seconds AS study_seconds, 0 AS planned_seconds, 1 AS sessions, s.pomodoros
+			FROM study_sessions s
+			WHERE s.user_id = ? AND s.ended_at IS NOT NULL AND s.started_at >= ? AND s.started_at < ?
+			UNION ALL
+			SELECT ` + weekStart("b.start_at") + `, b.course_id,
+				0, TIMESTAMPDIFF(SECOND, b.start_at, b.end_at), 0, 0
+			FROM study_blocks b
+			JOIN study_plans p ON p.id = b.plan_id
+			WHERE b.user_id = ? AND p.accepted_at IS NOT NULL AND b.start_at >= ? AND b.start_at < ?
+				AND NOT EXISTS (
+					SELECT 1 FROM study_plans q
+					WHERE q.user_id = p.user_id AND q.accepted_at > p.accepted_at AND q.starts_at <= b.start_at
+				)
+		) AS t
+		JOIN courses c ON c.id = t.course_id
+		GROUP BY t.week_start, t.course_id, c.course_id, c.course_name
+		ORDER BY t.week_start ASC, c.course_id ASC`
+
+	var weeks []models.CourseWeekStudyTime
+	err := r.db.WithContext(ctx).
+		Raw(query, offset, offset, userID, from, to, offset, offset, userID, from, to).
+		Scan(&weeks).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return weeks, nil
+}
+
+func (r *StudySessionRepository) StudyStreaks(ctx context.Context, userID, offset string) ([]models.StudyStreak, error) {
+	// Gaps and islands: consecutive days minus their row number share one date
+	query := `SELECT MIN(day) AS start_date, MAX(day) AS end_date, COUNT(*) AS days
+		FROM (
+			SELECT day, DATE_SUB(day, INTERVAL ROW_NUMBER() OVER (ORDER BY day) DAY) AS island
+			FROM (
+				SELECT DISTINCT DATE(CONVERT_TZ(started_at, '+00:00', ?)) AS day
+				FROM study_sessions
+				WHERE user_id = ? AND ended_at IS NOT NULL
+			) AS days
+		) AS islands
+		GROUP BY island
+		ORDER BY end_date DESC`
+
+	var streaks []models.StudyStreak
+	err := r.db.WithContext(ctx).Raw(query, offset, userID).Scan(&streaks).Error
+	if err != nil {
+		return nil, err
+	}
+	return streaks, nil
+}
+
+// courseGradeStudyTime is the query behind CourseGradeStudyTime and StudyGradeCorrelation
+const courseGradeStudyTime = `SELECT c.id AS course_id, c.course_id AS course_code, c.course_name, c.gpa,
+		COALESCE(SUM(s.duration_seconds), 0) DIV 60 AS study_minutes
+	FROM courses c
+	LEFT JOIN study_sessions s ON s.course_id = c.id AND s.ended_at IS NOT NULL AND s.started_at >= ? AND s.started_at < ?
+	WHERE c.user_id = ? AND c.gpa > 0
+	GROUP BY c.id, c.course_id, c.course_name, c.gpa`
+
+func (r *StudySessionRepository) CourseGradeStudyTime(ctx context.Context, userID string, from, to time.Time) ([]models.CourseGradeStudyTime, error) {
+	var courses []models.CourseGradeStudyTime
+	err := r.db.WithContext(ctx).
+		Raw(courseGradeStudyTime+" ORDER BY c.course_id ASC", from, to, userID).
+		Scan(&courses).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return courses, nil
+}
+
+func (r *StudySessionRepository) StudyGradeCorrelation(ctx context.Context, userID string, from, to time.Time) (*models.StudyGradeCorrelation, error) {
+	// Pearson's r from sums; NULLIF leaves it null when either side has no spread
+	query := `SELECT COUNT(*) AS courses,
+			(COUNT(*) * SUM(t.study_minutes * t.gpa) - SUM(t.study_minutes) * SUM(t.gpa)) /
+			NULLIF(SQRT((COUNT(*) * SUM(t.study_minutes * t.study_minutes) - SUM(t.study_minutes) * SUM(t.study_minutes)) *
+				(COUNT(*) * SUM(t.gpa * t.gpa) - SUM(t.gpa) * SUM(t.gpa))), 0) AS coefficient
+		FROM (` + courseGradeStudyTime + `) AS t`
+
+	var correlation models.StudyGradeCorrelation
+	err := r.db.WithContext(ctx).Raw(query, from, to, userID).Scan(&correlation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &correlation, nil
+}
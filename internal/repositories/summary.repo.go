@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ISummaryRepository interface {
+	// CreateSummary inserts a summary as the next version of its note
+	CreateSummary(ctx context.Context, summary *models.NoteSummary) error
+	GetSummaryByID(ctx context.Context, id int, userID string) (*models.NoteSummary, error)
+	GetSummary(ctx context.Context, noteID, version int) (*models.NoteSummary, error)
+	ListSummaries(ctx context.Context, noteID int) ([]models.NoteSummary, error)
+	// FindActiveSummary returns a pending or processing summary of the note
+	// in the given language for the given note version
+	FindActiveSummary(ctx context.Context, noteID, noteVersion int, language string) (*models.NoteSummary, error)
+	UpdateSummary(ctx context.Context, id int, updates map[string]any) error
+}
+
+type SummaryRepository struct {
+	db *gorm.DB
+}
+
+// NewSummaryRepository creates a new note summary repository with the given database connection.
+func NewSummaryRepository(db *gorm.DB) ISummaryRepository {
+	return &SummaryRepository{db: db}
+}
+
+// CreateSummary locks the note row so concurrent requests get distinct versions.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *SummaryRepository) CreateSummary(ctx context.Context, summary *models.NoteSummary) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var note models.Note
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", summary.NoteID).
+			First(&note).Error
+		if err != nil {
+			return err
+		}
+
+		var latest int
+		err = tx.Model(&models.NoteSummary{}).
+			Where("note_id = ?", summary.NoteID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+
+		summary.Version = latest + 1
+		return tx.Create(summary).Error
+	})
+}
+
+func (r *SummaryRepository) GetSummaryByID(ctx context.Context, id int, userID string) (*models.NoteSummary, error) {
+	var summary models.NoteSummary
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&summary).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (r *SummaryRepository) GetSummary(ctx context.Context, noteID, version int) (*models.NoteSummary, error) {
+	var summary models.NoteSummary
+	err := r.db.WithContext(ctx).
+		Where("note_id = ? AND version = ?", noteID, version).
+		First(&summary).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// ListSummaries returns all versions of a note's summary, newest first
+func (r *SummaryRepository) ListSummaries(ctx context.Context, noteID int) ([]models.NoteSummary, error) {
+	var summaries []models.NoteSummary
+	err := r.db.WithContext(ctx).
+		Where("note_id = ?", noteID).
+		Order("version DESC").
+		Find(&summaries).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+func (r *SummaryRepository) FindActiveSummary(ctx context.Context, noteID, noteVersion int, language string) (*models.NoteSummary, error) {
+	var summary models.NoteSummary
+	err := r.db.WithContext(ctx).
+		Where("note_id = ? AND note_version = ? AND language = ?", noteID, noteVersion, language).
+		Where("status IN ?", []int8{consts.SummaryStatus.PENDING, consts.SummaryStatus.PROCESSING}).
+		Order("version DESC").
+		First(&summary).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// UpdateSummary applies the given column updates.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *SummaryRepository) UpdateSummary(ctx context.Context, id int, updates map[string]any) error {
+	// Remove fields that shouldn't be updated directly
+	delete(updates, "id")
+	delete(updates, "note_id")
+	delete(updates, "user_id")
+	delete(updates, "version")
+	delete(updates, "created_at")
+
+	return r.db.WithContext(ctx).Model(&models.NoteSummary{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type ITagRepository interface {
+	// FindOrCreateTags returns the tags with the given names, creating missing ones
+	FindOrCreateTags(ctx context.Context, names []string) ([]models.Tag, error)
+
+	WithTx(tx *gorm.DB) ITagRepository
+}
+
+type TagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new tag repository with the given database connection.
+func NewTagRepository(db *gorm.DB) ITagRepository {
+	return &TagRepository{db: db}
+}
+
+// WithTx creates a new instance of the repository with a transaction
+func (r *TagRepository) WithTx(tx *gorm.DB) ITagRepository {
+	return &TagRepository{db: tx}
+}
+
+// FindOrCreateTags matches names case-insensitively (through the column collation)
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *TagRepository) FindOrCreateTags(ctx context.Context, names []string) ([]models.Tag, error) {
+	if len(names) == 0 {
+		return []models.Tag{}, nil
+	}
+
+	var existing []models.Tag
+	if err := r.db.WithContext(ctx).Where("name IN ?", names).Order("id ASC").Find(&existing).Error; err != nil {
+		return nil, err
+	}
+
+	found := map[string]bool{}
+	tags := make([]models.Tag, 0, len(names))
+	for _, tag := range existing {
+		key := strings.ToLower(tag.Name)
+		if !found[key] {
+			found[key] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	var missing []models.Tag
+	for _, name := range names {
+		if !found[strings.ToLower(name)] {
+			found[strings.ToLower(name)] = true
+			missing = append(missing, models.Tag{Name: name})
+		}
+	}
+	if len(missing) > 0 {
+		if err := r.db.WithContext(ctx).Omit("Courses", "Notes").Create(&missing).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, missing...)
+	}
+	return tags, nil
+}
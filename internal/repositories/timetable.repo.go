@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type IClassSessionRepository interface {
+	CreateSession(ctx context.Context, session *models.ClassSession) error
+	GetSessionByID(ctx context.Context, id int, userID string) (*models.ClassSession, error)
+	ListSessions(ctx context.Context, userID string, courseID *int) ([]models.ClassSession, error)
+	UpdateSession(ctx context.Context, id int, userID string, updates map[string]any) error
+	DeleteSession(ctx context.Context, id int, userID string) error
+
+	CreateException(ctx context.Context, exception *models.ClassSessionException) error
+	DeleteException(ctx context.Context, sessionID int, date time.Time) error
+	// ReplaceExceptions sets the session's cancelled dates to exactly the given ones
+	ReplaceExceptions(ctx context.Context, sessionID int, exceptions []models.ClassSessionException) error
+
+	WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+	WithTx(tx *gorm.DB) IClassSessionRepository
+}
+
+type ClassSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewClassSessionRepository creates a new class session repository with the given database connection.
+func NewClassSessionRepository(db *gorm.DB) IClassSessionRepository {
+	return &ClassSessionRepository{db: db}
+}
+
+// WithTx creates a new instance of the repository with a transaction
+func (r *ClassSessionRepository) WithTx(tx *gorm.DB) IClassSessionRepository {
+	return &ClassSessionRepository{db: tx}
+}
+
+// CreateSession inserts a new class session.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *ClassSessionRepository) CreateSession(ctx context.Context, session *models.ClassSession) error {
+	return r.db.WithContext(ctx).Omit("Course", "Exceptions").Create(session).Error
+}
+
+// GetSessionByID retrieves a session owned by the user, with its course and exceptions.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *ClassSessionRepository) GetSessionByID(ctx context.Context, id int, userID string) (*models.ClassSession, error) {
+	var session models.ClassSession
+	err := r.db.WithContext(ctx).
+		Preload("Course").
+		Preload("Exceptions").
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&session).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListSessions returns all the user's sessions, optionally for a single course
+func (r *ClassSessionRepository) ListSessions(ctx context.Context, userID string, courseID *int) ([]models.ClassSession, error) {
+	query := r.db.WithContext(ctx).
+		Preload("Course").
+		Preload("Exceptions").
+		Where("user_id = ?", userID)
+
+	if courseID != nil {
+		query = query.Where("course_id = ?", *courseID)
+	}
+
+	var sessions []models.ClassSession
+	err := query.Order("weekday ASC, start_time ASC").Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// UpdateSession updates session fields scoped to the owning user.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *ClassSessionRepository) UpdateSession(ctx context.Context, id int, userID string, updates map[string]any) error {
+	// Remove fields that shouldn't be updated directly
+	delete(updates, "id")
+	delete(updates, "user_id")
+	delete(updates, "created_at")
+
+	return r.db.WithContext(ctx).Model(&models.ClassSession{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(updates).Error
+}
+
+// DeleteSession removes a session and, through the FK, its exceptions.
+// Returns gorm.ErrRecordNotFound when the session does not belong to the user
+func (r *ClassSessionRepository) DeleteSession(ctx context.Context, id int, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&models.ClassSession{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CreateException cancels one occurrence of a session.
+// Returns raw GORM error - service layer should handle error interpretation
+func (r *ClassSessionRepository) CreateException(ctx context.Context, exception *models.ClassSessionException) error {
+	return r.db.WithContext(ctx).Create(exception).Error
+}
+
+// DeleteException restores a cancelled occurrence.
+// Returns gorm.ErrRecordNotFound when the date was not cancelled
+func (r *ClassSessionRepository) DeleteException(ctx context.Context, sessionID int, date time.Time) error {
+	result := r.db.WithContext(ctx).
+		Where("session_id = ? AND date = ?", sessionID, date.Format(consts.DATE_LAYOUT)).
+		Delete(&models.ClassSessionException{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ReplaceExceptions deletes the session's exceptions and inserts the given ones.
+// Call inside WithTransaction so a failed insert does not lose the old dates.
+func (r *ClassSessionRepository) ReplaceExceptions(ctx context.Context, sessionID int, exceptions []models.ClassSessionException) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Where("session_id = ?", sessionID).Delete(&models.ClassSessionException{}).Error; err != nil {
+		return err
+	}
+	if len(exceptions) == 0 {
+		return nil
+	}
+
+	for i := range exceptions {
+		exceptions[i].SessionID = sessionID
+	}
+	return db.Create(&exceptions).Error
+}
+
+// WithTransaction executes a function within a database transaction
+func (r *ClassSessionRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(tx)
+	})
+}
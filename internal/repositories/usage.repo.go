@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IUsageRepository interface {
+	CreateRecord(ctx context.Context, record *models.UsageRecord) error
+	// ReserveRecord creates the record as a reservation unless the user already has limit
+	// operations in [from, to). It reports whether the record was created. The user's row
+	// is locked meanwhile, so concurrent reservations cannot exceed the limit together.
+	ReserveRecord(ctx context.Context, record *models.UsageRecord, from, to time.Time, limit *int64) (bool, error)
+	// CompleteRecord fills in the reservation with the record's ID, or creates the record
+	// when it has no reservation or the reservation is gone
+	CompleteRecord(ctx context.Context, record *models.UsageRecord) error
+	// DeleteReservation drops a reservation that was not completed
+	DeleteReservation(ctx context.Context, id int) error
+	// SumUsage totals the user's ledger per operation over [from, to)
+	SumUsage(ctx context.Context, userID string, from, to time.Time) ([]models.OperationUsage, error)
+}
+
+type UsageRepository struct {
+	db *gorm.DB
+}
+
+// NewUsageRepository creates a new usage repository with the given database connection.
+func NewUsageRepository(db *gorm.DB) IUsageRepository {
+	return &UsageRepository{db: db}
+}
+
+func (r *UsageRepository) CreateRecord(ctx context.Context, record *models.UsageRecord) error {
+	return r.db.WithContext(ctx).Create(record).Error
+}
+
+func (r *UsageRepository) ReserveRecord(ctx context.Context, record *models.UsageRecord, from, to time.Time, limit *int64) (bool, error) {
+	reserved := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		err := tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+			Select("user_id").
+			Where("user_id = ?", record.UserID).
+			First(&user).Error
+		if err != nil {
+			return err
+		}
+
+		if limit != nil {
+			var used int64
+			err = tx.Model(&models.UsageRecord{}).
+				Where("user_id = ? AND created_at >= ? AND created_at < ?", record.UserID, from, to).
+				Where("operation NOT IN ?", consts.USAGE_BACKGROUND_OPERATIONS).
+				Count(&used).Error
+			if err != nil {
+				return err
+			}
+			if used >= *limit {
+				return nil
+			}
+		}
+
+		record.Reserved = true
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+		reserved = true
+		return nil
+	})
+	return reserved, err
+}
+
+func (r *UsageRepository) CompleteRecord(ctx context.Context, record *models.UsageRecord) error {
+	if record.ID != 0 {
+		result := r.db.WithContext(ctx).
+			Model(&models.UsageRecord{}).
+			Where("id = ? AND reserved = ?", record.ID, true).
+			Updates(map[string]any{
+				"reference":     record.Reference,
+				"provider":      record.Provider,
+				"model":         record.Model,
+				"input_tokens":  record.InputTokens,
+				"output_tokens": record.OutputTokens,
+				"reserved":      false,
+			})
+		if result.Error != nil || result.RowsAffected > 0 {
+			return result.Error
+		}
+		record.ID = 0
+	}
+	record.Reserved = false
+	return r.CreateRecord(ctx, record)
+}
+
+func (r *UsageRepository) DeleteReservation(ctx context.Context, id int) error {
+	return r.db.WithContext(ctx).
+		Where("id = ? AND reserved = ?", id, true).
+		Delete(&models.UsageRecord{}).Error
+}
+
+func (r *UsageRepository) SumUsage(ctx context.Context, userID string, from, to time.Time) ([]models.OperationUsage, error) {
+	var usage []models.OperationUsage
+	err := r.db.WithContext(ctx).
+		Model(&models.UsageRecord{}).
+		Select("operation, COUNT(*) AS count, COALESCE(SUM(input_tokens), 0) AS input_tokens, COALESCE(SUM(output_tokens), 0) AS output_tokens").
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+		Group("operation").
+		Order("operation ASC").
+		Scan(&usage).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
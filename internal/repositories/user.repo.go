@@ -14,6 +14,7 @@ type IUserRepository interface {
 	CreateUser(ctx context.Context, user *models.User) error
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	GetUserByID(ctx context.Context, userID string) (*models.User, error)
+	GetUsersByIDs(ctx context.Context, userIDs []string) ([]models.User, error)
 
 	// Update operations
 	ActivateUserAccount(ctx context.Context, userID string, status, isEmailVerified int8) error
@@ -52,7 +53,7 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) erro
 func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
 	err := r.db.WithContext(ctx).
-		Select("user_id, username, email, password, phone_number, account_status, is_email_verified, is_phone_verified, created_at, updated_at").
+		Select("user_id, username, email, password, phone_number, account_status, is_email_verified, is_phone_verified, timezone, tier, required_credits, created_at, updated_at").
 		Where("email = ?", email).
 		First(&user).Error
 
@@ -67,7 +68,7 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 func (r *UserRepository) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
 	var user models.User
 	err := r.db.WithContext(ctx).
-		Select("user_id, username, email, password, phone_number, account_status, is_email_verified, is_phone_verified, created_at, updated_at").
+		Select("user_id, username, email, password, phone_number, account_status, is_email_verified, is_phone_verified, timezone, tier, required_credits, created_at, updated_at").
 		Where("user_id = ?", userID).
 		First(&user).Error
 
@@ -77,6 +78,20 @@ func (r *UserRepository) GetUserByID(ctx context.Context, userID string) (*model
 	return &user, nil
 }
 
+// GetUsersByIDs retrieves the users with the given IDs. Unknown IDs are skipped.
+func (r *UserRepository) GetUsersByIDs(ctx context.Context, userIDs []string) ([]models.User, error) {
+	var users []models.User
+	err := r.db.WithContext(ctx).
+		Select("user_id, username, email, timezone, tier").
+		Where("user_id IN ?", userIDs).
+		Find(&users).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 // UpdateUser updates user fields.
 // Returns raw GORM error - service layer should handle error interpretation
 func (r *UserRepository) UpdateUser(ctx context.Context, userID string, updates map[string]interface{}) error {
@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/pkg/vector"
+	"gorm.io/gorm"
+)
+
+// IVectorStore stores chunk embeddings and finds the ones nearest to a query
+type IVectorStore interface {
+	// ReplaceNoteEmbeddings swaps every embedding of the note for the given ones
+	ReplaceNoteEmbeddings(ctx context.Context, noteID int, embeddings []models.Embedding) error
+	// ReplaceFileEmbeddings swaps every embedding of the file for the given ones
+	ReplaceFileEmbeddings(ctx context.Context, fileID int, embeddings []models.Embedding) error
+	// Search returns up to query.TopK chunks by descending cosine similarity
+	Search(ctx context.Context, query models.VectorQuery) ([]models.VectorMatch, error)
+}
+
+// vectorScanBatch is how many embeddings a search loads per round trip
+const vectorScanBatch = 500
+
+// MySQLVectorStore keeps vectors in a blob column and searches them by brute
+// force: every candidate row is scored in Go. That is fine for one student's
+// material; a dedicated index can implement IVectorStore when it is not.
+type MySQLVectorStore struct {
+	db *gorm.DB
+}
+
+// NewMySQLVectorStore creates a vector store on the given database connection.
+func NewMySQLVectorStore(db *gorm.DB) IVectorStore {
+	return &MySQLVectorStore{db: db}
+}
+
+func (s *MySQLVectorStore) ReplaceNoteEmbeddings(ctx context.Context, noteID int, embeddings []models.Embedding) error {
+	return s.replace(ctx, "note_id = ?", noteID, embeddings)
+}
+
+func (s *MySQLVectorStore) ReplaceFileEmbeddings(ctx context.Context, fileID int, embeddings []models.Embedding) error {
+	return s.replace(ctx, "file_id = ?", fileID, embeddings)
+}
+
+func (s *MySQLVectorStore) replace(ctx context.Context, condition string, id int, embeddings []models.Embedding) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(condition, id).Delete(&models.Embedding{}).Error; err != nil {
+			return err
+		}
+		if len(embeddings) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&embeddings, 100).Error
+	})
+}
+
+func (s *MySQLVectorStore) Search(ctx context.Context, query models.VectorQuery) ([]models.VectorMatch, error) {
+	target := vector.Normalize(query.Vector)
+	top := vector.NewTopK[models.Embedding](query.TopK)
+
+	scan := s.db.WithContext(ctx).
+		Where("user_id = ? AND model = ? AND dimensions = ?", query.UserID, query.Model, len(target))
+	if query.CourseID != nil {
+		scan = scan.Where("course_id = ?", *query.CourseID)
+	}
+
+	var batch []models.Embedding
+	err := scan.FindInBatches(&batch, vectorScanBatch, func(tx *gorm.DB, _ int) error {
+		for _, embedding := range batch {
+			v, err := vector.Decode(embedding.Vector)
+			if err != nil {
+				continue
+			}
+			score, err := vector.Dot(target, v)
+			if err != nil || score < query.MinScore {
+				continue
+			}
+			top.Push(embedding, score)
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	embeddings, scores := top.Results()
+	matches := make([]models.VectorMatch, len(embeddings))
+	var noteIDs, fileIDs []int
+	for i, embedding := range embeddings {
+		matches[i] = models.VectorMatch{Embedding: embedding, Score: scores[i]}
+		if embedding.NoteID != nil {
+			noteIDs = append(noteIDs, *embedding.NoteID)
+		}
+		if embedding.FileID != nil {
+			fileIDs = append(fileIDs, *embedding.FileID)
+		}
+	}
+	if err := s.fillTitles(ctx, matches, noteIDs, fileIDs); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// fillTitles sets each match's title to its note's title or file's name
+func (s *MySQLVectorStore) fillTitles(ctx context.Context, matches []models.VectorMatch, noteIDs, fileIDs []int) error {
+	noteTitles := map[int]string{}
+	if len(noteIDs) > 0 {
+		var notes []models.Note
+		if err := s.db.WithContext(ctx).Select("id, title").Where("id IN ?", noteIDs).Find(&notes).Error; err != nil {
+			return err
+		}
+		for _, note := range notes {
+			noteTitles[note.ID] = note.Title
+		}
+	}
+	fileNames := map[int]string{}
+	if len(fileIDs) > 0 {
+		var files []models.File
+		if err := s.db.WithContext(ctx).Select("id, filename").Where("id IN ?", fileIDs).Find(&files).Error; err != nil {
+			return err
+		}
+		for _, file := range files {
+			fileNames[file.ID] = file.Filename
+		}
+	}
+
+	for i := range matches {
+		if matches[i].NoteID != nil {
+			matches[i].Title = noteTitles[*matches[i].NoteID]
+		} else if matches[i].FileID != nil {
+			matches[i].Title = fileNames[*matches[i].FileID]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupAnalyticsRoutes configures grade and performance analytics routes;
+// study time analytics live with the study session routes
+func SetupAnalyticsRoutes(apiV1 *gin.RouterGroup) {
+
+	// Initialize dependencies
+	analyticsRepo := repositories.NewAnalyticsRepository(global.Mdb)
+	userRepo := repositories.NewUserRepository(global.Mdb)
+	analyticsService := services.NewAnalyticsService(analyticsRepo, userRepo)
+	analyticsController := controllers.NewAnalyticsController(analyticsService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+
+	// Analytics routes
+	analytics := apiV1.Group("/analytics", authMiddleware.Auth())
+	{
+		analytics.GET("/gpa-trend", analyticsController.GetGPATrend)
+		analytics.GET("/performance", analyticsController.GetPerformance)
+		analytics.GET("/credits", analyticsController.GetCreditProgress)
+		analytics.PUT("/credits", analyticsController.UpdateRequiredCredits)
+		analytics.GET("/forecasts", analyticsController.GetForecasts)
+	}
+}
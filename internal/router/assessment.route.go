@@ -0,0 +1,31 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupAssessmentRoutes configures the routes of a course's graded components
+func SetupAssessmentRoutes(apiV1 *gin.RouterGroup) {
+
+	// Initialize dependencies
+	assessmentRepo := repositories.NewAssessmentRepository(global.Mdb)
+	courseRepo := repositories.NewCourseRepository(global.Mdb)
+	assessmentService := services.NewAssessmentService(assessmentRepo, courseRepo)
+	assessmentController := controllers.NewAssessmentController(assessmentService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+
+	// Assessment routes
+	assessments := apiV1.Group("/courses/:id/assessments", authMiddleware.Auth())
+	{
+		assessments.GET("", assessmentController.GetAssessments)
+		assessments.PUT("", assessmentController.ReplaceAssessments)
+		assessments.PUT("/:assessment_id/score", assessmentController.RecordScore)
+	}
+}
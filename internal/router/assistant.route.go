@@ -0,0 +1,36 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupAssistantRoutes configures study assistant conversation routes
+func SetupAssistantRoutes(apiV1 *gin.RouterGroup) {
+
+	// Initialize dependencies
+	assistantRepo := repositories.NewAssistantRepository(global.Mdb)
+	vectorStore := repositories.NewMySQLVectorStore(global.Mdb)
+	courseRepo := repositories.NewCourseRepository(global.Mdb)
+	quotaService := newQuotaService()
+	assistantService := services.NewAssistantService(assistantRepo, vectorStore, courseRepo, repositories.NewUsageRepository(global.Mdb), quotaService, global.AI)
+	assistantController := controllers.NewAssistantController(assistantService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+	quotaMiddleware := middleware.NewQuotaMiddleware(quotaService)
+
+	// Assistant routes
+	conversations := apiV1.Group("/assistant/conversations", authMiddleware.Auth())
+	{
+		conversations.POST("", assistantController.CreateConversation)
+		conversations.GET("", assistantController.ListConversations)
+		conversations.GET("/:id", assistantController.GetConversation)
+		conversations.DELETE("/:id", assistantController.DeleteConversation)
+		conversations.POST("/:id/messages", quotaMiddleware.AIOperation(), assistantController.SendMessage)
+	}
+}
@@ -0,0 +1,33 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/queue"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupBillingRoutes configures checkout, subscription and payment provider webhook routes
+func SetupBillingRoutes(apiV1 *gin.RouterGroup, jobs *queue.Client) {
+
+	// Initialize dependencies
+	billingService := services.NewBillingService(repositories.NewBillingRepository(global.Mdb), repositories.NewPlanRepository(global.Mdb),
+		repositories.NewUserRepository(global.Mdb), global.Billing, jobs)
+	billingController := controllers.NewBillingController(billingService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+
+	// Billing routes
+	billing := apiV1.Group("/billing")
+	{
+		billing.POST("/checkout", authMiddleware.Auth(), billingController.CreateCheckout)
+		billing.GET("/subscription", authMiddleware.Auth(), billingController.GetSubscription)
+
+		// Webhooks are authorized by the provider's signature, not a JWT
+		billing.POST("/webhook", billingController.HandleWebhook)
+	}
+}
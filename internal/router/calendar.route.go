@@ -0,0 +1,39 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupCalendarRoutes configures iCalendar export and subscription routes
+func SetupCalendarRoutes(apiV1 *gin.RouterGroup) {
+
+	// Initialize dependencies
+	calendarRepo := repositories.NewCalendarRepository(global.Mdb)
+	userRepo := repositories.NewUserRepository(global.Mdb)
+	sessionRepo := repositories.NewClassSessionRepository(global.Mdb)
+	reminderRepo := repositories.NewReminderRepository(global.Mdb)
+	notificationRepo := repositories.NewNotificationRepository(global.Mdb)
+	calendarService := services.NewCalendarService(calendarRepo, userRepo, sessionRepo, reminderRepo, notificationRepo)
+	calendarController := controllers.NewCalendarController(calendarService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+
+	// Calendar routes
+	calendar := apiV1.Group("/calendar")
+	{
+		// Subscription feed is authenticated by its secret token, not a JWT
+		calendar.GET("/:token", calendarController.GetFeedCalendar)
+
+		private := calendar.Group("", authMiddleware.Auth())
+		private.GET("/feed", calendarController.GetFeed)
+		private.POST("/feed/rotate", calendarController.RotateFeedToken)
+		private.PUT("/timezone", calendarController.UpdateTimezone)
+		private.GET("/export.ics", calendarController.ExportCalendar)
+	}
+}
@@ -0,0 +1,33 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupChatRoutes configures the study group chat routes
+func SetupChatRoutes(apiV1 *gin.RouterGroup) {
+
+	// Initialize dependencies
+	studyGroupRepo := repositories.NewStudyGroupRepository(global.Mdb)
+	chatRepo := repositories.NewChatRepository(global.Mdb)
+	userRepo := repositories.NewUserRepository(global.Mdb)
+	chatBroker := repositories.NewRedisChatBroker(global.Redis)
+	chatService := services.NewChatService(studyGroupRepo, chatRepo, userRepo, chatBroker)
+	chatController := controllers.NewChatController(chatService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+
+	// Chat routes
+	chat := apiV1.Group("/study-groups/:id")
+	{
+		chat.GET("/messages", authMiddleware.Auth(), chatController.ListMessages)
+		chat.POST("/messages", authMiddleware.Auth(), chatController.SendMessage)
+		chat.GET("/chat", authMiddleware.WebSocketAuth(), chatController.Connect)
+	}
+}
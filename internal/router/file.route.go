@@ -0,0 +1,53 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/queue"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/storage"
+)
+
+// SetupFileRoutes configures file upload routes and, for the local storage
+// backend, the endpoint behind its presigned URLs
+func SetupFileRoutes(apiV1 *gin.RouterGroup, jobs *queue.Client) {
+
+	// Initialize dependencies
+	fileRepo := repositories.NewFileRepository(global.Mdb)
+	userRepo := repositories.NewUserRepository(global.Mdb)
+	courseRepo := repositories.NewCourseRepository(global.Mdb)
+	fileService := services.NewFileService(fileRepo, userRepo, courseRepo, global.Storage, jobs)
+	progressService := services.NewProgressService(repositories.NewRedisProgressStore(global.Redis))
+	fileController := controllers.NewFileController(fileService, progressService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+
+	// File routes
+	files := apiV1.Group("/files", authMiddleware.Auth())
+	{
+		files.POST("", fileController.UploadFile)
+		files.GET("", fileController.ListFiles)
+		files.GET("/usage", fileController.GetUsage)
+		files.POST("/uploads", fileController.CreateUpload)
+		files.GET("/:id", fileController.GetFile)
+		files.DELETE("/:id", fileController.DeleteFile)
+		files.POST("/:id/complete", fileController.CompleteUpload)
+		files.GET("/:id/download", fileController.GetDownloadURL)
+		files.GET("/:id/chunks", fileController.ListChunks)
+		files.POST("/:id/extract", fileController.RequestExtraction)
+		files.GET("/:id/extract/events", fileController.StreamExtractionProgress)
+	}
+
+	// Presigned URLs of the local backend are authorized by their signature, not a JWT
+	if local, ok := global.Storage.(*storage.LocalStorage); ok {
+		storageController := controllers.NewStorageController(local, services.MaxFileSize())
+
+		blobs := apiV1.Group("/storage")
+		blobs.PUT("/*key", storageController.PutObject)
+		blobs.GET("/*key", storageController.GetObject)
+	}
+}
@@ -0,0 +1,45 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/queue"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupFlashcardRoutes configures flashcard, generation and review routes
+func SetupFlashcardRoutes(apiV1 *gin.RouterGroup, jobs *queue.Client) {
+
+	// Initialize dependencies
+	flashcardRepo := repositories.NewFlashcardRepository(global.Mdb)
+	noteRepo := repositories.NewNoteRepository(global.Mdb)
+	fileRepo := repositories.NewFileRepository(global.Mdb)
+	userRepo := repositories.NewUserRepository(global.Mdb)
+	courseRepo := repositories.NewCourseRepository(global.Mdb)
+	quotaService := newQuotaService()
+	flashcardService := services.NewFlashcardService(flashcardRepo, noteRepo, fileRepo, userRepo, courseRepo, quotaService, jobs)
+	progressService := services.NewProgressService(repositories.NewRedisProgressStore(global.Redis))
+	flashcardController := controllers.NewFlashcardController(flashcardService, progressService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+	quotaMiddleware := middleware.NewQuotaMiddleware(quotaService)
+
+	// Flashcard routes
+	flashcards := apiV1.Group("/flashcards", authMiddleware.Auth())
+	{
+		flashcards.POST("", flashcardController.CreateFlashcard)
+		flashcards.GET("", flashcardController.ListFlashcards)
+		flashcards.GET("/due", flashcardController.ListDueFlashcards)
+		flashcards.POST("/generations", quotaMiddleware.AIOperation(), flashcardController.GenerateFlashcards)
+		flashcards.GET("/generations/:id", flashcardController.GetGeneration)
+		flashcards.GET("/generations/:id/events", flashcardController.StreamGenerationProgress)
+		flashcards.GET("/:id", flashcardController.GetFlashcard)
+		flashcards.PUT("/:id", flashcardController.UpdateFlashcard)
+		flashcards.DELETE("/:id", flashcardController.DeleteFlashcard)
+		flashcards.POST("/:id/review", flashcardController.ReviewFlashcard)
+	}
+}
@@ -0,0 +1,49 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/queue"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupNoteRoutes configures lecture note, revision history and summary routes
+func SetupNoteRoutes(apiV1 *gin.RouterGroup, jobs *queue.Client) {
+
+	// Initialize dependencies
+	noteRepo := repositories.NewNoteRepository(global.Mdb)
+	tagRepo := repositories.NewTagRepository(global.Mdb)
+	courseRepo := repositories.NewCourseRepository(global.Mdb)
+	noteService := services.NewNoteService(noteRepo, tagRepo, courseRepo, repositories.NewRedisNoteCollabStore(global.Redis), jobs)
+	noteController := controllers.NewNoteController(noteService)
+	summaryRepo := repositories.NewSummaryRepository(global.Mdb)
+	quotaService := newQuotaService()
+	summaryService := services.NewSummaryService(summaryRepo, noteRepo, quotaService, jobs)
+	progressService := services.NewProgressService(repositories.NewRedisProgressStore(global.Redis))
+	summaryController := controllers.NewSummaryController(summaryService, progressService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+	quotaMiddleware := middleware.NewQuotaMiddleware(quotaService)
+
+	// Note routes
+	notes := apiV1.Group("/notes", authMiddleware.Auth())
+	{
+		notes.POST("", noteController.CreateNote)
+		notes.GET("", noteController.ListNotes)
+		notes.GET("/:id", noteController.GetNote)
+		notes.PUT("/:id", noteController.UpdateNote)
+		notes.DELETE("/:id", noteController.DeleteNote)
+		notes.GET("/:id/diff", noteController.DiffRevisions)
+		notes.GET("/:id/revisions", noteController.ListRevisions)
+		notes.GET("/:id/revisions/:version", noteController.GetRevision)
+		notes.POST("/:id/revisions/:version/restore", noteController.RestoreRevision)
+		notes.POST("/:id/summaries", quotaMiddleware.AIOperation(), summaryController.RequestSummary)
+		notes.GET("/:id/summaries", summaryController.ListSummaries)
+		notes.GET("/:id/summaries/:version", summaryController.GetSummary)
+		notes.GET("/:id/summaries/:version/events", summaryController.StreamSummaryProgress)
+	}
+}
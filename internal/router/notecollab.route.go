@@ -0,0 +1,31 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/queue"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupNoteCollabRoutes configures the collaborative note editing routes
+func SetupNoteCollabRoutes(apiV1 *gin.RouterGroup, jobs *queue.Client) {
+
+	// Initialize dependencies
+	noteRepo := repositories.NewNoteRepository(global.Mdb)
+	studyGroupRepo := repositories.NewStudyGroupRepository(global.Mdb)
+	collabStore := repositories.NewRedisNoteCollabStore(global.Redis)
+	noteCollabService := services.NewNoteCollabService(noteRepo, studyGroupRepo, collabStore, jobs)
+	noteCollabController := controllers.NewNoteCollabController(noteCollabService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+
+	// Collaborative editing routes
+	collab := apiV1.Group("/notes/:id")
+	{
+		collab.GET("/collab", authMiddleware.WebSocketAuth(), noteCollabController.Connect)
+	}
+}
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupNotificationRoutes configures reminder notification routes
+func SetupNotificationRoutes(apiV1 *gin.RouterGroup) {
+
+	// Initialize dependencies
+	notificationRepo := repositories.NewNotificationRepository(global.Mdb)
+	notificationService := services.NewNotificationService(notificationRepo)
+	notificationController := controllers.NewNotificationController(notificationService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+
+	// Notification routes
+	notifications := apiV1.Group("/notifications", authMiddleware.Auth())
+	{
+		notifications.GET("", notificationController.ListNotifications)
+		notifications.GET("/settings", notificationController.GetSettings)
+		notifications.PUT("/settings", notificationController.UpdateSettings)
+	}
+}
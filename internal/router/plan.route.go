@@ -0,0 +1,33 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupPlanRoutes configures plan and usage routes
+func SetupPlanRoutes(apiV1 *gin.RouterGroup) {
+
+	// Initialize dependencies
+	planController := controllers.NewPlanController(newQuotaService())
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+
+	// Plan routes
+	apiV1.GET("/plans", planController.ListPlans)
+	me := apiV1.Group("/users/me", authMiddleware.Auth())
+	{
+		me.GET("/usage", planController.GetUsage)
+	}
+}
+
+// newQuotaService builds the quota service that AI and course-creating routes check
+func newQuotaService() services.IQuotaService {
+	return services.NewQuotaService(repositories.NewPlanRepository(global.Mdb), repositories.NewUsageRepository(global.Mdb),
+		repositories.NewUserRepository(global.Mdb), repositories.NewCourseRepository(global.Mdb), repositories.NewFileRepository(global.Mdb))
+}
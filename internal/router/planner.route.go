@@ -0,0 +1,42 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupPlannerRoutes configures study preference and study plan routes
+func SetupPlannerRoutes(apiV1 *gin.RouterGroup) {
+
+	// Initialize dependencies
+	plannerRepo := repositories.NewPlannerRepository(global.Mdb)
+	reminderRepo := repositories.NewReminderRepository(global.Mdb)
+	sessionRepo := repositories.NewClassSessionRepository(global.Mdb)
+	courseRepo := repositories.NewCourseRepository(global.Mdb)
+	userRepo := repositories.NewUserRepository(global.Mdb)
+	plannerService := services.NewPlannerService(plannerRepo, reminderRepo, sessionRepo, courseRepo, userRepo)
+	plannerController := controllers.NewPlannerController(plannerService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+
+	// Planner routes
+	planner := apiV1.Group("/planner", authMiddleware.Auth())
+	{
+		planner.GET("/preferences", plannerController.GetPreferences)
+		planner.PUT("/preferences", plannerController.UpdatePreferences)
+		planner.PUT("/courses/:id/difficulty", plannerController.SetCourseDifficulty)
+
+		planner.POST("/plans", plannerController.GeneratePlan)
+		planner.GET("/plans", plannerController.ListPlans)
+		planner.GET("/plans/:id", plannerController.GetPlan)
+		planner.POST("/plans/:id/accept", plannerController.AcceptPlan)
+		planner.POST("/plans/:id/regenerate", plannerController.RegeneratePlan)
+		planner.PATCH("/plans/:id/blocks/:block_id", plannerController.AdjustBlock)
+		planner.DELETE("/plans/:id/blocks/:block_id", plannerController.DeleteBlock)
+	}
+}
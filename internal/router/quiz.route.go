@@ -0,0 +1,43 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/queue"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupQuizRoutes configures quiz generation, practice attempt and statistics routes
+func SetupQuizRoutes(apiV1 *gin.RouterGroup, jobs *queue.Client) {
+
+	// Initialize dependencies
+	quizRepo := repositories.NewQuizRepository(global.Mdb)
+	noteRepo := repositories.NewNoteRepository(global.Mdb)
+	courseRepo := repositories.NewCourseRepository(global.Mdb)
+	quotaService := newQuotaService()
+	quizService := services.NewQuizService(quizRepo, noteRepo, courseRepo, quotaService, jobs)
+	progressService := services.NewProgressService(repositories.NewRedisProgressStore(global.Redis))
+	quizController := controllers.NewQuizController(quizService, progressService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+	quotaMiddleware := middleware.NewQuotaMiddleware(quotaService)
+
+	// Quiz routes
+	quizzes := apiV1.Group("/quizzes", authMiddleware.Auth())
+	{
+		quizzes.POST("", quotaMiddleware.AIOperation(), quizController.CreateQuiz)
+		quizzes.GET("", quizController.ListQuizzes)
+		quizzes.GET("/:id", quizController.GetQuiz)
+		quizzes.GET("/:id/events", quizController.StreamQuizProgress)
+		quizzes.DELETE("/:id", quizController.DeleteQuiz)
+		quizzes.GET("/:id/stats", quizController.GetStats)
+		quizzes.POST("/:id/attempts", quizController.StartAttempt)
+		quizzes.GET("/:id/attempts", quizController.ListAttempts)
+		quizzes.GET("/:id/attempts/:attempt", quizController.GetAttempt)
+		quizzes.POST("/:id/attempts/:attempt/submit", quizController.SubmitAttempt)
+	}
+}
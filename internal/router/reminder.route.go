@@ -0,0 +1,36 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupReminderRoutes configures reminder, assignment and exam routes
+func SetupReminderRoutes(apiV1 *gin.RouterGroup) {
+
+	// Initialize dependencies
+	reminderRepo := repositories.NewReminderRepository(global.Mdb)
+	courseRepo := repositories.NewCourseRepository(global.Mdb)
+	notificationRepo := repositories.NewNotificationRepository(global.Mdb)
+	userRepo := repositories.NewUserRepository(global.Mdb)
+	reminderService := services.NewReminderService(reminderRepo, courseRepo, notificationRepo, userRepo)
+	reminderController := controllers.NewReminderController(reminderService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+
+	// Reminder routes
+	reminders := apiV1.Group("/reminders", authMiddleware.Auth())
+	{
+		reminders.POST("", reminderController.CreateReminder)
+		reminders.GET("", reminderController.ListReminders)
+		reminders.GET("/:id", reminderController.GetReminder)
+		reminders.PUT("/:id", reminderController.UpdateReminder)
+		reminders.PATCH("/:id/status", reminderController.UpdateReminderStatus)
+		reminders.DELETE("/:id", reminderController.DeleteReminder)
+	}
+}
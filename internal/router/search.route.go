@@ -0,0 +1,25 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupSearchRoutes configures full-text search routes
+func SetupSearchRoutes(apiV1 *gin.RouterGroup) {
+
+	// Initialize dependencies
+	searchRepo := repositories.NewMySQLSearchRepository(global.Mdb)
+	searchService := services.NewSearchService(searchRepo)
+	searchController := controllers.NewSearchController(searchService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+
+	// Search routes
+	apiV1.GET("/search", authMiddleware.Auth(), searchController.Search)
+}
@@ -0,0 +1,51 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupStudyGroupRoutes configures study group, invitation and shared note routes
+func SetupStudyGroupRoutes(apiV1 *gin.RouterGroup) {
+
+	// Initialize dependencies
+	studyGroupRepo := repositories.NewStudyGroupRepository(global.Mdb)
+	courseRepo := repositories.NewCourseRepository(global.Mdb)
+	noteRepo := repositories.NewNoteRepository(global.Mdb)
+	userRepo := repositories.NewUserRepository(global.Mdb)
+	mailRepo := repositories.NewMailRepository(global.Mdb)
+	chatBroker := repositories.NewRedisChatBroker(global.Redis)
+	studyGroupService := services.NewStudyGroupService(studyGroupRepo, courseRepo, noteRepo, userRepo, mailRepo, helper.NewMailHelper(), chatBroker)
+	studyGroupController := controllers.NewStudyGroupController(studyGroupService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+
+	// Study group routes
+	groups := apiV1.Group("/study-groups", authMiddleware.Auth())
+	{
+		groups.POST("", studyGroupController.CreateGroup)
+		groups.GET("", studyGroupController.ListGroups)
+		groups.POST("/join", studyGroupController.Join)
+		groups.GET("/:id", studyGroupController.GetGroup)
+		groups.PATCH("/:id", studyGroupController.UpdateGroup)
+		groups.DELETE("/:id", studyGroupController.DeleteGroup)
+
+		groups.POST("/:id/invitations", studyGroupController.CreateInvitation)
+		groups.GET("/:id/invitations", studyGroupController.ListInvitations)
+		groups.DELETE("/:id/invitations/:invitation_id", studyGroupController.RevokeInvitation)
+
+		groups.POST("/:id/leave", studyGroupController.Leave)
+		groups.PUT("/:id/members/:user_id/role", studyGroupController.UpdateMemberRole)
+		groups.DELETE("/:id/members/:user_id", studyGroupController.RemoveMember)
+
+		groups.POST("/:id/notes", studyGroupController.ShareNote)
+		groups.GET("/:id/notes", studyGroupController.ListSharedNotes)
+		groups.GET("/:id/notes/:note_id", studyGroupController.GetSharedNote)
+		groups.DELETE("/:id/notes/:note_id", studyGroupController.UnshareNote)
+	}
+}
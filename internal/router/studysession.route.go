@@ -0,0 +1,41 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupStudySessionRoutes configures study time tracking and analytics routes
+func SetupStudySessionRoutes(apiV1 *gin.RouterGroup) {
+
+	// Initialize dependencies
+	sessionRepo := repositories.NewStudySessionRepository(global.Mdb)
+	courseRepo := repositories.NewCourseRepository(global.Mdb)
+	noteRepo := repositories.NewNoteRepository(global.Mdb)
+	userRepo := repositories.NewUserRepository(global.Mdb)
+	studySessionService := services.NewStudySessionService(sessionRepo, courseRepo, noteRepo, userRepo)
+	studySessionController := controllers.NewStudySessionController(studySessionService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+
+	// Study session routes
+	sessions := apiV1.Group("/study-sessions", authMiddleware.Auth())
+	{
+		sessions.POST("/start", studySessionController.StartSession)
+		sessions.POST("/:id/stop", studySessionController.StopSession)
+		sessions.POST("", studySessionController.LogSession)
+		sessions.GET("", studySessionController.ListSessions)
+		sessions.DELETE("/:id", studySessionController.DeleteSession)
+	}
+
+	// Analytics routes
+	analytics := apiV1.Group("/analytics", authMiddleware.Auth())
+	{
+		analytics.GET("/study-time", studySessionController.GetStudyTime)
+	}
+}
@@ -0,0 +1,42 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+// SetupTimetableRoutes configures class session and weekly timetable routes
+func SetupTimetableRoutes(apiV1 *gin.RouterGroup) {
+
+	// Initialize dependencies
+	sessionRepo := repositories.NewClassSessionRepository(global.Mdb)
+	courseRepo := repositories.NewCourseRepository(global.Mdb)
+	userRepo := repositories.NewUserRepository(global.Mdb)
+	semesterRepo := repositories.NewSemesterRepository(global.Mdb)
+	timetableService := services.NewTimetableService(sessionRepo, courseRepo)
+	importService := services.NewTimetableImportService(userRepo, courseRepo, semesterRepo, sessionRepo, newQuotaService())
+	timetableController := controllers.NewTimetableController(timetableService, importService)
+
+	authMiddleware := middleware.NewAuthMiddleware(helper.NewJWTHelper())
+
+	// Timetable routes
+	timetable := apiV1.Group("/timetable", authMiddleware.Auth())
+	{
+		timetable.GET("", timetableController.GetTimetable)
+		timetable.POST("/import", timetableController.ImportTimetable)
+
+		sessions := timetable.Group("/sessions")
+		sessions.POST("", timetableController.CreateSession)
+		sessions.GET("", timetableController.ListSessions)
+		sessions.GET("/:id", timetableController.GetSession)
+		sessions.PUT("/:id", timetableController.UpdateSession)
+		sessions.DELETE("/:id", timetableController.DeleteSession)
+		sessions.POST("/:id/exceptions", timetableController.AddException)
+		sessions.DELETE("/:id/exceptions/:date", timetableController.RemoveException)
+	}
+}
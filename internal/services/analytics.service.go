@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/pkg/grading"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+// IAnalyticsService reports academic performance from the grades of the
+// user's courses. Aggregation happens in SQL; the service only fills in
+// defaults and the forecast ranges.
+type IAnalyticsService interface {
+	GetGPATrend(ctx context.Context, userID string) ([]models.SemesterGPA, int)
+	GetPerformance(ctx context.Context, userID string) (*models.PerformanceBreakdown, int)
+	GetCreditProgress(ctx context.Context, userID string) (*models.CreditProgress, int)
+	UpdateRequiredCredits(ctx context.Context, userID string, req *models.UpdateRequiredCreditsRequest) (*models.CreditProgress, int)
+	// GetForecasts projects the final grade of every ungraded course with scored assessments or exams
+	GetForecasts(ctx context.Context, userID string) ([]models.CourseForecast, int)
+}
+
+type AnalyticsService struct {
+	analyticsRepo repo.IAnalyticsRepository
+	userRepo      repo.IUserRepository
+}
+
+func NewAnalyticsService(analyticsRepository repo.IAnalyticsRepository, userRepository repo.IUserRepository) IAnalyticsService {
+	return &AnalyticsService{
+		analyticsRepo: analyticsRepository,
+		userRepo:      userRepository,
+	}
+}
+
+func (s *AnalyticsService) GetGPATrend(ctx context.Context, userID string) ([]models.SemesterGPA, int) {
+	semesters, err := s.analyticsRepo.SemesterGPA(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error aggregating semester GPA", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	if semesters == nil {
+		semesters = []models.SemesterGPA{}
+	}
+	return semesters, response.CodeSuccess
+}
+
+func (s *AnalyticsService) GetPerformance(ctx context.Context, userID string) (*models.PerformanceBreakdown, int) {
+	tags, err := s.analyticsRepo.TagPerformance(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error aggregating tag performance", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	subjects, err := s.analyticsRepo.SubjectPerformance(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error aggregating subject performance", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	breakdown := &models.PerformanceBreakdown{Tags: tags, Subjects: subjects}
+	if breakdown.Tags == nil {
+		breakdown.Tags = []models.GroupPerformance{}
+	}
+	if breakdown.Subjects == nil {
+		breakdown.Subjects = []models.GroupPerformance{}
+	}
+	return breakdown, response.CodeSuccess
+}
+
+func (s *AnalyticsService) GetCreditProgress(ctx context.Context, userID string) (*models.CreditProgress, int) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error getting user", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	now := time.Now().In(userLocation(user))
+	progress, err := s.analyticsRepo.CreditProgress(ctx, userID, consts.COURSE_PASSING_GPA, now)
+	if err != nil {
+		global.Log.Error("Error aggregating credits", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	progress.Required = consts.DEFAULT_REQUIRED_CREDITS
+	if user.RequiredCredits != nil {
+		progress.Required = *user.RequiredCredits
+	}
+	progress.Remaining = max(int64(progress.Required)-progress.Earned, 0)
+	progress.Percent = math.Min(math.Round(float64(progress.Earned)/float64(progress.Required)*1000)/10, 100)
+	return progress, response.CodeSuccess
+}
+
+func (s *AnalyticsService) UpdateRequiredCredits(ctx context.Context, userID string, req *models.UpdateRequiredCreditsRequest) (*models.CreditProgress, int) {
+	if err := s.userRepo.UpdateUser(ctx, userID, map[string]any{"required_credits": req.RequiredCredits}); err != nil {
+		global.Log.Error("Error updating required credits", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success updating required credits", zap.String("userID", userID))
+	return s.GetCreditProgress(ctx, userID)
+}
+
+func (s *AnalyticsService) GetForecasts(ctx context.Context, userID string) ([]models.CourseForecast, int) {
+	courses, err := s.analyticsRepo.CourseExamResults(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error aggregating exam results", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	forecasts := make([]models.CourseForecast, 0, len(courses))
+	for _, course := range courses {
+		forecast := grading.Project(grading.Results{
+			Weight: course.GradedWeight,
+			Mean:   course.MeanScore,
+			StdDev: course.ScoreStdDev,
+			Count:  course.Results,
+		})
+		forecasts = append(forecasts, models.CourseForecast{
+			CourseID:       course.CourseID,
+			CourseCode:     course.CourseCode,
+			CourseName:     course.CourseName,
+			Credits:        course.Credits,
+			GradedWeight:   roundScore(course.GradedWeight),
+			CurrentScore:   roundScore(course.MeanScore),
+			PredictedScore: roundScore(forecast.Predicted),
+			LowScore:       roundScore(forecast.Low),
+			HighScore:      roundScore(forecast.High),
+			MinScore:       roundScore(forecast.Min),
+			MaxScore:       roundScore(forecast.Max),
+			PredictedGPA:   grading.Points(forecast.Predicted),
+			LowGPA:         grading.Points(forecast.Low),
+			HighGPA:        grading.Points(forecast.High),
+			Confidence:     grading.Confidence,
+		})
+	}
+	return forecasts, response.CodeSuccess
+}
+
+// roundScore keeps one decimal of a percentage
+func roundScore(score float64) float64 {
+	return math.Round(score*10) / 10
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/grading"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// IAssessmentService manages the graded components of a course. A course with
+// assessments takes its GPA from them: the grade of the final result once every
+// assessment is scored, and 0 (ungraded) until then.
+type IAssessmentService interface {
+	GetAssessments(ctx context.Context, userID string, courseID int) (*models.AssessmentSummary, int)
+	// ReplaceAssessments sets the course's assessments, whose weights must add up to 100
+	ReplaceAssessments(ctx context.Context, userID string, courseID int, req *models.ReplaceAssessmentsRequest) (*models.AssessmentSummary, int)
+	RecordScore(ctx context.Context, userID string, courseID, id int, req *models.RecordAssessmentScoreRequest) (*models.AssessmentSummary, int)
+}
+
+type AssessmentService struct {
+	assessmentRepo repo.IAssessmentRepository
+	courseRepo     repo.ICourseRepository
+}
+
+func NewAssessmentService(assessmentRepository repo.IAssessmentRepository, courseRepository repo.ICourseRepository) IAssessmentService {
+	return &AssessmentService{
+		assessmentRepo: assessmentRepository,
+		courseRepo:     courseRepository,
+	}
+}
+
+func (s *AssessmentService) GetAssessments(ctx context.Context, userID string, courseID int) (*models.AssessmentSummary, int) {
+	course, code := s.getCourse(ctx, userID, courseID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	assessments, err := s.assessmentRepo.ListAssessments(ctx, courseID, userID)
+	if err != nil {
+		global.Log.Error("Error listing assessments", zap.Error(err), zap.Int("courseID", courseID))
+		return nil, response.CodeServerBusy
+	}
+	return summarizeAssessments(courseID, assessments, float64(course.GPA)), response.CodeSuccess
+}
+
+func (s *AssessmentService) ReplaceAssessments(ctx context.Context, userID string, courseID int, req *models.ReplaceAssessmentsRequest) (*models.AssessmentSummary, int) {
+	if _, code := s.getCourse(ctx, userID, courseID); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	total := 0.0
+	for _, input := range req.Assessments {
+		total += input.Weight
+		if input.Score != nil && *input.Score > input.MaxScore {
+			global.Log.Warn(errMessage.ErrInvalidAssessmentScore.Error(), zap.Float64("score", *input.Score), zap.Float64("maxScore", input.MaxScore))
+			return nil, response.CodeInvalidAssessmentScore
+		}
+	}
+	// An empty set is allowed and removes every assessment
+	if len(req.Assessments) > 0 && math.Abs(total-100) > consts.ASSESSMENT_WEIGHT_TOLERANCE {
+		global.Log.Warn(errMessage.ErrInvalidAssessmentWeights.Error(), zap.Float64("total", total), zap.Int("courseID", courseID))
+		return nil, response.CodeInvalidAssessmentWeights
+	}
+
+	existing, err := s.assessmentRepo.ListAssessments(ctx, courseID, userID)
+	if err != nil {
+		global.Log.Error("Error listing assessments", zap.Error(err), zap.Int("courseID", courseID))
+		return nil, response.CodeServerBusy
+	}
+	known := make(map[int]bool, len(existing))
+	for _, assessment := range existing {
+		known[assessment.ID] = true
+	}
+
+	var keepIDs []int
+	var created []models.Assessment
+	updated := map[int]map[string]any{}
+	assessments := make([]models.Assessment, 0, len(req.Assessments))
+	for _, input := range req.Assessments {
+		assessment := models.Assessment{
+			CourseID: courseID,
+			UserID:   userID,
+			Name:     strings.TrimSpace(input.Name),
+			Weight:   input.Weight,
+			MaxScore: input.MaxScore,
+		}
+		if input.Score != nil {
+			assessment.Score = sql.NullFloat64{Float64: *input.Score, Valid: true}
+		}
+		if input.DueDate != nil && *input.DueDate != "" {
+			dueDate, err := time.Parse(consts.DATE_LAYOUT, *input.DueDate)
+			if err != nil {
+				global.Log.Warn(errMessage.ErrInvalidAssessmentDueDate.Error(), zap.String("due_date", *input.DueDate))
+				return nil, response.CodeInvalidAssessmentDueDate
+			}
+			assessment.DueDate = sql.NullTime{Time: dueDate, Valid: true}
+		}
+		assessments = append(assessments, assessment)
+
+		if input.ID == nil {
+			created = append(created, assessment)
+			continue
+		}
+		// Each existing assessment can be kept once; anything else is not the course's
+		if !known[*input.ID] || updated[*input.ID] != nil {
+			global.Log.Warn(errMessage.ErrAssessmentNotFound.Error(), zap.Int("assessmentID", *input.ID), zap.Int("courseID", courseID))
+			return nil, response.CodeAssessmentNotFound
+		}
+		keepIDs = append(keepIDs, *input.ID)
+		updated[*input.ID] = map[string]any{
+			"name":      assessment.Name,
+			"weight":    assessment.Weight,
+			"max_score": assessment.MaxScore,
+			"score":     assessment.Score,
+			"due_date":  assessment.DueDate,
+		}
+	}
+
+	err = s.assessmentRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		assessmentRepo := s.assessmentRepo.WithTx(tx)
+		if err := assessmentRepo.DeleteAssessments(ctx, courseID, userID, keepIDs); err != nil {
+			return err
+		}
+		for id, updates := range updated {
+			if err := assessmentRepo.UpdateAssessment(ctx, id, userID, updates); err != nil {
+				return err
+			}
+		}
+		if err := assessmentRepo.CreateAssessments(ctx, created); err != nil {
+			return err
+		}
+		return s.syncCourseGPA(ctx, s.courseRepo.WithTx(tx), userID, courseID, assessments)
+	})
+	if err != nil {
+		global.Log.Error("Error replacing assessments", zap.Error(err), zap.Int("courseID", courseID))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success replacing assessments", zap.Int("courseID", courseID), zap.Int("assessments", len(assessments)))
+	return s.GetAssessments(ctx, userID, courseID)
+}
+
+func (s *AssessmentService) RecordScore(ctx context.Context, userID string, courseID, id int, req *models.RecordAssessmentScoreRequest) (*models.AssessmentSummary, int) {
+	if _, code := s.getCourse(ctx, userID, courseID); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	assessment, err := s.assessmentRepo.GetAssessmentByID(ctx, id, courseID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrAssessmentNotFound.Error(), zap.Int("assessmentID", id), zap.Int("courseID", courseID))
+			return nil, response.CodeAssessmentNotFound
+		}
+
+		global.Log.Error("Error getting assessment", zap.Error(err), zap.Int("assessmentID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	score := sql.NullFloat64{}
+	if req.Score != nil {
+		if *req.Score > assessment.MaxScore {
+			global.Log.Warn(errMessage.ErrInvalidAssessmentScore.Error(), zap.Float64("score", *req.Score), zap.Float64("maxScore", assessment.MaxScore))
+			return nil, response.CodeInvalidAssessmentScore
+		}
+		score = sql.NullFloat64{Float64: *req.Score, Valid: true}
+	}
+
+	err = s.assessmentRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		assessmentRepo := s.assessmentRepo.WithTx(tx)
+		if err := assessmentRepo.UpdateAssessment(ctx, id, userID, map[string]any{"score": score}); err != nil {
+			return err
+		}
+		assessments, err := assessmentRepo.ListAssessments(ctx, courseID, userID)
+		if err != nil {
+			return err
+		}
+		return s.syncCourseGPA(ctx, s.courseRepo.WithTx(tx), userID, courseID, assessments)
+	})
+	if err != nil {
+		global.Log.Error("Error recording assessment score", zap.Error(err), zap.Int("assessmentID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success recording assessment score", zap.Int("assessmentID", id), zap.Int("courseID", courseID))
+	return s.GetAssessments(ctx, userID, courseID)
+}
+
+func (s *AssessmentService) getCourse(ctx context.Context, userID string, courseID int) (*models.Course, int) {
+	course, err := s.courseRepo.GetCourseByID(ctx, courseID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrCourseNotFound.Error(), zap.Int("courseID", courseID))
+			return nil, response.CodeCourseNotFound
+		}
+
+		global.Log.Error("Error getting course", zap.Error(err), zap.Int("courseID", courseID))
+		return nil, response.CodeServerBusy
+	}
+	return course, response.CodeSuccess
+}
+
+// syncCourseGPA sets the course's GPA from its assessments. A course without
+// assessments keeps the GPA it was given by hand or by import.
+func (s *AssessmentService) syncCourseGPA(ctx context.Context, courseRepo repo.ICourseRepository, userID string, courseID int, assessments []models.Assessment) error {
+	if len(assessments) == 0 {
+		return nil
+	}
+
+	gpa := 0.0
+	if final := finalGrade(assessments); final != nil {
+		gpa = grading.Points(*final)
+	}
+	return courseRepo.UpdateCourse(ctx, courseID, userID, map[string]any{"gpa": gpa})
+}
+
+// assessmentComponents converts scores to percentages of the max score
+func assessmentComponents(assessments []models.Assessment) []grading.Component {
+	components := make([]grading.Component, 0, len(assessments))
+	for _, assessment := range assessments {
+		component := grading.Component{Weight: assessment.Weight}
+		if assessment.Score.Valid {
+			percent := assessment.Score.Float64 / assessment.MaxScore * 100
+			component.Score = &percent
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// finalGrade is the course's final grade in percent, nil while any assessment is ungraded
+func finalGrade(assessments []models.Assessment) *float64 {
+	for _, assessment := range assessments {
+		if !assessment.Score.Valid {
+			return nil
+		}
+	}
+	final := grading.Tally(assessmentComponents(assessments)).Secured
+	return &final
+}
+
+func summarizeAssessments(courseID int, assessments []models.Assessment, gpa float64) *models.AssessmentSummary {
+	if assessments == nil {
+		assessments = []models.Assessment{}
+	}
+
+	standing := grading.Tally(assessmentComponents(assessments))
+	summary := &models.AssessmentSummary{
+		CourseID:     courseID,
+		Assessments:  assessments,
+		GradedWeight: roundScore(standing.GradedWeight),
+		SecuredGrade: roundScore(standing.Secured),
+		GPA:          gpa,
+		Targets:      []models.AssessmentGradeTarget{},
+	}
+	if len(assessments) == 0 {
+		return summary
+	}
+
+	summary.RemainingWeight = roundScore(math.Max(100-standing.GradedWeight, 0))
+	if standing.GradedWeight > 0 {
+		current := roundScore(standing.Current)
+		summary.CurrentGrade = &current
+	}
+	if final := finalGrade(assessments); final != nil {
+		rounded := roundScore(*final)
+		summary.FinalGrade = &rounded
+	}
+
+	for _, step := range grading.Scale() {
+		target := models.AssessmentGradeTarget{GPA: step.Points, MinPercent: step.Percent}
+		if needed, ok := grading.Needed(standing, step.Percent); ok {
+			// Round up so scoring the figure shown is always enough
+			needed = math.Ceil(needed*10) / 10
+			target.Needed = &needed
+			target.Secured = needed == 0
+		}
+		summary.Targets = append(summary.Targets, target)
+	}
+	return summary
+}
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/pkg/ai"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type IAssistantService interface {
+	CreateConversation(ctx context.Context, userID string, req *models.CreateConversationRequest) (*models.AssistantConversation, int)
+	ListConversations(ctx context.Context, userID string) ([]models.AssistantConversation, int)
+	// GetConversation returns the conversation with all of its messages
+	GetConversation(ctx context.Context, userID string, id int) (*models.AssistantConversation, int)
+	DeleteConversation(ctx context.Context, userID string, id int) int
+	// SendMessage answers a question from the user's material. With onDelta
+	// the answer is streamed through it as it is generated.
+	SendMessage(ctx context.Context, userID string, id int, req *models.SendMessageRequest, onDelta func(delta string) error) (*models.AssistantReply, int)
+}
+
+type AssistantService struct {
+	assistantRepo repo.IAssistantRepository
+	vectorStore   repo.IVectorStore
+	courseRepo    repo.ICourseRepository
+	usageRepo     repo.IUsageRepository
+	quotaService  IQuotaService
+	provider      ai.Provider
+}
+
+func NewAssistantService(assistantRepository repo.IAssistantRepository, vectorStore repo.IVectorStore, courseRepository repo.ICourseRepository,
+	usageRepository repo.IUsageRepository, quotaService IQuotaService, provider ai.Provider) IAssistantService {
+	return &AssistantService{
+		assistantRepo: assistantRepository,
+		vectorStore:   vectorStore,
+		courseRepo:    courseRepository,
+		usageRepo:     usageRepository,
+		quotaService:  quotaService,
+		provider:      provider,
+	}
+}
+
+func (s *AssistantService) CreateConversation(ctx context.Context, userID string, req *models.CreateConversationRequest) (*models.AssistantConversation, int) {
+	if req.CourseID != nil {
+		if _, err := s.courseRepo.GetCourseByID(ctx, *req.CourseID, userID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				global.Log.Warn(errMessage.ErrCourseNotFound.Error(), zap.String("userID", userID), zap.Int("courseID", *req.CourseID))
+				return nil, response.CodeCourseNotFound
+			}
+			global.Log.Error("Error getting course", zap.Error(err), zap.Int("courseID", *req.CourseID))
+			return nil, response.CodeServerBusy
+		}
+	}
+
+	conversation := &models.AssistantConversation{
+		UserID:   userID,
+		CourseID: req.CourseID,
+		Title:    strings.TrimSpace(req.Title),
+	}
+	if err := s.assistantRepo.CreateConversation(ctx, conversation); err != nil {
+		global.Log.Error("Error creating conversation", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	return conversation, response.CodeSuccess
+}
+
+func (s *AssistantService) ListConversations(ctx context.Context, userID string) ([]models.AssistantConversation, int) {
+	conversations, err := s.assistantRepo.ListConversations(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error listing conversations", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	return conversations, response.CodeSuccess
+}
+
+func (s *AssistantService) GetConversation(ctx context.Context, userID string, id int) (*models.AssistantConversation, int) {
+	conversation, code := s.getConversation(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	messages, err := s.assistantRepo.ListMessages(ctx, conversation.ID, 0)
+	if err != nil {
+		global.Log.Error("Error listing messages", zap.Error(err), zap.Int("conversationID", id))
+		return nil, response.CodeServerBusy
+	}
+	conversation.Messages = messages
+	return conversation, response.CodeSuccess
+}
+
+func (s *AssistantService) DeleteConversation(ctx context.Context, userID string, id int) int {
+	if err := s.assistantRepo.DeleteConversation(ctx, id, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrConversationNotFound.Error(), zap.String("userID", userID), zap.Int("conversationID", id))
+			return response.CodeConversationNotFound
+		}
+
+		global.Log.Error("Error deleting conversation", zap.Error(err), zap.Int("conversationID", id))
+		return response.CodeServerBusy
+	}
+
+	global.Log.Info("Success deleting conversation", zap.String("userID", userID), zap.Int("conversationID", id))
+	return response.CodeSuccess
+}
+
+// SendMessage retrieves the chunks of the user's notes and files closest to
+// the question, scoped to the conversation's course, and asks the model to
+// answer from them with [n] citations. The question is stored first, so it
+// stays in the history even when answering fails.
+func (s *AssistantService) SendMessage(ctx context.Context, userID string, id int, req *models.SendMessageRequest, onDelta func(delta string) error) (*models.AssistantReply, int) {
+	conversation, code := s.getConversation(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	reservation, code := s.quotaService.ReserveAIOperation(ctx, userID, consts.UsageOperation.ASSISTANT_MESSAGE)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	answered := false
+	defer func() {
+		// A client that disconnects cancels ctx, the reservation is given back regardless
+		if !answered {
+			s.quotaService.ReleaseAIOperation(context.WithoutCancel(ctx), reservation)
+		}
+	}()
+
+	history, err := s.assistantRepo.ListMessages(ctx, conversation.ID, consts.ASSISTANT_HISTORY_MESSAGES)
+	if err != nil {
+		global.Log.Error("Error listing messages", zap.Error(err), zap.Int("conversationID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	question := strings.TrimSpace(req.Content)
+	matches, err := s.retrieve(ctx, userID, conversation.CourseID, question)
+	if err != nil {
+		global.Log.Error("Error retrieving sources", zap.Error(err), zap.Int("conversationID", id))
+		return nil, response.CodeAssistantReplyFailed
+	}
+
+	reply := &models.AssistantReply{
+		Question: models.AssistantMessage{
+			ConversationID: conversation.ID,
+			UserID:         userID,
+			Role:           consts.AssistantRole.USER,
+			Content:        question,
+		},
+	}
+	if err := s.assistantRepo.CreateMessage(ctx, &reply.Question); err != nil {
+		global.Log.Error("Error creating message", zap.Error(err), zap.Int("conversationID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	chat := ai.ChatRequest{
+		System:    fmt.Sprintf(consts.ASSISTANT_PROMPT, formatSources(matches)),
+		MaxTokens: consts.ASSISTANT_MAX_OUTPUT_TOKENS,
+	}
+	for _, message := range history {
+		chat.Messages = append(chat.Messages, ai.Message{Role: ai.Role(message.Role), Content: message.Content})
+	}
+	chat.Messages = append(chat.Messages, ai.Message{Role: ai.RoleUser, Content: question})
+
+	var resp *ai.ChatResponse
+	if onDelta != nil {
+		resp, err = s.provider.Stream(ctx, chat, onDelta)
+	} else {
+		resp, err = s.provider.Chat(ctx, chat)
+	}
+	if err != nil {
+		global.Log.Error("Error generating assistant reply", zap.Error(err), zap.Int("conversationID", id))
+		return nil, response.CodeAssistantReplyFailed
+	}
+
+	reply.Citations = citeSources(resp.Content, matches)
+	citations, _ := json.Marshal(reply.Citations)
+	reply.Answer = models.AssistantMessage{
+		ConversationID: conversation.ID,
+		UserID:         userID,
+		Role:           consts.AssistantRole.ASSISTANT,
+		Content:        resp.Content,
+		Citations:      citations,
+		Provider:       s.provider.Name(),
+		Model:          resp.Model,
+		InputTokens:    resp.Usage.InputTokens,
+		OutputTokens:   resp.Usage.OutputTokens,
+	}
+	if err := s.assistantRepo.CreateMessage(ctx, &reply.Answer); err != nil {
+		global.Log.Error("Error creating message", zap.Error(err), zap.Int("conversationID", id))
+		return nil, response.CodeServerBusy
+	}
+	answered = true
+	recordUsage(ctx, s.usageRepo, models.UsageRecord{
+		ID:           reservation,
+		UserID:       userID,
+		Operation:    consts.UsageOperation.ASSISTANT_MESSAGE,
+		Reference:    fmt.Sprintf("assistant_message:%d", reply.Answer.ID),
+		Provider:     reply.Answer.Provider,
+		Model:        reply.Answer.Model,
+		InputTokens:  reply.Answer.InputTokens,
+		OutputTokens: reply.Answer.OutputTokens,
+	})
+
+	// Name untitled conversations after their first question; the update also marks the conversation active
+	updates := map[string]any{}
+	if conversation.Title == "" {
+		updates["title"] = truncate(question, consts.ASSISTANT_TITLE_RUNES)
+	}
+	if err := s.assistantRepo.UpdateConversation(ctx, conversation.ID, userID, updates); err != nil {
+		global.Log.Error("Error updating conversation", zap.Error(err), zap.Int("conversationID", id))
+	}
+
+	global.Log.Info("Assistant replied", zap.Int("conversationID", id), zap.Int("sources", len(matches)), zap.Int("citations", len(reply.Citations)))
+	return reply, response.CodeSuccess
+}
+
+// retrieve embeds the question and searches the vector store. Without an
+// embeddings provider the assistant answers without sources.
+func (s *AssistantService) retrieve(ctx context.Context, userID string, courseID *int, question string) ([]models.VectorMatch, error) {
+	resp, err := s.provider.Embed(ctx, ai.EmbeddingRequest{Input: []string{question}})
+	if errors.Is(err, ai.ErrUnsupported) {
+		global.Log.Warn("AI provider has no embeddings, answering without sources", zap.String("provider", s.provider.Name()))
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Vectors) != 1 {
+		return nil, fmt.Errorf("%w: %d vectors for one input", ai.ErrInvalidOutput, len(resp.Vectors))
+	}
+
+	return s.vectorStore.Search(ctx, models.VectorQuery{
+		UserID:   userID,
+		CourseID: courseID,
+		Model:    resp.Model,
+		Vector:   resp.Vectors[0],
+		TopK:     consts.ASSISTANT_TOP_K,
+		MinScore: consts.ASSISTANT_MIN_SCORE,
+	})
+}
+
+func (s *AssistantService) getConversation(ctx context.Context, userID string, id int) (*models.AssistantConversation, int) {
+	conversation, err := s.assistantRepo.GetConversationByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrConversationNotFound.Error(), zap.String("userID", userID), zap.Int("conversationID", id))
+			return nil, response.CodeConversationNotFound
+		}
+		global.Log.Error("Error getting conversation", zap.Error(err), zap.Int("conversationID", id))
+		return nil, response.CodeServerBusy
+	}
+	return conversation, response.CodeSuccess
+}
+
+// formatSources numbers the retrieved chunks for the prompt, starting at 1
+func formatSources(matches []models.VectorMatch) string {
+	if len(matches) == 0 {
+		return "(no relevant sources found)"
+	}
+	var b strings.Builder
+	for i, match := range matches {
+		fmt.Fprintf(&b, "[%d] %s", i+1, match.Title)
+		if match.PageStart != nil {
+			if match.PageEnd != nil && *match.PageEnd != *match.PageStart {
+				fmt.Fprintf(&b, " (pages %d-%d)", *match.PageStart, *match.PageEnd)
+			} else {
+				fmt.Fprintf(&b, " (page %d)", *match.PageStart)
+			}
+		}
+		fmt.Fprintf(&b, "\n%s\n\n", match.Content)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+var citationPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// citeSources returns the sources the answer cites with [n], in number order.
+// Numbers that match no source are ignored.
+func citeSources(answer string, matches []models.VectorMatch) []models.Citation {
+	var numbers []int
+	for _, found := range citationPattern.FindAllStringSubmatch(answer, -1) {
+		n, err := strconv.Atoi(found[1])
+		if err != nil || n < 1 || n > len(matches) || slices.Contains(numbers, n) {
+			continue
+		}
+		numbers = append(numbers, n)
+	}
+	slices.Sort(numbers)
+
+	citations := make([]models.Citation, len(numbers))
+	for i, n := range numbers {
+		match := matches[n-1]
+		citations[i] = models.Citation{
+			Number:     n,
+			NoteID:     match.NoteID,
+			FileID:     match.FileID,
+			Title:      match.Title,
+			ChunkIndex: match.ChunkIndex,
+			PageStart:  match.PageStart,
+			PageEnd:    match.PageEnd,
+			Snippet:    truncate(match.Content, consts.ASSISTANT_SNIPPET_RUNES),
+			Score:      match.Score,
+		}
+	}
+	return citations
+}
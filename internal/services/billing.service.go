@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/queue"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/pkg/billing"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// IBillingService sells plans through the billing provider. The provider's
+// webhooks are the source of truth: checkout only starts a payment, and the
+// subscription row and users.tier change when the matching event arrives.
+type IBillingService interface {
+	CreateCheckout(ctx context.Context, userID string, req *models.CreateCheckoutRequest) (*models.CheckoutResponse, int)
+	// GetSubscription returns nil without an error for users who never subscribed
+	GetSubscription(ctx context.Context, userID string) (*models.Subscription, int)
+	// HandleWebhook verifies and applies one webhook. Redelivered and outdated
+	// events are acknowledged without changing anything.
+	HandleWebhook(ctx context.Context, payload []byte, header http.Header) int
+
+	// DowngradeExpired moves the user to the free plan if the grace period of
+	// their subscription is over; it runs as a delayed job
+	DowngradeExpired(ctx context.Context, payload models.BillingDowngradePayload) error
+}
+
+type BillingService struct {
+	billingRepo repo.IBillingRepository
+	planRepo    repo.IPlanRepository
+	userRepo    repo.IUserRepository
+	provider    billing.Provider
+	jobs        IJobQueue
+}
+
+func NewBillingService(billingRepository repo.IBillingRepository, planRepository repo.IPlanRepository, userRepository repo.IUserRepository,
+	provider billing.Provider, jobs IJobQueue) IBillingService {
+	return &BillingService{
+		billingRepo: billingRepository,
+		planRepo:    planRepository,
+		userRepo:    userRepository,
+		provider:    provider,
+		jobs:        jobs,
+	}
+}
+
+func (s *BillingService) CreateCheckout(ctx context.Context, userID string, req *models.CreateCheckoutRequest) (*models.CheckoutResponse, int) {
+	plan, err := s.planRepo.GetPlan(ctx, req.PlanCode)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrPlanNotFound.Error(), zap.String("plan", req.PlanCode))
+			return nil, response.CodePlanNotPurchasable
+		}
+
+		global.Log.Error("Error getting plan", zap.Error(err), zap.String("plan", req.PlanCode))
+		return nil, response.CodeServerBusy
+	}
+	if plan.PriceCents <= 0 {
+		global.Log.Warn(errMessage.ErrPlanNotPurchasable.Error(), zap.String("plan", plan.Code))
+		return nil, response.CodePlanNotPurchasable
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrUserNotFound.Error(), zap.String("userID", userID))
+			return nil, response.CodeUserNotFound
+		}
+
+		global.Log.Error("Error getting user", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	subscription, err := s.planRepo.GetSubscriptionByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		global.Log.Error("Error getting subscription", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	// Plan changes of a live subscription go through the provider, not a second checkout
+	if subscription != nil && subscription.Status != consts.SubscriptionStatus.CANCELED {
+		global.Log.Warn(errMessage.ErrSubscriptionExists.Error(), zap.String("userID", userID), zap.String("status", subscription.Status))
+		return nil, response.CodeSubscriptionExists
+	}
+
+	cfg := global.Config.Billing
+	checkout := billing.CheckoutRequest{
+		UserID:     userID,
+		Email:      user.Email,
+		PlanCode:   plan.Code,
+		SuccessURL: cfg.SuccessURL,
+		CancelURL:  cfg.CancelURL,
+	}
+	if subscription == nil {
+		// Only first-time subscribers get a trial
+		checkout.TrialDays = cfg.TrialDays
+	} else {
+		checkout.CustomerID = subscription.ProviderCustomerID
+	}
+
+	session, err := s.provider.CreateCheckoutSession(ctx, checkout)
+	if err != nil {
+		if errors.Is(err, billing.ErrUnknownPlan) {
+			global.Log.Warn(errMessage.ErrPlanNotPurchasable.Error(), zap.String("plan", plan.Code), zap.String("provider", s.provider.Name()))
+			return nil, response.CodePlanNotPurchasable
+		}
+
+		global.Log.Error("Error creating checkout session", zap.Error(err), zap.String("userID", userID), zap.String("plan", plan.Code))
+		return nil, response.CodeCheckoutFailed
+	}
+
+	global.Log.Info("Success creating checkout session", zap.String("userID", userID), zap.String("plan", plan.Code), zap.String("sessionID", session.ID))
+	return &models.CheckoutResponse{SessionID: session.ID, URL: session.URL, ExpiresAt: session.ExpiresAt}, response.CodeSuccess
+}
+
+func (s *BillingService) GetSubscription(ctx context.Context, userID string) (*models.Subscription, int) {
+	subscription, err := s.planRepo.GetSubscriptionByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.CodeSuccess
+		}
+
+		global.Log.Error("Error getting subscription", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	return subscription, response.CodeSuccess
+}
+
+func (s *BillingService) HandleWebhook(ctx context.Context, payload []byte, header http.Header) int {
+	provider := s.provider.Name()
+	event, err := s.provider.ParseEvent(payload, header)
+	if err != nil {
+		global.Log.Warn(errMessage.ErrInvalidWebhook.Error(), zap.Error(err), zap.String("provider", provider))
+		return response.CodeInvalidWebhook
+	}
+
+	err = s.billingRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		billingRepo := s.billingRepo.WithTx(tx)
+
+		subscription, err := s.matchSubscription(ctx, billingRepo, event)
+		if err != nil {
+			return err
+		}
+
+		// The event is stored even when it changes nothing, so its redelivery is a no-op too
+		record := &models.BillingEvent{Provider: provider, EventID: event.ID, Type: event.Type, Payload: string(payload)}
+		if subscription != nil {
+			record.UserID = &subscription.UserID
+		}
+		if err := billingRepo.CreateEvent(ctx, record); err != nil {
+			return err
+		}
+		if subscription == nil {
+			return nil
+		}
+		if subscription.LastEventAt != nil && event.CreatedAt.Before(*subscription.LastEventAt) {
+			global.Log.Info("Ignoring outdated billing event", zap.String("eventID", event.ID), zap.String("userID", subscription.UserID))
+			return nil
+		}
+
+		tier := s.applyEvent(subscription, event)
+		if err := billingRepo.SaveSubscription(ctx, subscription); err != nil {
+			return err
+		}
+		if err := s.userRepo.WithTx(tx).UpdateUser(ctx, subscription.UserID, map[string]any{"tier": tier}); err != nil {
+			return err
+		}
+
+		// Queued inside the transaction: if this fails the provider redelivers the
+		// event, and a job left behind by a rolled back transaction rechecks the row
+		if subscription.GraceEndsAt != nil && tier != consts.UserTier.FREE {
+			return s.scheduleDowngrade(ctx, subscription)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			global.Log.Info("Ignoring redelivered billing event", zap.String("eventID", event.ID), zap.String("provider", provider))
+			return response.CodeSuccess
+		}
+
+		global.Log.Error("Error applying billing event", zap.Error(err), zap.String("eventID", event.ID), zap.String("type", event.Type))
+		return response.CodeServerBusy
+	}
+
+	global.Log.Info("Success applying billing event", zap.String("eventID", event.ID), zap.String("type", event.Type))
+	return response.CodeSuccess
+}
+
+// matchSubscription finds the row an event applies to, or starts a new one for
+// the user named in the checkout. It returns nil for events that change no
+// subscription we know of.
+func (s *BillingService) matchSubscription(ctx context.Context, billingRepo repo.IBillingRepository, event *billing.Event) (*models.Subscription, error) {
+	data := event.Subscription
+	if data == nil || data.Status == "" {
+		return nil, nil
+	}
+
+	if _, err := s.planRepo.GetPlan(ctx, data.PlanCode); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrPlanNotFound.Error(), zap.String("eventID", event.ID), zap.String("plan", data.PlanCode))
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	subscription, err := billingRepo.GetSubscriptionByProviderID(ctx, s.provider.Name(), data.ID)
+	if err == nil {
+		return subscription, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if data.UserID == "" {
+		global.Log.Warn(errMessage.ErrBillingEventUnmatched.Error(), zap.String("eventID", event.ID), zap.String("subscription", data.ID))
+		return nil, nil
+	}
+	subscription, err = billingRepo.GetSubscriptionByUserID(ctx, data.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.Subscription{UserID: data.UserID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// A user has one row: a new subscription takes it over once the previous one
+	// is canceled, but late events of an old subscription must not overwrite a live one
+	if subscription.ProviderSubscriptionID != nil && subscription.Status != consts.SubscriptionStatus.CANCELED {
+		global.Log.Warn(errMessage.ErrBillingEventUnmatched.Error(), zap.String("eventID", event.ID), zap.String("subscription", data.ID),
+			zap.String("userID", data.UserID))
+		return nil, nil
+	}
+	return subscription, nil
+}
+
+// applyEvent copies the provider's state onto the row and returns the tier the
+// user should have. Past-due subscriptions keep their plan for the grace period
+// and canceled ones until the end of the period already paid for.
+func (s *BillingService) applyEvent(subscription *models.Subscription, event *billing.Event) string {
+	data := event.Subscription
+	previous := subscription.Status
+
+	subscription.Provider = s.provider.Name()
+	subscription.ProviderSubscriptionID = &data.ID
+	if data.CustomerID != "" {
+		subscription.ProviderCustomerID = data.CustomerID
+	}
+	subscription.PlanCode = data.PlanCode
+	subscription.Status = data.Status
+	subscription.CurrentPeriodStart = data.CurrentPeriodStart
+	subscription.CurrentPeriodEnd = data.CurrentPeriodEnd
+	subscription.TrialEndsAt = data.TrialEnd
+	subscription.CancelAtPeriodEnd = data.CancelAtPeriodEnd
+	subscription.CanceledAt = data.CanceledAt
+	subscription.LastEventAt = &event.CreatedAt
+
+	switch data.Status {
+	case consts.SubscriptionStatus.PAST_DUE:
+		// Further retries of a failed payment keep the original deadline
+		if previous != consts.SubscriptionStatus.PAST_DUE || subscription.GraceEndsAt == nil {
+			graceEnd := event.CreatedAt.Add(gracePeriod())
+			subscription.GraceEndsAt = &graceEnd
+		}
+	case consts.SubscriptionStatus.CANCELED:
+		graceEnd := data.CurrentPeriodEnd
+		// An unpaid period was never paid for, so the past-due deadline still applies
+		if previous == consts.SubscriptionStatus.PAST_DUE && subscription.GraceEndsAt != nil && subscription.GraceEndsAt.Before(graceEnd) {
+			graceEnd = *subscription.GraceEndsAt
+		}
+		subscription.GraceEndsAt = &graceEnd
+	default:
+		subscription.GraceEndsAt = nil
+	}
+
+	if subscription.GraceEndsAt != nil && !time.Now().Before(*subscription.GraceEndsAt) {
+		return consts.UserTier.FREE
+	}
+	return subscription.PlanCode
+}
+
+func (s *BillingService) scheduleDowngrade(ctx context.Context, subscription *models.Subscription) error {
+	if s.jobs == nil {
+		return nil
+	}
+	graceEnd := *subscription.GraceEndsAt
+	payload := models.BillingDowngradePayload{SubscriptionID: subscription.ID, UserID: subscription.UserID}
+	// One job per deadline, so redelivered events replace the job instead of adding one
+	jobID := fmt.Sprintf("billing-downgrade:%d:%d", subscription.ID, graceEnd.Unix())
+	_, err := s.jobs.Enqueue(ctx, consts.JobType.BILLING_DOWNGRADE, payload, queue.WithDelay(time.Until(graceEnd)), queue.WithJobID(jobID))
+	return err
+}
+
+func (s *BillingService) DowngradeExpired(ctx context.Context, payload models.BillingDowngradePayload) error {
+	downgraded := false
+	err := s.billingRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		subscription, err := s.billingRepo.WithTx(tx).GetSubscriptionByUserID(ctx, payload.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		// The subscription was paid, renewed or replaced since the job was queued
+		if subscription.ID != payload.SubscriptionID || subscription.GraceEndsAt == nil || time.Now().Before(*subscription.GraceEndsAt) {
+			return nil
+		}
+
+		downgraded = true
+		return s.userRepo.WithTx(tx).UpdateUser(ctx, payload.UserID, map[string]any{"tier": consts.UserTier.FREE})
+	})
+	if err != nil {
+		global.Log.Error("Error downgrading subscription", zap.Error(err), zap.String("userID", payload.UserID), zap.Int("subscriptionID", payload.SubscriptionID))
+		return err
+	}
+
+	if downgraded {
+		global.Log.Info("Success downgrading user to the free plan", zap.String("userID", payload.UserID), zap.Int("subscriptionID", payload.SubscriptionID))
+	}
+	return nil
+}
+
+func gracePeriod() time.Duration {
+	if global.Config.Billing.GracePeriod > 0 {
+		return time.Duration(global.Config.Billing.GracePeriod) * 24 * time.Hour
+	}
+	return consts.BILLING_DEFAULT_GRACE_PERIOD
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/utils"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/ical"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ICalendarService interface {
+	// GetFeed returns the user's subscription URL, creating the feed on first use
+	GetFeed(ctx context.Context, userID string) (*models.CalendarFeedResponse, int)
+	// RotateFeedToken replaces the feed token, revoking every previously shared URL
+	RotateFeedToken(ctx context.Context, userID string) (*models.CalendarFeedResponse, int)
+	UpdateTimezone(ctx context.Context, userID string, timezone string) (*models.CalendarFeedResponse, int)
+
+	ExportCalendar(ctx context.Context, userID string) ([]byte, int)
+	ExportFeed(ctx context.Context, token string) ([]byte, int)
+}
+
+type CalendarService struct {
+	calendarRepo     repo.ICalendarRepository
+	userRepo         repo.IUserRepository
+	sessionRepo      repo.IClassSessionRepository
+	reminderRepo     repo.IReminderRepository
+	notificationRepo repo.INotificationRepository
+}
+
+func NewCalendarService(calendarRepository repo.ICalendarRepository, userRepository repo.IUserRepository, sessionRepository repo.IClassSessionRepository,
+	reminderRepository repo.IReminderRepository, notificationRepository repo.INotificationRepository) ICalendarService {
+	return &CalendarService{
+		calendarRepo:     calendarRepository,
+		userRepo:         userRepository,
+		sessionRepo:      sessionRepository,
+		reminderRepo:     reminderRepository,
+		notificationRepo: notificationRepository,
+	}
+}
+
+func (s *CalendarService) GetFeed(ctx context.Context, userID string) (*models.CalendarFeedResponse, int) {
+	feed, err := s.calendarRepo.GetFeedByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return s.RotateFeedToken(ctx, userID)
+		}
+
+		global.Log.Error("Error getting calendar feed", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	return s.feedResponse(ctx, feed)
+}
+
+func (s *CalendarService) RotateFeedToken(ctx context.Context, userID string) (*models.CalendarFeedResponse, int) {
+	token, err := utils.GenerateToken(consts.CALENDAR_TOKEN_BYTES)
+	if err != nil {
+		global.Log.Error("Error generating calendar token", zap.Error(err))
+		return nil, response.CodeServerBusy
+	}
+
+	feed := &models.CalendarFeed{UserID: userID, Token: token}
+	if err := s.calendarRepo.UpsertFeed(ctx, feed); err != nil {
+		global.Log.Error("Error saving calendar feed", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success rotating calendar feed token", zap.String("userID", userID))
+	feed.UpdatedAt = time.Now().UTC()
+	return s.feedResponse(ctx, feed)
+}
+
+// UpdateTimezone changes the timezone deadlines are read in. Every deadline moves with it, so
+// unsent notifications are dropped to be rebuilt for the new deadlines and reminder statuses
+// are derived again.
+func (s *CalendarService) UpdateTimezone(ctx context.Context, userID string, timezone string) (*models.CalendarFeedResponse, int) {
+	timezone = strings.TrimSpace(timezone)
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "" || strings.EqualFold(timezone, "Local") {
+		global.Log.Warn(errMessage.ErrInvalidTimezone.Error(), zap.String("userID", userID), zap.String("timezone", timezone))
+		return nil, response.CodeCalendarInvalidTimezone
+	}
+
+	err = s.userRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := s.userRepo.WithTx(tx).UpdateUser(ctx, userID, map[string]any{"timezone": timezone}); err != nil {
+			return fmt.Errorf("update timezone: %w", err)
+		}
+		if err := s.notificationRepo.WithTx(tx).DeleteUnsentByUser(ctx, userID); err != nil {
+			return fmt.Errorf("delete unsent notifications: %w", err)
+		}
+		if err := s.reminderRepo.WithTx(tx).RefreshReminderStatuses(ctx, userID, loc, time.Now()); err != nil {
+			return fmt.Errorf("refresh reminder statuses: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		global.Log.Error("Error updating user timezone", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	return s.GetFeed(ctx, userID)
+}
+
+// ExportCalendar renders the authenticated user's calendar
+func (s *CalendarService) ExportCalendar(ctx context.Context, userID string) ([]byte, int) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.CodeUserNotFound
+		}
+
+		global.Log.Error("Error getting user", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	sessions, err := s.sessionRepo.ListSessions(ctx, userID, nil)
+	if err != nil {
+		global.Log.Error("Error listing class sessions", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	reminders, err := s.reminderRepo.ListReminders(ctx, models.ReminderFilter{UserID: userID})
+	if err != nil {
+		global.Log.Error("Error listing reminders", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	calendar := BuildCalendar(userLocation(user), sessions, reminders, time.Now().UTC())
+	return calendar.Encode(), response.CodeSuccess
+}
+
+// ExportFeed renders the calendar of the feed identified by its secret token
+func (s *CalendarService) ExportFeed(ctx context.Context, token string) ([]byte, int) {
+	feed, err := s.calendarRepo.GetFeedByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrCalendarFeedNotFound.Error())
+			return nil, response.CodeCalendarFeedNotFound
+		}
+
+		global.Log.Error("Error getting calendar feed", zap.Error(err))
+		return nil, response.CodeServerBusy
+	}
+
+	return s.ExportCalendar(ctx, feed.UserID)
+}
+
+func (s *CalendarService) feedResponse(ctx context.Context, feed *models.CalendarFeed) (*models.CalendarFeedResponse, int) {
+	user, err := s.userRepo.GetUserByID(ctx, feed.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.CodeUserNotFound
+		}
+
+		global.Log.Error("Error getting user", zap.Error(err), zap.String("userID", feed.UserID))
+		return nil, response.CodeServerBusy
+	}
+
+	baseURL := strings.TrimSuffix(global.Config.Calendar.FeedBaseURL, "/")
+	return &models.CalendarFeedResponse{
+		URL:       fmt.Sprintf("%s/api/v1/calendar/%s%s", baseURL, feed.Token, consts.CALENDAR_FEED_SUFFIX),
+		Timezone:  userLocation(user).String(),
+		RotatedAt: feed.UpdatedAt,
+	}, response.CodeSuccess
+}
+
+// userLocation resolves the user's configured timezone, falling back to UTC
+func userLocation(user *models.User) *time.Location {
+	if user.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		global.Log.Warn(errMessage.ErrInvalidTimezone.Error(), zap.String("userID", user.UserID), zap.String("timezone", user.Timezone))
+		return time.UTC
+	}
+	return loc
+}
+
+// BuildCalendar converts class sessions into recurring events in loc and
+// reminders into events at their deadlines, which are also read in loc
+func BuildCalendar(loc *time.Location, sessions []models.ClassSession, reminders []models.Reminder, stamp time.Time) *ical.Calendar {
+	calendar := &ical.Calendar{
+		ProdID:   consts.CALENDAR_PROD_ID,
+		Name:     consts.CALENDAR_NAME,
+		Location: loc,
+	}
+
+	for i := range sessions {
+		calendar.Events = append(calendar.Events, sessionEvent(&sessions[i], loc, stamp))
+	}
+	for i := range reminders {
+		calendar.Events = append(calendar.Events, reminderEvent(&reminders[i], loc, stamp))
+	}
+	return calendar
+}
+
+func sessionEvent(session *models.ClassSession, loc *time.Location, stamp time.Time) ical.Event {
+	first := session.FirstOccurrence()
+	event := ical.Event{
+		UID:        fmt.Sprintf("class-session-%d@%s", session.ID, consts.CALENDAR_UID_DOMAIN),
+		Summary:    "Class",
+		Location:   session.Location.String,
+		Categories: []string{"Class"},
+		Start:      atClock(first, session.StartTime, loc),
+		End:        atClock(first, session.EndTime, loc),
+		Local:      true,
+		Stamp:      stamp,
+	}
+	if session.Course != nil {
+		event.Summary = strings.TrimSpace(session.Course.CourseID + " " + session.Course.CourseName)
+	}
+
+	rule := "FREQ=WEEKLY;BYDAY=" + ical.Weekday(time.Weekday(session.Weekday))
+	if session.Recurrence == consts.ClassSessionRecurrence.BIWEEKLY {
+		rule = "FREQ=WEEKLY;INTERVAL=2;BYDAY=" + ical.Weekday(time.Weekday(session.Weekday))
+	}
+	if session.EndDate.Valid {
+		// UNTIL must be UTC when DTSTART carries a TZID
+		rule += ";UNTIL=" + ical.FormatUTC(atClock(session.EndDate.Time, session.StartTime, loc))
+	}
+	event.RRule = rule
+
+	for _, exception := range session.Exceptions {
+		event.ExDates = append(event.ExDates, atClock(exception.Date, session.StartTime, loc))
+	}
+	return event
+}
+
+func reminderEvent(reminder *models.Reminder, loc *time.Location, stamp time.Time) ical.Event {
+	category := "Reminder"
+	switch reminder.Type {
+	case consts.ReminderType.ASSIGNMENT:
+		category = "Assignment"
+	case consts.ReminderType.EXAM:
+		category = "Exam"
+	}
+
+	return ical.Event{
+		UID:         fmt.Sprintf("reminder-%d@%s", reminder.ID, consts.CALENDAR_UID_DOMAIN),
+		Summary:     fmt.Sprintf("%s: %s", category, reminder.Title),
+		Description: reminder.Description,
+		Location:    reminder.Location.String,
+		Categories:  []string{category},
+		Start:       reminder.Deadline(loc),
+		Stamp:       stamp,
+	}
+}
+
+// atClock places a stored HH:MM:SS time on the calendar date of day in loc
+func atClock(day time.Time, clock string, loc *time.Location) time.Time {
+	parsed, err := time.Parse(time.TimeOnly, clock)
+	if err != nil {
+		parsed = time.Time{}
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), parsed.Hour(), parsed.Minute(), parsed.Second(), 0, loc)
+}
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/utils"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// IChatService runs the chat of study groups. Messages are stored, then
+// published through the broker so the connections of every API instance
+// receive them.
+type IChatService interface {
+	// CheckAccess tells whether the user may open the group's chat
+	CheckAccess(ctx context.Context, userID string, groupID int) int
+	ListMessages(ctx context.Context, userID string, groupID int, query *models.ChatMessageQuery) (*models.ChatMessagePage, int)
+	SendMessage(ctx context.Context, userID string, groupID int, req *models.SendChatMessageRequest) (*models.ChatMessage, int)
+	SetTyping(ctx context.Context, userID string, groupID int, typing bool) int
+
+	// Connect starts delivering the group's events to the client and marks
+	// the user online. Call leave once the connection is gone.
+	Connect(ctx context.Context, client *ChatClient) (leave func(), code int)
+}
+
+// ChatClient is one open chat connection
+type ChatClient struct {
+	GroupID int
+	UserID  string
+	// Send delivers an event; it must not block
+	Send func(event models.ChatEvent) error
+	// Kick closes the connection with a WebSocket close code
+	Kick func(code int, reason string)
+
+	connID string
+}
+
+// chatRoom holds the connections of one group on this instance, which share
+// a single broker subscription
+type chatRoom struct {
+	cancel  context.CancelFunc
+	clients map[*ChatClient]struct{}
+}
+
+type ChatService struct {
+	studyGroupRepo repo.IStudyGroupRepository
+	chatRepo       repo.IChatRepository
+	userRepo       repo.IUserRepository
+	chatBroker     repo.IChatBroker
+
+	mu    sync.Mutex
+	rooms map[int]*chatRoom
+}
+
+// NewChatService creates the chat service. Connections are tracked in
+// memory, so one instance must serve every chat route of the process.
+func NewChatService(
+	studyGroupRepository repo.IStudyGroupRepository,
+	chatRepository repo.IChatRepository,
+	userRepository repo.IUserRepository,
+	chatBroker repo.IChatBroker,
+) IChatService {
+	return &ChatService{
+		studyGroupRepo: studyGroupRepository,
+		chatRepo:       chatRepository,
+		userRepo:       userRepository,
+		chatBroker:     chatBroker,
+		rooms:          make(map[int]*chatRoom),
+	}
+}
+
+func (s *ChatService) CheckAccess(ctx context.Context, userID string, groupID int) int {
+	if _, err := s.studyGroupRepo.GetMember(ctx, groupID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrStudyGroupNotFound.Error(), zap.Int("groupID", groupID), zap.String("userID", userID))
+			return response.CodeStudyGroupNotFound
+		}
+
+		global.Log.Error("Error getting study group member", zap.Error(err), zap.Int("groupID", groupID))
+		return response.CodeServerBusy
+	}
+	return response.CodeSuccess
+}
+
+func (s *ChatService) ListMessages(ctx context.Context, userID string, groupID int, query *models.ChatMessageQuery) (*models.ChatMessagePage, int) {
+	if code := s.CheckAccess(ctx, userID, groupID); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = consts.CHAT_PAGE_DEFAULT_SIZE
+	}
+	limit = min(limit, consts.CHAT_PAGE_MAX_SIZE)
+
+	messages, err := s.chatRepo.ListMessages(ctx, groupID, query.Before, limit)
+	if err != nil {
+		global.Log.Error("Error listing chat messages", zap.Error(err), zap.Int("groupID", groupID))
+		return nil, response.CodeServerBusy
+	}
+
+	page := &models.ChatMessagePage{Messages: messages}
+	if page.Messages == nil {
+		page.Messages = []models.ChatMessage{}
+	}
+	// A full page may have older messages behind it
+	if len(messages) == limit {
+		page.NextBefore = &messages[len(messages)-1].ID
+	}
+	return page, response.CodeSuccess
+}
+
+func (s *ChatService) SendMessage(ctx context.Context, userID string, groupID int, req *models.SendChatMessageRequest) (*models.ChatMessage, int) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" || utf8.RuneCountInString(body) > consts.CHAT_MESSAGE_MAX_LENGTH {
+		global.Log.Warn(errMessage.ErrChatMessageInvalid.Error(), zap.Int("groupID", groupID), zap.String("userID", userID))
+		return nil, response.CodeChatMessageInvalid
+	}
+	// Checked per message so a removed member cannot keep posting
+	if code := s.CheckAccess(ctx, userID, groupID); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error getting user", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	stored := &models.StudyGroupMessage{GroupID: groupID, UserID: userID, Body: body}
+	if err := s.chatRepo.CreateMessage(ctx, stored); err != nil {
+		global.Log.Error("Error creating chat message", zap.Error(err), zap.Int("groupID", groupID), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	message := &models.ChatMessage{
+		ID:        stored.ID,
+		GroupID:   groupID,
+		UserID:    userID,
+		Username:  user.Username,
+		Body:      body,
+		CreatedAt: stored.CreatedAt,
+	}
+	// The message is saved either way; members who miss it load it with the history
+	event := models.ChatEvent{Type: consts.ChatEventType.MESSAGE, GroupID: groupID, Message: message, ClientID: req.ClientID}
+	if err := s.chatBroker.Publish(ctx, groupID, event); err != nil {
+		global.Log.Error("Error publishing chat message", zap.Error(err), zap.Int("groupID", groupID), zap.Int("messageID", message.ID))
+	}
+
+	global.Log.Info("Success sending chat message", zap.Int("groupID", groupID), zap.Int("messageID", message.ID), zap.String("userID", userID))
+	return message, response.CodeSuccess
+}
+
+func (s *ChatService) SetTyping(ctx context.Context, userID string, groupID int, typing bool) int {
+	event := models.ChatEvent{Type: consts.ChatEventType.TYPING, GroupID: groupID, UserID: userID, Typing: &typing}
+	if err := s.chatBroker.Publish(ctx, groupID, event); err != nil {
+		global.Log.Error("Error publishing typing event", zap.Error(err), zap.Int("groupID", groupID))
+		return response.CodeServerBusy
+	}
+	return response.CodeSuccess
+}
+
+func (s *ChatService) Connect(ctx context.Context, client *ChatClient) (func(), int) {
+	if code := s.CheckAccess(ctx, client.UserID, client.GroupID); code != response.CodeSuccess {
+		return nil, code
+	}
+	connID, err := utils.GenerateToken(8)
+	if err != nil {
+		global.Log.Error("Error generating chat connection id", zap.Error(err))
+		return nil, response.CodeServerBusy
+	}
+	client.connID = connID
+
+	if err := s.join(client); err != nil {
+		global.Log.Error("Error subscribing to chat", zap.Error(err), zap.Int("groupID", client.GroupID))
+		return nil, response.CodeServerBusy
+	}
+	s.setOnline(ctx, client)
+	s.publishPresence(ctx, client.GroupID)
+
+	var once sync.Once
+	leave := func() {
+		once.Do(func() {
+			s.leave(client)
+			// The request context is usually gone by now
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := s.chatBroker.SetOffline(ctx, client.GroupID, client.UserID, client.connID); err != nil {
+				global.Log.Error("Error clearing chat presence", zap.Error(err), zap.Int("groupID", client.GroupID))
+			}
+			s.publishPresence(ctx, client.GroupID)
+		})
+	}
+
+	global.Log.Info("Success connecting to chat", zap.Int("groupID", client.GroupID), zap.String("userID", client.UserID))
+	return leave, response.CodeSuccess
+}
+
+// join adds the client to its room, subscribing for the first client of the group
+func (s *ChatService) join(client *ChatClient) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, ok := s.rooms[client.GroupID]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		events, err := s.chatBroker.Subscribe(ctx, client.GroupID)
+		if err != nil {
+			cancel()
+			return err
+		}
+		room = &chatRoom{cancel: cancel, clients: make(map[*ChatClient]struct{})}
+		s.rooms[client.GroupID] = room
+		go s.runRoom(ctx, client.GroupID, room, events)
+	}
+	room.clients[client] = struct{}{}
+	return nil
+}
+
+// leave removes the client, dropping the subscription with the last one
+func (s *ChatService) leave(client *ChatClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, ok := s.rooms[client.GroupID]
+	if !ok {
+		return
+	}
+	// The client's room may have been dropped and replaced since it joined
+	if _, member := room.clients[client]; !member {
+		return
+	}
+	delete(room.clients, client)
+	if len(room.clients) == 0 {
+		room.cancel()
+		delete(s.rooms, client.GroupID)
+	}
+}
+
+// runRoom delivers the group's events to its clients on this instance and
+// keeps their presence from expiring
+func (s *ChatService) runRoom(ctx context.Context, groupID int, room *chatRoom, events <-chan models.ChatEvent) {
+	ticker := time.NewTicker(consts.CHAT_PRESENCE_REFRESH)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, client := range s.clients(room) {
+				s.setOnline(ctx, client)
+			}
+			// Also drops members whose instance went away without saying so
+			s.publishPresence(ctx, groupID)
+		case event, ok := <-events:
+			if !ok {
+				if ctx.Err() == nil {
+					// Lost the relay; clients reconnect and resubscribe
+					global.Log.Error("Error receiving chat events", zap.Int("groupID", groupID))
+					s.dropRoom(groupID, room)
+					for _, client := range s.clients(room) {
+						client.Kick(consts.CHAT_CLOSE_UNAVAILABLE, "chat unavailable")
+					}
+				}
+				return
+			}
+			for _, client := range s.clients(room) {
+				removed := event.Type == consts.ChatEventType.MEMBER_REMOVED &&
+					(event.UserID == "" || event.UserID == client.UserID)
+				_ = client.Send(event)
+				if removed {
+					client.Kick(consts.CHAT_CLOSE_REMOVED, "removed from the study group")
+				}
+			}
+		}
+	}
+}
+
+// dropRoom forgets a room whose subscription is gone, so clients that
+// reconnect open a new one instead of joining a room nothing feeds
+func (s *ChatService) dropRoom(groupID int, room *chatRoom) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rooms[groupID] == room {
+		delete(s.rooms, groupID)
+	}
+	room.cancel()
+}
+
+func (s *ChatService) clients(room *chatRoom) []*ChatClient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clients := make([]*ChatClient, 0, len(room.clients))
+	for client := range room.clients {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// setOnline marks the client online for another CHAT_PRESENCE_TTL
+func (s *ChatService) setOnline(ctx context.Context, client *ChatClient) {
+	until := time.Now().Add(consts.CHAT_PRESENCE_TTL)
+	if err := s.chatBroker.SetOnline(ctx, client.GroupID, client.UserID, client.connID, until); err != nil {
+		global.Log.Error("Error setting chat presence", zap.Error(err), zap.Int("groupID", client.GroupID))
+	}
+}
+
+// publishPresence tells the group which members are online
+func (s *ChatService) publishPresence(ctx context.Context, groupID int) {
+	online, err := s.chatBroker.Online(ctx, groupID, time.Now())
+	if err != nil {
+		global.Log.Error("Error getting chat presence", zap.Error(err), zap.Int("groupID", groupID))
+		return
+	}
+	event := models.ChatEvent{Type: consts.ChatEventType.PRESENCE, GroupID: groupID, Online: online}
+	if err := s.chatBroker.Publish(ctx, groupID, event); err != nil {
+		global.Log.Error("Error publishing chat presence", zap.Error(err), zap.Int("groupID", groupID))
+	}
+}
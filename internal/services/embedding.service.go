@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/pkg/ai"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"github.com/nas03/scholar-ai/backend/pkg/vector"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// IEmbeddingService runs in the worker and keeps the assistant's vector store
+// in step with notes and extracted files
+type IEmbeddingService interface {
+	// IndexSource replaces the embeddings of one note or file. It returns an
+	// error only for failures worth retrying.
+	IndexSource(ctx context.Context, payload models.EmbeddingIndexPayload) error
+}
+
+type EmbeddingService struct {
+	vectorStore  repo.IVectorStore
+	noteRepo     repo.INoteRepository
+	fileRepo     repo.IFileRepository
+	usageRepo    repo.IUsageRepository
+	quotaService IQuotaService
+	provider     ai.Provider
+}
+
+func NewEmbeddingService(vectorStore repo.IVectorStore, noteRepository repo.INoteRepository, fileRepository repo.IFileRepository,
+	usageRepository repo.IUsageRepository, quotaService IQuotaService, provider ai.Provider) IEmbeddingService {
+	return &EmbeddingService{
+		vectorStore:  vectorStore,
+		noteRepo:     noteRepository,
+		fileRepo:     fileRepository,
+		usageRepo:    usageRepository,
+		quotaService: quotaService,
+		provider:     provider,
+	}
+}
+
+func (s *EmbeddingService) IndexSource(ctx context.Context, payload models.EmbeddingIndexPayload) error {
+	var chunks []sourceChunk
+	var courseID *int
+	var reference string
+	var err error
+	if payload.NoteID != nil {
+		chunks, courseID, err = s.noteSource(ctx, *payload.NoteID, payload.UserID)
+		reference = fmt.Sprintf("note:%d", *payload.NoteID)
+	} else if payload.FileID != nil {
+		chunks, courseID, err = s.fileSource(ctx, *payload.FileID, payload.UserID)
+		reference = fmt.Sprintf("file:%d", *payload.FileID)
+	} else {
+		return nil
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Deleted since the job was enqueued; its embeddings went with it
+			return nil
+		}
+		return fmt.Errorf("load source: %w", err)
+	}
+
+	// Sources are indexed again when they next change, in a period with tokens left
+	if code := s.quotaService.CheckAITokens(ctx, payload.UserID); code != response.CodeSuccess {
+		if code == response.CodeServerBusy {
+			return errors.New("check AI quota")
+		}
+		return nil
+	}
+
+	embeddings, usage, err := s.embed(ctx, chunks, payload.UserID, courseID)
+	if err != nil {
+		if permanentAIError(err) {
+			global.Log.Warn("Failed to embed source", zap.Error(err), zap.Any("noteID", payload.NoteID), zap.Any("fileID", payload.FileID))
+			return nil
+		}
+		return err
+	}
+	for i := range embeddings {
+		embeddings[i].NoteID, embeddings[i].FileID = payload.NoteID, payload.FileID
+	}
+
+	if payload.NoteID != nil {
+		err = s.vectorStore.ReplaceNoteEmbeddings(ctx, *payload.NoteID, embeddings)
+	} else {
+		err = s.vectorStore.ReplaceFileEmbeddings(ctx, *payload.FileID, embeddings)
+	}
+	if err != nil {
+		return fmt.Errorf("store embeddings: %w", err)
+	}
+	if len(embeddings) > 0 {
+		recordUsage(ctx, s.usageRepo, models.UsageRecord{
+			UserID:      payload.UserID,
+			Operation:   consts.UsageOperation.EMBEDDING_INDEX,
+			Reference:   reference,
+			Provider:    s.provider.Name(),
+			Model:       embeddings[0].Model,
+			InputTokens: usage.InputTokens,
+		})
+	}
+
+	global.Log.Info("Success indexing source", zap.Any("noteID", payload.NoteID), zap.Any("fileID", payload.FileID), zap.Int("chunks", len(embeddings)))
+	return nil
+}
+
+func (s *EmbeddingService) noteSource(ctx context.Context, id int, userID string) ([]sourceChunk, *int, error) {
+	note, err := s.noteRepo.GetNoteByID(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	courseID := note.CourseID
+	return noteSourceChunks(note), &courseID, nil
+}
+
+func (s *EmbeddingService) fileSource(ctx context.Context, id int, userID string) ([]sourceChunk, *int, error) {
+	file, err := s.fileRepo.GetFileByID(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	fileChunks, err := s.fileRepo.ListChunks(ctx, file.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fileSourceChunks(fileChunks), fileCourseID(file), nil
+}
+
+// embed requests the chunks' vectors in batches and normalizes them. The
+// returned usage covers every batch.
+func (s *EmbeddingService) embed(ctx context.Context, chunks []sourceChunk, userID string, courseID *int) ([]models.Embedding, ai.Usage, error) {
+	var usage ai.Usage
+	embeddings := make([]models.Embedding, 0, len(chunks))
+	for start := 0; start < len(chunks); start += consts.EMBEDDING_BATCH_SIZE {
+		batch := chunks[start:min(start+consts.EMBEDDING_BATCH_SIZE, len(chunks))]
+		input := make([]string, len(batch))
+		for i, chunk := range batch {
+			input[i] = chunk.text
+		}
+
+		resp, err := s.provider.Embed(ctx, ai.EmbeddingRequest{Input: input})
+		if err != nil {
+			return nil, usage, err
+		}
+		usage.InputTokens += resp.Usage.InputTokens
+		if len(resp.Vectors) != len(batch) {
+			return nil, usage, fmt.Errorf("%w: %d vectors for %d inputs", ai.ErrInvalidOutput, len(resp.Vectors), len(batch))
+		}
+
+		for i, chunk := range batch {
+			v := vector.Normalize(resp.Vectors[i])
+			embeddings = append(embeddings, models.Embedding{
+				UserID:     userID,
+				CourseID:   courseID,
+				ChunkIndex: chunk.index,
+				PageStart:  chunk.pageStart,
+				PageEnd:    chunk.pageEnd,
+				Content:    chunk.text,
+				Model:      resp.Model,
+				Dimensions: len(v),
+				Vector:     vector.Encode(v),
+			})
+		}
+	}
+	return embeddings, usage, nil
+}
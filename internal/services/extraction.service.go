@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/extract"
+	"github.com/nas03/scholar-ai/backend/pkg/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// IExtractionService runs in the worker and turns uploaded files into text chunks
+type IExtractionService interface {
+	// ExtractFile returns an error only for failures worth retrying; documents
+	// that cannot be read are recorded on the file row instead
+	ExtractFile(ctx context.Context, payload models.FileExtractPayload) error
+}
+
+type ExtractionService struct {
+	fileRepo      repo.IFileRepository
+	store         storage.Storage
+	jobs          IJobQueue
+	progressStore repo.IProgressStore
+}
+
+func NewExtractionService(fileRepository repo.IFileRepository, store storage.Storage, jobs IJobQueue, progressStore repo.IProgressStore) IExtractionService {
+	return &ExtractionService{
+		fileRepo:      fileRepository,
+		store:         store,
+		jobs:          jobs,
+		progressStore: progressStore,
+	}
+}
+
+func (s *ExtractionService) ExtractFile(ctx context.Context, payload models.FileExtractPayload) error {
+	file, err := s.fileRepo.GetFileByID(ctx, payload.FileID, payload.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Deleted since the job was enqueued
+			global.Log.Warn(errMessage.ErrFileNotFound.Error(), zap.Int("fileID", payload.FileID))
+			return nil
+		}
+		return fmt.Errorf("get file %d: %w", payload.FileID, err)
+	}
+	if file.Status != consts.FileStatus.READY {
+		global.Log.Warn(errMessage.ErrFileNotReady.Error(), zap.Int("fileID", file.ID))
+		return nil
+	}
+
+	if err := s.setStatus(ctx, file, consts.FileExtractionStatus.PROCESSING, ""); err != nil {
+		return err
+	}
+
+	pages, err := s.extractPages(ctx, file)
+	switch {
+	case errors.Is(err, extract.ErrUnsupported):
+		global.Log.Info("File type has no extractable text", zap.Int("fileID", file.ID), zap.String("mimeType", file.MimeType))
+		return s.setStatus(ctx, file, consts.FileExtractionStatus.UNSUPPORTED, "")
+	case errors.Is(err, extract.ErrMalformed):
+		global.Log.Warn("Failed to extract file text", zap.Int("fileID", file.ID), zap.Error(err))
+		return s.setStatus(ctx, file, consts.FileExtractionStatus.FAILED, err.Error())
+	case err != nil:
+		// Storage or disk trouble: record it for the UI and let the queue retry
+		if statusErr := s.setStatus(ctx, file, consts.FileExtractionStatus.FAILED, err.Error()); statusErr != nil {
+			global.Log.Error("Error updating extraction status", zap.Error(statusErr), zap.Int("fileID", file.ID))
+		}
+		return fmt.Errorf("extract file %d: %w", file.ID, err)
+	}
+
+	pieces := extract.Split(pages, consts.EXTRACT_CHUNK_RUNES, consts.EXTRACT_CHUNK_OVERLAP)
+	chunks := make([]models.FileChunk, len(pieces))
+	for i, piece := range pieces {
+		chunks[i] = models.FileChunk{
+			FileID:     file.ID,
+			UserID:     file.UserID,
+			CourseID:   file.CourseID,
+			ChunkIndex: piece.Index,
+			PageStart:  piece.PageStart,
+			PageEnd:    piece.PageEnd,
+			Content:    piece.Text,
+		}
+	}
+
+	pageCount := 0
+	if len(pages) > 0 {
+		pageCount = pages[len(pages)-1].Number
+	}
+
+	err = s.fileRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		fileRepo := s.fileRepo.WithTx(tx)
+		if err := fileRepo.ReplaceChunks(ctx, file.ID, chunks); err != nil {
+			return err
+		}
+		return fileRepo.UpdateFile(ctx, file.ID, file.UserID, map[string]any{
+			"extraction_status": consts.FileExtractionStatus.DONE,
+			"extraction_error":  sql.NullString{},
+			"page_count":        pageCount,
+			"extracted_at":      time.Now(),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("store chunks of file %d: %w", file.ID, err)
+	}
+	publishProgress(ctx, s.progressStore, consts.JobProgressTopic.FILE, file.ID, models.JobProgress{Status: consts.FileExtractionStatus.DONE, Final: true})
+
+	global.Log.Info("Success extracting file text", zap.Int("fileID", file.ID), zap.Int("pages", pageCount), zap.Int("chunks", len(chunks)))
+
+	// Embed the new chunks for the assistant; the extraction itself succeeded either way
+	index := models.EmbeddingIndexPayload{FileID: &file.ID, UserID: file.UserID}
+	if _, err := s.jobs.Enqueue(ctx, consts.JobType.EMBEDDING_INDEX, index); err != nil {
+		global.Log.Error("Error enqueuing file indexing", zap.Error(err), zap.Int("fileID", file.ID))
+	}
+	return nil
+}
+
+// extractPages copies the object to a temporary file, since the PDF and zip
+// readers need random access
+func (s *ExtractionService) extractPages(ctx context.Context, file *models.File) ([]extract.Page, error) {
+	object, err := s.store.Get(ctx, file.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("read stored file: %w", err)
+	}
+	defer object.Close()
+
+	tmp, err := os.CreateTemp("", "extract-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, io.LimitReader(object, MaxFileSize()+1))
+	if err != nil {
+		return nil, fmt.Errorf("download stored file: %w", err)
+	}
+	if size > MaxFileSize() {
+		return nil, fmt.Errorf("%w: file exceeds the size limit", extract.ErrMalformed)
+	}
+
+	return extract.Extract(file.MimeType, tmp, size)
+}
+
+func (s *ExtractionService) setStatus(ctx context.Context, file *models.File, status int8, message string) error {
+	updates := map[string]any{
+		"extraction_status": status,
+		"extraction_error":  sql.NullString{String: truncate(message, consts.EXTRACT_ERROR_LENGTH), Valid: message != ""},
+	}
+	if err := s.fileRepo.UpdateFile(ctx, file.ID, file.UserID, updates); err != nil {
+		return fmt.Errorf("update extraction status of file %d: %w", file.ID, err)
+	}
+
+	event := models.JobProgress{Status: status, Error: truncate(message, consts.EXTRACT_ERROR_LENGTH)}
+	if status == consts.FileExtractionStatus.PROCESSING {
+		startProgress(ctx, s.progressStore, consts.JobProgressTopic.FILE, file.ID, event)
+	} else {
+		event.Final = true
+		publishProgress(ctx, s.progressStore, consts.JobProgressTopic.FILE, file.ID, event)
+	}
+	return nil
+}
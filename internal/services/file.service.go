@@ -0,0 +1,591 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/utils"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/filetype"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"github.com/nas03/scholar-ai/backend/pkg/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type IFileService interface {
+	UploadFile(ctx context.Context, userID string, req *models.UploadFileRequest) (*models.File, int)
+	CreateUpload(ctx context.Context, userID string, req *models.CreateFileUploadRequest) (*models.FileUploadResponse, int)
+	CompleteUpload(ctx context.Context, userID string, id int, uploadID string) (*models.File, int)
+	GetFile(ctx context.Context, userID string, id int) (*models.File, int)
+	ListFiles(ctx context.Context, userID string, query *models.FileQuery) ([]models.File, int)
+	DeleteFile(ctx context.Context, userID string, id int) int
+	GetDownloadURL(ctx context.Context, userID string, id int) (*models.FileDownloadResponse, int)
+	GetUsage(ctx context.Context, userID string) (*models.StorageUsage, int)
+	RequestExtraction(ctx context.Context, userID string, id int) (*models.File, int)
+	ListChunks(ctx context.Context, userID string, id int) ([]models.FileChunk, int)
+
+	// SweepUploads removes one batch of presigned uploads whose URL expired before now
+	SweepUploads(ctx context.Context, now time.Time) error
+}
+
+type FileService struct {
+	fileRepo   repo.IFileRepository
+	userRepo   repo.IUserRepository
+	courseRepo repo.ICourseRepository
+	store      storage.Storage
+	jobs       IJobQueue
+}
+
+func NewFileService(fileRepository repo.IFileRepository, userRepository repo.IUserRepository, courseRepository repo.ICourseRepository, store storage.Storage, jobs IJobQueue) IFileService {
+	return &FileService{
+		fileRepo:   fileRepository,
+		userRepo:   userRepository,
+		courseRepo: courseRepository,
+		store:      store,
+		jobs:       jobs,
+	}
+}
+
+// UploadFile streams a multipart upload into storage. The content type is
+// sniffed from the first bytes and the checksum computed on the way through.
+func (s *FileService) UploadFile(ctx context.Context, userID string, req *models.UploadFileRequest) (*models.File, int) {
+	filename := cleanFilename(req.File.Filename)
+	if code := s.checkCourse(ctx, userID, req.CourseID); code != response.CodeSuccess {
+		return nil, code
+	}
+	if code := s.checkQuota(ctx, userID, req.File.Size); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	src, err := req.File.Open()
+	if err != nil {
+		global.Log.Error("Error opening uploaded file", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	defer src.Close()
+
+	head, mimeType, code := sniffFile(src, filename)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	key, err := newStorageKey(userID, filename)
+	if err != nil {
+		global.Log.Error("Error generating storage key", zap.Error(err))
+		return nil, response.CodeServerBusy
+	}
+
+	hash := sha256.New()
+	body := io.TeeReader(io.MultiReader(bytes.NewReader(head), src), hash)
+	if err := s.store.Put(ctx, key, body, req.File.Size, mimeType); err != nil {
+		global.Log.Error("Error storing uploaded file", zap.Error(err), zap.String("key", key))
+		return nil, response.CodeServerBusy
+	}
+
+	file := &models.File{
+		UserID:     userID,
+		StorageKey: key,
+		Filename:   filename,
+		MimeType:   mimeType,
+		Size:       req.File.Size,
+		Checksum:   sql.NullString{String: hex.EncodeToString(hash.Sum(nil)), Valid: true},
+		Status:     consts.FileStatus.READY,
+	}
+	if req.CourseID != nil {
+		file.CourseID = sql.NullInt64{Int64: int64(*req.CourseID), Valid: true}
+	}
+
+	if err := s.fileRepo.CreateFile(ctx, file); err != nil {
+		global.Log.Error("Error creating file", zap.Error(err), zap.String("userID", userID))
+		s.deleteObject(ctx, key)
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success uploading file", zap.String("userID", userID), zap.Int("fileID", file.ID), zap.String("mimeType", mimeType))
+	s.enqueueExtraction(ctx, file)
+	return file, response.CodeSuccess
+}
+
+// CreateUpload reserves quota for a file and returns a presigned URL the
+// client uploads the body to directly. The URL targets a staging key derived
+// from a fresh upload ID; the file's own key is only ever written by
+// CompleteUpload, which accepts that upload ID once.
+func (s *FileService) CreateUpload(ctx context.Context, userID string, req *models.CreateFileUploadRequest) (*models.FileUploadResponse, int) {
+	filename := cleanFilename(req.Filename)
+	mimeType, err := filetype.FromExtension(filename)
+	if err != nil {
+		global.Log.Warn(errMessage.ErrFileTypeNotAllowed.Error(), zap.String("filename", filename))
+		return nil, response.CodeFileTypeNotAllowed
+	}
+	if code := s.checkCourse(ctx, userID, req.CourseID); code != response.CodeSuccess {
+		return nil, code
+	}
+	if code := s.checkQuota(ctx, userID, req.Size); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	key, err := newStorageKey(userID, filename)
+	if err != nil {
+		global.Log.Error("Error generating storage key", zap.Error(err))
+		return nil, response.CodeServerBusy
+	}
+
+	uploadID, err := utils.GenerateToken(16)
+	if err != nil {
+		global.Log.Error("Error generating upload ID", zap.Error(err))
+		return nil, response.CodeServerBusy
+	}
+
+	expiry := presignExpiry()
+	expiresAt := time.Now().Add(expiry)
+	uploadURL, err := s.store.PresignPut(ctx, stagingKey(uploadID), mimeType, expiry)
+	if err != nil {
+		global.Log.Error("Error presigning upload", zap.Error(err), zap.String("key", key))
+		return nil, response.CodeServerBusy
+	}
+
+	file := &models.File{
+		UserID:     userID,
+		StorageKey: key,
+		Filename:   filename,
+		MimeType:   mimeType,
+		Size:       req.Size,
+		Status:     consts.FileStatus.PENDING,
+
+		UploadID:        sql.NullString{String: uploadID, Valid: true},
+		UploadExpiresAt: sql.NullTime{Time: expiresAt, Valid: true},
+	}
+	if req.CourseID != nil {
+		file.CourseID = sql.NullInt64{Int64: int64(*req.CourseID), Valid: true}
+	}
+	if err := s.fileRepo.CreateFile(ctx, file); err != nil {
+		global.Log.Error("Error creating file", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success creating file upload", zap.String("userID", userID), zap.Int("fileID", file.ID))
+	return &models.FileUploadResponse{
+		File:      file,
+		UploadID:  uploadID,
+		UploadURL: uploadURL,
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": mimeType},
+		ExpiresAt: expiresAt,
+	}, response.CodeSuccess
+}
+
+// CompleteUpload moves an object uploaded through a presigned URL from its
+// staging key to the file's key and verifies it there, so the client cannot
+// change the content after it was checked. Objects that are too large or whose
+// content is not what the name claims are removed. Each upload ID completes
+// once; anything uploaded to its staging key afterwards is left to SweepUploads.
+func (s *FileService) CompleteUpload(ctx context.Context, userID string, id int, uploadID string) (*models.File, int) {
+	file, code := s.GetFile(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	if file.Status != consts.FileStatus.PENDING || !file.UploadID.Valid || file.UploadID.String != uploadID {
+		global.Log.Warn(errMessage.ErrFileUploadNotFound.Error(), zap.String("userID", userID), zap.Int("fileID", id))
+		return nil, response.CodeFileUploadNotFound
+	}
+
+	staged := stagingKey(uploadID)
+	if err := s.store.Copy(ctx, staged, file.StorageKey); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			global.Log.Warn(errMessage.ErrFileUploadIncomplete.Error(), zap.String("userID", userID), zap.Int("fileID", id))
+			return nil, response.CodeFileUploadIncomplete
+		}
+
+		global.Log.Error("Error moving staged upload", zap.Error(err), zap.Int("fileID", id))
+		return nil, response.CodeServerBusy
+	}
+	s.deleteObject(ctx, staged)
+
+	info, err := s.store.Stat(ctx, file.StorageKey)
+	if err != nil {
+		global.Log.Error("Error getting stored file", zap.Error(err), zap.Int("fileID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	if info.Size > MaxFileSize() {
+		global.Log.Warn(errMessage.ErrFileTooLarge.Error(), zap.String("userID", userID), zap.Int("fileID", id), zap.Int64("size", info.Size))
+		s.discardUpload(ctx, file)
+		return nil, response.CodeFileTooLarge
+	}
+	// The declared size is already reserved; only the excess needs quota
+	if info.Size > file.Size {
+		if code := s.checkQuota(ctx, userID, info.Size-file.Size); code != response.CodeSuccess {
+			s.discardUpload(ctx, file)
+			return nil, code
+		}
+	}
+
+	object, err := s.store.Get(ctx, file.StorageKey)
+	if err != nil {
+		global.Log.Error("Error reading stored file", zap.Error(err), zap.Int("fileID", id))
+		return nil, response.CodeServerBusy
+	}
+	defer object.Close()
+
+	head, mimeType, code := sniffFile(object, file.Filename)
+	if code != response.CodeSuccess {
+		s.discardUpload(ctx, file)
+		return nil, code
+	}
+
+	hash := sha256.New()
+	hash.Write(head)
+	if _, err := io.Copy(hash, object); err != nil {
+		global.Log.Error("Error hashing stored file", zap.Error(err), zap.Int("fileID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	updates := map[string]any{
+		"mime_type": mimeType,
+		"size":      info.Size,
+		"checksum":  hex.EncodeToString(hash.Sum(nil)),
+		"status":    consts.FileStatus.READY,
+	}
+	if err := s.fileRepo.UpdateFile(ctx, id, userID, updates); err != nil {
+		global.Log.Error("Error updating file", zap.Error(err), zap.Int("fileID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success completing file upload", zap.String("userID", userID), zap.Int("fileID", id), zap.String("mimeType", mimeType))
+	s.enqueueExtraction(ctx, file)
+	return s.GetFile(ctx, userID, id)
+}
+
+func (s *FileService) GetFile(ctx context.Context, userID string, id int) (*models.File, int) {
+	file, err := s.fileRepo.GetFileByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrFileNotFound.Error(), zap.String("userID", userID), zap.Int("fileID", id))
+			return nil, response.CodeFileNotFound
+		}
+
+		global.Log.Error("Error getting file", zap.Error(err), zap.Int("fileID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	return file, response.CodeSuccess
+}
+
+func (s *FileService) ListFiles(ctx context.Context, userID string, query *models.FileQuery) ([]models.File, int) {
+	files, err := s.fileRepo.ListFiles(ctx, userID, query.CourseID)
+	if err != nil {
+		global.Log.Error("Error listing files", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	return files, response.CodeSuccess
+}
+
+func (s *FileService) DeleteFile(ctx context.Context, userID string, id int) int {
+	file, code := s.GetFile(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return code
+	}
+
+	if err := s.fileRepo.DeleteFile(ctx, id, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrFileNotFound.Error(), zap.String("userID", userID), zap.Int("fileID", id))
+			return response.CodeFileNotFound
+		}
+
+		global.Log.Error("Error deleting file", zap.Error(err), zap.Int("fileID", id))
+		return response.CodeServerBusy
+	}
+	s.deleteObject(ctx, file.StorageKey)
+	if file.UploadID.Valid {
+		s.deleteObject(ctx, stagingKey(file.UploadID.String))
+	}
+
+	global.Log.Info("Success deleting file", zap.String("userID", userID), zap.Int("fileID", id))
+	return response.CodeSuccess
+}
+
+func (s *FileService) GetDownloadURL(ctx context.Context, userID string, id int) (*models.FileDownloadResponse, int) {
+	file, code := s.GetFile(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	if file.Status != consts.FileStatus.READY {
+		global.Log.Warn(errMessage.ErrFileNotReady.Error(), zap.String("userID", userID), zap.Int("fileID", id))
+		return nil, response.CodeFileNotReady
+	}
+
+	expiry := presignExpiry()
+	url, err := s.store.PresignGet(ctx, file.StorageKey, storage.GetOptions{Filename: file.Filename, ContentType: file.MimeType}, expiry)
+	if err != nil {
+		global.Log.Error("Error presigning download", zap.Error(err), zap.Int("fileID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	return &models.FileDownloadResponse{URL: url, ExpiresAt: time.Now().Add(expiry)}, response.CodeSuccess
+}
+
+func (s *FileService) GetUsage(ctx context.Context, userID string) (*models.StorageUsage, int) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrUserNotFound.Error(), zap.String("userID", userID))
+			return nil, response.CodeUserNotFound
+		}
+
+		global.Log.Error("Error getting user", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	used, err := s.fileRepo.UsedBytes(ctx, userID, time.Now().Add(-presignExpiry()))
+	if err != nil {
+		global.Log.Error("Error summing file sizes", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	return &models.StorageUsage{
+		Tier:       user.Tier,
+		UsedBytes:  used,
+		QuotaBytes: StorageQuota(user.Tier),
+		MaxFile:    MaxFileSize(),
+	}, response.CodeSuccess
+}
+
+// RequestExtraction queues the file's text extraction again, e.g. after a failure
+func (s *FileService) RequestExtraction(ctx context.Context, userID string, id int) (*models.File, int) {
+	file, code := s.GetFile(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	if file.Status != consts.FileStatus.READY {
+		global.Log.Warn(errMessage.ErrFileNotReady.Error(), zap.String("userID", userID), zap.Int("fileID", id))
+		return nil, response.CodeFileNotReady
+	}
+
+	updates := map[string]any{
+		"extraction_status": consts.FileExtractionStatus.PENDING,
+		"extraction_error":  sql.NullString{},
+	}
+	if err := s.fileRepo.UpdateFile(ctx, id, userID, updates); err != nil {
+		global.Log.Error("Error updating file", zap.Error(err), zap.Int("fileID", id))
+		return nil, response.CodeServerBusy
+	}
+	if !s.enqueueExtraction(ctx, file) {
+		return nil, response.CodeServerBusy
+	}
+
+	return s.GetFile(ctx, userID, id)
+}
+
+func (s *FileService) ListChunks(ctx context.Context, userID string, id int) ([]models.FileChunk, int) {
+	if _, code := s.GetFile(ctx, userID, id); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	chunks, err := s.fileRepo.ListChunks(ctx, id)
+	if err != nil {
+		global.Log.Error("Error listing file chunks", zap.Error(err), zap.Int("fileID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	return chunks, response.CodeSuccess
+}
+
+// SweepUploads cleans up after presigned uploads whose URL expired before now,
+// allowing STORAGE_UPLOAD_GRACE for uploads still in flight. Uploads that were
+// never completed are deleted with their row; for completed ones only the
+// staging key is removed, since the URL may have been used again after
+// completion. The S3 lifecycle rule on the staging prefix is a backstop for
+// objects this misses.
+func (s *FileService) SweepUploads(ctx context.Context, now time.Time) error {
+	files, err := s.fileRepo.ListExpiredUploads(ctx, now.Add(-consts.STORAGE_UPLOAD_GRACE), consts.STORAGE_SWEEP_BATCH)
+	if err != nil {
+		global.Log.Error("Error listing expired uploads", zap.Error(err))
+		return err
+	}
+
+	for i := range files {
+		file := &files[i]
+		if file.Status == consts.FileStatus.PENDING {
+			s.discardUpload(ctx, file)
+			continue
+		}
+
+		if file.UploadID.Valid {
+			if err := s.store.Delete(ctx, stagingKey(file.UploadID.String)); err != nil {
+				global.Log.Error("Error deleting stored object", zap.Error(err), zap.Int("fileID", file.ID))
+				continue
+			}
+		}
+		updates := map[string]any{
+			"upload_id":         sql.NullString{},
+			"upload_expires_at": sql.NullTime{},
+		}
+		if err := s.fileRepo.UpdateFile(ctx, file.ID, file.UserID, updates); err != nil {
+			global.Log.Error("Error updating file", zap.Error(err), zap.Int("fileID", file.ID))
+			return err
+		}
+	}
+
+	if len(files) > 0 {
+		global.Log.Info("Success sweeping expired uploads", zap.Int("count", len(files)))
+	}
+	return nil
+}
+
+// enqueueExtraction schedules text extraction. A failure leaves the file
+// pending; the upload itself has succeeded and extraction can be requested again.
+func (s *FileService) enqueueExtraction(ctx context.Context, file *models.File) bool {
+	payload := models.FileExtractPayload{FileID: file.ID, UserID: file.UserID}
+	if _, err := s.jobs.Enqueue(ctx, consts.JobType.FILE_EXTRACT, payload); err != nil {
+		global.Log.Error("Error enqueuing file extraction", zap.Error(err), zap.Int("fileID", file.ID))
+		return false
+	}
+	return true
+}
+
+// checkQuota rejects files over the size limit or that would exceed the user's tier quota
+func (s *FileService) checkQuota(ctx context.Context, userID string, size int64) int {
+	if size > MaxFileSize() {
+		global.Log.Warn(errMessage.ErrFileTooLarge.Error(), zap.String("userID", userID), zap.Int64("size", size))
+		return response.CodeFileTooLarge
+	}
+
+	usage, code := s.GetUsage(ctx, userID)
+	if code != response.CodeSuccess {
+		return code
+	}
+	if usage.UsedBytes+size > usage.QuotaBytes {
+		global.Log.Warn(errMessage.ErrFileQuotaExceeded.Error(), zap.String("userID", userID), zap.String("tier", usage.Tier), zap.Int64("used", usage.UsedBytes), zap.Int64("size", size))
+		return response.CodeFileQuotaExceeded
+	}
+	return response.CodeSuccess
+}
+
+func (s *FileService) checkCourse(ctx context.Context, userID string, courseID *int) int {
+	if courseID == nil {
+		return response.CodeSuccess
+	}
+	if _, err := s.courseRepo.GetCourseByID(ctx, *courseID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrCourseNotFound.Error(), zap.String("userID", userID), zap.Int("courseID", *courseID))
+			return response.CodeCourseNotFound
+		}
+
+		global.Log.Error("Error getting course", zap.Error(err), zap.Int("courseID", *courseID))
+		return response.CodeServerBusy
+	}
+	return response.CodeSuccess
+}
+
+// discardUpload removes a rejected presigned upload together with its row
+func (s *FileService) discardUpload(ctx context.Context, file *models.File) {
+	if err := s.fileRepo.DeleteFile(ctx, file.ID, file.UserID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		global.Log.Error("Error deleting rejected file", zap.Error(err), zap.Int("fileID", file.ID))
+	}
+	s.deleteObject(ctx, file.StorageKey)
+	if file.UploadID.Valid {
+		s.deleteObject(ctx, stagingKey(file.UploadID.String))
+	} else {
+		// Announced before uploads had their own ID
+		s.deleteObject(ctx, stagingKey(file.StorageKey))
+	}
+}
+
+func (s *FileService) deleteObject(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil {
+		global.Log.Error("Error deleting stored object", zap.Error(err), zap.String("key", key))
+	}
+}
+
+// sniffFile reads the first bytes of r and identifies the content. The bytes
+// read are returned so the caller can still store or hash the whole file.
+func sniffFile(r io.Reader, filename string) ([]byte, string, int) {
+	head := make([]byte, filetype.SniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		global.Log.Error("Error reading uploaded file", zap.Error(err))
+		return nil, "", response.CodeServerBusy
+	}
+	head = head[:n]
+
+	mimeType, err := filetype.Detect(head, filename)
+	if err != nil {
+		global.Log.Warn(errMessage.ErrFileTypeNotAllowed.Error(), zap.String("filename", filename), zap.Error(err))
+		return nil, "", response.CodeFileTypeNotAllowed
+	}
+	return head, mimeType, response.CodeSuccess
+}
+
+// StorageQuota returns the total bytes a user of the tier may store
+func StorageQuota(tier string) int64 {
+	quotas := global.Config.Storage.Quotas
+	if len(quotas) == 0 {
+		quotas = consts.STORAGE_DEFAULT_QUOTAS
+	}
+	if quota, ok := quotas[tier]; ok {
+		return quota
+	}
+	return quotas[consts.UserTier.FREE]
+}
+
+// MaxFileSize returns the largest accepted upload in bytes
+func MaxFileSize() int64 {
+	if global.Config.Storage.MaxFileSize > 0 {
+		return global.Config.Storage.MaxFileSize
+	}
+	return consts.STORAGE_DEFAULT_MAX_FILE_SIZE
+}
+
+func presignExpiry() time.Duration {
+	if global.Config.Storage.PresignExpiry > 0 {
+		return time.Duration(global.Config.Storage.PresignExpiry) * time.Second
+	}
+	return consts.STORAGE_DEFAULT_PRESIGN_EXPIRY
+}
+
+// newStorageKey keeps user files under a per-user prefix with a random name;
+// the original name is only stored in the database
+func newStorageKey(userID, filename string) (string, error) {
+	token, err := utils.GenerateToken(16)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("users/%s/%s%s", userID, token, strings.ToLower(filepath.Ext(filename))), nil
+}
+
+// stagingKey is where the presigned upload with the given ID lands
+func stagingKey(uploadID string) string {
+	return consts.STORAGE_STAGING_PREFIX + uploadID
+}
+
+// cleanFilename strips directories and control characters from a client supplied name
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	return truncate(name, 255)
+}
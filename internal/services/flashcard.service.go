@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"github.com/nas03/scholar-ai/backend/pkg/srs"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type IFlashcardService interface {
+	GenerateFlashcards(ctx context.Context, userID string, req *models.GenerateFlashcardsRequest) (*models.FlashcardGeneration, int)
+	GetGeneration(ctx context.Context, userID string, id int) (*models.FlashcardGeneration, int)
+	CreateFlashcard(ctx context.Context, userID string, req *models.CreateFlashcardRequest) (*models.Flashcard, int)
+	ListFlashcards(ctx context.Context, userID string, query *models.FlashcardQuery) ([]models.Flashcard, int)
+	GetFlashcard(ctx context.Context, userID string, id int) (*models.Flashcard, int)
+	UpdateFlashcard(ctx context.Context, userID string, id int, req *models.UpdateFlashcardRequest) (*models.Flashcard, int)
+	DeleteFlashcard(ctx context.Context, userID string, id int) int
+	ListDueFlashcards(ctx context.Context, userID string, query *models.DueFlashcardQuery) (*models.DueFlashcards, int)
+	ReviewFlashcard(ctx context.Context, userID string, id int, req *models.ReviewFlashcardRequest) (*models.Flashcard, int)
+}
+
+type FlashcardService struct {
+	flashcardRepo repo.IFlashcardRepository
+	noteRepo      repo.INoteRepository
+	fileRepo      repo.IFileRepository
+	userRepo      repo.IUserRepository
+	courseRepo    repo.ICourseRepository
+	quotaService  IQuotaService
+	jobs          IJobQueue
+}
+
+func NewFlashcardService(flashcardRepository repo.IFlashcardRepository, noteRepository repo.INoteRepository, fileRepository repo.IFileRepository,
+	userRepository repo.IUserRepository, courseRepository repo.ICourseRepository, quotaService IQuotaService, jobs IJobQueue) IFlashcardService {
+	return &FlashcardService{
+		flashcardRepo: flashcardRepository,
+		noteRepo:      noteRepository,
+		fileRepo:      fileRepository,
+		userRepo:      userRepository,
+		courseRepo:    courseRepository,
+		quotaService:  quotaService,
+		jobs:          jobs,
+	}
+}
+
+// GenerateFlashcards checks the source and queues card generation. The cards
+// appear once the generation's status is done.
+func (s *FlashcardService) GenerateFlashcards(ctx context.Context, userID string, req *models.GenerateFlashcardsRequest) (*models.FlashcardGeneration, int) {
+	if (req.NoteID == nil) == (req.FileID == nil) {
+		global.Log.Warn(errMessage.ErrInvalidFlashcardSource.Error(), zap.String("userID", userID))
+		return nil, response.CodeFlashcardInvalidSource
+	}
+
+	language := strings.ToLower(strings.TrimSpace(req.Language))
+	if language == "" {
+		language = consts.AI_DEFAULT_LANGUAGE
+	}
+	if _, ok := consts.AI_LANGUAGES[language]; !ok {
+		global.Log.Warn(errMessage.ErrInvalidFlashcardLanguage.Error(), zap.String("language", req.Language))
+		return nil, response.CodeFlashcardInvalidLanguage
+	}
+
+	if code := s.checkSource(ctx, userID, req); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	count := req.Count
+	if count == 0 {
+		count = consts.FLASHCARD_DEFAULT_COUNT
+	}
+	reservation, code := s.quotaService.ReserveAIOperation(ctx, userID, consts.UsageOperation.FLASHCARD_GENERATE)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	generation := &models.FlashcardGeneration{
+		UserID:        userID,
+		NoteID:        req.NoteID,
+		FileID:        req.FileID,
+		Language:      language,
+		Count:         count,
+		Status:        consts.FlashcardGenerationStatus.PENDING,
+		PromptVersion: consts.FLASHCARD_PROMPT_VERSION,
+	}
+	if err := s.flashcardRepo.CreateGeneration(ctx, generation); err != nil {
+		s.quotaService.ReleaseAIOperation(ctx, reservation)
+		global.Log.Error("Error creating flashcard generation", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	payload := models.FlashcardGeneratePayload{GenerationID: generation.ID, UserID: userID, ReservationID: reservation}
+	if _, err := s.jobs.Enqueue(ctx, consts.JobType.FLASHCARD_GENERATE, payload); err != nil {
+		s.quotaService.ReleaseAIOperation(ctx, reservation)
+		global.Log.Error("Error enqueuing flashcard generation", zap.Error(err), zap.Int("generationID", generation.ID))
+		failed := map[string]any{
+			"status": consts.FlashcardGenerationStatus.FAILED,
+			"error":  "could not be queued",
+		}
+		if err := s.flashcardRepo.UpdateGeneration(ctx, generation.ID, failed); err != nil {
+			global.Log.Error("Error updating flashcard generation status", zap.Error(err), zap.Int("generationID", generation.ID))
+		}
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Flashcard generation requested", zap.Int("generationID", generation.ID), zap.Int("count", count))
+	return generation, response.CodeSuccess
+}
+
+// checkSource verifies the note or file belongs to the user and has text
+func (s *FlashcardService) checkSource(ctx context.Context, userID string, req *models.GenerateFlashcardsRequest) int {
+	if req.NoteID != nil {
+		note, err := s.noteRepo.GetNoteByID(ctx, *req.NoteID, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				global.Log.Warn(errMessage.ErrNoteNotFound.Error(), zap.String("userID", userID), zap.Int("noteID", *req.NoteID))
+				return response.CodeNoteNotFound
+			}
+			global.Log.Error("Error getting note", zap.Error(err), zap.Int("noteID", *req.NoteID))
+			return response.CodeServerBusy
+		}
+		if strings.TrimSpace(note.ContentText) == "" {
+			global.Log.Warn(errMessage.ErrFlashcardSourceEmpty.Error(), zap.Int("noteID", note.ID))
+			return response.CodeFlashcardSourceEmpty
+		}
+		return response.CodeSuccess
+	}
+
+	file, err := s.fileRepo.GetFileByID(ctx, *req.FileID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrFileNotFound.Error(), zap.String("userID", userID), zap.Int("fileID", *req.FileID))
+			return response.CodeFileNotFound
+		}
+		global.Log.Error("Error getting file", zap.Error(err), zap.Int("fileID", *req.FileID))
+		return response.CodeServerBusy
+	}
+	if file.ExtractionStatus != consts.FileExtractionStatus.DONE {
+		global.Log.Warn(errMessage.ErrFlashcardSourceEmpty.Error(), zap.Int("fileID", file.ID), zap.Int8("extractionStatus", file.ExtractionStatus))
+		return response.CodeFlashcardSourceEmpty
+	}
+	return response.CodeSuccess
+}
+
+// GetGeneration returns a generation; clients poll it until the status is done or failed
+func (s *FlashcardService) GetGeneration(ctx context.Context, userID string, id int) (*models.FlashcardGeneration, int) {
+	generation, err := s.flashcardRepo.GetGenerationByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrFlashcardGenerationNotFound.Error(), zap.String("userID", userID), zap.Int("generationID", id))
+			return nil, response.CodeFlashcardGenerationNotFound
+		}
+		global.Log.Error("Error getting flashcard generation", zap.Error(err), zap.Int("generationID", id))
+		return nil, response.CodeServerBusy
+	}
+	return generation, response.CodeSuccess
+}
+
+// CreateFlashcard adds a hand-written card, due for its first review today
+func (s *FlashcardService) CreateFlashcard(ctx context.Context, userID string, req *models.CreateFlashcardRequest) (*models.Flashcard, int) {
+	if code := s.checkCourse(ctx, userID, req.CourseID); code != response.CodeSuccess {
+		return nil, code
+	}
+	today, code := s.today(ctx, userID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	initial := srs.New()
+	card := &models.Flashcard{
+		UserID:     userID,
+		CourseID:   req.CourseID,
+		Front:      strings.TrimSpace(req.Front),
+		Back:       strings.TrimSpace(req.Back),
+		EaseFactor: initial.EaseFactor,
+		DueDate:    today,
+	}
+	if err := s.flashcardRepo.CreateFlashcard(ctx, card); err != nil {
+		global.Log.Error("Error creating flashcard", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	return card, response.CodeSuccess
+}
+
+func (s *FlashcardService) ListFlashcards(ctx context.Context, userID string, query *models.FlashcardQuery) ([]models.Flashcard, int) {
+	cards, err := s.flashcardRepo.ListFlashcards(ctx, models.FlashcardFilter{
+		UserID:       userID,
+		CourseID:     query.CourseID,
+		NoteID:       query.NoteID,
+		FileID:       query.FileID,
+		GenerationID: query.GenerationID,
+	})
+	if err != nil {
+		global.Log.Error("Error listing flashcards", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	return cards, response.CodeSuccess
+}
+
+func (s *FlashcardService) GetFlashcard(ctx context.Context, userID string, id int) (*models.Flashcard, int) {
+	card, err := s.flashcardRepo.GetFlashcardByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrFlashcardNotFound.Error(), zap.String("userID", userID), zap.Int("flashcardID", id))
+			return nil, response.CodeFlashcardNotFound
+		}
+		global.Log.Error("Error getting flashcard", zap.Error(err), zap.Int("flashcardID", id))
+		return nil, response.CodeServerBusy
+	}
+	return card, response.CodeSuccess
+}
+
+// UpdateFlashcard edits the card's text or course; its schedule is kept
+func (s *FlashcardService) UpdateFlashcard(ctx context.Context, userID string, id int, req *models.UpdateFlashcardRequest) (*models.Flashcard, int) {
+	if _, code := s.GetFlashcard(ctx, userID, id); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	updates := map[string]any{}
+	if req.Front != nil {
+		if front := strings.TrimSpace(*req.Front); front != "" {
+			updates["front"] = front
+		}
+	}
+	if req.Back != nil {
+		if back := strings.TrimSpace(*req.Back); back != "" {
+			updates["back"] = back
+		}
+	}
+	if req.CourseID != nil {
+		if code := s.checkCourse(ctx, userID, req.CourseID); code != response.CodeSuccess {
+			return nil, code
+		}
+		updates["course_id"] = *req.CourseID
+	}
+
+	if len(updates) > 0 {
+		if err := s.flashcardRepo.UpdateFlashcard(ctx, id, userID, updates); err != nil {
+			global.Log.Error("Error updating flashcard", zap.Error(err), zap.Int("flashcardID", id))
+			return nil, response.CodeServerBusy
+		}
+	}
+	return s.GetFlashcard(ctx, userID, id)
+}
+
+func (s *FlashcardService) DeleteFlashcard(ctx context.Context, userID string, id int) int {
+	if err := s.flashcardRepo.DeleteFlashcard(ctx, id, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrFlashcardNotFound.Error(), zap.String("userID", userID), zap.Int("flashcardID", id))
+			return response.CodeFlashcardNotFound
+		}
+
+		global.Log.Error("Error deleting flashcard", zap.Error(err), zap.Int("flashcardID", id))
+		return response.CodeServerBusy
+	}
+
+	global.Log.Info("Success deleting flashcard", zap.String("userID", userID), zap.Int("flashcardID", id))
+	return response.CodeSuccess
+}
+
+// ListDueFlashcards returns the cards due today or earlier in the user's timezone
+func (s *FlashcardService) ListDueFlashcards(ctx context.Context, userID string, query *models.DueFlashcardQuery) (*models.DueFlashcards, int) {
+	today, code := s.today(ctx, userID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	filter := models.DueFlashcardFilter{
+		UserID:   userID,
+		CourseID: query.CourseID,
+		Today:    today,
+		Limit:    query.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = consts.FLASHCARD_DUE_LIMIT
+	}
+
+	total, err := s.flashcardRepo.CountDueFlashcards(ctx, filter)
+	if err != nil {
+		global.Log.Error("Error counting due flashcards", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	cards, err := s.flashcardRepo.ListDueFlashcards(ctx, filter)
+	if err != nil {
+		global.Log.Error("Error listing due flashcards", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	return &models.DueFlashcards{
+		Date:  today.Format(consts.DATE_LAYOUT),
+		Total: total,
+		Cards: cards,
+	}, response.CodeSuccess
+}
+
+// ReviewFlashcard records a 0-5 grade and schedules the next review with SM-2,
+// counting intervals from today in the user's timezone
+func (s *FlashcardService) ReviewFlashcard(ctx context.Context, userID string, id int, req *models.ReviewFlashcardRequest) (*models.Flashcard, int) {
+	card, code := s.GetFlashcard(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	today, code := s.today(ctx, userID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	next, err := srs.Review(srs.State{
+		EaseFactor:  card.EaseFactor,
+		Interval:    card.IntervalDays,
+		Repetitions: card.Repetitions,
+	}, *req.Grade)
+	if err != nil {
+		return nil, response.CodeInvalidParams
+	}
+
+	now := time.Now()
+	dueDate := today.AddDate(0, 0, next.Interval)
+	updates := map[string]any{
+		"ease_factor":      next.EaseFactor,
+		"interval_days":    next.Interval,
+		"repetitions":      next.Repetitions,
+		"due_date":         dueDate,
+		"last_reviewed_at": now,
+	}
+	if *req.Grade < srs.PassingGrade {
+		updates["lapses"] = gorm.Expr("lapses + 1")
+	}
+
+	err = s.flashcardRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		flashcardRepo := s.flashcardRepo.WithTx(tx)
+		if err := flashcardRepo.UpdateFlashcard(ctx, card.ID, userID, updates); err != nil {
+			return err
+		}
+		return flashcardRepo.CreateReview(ctx, &models.FlashcardReview{
+			FlashcardID:  card.ID,
+			UserID:       userID,
+			Grade:        int8(*req.Grade),
+			EaseFactor:   next.EaseFactor,
+			IntervalDays: next.Interval,
+			DueDate:      dueDate,
+		})
+	})
+	if err != nil {
+		global.Log.Error("Error reviewing flashcard", zap.Error(err), zap.Int("flashcardID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	return s.GetFlashcard(ctx, userID, id)
+}
+
+func (s *FlashcardService) checkCourse(ctx context.Context, userID string, courseID *int) int {
+	if courseID == nil {
+		return response.CodeSuccess
+	}
+	if _, err := s.courseRepo.GetCourseByID(ctx, *courseID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrCourseNotFound.Error(), zap.String("userID", userID), zap.Int("courseID", *courseID))
+			return response.CodeCourseNotFound
+		}
+
+		global.Log.Error("Error getting course", zap.Error(err), zap.Int("courseID", *courseID))
+		return response.CodeServerBusy
+	}
+	return response.CodeSuccess
+}
+
+// today returns the user's current calendar date as midnight UTC, the form DATE columns are compared in
+func (s *FlashcardService) today(ctx context.Context, userID string) (time.Time, int) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrUserNotFound.Error(), zap.String("userID", userID))
+			return time.Time{}, response.CodeUserNotFound
+		}
+		global.Log.Error("Error getting user", zap.Error(err), zap.String("userID", userID))
+		return time.Time{}, response.CodeServerBusy
+	}
+	return localDate(time.Now(), userLocation(user)), response.CodeSuccess
+}
+
+// localDate returns the calendar date of t in loc as midnight UTC
+func localDate(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,441 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/pkg/ai"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/extract"
+	"github.com/nas03/scholar-ai/backend/pkg/srs"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// IFlashcardGenerationService runs in the worker and turns a note or file into flashcards
+type IFlashcardGenerationService interface {
+	// GenerateFlashcards returns an error only for failures worth retrying;
+	// sources the model cannot handle are recorded on the generation row instead
+	GenerateFlashcards(ctx context.Context, payload models.FlashcardGeneratePayload) error
+}
+
+type FlashcardGenerationService struct {
+	flashcardRepo repo.IFlashcardRepository
+	noteRepo      repo.INoteRepository
+	fileRepo      repo.IFileRepository
+	userRepo      repo.IUserRepository
+	provider      ai.Provider
+	progressStore repo.IProgressStore
+	usageRepo     repo.IUsageRepository
+}
+
+func NewFlashcardGenerationService(flashcardRepository repo.IFlashcardRepository, noteRepository repo.INoteRepository, fileRepository repo.IFileRepository,
+	userRepository repo.IUserRepository, provider ai.Provider, progressStore repo.IProgressStore, usageRepository repo.IUsageRepository) IFlashcardGenerationService {
+	return &FlashcardGenerationService{
+		flashcardRepo: flashcardRepository,
+		noteRepo:      noteRepository,
+		fileRepo:      fileRepository,
+		userRepo:      userRepository,
+		provider:      provider,
+		progressStore: progressStore,
+		usageRepo:     usageRepository,
+	}
+}
+
+// sourceChunk is a piece of the source text that generated items point
+// back to. Pages are only known for files, notes only for course-wide sources.
+type sourceChunk struct {
+	index     int
+	pageStart *int
+	pageEnd   *int
+	noteID    *int
+	text      string
+}
+
+func (s *FlashcardGenerationService) GenerateFlashcards(ctx context.Context, payload models.FlashcardGeneratePayload) error {
+	generation, err := s.flashcardRepo.GetGenerationByID(ctx, payload.GenerationID, payload.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrFlashcardGenerationNotFound.Error(), zap.Int("generationID", payload.GenerationID))
+			releaseUsage(ctx, s.usageRepo, payload.ReservationID)
+			return nil
+		}
+		return fmt.Errorf("get flashcard generation %d: %w", payload.GenerationID, err)
+	}
+	if generation.Status == consts.FlashcardGenerationStatus.DONE {
+		return nil
+	}
+
+	chunks, courseID, err := s.loadSource(ctx, generation)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// The source was deleted since the job was enqueued
+			global.Log.Warn(errMessage.ErrInvalidFlashcardSource.Error(), zap.Int("generationID", generation.ID))
+			if statusErr := s.fail(ctx, generation.ID, errMessage.ErrInvalidFlashcardSource); statusErr != nil {
+				global.Log.Error("Error updating flashcard generation status", zap.Error(statusErr), zap.Int("generationID", generation.ID))
+			}
+			releaseUsage(ctx, s.usageRepo, payload.ReservationID)
+			return nil
+		}
+		return fmt.Errorf("load source of flashcard generation %d: %w", generation.ID, err)
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, generation.UserID)
+	if err != nil {
+		return fmt.Errorf("get user %s: %w", generation.UserID, err)
+	}
+
+	err = s.flashcardRepo.UpdateGeneration(ctx, generation.ID, map[string]any{
+		"status": consts.FlashcardGenerationStatus.PROCESSING,
+		"error":  sql.NullString{},
+	})
+	if err != nil {
+		return fmt.Errorf("update status of flashcard generation %d: %w", generation.ID, err)
+	}
+	startProgress(ctx, s.progressStore, consts.JobProgressTopic.FLASHCARD_GENERATION, generation.ID, models.JobProgress{Status: consts.FlashcardGenerationStatus.PROCESSING})
+
+	run := &flashcardRun{
+		provider: s.provider,
+		language: consts.AI_LANGUAGES[generation.Language],
+		progress: func(done, total int) {
+			publishProgress(ctx, s.progressStore, consts.JobProgressTopic.FLASHCARD_GENERATION, generation.ID, models.JobProgress{
+				Status: consts.FlashcardGenerationStatus.PROCESSING,
+				Stage:  "generate",
+				Done:   done,
+				Total:  total,
+			})
+		},
+	}
+	if run.language == "" {
+		run.language = consts.AI_LANGUAGES[consts.AI_DEFAULT_LANGUAGE]
+	}
+
+	drafts, err := run.generate(ctx, chunks, generation.Count)
+	if err != nil {
+		if statusErr := s.fail(ctx, generation.ID, err); statusErr != nil {
+			global.Log.Error("Error updating flashcard generation status", zap.Error(statusErr), zap.Int("generationID", generation.ID))
+		}
+		if permanentAIError(err) {
+			global.Log.Warn("Failed to generate flashcards", zap.Int("generationID", generation.ID), zap.Error(err))
+			releaseUsage(ctx, s.usageRepo, payload.ReservationID)
+			return nil
+		}
+		// Rate limits, outages and timeouts: let the queue retry
+		return fmt.Errorf("generate flashcards %d: %w", generation.ID, err)
+	}
+
+	dueDate := localDate(time.Now(), userLocation(user))
+	initial := srs.New()
+	cards := make([]models.Flashcard, len(drafts))
+	for i, draft := range drafts {
+		chunkIndex := draft.chunk.index
+		cards[i] = models.Flashcard{
+			UserID:       generation.UserID,
+			CourseID:     courseID,
+			GenerationID: &generation.ID,
+			NoteID:       generation.NoteID,
+			FileID:       generation.FileID,
+			ChunkIndex:   &chunkIndex,
+			PageStart:    draft.chunk.pageStart,
+			PageEnd:      draft.chunk.pageEnd,
+			Front:        draft.front,
+			Back:         draft.back,
+			EaseFactor:   initial.EaseFactor,
+			DueDate:      dueDate,
+		}
+	}
+
+	err = s.flashcardRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		flashcardRepo := s.flashcardRepo.WithTx(tx)
+		if err := flashcardRepo.CreateFlashcards(ctx, cards); err != nil {
+			return err
+		}
+		return flashcardRepo.UpdateGeneration(ctx, generation.ID, map[string]any{
+			"status":        consts.FlashcardGenerationStatus.DONE,
+			"created_count": len(cards),
+			"provider":      s.provider.Name(),
+			"model":         run.model,
+			"input_tokens":  run.usage.InputTokens,
+			"output_tokens": run.usage.OutputTokens,
+			"completed_at":  time.Now(),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("store flashcards of generation %d: %w", generation.ID, err)
+	}
+	publishProgress(ctx, s.progressStore, consts.JobProgressTopic.FLASHCARD_GENERATION, generation.ID, models.JobProgress{Status: consts.FlashcardGenerationStatus.DONE, Final: true})
+	recordUsage(ctx, s.usageRepo, models.UsageRecord{
+		ID:           payload.ReservationID,
+		UserID:       generation.UserID,
+		Operation:    consts.UsageOperation.FLASHCARD_GENERATE,
+		Reference:    progressTopic(consts.JobProgressTopic.FLASHCARD_GENERATION, generation.ID),
+		Provider:     s.provider.Name(),
+		Model:        run.model,
+		InputTokens:  run.usage.InputTokens,
+		OutputTokens: run.usage.OutputTokens,
+	})
+
+	global.Log.Info("Success generating flashcards", zap.Int("generationID", generation.ID), zap.Int("cards", len(cards)), zap.Int("calls", run.calls))
+	return nil
+}
+
+// loadSource returns the source's chunks and the course new cards belong to
+func (s *FlashcardGenerationService) loadSource(ctx context.Context, generation *models.FlashcardGeneration) ([]sourceChunk, *int, error) {
+	if generation.NoteID != nil {
+		note, err := s.noteRepo.GetNoteByID(ctx, *generation.NoteID, generation.UserID)
+		if err != nil {
+			return nil, nil, err
+		}
+		courseID := note.CourseID
+		return noteSourceChunks(note), &courseID, nil
+	}
+	if generation.FileID == nil {
+		return nil, nil, gorm.ErrRecordNotFound
+	}
+
+	file, err := s.fileRepo.GetFileByID(ctx, *generation.FileID, generation.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	fileChunks, err := s.fileRepo.ListChunks(ctx, file.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fileSourceChunks(fileChunks), fileCourseID(file), nil
+}
+
+// noteSourceChunks splits a note's text the way extracted files are chunked
+func noteSourceChunks(note *models.Note) []sourceChunk {
+	pieces := extract.Split([]extract.Page{{Number: 1, Text: note.ContentText}}, consts.EXTRACT_CHUNK_RUNES, consts.EXTRACT_CHUNK_OVERLAP)
+	chunks := make([]sourceChunk, len(pieces))
+	for i, piece := range pieces {
+		chunks[i] = sourceChunk{index: piece.Index, text: piece.Text}
+	}
+	return chunks
+}
+
+func fileSourceChunks(fileChunks []models.FileChunk) []sourceChunk {
+	chunks := make([]sourceChunk, len(fileChunks))
+	for i, chunk := range fileChunks {
+		pageStart, pageEnd := chunk.PageStart, chunk.PageEnd
+		chunks[i] = sourceChunk{index: chunk.ChunkIndex, pageStart: &pageStart, pageEnd: &pageEnd, text: chunk.Content}
+	}
+	return chunks
+}
+
+func fileCourseID(file *models.File) *int {
+	if !file.CourseID.Valid {
+		return nil
+	}
+	id := int(file.CourseID.Int64)
+	return &id
+}
+
+func (s *FlashcardGenerationService) fail(ctx context.Context, id int, cause error) error {
+	message := truncate(cause.Error(), consts.FLASHCARD_ERROR_LENGTH)
+	publishProgress(ctx, s.progressStore, consts.JobProgressTopic.FLASHCARD_GENERATION, id, models.JobProgress{Status: consts.FlashcardGenerationStatus.FAILED, Error: message, Final: true})
+	return s.flashcardRepo.UpdateGeneration(ctx, id, map[string]any{
+		"status": consts.FlashcardGenerationStatus.FAILED,
+		"error":  sql.NullString{String: message, Valid: true},
+	})
+}
+
+// flashcardDraft is a generated card before it is stored
+type flashcardDraft struct {
+	front string
+	back  string
+	chunk sourceChunk
+}
+
+// flashcardRun generates the cards of one generation. The source is packed
+// into batches that fit the context budget; long sources are sampled evenly
+// and the requested count is shared between the batches by size.
+type flashcardRun struct {
+	provider ai.Provider
+	language string
+	progress func(done, total int) // optional, called before each batch
+
+	model string
+	usage ai.Usage
+	calls int
+}
+
+func (r *flashcardRun) generate(ctx context.Context, chunks []sourceChunk, count int) ([]flashcardDraft, error) {
+	var nonEmpty []sourceChunk
+	for _, chunk := range chunks {
+		if strings.TrimSpace(chunk.text) != "" {
+			nonEmpty = append(nonEmpty, chunk)
+		}
+	}
+	if len(nonEmpty) == 0 || count <= 0 {
+		return nil, errMessage.ErrFlashcardSourceEmpty
+	}
+
+	batches := sampleBatches(batchChunks(nonEmpty, consts.FLASHCARD_BATCH_TOKENS), min(consts.FLASHCARD_MAX_BATCHES, count))
+	counts := shareCount(batches, count)
+
+	var drafts []flashcardDraft
+	for i, batch := range batches {
+		if r.progress != nil {
+			r.progress(i, len(batches))
+		}
+		generated, err := r.chat(ctx, batch, counts[i])
+		if err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, generated...)
+	}
+	return drafts, nil
+}
+
+// chat asks for n cards about one batch of chunks
+func (r *flashcardRun) chat(ctx context.Context, batch []sourceChunk, n int) ([]flashcardDraft, error) {
+	var b strings.Builder
+	for _, chunk := range batch {
+		fmt.Fprintf(&b, "[chunk %d]\n%s\n\n", chunk.index, chunk.text)
+	}
+	req := ai.ChatRequest{
+		System:    fmt.Sprintf(consts.FLASHCARD_PROMPT, r.language, n),
+		Messages:  []ai.Message{{Role: ai.RoleUser, Content: strings.TrimSpace(b.String())}},
+		MaxTokens: consts.FLASHCARD_MAX_OUTPUT_TOKENS,
+	}
+
+	var drafts []flashcardDraft
+	resp, usage, err := ai.ChatJSON(ctx, r.provider, req, consts.FLASHCARD_OUTPUT_ATTEMPTS, func(object string) error {
+		var err error
+		drafts, err = parseFlashcards(object, batch)
+		return err
+	})
+	r.calls++
+	r.usage.InputTokens += usage.InputTokens
+	r.usage.OutputTokens += usage.OutputTokens
+	if err != nil {
+		return nil, err
+	}
+	r.model = resp.Model
+
+	if len(drafts) > n {
+		drafts = drafts[:n]
+	}
+	return drafts, nil
+}
+
+// parseFlashcards decodes and validates generated cards. A card citing a
+// chunk outside the batch is attributed to the batch's first chunk.
+func parseFlashcards(object string, batch []sourceChunk) ([]flashcardDraft, error) {
+	var raw struct {
+		Cards []struct {
+			Front string `json:"front"`
+			Back  string `json:"back"`
+			Chunk *int   `json:"chunk"`
+		} `json:"cards"`
+	}
+	if err := json.Unmarshal([]byte(object), &raw); err != nil {
+		return nil, err
+	}
+	if len(raw.Cards) == 0 {
+		return nil, errMessage.ErrInvalidFlashcardOutput
+	}
+
+	drafts := make([]flashcardDraft, 0, len(raw.Cards))
+	for i, card := range raw.Cards {
+		front, back := strings.TrimSpace(card.Front), strings.TrimSpace(card.Back)
+		if front == "" || back == "" {
+			return nil, fmt.Errorf("%w: card %d has an empty side", errMessage.ErrInvalidFlashcardOutput, i+1)
+		}
+		if utf8.RuneCountInString(front) > consts.FLASHCARD_FRONT_MAX_RUNES || utf8.RuneCountInString(back) > consts.FLASHCARD_BACK_MAX_RUNES {
+			return nil, fmt.Errorf("%w: card %d is too long", errMessage.ErrInvalidFlashcardOutput, i+1)
+		}
+
+		draft := flashcardDraft{front: front, back: back, chunk: batch[0]}
+		if card.Chunk != nil {
+			for _, chunk := range batch {
+				if chunk.index == *card.Chunk {
+					draft.chunk = chunk
+					break
+				}
+			}
+		}
+		drafts = append(drafts, draft)
+	}
+	return drafts, nil
+}
+
+// batchChunks packs consecutive chunks into batches within the token budget
+func batchChunks(chunks []sourceChunk, budget int) [][]sourceChunk {
+	var batches [][]sourceChunk
+	var current []sourceChunk
+	tokens := 0
+	for _, chunk := range chunks {
+		size := ai.EstimateTokens(chunk.text)
+		if len(current) > 0 && tokens+size > budget {
+			batches = append(batches, current)
+			current, tokens = nil, 0
+		}
+		current = append(current, chunk)
+		tokens += size
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// sampleBatches keeps at most limit batches spread evenly over the source
+func sampleBatches(batches [][]sourceChunk, limit int) [][]sourceChunk {
+	if limit <= 0 || len(batches) <= limit {
+		return batches
+	}
+	sampled := make([][]sourceChunk, limit)
+	for i := range sampled {
+		sampled[i] = batches[i*len(batches)/limit]
+	}
+	return sampled
+}
+
+// shareCount splits count cards between the batches in proportion to their
+// size, giving every batch at least one. There are never more batches than cards.
+func shareCount(batches [][]sourceChunk, count int) []int {
+	sizes := make([]int, len(batches))
+	total := 0
+	for i, batch := range batches {
+		for _, chunk := range batch {
+			sizes[i] += ai.EstimateTokens(chunk.text)
+		}
+		sizes[i] = max(sizes[i], 1)
+		total += sizes[i]
+	}
+
+	counts := make([]int, len(batches))
+	remaining := count - len(batches)
+	assigned := 0
+	remainders := make([]int, len(batches))
+	for i := range batches {
+		share := remaining * sizes[i]
+		counts[i] = 1 + share/total
+		remainders[i] = share % total
+		assigned += share / total
+	}
+	// Hand out what rounding left over to the largest remainders
+	for ; assigned < remaining; assigned++ {
+		best := 0
+		for i := range remainders {
+			if remainders[i] > remainders[best] {
+				best = i
+			}
+		}
+		counts[best]++
+		remainders[best] = -1
+	}
+	return counts
+}
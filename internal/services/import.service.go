@@ -0,0 +1,648 @@
+package services
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/ical"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// courseCodePattern matches a leading course code such as "CS101", "CS 101" or "MATH-2001A"
+var courseCodePattern = regexp.MustCompile(`^\s*([A-Za-z]{2,6})[\s-]?(\d{2,5}[A-Za-z]?)\b[\s:\-–]*(.*)$`)
+
+type ITimetableImportService interface {
+	// ImportTimetable maps the VEVENTs of an iCalendar file to courses and class sessions.
+	// Nothing is written unless commit is true; the result doubles as the preview.
+	// The file replaces the sessions imported earlier from the same source, which defaults
+	// to the calendar's name. Clashing sessions are only saved when allowConflicts is set.
+	ImportTimetable(ctx context.Context, userID string, file io.Reader, source string, semesterID *int, commit, allowConflicts bool) (*models.TimetableImportResult, int)
+}
+
+type TimetableImportService struct {
+	userRepo     repo.IUserRepository
+	courseRepo   repo.ICourseRepository
+	semesterRepo repo.ISemesterRepository
+	sessionRepo  repo.IClassSessionRepository
+	quotaService IQuotaService
+}
+
+func NewTimetableImportService(userRepository repo.IUserRepository, courseRepository repo.ICourseRepository, semesterRepository repo.ISemesterRepository,
+	sessionRepository repo.IClassSessionRepository, quotaService IQuotaService) ITimetableImportService {
+	return &TimetableImportService{
+		userRepo:     userRepository,
+		courseRepo:   courseRepository,
+		semesterRepo: semesterRepository,
+		sessionRepo:  sessionRepository,
+		quotaService: quotaService,
+	}
+}
+
+// importedSession is a class session planned from one VEVENT (and one weekday of it)
+type importedSession struct {
+	session    models.ClassSession
+	exceptions []time.Time
+	series     string // UID of the event, shared by the sessions of its weekdays
+	courseCode string
+	courseName string
+	action     string
+	existing   *models.ClassSession
+	conflicts  []models.SessionConflict
+}
+
+func (s *TimetableImportService) ImportTimetable(ctx context.Context, userID string, file io.Reader, source string, semesterID *int, commit, allowConflicts bool) (*models.TimetableImportResult, int) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.CodeUserNotFound
+		}
+
+		global.Log.Error("Error getting user", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	loc := userLocation(user)
+
+	calendar, err := ical.Parse(file, loc)
+	if err != nil {
+		global.Log.Warn(errMessage.ErrInvalidTimetableImport.Error(), zap.String("userID", userID), zap.Error(err))
+		return nil, response.CodeTimetableImportInvalid
+	}
+
+	source = importSource(source, calendar)
+	planned, skipped := planImportSessions(calendar, loc)
+	for i := range planned {
+		planned[i].session.ImportSource = source
+	}
+	result := &models.TimetableImportResult{
+		Source:   source,
+		Courses:  []models.TimetableImportCourse{},
+		Sessions: []models.TimetableImportSession{},
+		Skipped:  skipped,
+	}
+
+	// Resolve course codes against the user's courses
+	courses, err := s.courseRepo.ListCourses(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error listing courses", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	courseByCode := map[string]*models.Course{}
+	for i := range courses {
+		courseByCode[normalizeCourseCode(courses[i].CourseID)] = &courses[i]
+	}
+
+	var newCourses []*models.Course
+	seenCodes := map[string]bool{}
+	for _, plan := range planned {
+		code := normalizeCourseCode(plan.courseCode)
+		if seenCodes[code] {
+			continue
+		}
+		seenCodes[code] = true
+
+		if course, ok := courseByCode[code]; ok {
+			result.Courses = append(result.Courses, models.TimetableImportCourse{
+				ID: course.ID, CourseCode: course.CourseID, CourseName: course.CourseName, Action: consts.TimetableImportAction.EXISTING,
+			})
+			continue
+		}
+		course := &models.Course{UserID: userID, CourseID: plan.courseCode, CourseName: plan.courseName}
+		courseByCode[code] = course
+		newCourses = append(newCourses, course)
+	}
+
+	if len(newCourses) > 0 {
+		semester, code := s.resolveSemester(ctx, semesterID, planned)
+		if code != response.CodeSuccess {
+			return nil, code
+		}
+		for _, course := range newCourses {
+			course.SemesterID = semester
+		}
+	}
+
+	existing, err := s.sessionRepo.ListSessions(ctx, userID, nil)
+	if err != nil {
+		global.Log.Error("Error listing class sessions", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	removed := matchImportedSessions(existing, planned, source)
+	conflicted := findImportConflicts(existing, planned, removed)
+
+	if commit {
+		if conflicted > 0 && !allowConflicts {
+			global.Log.Warn(errMessage.ErrClassSessionConflict.Error(), zap.String("userID", userID), zap.Int("conflicts", conflicted))
+			return importResult(result, newCourses, planned, removed), response.CodeClassSessionConflict
+		}
+		// Imported courses count against the plan's course limit
+		if code := s.quotaService.CheckCourses(ctx, userID, len(newCourses)); code != response.CodeSuccess {
+			return nil, code
+		}
+		if code := s.commitImport(ctx, userID, newCourses, courseByCode, planned, removed); code != response.CodeSuccess {
+			return nil, code
+		}
+		result.Committed = true
+		global.Log.Info("Success importing timetable", zap.String("userID", userID), zap.Int("courses", len(newCourses)), zap.Int("sessions", len(planned)), zap.Int("removed", len(removed)))
+	}
+
+	return importResult(result, newCourses, planned, removed), response.CodeSuccess
+}
+
+// matchImportedSessions pairs planned sessions with the sessions of earlier imports from
+// the same source and sets their action. Sessions match by UID first. A multi-day event
+// keys its sessions uid#MO, uid#WE, ..., so when its days change upstream the sessions of
+// the days it dropped are reused for the days it gained. The source's sessions left over
+// belong to events no longer in the file and are returned for removal. Sessions imported
+// before sources were recorded can be matched by any source but are never removed.
+func matchImportedSessions(existing []models.ClassSession, planned []importedSession, source string) []*models.ClassSession {
+	matched := map[int]bool{}
+	claim := func(plan *importedSession, fits func(current *models.ClassSession) bool) {
+		for i := range existing {
+			current := &existing[i]
+			fromSource := current.ImportSource == source || current.ImportSource == ""
+			if current.ExternalUID.Valid && fromSource && !matched[current.ID] && fits(current) {
+				plan.existing = current
+				matched[current.ID] = true
+				return
+			}
+		}
+	}
+
+	for i := range planned {
+		plan := &planned[i]
+		claim(plan, func(current *models.ClassSession) bool {
+			return current.ExternalUID.String == plan.session.ExternalUID.String
+		})
+	}
+	for _, sameDay := range []bool{true, false} {
+		for i := range planned {
+			plan := &planned[i]
+			if plan.existing != nil {
+				continue
+			}
+			claim(plan, func(current *models.ClassSession) bool {
+				return importSeries(current.ExternalUID.String) == plan.series && (!sameDay || current.Weekday == plan.session.Weekday)
+			})
+		}
+	}
+
+	for i := range planned {
+		plan := &planned[i]
+		switch {
+		case plan.existing == nil:
+			plan.action = consts.TimetableImportAction.CREATE
+		case sameImportedSession(plan.existing, plan):
+			plan.action = consts.TimetableImportAction.UNCHANGED
+		default:
+			plan.action = consts.TimetableImportAction.UPDATE
+		}
+	}
+
+	var removed []*models.ClassSession
+	for i := range existing {
+		if existing[i].ExternalUID.Valid && existing[i].ImportSource == source && !matched[existing[i].ID] {
+			removed = append(removed, &existing[i])
+		}
+	}
+	return removed
+}
+
+// findImportConflicts checks every planned session against the other planned ones and
+// the user's sessions that the import leaves in place. It returns how many clash.
+func findImportConflicts(existing []models.ClassSession, planned []importedSession, removed []*models.ClassSession) int {
+	replaced := map[int]bool{}
+	for _, plan := range planned {
+		if plan.existing != nil {
+			replaced[plan.existing.ID] = true
+		}
+	}
+	for _, session := range removed {
+		replaced[session.ID] = true
+	}
+
+	var timetable []models.ClassSession
+	for _, session := range existing {
+		if !replaced[session.ID] {
+			timetable = append(timetable, session)
+		}
+	}
+	for _, plan := range planned {
+		session := plan.session
+		session.Course = &models.Course{CourseID: plan.courseCode, CourseName: plan.courseName}
+		if plan.existing != nil {
+			session.ID = plan.existing.ID
+		}
+		for _, date := range plan.exceptions {
+			session.Exceptions = append(session.Exceptions, models.ClassSessionException{Date: date})
+		}
+		timetable = append(timetable, session)
+	}
+
+	conflicted := 0
+	offset := len(timetable) - len(planned)
+	for i := range planned {
+		candidate := &timetable[offset+i]
+		others := slices.Concat(timetable[:offset+i], timetable[offset+i+1:])
+		planned[i].conflicts = sessionConflicts(candidate, others)
+		if len(planned[i].conflicts) > 0 {
+			conflicted++
+		}
+	}
+	return conflicted
+}
+
+func importResult(result *models.TimetableImportResult, newCourses []*models.Course, planned []importedSession, removed []*models.ClassSession) *models.TimetableImportResult {
+	for _, course := range newCourses {
+		result.Courses = append(result.Courses, models.TimetableImportCourse{
+			ID: course.ID, CourseCode: course.CourseID, CourseName: course.CourseName, Action: consts.TimetableImportAction.CREATE,
+		})
+	}
+	for _, plan := range planned {
+		result.Sessions = append(result.Sessions, importSessionResult(&plan))
+	}
+	for _, session := range removed {
+		result.Sessions = append(result.Sessions, removedSessionResult(session))
+	}
+	return result
+}
+
+// resolveSemester validates the requested semester or detects it from the earliest imported date
+func (s *TimetableImportService) resolveSemester(ctx context.Context, semesterID *int, planned []importedSession) (int, int) {
+	if semesterID != nil {
+		if _, err := s.semesterRepo.GetSemesterByID(ctx, *semesterID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				global.Log.Warn(errMessage.ErrSemesterNotFound.Error(), zap.Int("semesterID", *semesterID))
+				return 0, response.CodeSemesterNotFound
+			}
+
+			global.Log.Error("Error getting semester", zap.Error(err), zap.Int("semesterID", *semesterID))
+			return 0, response.CodeServerBusy
+		}
+		return *semesterID, response.CodeSuccess
+	}
+
+	earliest := planned[0].session.StartDate
+	for _, plan := range planned[1:] {
+		if plan.session.StartDate.Before(earliest) {
+			earliest = plan.session.StartDate
+		}
+	}
+
+	semester, err := s.semesterRepo.FindSemesterByDate(ctx, earliest)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrSemesterNotFound.Error(), zap.String("date", earliest.Format(consts.DATE_LAYOUT)))
+			return 0, response.CodeTimetableImportNoSemester
+		}
+
+		global.Log.Error("Error finding semester", zap.Error(err))
+		return 0, response.CodeServerBusy
+	}
+	return semester.ID, response.CodeSuccess
+}
+
+func (s *TimetableImportService) commitImport(ctx context.Context, userID string, newCourses []*models.Course, courseByCode map[string]*models.Course, planned []importedSession, removed []*models.ClassSession) int {
+	err := s.sessionRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		courseRepo := s.courseRepo.WithTx(tx)
+		sessionRepo := s.sessionRepo.WithTx(tx)
+
+		for _, session := range removed {
+			if err := sessionRepo.DeleteSession(ctx, session.ID, userID); err != nil {
+				return fmt.Errorf("delete session %d: %w", session.ID, err)
+			}
+		}
+
+		for _, course := range newCourses {
+			if err := courseRepo.CreateCourse(ctx, course); err != nil {
+				return fmt.Errorf("create course %s: %w", course.CourseID, err)
+			}
+		}
+
+		for i := range planned {
+			plan := &planned[i]
+			plan.session.CourseID = courseByCode[normalizeCourseCode(plan.courseCode)].ID
+
+			switch plan.action {
+			case consts.TimetableImportAction.CREATE:
+				if err := sessionRepo.CreateSession(ctx, &plan.session); err != nil {
+					return fmt.Errorf("create session %s: %w", plan.session.ExternalUID.String, err)
+				}
+			case consts.TimetableImportAction.UPDATE:
+				plan.session.ID = plan.existing.ID
+				updates := map[string]any{
+					"external_uid":  plan.session.ExternalUID,
+					"import_source": plan.session.ImportSource,
+					"course_id":     plan.session.CourseID,
+					"weekday":       plan.session.Weekday,
+					"start_time":    plan.session.StartTime,
+					"end_time":      plan.session.EndTime,
+					"location":      plan.session.Location,
+					"recurrence":    plan.session.Recurrence,
+					"start_date":    plan.session.StartDate,
+					"end_date":      plan.session.EndDate,
+				}
+				if err := sessionRepo.UpdateSession(ctx, plan.session.ID, userID, updates); err != nil {
+					return fmt.Errorf("update session %d: %w", plan.session.ID, err)
+				}
+			default:
+				plan.session.ID = plan.existing.ID
+				continue
+			}
+
+			exceptions := make([]models.ClassSessionException, 0, len(plan.exceptions))
+			for _, date := range plan.exceptions {
+				exceptions = append(exceptions, models.ClassSessionException{Date: date})
+			}
+			if err := sessionRepo.ReplaceExceptions(ctx, plan.session.ID, exceptions); err != nil {
+				return fmt.Errorf("save exceptions of session %d: %w", plan.session.ID, err)
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		global.Log.Error("Error committing timetable import", zap.Error(err), zap.String("userID", userID))
+		return response.CodeServerBusy
+	}
+	return response.CodeSuccess
+}
+
+// planImportSessions turns calendar events into class sessions in loc. Events that
+// cannot be represented (all-day, multi-day, non-weekly rules) are reported as skipped.
+func planImportSessions(calendar *ical.Calendar, loc *time.Location) ([]importedSession, []models.TimetableImportSkipped) {
+	skipped := []models.TimetableImportSkipped{}
+	skip := func(event *ical.Event, reason string) {
+		skipped = append(skipped, models.TimetableImportSkipped{UID: event.UID, Summary: event.Summary, Reason: reason})
+	}
+
+	// Moved or edited occurrences cancel the original date of their series
+	cancelled := map[string][]time.Time{}
+	for _, event := range calendar.Events {
+		if !event.RecurrenceID.IsZero() {
+			cancelled[event.UID] = append(cancelled[event.UID], dateIn(event.RecurrenceID, loc))
+		}
+	}
+
+	var planned []importedSession
+	seen := map[string]bool{}
+	for i := range calendar.Events {
+		event := &calendar.Events[i]
+		uid := event.UID
+		if uid == "" {
+			uid = syntheticUID(event)
+		}
+		if !event.RecurrenceID.IsZero() {
+			uid += "/" + dateIn(event.RecurrenceID, loc).Format(consts.DATE_LAYOUT)
+		}
+
+		start, end := event.Start.In(loc), event.End.In(loc)
+		switch {
+		case event.AllDay:
+			skip(event, "all-day events are not class sessions")
+			continue
+		case event.End.IsZero() || !end.After(start):
+			skip(event, "event has no duration")
+			continue
+		case dateIn(start, loc) != dateIn(end, loc):
+			skip(event, "event spans several days")
+			continue
+		case strings.TrimSpace(event.Summary) == "":
+			skip(event, "event has no summary to derive a course from")
+			continue
+		}
+
+		days := []time.Weekday{start.Weekday()}
+		recurrence := consts.ClassSessionRecurrence.WEEKLY
+		startDate := dateIn(start, loc)
+		endDate := sql.NullTime{Time: startDate, Valid: true}
+
+		if event.RRule != "" && event.RecurrenceID.IsZero() {
+			rule, err := ical.ParseRule(event.RRule, loc)
+			if err != nil || rule.Freq != "WEEKLY" || rule.Interval > 2 {
+				skip(event, "only weekly and bi-weekly recurrences are supported")
+				continue
+			}
+			if rule.Interval == 2 {
+				recurrence = consts.ClassSessionRecurrence.BIWEEKLY
+			}
+			if len(rule.ByDay) > 0 {
+				days = rule.ByDay
+			}
+
+			switch {
+			case !rule.Until.IsZero():
+				endDate = sql.NullTime{Time: dateIn(rule.Until, loc), Valid: true}
+			case rule.Count > 0:
+				// COUNT spreads over every BYDAY; end after the week holding the last occurrence
+				weeks := (rule.Count + len(days) - 1) / len(days)
+				endDate = sql.NullTime{Time: startDate.AddDate(0, 0, (weeks-1)*7*rule.Interval+6), Valid: true}
+			default:
+				endDate = sql.NullTime{}
+			}
+		}
+
+		courseCode, courseName := parseCourseSummary(event.Summary)
+		for _, day := range days {
+			key := uid
+			if len(days) > 1 {
+				key += "#" + ical.Weekday(day)
+			}
+			if seen[key] {
+				skip(event, "duplicate event UID")
+				continue
+			}
+			seen[key] = true
+
+			session := models.ClassSession{
+				Weekday:     int8(day),
+				StartTime:   start.Format(time.TimeOnly),
+				EndTime:     end.Format(time.TimeOnly),
+				Recurrence:  recurrence,
+				StartDate:   startDate,
+				EndDate:     endDate,
+				ExternalUID: sql.NullString{String: key, Valid: true},
+			}
+			if location := strings.TrimSpace(event.Location); location != "" {
+				session.Location = sql.NullString{String: location, Valid: true}
+			}
+
+			var exceptions []time.Time
+			for _, exDate := range append(slices.Clone(event.ExDates), cancelled[event.UID]...) {
+				date := dateIn(exDate, loc)
+				if session.IsScheduledOn(date) && !slices.ContainsFunc(exceptions, date.Equal) {
+					exceptions = append(exceptions, date)
+				}
+			}
+			slices.SortFunc(exceptions, func(a, b time.Time) int { return a.Compare(b) })
+
+			planned = append(planned, importedSession{
+				session:    session,
+				exceptions: exceptions,
+				series:     uid,
+				courseCode: courseCode,
+				courseName: courseName,
+			})
+		}
+	}
+	return planned, skipped
+}
+
+func sameImportedSession(current *models.ClassSession, plan *importedSession) bool {
+	next := &plan.session
+	if current.ExternalUID != next.ExternalUID || current.ImportSource != next.ImportSource || current.Weekday != next.Weekday || current.StartTime != next.StartTime || current.EndTime != next.EndTime ||
+		current.Recurrence != next.Recurrence || current.Location != next.Location ||
+		!storedDate(current.StartDate).Equal(next.StartDate) || current.EndDate.Valid != next.EndDate.Valid {
+		return false
+	}
+	if current.EndDate.Valid && !storedDate(current.EndDate.Time).Equal(next.EndDate.Time) {
+		return false
+	}
+	if current.Course != nil && normalizeCourseCode(current.Course.CourseID) != normalizeCourseCode(plan.courseCode) {
+		return false
+	}
+
+	if len(current.Exceptions) != len(plan.exceptions) {
+		return false
+	}
+	for _, exception := range current.Exceptions {
+		if !slices.ContainsFunc(plan.exceptions, storedDate(exception.Date).Equal) {
+			return false
+		}
+	}
+	return true
+}
+
+func importSessionResult(plan *importedSession) models.TimetableImportSession {
+	result := models.TimetableImportSession{
+		SessionID:  plan.session.ID,
+		UID:        plan.session.ExternalUID.String,
+		CourseCode: plan.courseCode,
+		Weekday:    plan.session.Weekday,
+		StartTime:  plan.session.StartTime,
+		EndTime:    plan.session.EndTime,
+		Location:   plan.session.Location.String,
+		Recurrence: plan.session.Recurrence,
+		StartDate:  plan.session.StartDate.Format(consts.DATE_LAYOUT),
+		Action:     plan.action,
+	}
+	if plan.existing != nil {
+		result.SessionID = plan.existing.ID
+	}
+	if plan.session.EndDate.Valid {
+		result.EndDate = plan.session.EndDate.Time.Format(consts.DATE_LAYOUT)
+	}
+	for _, date := range plan.exceptions {
+		result.Exceptions = append(result.Exceptions, date.Format(consts.DATE_LAYOUT))
+	}
+	result.Conflicts = plan.conflicts
+	return result
+}
+
+// removedSessionResult describes a session of an earlier import whose event left the file
+func removedSessionResult(session *models.ClassSession) models.TimetableImportSession {
+	result := models.TimetableImportSession{
+		SessionID:  session.ID,
+		UID:        session.ExternalUID.String,
+		Weekday:    session.Weekday,
+		StartTime:  session.StartTime,
+		EndTime:    session.EndTime,
+		Location:   session.Location.String,
+		Recurrence: session.Recurrence,
+		StartDate:  storedDate(session.StartDate).Format(consts.DATE_LAYOUT),
+		Action:     consts.TimetableImportAction.DELETE,
+	}
+	if session.Course != nil {
+		result.CourseCode = session.Course.CourseID
+	}
+	if session.EndDate.Valid {
+		result.EndDate = storedDate(session.EndDate.Time).Format(consts.DATE_LAYOUT)
+	}
+	return result
+}
+
+// parseCourseSummary splits "CS101 Algorithms" into code and name. Summaries
+// without a recognisable code use the whole summary as both.
+func parseCourseSummary(summary string) (string, string) {
+	summary = strings.TrimSpace(summary)
+	match := courseCodePattern.FindStringSubmatch(summary)
+	if match == nil {
+		return truncate(summary, 255), truncate(summary, 255)
+	}
+
+	code := strings.ToUpper(match[1] + match[2])
+	name := strings.TrimSpace(match[3])
+	if name == "" {
+		name = code
+	}
+	return code, truncate(name, 255)
+}
+
+func normalizeCourseCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
+
+// importSource names the timetable a file belongs to: the requested source, else the
+// calendar's name or, failing that, the application that produced it
+func importSource(requested string, calendar *ical.Calendar) string {
+	for _, source := range []string{requested, calendar.Name, calendar.ProdID} {
+		if source = strings.TrimSpace(source); source != "" {
+			return truncate(source, 255)
+		}
+	}
+	return consts.TIMETABLE_IMPORT_DEFAULT_SOURCE
+}
+
+// importSeries strips the weekday suffix from the UID of a multi-day event's session
+func importSeries(uid string) string {
+	i := strings.LastIndex(uid, "#")
+	if i < 0 {
+		return uid
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if uid[i+1:] == ical.Weekday(day) {
+			return uid[:i]
+		}
+	}
+	return uid
+}
+
+// syntheticUID derives a stable key for events without a UID so re-imports still deduplicate
+func syntheticUID(event *ical.Event) string {
+	sum := sha1.Sum([]byte(event.Summary + "|" + event.Start.UTC().Format(time.RFC3339)))
+	return "generated-" + hex.EncodeToString(sum[:8])
+}
+
+// dateIn returns midnight UTC of t's calendar date in loc, matching DATE columns
+func dateIn(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// storedDate normalises a value read from a DATE column to midnight UTC
+func storedDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func truncate(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit])
+}
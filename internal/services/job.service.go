@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+
+	"github.com/nas03/scholar-ai/backend/internal/queue"
+)
+
+// IJobQueue is the part of the background job queue services enqueue work on.
+// *queue.Client implements it.
+type IJobQueue interface {
+	Enqueue(ctx context.Context, jobType string, payload any, options ...queue.EnqueueOption) (*queue.Job, error)
+}
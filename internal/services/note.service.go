@@ -0,0 +1,568 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/pkg/diff"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"github.com/nas03/scholar-ai/backend/pkg/richtext"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type INoteService interface {
+	CreateNote(ctx context.Context, userID string, req *models.CreateNoteRequest) (*models.Note, int)
+	GetNote(ctx context.Context, userID string, id int) (*models.Note, int)
+	ListNotes(ctx context.Context, userID string, query *models.NoteQuery) ([]models.Note, int)
+	// UpdateNote and RestoreRevision refuse with CodeNoteVersionConflict when
+	// ifMatch is set and holds none of the note's current ETag
+	UpdateNote(ctx context.Context, userID string, id int, ifMatch string, req *models.UpdateNoteRequest) (*models.Note, int)
+	DeleteNote(ctx context.Context, userID string, id int) int
+	ListRevisions(ctx context.Context, userID string, id int) ([]models.NoteRevision, int)
+	GetRevision(ctx context.Context, userID string, id, version int) (*models.NoteRevision, int)
+	DiffRevisions(ctx context.Context, userID string, id int, query *models.NoteDiffQuery) (*models.NoteDiff, int)
+	RestoreRevision(ctx context.Context, userID string, id, version int, ifMatch string) (*models.Note, int)
+}
+
+type NoteService struct {
+	noteRepo    repo.INoteRepository
+	tagRepo     repo.ITagRepository
+	courseRepo  repo.ICourseRepository
+	collabStore repo.INoteCollabStore
+	jobs        IJobQueue
+}
+
+func NewNoteService(noteRepository repo.INoteRepository, tagRepository repo.ITagRepository, courseRepository repo.ICourseRepository, collabStore repo.INoteCollabStore, jobs IJobQueue) INoteService {
+	return &NoteService{
+		noteRepo:    noteRepository,
+		tagRepo:     tagRepository,
+		courseRepo:  courseRepository,
+		collabStore: collabStore,
+		jobs:        jobs,
+	}
+}
+
+// renderedContent is an editor document together with its server-side renderings
+type renderedContent struct {
+	JSON []byte
+	HTML string
+	Text string
+}
+
+// CreateNote stores a note as version 1 and records its first revision
+func (s *NoteService) CreateNote(ctx context.Context, userID string, req *models.CreateNoteRequest) (*models.Note, int) {
+	if code := s.checkCourse(ctx, userID, req.CourseID); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	lectureDate, err := time.Parse(consts.DATE_LAYOUT, req.LectureDate)
+	if err != nil {
+		global.Log.Warn(errMessage.ErrInvalidLectureDate.Error(), zap.String("lecture_date", req.LectureDate))
+		return nil, response.CodeNoteInvalidDate
+	}
+
+	content, code := renderContent(req.Content)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	tagNames, ok := normalizeTags(req.Tags)
+	if !ok {
+		global.Log.Warn(errMessage.ErrInvalidNoteTags.Error(), zap.Strings("tags", req.Tags))
+		return nil, response.CodeNoteInvalidTags
+	}
+
+	note := &models.Note{
+		UserID:      userID,
+		CourseID:    req.CourseID,
+		LectureDate: lectureDate,
+		Title:       strings.TrimSpace(req.Title),
+		Content:     content.JSON,
+		ContentHTML: content.HTML,
+		ContentText: content.Text,
+		Version:     1,
+	}
+
+	err = s.noteRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		tags, err := s.tagRepo.WithTx(tx).FindOrCreateTags(ctx, tagNames)
+		if err != nil {
+			return err
+		}
+		note.Tags = tags
+
+		noteRepo := s.noteRepo.WithTx(tx)
+		if err := noteRepo.CreateNote(ctx, note); err != nil {
+			return err
+		}
+		return noteRepo.CreateRevision(ctx, revisionOf(note, sql.NullInt64{}))
+	})
+	if err != nil {
+		global.Log.Error("Error creating note", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success creating note", zap.String("userID", userID), zap.Int("noteID", note.ID))
+	s.enqueueIndexing(ctx, userID, note.ID)
+	return s.GetNote(ctx, userID, note.ID)
+}
+
+func (s *NoteService) GetNote(ctx context.Context, userID string, id int) (*models.Note, int) {
+	note, err := s.noteRepo.GetNoteByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrNoteNotFound.Error(), zap.String("userID", userID), zap.Int("noteID", id))
+			return nil, response.CodeNoteNotFound
+		}
+
+		global.Log.Error("Error getting note", zap.Error(err), zap.Int("noteID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	return note, response.CodeSuccess
+}
+
+func (s *NoteService) ListNotes(ctx context.Context, userID string, query *models.NoteQuery) ([]models.Note, int) {
+	filter := models.NoteFilter{
+		UserID:   userID,
+		CourseID: query.CourseID,
+		Tag:      strings.TrimSpace(query.Tag),
+	}
+
+	for _, bound := range []struct {
+		value string
+		dest  **time.Time
+	}{{query.From, &filter.From}, {query.To, &filter.To}} {
+		if bound.value == "" {
+			continue
+		}
+		date, err := time.Parse(consts.DATE_LAYOUT, bound.value)
+		if err != nil {
+			global.Log.Warn(errMessage.ErrInvalidLectureDate.Error(), zap.String("date", bound.value))
+			return nil, response.CodeNoteInvalidDate
+		}
+		*bound.dest = &date
+	}
+
+	notes, err := s.noteRepo.ListNotes(ctx, filter)
+	if err != nil {
+		global.Log.Error("Error listing notes", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	return notes, response.CodeSuccess
+}
+
+// UpdateNote applies the changes. Edits to the title or content create a new
+// revision; changes to the course, date or tags alone do not. The content
+// cannot be replaced while the note is open in a collaborative session.
+func (s *NoteService) UpdateNote(ctx context.Context, userID string, id int, ifMatch string, req *models.UpdateNoteRequest) (*models.Note, int) {
+	note, code := s.GetNote(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	if !matchesETag(ifMatch, note) {
+		global.Log.Warn(errMessage.ErrNoteVersionConflict.Error(), zap.Int("noteID", id), zap.String("ifMatch", ifMatch), zap.String("etag", note.ETag()))
+		return nil, response.CodeNoteVersionConflict
+	}
+
+	updates := map[string]any{}
+	revised := false
+
+	if req.CourseID != nil && *req.CourseID != note.CourseID {
+		if code := s.checkCourse(ctx, userID, *req.CourseID); code != response.CodeSuccess {
+			return nil, code
+		}
+		updates["course_id"] = *req.CourseID
+	}
+	if req.LectureDate != nil {
+		lectureDate, err := time.Parse(consts.DATE_LAYOUT, *req.LectureDate)
+		if err != nil {
+			global.Log.Warn(errMessage.ErrInvalidLectureDate.Error(), zap.String("lecture_date", *req.LectureDate))
+			return nil, response.CodeNoteInvalidDate
+		}
+		updates["lecture_date"] = lectureDate
+	}
+	if req.Title != nil {
+		if title := strings.TrimSpace(*req.Title); title != note.Title {
+			note.Title = title
+			updates["title"] = title
+			revised = true
+		}
+	}
+	if len(req.Content) > 0 {
+		content, code := renderContent(req.Content)
+		if code != response.CodeSuccess {
+			return nil, code
+		}
+		if string(content.JSON) != string(note.Content) {
+			note.Content, note.ContentHTML, note.ContentText = content.JSON, content.HTML, content.Text
+			updates["content"] = note.Content
+			updates["content_html"] = note.ContentHTML
+			updates["content_text"] = note.ContentText
+			revised = true
+		}
+	}
+
+	var tagNames []string
+	if req.Tags != nil {
+		var ok bool
+		if tagNames, ok = normalizeTags(*req.Tags); !ok {
+			global.Log.Warn(errMessage.ErrInvalidNoteTags.Error(), zap.Strings("tags", *req.Tags))
+			return nil, response.CodeNoteInvalidTags
+		}
+	}
+
+	if revised {
+		note.Version++
+		updates["version"] = note.Version
+	}
+	if len(updates) == 0 && req.Tags != nil {
+		// Tag changes alone still have to move the ETag
+		updates["updated_at"] = time.Now()
+	}
+
+	err := s.noteRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		noteRepo := s.noteRepo.WithTx(tx)
+		_, contentChanged := updates["content"]
+		if err := s.lockForWrite(ctx, noteRepo, id, userID, ifMatch, contentChanged); err != nil {
+			return err
+		}
+
+		if len(updates) > 0 {
+			if err := noteRepo.UpdateNote(ctx, id, userID, updates); err != nil {
+				return err
+			}
+		}
+		if req.Tags != nil {
+			tags, err := s.tagRepo.WithTx(tx).FindOrCreateTags(ctx, tagNames)
+			if err != nil {
+				return err
+			}
+			if err := noteRepo.ReplaceTags(ctx, note, tags); err != nil {
+				return err
+			}
+		}
+		if revised {
+			return s.addRevision(ctx, noteRepo, note, sql.NullInt64{})
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errMessage.ErrNoteVersionConflict) {
+			global.Log.Warn(err.Error(), zap.Int("noteID", id), zap.String("ifMatch", ifMatch))
+			return nil, response.CodeNoteVersionConflict
+		}
+		if errors.Is(err, errMessage.ErrNoteCollabActive) {
+			global.Log.Warn(err.Error(), zap.Int("noteID", id))
+			return nil, response.CodeNoteCollabActive
+		}
+
+		global.Log.Error("Error updating note", zap.Error(err), zap.Int("noteID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success updating note", zap.String("userID", userID), zap.Int("noteID", id), zap.Int("version", note.Version))
+	if _, moved := updates["course_id"]; revised || moved {
+		s.enqueueIndexing(ctx, userID, id)
+	}
+	return s.GetNote(ctx, userID, id)
+}
+
+func (s *NoteService) DeleteNote(ctx context.Context, userID string, id int) int {
+	if err := s.noteRepo.DeleteNote(ctx, id, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrNoteNotFound.Error(), zap.String("userID", userID), zap.Int("noteID", id))
+			return response.CodeNoteNotFound
+		}
+
+		global.Log.Error("Error deleting note", zap.Error(err), zap.Int("noteID", id))
+		return response.CodeServerBusy
+	}
+
+	global.Log.Info("Success deleting note", zap.String("userID", userID), zap.Int("noteID", id))
+	return response.CodeSuccess
+}
+
+func (s *NoteService) ListRevisions(ctx context.Context, userID string, id int) ([]models.NoteRevision, int) {
+	if _, code := s.GetNote(ctx, userID, id); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	revisions, err := s.noteRepo.ListRevisions(ctx, id)
+	if err != nil {
+		global.Log.Error("Error listing note revisions", zap.Error(err), zap.Int("noteID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	return revisions, response.CodeSuccess
+}
+
+func (s *NoteService) GetRevision(ctx context.Context, userID string, id, version int) (*models.NoteRevision, int) {
+	if _, code := s.GetNote(ctx, userID, id); code != response.CodeSuccess {
+		return nil, code
+	}
+	return s.getRevision(ctx, id, version)
+}
+
+// DiffRevisions compares two revisions line by line. Without parameters it
+// shows the latest change.
+func (s *NoteService) DiffRevisions(ctx context.Context, userID string, id int, query *models.NoteDiffQuery) (*models.NoteDiff, int) {
+	note, code := s.GetNote(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	to := query.To
+	if to == 0 {
+		to = note.Version
+	}
+	from := query.From
+	if from == 0 {
+		from = to - 1
+	}
+
+	toRevision, code := s.getRevision(ctx, id, to)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	// The first revision is compared against an empty note
+	fromRevision := &models.NoteRevision{Version: from}
+	if from > 0 {
+		if fromRevision, code = s.getRevision(ctx, id, from); code != response.CodeSuccess {
+			return nil, code
+		}
+	}
+
+	result := &models.NoteDiff{
+		From:      fromRevision.Version,
+		To:        toRevision.Version,
+		FromTitle: fromRevision.Title,
+		ToTitle:   toRevision.Title,
+		Lines:     diff.Lines(fromRevision.ContentText, toRevision.ContentText),
+	}
+	for _, line := range result.Lines {
+		switch line.Op {
+		case diff.Insert:
+			result.Added++
+		case diff.Delete:
+			result.Removed++
+		}
+	}
+
+	return result, response.CodeSuccess
+}
+
+// RestoreRevision copies an old revision into the note as a new version, so
+// the history stays append-only
+func (s *NoteService) RestoreRevision(ctx context.Context, userID string, id, version int, ifMatch string) (*models.Note, int) {
+	note, code := s.GetNote(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	if !matchesETag(ifMatch, note) {
+		global.Log.Warn(errMessage.ErrNoteVersionConflict.Error(), zap.Int("noteID", id), zap.String("ifMatch", ifMatch), zap.String("etag", note.ETag()))
+		return nil, response.CodeNoteVersionConflict
+	}
+
+	revision, code := s.getRevision(ctx, id, version)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	content, code := renderContent(revision.Content)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	note.Title = revision.Title
+	note.Content, note.ContentHTML, note.ContentText = content.JSON, content.HTML, content.Text
+	note.Version++
+
+	err := s.noteRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		noteRepo := s.noteRepo.WithTx(tx)
+		if err := s.lockForWrite(ctx, noteRepo, id, userID, ifMatch, true); err != nil {
+			return err
+		}
+		err := noteRepo.UpdateNote(ctx, id, userID, map[string]any{
+			"title":        note.Title,
+			"content":      note.Content,
+			"content_html": note.ContentHTML,
+			"content_text": note.ContentText,
+			"version":      note.Version,
+		})
+		if err != nil {
+			return err
+		}
+		return s.addRevision(ctx, noteRepo, note, sql.NullInt64{Int64: int64(version), Valid: true})
+	})
+	if err != nil {
+		if errors.Is(err, errMessage.ErrNoteVersionConflict) {
+			global.Log.Warn(err.Error(), zap.Int("noteID", id), zap.String("ifMatch", ifMatch))
+			return nil, response.CodeNoteVersionConflict
+		}
+		if errors.Is(err, errMessage.ErrNoteCollabActive) {
+			global.Log.Warn(err.Error(), zap.Int("noteID", id))
+			return nil, response.CodeNoteCollabActive
+		}
+
+		global.Log.Error("Error restoring note revision", zap.Error(err), zap.Int("noteID", id), zap.Int("version", version))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success restoring note revision", zap.String("userID", userID), zap.Int("noteID", id), zap.Int("restoredFrom", version))
+	s.enqueueIndexing(ctx, userID, id)
+	return s.GetNote(ctx, userID, id)
+}
+
+// lockForWrite locks the note row for the rest of the transaction and
+// repeats the If-Match check there, so two writers holding the same ETag
+// cannot both pass. Content changes are refused while people edit the note
+// together; sessions open under the same lock, so one cannot start between
+// this check and the write.
+func (s *NoteService) lockForWrite(ctx context.Context, noteRepo repo.INoteRepository, id int, userID, ifMatch string, content bool) error {
+	note, err := noteRepo.LockNote(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if !matchesETag(ifMatch, note) {
+		return errMessage.ErrNoteVersionConflict
+	}
+	if !content {
+		return nil
+	}
+
+	active, err := s.collabStore.Active(ctx, id, time.Now())
+	if err != nil {
+		return err
+	}
+	if active {
+		return errMessage.ErrNoteCollabActive
+	}
+	return nil
+}
+
+// matchesETag evaluates an If-Match header against the note. An empty header
+// skips the check; otherwise it is "*" or a list of ETags compared strongly.
+func matchesETag(ifMatch string, note *models.Note) bool {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "" || ifMatch == "*" {
+		return true
+	}
+	etag := note.ETag()
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		if strings.TrimSpace(candidate) == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueueIndexing queues re-embedding of the note for the assistant. A failure
+// only leaves the assistant with the previous text until the next edit.
+func (s *NoteService) enqueueIndexing(ctx context.Context, userID string, id int) {
+	payload := models.EmbeddingIndexPayload{NoteID: &id, UserID: userID}
+	if _, err := s.jobs.Enqueue(ctx, consts.JobType.EMBEDDING_INDEX, payload); err != nil {
+		global.Log.Error("Error enqueuing note indexing", zap.Error(err), zap.Int("noteID", id))
+	}
+}
+
+func (s *NoteService) getRevision(ctx context.Context, id, version int) (*models.NoteRevision, int) {
+	revision, err := s.noteRepo.GetRevision(ctx, id, version)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrNoteRevisionNotFound.Error(), zap.Int("noteID", id), zap.Int("version", version))
+			return nil, response.CodeNoteRevisionNotFound
+		}
+
+		global.Log.Error("Error getting note revision", zap.Error(err), zap.Int("noteID", id), zap.Int("version", version))
+		return nil, response.CodeServerBusy
+	}
+
+	return revision, response.CodeSuccess
+}
+
+// addRevision records the note's current state and drops revisions beyond the retention limit
+func (s *NoteService) addRevision(ctx context.Context, noteRepo repo.INoteRepository, note *models.Note, restoredFrom sql.NullInt64) error {
+	if err := noteRepo.CreateRevision(ctx, revisionOf(note, restoredFrom)); err != nil {
+		return err
+	}
+	return noteRepo.PruneRevisions(ctx, note.ID, consts.NOTE_MAX_REVISIONS)
+}
+
+func (s *NoteService) checkCourse(ctx context.Context, userID string, courseID int) int {
+	if _, err := s.courseRepo.GetCourseByID(ctx, courseID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrCourseNotFound.Error(), zap.String("userID", userID), zap.Int("courseID", courseID))
+			return response.CodeCourseNotFound
+		}
+
+		global.Log.Error("Error getting course", zap.Error(err), zap.Int("courseID", courseID))
+		return response.CodeServerBusy
+	}
+	return response.CodeSuccess
+}
+
+func revisionOf(note *models.Note, restoredFrom sql.NullInt64) *models.NoteRevision {
+	return &models.NoteRevision{
+		NoteID:       note.ID,
+		Version:      note.Version,
+		Title:        note.Title,
+		Content:      note.Content,
+		ContentText:  note.ContentText,
+		RestoredFrom: restoredFrom,
+	}
+}
+
+// renderContent validates an editor document and renders the stored forms
+func renderContent(raw []byte) (*renderedContent, int) {
+	if len(raw) > consts.NOTE_MAX_CONTENT_BYTES {
+		global.Log.Warn(errMessage.ErrNoteContentTooLarge.Error(), zap.Int("bytes", len(raw)))
+		return nil, response.CodeNoteContentTooLarge
+	}
+
+	doc, err := richtext.Parse(raw)
+	if err != nil {
+		global.Log.Warn(errMessage.ErrInvalidNoteContent.Error(), zap.Error(err))
+		return nil, response.CodeNoteInvalidContent
+	}
+
+	normalized, err := doc.JSON()
+	if err != nil {
+		global.Log.Warn(errMessage.ErrInvalidNoteContent.Error(), zap.Error(err))
+		return nil, response.CodeNoteInvalidContent
+	}
+
+	return &renderedContent{JSON: normalized, HTML: doc.HTML(), Text: doc.PlainText()}, response.CodeSuccess
+}
+
+// normalizeTags trims and de-duplicates tag names case-insensitively, keeping the first spelling
+func normalizeTags(names []string) ([]string, bool) {
+	seen := map[string]bool{}
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if len([]rune(name)) > consts.NOTE_MAX_TAG_LENGTH {
+			return nil, false
+		}
+		key := strings.ToLower(name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, name)
+	}
+	return result, len(result) <= consts.NOTE_MAX_TAGS
+}
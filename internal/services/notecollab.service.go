@@ -0,0 +1,664 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/utils"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/ot"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"github.com/nas03/scholar-ai/backend/pkg/richtext"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// INoteCollabService runs collaborative editing sessions of notes. Clients
+// send operations on the linear form of the document (richtext.Token) made
+// on a revision they know; the server transforms them past the operations
+// that came first, applies them and relays them in order to every
+// connection, whichever API instance it is on. The document is saved into
+// the note as a new version every NOTE_COLLAB_SNAPSHOT_INTERVAL and when the
+// last connection of an instance leaves.
+type INoteCollabService interface {
+	// CheckAccess returns the note when the user wrote it or belongs to a
+	// study group it is shared into
+	CheckAccess(ctx context.Context, userID string, noteID int) (*models.Note, int)
+
+	// Connect joins the client to the note's session, opening one if needed,
+	// and sends it the document. Call leave once the connection is gone.
+	Connect(ctx context.Context, client *NoteCollabClient) (leave func(), code int)
+	// Submit applies an operation the client made on revision rev. It is
+	// acknowledged by the op event carrying the client's connection id.
+	Submit(ctx context.Context, client *NoteCollabClient, rev int, op models.NoteOperation) int
+}
+
+// NoteCollabClient is one open editing connection
+type NoteCollabClient struct {
+	NoteID int
+	UserID string
+	// Send delivers an event; it must not block
+	Send func(event models.NoteCollabEvent) error
+	// Kick closes the connection with a WebSocket close code
+	Kick func(code int, reason string)
+
+	connID string
+	rev    int // revision of the document the client started from
+}
+
+// collabRoom holds the connections of one note on this instance, which share
+// a single subscription, and the document at the latest revision seen here
+type collabRoom struct {
+	noteID int
+	owner  string // author of the note, whose row the snapshots update
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	doc     []richtext.Token
+	rev     int
+	sent    int // latest revision relayed to the clients
+	clients map[*NoteCollabClient]struct{}
+}
+
+type NoteCollabService struct {
+	noteRepo       repo.INoteRepository
+	studyGroupRepo repo.IStudyGroupRepository
+	collabStore    repo.INoteCollabStore
+	jobs           IJobQueue
+
+	mu    sync.Mutex
+	rooms map[int]*collabRoom
+}
+
+// NewNoteCollabService creates the collaborative editing service. Connections
+// are tracked in memory, so one instance must serve every editing route of
+// the process.
+func NewNoteCollabService(
+	noteRepository repo.INoteRepository,
+	studyGroupRepository repo.IStudyGroupRepository,
+	collabStore repo.INoteCollabStore,
+	jobs IJobQueue,
+) INoteCollabService {
+	return &NoteCollabService{
+		noteRepo:       noteRepository,
+		studyGroupRepo: studyGroupRepository,
+		collabStore:    collabStore,
+		jobs:           jobs,
+		rooms:          make(map[int]*collabRoom),
+	}
+}
+
+func (s *NoteCollabService) CheckAccess(ctx context.Context, userID string, noteID int) (*models.Note, int) {
+	note, err := s.noteRepo.GetNoteByID(ctx, noteID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		note, err = s.studyGroupRepo.FindSharedNote(ctx, noteID, userID)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrNoteNotFound.Error(), zap.String("userID", userID), zap.Int("noteID", noteID))
+			return nil, response.CodeNoteNotFound
+		}
+
+		global.Log.Error("Error getting note", zap.Error(err), zap.Int("noteID", noteID))
+		return nil, response.CodeServerBusy
+	}
+	return note, response.CodeSuccess
+}
+
+func (s *NoteCollabService) Connect(ctx context.Context, client *NoteCollabClient) (func(), int) {
+	note, code := s.CheckAccess(ctx, client.UserID, client.NoteID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	connID, err := utils.GenerateToken(8)
+	if err != nil {
+		global.Log.Error("Error generating collab connection id", zap.Error(err))
+		return nil, response.CodeServerBusy
+	}
+	client.connID = connID
+
+	// Counted before the session opens, so it is not replaced under this client
+	s.setOnline(ctx, client)
+	if err := s.join(ctx, client, note); err != nil {
+		global.Log.Error("Error joining collaborative session", zap.Error(err), zap.Int("noteID", client.NoteID))
+		if err := s.collabStore.SetOffline(ctx, client.NoteID, client.connID); err != nil {
+			global.Log.Error("Error clearing collab connection", zap.Error(err), zap.Int("noteID", client.NoteID))
+		}
+		return nil, response.CodeServerBusy
+	}
+
+	var once sync.Once
+	leave := func() {
+		once.Do(func() { s.leave(client) })
+	}
+
+	global.Log.Info("Success connecting to collaborative session", zap.Int("noteID", client.NoteID), zap.String("userID", client.UserID))
+	return leave, response.CodeSuccess
+}
+
+func (s *NoteCollabService) Submit(ctx context.Context, client *NoteCollabClient, rev int, op models.NoteOperation) int {
+	if err := op.Validate(); err != nil || rev < 0 {
+		global.Log.Warn(errMessage.ErrInvalidCollabOp.Error(), zap.Int("noteID", client.NoteID), zap.Int("rev", rev))
+		return response.CodeNoteCollabInvalidOp
+	}
+
+	s.mu.Lock()
+	room := s.rooms[client.NoteID]
+	s.mu.Unlock()
+	if room == nil {
+		global.Log.Warn(errMessage.ErrNoteCollabClosed.Error(), zap.Int("noteID", client.NoteID))
+		return response.CodeNoteCollabResync
+	}
+
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	for range consts.NOTE_COLLAB_SUBMIT_ATTEMPTS {
+		state, ops, err := s.sync(ctx, room)
+		if err != nil {
+			if errors.Is(err, errMessage.ErrNoteCollabClosed) {
+				global.Log.Warn(err.Error(), zap.Int("noteID", room.noteID))
+				return response.CodeNoteCollabResync
+			}
+
+			global.Log.Error("Error reading collaborative session", zap.Error(err), zap.Int("noteID", room.noteID))
+			return response.CodeServerBusy
+		}
+		if rev < state.Base {
+			global.Log.Warn(errMessage.ErrCollabRevisionGone.Error(), zap.Int("noteID", room.noteID), zap.Int("rev", rev), zap.Int("base", state.Base))
+			return response.CodeNoteCollabResync
+		}
+		if rev > room.rev {
+			global.Log.Warn(errMessage.ErrInvalidCollabOp.Error(), zap.Int("noteID", room.noteID), zap.Int("rev", rev), zap.Int("latest", room.rev))
+			return response.CodeNoteCollabInvalidOp
+		}
+
+		// Move the operation past the ones the client had not seen
+		transformed := op
+		for _, event := range ops {
+			if event.Rev <= rev {
+				continue
+			}
+			if transformed, _, err = ot.Transform(transformed, event.Ops); err != nil {
+				global.Log.Warn(errMessage.ErrInvalidCollabOp.Error(), zap.Error(err), zap.Int("noteID", room.noteID), zap.Int("rev", rev))
+				return response.CodeNoteCollabInvalidOp
+			}
+		}
+		doc, code := applyCollabOp(room.doc, transformed)
+		if code != response.CodeSuccess {
+			return code
+		}
+
+		event := models.NoteCollabEvent{
+			Type:   consts.NoteCollabEventType.OP,
+			NoteID: room.noteID,
+			Rev:    room.rev + 1,
+			ConnID: client.connID,
+			UserID: client.UserID,
+			Ops:    transformed,
+		}
+		appended, err := s.collabStore.Append(ctx, room.noteID, event)
+		if err != nil {
+			global.Log.Error("Error appending collaborative operation", zap.Error(err), zap.Int("noteID", room.noteID))
+			return response.CodeServerBusy
+		}
+		if appended {
+			room.doc, room.rev = doc, event.Rev
+			return response.CodeSuccess
+		}
+		// Another instance appended first; catch up and transform past that too
+	}
+
+	global.Log.Warn("Too many concurrent collaborative operations", zap.Int("noteID", room.noteID))
+	return response.CodeServerBusy
+}
+
+// join adds the client to its room, opening the room for the first client
+// of the note, and sends it the document
+func (s *NoteCollabService) join(ctx context.Context, client *NoteCollabClient, note *models.Note) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, ok := s.rooms[client.NoteID]
+	if !ok {
+		var err error
+		if room, err = s.openRoom(ctx, note); err != nil {
+			return err
+		}
+		s.rooms[client.NoteID] = room
+	}
+
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	doc, err := richtext.FromTokens(room.doc)
+	if err != nil {
+		return err
+	}
+	data, err := doc.JSON()
+	if err != nil {
+		return err
+	}
+	client.rev = room.rev
+	room.clients[client] = struct{}{}
+	_ = client.Send(models.NoteCollabEvent{
+		Type:   consts.NoteCollabEventType.INIT,
+		NoteID: room.noteID,
+		Rev:    room.rev,
+		Doc:    data,
+		ConnID: client.connID,
+	})
+	return nil
+}
+
+// openRoom subscribes to the note's session, opening the session from the
+// note's content when none is running. The caller holds s.mu.
+func (s *NoteCollabService) openRoom(ctx context.Context, note *models.Note) (*collabRoom, error) {
+	roomCtx, cancel := context.WithCancel(context.Background())
+	// Subscribed first, so no operation falls between the read and the feed
+	events, err := s.collabStore.Subscribe(roomCtx, note.ID)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// Opened under the note's row lock, which REST writes of the content take
+	// too: either they see this connection and refuse, or the session starts
+	// from what they wrote
+	err = s.noteRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		locked, err := s.noteRepo.WithTx(tx).LockNote(ctx, note.ID, note.UserID)
+		if err != nil {
+			return err
+		}
+		content, err := normalizeContent(locked.Content)
+		if err != nil {
+			return err
+		}
+		_, err = s.collabStore.Open(ctx, note.ID, content, time.Now())
+		return err
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	room := &collabRoom{
+		noteID:  note.ID,
+		owner:   note.UserID,
+		cancel:  cancel,
+		rev:     -1,
+		clients: make(map[*NoteCollabClient]struct{}),
+	}
+	if _, _, err := s.sync(ctx, room); err != nil {
+		cancel()
+		return nil, err
+	}
+	room.sent = room.rev
+
+	go s.runRoom(roomCtx, room, events)
+	return room, nil
+}
+
+// leave removes the client. The last client of the room saves the document
+// and closes the session if no other instance has it open.
+func (s *NoteCollabService) leave(client *NoteCollabClient) {
+	s.mu.Lock()
+	room := s.rooms[client.NoteID]
+	last := false
+	if room != nil {
+		room.mu.Lock()
+		// The client's room may have been dropped and replaced since it joined
+		_, member := room.clients[client]
+		delete(room.clients, client)
+		last = member && len(room.clients) == 0
+		room.mu.Unlock()
+		if last {
+			room.cancel()
+			delete(s.rooms, client.NoteID)
+		}
+	}
+	s.mu.Unlock()
+
+	// The request context is usually gone by now
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.collabStore.SetOffline(ctx, client.NoteID, client.connID); err != nil {
+		global.Log.Error("Error clearing collab connection", zap.Error(err), zap.Int("noteID", client.NoteID))
+	}
+	if !last {
+		return
+	}
+
+	s.snapshot(ctx, room)
+	if _, err := s.collabStore.Close(ctx, client.NoteID, time.Now()); err != nil {
+		global.Log.Error("Error closing collaborative session", zap.Error(err), zap.Int("noteID", client.NoteID))
+	}
+}
+
+// runRoom relays the note's events to its clients on this instance, keeps
+// their connections counted and saves the document periodically
+func (s *NoteCollabService) runRoom(ctx context.Context, room *collabRoom, events <-chan models.NoteCollabEvent) {
+	refresh := time.NewTicker(consts.NOTE_COLLAB_CONNECTION_REFRESH)
+	defer refresh.Stop()
+	snapshot := time.NewTicker(consts.NOTE_COLLAB_SNAPSHOT_INTERVAL)
+	defer snapshot.Stop()
+
+	for {
+		select {
+		case <-refresh.C:
+			s.refreshClients(ctx, room)
+		case <-snapshot.C:
+			s.snapshot(ctx, room)
+			// Also relays operations whose event never arrived
+			s.deliver(ctx, room, nil)
+		case event, ok := <-events:
+			if !ok {
+				if ctx.Err() == nil {
+					// Lost the relay; clients reconnect and resubscribe
+					global.Log.Error("Error receiving collaborative editing events", zap.Int("noteID", room.noteID))
+					s.dropRoom(room)
+					for _, client := range s.clients(room) {
+						client.Kick(consts.NOTE_COLLAB_CLOSE_UNAVAILABLE, "editing unavailable")
+					}
+				}
+				return
+			}
+			s.deliver(ctx, room, &event)
+		}
+	}
+}
+
+// deliver relays operations to the room's clients in revision order, each
+// exactly once. Without an event, or with one that does not follow the last
+// relayed revision, it reads what is missing from the session's log.
+func (s *NoteCollabService) deliver(ctx context.Context, room *collabRoom, event *models.NoteCollabEvent) {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	if event != nil {
+		switch {
+		case event.Type == consts.NoteCollabEventType.SNAPSHOT:
+			for client := range room.clients {
+				_ = client.Send(*event)
+			}
+			return
+		case event.Type != consts.NoteCollabEventType.OP || event.Rev <= room.sent:
+			return
+		case event.Rev == room.sent+1 && event.Rev <= room.rev+1:
+			if event.Rev == room.rev+1 {
+				doc, err := ot.Apply(room.doc, event.Ops)
+				if err != nil {
+					global.Log.Error("Error applying collaborative operation", zap.Error(err), zap.Int("noteID", room.noteID), zap.Int("rev", event.Rev))
+					s.kickAll(room, consts.NOTE_COLLAB_CLOSE_RESYNC, "document out of sync")
+					return
+				}
+				room.doc, room.rev = doc, event.Rev
+			}
+			s.relay(room, *event)
+			return
+		}
+	}
+
+	state, ops, err := s.sync(ctx, room)
+	if err != nil {
+		if errors.Is(err, errMessage.ErrNoteCollabClosed) {
+			global.Log.Warn(err.Error(), zap.Int("noteID", room.noteID))
+			s.kickAll(room, consts.NOTE_COLLAB_CLOSE_RESYNC, "editing session ended")
+		} else if ctx.Err() == nil {
+			global.Log.Error("Error reading collaborative session", zap.Error(err), zap.Int("noteID", room.noteID))
+		}
+		return
+	}
+	if room.sent < state.Base {
+		// The operations in between only survive inside the snapshot
+		for client := range room.clients {
+			if client.rev < state.Base {
+				client.Kick(consts.NOTE_COLLAB_CLOSE_RESYNC, "document moved on")
+			}
+		}
+	}
+	for _, op := range ops {
+		if op.Rev > room.sent {
+			s.relay(room, op)
+		}
+	}
+	room.sent = room.rev
+}
+
+// relay sends an operation to the clients that started before it. The
+// caller holds room.mu.
+func (s *NoteCollabService) relay(room *collabRoom, event models.NoteCollabEvent) {
+	for client := range room.clients {
+		if client.rev < event.Rev {
+			_ = client.Send(event)
+		}
+	}
+	room.sent = event.Rev
+}
+
+// sync brings the room's document up to the session's latest revision and
+// returns the session with the operations after its snapshot. The caller
+// holds room.mu.
+func (s *NoteCollabService) sync(ctx context.Context, room *collabRoom) (*models.NoteCollabState, []models.NoteCollabEvent, error) {
+	state, ops, err := s.collabStore.Ops(ctx, room.noteID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// A session that is gone or behind the room was replaced without it
+	if state == nil || room.rev > state.Rev {
+		return nil, nil, errMessage.ErrNoteCollabClosed
+	}
+
+	if room.rev < state.Base {
+		doc, err := richtext.Parse(state.Doc)
+		if err != nil {
+			return nil, nil, err
+		}
+		room.doc, room.rev = doc.Tokens(), state.Base
+	}
+	for _, event := range ops {
+		if event.Rev <= room.rev {
+			continue
+		}
+		doc, err := ot.Apply(room.doc, event.Ops)
+		if err != nil {
+			return nil, nil, err
+		}
+		room.doc, room.rev = doc, event.Rev
+	}
+	return state, ops, nil
+}
+
+// refreshClients keeps the room's connections counted as open and drops
+// users who lost access to the note, e.g. by leaving the study group
+func (s *NoteCollabService) refreshClients(ctx context.Context, room *collabRoom) {
+	for _, client := range s.clients(room) {
+		if _, code := s.CheckAccess(ctx, client.UserID, room.noteID); code == response.CodeNoteNotFound {
+			client.Kick(consts.NOTE_COLLAB_CLOSE_FORBIDDEN, "no access to the note")
+			continue
+		}
+		s.setOnline(ctx, client)
+	}
+}
+
+// snapshot saves the session's document into the note as a new version when
+// operations came after the last snapshot. Instances race for it; the note's
+// row lock and the store's check of the snapshot revision let one of them
+// save each document.
+func (s *NoteCollabService) snapshot(ctx context.Context, room *collabRoom) {
+	room.mu.Lock()
+	_, _, err := s.sync(ctx, room)
+	tokens, rev := room.doc, room.rev
+	room.mu.Unlock()
+	if err != nil {
+		if !errors.Is(err, errMessage.ErrNoteCollabClosed) && ctx.Err() == nil {
+			global.Log.Error("Error reading collaborative session", zap.Error(err), zap.Int("noteID", room.noteID))
+		}
+		return
+	}
+
+	doc, err := richtext.FromTokens(tokens)
+	if err != nil {
+		global.Log.Error("Error building collaborative document", zap.Error(err), zap.Int("noteID", room.noteID))
+		return
+	}
+	raw, err := doc.JSON()
+	if err != nil {
+		global.Log.Error("Error building collaborative document", zap.Error(err), zap.Int("noteID", room.noteID))
+		return
+	}
+	content, code := renderContent(raw)
+	if code != response.CodeSuccess {
+		return
+	}
+
+	var saved *models.Note
+	err = s.noteRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		noteRepo := s.noteRepo.WithTx(tx)
+		note, err := noteRepo.LockNote(ctx, room.noteID, room.owner)
+		if err != nil {
+			return err
+		}
+		// Read under the lock: another instance may have saved a later revision meanwhile
+		state, err := s.collabStore.State(ctx, room.noteID)
+		if err != nil || state == nil || state.Base >= rev {
+			return err
+		}
+
+		current, err := normalizeContent(note.Content)
+		if err != nil {
+			return err
+		}
+		if string(content.JSON) != string(current) {
+			note.Content, note.ContentHTML, note.ContentText = content.JSON, content.HTML, content.Text
+			note.Version++
+			err := noteRepo.UpdateNote(ctx, note.ID, note.UserID, map[string]any{
+				"content":      note.Content,
+				"content_html": note.ContentHTML,
+				"content_text": note.ContentText,
+				"version":      note.Version,
+			})
+			if err != nil {
+				return err
+			}
+			if err := noteRepo.CreateRevision(ctx, revisionOf(note, sql.NullInt64{})); err != nil {
+				return err
+			}
+			if err := noteRepo.PruneRevisions(ctx, note.ID, consts.NOTE_MAX_REVISIONS); err != nil {
+				return err
+			}
+			saved = note
+		}
+
+		ok, err := s.collabStore.Snapshot(ctx, room.noteID, state.Base, models.NoteCollabState{Base: rev, Doc: content.JSON})
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errMessage.ErrNoteCollabClosed
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errMessage.ErrNoteCollabClosed) {
+			global.Log.Warn(err.Error(), zap.Int("noteID", room.noteID))
+			return
+		}
+
+		global.Log.Error("Error saving collaborative edits", zap.Error(err), zap.Int("noteID", room.noteID))
+		return
+	}
+	if saved == nil {
+		return
+	}
+
+	global.Log.Info("Success saving collaborative edits", zap.Int("noteID", room.noteID), zap.Int("version", saved.Version), zap.Int("rev", rev))
+	event := models.NoteCollabEvent{Type: consts.NoteCollabEventType.SNAPSHOT, NoteID: room.noteID, Rev: rev, Version: saved.Version}
+	if err := s.collabStore.Publish(ctx, room.noteID, event); err != nil {
+		global.Log.Error("Error publishing collaborative snapshot", zap.Error(err), zap.Int("noteID", room.noteID))
+	}
+	payload := models.EmbeddingIndexPayload{NoteID: &room.noteID, UserID: room.owner}
+	if _, err := s.jobs.Enqueue(ctx, consts.JobType.EMBEDDING_INDEX, payload); err != nil {
+		global.Log.Error("Error enqueuing note indexing", zap.Error(err), zap.Int("noteID", room.noteID))
+	}
+}
+
+// dropRoom forgets a room whose subscription is gone, so clients that
+// reconnect open a new one instead of joining a room nothing feeds
+func (s *NoteCollabService) dropRoom(room *collabRoom) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rooms[room.noteID] == room {
+		delete(s.rooms, room.noteID)
+	}
+	room.cancel()
+}
+
+func (s *NoteCollabService) clients(room *collabRoom) []*NoteCollabClient {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	clients := make([]*NoteCollabClient, 0, len(room.clients))
+	for client := range room.clients {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// kickAll closes every connection of the room. The caller holds room.mu.
+func (s *NoteCollabService) kickAll(room *collabRoom, code int, reason string) {
+	for client := range room.clients {
+		client.Kick(code, reason)
+	}
+}
+
+// setOnline counts the connection as open for another NOTE_COLLAB_CONNECTION_TTL
+func (s *NoteCollabService) setOnline(ctx context.Context, client *NoteCollabClient) {
+	until := time.Now().Add(consts.NOTE_COLLAB_CONNECTION_TTL)
+	if err := s.collabStore.SetOnline(ctx, client.NoteID, client.connID, until); err != nil {
+		global.Log.Error("Error refreshing collab connection", zap.Error(err), zap.Int("noteID", client.NoteID))
+	}
+}
+
+// normalizeContent re-encodes stored note content the way the server writes
+// it, since the database may format JSON differently
+func normalizeContent(raw []byte) ([]byte, error) {
+	doc, err := richtext.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	return doc.JSON()
+}
+
+// applyCollabOp applies the operation and checks that the result is still a
+// valid document within the size limit
+func applyCollabOp(tokens []richtext.Token, op models.NoteOperation) ([]richtext.Token, int) {
+	result, err := ot.Apply(tokens, op)
+	if err != nil {
+		global.Log.Warn(errMessage.ErrInvalidCollabOp.Error(), zap.Error(err))
+		return nil, response.CodeNoteCollabInvalidOp
+	}
+	doc, err := richtext.FromTokens(result)
+	if err != nil {
+		global.Log.Warn(errMessage.ErrInvalidCollabOp.Error(), zap.Error(err))
+		return nil, response.CodeNoteCollabInvalidOp
+	}
+	data, err := doc.JSON()
+	if err != nil {
+		global.Log.Warn(errMessage.ErrInvalidCollabOp.Error(), zap.Error(err))
+		return nil, response.CodeNoteCollabInvalidOp
+	}
+	if len(data) > consts.NOTE_MAX_CONTENT_BYTES {
+		global.Log.Warn(errMessage.ErrNoteContentTooLarge.Error(), zap.Int("bytes", len(data)))
+		return nil, response.CodeNoteContentTooLarge
+	}
+	return result, response.CodeSuccess
+}
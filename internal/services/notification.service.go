@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type INotificationService interface {
+	GetSettings(ctx context.Context, userID string) (*models.NotificationSettingResponse, int)
+	UpdateSettings(ctx context.Context, userID string, req *models.UpdateNotificationSettingRequest) (*models.NotificationSettingResponse, int)
+	ListNotifications(ctx context.Context, userID string, limit int) ([]models.ReminderNotification, int)
+}
+
+type NotificationService struct {
+	notificationRepo repo.INotificationRepository
+}
+
+func NewNotificationService(notificationRepository repo.INotificationRepository) INotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepository,
+	}
+}
+
+// GetSettings returns the user's notification preferences, or the configured defaults
+func (s *NotificationService) GetSettings(ctx context.Context, userID string) (*models.NotificationSettingResponse, int) {
+	setting, err := s.notificationRepo.GetSetting(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &models.NotificationSettingResponse{
+				OffsetsMinutes: defaultNotificationOffsets(),
+				EmailEnabled:   true,
+			}, response.CodeSuccess
+		}
+
+		global.Log.Error("Error getting notification setting", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	return &models.NotificationSettingResponse{
+		OffsetsMinutes: setting.OffsetsMinutes(),
+		EmailEnabled:   setting.EmailEnabled == consts.Flag.TRUE,
+	}, response.CodeSuccess
+}
+
+// UpdateSettings replaces the user's notification preferences. Unsent notifications are
+// dropped so the worker rebuilds them with the new offsets on its next scan.
+func (s *NotificationService) UpdateSettings(ctx context.Context, userID string, req *models.UpdateNotificationSettingRequest) (*models.NotificationSettingResponse, int) {
+	offsets := slices.Clone(req.OffsetsMinutes)
+	slices.Sort(offsets)
+	if len(slices.Compact(offsets)) != len(req.OffsetsMinutes) {
+		global.Log.Warn(errMessage.ErrInvalidNotificationOffsets.Error(), zap.String("userID", userID), zap.Ints("offsets", req.OffsetsMinutes))
+		return nil, response.CodeNotificationInvalidOffsets
+	}
+	slices.Reverse(offsets)
+
+	emailEnabled := consts.Flag.FALSE
+	if *req.EmailEnabled {
+		emailEnabled = consts.Flag.TRUE
+	}
+
+	setting := &models.NotificationSetting{
+		UserID:       userID,
+		Offsets:      models.FormatOffsets(offsets),
+		EmailEnabled: emailEnabled,
+	}
+	if err := s.notificationRepo.UpsertSetting(ctx, setting); err != nil {
+		global.Log.Error("Error saving notification setting", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	if err := s.notificationRepo.DeleteUnsentByUser(ctx, userID); err != nil {
+		global.Log.Warn("Failed to reset pending notifications", zap.Error(err), zap.String("userID", userID))
+	}
+
+	global.Log.Info("Success updating notification setting", zap.String("userID", userID), zap.Ints("offsets", offsets))
+	return &models.NotificationSettingResponse{
+		OffsetsMinutes: offsets,
+		EmailEnabled:   *req.EmailEnabled,
+	}, response.CodeSuccess
+}
+
+// ListNotifications returns the user's most recent scheduled and sent notifications
+func (s *NotificationService) ListNotifications(ctx context.Context, userID string, limit int) ([]models.ReminderNotification, int) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	notifications, err := s.notificationRepo.ListNotifications(ctx, userID, limit)
+	if err != nil {
+		global.Log.Error("Error listing notifications", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	return notifications, response.CodeSuccess
+}
+
+// defaultNotificationOffsets returns the configured default offsets, falling back to 1 week, 3 days and 1 day
+func defaultNotificationOffsets() []int {
+	if len(global.Config.Notification.DefaultOffsets) > 0 {
+		return slices.Clone(global.Config.Notification.DefaultOffsets)
+	}
+	return slices.Clone(consts.NOTIFICATION_DEFAULT_OFFSETS)
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// IReminderNotifier drives the reminder notification engine. Each Tick enqueues
+// notifications for upcoming deadlines and sends the ones that are due.
+type IReminderNotifier interface {
+	Tick(ctx context.Context, now time.Time) error
+}
+
+type ReminderNotifier struct {
+	reminderRepo     repo.IReminderRepository
+	notificationRepo repo.INotificationRepository
+	userRepo         repo.IUserRepository
+	courseRepo       repo.ICourseRepository
+	mailRepo         repo.IMailRepository
+	mailHelper       helper.IMailHelper
+}
+
+func NewReminderNotifier(
+	reminderRepository repo.IReminderRepository,
+	notificationRepository repo.INotificationRepository,
+	userRepository repo.IUserRepository,
+	courseRepository repo.ICourseRepository,
+	mailRepository repo.IMailRepository,
+	mailHelper helper.IMailHelper,
+) IReminderNotifier {
+	return &ReminderNotifier{
+		reminderRepo:     reminderRepository,
+		notificationRepo: notificationRepository,
+		userRepo:         userRepository,
+		courseRepo:       courseRepository,
+		mailRepo:         mailRepository,
+		mailHelper:       mailHelper,
+	}
+}
+
+// Tick runs one scan. All state lives in the reminder_notifications table, so a
+// restarted worker simply picks up where the previous one stopped.
+func (n *ReminderNotifier) Tick(ctx context.Context, now time.Time) error {
+	if _, err := n.reminderRepo.MarkOverdueReminders(ctx, "", now); err != nil {
+		global.Log.Warn("Failed to mark overdue reminders", zap.Error(err))
+	}
+
+	if err := n.enqueueUpcoming(ctx, now); err != nil {
+		return fmt.Errorf("enqueue reminder notifications: %w", err)
+	}
+
+	if err := n.sendDue(ctx, now); err != nil {
+		return fmt.Errorf("send reminder notifications: %w", err)
+	}
+	return nil
+}
+
+// enqueueUpcoming creates one notification per reminder, offset and deadline. Offsets that
+// would fire before the reminder even existed are skipped, so a reminder created two days
+// ahead does not receive a "one week left" mail. An offset already sent for the same
+// deadline is never enqueued again, even after the reminder's pending ones were reset.
+func (n *ReminderNotifier) enqueueUpcoming(ctx context.Context, now time.Time) error {
+	reminders, err := n.reminderRepo.ListPendingRemindersDueBetween(ctx, now, now.Add(consts.NOTIFICATION_MAX_OFFSET))
+	if err != nil || len(reminders) == 0 {
+		return err
+	}
+
+	userIDs := make([]string, 0, len(reminders))
+	for _, reminder := range reminders {
+		userIDs = append(userIDs, reminder.UserID)
+	}
+	settings, err := n.notificationRepo.GetSettings(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	settingByUser := make(map[string]models.NotificationSetting, len(settings))
+	for _, setting := range settings {
+		settingByUser[setting.UserID] = setting
+	}
+
+	// Deadlines are the owner's wall clock
+	users, err := n.userRepo.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	locationByUser := make(map[string]*time.Location, len(users))
+	for i := range users {
+		locationByUser[users[i].UserID] = userLocation(&users[i])
+	}
+
+	var notifications []models.ReminderNotification
+	for _, reminder := range reminders {
+		offsets := defaultNotificationOffsets()
+		if setting, ok := settingByUser[reminder.UserID]; ok {
+			if setting.EmailEnabled != consts.Flag.TRUE {
+				continue
+			}
+			offsets = setting.OffsetsMinutes()
+		}
+
+		loc, ok := locationByUser[reminder.UserID]
+		if !ok {
+			continue
+		}
+		deadline := reminder.Deadline(loc)
+		for _, offset := range offsets {
+			scheduledAt := deadline.Add(-time.Duration(offset) * time.Minute)
+			if scheduledAt.Before(reminder.CreatedAt) {
+				continue
+			}
+			notifications = append(notifications, models.ReminderNotification{
+				ReminderID:    reminder.ID,
+				UserID:        reminder.UserID,
+				OffsetMinutes: offset,
+				Deadline:      deadline,
+				ScheduledAt:   scheduledAt,
+				Status:        consts.NotificationStatus.PENDING,
+			})
+		}
+	}
+
+	return n.notificationRepo.EnqueueNotifications(ctx, notifications)
+}
+
+// sendDue claims due notifications and mails them. When several offsets of the same
+// reminder are due at once (e.g. after downtime) only the closest one is sent.
+func (n *ReminderNotifier) sendDue(ctx context.Context, now time.Time) error {
+	claimTimeout := consts.NOTIFICATION_DEFAULT_CLAIM_TIMEOUT
+	if global.Config.Notification.ClaimTimeout > 0 {
+		claimTimeout = time.Duration(global.Config.Notification.ClaimTimeout) * time.Second
+	}
+	batchSize := consts.NOTIFICATION_DEFAULT_BATCH_SIZE
+	if global.Config.Notification.BatchSize > 0 {
+		batchSize = global.Config.Notification.BatchSize
+	}
+
+	claimed, err := n.notificationRepo.ClaimDueNotifications(ctx, now, now.Add(-claimTimeout), batchSize)
+	if err != nil {
+		return err
+	}
+
+	closest := make(map[int]models.ReminderNotification, len(claimed))
+	for _, notification := range claimed {
+		current, ok := closest[notification.ReminderID]
+		if !ok || notification.OffsetMinutes < current.OffsetMinutes {
+			if ok {
+				n.skip(ctx, current, "superseded by a closer notification")
+			}
+			closest[notification.ReminderID] = notification
+			continue
+		}
+		n.skip(ctx, notification, "superseded by a closer notification")
+	}
+
+	for _, notification := range closest {
+		n.send(ctx, notification, now)
+	}
+	return nil
+}
+
+func (n *ReminderNotifier) send(ctx context.Context, notification models.ReminderNotification, now time.Time) {
+	reminder, err := n.reminderRepo.GetReminderByID(ctx, notification.ReminderID, notification.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			n.skip(ctx, notification, "reminder deleted")
+			return
+		}
+		n.fail(ctx, notification, now, err)
+		return
+	}
+	if reminder.Status != consts.ReminderStatus.PENDING {
+		n.skip(ctx, notification, "reminder no longer pending")
+		return
+	}
+
+	user, err := n.userRepo.GetUserByID(ctx, notification.UserID)
+	if err != nil {
+		n.fail(ctx, notification, now, err)
+		return
+	}
+
+	// The deadline moved (new due date or timezone) since the notification was enqueued;
+	// the next scan enqueues one for the current deadline
+	deadline := reminder.Deadline(userLocation(user))
+	if !deadline.Equal(notification.Deadline) {
+		n.skip(ctx, notification, "deadline changed")
+		return
+	}
+	if !deadline.After(now) {
+		n.skip(ctx, notification, "deadline already passed")
+		return
+	}
+
+	data := models.ReminderNotificationMail{
+		Username: user.Username,
+		Title:    reminder.Title,
+		DueAt:    deadline.Format("Mon, 02 Jan 2006 15:04"),
+		TimeLeft: formatTimeLeft(deadline.Sub(now)),
+	}
+	if reminder.CourseID != nil {
+		if course, err := n.courseRepo.GetCourseByID(ctx, *reminder.CourseID, reminder.UserID); err == nil {
+			data.Course = fmt.Sprintf(" (%s - %s)", course.CourseID, course.CourseName)
+		}
+	}
+
+	subject := fmt.Sprintf("Reminder: %s is due in %s", data.Title, data.TimeLeft)
+	body := consts.REMINDER_NOTIFICATION_FALLBACK_HTML
+	if template, err := n.mailRepo.GetMailTemplate(ctx, consts.REMINDER_NOTIFICATION_MAIL); err == nil {
+		subject = n.mailHelper.ReplaceParameters(ctx, template.Subject, data)
+		body = template.Body
+	} else {
+		global.Log.Warn("Reminder mail template missing, using fallback", zap.Int("mail_id", consts.REMINDER_NOTIFICATION_MAIL), zap.Error(err))
+	}
+
+	if _, err := n.mailHelper.SendMail(ctx, user.Email, subject, n.mailHelper.ReplaceParameters(ctx, body, data)); err != nil {
+		n.fail(ctx, notification, now, err)
+		return
+	}
+
+	if err := n.notificationRepo.MarkNotificationSent(ctx, notification.ID, now); err != nil {
+		// The mail is out; a stale claim would resend it, so make the failure loud
+		global.Log.Error("Failed to mark notification as sent", zap.Error(err), zap.Int("notificationID", notification.ID))
+		return
+	}
+	global.Log.Info("Reminder notification sent", zap.Int("notificationID", notification.ID), zap.Int("reminderID", reminder.ID), zap.Int("offset_minutes", notification.OffsetMinutes))
+}
+
+func (n *ReminderNotifier) skip(ctx context.Context, notification models.ReminderNotification, reason string) {
+	if err := n.notificationRepo.MarkNotificationSkipped(ctx, notification.ID, reason); err != nil {
+		global.Log.Error("Failed to mark notification as skipped", zap.Error(err), zap.Int("notificationID", notification.ID))
+	}
+}
+
+// fail re-queues the notification with quadratic backoff until max attempts is reached
+func (n *ReminderNotifier) fail(ctx context.Context, notification models.ReminderNotification, now time.Time, cause error) {
+	maxAttempts := consts.NOTIFICATION_DEFAULT_MAX_ATTEMPTS
+	if global.Config.Notification.MaxAttempts > 0 {
+		maxAttempts = global.Config.Notification.MaxAttempts
+	}
+
+	var retryAt *time.Time
+	if notification.Attempts < maxAttempts {
+		next := now.Add(time.Duration(notification.Attempts*notification.Attempts) * time.Minute)
+		retryAt = &next
+	}
+
+	global.Log.Warn("Failed to send reminder notification", zap.Error(cause), zap.Int("notificationID", notification.ID), zap.Int("attempts", notification.Attempts), zap.Bool("will_retry", retryAt != nil))
+	if err := n.notificationRepo.MarkNotificationFailed(ctx, notification.ID, cause.Error(), retryAt); err != nil {
+		global.Log.Error("Failed to record notification failure", zap.Error(err), zap.Int("notificationID", notification.ID))
+	}
+}
+
+// formatTimeLeft renders a duration as "3 days", "1 day" or "5 hours" for mail copy
+func formatTimeLeft(d time.Duration) string {
+	days := int(d.Round(time.Hour).Hours()) / 24
+	switch {
+	case days > 1:
+		return fmt.Sprintf("%d days", days)
+	case days == 1:
+		return "1 day"
+	case d >= 2*time.Hour:
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	case d >= time.Hour:
+		return "1 hour"
+	default:
+		return fmt.Sprintf("%d minutes", int(d.Minutes()))
+	}
+}
@@ -0,0 +1,600 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/planner"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// IPlannerService schedules study blocks for upcoming exams and assignments
+// around the user's classes. A generated plan is a draft until accepted; at
+// most one draft and one accepted plan are live, older ones are superseded.
+type IPlannerService interface {
+	GetPreferences(ctx context.Context, userID string) (*models.StudyPreferenceResponse, int)
+	UpdatePreferences(ctx context.Context, userID string, req *models.UpdateStudyPreferenceRequest) (*models.StudyPreferenceResponse, int)
+	SetCourseDifficulty(ctx context.Context, userID string, courseID int, req *models.SetCourseDifficultyRequest) (*models.Course, int)
+
+	GeneratePlan(ctx context.Context, userID string, req *models.GenerateStudyPlanRequest) (*models.StudyPlanDetail, int)
+	ListPlans(ctx context.Context, userID string) ([]models.StudyPlan, int)
+	GetPlan(ctx context.Context, userID string, id int) (*models.StudyPlanDetail, int)
+	AcceptPlan(ctx context.Context, userID string, id int) (*models.StudyPlanDetail, int)
+	// RegeneratePlan replans from now over the same horizon, keeping the
+	// upcoming blocks the user adjusted by hand
+	RegeneratePlan(ctx context.Context, userID string, id int) (*models.StudyPlanDetail, int)
+
+	AdjustBlock(ctx context.Context, userID string, planID, blockID int, req *models.AdjustStudyBlockRequest) (*models.StudyBlock, int)
+	DeleteBlock(ctx context.Context, userID string, planID, blockID int) int
+}
+
+type PlannerService struct {
+	plannerRepo  repo.IPlannerRepository
+	reminderRepo repo.IReminderRepository
+	sessionRepo  repo.IClassSessionRepository
+	courseRepo   repo.ICourseRepository
+	userRepo     repo.IUserRepository
+}
+
+func NewPlannerService(plannerRepository repo.IPlannerRepository, reminderRepository repo.IReminderRepository, sessionRepository repo.IClassSessionRepository,
+	courseRepository repo.ICourseRepository, userRepository repo.IUserRepository) IPlannerService {
+	return &PlannerService{
+		plannerRepo:  plannerRepository,
+		reminderRepo: reminderRepository,
+		sessionRepo:  sessionRepository,
+		courseRepo:   courseRepository,
+		userRepo:     userRepository,
+	}
+}
+
+// studyInputs is everything a plan is generated from. Its hash is stored with
+// the plan, so a later change of any input marks the plan as outdated.
+type studyInputs struct {
+	Timezone   string
+	Preference *models.StudyPreferenceResponse
+	Deadlines  []planner.Deadline
+	Busy       []planner.Interval
+
+	loc     *time.Location
+	windows []planner.Window
+}
+
+func (in *studyInputs) options() planner.Options {
+	return planner.Options{
+		Location:        in.loc,
+		BlockMinutes:    in.Preference.BlockMinutes,
+		BreakMinutes:    in.Preference.BreakMinutes,
+		MaxDailyMinutes: in.Preference.MaxDailyMinutes,
+	}
+}
+
+func (in *studyInputs) hash() (string, error) {
+	encoded, err := json.Marshal(in)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (s *PlannerService) GetPreferences(ctx context.Context, userID string) (*models.StudyPreferenceResponse, int) {
+	preference, err := s.loadPreference(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error getting study preferences", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	return preference, response.CodeSuccess
+}
+
+func (s *PlannerService) UpdatePreferences(ctx context.Context, userID string, req *models.UpdateStudyPreferenceRequest) (*models.StudyPreferenceResponse, int) {
+	preference, err := s.loadPreference(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error getting study preferences", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	windows := make([]models.StudyWindow, 0, len(req.Windows))
+	for _, window := range req.Windows {
+		start, end, err := parseSessionTimes(window.StartTime, window.EndTime)
+		if err != nil {
+			global.Log.Warn(errMessage.ErrInvalidStudyWindow.Error(), zap.String("start", window.StartTime), zap.String("end", window.EndTime))
+			return nil, response.CodeInvalidStudyWindow
+		}
+		windows = append(windows, models.StudyWindow{Weekday: window.Weekday, StartTime: start, EndTime: end})
+	}
+	preference.Windows = windows
+
+	if req.BlockMinutes != nil {
+		preference.BlockMinutes = *req.BlockMinutes
+	}
+	if req.BreakMinutes != nil {
+		preference.BreakMinutes = *req.BreakMinutes
+	}
+	if req.MaxDailyMinutes != nil {
+		preference.MaxDailyMinutes = *req.MaxDailyMinutes
+	}
+
+	encoded, err := json.Marshal(preference.Windows)
+	if err != nil {
+		global.Log.Error("Error encoding study windows", zap.Error(err))
+		return nil, response.CodeServerBusy
+	}
+	record := &models.StudyPreference{
+		UserID:          userID,
+		Windows:         encoded,
+		BlockMinutes:    preference.BlockMinutes,
+		BreakMinutes:    preference.BreakMinutes,
+		MaxDailyMinutes: preference.MaxDailyMinutes,
+	}
+	if err := s.plannerRepo.UpsertPreference(ctx, record); err != nil {
+		global.Log.Error("Error saving study preferences", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success updating study preferences", zap.String("userID", userID))
+	return preference, response.CodeSuccess
+}
+
+func (s *PlannerService) SetCourseDifficulty(ctx context.Context, userID string, courseID int, req *models.SetCourseDifficultyRequest) (*models.Course, int) {
+	course, err := s.courseRepo.GetCourseByID(ctx, courseID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrCourseNotFound.Error(), zap.Int("courseID", courseID))
+			return nil, response.CodeCourseNotFound
+		}
+
+		global.Log.Error("Error getting course", zap.Error(err), zap.Int("courseID", courseID))
+		return nil, response.CodeServerBusy
+	}
+
+	if err := s.courseRepo.UpdateCourse(ctx, courseID, userID, map[string]any{"difficulty": req.Difficulty}); err != nil {
+		global.Log.Error("Error updating course difficulty", zap.Error(err), zap.Int("courseID", courseID))
+		return nil, response.CodeServerBusy
+	}
+
+	course.Difficulty = req.Difficulty
+	return course, response.CodeSuccess
+}
+
+func (s *PlannerService) GeneratePlan(ctx context.Context, userID string, req *models.GenerateStudyPlanRequest) (*models.StudyPlanDetail, int) {
+	horizon := consts.STUDY_PLAN_DEFAULT_HORIZON_DAYS
+	if req.HorizonDays > 0 {
+		horizon = min(req.HorizonDays, consts.STUDY_PLAN_MAX_HORIZON_DAYS)
+	}
+
+	from := time.Now().UTC().Truncate(time.Minute)
+	return s.createPlan(ctx, userID, from, from.AddDate(0, 0, horizon), nil)
+}
+
+func (s *PlannerService) ListPlans(ctx context.Context, userID string) ([]models.StudyPlan, int) {
+	plans, err := s.plannerRepo.ListPlans(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error listing study plans", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	return plans, response.CodeSuccess
+}
+
+func (s *PlannerService) GetPlan(ctx context.Context, userID string, id int) (*models.StudyPlanDetail, int) {
+	plan, code := s.getPlan(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	detail := &models.StudyPlanDetail{StudyPlan: *plan}
+	if plan.Status == consts.StudyPlanStatus.SUPERSEDED {
+		return detail, response.CodeSuccess
+	}
+
+	inputs, err := s.loadInputs(ctx, userID, plan.StartsAt, plan.EndsAt)
+	if err != nil {
+		global.Log.Error("Error loading study plan inputs", zap.Error(err), zap.Int("planID", id))
+		return nil, response.CodeServerBusy
+	}
+	hash, err := inputs.hash()
+	if err != nil {
+		global.Log.Error("Error hashing study plan inputs", zap.Error(err), zap.Int("planID", id))
+		return nil, response.CodeServerBusy
+	}
+	detail.Outdated = hash != plan.InputHash
+	return detail, response.CodeSuccess
+}
+
+func (s *PlannerService) AcceptPlan(ctx context.Context, userID string, id int) (*models.StudyPlanDetail, int) {
+	plan, code := s.getPlan(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	switch plan.Status {
+	case consts.StudyPlanStatus.SUPERSEDED:
+		global.Log.Warn(errMessage.ErrStudyPlanSuperseded.Error(), zap.Int("planID", id))
+		return nil, response.CodeStudyPlanSuperseded
+	case consts.StudyPlanStatus.ACCEPTED:
+		return s.GetPlan(ctx, userID, id)
+	}
+
+	err := s.plannerRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		plannerRepo := s.plannerRepo.WithTx(tx)
+
+		// The accepted plan replaces the previous one and any other draft
+		statuses := []int8{consts.StudyPlanStatus.DRAFT, consts.StudyPlanStatus.ACCEPTED}
+		if err := plannerRepo.SupersedePlans(ctx, userID, statuses, id); err != nil {
+			return err
+		}
+		return plannerRepo.UpdatePlan(ctx, id, userID, map[string]any{
+			"status":      consts.StudyPlanStatus.ACCEPTED,
+			"accepted_at": time.Now().UTC(),
+		})
+	})
+	if err != nil {
+		global.Log.Error("Error accepting study plan", zap.Error(err), zap.Int("planID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success accepting study plan", zap.Int("planID", id), zap.String("userID", userID))
+	return s.GetPlan(ctx, userID, id)
+}
+
+func (s *PlannerService) RegeneratePlan(ctx context.Context, userID string, id int) (*models.StudyPlanDetail, int) {
+	plan, code := s.livePlan(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	from := time.Now().UTC().Truncate(time.Minute)
+	var pinned []models.StudyBlock
+	for _, block := range plan.Blocks {
+		if block.Adjusted && block.StartAt.After(from) {
+			pinned = append(pinned, block)
+		}
+	}
+	return s.createPlan(ctx, userID, from, from.Add(plan.EndsAt.Sub(plan.StartsAt)), pinned)
+}
+
+func (s *PlannerService) AdjustBlock(ctx context.Context, userID string, planID, blockID int, req *models.AdjustStudyBlockRequest) (*models.StudyBlock, int) {
+	plan, code := s.livePlan(ctx, userID, planID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	var block *models.StudyBlock
+	for i := range plan.Blocks {
+		if plan.Blocks[i].ID == blockID {
+			block = &plan.Blocks[i]
+		}
+	}
+	if block == nil {
+		global.Log.Warn(errMessage.ErrStudyBlockNotFound.Error(), zap.Int("blockID", blockID))
+		return nil, response.CodeStudyBlockNotFound
+	}
+
+	start, end := req.StartAt.UTC(), req.EndAt.UTC()
+	length := end.Sub(start)
+	if length < time.Duration(consts.STUDY_MIN_BLOCK_MINUTES)*time.Minute || length > time.Duration(consts.STUDY_MAX_BLOCK_MINUTES)*time.Minute {
+		global.Log.Warn(errMessage.ErrInvalidStudyBlockTime.Error(), zap.Time("start", start), zap.Time("end", end))
+		return nil, response.CodeInvalidStudyBlockTime
+	}
+
+	interval := planner.Interval{Start: start, End: end}
+	for _, other := range plan.Blocks {
+		if other.ID != blockID && planner.Overlaps(interval, planner.Interval{Start: other.StartAt, End: other.EndAt}) {
+			global.Log.Warn(errMessage.ErrStudyBlockConflict.Error(), zap.Int("blockID", blockID), zap.Int("otherBlockID", other.ID))
+			return nil, response.CodeStudyBlockConflict
+		}
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error getting user", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	classes, err := s.loadClasses(ctx, userID, userLocation(user), start, end)
+	if err != nil {
+		global.Log.Error("Error loading classes", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	for _, class := range classes {
+		if planner.Overlaps(interval, class) {
+			global.Log.Warn(errMessage.ErrStudyBlockConflict.Error(), zap.Int("blockID", blockID), zap.Time("classStart", class.Start))
+			return nil, response.CodeStudyBlockConflict
+		}
+	}
+
+	updates := map[string]any{"start_at": start, "end_at": end, "adjusted": true}
+	if err := s.plannerRepo.UpdateBlock(ctx, blockID, userID, updates); err != nil {
+		global.Log.Error("Error updating study block", zap.Error(err), zap.Int("blockID", blockID))
+		return nil, response.CodeServerBusy
+	}
+
+	block.StartAt, block.EndAt, block.Adjusted = start, end, true
+	return block, response.CodeSuccess
+}
+
+func (s *PlannerService) DeleteBlock(ctx context.Context, userID string, planID, blockID int) int {
+	if _, code := s.livePlan(ctx, userID, planID); code != response.CodeSuccess {
+		return code
+	}
+
+	if err := s.plannerRepo.DeleteBlock(ctx, blockID, planID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrStudyBlockNotFound.Error(), zap.Int("blockID", blockID))
+			return response.CodeStudyBlockNotFound
+		}
+
+		global.Log.Error("Error deleting study block", zap.Error(err), zap.Int("blockID", blockID))
+		return response.CodeServerBusy
+	}
+	return response.CodeSuccess
+}
+
+// createPlan schedules the deadlines due between from and to into a new
+// draft, replacing the previous draft. Pinned blocks are copied over as they
+// are and count toward the study their deadline needs.
+func (s *PlannerService) createPlan(ctx context.Context, userID string, from, to time.Time, pinned []models.StudyBlock) (*models.StudyPlanDetail, int) {
+	inputs, err := s.loadInputs(ctx, userID, from, to)
+	if err != nil {
+		global.Log.Error("Error loading study plan inputs", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	if len(inputs.Deadlines) == 0 {
+		global.Log.Warn(errMessage.ErrStudyPlanNothingToPlan.Error(), zap.String("userID", userID))
+		return nil, response.CodeStudyPlanNothingToPlan
+	}
+	hash, err := inputs.hash()
+	if err != nil {
+		global.Log.Error("Error hashing study plan inputs", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	deadlines := append([]planner.Deadline(nil), inputs.Deadlines...)
+	busy := append([]planner.Interval(nil), inputs.Busy...)
+	plan := &models.StudyPlan{UserID: userID, Status: consts.StudyPlanStatus.DRAFT, StartsAt: from, EndsAt: to, InputHash: hash}
+
+	// Hand-placed blocks stay only while their exam or assignment is still ahead
+	for _, block := range pinned {
+		index := -1
+		for i := range deadlines {
+			if block.ReminderID != nil && deadlines[i].ID == *block.ReminderID {
+				index = i
+			}
+		}
+		if index < 0 {
+			continue
+		}
+		deadlines[index].Planned += int(block.EndAt.Sub(block.StartAt).Minutes())
+		busy = append(busy, planner.Interval{Start: block.StartAt, End: block.EndAt})
+		plan.Blocks = append(plan.Blocks, models.StudyBlock{
+			UserID:     userID,
+			CourseID:   block.CourseID,
+			ReminderID: block.ReminderID,
+			StartAt:    block.StartAt,
+			EndAt:      block.EndAt,
+			Adjusted:   true,
+		})
+	}
+
+	schedule := planner.Plan(from, deadlines, inputs.windows, busy, inputs.options())
+	for _, block := range schedule.Blocks {
+		reminderID := block.DeadlineID
+		plan.Blocks = append(plan.Blocks, models.StudyBlock{
+			UserID:     userID,
+			CourseID:   block.CourseID,
+			ReminderID: &reminderID,
+			StartAt:    block.Start.UTC(),
+			EndAt:      block.End.UTC(),
+		})
+	}
+	sort.Slice(plan.Blocks, func(i, j int) bool { return plan.Blocks[i].StartAt.Before(plan.Blocks[j].StartAt) })
+
+	shortfalls := make([]models.StudyPlanShortfall, 0, len(schedule.Shortfalls))
+	for _, shortfall := range schedule.Shortfalls {
+		shortfalls = append(shortfalls, models.StudyPlanShortfall{ReminderID: shortfall.DeadlineID, Minutes: shortfall.Minutes})
+	}
+	if plan.Shortfalls, err = json.Marshal(shortfalls); err != nil {
+		global.Log.Error("Error encoding study plan shortfalls", zap.Error(err))
+		return nil, response.CodeServerBusy
+	}
+
+	err = s.plannerRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		plannerRepo := s.plannerRepo.WithTx(tx)
+		if err := plannerRepo.SupersedePlans(ctx, userID, []int8{consts.StudyPlanStatus.DRAFT}, 0); err != nil {
+			return err
+		}
+		return plannerRepo.CreatePlan(ctx, plan)
+	})
+	if err != nil {
+		global.Log.Error("Error creating study plan", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success generating study plan", zap.Int("planID", plan.ID), zap.Int("blocks", len(plan.Blocks)), zap.String("userID", userID))
+	return &models.StudyPlanDetail{StudyPlan: *plan}, response.CodeSuccess
+}
+
+func (s *PlannerService) getPlan(ctx context.Context, userID string, id int) (*models.StudyPlan, int) {
+	plan, err := s.plannerRepo.GetPlanByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrStudyPlanNotFound.Error(), zap.Int("planID", id))
+			return nil, response.CodeStudyPlanNotFound
+		}
+
+		global.Log.Error("Error getting study plan", zap.Error(err), zap.Int("planID", id))
+		return nil, response.CodeServerBusy
+	}
+	return plan, response.CodeSuccess
+}
+
+// livePlan returns a draft or accepted plan; superseded plans are read-only
+func (s *PlannerService) livePlan(ctx context.Context, userID string, id int) (*models.StudyPlan, int) {
+	plan, code := s.getPlan(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	if plan.Status == consts.StudyPlanStatus.SUPERSEDED {
+		global.Log.Warn(errMessage.ErrStudyPlanSuperseded.Error(), zap.Int("planID", id))
+		return nil, response.CodeStudyPlanSuperseded
+	}
+	return plan, response.CodeSuccess
+}
+
+// loadPreference returns the saved preferences or the defaults
+func (s *PlannerService) loadPreference(ctx context.Context, userID string) (*models.StudyPreferenceResponse, error) {
+	record, err := s.plannerRepo.GetPreference(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return defaultStudyPreference(), nil
+		}
+		return nil, err
+	}
+
+	preference := &models.StudyPreferenceResponse{
+		BlockMinutes:    record.BlockMinutes,
+		BreakMinutes:    record.BreakMinutes,
+		MaxDailyMinutes: record.MaxDailyMinutes,
+	}
+	if err := json.Unmarshal(record.Windows, &preference.Windows); err != nil {
+		return nil, err
+	}
+	return preference, nil
+}
+
+// loadInputs collects the exams and assignments due in (from, to] with their
+// courses, and the classes in between in the user's timezone. Reminders not
+// linked to a course have nothing to study for and are left out.
+func (s *PlannerService) loadInputs(ctx context.Context, userID string, from, to time.Time) (*studyInputs, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	preference, err := s.loadPreference(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	inputs := &studyInputs{
+		Timezone:   user.Timezone,
+		Preference: preference,
+		loc:        userLocation(user),
+		windows:    plannerWindows(preference.Windows),
+	}
+
+	courses, err := s.courseRepo.ListCourses(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*models.Course, len(courses))
+	for i := range courses {
+		byID[courses[i].ID] = &courses[i]
+	}
+
+	// Due dates are stored on the user's calendar
+	fromDay, toDay := from.In(inputs.loc), to.In(inputs.loc)
+	reminders, err := s.reminderRepo.ListReminders(ctx, models.ReminderFilter{UserID: userID, From: &fromDay, To: &toDay})
+	if err != nil {
+		return nil, err
+	}
+	for i := range reminders {
+		reminder := &reminders[i]
+		if reminder.Type == consts.ReminderType.COURSE || reminder.Status == consts.ReminderStatus.COMPLETED || reminder.CourseID == nil {
+			continue
+		}
+		course, ok := byID[*reminder.CourseID]
+		due := reminder.Deadline(inputs.loc)
+		if !ok || !due.After(from) || due.After(to) {
+			continue
+		}
+
+		deadline := planner.Deadline{
+			ID:       reminder.ID,
+			CourseID: course.ID,
+			Due:      due,
+			Exam:     reminder.Type == consts.ReminderType.EXAM,
+			Credits:  course.Credits,
+			Grade:    float64(course.GPA),
+		}
+		if course.Difficulty != nil {
+			deadline.Difficulty = int(*course.Difficulty)
+		}
+		if reminder.Weight.Valid {
+			deadline.Weight = reminder.Weight.Float64
+		}
+		inputs.Deadlines = append(inputs.Deadlines, deadline)
+	}
+
+	if inputs.Busy, err = s.loadClasses(ctx, userID, inputs.loc, from, to); err != nil {
+		return nil, err
+	}
+	return inputs, nil
+}
+
+// loadClasses returns the user's class occurrences overlapping [from, to]
+func (s *PlannerService) loadClasses(ctx context.Context, userID string, loc *time.Location, from, to time.Time) ([]planner.Interval, error) {
+	sessions, err := s.sessionRepo.ListSessions(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	span := planner.Interval{Start: from, End: to}
+	var classes []planner.Interval
+	for i := range sessions {
+		session := &sessions[i]
+		for _, date := range session.Occurrences(from.In(loc), to.In(loc)) {
+			class := planner.Interval{Start: atClock(date, session.StartTime, loc), End: atClock(date, session.EndTime, loc)}
+			if planner.Overlaps(class, span) {
+				classes = append(classes, class)
+			}
+		}
+	}
+	sort.Slice(classes, func(i, j int) bool { return classes[i].Start.Before(classes[j].Start) })
+	return classes, nil
+}
+
+func defaultStudyPreference() *models.StudyPreferenceResponse {
+	preference := &models.StudyPreferenceResponse{
+		BlockMinutes:    consts.STUDY_DEFAULT_BLOCK_MINUTES,
+		BreakMinutes:    consts.STUDY_DEFAULT_BREAK_MINUTES,
+		MaxDailyMinutes: consts.STUDY_DEFAULT_MAX_DAILY_MINUTES,
+	}
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		window := consts.STUDY_DEFAULT_WEEKDAY_WINDOW
+		if weekday == time.Saturday || weekday == time.Sunday {
+			window = consts.STUDY_DEFAULT_WEEKEND_WINDOW
+		}
+		preference.Windows = append(preference.Windows, models.StudyWindow{Weekday: int8(weekday), StartTime: window[0], EndTime: window[1]})
+	}
+	return preference
+}
+
+// plannerWindows converts stored HH:MM:SS windows to minutes since midnight
+func plannerWindows(windows []models.StudyWindow) []planner.Window {
+	minutes := func(clock string) int {
+		parsed, err := time.Parse(time.TimeOnly, clock)
+		if err != nil {
+			return 0
+		}
+		return parsed.Hour()*60 + parsed.Minute()
+	}
+
+	converted := make([]planner.Window, 0, len(windows))
+	for _, window := range windows {
+		converted = append(converted, planner.Window{
+			Weekday: time.Weekday(window.Weekday),
+			Start:   minutes(window.StartTime),
+			End:     minutes(window.EndTime),
+		})
+	}
+	return converted
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+)
+
+type IProgressService interface {
+	// Follow calls onEvent with the progress events of a job after
+	// lastEventID until the final one, the client disconnects or
+	// consts.JOB_PROGRESS_MAX_STREAM passes. current is the state of the row
+	// the job works on; when it is final it is the only event sent.
+	Follow(ctx context.Context, kind string, id int, current models.JobProgress, lastEventID string, onEvent func(event models.JobProgress) error) int
+}
+
+type ProgressService struct {
+	progressStore repo.IProgressStore
+}
+
+func NewProgressService(progressStore repo.IProgressStore) IProgressService {
+	return &ProgressService{
+		progressStore: progressStore,
+	}
+}
+
+// streamIDPattern matches Redis stream entry IDs; anything else a client
+// sends as Last-Event-ID replays the job from the start
+var streamIDPattern = regexp.MustCompile(`^\d+-\d+$`)
+
+func (s *ProgressService) Follow(ctx context.Context, kind string, id int, current models.JobProgress, lastEventID string, onEvent func(event models.JobProgress) error) int {
+	if current.Final {
+		_ = onEvent(current)
+		return response.CodeSuccess
+	}
+	if !streamIDPattern.MatchString(lastEventID) {
+		lastEventID = ""
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, consts.JOB_PROGRESS_MAX_STREAM)
+	defer cancel()
+	topic := progressTopic(kind, id)
+	for {
+		events, err := s.progressStore.Read(ctx, topic, lastEventID, consts.JOB_PROGRESS_BLOCK)
+		if ctx.Err() != nil {
+			// The client left, or reconnects with Last-Event-ID after the time limit
+			return response.CodeSuccess
+		}
+		if err != nil {
+			global.Log.Error("Error reading job progress", zap.Error(err), zap.String("topic", topic))
+			return response.CodeServerBusy
+		}
+
+		for _, event := range events {
+			if err := onEvent(event); err != nil {
+				return response.CodeSuccess
+			}
+			lastEventID = event.ID
+			if event.Final {
+				return response.CodeSuccess
+			}
+		}
+	}
+}
+
+func progressTopic(kind string, id int) string {
+	return fmt.Sprintf("%s:%d", kind, id)
+}
+
+// startProgress clears the events of earlier runs of a job and records that
+// it is processing. Progress is best effort, so failures are only logged.
+func startProgress(ctx context.Context, store repo.IProgressStore, kind string, id int, event models.JobProgress) {
+	if store == nil {
+		return
+	}
+	if err := store.Clear(ctx, progressTopic(kind, id)); err != nil {
+		global.Log.Warn("Failed to clear job progress", zap.Error(err), zap.String("topic", progressTopic(kind, id)))
+	}
+	publishProgress(ctx, store, kind, id, event)
+}
+
+// publishProgress records a progress event of a job; failures are only logged
+func publishProgress(ctx context.Context, store repo.IProgressStore, kind string, id int, event models.JobProgress) {
+	if store == nil {
+		return
+	}
+	if err := store.Publish(ctx, progressTopic(kind, id), event); err != nil {
+		global.Log.Warn("Failed to publish job progress", zap.Error(err), zap.String("topic", progressTopic(kind, id)))
+	}
+}
@@ -0,0 +1,551 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type IQuizService interface {
+	CreateQuiz(ctx context.Context, userID string, req *models.CreateQuizRequest) (*models.Quiz, int)
+	ListQuizzes(ctx context.Context, userID string, query *models.QuizQuery) ([]models.Quiz, int)
+	GetQuiz(ctx context.Context, userID string, id int) (*models.QuizDetail, int)
+	DeleteQuiz(ctx context.Context, userID string, id int) int
+	GetStats(ctx context.Context, userID string, id int) (*models.QuizStats, int)
+
+	StartAttempt(ctx context.Context, userID string, quizID int) (*models.QuizAttempt, int)
+	ListAttempts(ctx context.Context, userID string, quizID int) ([]models.QuizAttempt, int)
+	GetAttempt(ctx context.Context, userID string, quizID, attemptID int) (*models.QuizAttemptResult, int)
+	SubmitAttempt(ctx context.Context, userID string, quizID, attemptID int, req *models.SubmitQuizAttemptRequest) (*models.QuizAttemptResult, int)
+}
+
+type QuizService struct {
+	quizRepo     repo.IQuizRepository
+	noteRepo     repo.INoteRepository
+	courseRepo   repo.ICourseRepository
+	quotaService IQuotaService
+	jobs         IJobQueue
+}
+
+func NewQuizService(quizRepository repo.IQuizRepository, noteRepository repo.INoteRepository, courseRepository repo.ICourseRepository,
+	quotaService IQuotaService, jobs IJobQueue) IQuizService {
+	return &QuizService{
+		quizRepo:     quizRepository,
+		noteRepo:     noteRepository,
+		courseRepo:   courseRepository,
+		quotaService: quotaService,
+		jobs:         jobs,
+	}
+}
+
+// CreateQuiz validates the options and queues question generation from the
+// course's notes. The quiz can be attempted once its status is done.
+func (s *QuizService) CreateQuiz(ctx context.Context, userID string, req *models.CreateQuizRequest) (*models.Quiz, int) {
+	difficulty := strings.ToLower(strings.TrimSpace(req.Difficulty))
+	if difficulty == "" {
+		difficulty = consts.QuizDifficulty.MEDIUM
+	}
+	if difficulty != consts.QuizDifficulty.EASY && difficulty != consts.QuizDifficulty.MEDIUM && difficulty != consts.QuizDifficulty.HARD {
+		global.Log.Warn(errMessage.ErrInvalidQuizDifficulty.Error(), zap.String("difficulty", req.Difficulty))
+		return nil, response.CodeQuizInvalidDifficulty
+	}
+
+	types, ok := quizTypes(req.Types)
+	if !ok {
+		global.Log.Warn(errMessage.ErrInvalidQuizType.Error(), zap.Strings("types", req.Types))
+		return nil, response.CodeQuizInvalidType
+	}
+
+	language := strings.ToLower(strings.TrimSpace(req.Language))
+	if language == "" {
+		language = consts.AI_DEFAULT_LANGUAGE
+	}
+	if _, ok := consts.AI_LANGUAGES[language]; !ok {
+		global.Log.Warn(errMessage.ErrInvalidQuizLanguage.Error(), zap.String("language", req.Language))
+		return nil, response.CodeQuizInvalidLanguage
+	}
+
+	course, err := s.courseRepo.GetCourseByID(ctx, req.CourseID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrCourseNotFound.Error(), zap.String("userID", userID), zap.Int("courseID", req.CourseID))
+			return nil, response.CodeCourseNotFound
+		}
+		global.Log.Error("Error getting course", zap.Error(err), zap.Int("courseID", req.CourseID))
+		return nil, response.CodeServerBusy
+	}
+
+	notes, err := s.noteRepo.ListNotes(ctx, models.NoteFilter{UserID: userID, CourseID: &course.ID})
+	if err != nil {
+		global.Log.Error("Error listing notes", zap.Error(err), zap.Int("courseID", course.ID))
+		return nil, response.CodeServerBusy
+	}
+	if !slices.ContainsFunc(notes, func(note models.Note) bool { return strings.TrimSpace(note.ContentText) != "" }) {
+		global.Log.Warn(errMessage.ErrQuizNoNotes.Error(), zap.Int("courseID", course.ID))
+		return nil, response.CodeQuizNoNotes
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = course.CourseName + " quiz"
+	}
+	count := req.Count
+	if count == 0 {
+		count = consts.QUIZ_DEFAULT_COUNT
+	}
+	reservation, code := s.quotaService.ReserveAIOperation(ctx, userID, consts.UsageOperation.QUIZ_GENERATE)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	quiz := &models.Quiz{
+		UserID:        userID,
+		CourseID:      course.ID,
+		Title:         title,
+		Difficulty:    difficulty,
+		Types:         strings.Join(types, ","),
+		Language:      language,
+		Count:         count,
+		Status:        consts.QuizStatus.PENDING,
+		PromptVersion: consts.QUIZ_PROMPT_VERSION,
+	}
+	if err := s.quizRepo.CreateQuiz(ctx, quiz); err != nil {
+		s.quotaService.ReleaseAIOperation(ctx, reservation)
+		global.Log.Error("Error creating quiz", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	payload := models.QuizGeneratePayload{QuizID: quiz.ID, UserID: userID, ReservationID: reservation}
+	if _, err := s.jobs.Enqueue(ctx, consts.JobType.QUIZ_GENERATE, payload); err != nil {
+		s.quotaService.ReleaseAIOperation(ctx, reservation)
+		global.Log.Error("Error enqueuing quiz generation", zap.Error(err), zap.Int("quizID", quiz.ID))
+		failed := map[string]any{
+			"status": consts.QuizStatus.FAILED,
+			"error":  "could not be queued",
+		}
+		if err := s.quizRepo.UpdateQuiz(ctx, quiz.ID, failed); err != nil {
+			global.Log.Error("Error updating quiz status", zap.Error(err), zap.Int("quizID", quiz.ID))
+		}
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Quiz generation requested", zap.Int("quizID", quiz.ID), zap.Int("count", count))
+	return quiz, response.CodeSuccess
+}
+
+// quizTypes validates and deduplicates the requested question types, keeping
+// them in canonical order; none means all of them
+func quizTypes(requested []string) ([]string, bool) {
+	all := []string{consts.QuizQuestionType.MCQ, consts.QuizQuestionType.TRUE_FALSE, consts.QuizQuestionType.SHORT_ANSWER}
+	if len(requested) == 0 {
+		return all, true
+	}
+
+	wanted := map[string]bool{}
+	for _, t := range requested {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !slices.Contains(all, t) {
+			return nil, false
+		}
+		wanted[t] = true
+	}
+	var types []string
+	for _, t := range all {
+		if wanted[t] {
+			types = append(types, t)
+		}
+	}
+	return types, true
+}
+
+func (s *QuizService) ListQuizzes(ctx context.Context, userID string, query *models.QuizQuery) ([]models.Quiz, int) {
+	quizzes, err := s.quizRepo.ListQuizzes(ctx, userID, query.CourseID)
+	if err != nil {
+		global.Log.Error("Error listing quizzes", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	return quizzes, response.CodeSuccess
+}
+
+// GetQuiz returns the quiz with its questions; answer keys are only revealed on graded attempts
+func (s *QuizService) GetQuiz(ctx context.Context, userID string, id int) (*models.QuizDetail, int) {
+	quiz, code := s.getQuiz(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	questions, err := s.quizRepo.ListQuestions(ctx, quiz.ID)
+	if err != nil {
+		global.Log.Error("Error listing quiz questions", zap.Error(err), zap.Int("quizID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	detail := &models.QuizDetail{Quiz: *quiz, Questions: make([]models.QuizQuestionView, len(questions))}
+	for i := range questions {
+		detail.Questions[i] = questionView(&questions[i])
+	}
+	return detail, response.CodeSuccess
+}
+
+func (s *QuizService) DeleteQuiz(ctx context.Context, userID string, id int) int {
+	if err := s.quizRepo.DeleteQuiz(ctx, id, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrQuizNotFound.Error(), zap.String("userID", userID), zap.Int("quizID", id))
+			return response.CodeQuizNotFound
+		}
+
+		global.Log.Error("Error deleting quiz", zap.Error(err), zap.Int("quizID", id))
+		return response.CodeServerBusy
+	}
+
+	global.Log.Info("Success deleting quiz", zap.String("userID", userID), zap.Int("quizID", id))
+	return response.CodeSuccess
+}
+
+// GetStats aggregates the answers of graded attempts per question
+func (s *QuizService) GetStats(ctx context.Context, userID string, id int) (*models.QuizStats, int) {
+	quiz, code := s.getQuiz(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	questions, err := s.quizRepo.ListQuestions(ctx, quiz.ID)
+	if err != nil {
+		global.Log.Error("Error listing quiz questions", zap.Error(err), zap.Int("quizID", id))
+		return nil, response.CodeServerBusy
+	}
+	attempts, averageScore, err := s.quizRepo.CountGradedAttempts(ctx, quiz.ID)
+	if err != nil {
+		global.Log.Error("Error counting quiz attempts", zap.Error(err), zap.Int("quizID", id))
+		return nil, response.CodeServerBusy
+	}
+	aggregates, err := s.quizRepo.AggregateQuestions(ctx, quiz.ID)
+	if err != nil {
+		global.Log.Error("Error aggregating quiz answers", zap.Error(err), zap.Int("quizID", id))
+		return nil, response.CodeServerBusy
+	}
+	responses, err := s.quizRepo.CountResponses(ctx, quiz.ID)
+	if err != nil {
+		global.Log.Error("Error counting quiz responses", zap.Error(err), zap.Int("quizID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	byQuestion := make(map[int]models.QuizQuestionAggregate, len(aggregates))
+	for _, aggregate := range aggregates {
+		byQuestion[aggregate.QuestionID] = aggregate
+	}
+	responseCounts := map[int]map[string]int64{}
+	for _, row := range responses {
+		if responseCounts[row.QuestionID] == nil {
+			responseCounts[row.QuestionID] = map[string]int64{}
+		}
+		responseCounts[row.QuestionID][row.Response] = row.Count
+	}
+
+	stats := &models.QuizStats{
+		QuizID:       quiz.ID,
+		Attempts:     attempts,
+		AverageScore: averageScore,
+		Questions:    make([]models.QuizQuestionStat, len(questions)),
+	}
+	for i, question := range questions {
+		aggregate := byQuestion[question.ID]
+		stat := models.QuizQuestionStat{
+			QuestionID:   question.ID,
+			Position:     question.Position,
+			Type:         question.Type,
+			Prompt:       question.Prompt,
+			Answered:     aggregate.Answered,
+			Correct:      aggregate.Correct,
+			AverageScore: aggregate.AverageScore,
+			Responses:    responseCounts[question.ID],
+		}
+		if stat.Answered > 0 {
+			stat.CorrectRate = float64(stat.Correct) / float64(stat.Answered)
+		}
+		stats.Questions[i] = stat
+	}
+	return stats, response.CodeSuccess
+}
+
+// StartAttempt opens a new attempt at a quiz whose questions are ready
+func (s *QuizService) StartAttempt(ctx context.Context, userID string, quizID int) (*models.QuizAttempt, int) {
+	quiz, code := s.getQuiz(ctx, userID, quizID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	if quiz.Status != consts.QuizStatus.DONE {
+		global.Log.Warn(errMessage.ErrQuizNotReady.Error(), zap.Int("quizID", quiz.ID), zap.Int8("status", quiz.Status))
+		return nil, response.CodeQuizNotReady
+	}
+	questions, err := s.quizRepo.ListQuestions(ctx, quiz.ID)
+	if err != nil {
+		global.Log.Error("Error listing quiz questions", zap.Error(err), zap.Int("quizID", quiz.ID))
+		return nil, response.CodeServerBusy
+	}
+
+	attempt := &models.QuizAttempt{
+		QuizID:   quiz.ID,
+		UserID:   userID,
+		Status:   consts.QuizAttemptStatus.IN_PROGRESS,
+		MaxScore: len(questions),
+	}
+	if err := s.quizRepo.CreateAttempt(ctx, attempt); err != nil {
+		global.Log.Error("Error creating quiz attempt", zap.Error(err), zap.Int("quizID", quiz.ID))
+		return nil, response.CodeServerBusy
+	}
+	return attempt, response.CodeSuccess
+}
+
+func (s *QuizService) ListAttempts(ctx context.Context, userID string, quizID int) ([]models.QuizAttempt, int) {
+	if _, code := s.getQuiz(ctx, userID, quizID); code != response.CodeSuccess {
+		return nil, code
+	}
+	attempts, err := s.quizRepo.ListAttempts(ctx, quizID, userID)
+	if err != nil {
+		global.Log.Error("Error listing quiz attempts", zap.Error(err), zap.Int("quizID", quizID))
+		return nil, response.CodeServerBusy
+	}
+	return attempts, response.CodeSuccess
+}
+
+func (s *QuizService) GetAttempt(ctx context.Context, userID string, quizID, attemptID int) (*models.QuizAttemptResult, int) {
+	attempt, code := s.getAttempt(ctx, userID, quizID, attemptID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	return s.attemptResult(ctx, attempt)
+}
+
+// SubmitAttempt stores and grades the answers. Multiple choice and true/false
+// answers are graded right away; non-empty short answers are queued for AI
+// grading and the attempt stays in grading until they are scored.
+func (s *QuizService) SubmitAttempt(ctx context.Context, userID string, quizID, attemptID int, req *models.SubmitQuizAttemptRequest) (*models.QuizAttemptResult, int) {
+	attempt, code := s.getAttempt(ctx, userID, quizID, attemptID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	if attempt.Status != consts.QuizAttemptStatus.IN_PROGRESS {
+		global.Log.Warn(errMessage.ErrQuizAttemptSubmitted.Error(), zap.Int("attemptID", attempt.ID))
+		return nil, response.CodeQuizAttemptSubmitted
+	}
+
+	questions, err := s.quizRepo.ListQuestions(ctx, quizID)
+	if err != nil {
+		global.Log.Error("Error listing quiz questions", zap.Error(err), zap.Int("quizID", quizID))
+		return nil, response.CodeServerBusy
+	}
+
+	responses := make(map[int]string, len(req.Answers))
+	for _, input := range req.Answers {
+		_, duplicate := responses[input.QuestionID]
+		if duplicate || !slices.ContainsFunc(questions, func(q models.QuizQuestion) bool { return q.ID == input.QuestionID }) {
+			global.Log.Warn(errMessage.ErrInvalidQuizAnswer.Error(), zap.Int("attemptID", attempt.ID), zap.Int("questionID", input.QuestionID))
+			return nil, response.CodeQuizInvalidAnswer
+		}
+		responses[input.QuestionID] = strings.TrimSpace(input.Response)
+	}
+
+	answers := make([]models.QuizAnswer, len(questions))
+	total, pending := 0.0, 0
+	for i := range questions {
+		answers[i] = gradeAnswer(&questions[i], responses[questions[i].ID])
+		answers[i].AttemptID = attempt.ID
+		answers[i].UserID = userID
+		if answers[i].Score.Valid {
+			total += answers[i].Score.Float64
+		} else {
+			pending++
+		}
+	}
+
+	// Short answers are graded by AI, which the plan has to allow before anything is saved
+	reservation := 0
+	if pending > 0 {
+		reservation, code = s.quotaService.ReserveAIOperation(ctx, userID, consts.UsageOperation.QUIZ_GRADE)
+		if code != response.CodeSuccess {
+			return nil, code
+		}
+	}
+
+	now := time.Now()
+	updates := map[string]any{
+		"score":        total,
+		"max_score":    len(questions),
+		"submitted_at": now,
+	}
+	if pending == 0 {
+		updates["status"] = consts.QuizAttemptStatus.GRADED
+		updates["graded_at"] = now
+	} else {
+		updates["status"] = consts.QuizAttemptStatus.GRADING
+	}
+
+	err = s.quizRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		quizRepo := s.quizRepo.WithTx(tx)
+		if err := quizRepo.CreateAnswers(ctx, answers); err != nil {
+			return err
+		}
+		return quizRepo.UpdateAttempt(ctx, attempt.ID, updates)
+	})
+	if err != nil {
+		s.quotaService.ReleaseAIOperation(ctx, reservation)
+		// The answers are unique per attempt and question, so a concurrent submit loses here
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			global.Log.Warn(errMessage.ErrQuizAttemptSubmitted.Error(), zap.Int("attemptID", attempt.ID))
+			return nil, response.CodeQuizAttemptSubmitted
+		}
+		global.Log.Error("Error submitting quiz attempt", zap.Error(err), zap.Int("attemptID", attempt.ID))
+		return nil, response.CodeServerBusy
+	}
+
+	if pending > 0 {
+		payload := models.QuizGradePayload{AttemptID: attempt.ID, UserID: userID, ReservationID: reservation}
+		if _, err := s.jobs.Enqueue(ctx, consts.JobType.QUIZ_GRADE, payload); err != nil {
+			s.quotaService.ReleaseAIOperation(ctx, reservation)
+			global.Log.Error("Error enqueuing quiz grading", zap.Error(err), zap.Int("attemptID", attempt.ID))
+			failed := map[string]any{
+				"status": consts.QuizAttemptStatus.FAILED,
+				"error":  "could not be queued",
+			}
+			if err := s.quizRepo.UpdateAttempt(ctx, attempt.ID, failed); err != nil {
+				global.Log.Error("Error updating quiz attempt status", zap.Error(err), zap.Int("attemptID", attempt.ID))
+			}
+			return nil, response.CodeServerBusy
+		}
+	}
+
+	global.Log.Info("Quiz attempt submitted", zap.Int("attemptID", attempt.ID), zap.Float64("score", total), zap.Int("pending", pending))
+	return s.GetAttempt(ctx, userID, quizID, attempt.ID)
+}
+
+func (s *QuizService) getQuiz(ctx context.Context, userID string, id int) (*models.Quiz, int) {
+	quiz, err := s.quizRepo.GetQuizByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrQuizNotFound.Error(), zap.String("userID", userID), zap.Int("quizID", id))
+			return nil, response.CodeQuizNotFound
+		}
+		global.Log.Error("Error getting quiz", zap.Error(err), zap.Int("quizID", id))
+		return nil, response.CodeServerBusy
+	}
+	return quiz, response.CodeSuccess
+}
+
+func (s *QuizService) getAttempt(ctx context.Context, userID string, quizID, attemptID int) (*models.QuizAttempt, int) {
+	attempt, err := s.quizRepo.GetAttemptByID(ctx, attemptID, userID)
+	if err == nil && attempt.QuizID != quizID {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrQuizAttemptNotFound.Error(), zap.String("userID", userID), zap.Int("attemptID", attemptID))
+			return nil, response.CodeQuizAttemptNotFound
+		}
+		global.Log.Error("Error getting quiz attempt", zap.Error(err), zap.Int("attemptID", attemptID))
+		return nil, response.CodeServerBusy
+	}
+	return attempt, response.CodeSuccess
+}
+
+// attemptResult pairs every question with its answer, adding the solutions once the attempt is graded
+func (s *QuizService) attemptResult(ctx context.Context, attempt *models.QuizAttempt) (*models.QuizAttemptResult, int) {
+	questions, err := s.quizRepo.ListQuestions(ctx, attempt.QuizID)
+	if err != nil {
+		global.Log.Error("Error listing quiz questions", zap.Error(err), zap.Int("quizID", attempt.QuizID))
+		return nil, response.CodeServerBusy
+	}
+	answers, err := s.quizRepo.ListAnswers(ctx, attempt.ID)
+	if err != nil {
+		global.Log.Error("Error listing quiz answers", zap.Error(err), zap.Int("attemptID", attempt.ID))
+		return nil, response.CodeServerBusy
+	}
+
+	byQuestion := make(map[int]*models.QuizAnswer, len(answers))
+	for i := range answers {
+		byQuestion[answers[i].QuestionID] = &answers[i]
+	}
+
+	result := &models.QuizAttemptResult{QuizAttempt: *attempt, Items: make([]models.QuizAttemptItem, len(questions))}
+	for i := range questions {
+		item := models.QuizAttemptItem{Question: questionView(&questions[i]), Answer: byQuestion[questions[i].ID]}
+		if attempt.Status == consts.QuizAttemptStatus.GRADED {
+			item.Solution = questionSolution(&questions[i])
+		}
+		result.Items[i] = item
+	}
+	return result, response.CodeSuccess
+}
+
+func questionView(question *models.QuizQuestion) models.QuizQuestionView {
+	view := models.QuizQuestionView{
+		ID:         question.ID,
+		Position:   question.Position,
+		Type:       question.Type,
+		Difficulty: question.Difficulty,
+		Prompt:     question.Prompt,
+	}
+	if len(question.Options) > 0 {
+		json.Unmarshal(question.Options, &view.Options)
+	}
+	return view
+}
+
+func questionSolution(question *models.QuizQuestion) *models.QuizSolution {
+	return &models.QuizSolution{
+		AnswerIndex:     question.AnswerIndex,
+		AnswerBool:      question.AnswerBool,
+		ReferenceAnswer: question.ReferenceAnswer,
+		Rubric:          questionRubric(question),
+		Explanation:     question.Explanation,
+	}
+}
+
+func questionRubric(question *models.QuizQuestion) []models.RubricCriterion {
+	var rubric []models.RubricCriterion
+	if len(question.Rubric) > 0 {
+		json.Unmarshal(question.Rubric, &rubric)
+	}
+	return rubric
+}
+
+// gradeAnswer scores multiple choice and true/false answers against the key.
+// Short answers are left ungraded for the AI unless they are empty. Responses
+// are stored normalized so that statistics can group them.
+func gradeAnswer(question *models.QuizQuestion, raw string) models.QuizAnswer {
+	answer := models.QuizAnswer{QuestionID: question.ID, Response: raw, GradedBy: consts.QuizGrader.AUTO}
+	switch question.Type {
+	case consts.QuizQuestionType.MCQ:
+		if index, err := strconv.Atoi(raw); err == nil {
+			answer.Response = strconv.Itoa(index)
+			answer.IsCorrect = question.AnswerIndex != nil && index == *question.AnswerIndex
+		}
+	case consts.QuizQuestionType.TRUE_FALSE:
+		if value, err := strconv.ParseBool(strings.ToLower(raw)); err == nil {
+			answer.Response = strconv.FormatBool(value)
+			answer.IsCorrect = question.AnswerBool != nil && value == *question.AnswerBool
+		}
+	case consts.QuizQuestionType.SHORT_ANSWER:
+		if raw != "" {
+			answer.GradedBy = ""
+			return answer
+		}
+	}
+
+	answer.Score = sql.NullFloat64{Valid: true}
+	if answer.IsCorrect {
+		answer.Score.Float64 = 1
+	}
+	return answer
+}
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/pkg/ai"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// IQuizGenerationService runs in the worker and writes quiz questions from a course's notes
+type IQuizGenerationService interface {
+	// GenerateQuiz returns an error only for failures worth retrying;
+	// quizzes the model cannot write are recorded on the quiz row instead
+	GenerateQuiz(ctx context.Context, payload models.QuizGeneratePayload) error
+}
+
+type QuizGenerationService struct {
+	quizRepo      repo.IQuizRepository
+	noteRepo      repo.INoteRepository
+	provider      ai.Provider
+	progressStore repo.IProgressStore
+	usageRepo     repo.IUsageRepository
+}
+
+func NewQuizGenerationService(quizRepository repo.IQuizRepository, noteRepository repo.INoteRepository, provider ai.Provider,
+	progressStore repo.IProgressStore, usageRepository repo.IUsageRepository) IQuizGenerationService {
+	return &QuizGenerationService{
+		quizRepo:      quizRepository,
+		noteRepo:      noteRepository,
+		provider:      provider,
+		progressStore: progressStore,
+		usageRepo:     usageRepository,
+	}
+}
+
+func (s *QuizGenerationService) GenerateQuiz(ctx context.Context, payload models.QuizGeneratePayload) error {
+	quiz, err := s.quizRepo.GetQuizByID(ctx, payload.QuizID, payload.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Deleted since the job was enqueued
+			global.Log.Warn(errMessage.ErrQuizNotFound.Error(), zap.Int("quizID", payload.QuizID))
+			releaseUsage(ctx, s.usageRepo, payload.ReservationID)
+			return nil
+		}
+		return fmt.Errorf("get quiz %d: %w", payload.QuizID, err)
+	}
+	if quiz.Status == consts.QuizStatus.DONE {
+		return nil
+	}
+
+	notes, err := s.noteRepo.ListNotes(ctx, models.NoteFilter{UserID: quiz.UserID, CourseID: &quiz.CourseID})
+	if err != nil {
+		return fmt.Errorf("list notes of course %d: %w", quiz.CourseID, err)
+	}
+
+	err = s.quizRepo.UpdateQuiz(ctx, quiz.ID, map[string]any{
+		"status": consts.QuizStatus.PROCESSING,
+		"error":  sql.NullString{},
+	})
+	if err != nil {
+		return fmt.Errorf("update status of quiz %d: %w", quiz.ID, err)
+	}
+	startProgress(ctx, s.progressStore, consts.JobProgressTopic.QUIZ, quiz.ID, models.JobProgress{Status: consts.QuizStatus.PROCESSING})
+
+	run := &quizRun{
+		provider:   s.provider,
+		language:   consts.AI_LANGUAGES[quiz.Language],
+		difficulty: quiz.Difficulty,
+		types:      strings.Split(quiz.Types, ","),
+		progress: func(done, total int) {
+			publishProgress(ctx, s.progressStore, consts.JobProgressTopic.QUIZ, quiz.ID, models.JobProgress{
+				Status: consts.QuizStatus.PROCESSING,
+				Stage:  "generate",
+				Done:   done,
+				Total:  total,
+			})
+		},
+	}
+	if run.language == "" {
+		run.language = consts.AI_LANGUAGES[consts.AI_DEFAULT_LANGUAGE]
+	}
+
+	questions, err := run.generate(ctx, noteChunks(notes), quiz.Count)
+	if err != nil {
+		if statusErr := s.fail(ctx, quiz.ID, err); statusErr != nil {
+			global.Log.Error("Error updating quiz status", zap.Error(statusErr), zap.Int("quizID", quiz.ID))
+		}
+		if permanentAIError(err) {
+			global.Log.Warn("Failed to generate quiz", zap.Int("quizID", quiz.ID), zap.Error(err))
+			releaseUsage(ctx, s.usageRepo, payload.ReservationID)
+			return nil
+		}
+		// Rate limits, outages and timeouts: let the queue retry
+		return fmt.Errorf("generate quiz %d: %w", quiz.ID, err)
+	}
+
+	for i := range questions {
+		questions[i].QuizID = quiz.ID
+		questions[i].Position = i + 1
+	}
+	err = s.quizRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		quizRepo := s.quizRepo.WithTx(tx)
+		if err := quizRepo.CreateQuestions(ctx, questions); err != nil {
+			return err
+		}
+		return quizRepo.UpdateQuiz(ctx, quiz.ID, map[string]any{
+			"status":        consts.QuizStatus.DONE,
+			"provider":      s.provider.Name(),
+			"model":         run.model,
+			"input_tokens":  run.usage.InputTokens,
+			"output_tokens": run.usage.OutputTokens,
+			"completed_at":  time.Now(),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("store questions of quiz %d: %w", quiz.ID, err)
+	}
+	publishProgress(ctx, s.progressStore, consts.JobProgressTopic.QUIZ, quiz.ID, models.JobProgress{Status: consts.QuizStatus.DONE, Final: true})
+	recordUsage(ctx, s.usageRepo, models.UsageRecord{
+		ID:           payload.ReservationID,
+		UserID:       quiz.UserID,
+		Operation:    consts.UsageOperation.QUIZ_GENERATE,
+		Reference:    progressTopic(consts.JobProgressTopic.QUIZ, quiz.ID),
+		Provider:     s.provider.Name(),
+		Model:        run.model,
+		InputTokens:  run.usage.InputTokens,
+		OutputTokens: run.usage.OutputTokens,
+	})
+
+	global.Log.Info("Success generating quiz", zap.Int("quizID", quiz.ID), zap.Int("questions", len(questions)), zap.Int("calls", run.calls))
+	return nil
+}
+
+func (s *QuizGenerationService) fail(ctx context.Context, id int, cause error) error {
+	message := truncate(cause.Error(), consts.QUIZ_ERROR_LENGTH)
+	publishProgress(ctx, s.progressStore, consts.JobProgressTopic.QUIZ, id, models.JobProgress{Status: consts.QuizStatus.FAILED, Error: message, Final: true})
+	return s.quizRepo.UpdateQuiz(ctx, id, map[string]any{
+		"status": consts.QuizStatus.FAILED,
+		"error":  sql.NullString{String: message, Valid: true},
+	})
+}
+
+// noteChunks splits the notes into chunks numbered across the whole course,
+// oldest lecture first
+func noteChunks(notes []models.Note) []sourceChunk {
+	var chunks []sourceChunk
+	for i := len(notes) - 1; i >= 0; i-- {
+		noteID := notes[i].ID
+		for _, chunk := range noteSourceChunks(&notes[i]) {
+			chunk.index, chunk.noteID = len(chunks), &noteID
+			chunks = append(chunks, chunk)
+		}
+	}
+	return chunks
+}
+
+// quizRun generates the questions of one quiz, batching the notes the same
+// way flashcard generation batches its source
+type quizRun struct {
+	provider   ai.Provider
+	language   string
+	difficulty string
+	types      []string
+	progress   func(done, total int) // optional, called before each batch
+
+	model string
+	usage ai.Usage
+	calls int
+}
+
+func (r *quizRun) generate(ctx context.Context, chunks []sourceChunk, count int) ([]models.QuizQuestion, error) {
+	chunks = slices.DeleteFunc(chunks, func(chunk sourceChunk) bool { return strings.TrimSpace(chunk.text) == "" })
+	if len(chunks) == 0 || count <= 0 {
+		return nil, errMessage.ErrQuizNoNotes
+	}
+
+	batches := sampleBatches(batchChunks(chunks, consts.QUIZ_BATCH_TOKENS), min(consts.QUIZ_MAX_BATCHES, count))
+	counts := shareCount(batches, count)
+
+	var questions []models.QuizQuestion
+	for i, batch := range batches {
+		if r.progress != nil {
+			r.progress(i, len(batches))
+		}
+		generated, err := r.chat(ctx, batch, counts[i])
+		if err != nil {
+			return nil, err
+		}
+		questions = append(questions, generated...)
+	}
+	return questions, nil
+}
+
+// chat asks for n questions about one batch of chunks. Malformed questions
+// reject the whole reply so that the model rewrites it.
+func (r *quizRun) chat(ctx context.Context, batch []sourceChunk, n int) ([]models.QuizQuestion, error) {
+	var b strings.Builder
+	for _, chunk := range batch {
+		fmt.Fprintf(&b, "[chunk %d]\n%s\n\n", chunk.index, chunk.text)
+	}
+	req := ai.ChatRequest{
+		System:    fmt.Sprintf(consts.QUIZ_PROMPT, r.language, n, r.difficulty, strings.Join(r.types, ", ")),
+		Messages:  []ai.Message{{Role: ai.RoleUser, Content: strings.TrimSpace(b.String())}},
+		MaxTokens: consts.QUIZ_MAX_OUTPUT_TOKENS,
+	}
+
+	var questions []models.QuizQuestion
+	resp, usage, err := ai.ChatJSON(ctx, r.provider, req, consts.QUIZ_OUTPUT_ATTEMPTS, func(object string) error {
+		var err error
+		questions, err = parseQuizQuestions(object, batch, r.types, r.difficulty)
+		return err
+	})
+	r.calls++
+	r.usage.InputTokens += usage.InputTokens
+	r.usage.OutputTokens += usage.OutputTokens
+	if err != nil {
+		return nil, err
+	}
+	r.model = resp.Model
+
+	if len(questions) > n {
+		questions = questions[:n]
+	}
+	return questions, nil
+}
+
+// parseQuizQuestions decodes generated questions and checks that each is
+// complete for its type. The returned error names the first bad question so
+// that the model can fix it.
+func parseQuizQuestions(object string, batch []sourceChunk, types []string, difficulty string) ([]models.QuizQuestion, error) {
+	var raw struct {
+		Questions []struct {
+			Type            string                   `json:"type"`
+			Prompt          string                   `json:"prompt"`
+			Options         []string                 `json:"options"`
+			AnswerIndex     *int                     `json:"answer_index"`
+			Answer          *bool                    `json:"answer"`
+			ReferenceAnswer string                   `json:"reference_answer"`
+			Rubric          []models.RubricCriterion `json:"rubric"`
+			Explanation     string                   `json:"explanation"`
+			Chunk           *int                     `json:"chunk"`
+		} `json:"questions"`
+	}
+	if err := json.Unmarshal([]byte(object), &raw); err != nil {
+		return nil, err
+	}
+	if len(raw.Questions) == 0 {
+		return nil, errMessage.ErrInvalidQuizOutput
+	}
+
+	questions := make([]models.QuizQuestion, 0, len(raw.Questions))
+	for i, q := range raw.Questions {
+		invalid := func(reason string) error {
+			return fmt.Errorf("%w: question %d %s", errMessage.ErrInvalidQuizOutput, i+1, reason)
+		}
+
+		question := models.QuizQuestion{
+			Type:        strings.ToLower(strings.TrimSpace(q.Type)),
+			Difficulty:  difficulty,
+			Prompt:      strings.TrimSpace(q.Prompt),
+			Explanation: strings.TrimSpace(q.Explanation),
+			NoteID:      batch[0].noteID,
+		}
+		if !slices.Contains(types, question.Type) {
+			return nil, invalid(fmt.Sprintf("has type %q, expected one of %s", q.Type, strings.Join(types, ", ")))
+		}
+		if question.Prompt == "" {
+			return nil, invalid("has no prompt")
+		}
+
+		switch question.Type {
+		case consts.QuizQuestionType.MCQ:
+			options := make([]string, len(q.Options))
+			seen := map[string]bool{}
+			for j, option := range q.Options {
+				options[j] = strings.TrimSpace(option)
+				key := strings.ToLower(options[j])
+				if options[j] == "" || seen[key] {
+					return nil, invalid("has an empty or repeated option")
+				}
+				seen[key] = true
+			}
+			if len(options) < consts.QUIZ_MCQ_MIN_OPTIONS || len(options) > consts.QUIZ_MCQ_MAX_OPTIONS {
+				return nil, invalid(fmt.Sprintf("has %d options, expected %d to %d", len(options), consts.QUIZ_MCQ_MIN_OPTIONS, consts.QUIZ_MCQ_MAX_OPTIONS))
+			}
+			if q.AnswerIndex == nil || *q.AnswerIndex < 0 || *q.AnswerIndex >= len(options) {
+				return nil, invalid("has no answer_index within its options")
+			}
+			question.Options, _ = json.Marshal(options)
+			question.AnswerIndex = q.AnswerIndex
+		case consts.QuizQuestionType.TRUE_FALSE:
+			if q.Answer == nil {
+				return nil, invalid("has no boolean answer")
+			}
+			question.AnswerBool = q.Answer
+		case consts.QuizQuestionType.SHORT_ANSWER:
+			question.ReferenceAnswer = strings.TrimSpace(q.ReferenceAnswer)
+			if question.ReferenceAnswer == "" {
+				return nil, invalid("has no reference_answer")
+			}
+			if len(q.Rubric) == 0 || len(q.Rubric) > consts.QUIZ_RUBRIC_MAX_CRITERIA {
+				return nil, invalid(fmt.Sprintf("needs 1 to %d rubric criteria", consts.QUIZ_RUBRIC_MAX_CRITERIA))
+			}
+			for j := range q.Rubric {
+				q.Rubric[j].Criterion = strings.TrimSpace(q.Rubric[j].Criterion)
+				if q.Rubric[j].Criterion == "" || q.Rubric[j].Points < 1 || q.Rubric[j].Points > consts.QUIZ_RUBRIC_MAX_POINTS {
+					return nil, invalid(fmt.Sprintf("has a rubric criterion without text or with points outside 1 to %d", consts.QUIZ_RUBRIC_MAX_POINTS))
+				}
+			}
+			question.Rubric, _ = json.Marshal(q.Rubric)
+		}
+
+		if q.Chunk != nil {
+			for _, chunk := range batch {
+				if chunk.index == *q.Chunk {
+					question.NoteID = chunk.noteID
+					break
+				}
+			}
+		}
+		questions = append(questions, question)
+	}
+	return questions, nil
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/pkg/ai"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// IQuizGradingService runs in the worker and grades short answers against their rubric
+type IQuizGradingService interface {
+	// GradeAttempt returns an error only for failures worth retrying. Answers
+	// graded before a failure keep their score, so a retry picks up the rest.
+	GradeAttempt(ctx context.Context, payload models.QuizGradePayload) error
+}
+
+type QuizGradingService struct {
+	quizRepo  repo.IQuizRepository
+	provider  ai.Provider
+	usageRepo repo.IUsageRepository
+}
+
+func NewQuizGradingService(quizRepository repo.IQuizRepository, provider ai.Provider, usageRepository repo.IUsageRepository) IQuizGradingService {
+	return &QuizGradingService{
+		quizRepo:  quizRepository,
+		provider:  provider,
+		usageRepo: usageRepository,
+	}
+}
+
+func (s *QuizGradingService) GradeAttempt(ctx context.Context, payload models.QuizGradePayload) error {
+	attempt, err := s.quizRepo.GetAttemptByID(ctx, payload.AttemptID, payload.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Deleted with its quiz since the job was enqueued
+			global.Log.Warn(errMessage.ErrQuizAttemptNotFound.Error(), zap.Int("attemptID", payload.AttemptID))
+			releaseUsage(ctx, s.usageRepo, payload.ReservationID)
+			return nil
+		}
+		return fmt.Errorf("get quiz attempt %d: %w", payload.AttemptID, err)
+	}
+	if attempt.Status != consts.QuizAttemptStatus.GRADING {
+		return nil
+	}
+
+	quiz, err := s.quizRepo.GetQuizByID(ctx, attempt.QuizID, attempt.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrQuizNotFound.Error(), zap.Int("quizID", attempt.QuizID))
+			releaseUsage(ctx, s.usageRepo, payload.ReservationID)
+			return nil
+		}
+		return fmt.Errorf("get quiz %d: %w", attempt.QuizID, err)
+	}
+	questions, err := s.quizRepo.ListQuestions(ctx, quiz.ID)
+	if err != nil {
+		return fmt.Errorf("list questions of quiz %d: %w", quiz.ID, err)
+	}
+	answers, err := s.quizRepo.ListAnswers(ctx, attempt.ID)
+	if err != nil {
+		return fmt.Errorf("list answers of attempt %d: %w", attempt.ID, err)
+	}
+
+	byID := make(map[int]*models.QuizQuestion, len(questions))
+	for i := range questions {
+		byID[questions[i].ID] = &questions[i]
+	}
+	run := &gradingRun{provider: s.provider, language: consts.AI_LANGUAGES[quiz.Language]}
+	if run.language == "" {
+		run.language = consts.AI_LANGUAGES[consts.AI_DEFAULT_LANGUAGE]
+	}
+
+	total := 0.0
+	for i := range answers {
+		answer := &answers[i]
+		question := byID[answer.QuestionID]
+		if !answer.Score.Valid && question != nil {
+			score, feedback, err := run.grade(ctx, question, answer.Response)
+			if err != nil {
+				if permanentAIError(err) {
+					global.Log.Warn("Failed to grade short answer", zap.Int("attemptID", attempt.ID), zap.Int("questionID", question.ID), zap.Error(err))
+					if statusErr := s.fail(ctx, attempt.ID, err); statusErr != nil {
+						global.Log.Error("Error updating quiz attempt status", zap.Error(statusErr), zap.Int("attemptID", attempt.ID))
+					}
+					releaseUsage(ctx, s.usageRepo, payload.ReservationID)
+					return nil
+				}
+				// Rate limits, outages and timeouts: let the queue retry
+				return fmt.Errorf("grade answer %d: %w", answer.ID, err)
+			}
+
+			answer.Score = sql.NullFloat64{Float64: score, Valid: true}
+			err = s.quizRepo.UpdateAnswer(ctx, answer.ID, map[string]any{
+				"score":      answer.Score,
+				"is_correct": score >= consts.QUIZ_CORRECT_SCORE,
+				"feedback":   feedback,
+				"graded_by":  consts.QuizGrader.AI,
+			})
+			if err != nil {
+				return fmt.Errorf("store grade of answer %d: %w", answer.ID, err)
+			}
+		}
+		total += answer.Score.Float64
+	}
+
+	err = s.quizRepo.UpdateAttempt(ctx, attempt.ID, map[string]any{
+		"status":    consts.QuizAttemptStatus.GRADED,
+		"score":     total,
+		"graded_at": time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("update status of quiz attempt %d: %w", attempt.ID, err)
+	}
+	recordUsage(ctx, s.usageRepo, models.UsageRecord{
+		ID:           payload.ReservationID,
+		UserID:       attempt.UserID,
+		Operation:    consts.UsageOperation.QUIZ_GRADE,
+		Reference:    fmt.Sprintf("quiz_attempt:%d", attempt.ID),
+		Provider:     s.provider.Name(),
+		Model:        run.model,
+		InputTokens:  run.usage.InputTokens,
+		OutputTokens: run.usage.OutputTokens,
+	})
+
+	global.Log.Info("Success grading quiz attempt", zap.Int("attemptID", attempt.ID), zap.Float64("score", total))
+	return nil
+}
+
+func (s *QuizGradingService) fail(ctx context.Context, id int, cause error) error {
+	return s.quizRepo.UpdateAttempt(ctx, id, map[string]any{
+		"status": consts.QuizAttemptStatus.FAILED,
+		"error":  sql.NullString{String: truncate(cause.Error(), consts.QUIZ_ERROR_LENGTH), Valid: true},
+	})
+}
+
+// gradingRun grades the short answers of one attempt and adds up what the calls used
+type gradingRun struct {
+	provider ai.Provider
+	language string
+	model    string
+	usage    ai.Usage
+}
+
+// grade scores a response against the question's rubric and returns the
+// fraction of the rubric's points earned with feedback
+func (r *gradingRun) grade(ctx context.Context, question *models.QuizQuestion, response string) (float64, string, error) {
+	rubric := questionRubric(question)
+	if len(rubric) == 0 {
+		return 0, "", fmt.Errorf("%w: question %d has no rubric", errMessage.ErrInvalidGradingOutput, question.ID)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Question:\n%s\n\nReference answer:\n%s\n\nRubric:\n", question.Prompt, question.ReferenceAnswer)
+	for i, criterion := range rubric {
+		fmt.Fprintf(&b, "%d. %s (%d points)\n", i+1, criterion.Criterion, criterion.Points)
+	}
+	fmt.Fprintf(&b, "\nStudent answer:\n%s", response)
+
+	req := ai.ChatRequest{
+		System:    fmt.Sprintf(consts.QUIZ_GRADING_PROMPT, r.language),
+		Messages:  []ai.Message{{Role: ai.RoleUser, Content: b.String()}},
+		MaxTokens: consts.QUIZ_GRADING_MAX_TOKENS,
+	}
+
+	var score float64
+	var feedback string
+	resp, usage, err := ai.ChatJSON(ctx, r.provider, req, consts.QUIZ_OUTPUT_ATTEMPTS, func(object string) error {
+		var err error
+		score, feedback, err = parseGrading(object, rubric)
+		return err
+	})
+	r.usage.InputTokens += usage.InputTokens
+	r.usage.OutputTokens += usage.OutputTokens
+	if resp != nil {
+		r.model = resp.Model
+	}
+	return score, feedback, err
+}
+
+// parseGrading checks that every criterion was awarded at most its points
+func parseGrading(object string, rubric []models.RubricCriterion) (float64, string, error) {
+	var raw struct {
+		Criteria []struct {
+			Criterion string  `json:"criterion"`
+			Awarded   float64 `json:"awarded"`
+		} `json:"criteria"`
+		Feedback string `json:"feedback"`
+	}
+	if err := json.Unmarshal([]byte(object), &raw); err != nil {
+		return 0, "", err
+	}
+	if len(raw.Criteria) != len(rubric) {
+		return 0, "", fmt.Errorf("%w: expected %d criteria, got %d", errMessage.ErrInvalidGradingOutput, len(rubric), len(raw.Criteria))
+	}
+
+	awarded, possible := 0.0, 0
+	for i, criterion := range raw.Criteria {
+		if criterion.Awarded < 0 || criterion.Awarded > float64(rubric[i].Points) {
+			return 0, "", fmt.Errorf("%w: criterion %d awarded %v of %d points", errMessage.ErrInvalidGradingOutput, i+1, criterion.Awarded, rubric[i].Points)
+		}
+		awarded += criterion.Awarded
+		possible += rubric[i].Points
+	}
+	score := math.Round(awarded/float64(possible)*1000) / 1000
+	return score, strings.TrimSpace(raw.Feedback), nil
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// IQuotaService enforces the limits of the user's plan. AI operations are
+// reserved before work starts, so concurrent requests cannot go past the plan's
+// operations, and completed with their tokens once the work succeeds. Tokens are
+// only known by then, so operations already running when the token limit is
+// reached may still finish.
+type IQuotaService interface {
+	// CheckAIOperation reports whether the plan allows another AI operation in the current billing period
+	CheckAIOperation(ctx context.Context, userID string) int
+	// ReserveAIOperation counts an operation against the plan before it starts and returns
+	// the reservation's ID, which the work completes with recordUsage or gives back with
+	// ReleaseAIOperation when it fails
+	ReserveAIOperation(ctx context.Context, userID, operation string) (int, int)
+	ReleaseAIOperation(ctx context.Context, reservationID int)
+	// CheckAITokens reports whether the plan has tokens left for background work,
+	// which does not count as an operation
+	CheckAITokens(ctx context.Context, userID string) int
+	// CheckCourses reports whether the plan allows count more courses
+	CheckCourses(ctx context.Context, userID string, count int) int
+	GetUsage(ctx context.Context, userID string) (*models.UsageSummary, int)
+	ListPlans(ctx context.Context) ([]models.Plan, int)
+}
+
+type QuotaService struct {
+	planRepo   repo.IPlanRepository
+	usageRepo  repo.IUsageRepository
+	userRepo   repo.IUserRepository
+	courseRepo repo.ICourseRepository
+	fileRepo   repo.IFileRepository
+}
+
+func NewQuotaService(planRepository repo.IPlanRepository, usageRepository repo.IUsageRepository, userRepository repo.IUserRepository,
+	courseRepository repo.ICourseRepository, fileRepository repo.IFileRepository) IQuotaService {
+	return &QuotaService{
+		planRepo:   planRepository,
+		usageRepo:  usageRepository,
+		userRepo:   userRepository,
+		courseRepo: courseRepository,
+		fileRepo:   fileRepository,
+	}
+}
+
+func (s *QuotaService) CheckAIOperation(ctx context.Context, userID string) int {
+	usage, code := s.aiUsage(ctx, userID)
+	if code != response.CodeSuccess {
+		return code
+	}
+	if usage.AIOperations.Exceeded() || usage.AITokens.Exceeded() {
+		global.Log.Warn(errMessage.ErrAIQuotaExceeded.Error(), zap.String("userID", userID), zap.String("plan", usage.Plan.Code),
+			zap.Int64("operations", usage.AIOperations.Used), zap.Int64("tokens", usage.AITokens.Used))
+		return response.CodeAIQuotaExceeded
+	}
+	return response.CodeSuccess
+}
+
+func (s *QuotaService) ReserveAIOperation(ctx context.Context, userID, operation string) (int, int) {
+	usage, code := s.aiUsage(ctx, userID)
+	if code != response.CodeSuccess {
+		return 0, code
+	}
+	if usage.AITokens.Exceeded() {
+		global.Log.Warn(errMessage.ErrAIQuotaExceeded.Error(), zap.String("userID", userID), zap.String("plan", usage.Plan.Code), zap.Int64("tokens", usage.AITokens.Used))
+		return 0, response.CodeAIQuotaExceeded
+	}
+
+	record := &models.UsageRecord{UserID: userID, Operation: operation}
+	reserved, err := s.usageRepo.ReserveRecord(ctx, record, usage.PeriodStart, usage.PeriodEnd, usage.AIOperations.Limit)
+	if err != nil {
+		global.Log.Error("Error reserving usage", zap.Error(err), zap.String("userID", userID), zap.String("operation", operation))
+		return 0, response.CodeServerBusy
+	}
+	if !reserved {
+		global.Log.Warn(errMessage.ErrAIQuotaExceeded.Error(), zap.String("userID", userID), zap.String("plan", usage.Plan.Code), zap.String("operation", operation))
+		return 0, response.CodeAIQuotaExceeded
+	}
+	return record.ID, response.CodeSuccess
+}
+
+func (s *QuotaService) ReleaseAIOperation(ctx context.Context, reservationID int) {
+	releaseUsage(ctx, s.usageRepo, reservationID)
+}
+
+func (s *QuotaService) CheckAITokens(ctx context.Context, userID string) int {
+	usage, code := s.aiUsage(ctx, userID)
+	if code != response.CodeSuccess {
+		return code
+	}
+	if usage.AITokens.Exceeded() {
+		global.Log.Warn(errMessage.ErrAIQuotaExceeded.Error(), zap.String("userID", userID), zap.String("plan", usage.Plan.Code), zap.Int64("tokens", usage.AITokens.Used))
+		return response.CodeAIQuotaExceeded
+	}
+	return response.CodeSuccess
+}
+
+func (s *QuotaService) CheckCourses(ctx context.Context, userID string, count int) int {
+	if count <= 0 {
+		return response.CodeSuccess
+	}
+	plan, _, code := s.userPlan(ctx, userID)
+	if code != response.CodeSuccess {
+		return code
+	}
+	if plan.MaxCourses == nil {
+		return response.CodeSuccess
+	}
+
+	courses, err := s.courseRepo.CountCourses(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error counting courses", zap.Error(err), zap.String("userID", userID))
+		return response.CodeServerBusy
+	}
+	if courses+int64(count) > int64(*plan.MaxCourses) {
+		global.Log.Warn(errMessage.ErrCourseLimitReached.Error(), zap.String("userID", userID), zap.String("plan", plan.Code), zap.Int64("courses", courses), zap.Int("adding", count))
+		return response.CodeCourseLimitReached
+	}
+	return response.CodeSuccess
+}
+
+func (s *QuotaService) GetUsage(ctx context.Context, userID string) (*models.UsageSummary, int) {
+	usage, code := s.aiUsage(ctx, userID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	courses, err := s.courseRepo.CountCourses(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error counting courses", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	usage.Courses = models.UsageCounter{Used: courses, Limit: int64Limit(usage.Plan.MaxCourses)}
+
+	used, err := s.fileRepo.UsedBytes(ctx, userID, time.Now().Add(-presignExpiry()))
+	if err != nil {
+		global.Log.Error("Error summing file sizes", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	quota := StorageQuota(usage.Plan.Code)
+	usage.StorageBytes = models.UsageCounter{Used: used, Limit: &quota}
+
+	return usage, response.CodeSuccess
+}
+
+func (s *QuotaService) ListPlans(ctx context.Context) ([]models.Plan, int) {
+	plans, err := s.planRepo.ListPlans(ctx)
+	if err != nil {
+		global.Log.Error("Error listing plans", zap.Error(err))
+		return nil, response.CodeServerBusy
+	}
+	return plans, response.CodeSuccess
+}
+
+// aiUsage totals the ledger over the current billing period
+func (s *QuotaService) aiUsage(ctx context.Context, userID string) (*models.UsageSummary, int) {
+	plan, user, code := s.userPlan(ctx, userID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	subscription, err := s.planRepo.GetSubscriptionByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		global.Log.Error("Error getting subscription", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	start, end := billingPeriod(subscription, time.Now(), userLocation(user))
+
+	operations, err := s.usageRepo.SumUsage(ctx, userID, start, end)
+	if err != nil {
+		global.Log.Error("Error summing usage", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	usage := &models.UsageSummary{
+		Plan:         *plan,
+		PeriodStart:  start,
+		PeriodEnd:    end,
+		AIOperations: models.UsageCounter{Limit: int64Limit(plan.AIOperations)},
+		AITokens:     models.UsageCounter{Limit: plan.AITokens},
+		Operations:   operations,
+	}
+	for _, operation := range operations {
+		if !slices.Contains(consts.USAGE_BACKGROUND_OPERATIONS, operation.Operation) {
+			usage.AIOperations.Used += operation.Count
+		}
+		usage.AITokens.Used += operation.InputTokens + operation.OutputTokens
+	}
+	return usage, response.CodeSuccess
+}
+
+// userPlan returns the plan of the user's tier; unknown tiers get the free plan
+func (s *QuotaService) userPlan(ctx context.Context, userID string) (*models.Plan, *models.User, int) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrUserNotFound.Error(), zap.String("userID", userID))
+			return nil, nil, response.CodeUserNotFound
+		}
+
+		global.Log.Error("Error getting user", zap.Error(err), zap.String("userID", userID))
+		return nil, nil, response.CodeServerBusy
+	}
+
+	plan, err := s.planRepo.GetPlan(ctx, user.Tier)
+	if errors.Is(err, gorm.ErrRecordNotFound) && user.Tier != consts.UserTier.FREE {
+		global.Log.Warn(errMessage.ErrPlanNotFound.Error(), zap.String("userID", userID), zap.String("tier", user.Tier))
+		plan, err = s.planRepo.GetPlan(ctx, consts.UserTier.FREE)
+	}
+	if err != nil {
+		global.Log.Error("Error getting plan", zap.Error(err), zap.String("tier", user.Tier))
+		return nil, nil, response.CodeServerBusy
+	}
+	return plan, user, response.CodeSuccess
+}
+
+// billingPeriod returns the period usage is metered over: the subscription's
+// current period, or the calendar month in the user's timezone without one
+func billingPeriod(subscription *models.Subscription, now time.Time, loc *time.Location) (time.Time, time.Time) {
+	if subscription != nil && !now.Before(subscription.CurrentPeriodStart) && now.Before(subscription.CurrentPeriodEnd) {
+		return subscription.CurrentPeriodStart, subscription.CurrentPeriodEnd
+	}
+	local := now.In(loc)
+	start := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 1, 0)
+}
+
+func int64Limit(limit *int) *int64 {
+	if limit == nil {
+		return nil
+	}
+	value := int64(*limit)
+	return &value
+}
+
+// recordUsage adds a successful AI operation to the usage ledger, completing its
+// reservation when record.ID names one. The work is done by then, so failures are
+// only logged.
+func recordUsage(ctx context.Context, usageRepo repo.IUsageRepository, record models.UsageRecord) {
+	if usageRepo == nil {
+		return
+	}
+	if err := usageRepo.CompleteRecord(ctx, &record); err != nil {
+		global.Log.Error("Error recording usage", zap.Error(err), zap.String("userID", record.UserID), zap.String("operation", record.Operation))
+	}
+}
+
+// releaseUsage gives back the reservation of an AI operation that failed
+func releaseUsage(ctx context.Context, usageRepo repo.IUsageRepository, reservationID int) {
+	if usageRepo == nil || reservationID == 0 {
+		return
+	}
+	if err := usageRepo.DeleteReservation(ctx, reservationID); err != nil {
+		global.Log.Error("Error releasing usage reservation", zap.Error(err), zap.Int("reservationID", reservationID))
+	}
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type IReminderService interface {
+	CreateReminder(ctx context.Context, userID string, req *models.CreateReminderRequest) (*models.Reminder, int)
+	GetReminder(ctx context.Context, userID string, id int) (*models.Reminder, int)
+	ListReminders(ctx context.Context, userID string, query *models.ReminderQuery) ([]models.Reminder, int)
+	UpdateReminder(ctx context.Context, userID string, id int, req *models.UpdateReminderRequest) (*models.Reminder, int)
+	UpdateReminderStatus(ctx context.Context, userID string, id int, status int8) (*models.Reminder, int)
+	DeleteReminder(ctx context.Context, userID string, id int) int
+}
+
+type ReminderService struct {
+	reminderRepo     repo.IReminderRepository
+	courseRepo       repo.ICourseRepository
+	notificationRepo repo.INotificationRepository
+	userRepo         repo.IUserRepository
+}
+
+func NewReminderService(reminderRepository repo.IReminderRepository, courseRepository repo.ICourseRepository, notificationRepository repo.INotificationRepository, userRepository repo.IUserRepository) IReminderService {
+	return &ReminderService{
+		reminderRepo:     reminderRepository,
+		courseRepo:       courseRepository,
+		notificationRepo: notificationRepository,
+		userRepo:         userRepository,
+	}
+}
+
+func (s *ReminderService) CreateReminder(ctx context.Context, userID string, req *models.CreateReminderRequest) (*models.Reminder, int) {
+	dueDate, dueTime, err := parseReminderDeadline(req.DueDate, req.DueTime)
+	if err != nil {
+		global.Log.Warn(errMessage.ErrInvalidReminderDueDate.Error(), zap.String("due_date", req.DueDate), zap.String("due_time", req.DueTime))
+		return nil, response.CodeReminderInvalidDueDate
+	}
+
+	if req.Type != consts.ReminderType.EXAM && (req.Location != nil || req.Weight != nil || req.Score != nil) {
+		global.Log.Warn(errMessage.ErrReminderExamFieldsOnly.Error(), zap.String("userID", userID), zap.Int8("type", req.Type))
+		return nil, response.CodeReminderInvalidExamFields
+	}
+
+	if req.CourseID != nil {
+		if code := s.checkCourseOwnership(ctx, userID, *req.CourseID); code != response.CodeSuccess {
+			return nil, code
+		}
+	}
+
+	loc, code := s.location(ctx, userID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	reminder := &models.Reminder{
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+		DueDate:     dueDate,
+		DueTime:     dueTime,
+		UserID:      userID,
+		CourseID:    req.CourseID,
+		Type:        req.Type,
+		Status:      statusForDeadline(dueDate, dueTime, loc, time.Now()),
+	}
+	if req.Location != nil {
+		reminder.Location = sql.NullString{String: *req.Location, Valid: true}
+	}
+	if req.Weight != nil {
+		reminder.Weight = sql.NullFloat64{Float64: *req.Weight, Valid: true}
+	}
+	if req.Score != nil {
+		reminder.Score = sql.NullFloat64{Float64: *req.Score, Valid: true}
+	}
+
+	if err := s.reminderRepo.CreateReminder(ctx, reminder); err != nil {
+		global.Log.Error("Error creating reminder", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success creating reminder", zap.String("userID", userID), zap.Int("reminderID", reminder.ID))
+	return reminder, response.CodeSuccess
+}
+
+// GetReminder retrieves a single reminder, refreshing its overdue status first
+func (s *ReminderService) GetReminder(ctx context.Context, userID string, id int) (*models.Reminder, int) {
+	s.refreshOverdue(ctx, userID)
+
+	reminder, err := s.reminderRepo.GetReminderByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrReminderNotFound.Error(), zap.String("userID", userID), zap.Int("reminderID", id))
+			return nil, response.CodeReminderNotFound
+		}
+
+		global.Log.Error("Error getting reminder", zap.Error(err), zap.Int("reminderID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	return reminder, response.CodeSuccess
+}
+
+// ListReminders lists the user's reminders filtered by course, type, status and date range
+func (s *ReminderService) ListReminders(ctx context.Context, userID string, query *models.ReminderQuery) ([]models.Reminder, int) {
+	filter := models.ReminderFilter{
+		UserID:   userID,
+		CourseID: query.CourseID,
+		Type:     query.Type,
+		Status:   query.Status,
+	}
+
+	if query.From != "" {
+		from, err := time.Parse(consts.DATE_LAYOUT, query.From)
+		if err != nil {
+			return nil, response.CodeInvalidParams
+		}
+		filter.From = &from
+	}
+	if query.To != "" {
+		to, err := time.Parse(consts.DATE_LAYOUT, query.To)
+		if err != nil {
+			return nil, response.CodeInvalidParams
+		}
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, response.CodeInvalidParams
+	}
+
+	s.refreshOverdue(ctx, userID)
+
+	reminders, err := s.reminderRepo.ListReminders(ctx, filter)
+	if err != nil {
+		global.Log.Error("Error listing reminders", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	return reminders, response.CodeSuccess
+}
+
+func (s *ReminderService) UpdateReminder(ctx context.Context, userID string, id int, req *models.UpdateReminderRequest) (*models.Reminder, int) {
+	reminder, code := s.GetReminder(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	updates := map[string]any{}
+	if req.Title != nil {
+		updates["title"] = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.CourseID != nil {
+		if code := s.checkCourseOwnership(ctx, userID, *req.CourseID); code != response.CodeSuccess {
+			return nil, code
+		}
+		updates["course_id"] = *req.CourseID
+	}
+
+	reminderType := reminder.Type
+	if req.Type != nil {
+		reminderType = *req.Type
+		updates["type"] = reminderType
+	}
+	if reminderType != consts.ReminderType.EXAM {
+		if req.Location != nil || req.Weight != nil || req.Score != nil {
+			global.Log.Warn(errMessage.ErrReminderExamFieldsOnly.Error(), zap.String("userID", userID), zap.Int("reminderID", id))
+			return nil, response.CodeReminderInvalidExamFields
+		}
+		// Exam details are dropped when a reminder stops being an exam
+		updates["location"] = sql.NullString{}
+		updates["weight"] = sql.NullFloat64{}
+		updates["score"] = sql.NullFloat64{}
+	} else {
+		if req.Location != nil {
+			updates["location"] = sql.NullString{String: *req.Location, Valid: true}
+		}
+		if req.Weight != nil {
+			updates["weight"] = sql.NullFloat64{Float64: *req.Weight, Valid: true}
+		}
+		if req.Score != nil {
+			updates["score"] = sql.NullFloat64{Float64: *req.Score, Valid: true}
+		}
+	}
+
+	// Re-evaluate the deadline when it moves so overdue reminders can become pending again
+	if req.DueDate != nil || req.DueTime != nil {
+		dateStr := reminder.DueDate.Format(consts.DATE_LAYOUT)
+		timeStr := reminder.DueTime
+		if req.DueDate != nil {
+			dateStr = *req.DueDate
+		}
+		if req.DueTime != nil {
+			timeStr = *req.DueTime
+		}
+
+		dueDate, dueTime, err := parseReminderDeadline(dateStr, timeStr)
+		if err != nil {
+			global.Log.Warn(errMessage.ErrInvalidReminderDueDate.Error(), zap.String("due_date", dateStr), zap.String("due_time", timeStr))
+			return nil, response.CodeReminderInvalidDueDate
+		}
+		updates["due_date"] = dueDate
+		updates["due_time"] = dueTime
+
+		if reminder.Status != consts.ReminderStatus.COMPLETED {
+			loc, code := s.location(ctx, userID)
+			if code != response.CodeSuccess {
+				return nil, code
+			}
+			updates["status"] = statusForDeadline(dueDate, dueTime, loc, time.Now())
+		}
+	}
+
+	if err := s.reminderRepo.UpdateReminder(ctx, id, userID, updates); err != nil {
+		global.Log.Error("Error updating reminder", zap.Error(err), zap.Int("reminderID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	// Notifications scheduled for the old deadline are rebuilt by the worker
+	if _, moved := updates["due_date"]; moved {
+		if err := s.notificationRepo.DeleteByReminder(ctx, id); err != nil {
+			global.Log.Warn("Failed to reset reminder notifications", zap.Error(err), zap.Int("reminderID", id))
+		}
+	}
+
+	return s.GetReminder(ctx, userID, id)
+}
+
+// UpdateReminderStatus completes or reopens a reminder. Overdue is only ever set automatically.
+func (s *ReminderService) UpdateReminderStatus(ctx context.Context, userID string, id int, status int8) (*models.Reminder, int) {
+	reminder, code := s.GetReminder(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	updates := map[string]any{}
+	switch status {
+	case consts.ReminderStatus.COMPLETED:
+		if reminder.Status == consts.ReminderStatus.COMPLETED {
+			global.Log.Warn(errMessage.ErrInvalidStatusTransition.Error(), zap.Int("reminderID", id), zap.Int8("from", reminder.Status), zap.Int8("to", status))
+			return nil, response.CodeReminderInvalidTransition
+		}
+		updates["status"] = consts.ReminderStatus.COMPLETED
+		updates["completed_at"] = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	case consts.ReminderStatus.PENDING:
+		if reminder.Status != consts.ReminderStatus.COMPLETED {
+			global.Log.Warn(errMessage.ErrInvalidStatusTransition.Error(), zap.Int("reminderID", id), zap.Int8("from", reminder.Status), zap.Int8("to", status))
+			return nil, response.CodeReminderInvalidTransition
+		}
+		// Reopening a reminder whose deadline already passed lands it straight in overdue
+		loc, code := s.location(ctx, userID)
+		if code != response.CodeSuccess {
+			return nil, code
+		}
+		updates["status"] = statusForDeadline(reminder.DueDate, reminder.DueTime, loc, time.Now())
+		updates["completed_at"] = sql.NullTime{}
+	default:
+		global.Log.Warn(errMessage.ErrInvalidReminderStatus.Error(), zap.Int8("status", status))
+		return nil, response.CodeReminderInvalidStatus
+	}
+
+	if err := s.reminderRepo.UpdateReminder(ctx, id, userID, updates); err != nil {
+		global.Log.Error("Error updating reminder status", zap.Error(err), zap.Int("reminderID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success updating reminder status", zap.Int("reminderID", id), zap.Any("status", updates["status"]))
+	return s.GetReminder(ctx, userID, id)
+}
+
+func (s *ReminderService) DeleteReminder(ctx context.Context, userID string, id int) int {
+	if err := s.reminderRepo.DeleteReminder(ctx, id, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrReminderNotFound.Error(), zap.String("userID", userID), zap.Int("reminderID", id))
+			return response.CodeReminderNotFound
+		}
+
+		global.Log.Error("Error deleting reminder", zap.Error(err), zap.Int("reminderID", id))
+		return response.CodeServerBusy
+	}
+
+	global.Log.Info("Success deleting reminder", zap.String("userID", userID), zap.Int("reminderID", id))
+	return response.CodeSuccess
+}
+
+func (s *ReminderService) checkCourseOwnership(ctx context.Context, userID string, courseID int) int {
+	if _, err := s.courseRepo.GetCourseByID(ctx, courseID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrCourseNotFound.Error(), zap.String("userID", userID), zap.Int("courseID", courseID))
+			return response.CodeCourseNotFound
+		}
+
+		global.Log.Error("Error getting course", zap.Error(err), zap.Int("courseID", courseID))
+		return response.CodeServerBusy
+	}
+	return response.CodeSuccess
+}
+
+func (s *ReminderService) location(ctx context.Context, userID string) (*time.Location, int) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error getting user", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	return userLocation(user), response.CodeSuccess
+}
+
+// refreshOverdue lazily flips the user's passed pending reminders to overdue.
+// Failures are logged only; stale statuses are corrected on the next read.
+func (s *ReminderService) refreshOverdue(ctx context.Context, userID string) {
+	if _, err := s.reminderRepo.MarkOverdueReminders(ctx, userID, time.Now()); err != nil {
+		global.Log.Warn("Failed to refresh overdue reminders", zap.Error(err), zap.String("userID", userID))
+	}
+}
+
+// parseReminderDeadline validates a YYYY-MM-DD date and HH:MM (or HH:MM:SS) time
+// and returns them in the form stored by the reminders table
+func parseReminderDeadline(dateStr, timeStr string) (time.Time, string, error) {
+	dueDate, err := time.Parse(consts.DATE_LAYOUT, dateStr)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+
+	dueTime, err := time.Parse(consts.CLOCK_LAYOUT, timeStr)
+	if err != nil {
+		if dueTime, err = time.Parse(time.TimeOnly, timeStr); err != nil {
+			return time.Time{}, "", err
+		}
+	}
+
+	return dueDate, dueTime.Format(time.TimeOnly), nil
+}
+
+// statusForDeadline reads the deadline in the user's timezone loc
+func statusForDeadline(dueDate time.Time, dueTime string, loc *time.Location, now time.Time) int8 {
+	reminder := models.Reminder{DueDate: dueDate, DueTime: dueTime}
+	if reminder.Deadline(loc).Before(now) {
+		return consts.ReminderStatus.OVERDUE
+	}
+	return consts.ReminderStatus.PENDING
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"github.com/nas03/scholar-ai/backend/pkg/search"
+	"go.uber.org/zap"
+)
+
+type ISearchService interface {
+	Search(ctx context.Context, userID string, query *models.SearchQuery) (*models.SearchResponse, int)
+}
+
+type SearchService struct {
+	searchRepo repo.ISearchRepository
+}
+
+func NewSearchService(searchRepository repo.ISearchRepository) ISearchService {
+	return &SearchService{
+		searchRepo: searchRepository,
+	}
+}
+
+var searchTypes = []string{consts.SearchType.NOTE, consts.SearchType.FILE, consts.SearchType.FLASHCARD, consts.SearchType.COURSE, consts.SearchType.REMINDER}
+
+// Search ranks the user's notes, file text, flashcards, courses and reminders against the
+// query and returns one page of highlighted hits with per-type totals
+func (s *SearchService) Search(ctx context.Context, userID string, query *models.SearchQuery) (*models.SearchResponse, int) {
+	terms := search.Terms(query.Q)
+	if len(terms) == 0 {
+		global.Log.Warn(errMessage.ErrInvalidSearchQuery.Error(), zap.String("q", query.Q))
+		return nil, response.CodeSearchInvalidQuery
+	}
+
+	page, pageSize := query.Page, query.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = consts.SEARCH_DEFAULT_PAGE_SIZE
+	}
+
+	filter := models.SearchFilter{
+		UserID:   userID,
+		Terms:    terms,
+		CourseID: query.CourseID,
+		Tag:      strings.TrimSpace(query.Tag),
+		Limit:    pageSize,
+		Offset:   (page - 1) * pageSize,
+	}
+
+	for _, kind := range strings.Split(query.Types, ",") {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if kind == "" {
+			continue
+		}
+		if !slices.Contains(searchTypes, kind) {
+			global.Log.Warn(errMessage.ErrInvalidSearchType.Error(), zap.String("types", query.Types))
+			return nil, response.CodeSearchInvalidType
+		}
+		if !slices.Contains(filter.Types, kind) {
+			filter.Types = append(filter.Types, kind)
+		}
+	}
+
+	for _, bound := range []struct {
+		value string
+		dest  **time.Time
+	}{{query.From, &filter.From}, {query.To, &filter.To}} {
+		if bound.value == "" {
+			continue
+		}
+		date, err := time.Parse(consts.DATE_LAYOUT, bound.value)
+		if err != nil {
+			global.Log.Warn(errMessage.ErrInvalidSearchDate.Error(), zap.String("date", bound.value))
+			return nil, response.CodeSearchInvalidDate
+		}
+		*bound.dest = &date
+	}
+
+	counts, err := s.searchRepo.Count(ctx, filter)
+	if err != nil {
+		global.Log.Error("Error counting search results", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	result := &models.SearchResponse{
+		Query:    strings.Join(terms, " "),
+		Counts:   counts,
+		Page:     page,
+		PageSize: pageSize,
+		Hits:     []models.SearchHit{},
+	}
+	for _, count := range counts {
+		result.Total += count
+	}
+	if int64(filter.Offset) >= result.Total {
+		return result, response.CodeSuccess
+	}
+
+	rows, err := s.searchRepo.Search(ctx, filter)
+	if err != nil {
+		global.Log.Error("Error searching", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	for _, row := range rows {
+		hit := models.SearchHit{
+			Type:    row.Type,
+			ID:      row.ID,
+			Title:   row.Title,
+			Snippet: search.Highlight(row.Body, terms, consts.SEARCH_SNIPPET_RUNES),
+			Score:   row.Score,
+		}
+		if row.CourseID.Valid {
+			courseID := int(row.CourseID.Int64)
+			hit.CourseID = &courseID
+		}
+		if row.Date.Valid {
+			hit.Date = &row.Date.Time
+		}
+		if row.PageStart.Valid && row.PageEnd.Valid {
+			pageStart, pageEnd := int(row.PageStart.Int64), int(row.PageEnd.Int64)
+			hit.PageStart, hit.PageEnd = &pageStart, &pageEnd
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+
+	return result, response.CodeSuccess
+}
@@ -0,0 +1,637 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/utils"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// IStudyGroupService manages study groups. Only members can see a group;
+// admins manage invitations and members, and only the owner changes roles
+// or deletes the group.
+type IStudyGroupService interface {
+	CreateGroup(ctx context.Context, userID string, req *models.CreateStudyGroupRequest) (*models.StudyGroupDetail, int)
+	ListGroups(ctx context.Context, userID string) ([]models.StudyGroupSummary, int)
+	GetGroup(ctx context.Context, userID string, id int) (*models.StudyGroupDetail, int)
+	UpdateGroup(ctx context.Context, userID string, id int, req *models.UpdateStudyGroupRequest) (*models.StudyGroupDetail, int)
+	DeleteGroup(ctx context.Context, userID string, id int) int
+
+	// CreateInvitation emails an invitation, or creates a join link when no email is given
+	CreateInvitation(ctx context.Context, userID string, id int, req *models.CreateInvitationRequest) (*models.StudyGroupInvitationResponse, int)
+	ListInvitations(ctx context.Context, userID string, id int) ([]models.StudyGroupInvitationResponse, int)
+	RevokeInvitation(ctx context.Context, userID string, id, invitationID int) int
+	Join(ctx context.Context, userID string, req *models.JoinStudyGroupRequest) (*models.StudyGroupDetail, int)
+
+	// Leave removes the user from the group, unsharing their notes
+	Leave(ctx context.Context, userID string, id int) int
+	// RemoveMember kicks a member with a lower role, unsharing their notes
+	RemoveMember(ctx context.Context, userID string, id int, memberID string) int
+	UpdateMemberRole(ctx context.Context, userID string, id int, memberID string, req *models.UpdateMemberRoleRequest) (*models.StudyGroupDetail, int)
+
+	ShareNote(ctx context.Context, userID string, id int, req *models.ShareNoteRequest) ([]models.SharedNote, int)
+	ListSharedNotes(ctx context.Context, userID string, id int) ([]models.SharedNote, int)
+	GetSharedNote(ctx context.Context, userID string, id, noteID int) (*models.SharedNoteDetail, int)
+	// UnshareNote removes a note from the group; its author or an admin can
+	UnshareNote(ctx context.Context, userID string, id, noteID int) int
+}
+
+type StudyGroupService struct {
+	studyGroupRepo repo.IStudyGroupRepository
+	courseRepo     repo.ICourseRepository
+	noteRepo       repo.INoteRepository
+	userRepo       repo.IUserRepository
+	mailRepo       repo.IMailRepository
+	mailHelper     helper.IMailHelper
+	chatBroker     repo.IChatBroker
+}
+
+func NewStudyGroupService(
+	studyGroupRepository repo.IStudyGroupRepository,
+	courseRepository repo.ICourseRepository,
+	noteRepository repo.INoteRepository,
+	userRepository repo.IUserRepository,
+	mailRepository repo.IMailRepository,
+	mailHelper helper.IMailHelper,
+	chatBroker repo.IChatBroker,
+) IStudyGroupService {
+	return &StudyGroupService{
+		studyGroupRepo: studyGroupRepository,
+		courseRepo:     courseRepository,
+		noteRepo:       noteRepository,
+		userRepo:       userRepository,
+		mailRepo:       mailRepository,
+		mailHelper:     mailHelper,
+		chatBroker:     chatBroker,
+	}
+}
+
+func (s *StudyGroupService) CreateGroup(ctx context.Context, userID string, req *models.CreateStudyGroupRequest) (*models.StudyGroupDetail, int) {
+	course, err := s.courseRepo.GetCourseByID(ctx, req.CourseID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrCourseNotFound.Error(), zap.Int("courseID", req.CourseID))
+			return nil, response.CodeCourseNotFound
+		}
+
+		global.Log.Error("Error getting course", zap.Error(err), zap.Int("courseID", req.CourseID))
+		return nil, response.CodeServerBusy
+	}
+
+	group := &models.StudyGroup{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		CourseCode:  normalizeCourseCode(course.CourseID),
+		CourseName:  course.CourseName,
+		OwnerID:     userID,
+		Members: []models.StudyGroupMember{{
+			UserID:   userID,
+			Role:     consts.StudyGroupRole.OWNER,
+			JoinedAt: time.Now().UTC(),
+		}},
+	}
+	if err := s.studyGroupRepo.CreateGroup(ctx, group); err != nil {
+		global.Log.Error("Error creating study group", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success creating study group", zap.Int("groupID", group.ID), zap.String("userID", userID))
+	return s.GetGroup(ctx, userID, group.ID)
+}
+
+func (s *StudyGroupService) ListGroups(ctx context.Context, userID string) ([]models.StudyGroupSummary, int) {
+	groups, err := s.studyGroupRepo.ListGroupsByUser(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error listing study groups", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	if groups == nil {
+		groups = []models.StudyGroupSummary{}
+	}
+	return groups, response.CodeSuccess
+}
+
+func (s *StudyGroupService) GetGroup(ctx context.Context, userID string, id int) (*models.StudyGroupDetail, int) {
+	member, code := s.membership(ctx, userID, id, consts.StudyGroupRole.MEMBER)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	group, err := s.studyGroupRepo.GetGroupByID(ctx, id)
+	if err != nil {
+		global.Log.Error("Error getting study group", zap.Error(err), zap.Int("groupID", id))
+		return nil, response.CodeServerBusy
+	}
+	members, err := s.studyGroupRepo.ListMembers(ctx, id)
+	if err != nil {
+		global.Log.Error("Error listing study group members", zap.Error(err), zap.Int("groupID", id))
+		return nil, response.CodeServerBusy
+	}
+	return &models.StudyGroupDetail{StudyGroup: *group, Role: member.Role, Members: members}, response.CodeSuccess
+}
+
+func (s *StudyGroupService) UpdateGroup(ctx context.Context, userID string, id int, req *models.UpdateStudyGroupRequest) (*models.StudyGroupDetail, int) {
+	if _, code := s.membership(ctx, userID, id, consts.StudyGroupRole.ADMIN); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	updates := map[string]any{}
+	if req.Name != nil {
+		updates["name"] = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if len(updates) > 0 {
+		if err := s.studyGroupRepo.UpdateGroup(ctx, id, updates); err != nil {
+			global.Log.Error("Error updating study group", zap.Error(err), zap.Int("groupID", id))
+			return nil, response.CodeServerBusy
+		}
+	}
+
+	global.Log.Info("Success updating study group", zap.Int("groupID", id))
+	return s.GetGroup(ctx, userID, id)
+}
+
+func (s *StudyGroupService) DeleteGroup(ctx context.Context, userID string, id int) int {
+	if _, code := s.membership(ctx, userID, id, consts.StudyGroupRole.OWNER); code != response.CodeSuccess {
+		return code
+	}
+
+	if err := s.studyGroupRepo.DeleteGroup(ctx, id); err != nil {
+		global.Log.Error("Error deleting study group", zap.Error(err), zap.Int("groupID", id))
+		return response.CodeServerBusy
+	}
+
+	s.disconnectChat(ctx, id, "")
+	global.Log.Info("Success deleting study group", zap.Int("groupID", id), zap.String("userID", userID))
+	return response.CodeSuccess
+}
+
+func (s *StudyGroupService) CreateInvitation(ctx context.Context, userID string, id int, req *models.CreateInvitationRequest) (*models.StudyGroupInvitationResponse, int) {
+	if _, code := s.membership(ctx, userID, id, consts.StudyGroupRole.ADMIN); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	token, err := utils.GenerateToken(consts.STUDY_GROUP_TOKEN_BYTES)
+	if err != nil {
+		global.Log.Error("Error generating invitation token", zap.Error(err))
+		return nil, response.CodeServerBusy
+	}
+
+	days := consts.STUDY_GROUP_INVITE_DEFAULT_DAYS
+	if req.ExpiresInDays > 0 {
+		days = min(req.ExpiresInDays, consts.STUDY_GROUP_INVITE_MAX_DAYS)
+	}
+	invitation := &models.StudyGroupInvitation{
+		GroupID:   id,
+		Token:     token,
+		InvitedBy: userID,
+		ExpiresAt: time.Now().UTC().AddDate(0, 0, days).Truncate(time.Second),
+	}
+	if req.Email != nil {
+		invitation.Email = sql.NullString{String: strings.ToLower(strings.TrimSpace(*req.Email)), Valid: true}
+	}
+	if err := s.studyGroupRepo.CreateInvitation(ctx, invitation); err != nil {
+		global.Log.Error("Error creating invitation", zap.Error(err), zap.Int("groupID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	result := invitationResponse(*invitation)
+	if invitation.Email.Valid {
+		// The invitation stands when the mail fails; the link can still be shared by hand
+		if err := s.sendInvitation(ctx, userID, id, &result); err != nil {
+			global.Log.Warn("Failed to send study group invitation", zap.Error(err), zap.Int("invitationID", invitation.ID))
+		} else {
+			result.EmailSent = true
+		}
+	}
+
+	global.Log.Info("Success creating invitation", zap.Int("groupID", id), zap.Int("invitationID", invitation.ID), zap.Bool("email", invitation.Email.Valid))
+	return &result, response.CodeSuccess
+}
+
+func (s *StudyGroupService) ListInvitations(ctx context.Context, userID string, id int) ([]models.StudyGroupInvitationResponse, int) {
+	if _, code := s.membership(ctx, userID, id, consts.StudyGroupRole.ADMIN); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	invitations, err := s.studyGroupRepo.ListPendingInvitations(ctx, id, time.Now().UTC())
+	if err != nil {
+		global.Log.Error("Error listing invitations", zap.Error(err), zap.Int("groupID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	result := make([]models.StudyGroupInvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		result = append(result, invitationResponse(invitation))
+	}
+	return result, response.CodeSuccess
+}
+
+func (s *StudyGroupService) RevokeInvitation(ctx context.Context, userID string, id, invitationID int) int {
+	if _, code := s.membership(ctx, userID, id, consts.StudyGroupRole.ADMIN); code != response.CodeSuccess {
+		return code
+	}
+
+	if err := s.studyGroupRepo.DeleteInvitation(ctx, invitationID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrInvitationNotFound.Error(), zap.Int("invitationID", invitationID), zap.Int("groupID", id))
+			return response.CodeInvitationNotFound
+		}
+
+		global.Log.Error("Error revoking invitation", zap.Error(err), zap.Int("invitationID", invitationID))
+		return response.CodeServerBusy
+	}
+
+	global.Log.Info("Success revoking invitation", zap.Int("invitationID", invitationID), zap.Int("groupID", id))
+	return response.CodeSuccess
+}
+
+func (s *StudyGroupService) Join(ctx context.Context, userID string, req *models.JoinStudyGroupRequest) (*models.StudyGroupDetail, int) {
+	invitation, err := s.studyGroupRepo.GetInvitationByToken(ctx, req.Token)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		global.Log.Error("Error getting invitation", zap.Error(err))
+		return nil, response.CodeServerBusy
+	}
+	now := time.Now().UTC()
+	if invitation == nil || invitation.AcceptedAt.Valid || !invitation.ExpiresAt.After(now) {
+		global.Log.Warn(errMessage.ErrInvitationInvalid.Error(), zap.String("userID", userID))
+		return nil, response.CodeInvitationInvalid
+	}
+
+	if invitation.Email.Valid {
+		user, err := s.userRepo.GetUserByID(ctx, userID)
+		if err != nil {
+			global.Log.Error("Error getting user", zap.Error(err), zap.String("userID", userID))
+			return nil, response.CodeServerBusy
+		}
+		if !strings.EqualFold(user.Email, invitation.Email.String) {
+			global.Log.Warn(errMessage.ErrInvitationEmailMismatch.Error(), zap.Int("invitationID", invitation.ID), zap.String("userID", userID))
+			return nil, response.CodeInvitationEmailMismatch
+		}
+	}
+
+	groupID := invitation.GroupID
+	if _, err := s.studyGroupRepo.GetMember(ctx, groupID, userID); err == nil {
+		global.Log.Warn(errMessage.ErrAlreadyGroupMember.Error(), zap.Int("groupID", groupID), zap.String("userID", userID))
+		return nil, response.CodeAlreadyGroupMember
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		global.Log.Error("Error getting study group member", zap.Error(err), zap.Int("groupID", groupID))
+		return nil, response.CodeServerBusy
+	}
+
+	members, err := s.studyGroupRepo.CountMembers(ctx, groupID)
+	if err != nil {
+		global.Log.Error("Error counting study group members", zap.Error(err), zap.Int("groupID", groupID))
+		return nil, response.CodeServerBusy
+	}
+	if members >= int64(consts.STUDY_GROUP_MAX_MEMBERS) {
+		global.Log.Warn(errMessage.ErrStudyGroupFull.Error(), zap.Int("groupID", groupID))
+		return nil, response.CodeStudyGroupFull
+	}
+
+	err = s.studyGroupRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		studyGroupRepo := s.studyGroupRepo.WithTx(tx)
+		member := &models.StudyGroupMember{GroupID: groupID, UserID: userID, Role: consts.StudyGroupRole.MEMBER, JoinedAt: now}
+		if err := studyGroupRepo.AddMember(ctx, member); err != nil {
+			return err
+		}
+		// Email invitations are single use; join links stay valid until they expire
+		if invitation.Email.Valid {
+			return studyGroupRepo.UpdateInvitation(ctx, invitation.ID, map[string]any{"accepted_at": now})
+		}
+		return nil
+	})
+	if err != nil {
+		global.Log.Error("Error joining study group", zap.Error(err), zap.Int("groupID", groupID), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success joining study group", zap.Int("groupID", groupID), zap.Int("invitationID", invitation.ID), zap.String("userID", userID))
+	return s.GetGroup(ctx, userID, groupID)
+}
+
+func (s *StudyGroupService) Leave(ctx context.Context, userID string, id int) int {
+	member, code := s.membership(ctx, userID, id, consts.StudyGroupRole.MEMBER)
+	if code != response.CodeSuccess {
+		return code
+	}
+	if member.Role == consts.StudyGroupRole.OWNER {
+		global.Log.Warn(errMessage.ErrGroupOwnerCannotLeave.Error(), zap.Int("groupID", id), zap.String("userID", userID))
+		return response.CodeGroupOwnerCannotLeave
+	}
+
+	if err := s.removeMember(ctx, id, userID); err != nil {
+		global.Log.Error("Error leaving study group", zap.Error(err), zap.Int("groupID", id), zap.String("userID", userID))
+		return response.CodeServerBusy
+	}
+
+	s.disconnectChat(ctx, id, userID)
+	global.Log.Info("Success leaving study group", zap.Int("groupID", id), zap.String("userID", userID))
+	return response.CodeSuccess
+}
+
+func (s *StudyGroupService) RemoveMember(ctx context.Context, userID string, id int, memberID string) int {
+	actor, code := s.membership(ctx, userID, id, consts.StudyGroupRole.ADMIN)
+	if code != response.CodeSuccess {
+		return code
+	}
+	if memberID == userID {
+		global.Log.Warn(errMessage.ErrStudyGroupForbidden.Error(), zap.Int("groupID", id), zap.String("userID", userID))
+		return response.CodeStudyGroupForbidden
+	}
+
+	target, code := s.member(ctx, id, memberID)
+	if code != response.CodeSuccess {
+		return code
+	}
+	// Admins can remove members, the owner can also remove admins
+	if target.Role >= actor.Role {
+		global.Log.Warn(errMessage.ErrStudyGroupForbidden.Error(), zap.Int("groupID", id), zap.String("userID", userID), zap.String("memberID", memberID))
+		return response.CodeStudyGroupForbidden
+	}
+
+	if err := s.removeMember(ctx, id, memberID); err != nil {
+		global.Log.Error("Error removing study group member", zap.Error(err), zap.Int("groupID", id), zap.String("memberID", memberID))
+		return response.CodeServerBusy
+	}
+
+	s.disconnectChat(ctx, id, memberID)
+	global.Log.Info("Success removing study group member", zap.Int("groupID", id), zap.String("memberID", memberID), zap.String("userID", userID))
+	return response.CodeSuccess
+}
+
+func (s *StudyGroupService) UpdateMemberRole(ctx context.Context, userID string, id int, memberID string, req *models.UpdateMemberRoleRequest) (*models.StudyGroupDetail, int) {
+	if _, code := s.membership(ctx, userID, id, consts.StudyGroupRole.OWNER); code != response.CodeSuccess {
+		return nil, code
+	}
+	if memberID == userID {
+		global.Log.Warn(errMessage.ErrStudyGroupForbidden.Error(), zap.Int("groupID", id), zap.String("userID", userID))
+		return nil, response.CodeStudyGroupForbidden
+	}
+	if _, code := s.member(ctx, id, memberID); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	role := *req.Role
+	err := s.studyGroupRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		studyGroupRepo := s.studyGroupRepo.WithTx(tx)
+		if err := studyGroupRepo.UpdateMemberRole(ctx, id, memberID, role); err != nil {
+			return err
+		}
+		if role != consts.StudyGroupRole.OWNER {
+			return nil
+		}
+		// Handing over the group keeps the previous owner on as admin
+		if err := studyGroupRepo.UpdateMemberRole(ctx, id, userID, consts.StudyGroupRole.ADMIN); err != nil {
+			return err
+		}
+		return studyGroupRepo.UpdateGroup(ctx, id, map[string]any{"owner_id": memberID})
+	})
+	if err != nil {
+		global.Log.Error("Error updating study group role", zap.Error(err), zap.Int("groupID", id), zap.String("memberID", memberID))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success updating study group role", zap.Int("groupID", id), zap.String("memberID", memberID), zap.Int8("role", role))
+	return s.GetGroup(ctx, userID, id)
+}
+
+func (s *StudyGroupService) ShareNote(ctx context.Context, userID string, id int, req *models.ShareNoteRequest) ([]models.SharedNote, int) {
+	if _, code := s.membership(ctx, userID, id, consts.StudyGroupRole.MEMBER); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	note, err := s.noteRepo.GetNoteByID(ctx, req.NoteID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrNoteNotFound.Error(), zap.Int("noteID", req.NoteID), zap.String("userID", userID))
+			return nil, response.CodeNoteNotFound
+		}
+
+		global.Log.Error("Error getting note", zap.Error(err), zap.Int("noteID", req.NoteID))
+		return nil, response.CodeServerBusy
+	}
+
+	group, err := s.studyGroupRepo.GetGroupByID(ctx, id)
+	if err != nil {
+		global.Log.Error("Error getting study group", zap.Error(err), zap.Int("groupID", id))
+		return nil, response.CodeServerBusy
+	}
+	if note.Course == nil || normalizeCourseCode(note.Course.CourseID) != group.CourseCode {
+		global.Log.Warn(errMessage.ErrGroupNoteCourseMismatch.Error(), zap.Int("noteID", note.ID), zap.Int("groupID", id))
+		return nil, response.CodeGroupNoteCourseMismatch
+	}
+
+	if _, err := s.studyGroupRepo.GetSharedNote(ctx, id, note.ID); err == nil {
+		global.Log.Warn(errMessage.ErrGroupNoteAlreadyShared.Error(), zap.Int("noteID", note.ID), zap.Int("groupID", id))
+		return nil, response.CodeGroupNoteAlreadyShared
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		global.Log.Error("Error getting shared note", zap.Error(err), zap.Int("noteID", note.ID))
+		return nil, response.CodeServerBusy
+	}
+
+	shared := &models.StudyGroupNote{GroupID: id, NoteID: note.ID, SharedBy: userID, SharedAt: time.Now().UTC()}
+	if err := s.studyGroupRepo.ShareNote(ctx, shared); err != nil {
+		global.Log.Error("Error sharing note", zap.Error(err), zap.Int("noteID", note.ID), zap.Int("groupID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success sharing note", zap.Int("noteID", note.ID), zap.Int("groupID", id), zap.String("userID", userID))
+	return s.ListSharedNotes(ctx, userID, id)
+}
+
+func (s *StudyGroupService) ListSharedNotes(ctx context.Context, userID string, id int) ([]models.SharedNote, int) {
+	if _, code := s.membership(ctx, userID, id, consts.StudyGroupRole.MEMBER); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	notes, err := s.studyGroupRepo.ListSharedNotes(ctx, id)
+	if err != nil {
+		global.Log.Error("Error listing shared notes", zap.Error(err), zap.Int("groupID", id))
+		return nil, response.CodeServerBusy
+	}
+	if notes == nil {
+		notes = []models.SharedNote{}
+	}
+	return notes, response.CodeSuccess
+}
+
+func (s *StudyGroupService) GetSharedNote(ctx context.Context, userID string, id, noteID int) (*models.SharedNoteDetail, int) {
+	if _, code := s.membership(ctx, userID, id, consts.StudyGroupRole.MEMBER); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	shared, code := s.sharedNote(ctx, id, noteID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	detail := &models.SharedNoteDetail{Note: *shared.Note, SharedAt: shared.SharedAt}
+	if author, err := s.userRepo.GetUserByID(ctx, shared.Note.UserID); err == nil {
+		detail.AuthorName = author.Username
+	}
+	return detail, response.CodeSuccess
+}
+
+func (s *StudyGroupService) UnshareNote(ctx context.Context, userID string, id, noteID int) int {
+	member, code := s.membership(ctx, userID, id, consts.StudyGroupRole.MEMBER)
+	if code != response.CodeSuccess {
+		return code
+	}
+
+	shared, code := s.sharedNote(ctx, id, noteID)
+	if code != response.CodeSuccess {
+		return code
+	}
+	if shared.SharedBy != userID && member.Role < consts.StudyGroupRole.ADMIN {
+		global.Log.Warn(errMessage.ErrStudyGroupForbidden.Error(), zap.Int("groupID", id), zap.String("userID", userID), zap.Int("noteID", noteID))
+		return response.CodeStudyGroupForbidden
+	}
+
+	if err := s.studyGroupRepo.UnshareNote(ctx, id, noteID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		global.Log.Error("Error unsharing note", zap.Error(err), zap.Int("noteID", noteID), zap.Int("groupID", id))
+		return response.CodeServerBusy
+	}
+
+	global.Log.Info("Success unsharing note", zap.Int("noteID", noteID), zap.Int("groupID", id), zap.String("userID", userID))
+	return response.CodeSuccess
+}
+
+// membership loads the user's membership and checks it has at least the given
+// role. Non-members get CodeStudyGroupNotFound so groups stay private.
+func (s *StudyGroupService) membership(ctx context.Context, userID string, groupID int, role int8) (*models.StudyGroupMember, int) {
+	member, err := s.studyGroupRepo.GetMember(ctx, groupID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrStudyGroupNotFound.Error(), zap.Int("groupID", groupID), zap.String("userID", userID))
+			return nil, response.CodeStudyGroupNotFound
+		}
+
+		global.Log.Error("Error getting study group member", zap.Error(err), zap.Int("groupID", groupID))
+		return nil, response.CodeServerBusy
+	}
+
+	if member.Role < role {
+		global.Log.Warn(errMessage.ErrStudyGroupForbidden.Error(), zap.Int("groupID", groupID), zap.String("userID", userID), zap.Int8("role", member.Role))
+		return nil, response.CodeStudyGroupForbidden
+	}
+	return member, response.CodeSuccess
+}
+
+// member loads another member of the group
+func (s *StudyGroupService) member(ctx context.Context, groupID int, memberID string) (*models.StudyGroupMember, int) {
+	member, err := s.studyGroupRepo.GetMember(ctx, groupID, memberID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrGroupMemberNotFound.Error(), zap.Int("groupID", groupID), zap.String("memberID", memberID))
+			return nil, response.CodeGroupMemberNotFound
+		}
+
+		global.Log.Error("Error getting study group member", zap.Error(err), zap.Int("groupID", groupID))
+		return nil, response.CodeServerBusy
+	}
+	return member, response.CodeSuccess
+}
+
+func (s *StudyGroupService) sharedNote(ctx context.Context, groupID, noteID int) (*models.StudyGroupNote, int) {
+	shared, err := s.studyGroupRepo.GetSharedNote(ctx, groupID, noteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrGroupNoteNotFound.Error(), zap.Int("groupID", groupID), zap.Int("noteID", noteID))
+			return nil, response.CodeGroupNoteNotFound
+		}
+
+		global.Log.Error("Error getting shared note", zap.Error(err), zap.Int("groupID", groupID), zap.Int("noteID", noteID))
+		return nil, response.CodeServerBusy
+	}
+	return shared, response.CodeSuccess
+}
+
+// removeMember drops the membership together with the notes the member shared
+func (s *StudyGroupService) removeMember(ctx context.Context, groupID int, userID string) error {
+	return s.studyGroupRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		studyGroupRepo := s.studyGroupRepo.WithTx(tx)
+		if err := studyGroupRepo.UnshareNotesBy(ctx, groupID, userID); err != nil {
+			return err
+		}
+		return studyGroupRepo.RemoveMember(ctx, groupID, userID)
+	})
+}
+
+// disconnectChat closes the chat connections of a member who is no longer in
+// the group, or of everyone when userID is empty
+func (s *StudyGroupService) disconnectChat(ctx context.Context, groupID int, userID string) {
+	if s.chatBroker == nil {
+		return
+	}
+	event := models.ChatEvent{Type: consts.ChatEventType.MEMBER_REMOVED, GroupID: groupID, UserID: userID}
+	if err := s.chatBroker.Publish(ctx, groupID, event); err != nil {
+		global.Log.Error("Error publishing chat member removal", zap.Error(err), zap.Int("groupID", groupID), zap.String("userID", userID))
+	}
+}
+
+func (s *StudyGroupService) sendInvitation(ctx context.Context, inviterID string, groupID int, invitation *models.StudyGroupInvitationResponse) error {
+	inviter, err := s.userRepo.GetUserByID(ctx, inviterID)
+	if err != nil {
+		return err
+	}
+	group, err := s.studyGroupRepo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		return err
+	}
+
+	data := models.StudyGroupInvitationMail{
+		Inviter:   inviter.Username,
+		Group:     group.Name,
+		Course:    fmt.Sprintf("%s - %s", group.CourseCode, group.CourseName),
+		JoinURL:   invitation.JoinURL,
+		ExpiresAt: invitation.ExpiresAt.Format("Mon, 02 Jan 2006 15:04"),
+	}
+
+	// The names are chosen by users, so the HTML body only gets them escaped;
+	// the subject is plain text
+	escaped := models.StudyGroupInvitationMail{
+		Inviter:   html.EscapeString(data.Inviter),
+		Group:     html.EscapeString(data.Group),
+		Course:    html.EscapeString(data.Course),
+		JoinURL:   html.EscapeString(data.JoinURL),
+		ExpiresAt: html.EscapeString(data.ExpiresAt),
+	}
+
+	subject := fmt.Sprintf("%s invited you to %s", data.Inviter, data.Group)
+	body := consts.STUDY_GROUP_INVITATION_FALLBACK_HTML
+	if template, err := s.mailRepo.GetMailTemplate(ctx, consts.STUDY_GROUP_INVITATION_MAIL); err == nil {
+		subject = s.mailHelper.ReplaceParameters(ctx, template.Subject, data)
+		body = template.Body
+	} else {
+		global.Log.Warn("Study group invitation mail template missing, using fallback", zap.Int("mail_id", consts.STUDY_GROUP_INVITATION_MAIL), zap.Error(err))
+	}
+
+	_, err = s.mailHelper.SendMail(ctx, invitation.Email.String, subject, s.mailHelper.ReplaceParameters(ctx, body, escaped))
+	return err
+}
+
+func invitationResponse(invitation models.StudyGroupInvitation) models.StudyGroupInvitationResponse {
+	baseURL := strings.TrimSuffix(global.Config.StudyGroup.JoinBaseURL, "/")
+	return models.StudyGroupInvitationResponse{
+		StudyGroupInvitation: invitation,
+		JoinURL:              fmt.Sprintf("%s/%s", baseURL, url.PathEscape(invitation.Token)),
+	}
+}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// IStudySessionService tracks the time users spend studying and reports it.
+// A user has at most one running session; finished ones can also be logged
+// after the fact as long as they don't overlap.
+type IStudySessionService interface {
+	StartSession(ctx context.Context, userID string, req *models.StartStudySessionRequest) (*models.StudySession, int)
+	// StopSession ends a running session; sessions are capped at consts.STUDY_SESSION_MAX_DURATION
+	StopSession(ctx context.Context, userID string, id int, req *models.StopStudySessionRequest) (*models.StudySession, int)
+	LogSession(ctx context.Context, userID string, req *models.LogStudySessionRequest) (*models.StudySession, int)
+	ListSessions(ctx context.Context, userID string, query *models.StudySessionQuery) ([]models.StudySession, int)
+	DeleteSession(ctx context.Context, userID string, id int) int
+
+	// GetStudyTime reports study time per course and week against the
+	// accepted study plans, streaks and the correlation with grades
+	GetStudyTime(ctx context.Context, userID string, query *models.StudyTimeQuery) (*models.StudyTimeAnalytics, int)
+}
+
+type StudySessionService struct {
+	sessionRepo repo.IStudySessionRepository
+	courseRepo  repo.ICourseRepository
+	noteRepo    repo.INoteRepository
+	userRepo    repo.IUserRepository
+}
+
+func NewStudySessionService(sessionRepository repo.IStudySessionRepository, courseRepository repo.ICourseRepository, noteRepository repo.INoteRepository,
+	userRepository repo.IUserRepository) IStudySessionService {
+	return &StudySessionService{
+		sessionRepo: sessionRepository,
+		courseRepo:  courseRepository,
+		noteRepo:    noteRepository,
+		userRepo:    userRepository,
+	}
+}
+
+func (s *StudySessionService) StartSession(ctx context.Context, userID string, req *models.StartStudySessionRequest) (*models.StudySession, int) {
+	if code := s.checkSubject(ctx, userID, req.CourseID, req.NoteID); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	session := &models.StudySession{
+		UserID:    userID,
+		CourseID:  req.CourseID,
+		NoteID:    req.NoteID,
+		StartedAt: time.Now().UTC(),
+	}
+	err := s.sessionRepo.WithTransaction(ctx, func(tx *gorm.DB) error {
+		sessionRepo := s.sessionRepo.WithTx(tx)
+
+		_, err := sessionRepo.GetRunningSession(ctx, userID)
+		if err == nil {
+			return errMessage.ErrStudySessionRunning
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return sessionRepo.CreateSession(ctx, session)
+	})
+	if err != nil {
+		if errors.Is(err, errMessage.ErrStudySessionRunning) {
+			global.Log.Warn(errMessage.ErrStudySessionRunning.Error(), zap.String("userID", userID))
+			return nil, response.CodeStudySessionRunning
+		}
+
+		global.Log.Error("Error starting study session", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success starting study session", zap.Int("sessionID", session.ID), zap.String("userID", userID))
+	return session, response.CodeSuccess
+}
+
+func (s *StudySessionService) StopSession(ctx context.Context, userID string, id int, req *models.StopStudySessionRequest) (*models.StudySession, int) {
+	session, code := s.getSession(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	if session.EndedAt.Valid {
+		global.Log.Warn(errMessage.ErrStudySessionNotRunning.Error(), zap.Int("sessionID", id))
+		return nil, response.CodeStudySessionNotRunning
+	}
+
+	end := time.Now().UTC()
+	if end.Sub(session.StartedAt) > consts.STUDY_SESSION_MAX_DURATION {
+		end = session.StartedAt.Add(consts.STUDY_SESSION_MAX_DURATION)
+	}
+	session.EndedAt = sql.NullTime{Time: end, Valid: true}
+	session.DurationSeconds = int(end.Sub(session.StartedAt).Seconds())
+	if req.Pomodoros != nil {
+		session.Pomodoros = *req.Pomodoros
+	}
+
+	updates := map[string]any{
+		"ended_at":         session.EndedAt,
+		"duration_seconds": session.DurationSeconds,
+		"pomodoros":        session.Pomodoros,
+	}
+	if err := s.sessionRepo.UpdateSession(ctx, id, userID, updates); err != nil {
+		global.Log.Error("Error stopping study session", zap.Error(err), zap.Int("sessionID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success stopping study session", zap.Int("sessionID", id), zap.Int("seconds", session.DurationSeconds))
+	return session, response.CodeSuccess
+}
+
+func (s *StudySessionService) LogSession(ctx context.Context, userID string, req *models.LogStudySessionRequest) (*models.StudySession, int) {
+	start, end := req.StartedAt.UTC(), req.EndedAt.UTC()
+	if !start.Before(end) || end.After(time.Now().UTC()) || end.Sub(start) > consts.STUDY_SESSION_MAX_DURATION {
+		global.Log.Warn(errMessage.ErrInvalidStudySessionTime.Error(), zap.Time("start", start), zap.Time("end", end))
+		return nil, response.CodeInvalidStudySessionTime
+	}
+	if code := s.checkSubject(ctx, userID, req.CourseID, req.NoteID); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	overlapping, err := s.sessionRepo.CountOverlapping(ctx, userID, start, end)
+	if err != nil {
+		global.Log.Error("Error checking study session overlap", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	if overlapping > 0 {
+		global.Log.Warn(errMessage.ErrStudySessionOverlap.Error(), zap.Time("start", start), zap.Time("end", end))
+		return nil, response.CodeStudySessionOverlap
+	}
+
+	session := &models.StudySession{
+		UserID:          userID,
+		CourseID:        req.CourseID,
+		NoteID:          req.NoteID,
+		StartedAt:       start,
+		EndedAt:         sql.NullTime{Time: end, Valid: true},
+		DurationSeconds: int(end.Sub(start).Seconds()),
+		Pomodoros:       req.Pomodoros,
+	}
+	if err := s.sessionRepo.CreateSession(ctx, session); err != nil {
+		global.Log.Error("Error logging study session", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success logging study session", zap.Int("sessionID", session.ID), zap.String("userID", userID))
+	return session, response.CodeSuccess
+}
+
+func (s *StudySessionService) ListSessions(ctx context.Context, userID string, query *models.StudySessionQuery) ([]models.StudySession, int) {
+	filter := models.StudySessionFilter{UserID: userID, CourseID: query.CourseID}
+	if query.From != "" || query.To != "" {
+		loc, code := s.location(ctx, userID)
+		if code != response.CodeSuccess {
+			return nil, code
+		}
+		if query.From != "" {
+			from, err := time.ParseInLocation(consts.DATE_LAYOUT, query.From, loc)
+			if err != nil {
+				global.Log.Warn(errMessage.ErrInvalidStudyTimeRange.Error(), zap.String("from", query.From))
+				return nil, response.CodeInvalidStudyTimeRange
+			}
+			from = from.UTC()
+			filter.From = &from
+		}
+		if query.To != "" {
+			to, err := time.ParseInLocation(consts.DATE_LAYOUT, query.To, loc)
+			if err != nil {
+				global.Log.Warn(errMessage.ErrInvalidStudyTimeRange.Error(), zap.String("to", query.To))
+				return nil, response.CodeInvalidStudyTimeRange
+			}
+			to = to.AddDate(0, 0, 1).UTC()
+			filter.To = &to
+		}
+	}
+
+	sessions, err := s.sessionRepo.ListSessions(ctx, filter)
+	if err != nil {
+		global.Log.Error("Error listing study sessions", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	return sessions, response.CodeSuccess
+}
+
+func (s *StudySessionService) DeleteSession(ctx context.Context, userID string, id int) int {
+	if err := s.sessionRepo.DeleteSession(ctx, id, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrStudySessionNotFound.Error(), zap.Int("sessionID", id))
+			return response.CodeStudySessionNotFound
+		}
+
+		global.Log.Error("Error deleting study session", zap.Error(err), zap.Int("sessionID", id))
+		return response.CodeServerBusy
+	}
+	return response.CodeSuccess
+}
+
+func (s *StudySessionService) GetStudyTime(ctx context.Context, userID string, query *models.StudyTimeQuery) (*models.StudyTimeAnalytics, int) {
+	loc, code := s.location(ctx, userID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	to, from := today, today.AddDate(0, 0, 1-consts.STUDY_TIME_DEFAULT_RANGE_DAYS)
+	var err error
+	if query.To != "" {
+		if to, err = time.ParseInLocation(consts.DATE_LAYOUT, query.To, loc); err != nil {
+			global.Log.Warn(errMessage.ErrInvalidStudyTimeRange.Error(), zap.String("to", query.To))
+			return nil, response.CodeInvalidStudyTimeRange
+		}
+		from = to.AddDate(0, 0, 1-consts.STUDY_TIME_DEFAULT_RANGE_DAYS)
+	}
+	if query.From != "" {
+		if from, err = time.ParseInLocation(consts.DATE_LAYOUT, query.From, loc); err != nil {
+			global.Log.Warn(errMessage.ErrInvalidStudyTimeRange.Error(), zap.String("from", query.From))
+			return nil, response.CodeInvalidStudyTimeRange
+		}
+	}
+	if to.Before(from) || to.Sub(from).Hours()/24 >= float64(consts.STUDY_TIME_MAX_RANGE_DAYS) {
+		global.Log.Warn(errMessage.ErrInvalidStudyTimeRange.Error(), zap.Time("from", from), zap.Time("to", to))
+		return nil, response.CodeInvalidStudyTimeRange
+	}
+	start, end := from.UTC(), to.AddDate(0, 0, 1).UTC()
+	offset := utcOffset(now)
+
+	analytics := &models.StudyTimeAnalytics{
+		From: from.Format(consts.DATE_LAYOUT),
+		To:   to.Format(consts.DATE_LAYOUT),
+	}
+	if analytics.Weeks, err = s.sessionRepo.WeeklyStudyTime(ctx, userID, offset, start, end); err != nil {
+		global.Log.Error("Error aggregating weekly study time", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	streaks, err := s.sessionRepo.StudyStreaks(ctx, userID, offset)
+	if err != nil {
+		global.Log.Error("Error aggregating study streaks", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	if analytics.Grades, err = s.sessionRepo.CourseGradeStudyTime(ctx, userID, start, end); err != nil {
+		global.Log.Error("Error aggregating study time per course", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	correlation, err := s.sessionRepo.StudyGradeCorrelation(ctx, userID, start, end)
+	if err != nil {
+		global.Log.Error("Error correlating study time with grades", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	if analytics.Weeks == nil {
+		analytics.Weeks = []models.CourseWeekStudyTime{}
+	}
+	if analytics.Grades == nil {
+		analytics.Grades = []models.CourseGradeStudyTime{}
+	}
+	for _, week := range analytics.Weeks {
+		analytics.StudyMinutes += week.StudyMinutes
+		analytics.PlannedMinutes += week.PlannedMinutes
+	}
+	analytics.Streak = summarizeStreaks(streaks, now)
+	analytics.Correlation = *correlation
+	if correlation.Courses < int64(consts.STUDY_CORRELATION_MIN_COURSES) {
+		analytics.Correlation.Coefficient = nil
+	}
+	return analytics, response.CodeSuccess
+}
+
+// checkSubject verifies the course, and the note when given, belong to the user
+func (s *StudySessionService) checkSubject(ctx context.Context, userID string, courseID int, noteID *int) int {
+	if _, err := s.courseRepo.GetCourseByID(ctx, courseID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrCourseNotFound.Error(), zap.Int("courseID", courseID))
+			return response.CodeCourseNotFound
+		}
+
+		global.Log.Error("Error getting course", zap.Error(err), zap.Int("courseID", courseID))
+		return response.CodeServerBusy
+	}
+	if noteID == nil {
+		return response.CodeSuccess
+	}
+
+	note, err := s.noteRepo.GetNoteByID(ctx, *noteID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrNoteNotFound.Error(), zap.Int("noteID", *noteID))
+			return response.CodeNoteNotFound
+		}
+
+		global.Log.Error("Error getting note", zap.Error(err), zap.Int("noteID", *noteID))
+		return response.CodeServerBusy
+	}
+	// A note of another course would credit the wrong course's study time
+	if note.CourseID != courseID {
+		global.Log.Warn(errMessage.ErrNoteNotFound.Error(), zap.Int("noteID", *noteID), zap.Int("courseID", courseID))
+		return response.CodeNoteNotFound
+	}
+	return response.CodeSuccess
+}
+
+func (s *StudySessionService) getSession(ctx context.Context, userID string, id int) (*models.StudySession, int) {
+	session, err := s.sessionRepo.GetSessionByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrStudySessionNotFound.Error(), zap.Int("sessionID", id))
+			return nil, response.CodeStudySessionNotFound
+		}
+
+		global.Log.Error("Error getting study session", zap.Error(err), zap.Int("sessionID", id))
+		return nil, response.CodeServerBusy
+	}
+	return session, response.CodeSuccess
+}
+
+func (s *StudySessionService) location(ctx context.Context, userID string) (*time.Location, int) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		global.Log.Error("Error getting user", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+	return userLocation(user), response.CodeSuccess
+}
+
+// summarizeStreaks picks the longest streak and the current one, which is
+// still alive when its last day is today or yesterday
+func summarizeStreaks(streaks []models.StudyStreak, now time.Time) models.StudyStreakSummary {
+	var summary models.StudyStreakSummary
+	for i := range streaks {
+		if summary.Longest == nil || streaks[i].Days > summary.Longest.Days {
+			summary.Longest = &streaks[i]
+		}
+	}
+
+	// Streak dates are local calendar days scanned as UTC midnights
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if len(streaks) > 0 && !streaks[0].EndDate.Before(today.AddDate(0, 0, -1)) {
+		summary.Current = streaks[0].Days
+	}
+	return summary
+}
+
+// utcOffset formats the UTC offset in effect at t for CONVERT_TZ. Sessions
+// across a daylight saving change are bucketed with the current offset.
+func utcOffset(t time.Time) string {
+	_, seconds := t.Zone()
+	sign := '+'
+	if seconds < 0 {
+		sign, seconds = '-', -seconds
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, seconds/3600, seconds%3600/60)
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/pkg/ai"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/extract"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ISummarizationService runs in the worker and generates note summaries
+type ISummarizationService interface {
+	// SummarizeNote returns an error only for failures worth retrying; notes
+	// the model cannot summarize are recorded on the summary row instead
+	SummarizeNote(ctx context.Context, payload models.NoteSummarizePayload) error
+}
+
+type SummarizationService struct {
+	summaryRepo   repo.ISummaryRepository
+	noteRepo      repo.INoteRepository
+	provider      ai.Provider
+	progressStore repo.IProgressStore
+	usageRepo     repo.IUsageRepository
+}
+
+func NewSummarizationService(summaryRepository repo.ISummaryRepository, noteRepository repo.INoteRepository, provider ai.Provider,
+	progressStore repo.IProgressStore, usageRepository repo.IUsageRepository) ISummarizationService {
+	return &SummarizationService{
+		summaryRepo:   summaryRepository,
+		noteRepo:      noteRepository,
+		provider:      provider,
+		progressStore: progressStore,
+		usageRepo:     usageRepository,
+	}
+}
+
+func (s *SummarizationService) SummarizeNote(ctx context.Context, payload models.NoteSummarizePayload) error {
+	summary, err := s.summaryRepo.GetSummaryByID(ctx, payload.SummaryID, payload.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Deleted with its note since the job was enqueued
+			global.Log.Warn(errMessage.ErrSummaryNotFound.Error(), zap.Int("summaryID", payload.SummaryID))
+			releaseUsage(ctx, s.usageRepo, payload.ReservationID)
+			return nil
+		}
+		return fmt.Errorf("get summary %d: %w", payload.SummaryID, err)
+	}
+	if summary.Status == consts.SummaryStatus.DONE {
+		return nil
+	}
+
+	note, err := s.noteRepo.GetNoteByID(ctx, summary.NoteID, summary.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrNoteNotFound.Error(), zap.Int("noteID", summary.NoteID))
+			releaseUsage(ctx, s.usageRepo, payload.ReservationID)
+			return nil
+		}
+		return fmt.Errorf("get note %d: %w", summary.NoteID, err)
+	}
+
+	// The note may have changed since the request; record the version actually summarized
+	err = s.summaryRepo.UpdateSummary(ctx, summary.ID, map[string]any{
+		"status":       consts.SummaryStatus.PROCESSING,
+		"error":        sql.NullString{},
+		"note_version": note.Version,
+	})
+	if err != nil {
+		return fmt.Errorf("update status of summary %d: %w", summary.ID, err)
+	}
+	startProgress(ctx, s.progressStore, consts.JobProgressTopic.SUMMARY, summary.ID, models.JobProgress{Status: consts.SummaryStatus.PROCESSING})
+
+	run := &summaryRun{
+		provider: s.provider,
+		language: consts.AI_LANGUAGES[summary.Language],
+		title:    note.Title,
+		progress: func(stage string, done, total int) {
+			publishProgress(ctx, s.progressStore, consts.JobProgressTopic.SUMMARY, summary.ID, models.JobProgress{
+				Status: consts.SummaryStatus.PROCESSING,
+				Stage:  stage,
+				Done:   done,
+				Total:  total,
+			})
+		},
+	}
+	if run.language == "" {
+		run.language = consts.AI_LANGUAGES[consts.AI_DEFAULT_LANGUAGE]
+	}
+
+	content, err := run.summarize(ctx, note.ContentText)
+	if err != nil {
+		if statusErr := s.fail(ctx, summary.ID, err); statusErr != nil {
+			global.Log.Error("Error updating summary status", zap.Error(statusErr), zap.Int("summaryID", summary.ID))
+		}
+		if permanentAIError(err) {
+			global.Log.Warn("Failed to summarize note", zap.Int("summaryID", summary.ID), zap.Error(err))
+			releaseUsage(ctx, s.usageRepo, payload.ReservationID)
+			return nil
+		}
+		// Rate limits, outages and timeouts: let the queue retry
+		return fmt.Errorf("summarize note %d: %w", note.ID, err)
+	}
+
+	bullets, _ := json.Marshal(content.Bullets)
+	keyConcepts, _ := json.Marshal(content.KeyConcepts)
+	err = s.summaryRepo.UpdateSummary(ctx, summary.ID, map[string]any{
+		"status":        consts.SummaryStatus.DONE,
+		"bullets":       bullets,
+		"key_concepts":  keyConcepts,
+		"provider":      s.provider.Name(),
+		"model":         run.model,
+		"chunk_count":   run.chunks,
+		"input_tokens":  run.usage.InputTokens,
+		"output_tokens": run.usage.OutputTokens,
+		"completed_at":  time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("store summary %d: %w", summary.ID, err)
+	}
+	publishProgress(ctx, s.progressStore, consts.JobProgressTopic.SUMMARY, summary.ID, models.JobProgress{Status: consts.SummaryStatus.DONE, Final: true})
+	recordUsage(ctx, s.usageRepo, models.UsageRecord{
+		ID:           payload.ReservationID,
+		UserID:       summary.UserID,
+		Operation:    consts.UsageOperation.NOTE_SUMMARY,
+		Reference:    progressTopic(consts.JobProgressTopic.SUMMARY, summary.ID),
+		Provider:     s.provider.Name(),
+		Model:        run.model,
+		InputTokens:  run.usage.InputTokens,
+		OutputTokens: run.usage.OutputTokens,
+	})
+
+	global.Log.Info("Success summarizing note", zap.Int("summaryID", summary.ID), zap.Int("chunks", run.chunks), zap.Int("calls", run.calls))
+	return nil
+}
+
+func (s *SummarizationService) fail(ctx context.Context, id int, cause error) error {
+	message := truncate(cause.Error(), consts.SUMMARY_ERROR_LENGTH)
+	publishProgress(ctx, s.progressStore, consts.JobProgressTopic.SUMMARY, id, models.JobProgress{Status: consts.SummaryStatus.FAILED, Error: message, Final: true})
+	return s.summaryRepo.UpdateSummary(ctx, id, map[string]any{
+		"status": consts.SummaryStatus.FAILED,
+		"error":  sql.NullString{String: message, Valid: true},
+	})
+}
+
+// permanentAIError reports failures that retrying the same request cannot fix
+func permanentAIError(err error) bool {
+	var apiErr *ai.APIError
+	if errors.As(err, &apiErr) {
+		return !apiErr.Retryable()
+	}
+	return errors.Is(err, ai.ErrInvalidOutput) || errors.Is(err, ai.ErrEmptyInput) || errors.Is(err, ai.ErrUnsupported) ||
+		errors.Is(err, errMessage.ErrSummaryNoteEmpty) || errors.Is(err, errMessage.ErrFlashcardSourceEmpty) ||
+		errors.Is(err, errMessage.ErrQuizNoNotes)
+}
+
+// summaryRun summarizes one note map-reduce style: every part of the note
+// is summarized on its own, then the partial summaries are merged in batches
+// that fit the context budget until one is left
+type summaryRun struct {
+	provider ai.Provider
+	language string
+	title    string
+	progress func(stage string, done, total int) // optional
+
+	model  string
+	usage  ai.Usage
+	chunks int
+	calls  int
+}
+
+func (r *summaryRun) summarize(ctx context.Context, text string) (*models.SummaryContent, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, errMessage.ErrSummaryNoteEmpty
+	}
+
+	pieces := extract.Split([]extract.Page{{Number: 1, Text: text}}, consts.SUMMARY_CHUNK_TOKENS*ai.RunesPerToken, consts.SUMMARY_CHUNK_OVERLAP)
+	r.chunks = len(pieces)
+
+	partials := make([]*models.SummaryContent, 0, len(pieces))
+	for i, piece := range pieces {
+		r.report("summarize", i, len(pieces))
+		prompt := fmt.Sprintf("Notes title: %s\n\nPart %d of %d:\n\n%s", r.title, i+1, len(pieces), piece.Text)
+		partial, err := r.chat(ctx, consts.SUMMARY_MAP_PROMPT, prompt)
+		if err != nil {
+			return nil, err
+		}
+		partials = append(partials, partial)
+	}
+
+	for len(partials) > 1 {
+		r.report("merge", 0, len(partials))
+		var merged []*models.SummaryContent
+		for _, group := range groupPartials(partials, consts.SUMMARY_CHUNK_TOKENS) {
+			if len(group) == 1 {
+				merged = append(merged, group[0])
+				continue
+			}
+			result, err := r.chat(ctx, consts.SUMMARY_REDUCE_PROMPT, "Notes title: "+r.title+"\n\nPartial summaries:\n\n"+formatPartials(group))
+			if err != nil {
+				return nil, err
+			}
+			merged = append(merged, result)
+		}
+		partials = merged
+	}
+	return partials[0], nil
+}
+
+func (r *summaryRun) report(stage string, done, total int) {
+	if r.progress != nil {
+		r.progress(stage, done, total)
+	}
+}
+
+// chat asks for a summary object; ai.ChatJSON gives the model a chance to
+// repair a reply that is not valid JSON of the expected shape
+func (r *summaryRun) chat(ctx context.Context, systemPrompt, prompt string) (*models.SummaryContent, error) {
+	req := ai.ChatRequest{
+		System:    fmt.Sprintf(systemPrompt, r.language),
+		Messages:  []ai.Message{{Role: ai.RoleUser, Content: prompt}},
+		MaxTokens: consts.SUMMARY_MAX_OUTPUT_TOKENS,
+	}
+
+	var content *models.SummaryContent
+	resp, usage, err := ai.ChatJSON(ctx, r.provider, req, consts.SUMMARY_OUTPUT_ATTEMPTS, func(object string) error {
+		var err error
+		content, err = parseSummaryContent(object)
+		return err
+	})
+	r.calls++
+	r.usage.InputTokens += usage.InputTokens
+	r.usage.OutputTokens += usage.OutputTokens
+	if err != nil {
+		return nil, err
+	}
+	r.model = resp.Model
+	return content, nil
+}
+
+// parseSummaryContent decodes and cleans up a summary object
+func parseSummaryContent(object string) (*models.SummaryContent, error) {
+	var raw models.SummaryContent
+	if err := json.Unmarshal([]byte(object), &raw); err != nil {
+		return nil, err
+	}
+
+	content := &models.SummaryContent{Bullets: []string{}, KeyConcepts: []models.KeyConcept{}}
+	for _, bullet := range raw.Bullets {
+		if bullet = strings.TrimSpace(strings.TrimLeft(bullet, "-•* ")); bullet != "" {
+			content.Bullets = append(content.Bullets, bullet)
+		}
+	}
+	for _, concept := range raw.KeyConcepts {
+		concept.Term, concept.Explanation = strings.TrimSpace(concept.Term), strings.TrimSpace(concept.Explanation)
+		if concept.Term != "" {
+			content.KeyConcepts = append(content.KeyConcepts, concept)
+		}
+	}
+	if len(content.Bullets) == 0 {
+		return nil, errMessage.ErrInvalidSummaryOutput
+	}
+	return content, nil
+}
+
+// groupPartials batches consecutive partial summaries within the token
+// budget. Every group but possibly the last holds at least two, so each
+// reduce round shrinks the list.
+func groupPartials(partials []*models.SummaryContent, budget int) [][]*models.SummaryContent {
+	var groups [][]*models.SummaryContent
+	var current []*models.SummaryContent
+	tokens := 0
+	for _, partial := range partials {
+		size := ai.EstimateTokens(formatPartials([]*models.SummaryContent{partial}))
+		if len(current) >= 2 && tokens+size > budget {
+			groups = append(groups, current)
+			current, tokens = nil, 0
+		}
+		current = append(current, partial)
+		tokens += size
+	}
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
+
+func formatPartials(partials []*models.SummaryContent) string {
+	var b strings.Builder
+	for i, partial := range partials {
+		fmt.Fprintf(&b, "Part %d:\n", i+1)
+		for _, bullet := range partial.Bullets {
+			b.WriteString("- " + bullet + "\n")
+		}
+		if len(partial.KeyConcepts) > 0 {
+			b.WriteString("Key concepts:\n")
+			for _, concept := range partial.KeyConcepts {
+				b.WriteString("- " + concept.Term + ": " + concept.Explanation + "\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSpace(b.String())
+}
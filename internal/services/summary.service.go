@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ISummaryService interface {
+	RequestSummary(ctx context.Context, userID string, noteID int, req *models.CreateSummaryRequest) (*models.NoteSummary, int)
+	ListSummaries(ctx context.Context, userID string, noteID int) ([]models.NoteSummary, int)
+	GetSummary(ctx context.Context, userID string, noteID, version int) (*models.NoteSummary, int)
+}
+
+type SummaryService struct {
+	summaryRepo  repo.ISummaryRepository
+	noteRepo     repo.INoteRepository
+	quotaService IQuotaService
+	jobs         IJobQueue
+}
+
+func NewSummaryService(summaryRepository repo.ISummaryRepository, noteRepository repo.INoteRepository, quotaService IQuotaService, jobs IJobQueue) ISummaryService {
+	return &SummaryService{
+		summaryRepo:  summaryRepository,
+		noteRepo:     noteRepository,
+		quotaService: quotaService,
+		jobs:         jobs,
+	}
+}
+
+// RequestSummary creates a pending summary version and queues its generation.
+// While a summary of the same note version and language is still being
+// generated, that one is returned instead of starting another.
+func (s *SummaryService) RequestSummary(ctx context.Context, userID string, noteID int, req *models.CreateSummaryRequest) (*models.NoteSummary, int) {
+	language := strings.ToLower(strings.TrimSpace(req.Language))
+	if language == "" {
+		language = consts.AI_DEFAULT_LANGUAGE
+	}
+	if _, ok := consts.AI_LANGUAGES[language]; !ok {
+		global.Log.Warn(errMessage.ErrInvalidSummaryLanguage.Error(), zap.String("language", req.Language))
+		return nil, response.CodeSummaryInvalidLanguage
+	}
+
+	note, code := s.getNote(ctx, userID, noteID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	if strings.TrimSpace(note.ContentText) == "" {
+		global.Log.Warn(errMessage.ErrSummaryNoteEmpty.Error(), zap.Int("noteID", noteID))
+		return nil, response.CodeSummaryNoteEmpty
+	}
+
+	active, err := s.summaryRepo.FindActiveSummary(ctx, noteID, note.Version, language)
+	if err == nil {
+		return active, response.CodeSuccess
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		global.Log.Error("Error finding active summary", zap.Error(err), zap.Int("noteID", noteID))
+		return nil, response.CodeServerBusy
+	}
+
+	reservation, code := s.quotaService.ReserveAIOperation(ctx, userID, consts.UsageOperation.NOTE_SUMMARY)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	summary := &models.NoteSummary{
+		NoteID:        noteID,
+		UserID:        userID,
+		NoteVersion:   note.Version,
+		Language:      language,
+		Status:        consts.SummaryStatus.PENDING,
+		PromptVersion: consts.SUMMARY_PROMPT_VERSION,
+	}
+	if err := s.summaryRepo.CreateSummary(ctx, summary); err != nil {
+		s.quotaService.ReleaseAIOperation(ctx, reservation)
+		global.Log.Error("Error creating summary", zap.Error(err), zap.Int("noteID", noteID))
+		return nil, response.CodeServerBusy
+	}
+
+	payload := models.NoteSummarizePayload{SummaryID: summary.ID, UserID: userID, ReservationID: reservation}
+	if _, err := s.jobs.Enqueue(ctx, consts.JobType.NOTE_SUMMARIZE, payload); err != nil {
+		s.quotaService.ReleaseAIOperation(ctx, reservation)
+		global.Log.Error("Error enqueuing note summarization", zap.Error(err), zap.Int("summaryID", summary.ID))
+		failed := map[string]any{
+			"status": consts.SummaryStatus.FAILED,
+			"error":  sql.NullString{String: "could not be queued", Valid: true},
+		}
+		if err := s.summaryRepo.UpdateSummary(ctx, summary.ID, failed); err != nil {
+			global.Log.Error("Error updating summary status", zap.Error(err), zap.Int("summaryID", summary.ID))
+		}
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Summary requested", zap.Int("noteID", noteID), zap.Int("version", summary.Version), zap.String("language", language))
+	return summary, response.CodeSuccess
+}
+
+// ListSummaries returns every summary version of a note, newest first
+func (s *SummaryService) ListSummaries(ctx context.Context, userID string, noteID int) ([]models.NoteSummary, int) {
+	if _, code := s.getNote(ctx, userID, noteID); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	summaries, err := s.summaryRepo.ListSummaries(ctx, noteID)
+	if err != nil {
+		global.Log.Error("Error listing summaries", zap.Error(err), zap.Int("noteID", noteID))
+		return nil, response.CodeServerBusy
+	}
+	return summaries, response.CodeSuccess
+}
+
+// GetSummary returns one summary version; clients poll it until the status is done or failed
+func (s *SummaryService) GetSummary(ctx context.Context, userID string, noteID, version int) (*models.NoteSummary, int) {
+	if _, code := s.getNote(ctx, userID, noteID); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	summary, err := s.summaryRepo.GetSummary(ctx, noteID, version)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrSummaryNotFound.Error(), zap.Int("noteID", noteID), zap.Int("version", version))
+			return nil, response.CodeSummaryNotFound
+		}
+		global.Log.Error("Error getting summary", zap.Error(err), zap.Int("noteID", noteID))
+		return nil, response.CodeServerBusy
+	}
+	return summary, response.CodeSuccess
+}
+
+func (s *SummaryService) getNote(ctx context.Context, userID string, noteID int) (*models.Note, int) {
+	note, err := s.noteRepo.GetNoteByID(ctx, noteID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrNoteNotFound.Error(), zap.String("userID", userID), zap.Int("noteID", noteID))
+			return nil, response.CodeNoteNotFound
+		}
+		global.Log.Error("Error getting note", zap.Error(err), zap.Int("noteID", noteID))
+		return nil, response.CodeServerBusy
+	}
+	return note, response.CodeSuccess
+}
@@ -0,0 +1,409 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	repo "github.com/nas03/scholar-ai/backend/internal/repositories"
+	errMessage "github.com/nas03/scholar-ai/backend/pkg/errors"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ITimetableService interface {
+	CreateSession(ctx context.Context, userID string, req *models.CreateClassSessionRequest) (*models.ClassSessionResponse, int)
+	GetSession(ctx context.Context, userID string, id int) (*models.ClassSession, int)
+	ListSessions(ctx context.Context, userID string, courseID *int) ([]models.ClassSession, int)
+	UpdateSession(ctx context.Context, userID string, id int, req *models.UpdateClassSessionRequest) (*models.ClassSessionResponse, int)
+	DeleteSession(ctx context.Context, userID string, id int) int
+	AddException(ctx context.Context, userID string, id int, req *models.CreateClassSessionExceptionRequest) (*models.ClassSession, int)
+	RemoveException(ctx context.Context, userID string, id int, date string) (*models.ClassSession, int)
+	GetTimetable(ctx context.Context, userID string, query *models.TimetableQuery) ([]models.TimetableEntry, int)
+}
+
+type TimetableService struct {
+	sessionRepo repo.IClassSessionRepository
+	courseRepo  repo.ICourseRepository
+}
+
+func NewTimetableService(sessionRepository repo.IClassSessionRepository, courseRepository repo.ICourseRepository) ITimetableService {
+	return &TimetableService{
+		sessionRepo: sessionRepository,
+		courseRepo:  courseRepository,
+	}
+}
+
+// CreateSession adds a recurring class session. Clashes with the user's other
+// sessions are rejected unless the request explicitly allows them.
+func (s *TimetableService) CreateSession(ctx context.Context, userID string, req *models.CreateClassSessionRequest) (*models.ClassSessionResponse, int) {
+	if _, err := s.courseRepo.GetCourseByID(ctx, req.CourseID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrCourseNotFound.Error(), zap.String("userID", userID), zap.Int("courseID", req.CourseID))
+			return nil, response.CodeCourseNotFound
+		}
+
+		global.Log.Error("Error getting course", zap.Error(err), zap.Int("courseID", req.CourseID))
+		return nil, response.CodeServerBusy
+	}
+
+	session := &models.ClassSession{
+		UserID:     userID,
+		CourseID:   req.CourseID,
+		Weekday:    req.Weekday,
+		Recurrence: req.Recurrence,
+	}
+	if req.Location != nil {
+		session.Location = sql.NullString{String: strings.TrimSpace(*req.Location), Valid: true}
+	}
+
+	var err error
+	if session.StartTime, session.EndTime, err = parseSessionTimes(req.StartTime, req.EndTime); err != nil {
+		global.Log.Warn(errMessage.ErrInvalidClassSessionTime.Error(), zap.String("start_time", req.StartTime), zap.String("end_time", req.EndTime))
+		return nil, response.CodeClassSessionInvalidTime
+	}
+
+	endDate := ""
+	if req.EndDate != nil {
+		endDate = *req.EndDate
+	}
+	if session.StartDate, session.EndDate, err = parseSessionDates(req.StartDate, endDate); err != nil {
+		global.Log.Warn(errMessage.ErrInvalidClassSessionDate.Error(), zap.String("start_date", req.StartDate), zap.String("end_date", endDate))
+		return nil, response.CodeClassSessionInvalidDate
+	}
+
+	conflicts, code := s.findConflicts(ctx, userID, session)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	if len(conflicts) > 0 && !req.AllowConflicts {
+		global.Log.Warn(errMessage.ErrClassSessionConflict.Error(), zap.String("userID", userID), zap.Int("conflicts", len(conflicts)))
+		return &models.ClassSessionResponse{Conflicts: conflicts}, response.CodeClassSessionConflict
+	}
+
+	if err := s.sessionRepo.CreateSession(ctx, session); err != nil {
+		global.Log.Error("Error creating class session", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	global.Log.Info("Success creating class session", zap.String("userID", userID), zap.Int("sessionID", session.ID))
+	created, code := s.GetSession(ctx, userID, session.ID)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	return &models.ClassSessionResponse{Session: created, Conflicts: conflicts}, response.CodeSuccess
+}
+
+func (s *TimetableService) GetSession(ctx context.Context, userID string, id int) (*models.ClassSession, int) {
+	session, err := s.sessionRepo.GetSessionByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrClassSessionNotFound.Error(), zap.String("userID", userID), zap.Int("sessionID", id))
+			return nil, response.CodeClassSessionNotFound
+		}
+
+		global.Log.Error("Error getting class session", zap.Error(err), zap.Int("sessionID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	return session, response.CodeSuccess
+}
+
+func (s *TimetableService) ListSessions(ctx context.Context, userID string, courseID *int) ([]models.ClassSession, int) {
+	sessions, err := s.sessionRepo.ListSessions(ctx, userID, courseID)
+	if err != nil {
+		global.Log.Error("Error listing class sessions", zap.Error(err), zap.String("userID", userID))
+		return nil, response.CodeServerBusy
+	}
+
+	return sessions, response.CodeSuccess
+}
+
+func (s *TimetableService) UpdateSession(ctx context.Context, userID string, id int, req *models.UpdateClassSessionRequest) (*models.ClassSessionResponse, int) {
+	session, code := s.GetSession(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	// Apply the changes to a copy first so conflicts are checked against the new schedule
+	updated := *session
+	updates := map[string]any{}
+
+	if req.Weekday != nil {
+		updated.Weekday = *req.Weekday
+		updates["weekday"] = updated.Weekday
+	}
+	if req.Recurrence != nil {
+		updated.Recurrence = *req.Recurrence
+		updates["recurrence"] = updated.Recurrence
+	}
+	if req.Location != nil {
+		updated.Location = sql.NullString{String: strings.TrimSpace(*req.Location), Valid: *req.Location != ""}
+		updates["location"] = updated.Location
+	}
+
+	if req.StartTime != nil || req.EndTime != nil {
+		startStr, endStr := session.StartTime, session.EndTime
+		if req.StartTime != nil {
+			startStr = *req.StartTime
+		}
+		if req.EndTime != nil {
+			endStr = *req.EndTime
+		}
+
+		startTime, endTime, err := parseSessionTimes(startStr, endStr)
+		if err != nil {
+			global.Log.Warn(errMessage.ErrInvalidClassSessionTime.Error(), zap.String("start_time", startStr), zap.String("end_time", endStr))
+			return nil, response.CodeClassSessionInvalidTime
+		}
+		updated.StartTime, updated.EndTime = startTime, endTime
+		updates["start_time"], updates["end_time"] = startTime, endTime
+	}
+
+	if req.StartDate != nil || req.EndDate != nil {
+		startStr := session.StartDate.Format(consts.DATE_LAYOUT)
+		endStr := ""
+		if session.EndDate.Valid {
+			endStr = session.EndDate.Time.Format(consts.DATE_LAYOUT)
+		}
+		if req.StartDate != nil {
+			startStr = *req.StartDate
+		}
+		if req.EndDate != nil {
+			endStr = *req.EndDate
+		}
+
+		startDate, endDate, err := parseSessionDates(startStr, endStr)
+		if err != nil {
+			global.Log.Warn(errMessage.ErrInvalidClassSessionDate.Error(), zap.String("start_date", startStr), zap.String("end_date", endStr))
+			return nil, response.CodeClassSessionInvalidDate
+		}
+		updated.StartDate, updated.EndDate = startDate, endDate
+		updates["start_date"], updates["end_date"] = startDate, endDate
+	}
+
+	conflicts, code := s.findConflicts(ctx, userID, &updated)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	if len(conflicts) > 0 && !req.AllowConflicts {
+		global.Log.Warn(errMessage.ErrClassSessionConflict.Error(), zap.String("userID", userID), zap.Int("sessionID", id), zap.Int("conflicts", len(conflicts)))
+		return &models.ClassSessionResponse{Conflicts: conflicts}, response.CodeClassSessionConflict
+	}
+
+	if len(updates) > 0 {
+		if err := s.sessionRepo.UpdateSession(ctx, id, userID, updates); err != nil {
+			global.Log.Error("Error updating class session", zap.Error(err), zap.Int("sessionID", id))
+			return nil, response.CodeServerBusy
+		}
+	}
+
+	refreshed, code := s.GetSession(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+	return &models.ClassSessionResponse{Session: refreshed, Conflicts: conflicts}, response.CodeSuccess
+}
+
+func (s *TimetableService) DeleteSession(ctx context.Context, userID string, id int) int {
+	if err := s.sessionRepo.DeleteSession(ctx, id, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrClassSessionNotFound.Error(), zap.String("userID", userID), zap.Int("sessionID", id))
+			return response.CodeClassSessionNotFound
+		}
+
+		global.Log.Error("Error deleting class session", zap.Error(err), zap.Int("sessionID", id))
+		return response.CodeServerBusy
+	}
+
+	global.Log.Info("Success deleting class session", zap.String("userID", userID), zap.Int("sessionID", id))
+	return response.CodeSuccess
+}
+
+// AddException cancels a single occurrence, e.g. for a public holiday
+func (s *TimetableService) AddException(ctx context.Context, userID string, id int, req *models.CreateClassSessionExceptionRequest) (*models.ClassSession, int) {
+	session, code := s.GetSession(ctx, userID, id)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	date, err := time.Parse(consts.DATE_LAYOUT, req.Date)
+	if err != nil || !session.OccursOn(date) {
+		global.Log.Warn(errMessage.ErrInvalidSessionException.Error(), zap.Int("sessionID", id), zap.String("date", req.Date))
+		return nil, response.CodeClassSessionInvalidException
+	}
+
+	exception := &models.ClassSessionException{SessionID: id, Date: date}
+	if req.Reason != nil {
+		exception.Reason = sql.NullString{String: strings.TrimSpace(*req.Reason), Valid: true}
+	}
+	if err := s.sessionRepo.CreateException(ctx, exception); err != nil {
+		global.Log.Error("Error creating class session exception", zap.Error(err), zap.Int("sessionID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	return s.GetSession(ctx, userID, id)
+}
+
+// RemoveException restores a previously cancelled occurrence
+func (s *TimetableService) RemoveException(ctx context.Context, userID string, id int, dateStr string) (*models.ClassSession, int) {
+	if _, code := s.GetSession(ctx, userID, id); code != response.CodeSuccess {
+		return nil, code
+	}
+
+	date, err := time.Parse(consts.DATE_LAYOUT, dateStr)
+	if err != nil {
+		return nil, response.CodeInvalidParams
+	}
+
+	if err := s.sessionRepo.DeleteException(ctx, id, date); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			global.Log.Warn(errMessage.ErrInvalidSessionException.Error(), zap.Int("sessionID", id), zap.String("date", dateStr))
+			return nil, response.CodeClassSessionInvalidException
+		}
+
+		global.Log.Error("Error deleting class session exception", zap.Error(err), zap.Int("sessionID", id))
+		return nil, response.CodeServerBusy
+	}
+
+	return s.GetSession(ctx, userID, id)
+}
+
+// GetTimetable expands every session into its occurrences between from and to, inclusive
+func (s *TimetableService) GetTimetable(ctx context.Context, userID string, query *models.TimetableQuery) ([]models.TimetableEntry, int) {
+	from, errFrom := time.Parse(consts.DATE_LAYOUT, query.From)
+	to, errTo := time.Parse(consts.DATE_LAYOUT, query.To)
+	if errFrom != nil || errTo != nil || to.Before(from) || to.Sub(from).Hours()/24 > float64(consts.TIMETABLE_MAX_RANGE_DAYS) {
+		global.Log.Warn("Invalid timetable range", zap.String("from", query.From), zap.String("to", query.To))
+		return nil, response.CodeTimetableInvalidRange
+	}
+
+	sessions, code := s.ListSessions(ctx, userID, nil)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	entries := []models.TimetableEntry{}
+	for i := range sessions {
+		session := &sessions[i]
+		for _, date := range session.Occurrences(from, to) {
+			entry := models.TimetableEntry{
+				SessionID: session.ID,
+				CourseID:  session.CourseID,
+				Date:      date.Format(consts.DATE_LAYOUT),
+				Weekday:   session.Weekday,
+				StartTime: session.StartTime,
+				EndTime:   session.EndTime,
+				Location:  session.Location.String,
+			}
+			if session.Course != nil {
+				entry.CourseCode = session.Course.CourseID
+				entry.CourseName = session.Course.CourseName
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Date != entries[j].Date {
+			return entries[i].Date < entries[j].Date
+		}
+		return entries[i].StartTime < entries[j].StartTime
+	})
+	return entries, response.CodeSuccess
+}
+
+// findConflicts compares a candidate session against the user's other sessions
+func (s *TimetableService) findConflicts(ctx context.Context, userID string, candidate *models.ClassSession) ([]models.SessionConflict, int) {
+	sessions, code := s.ListSessions(ctx, userID, nil)
+	if code != response.CodeSuccess {
+		return nil, code
+	}
+
+	var others []models.ClassSession
+	for _, session := range sessions {
+		if session.ID != candidate.ID {
+			others = append(others, session)
+		}
+	}
+	return sessionConflicts(candidate, others), response.CodeSuccess
+}
+
+// sessionConflicts lists the sessions in others that meet at the same time as candidate
+func sessionConflicts(candidate *models.ClassSession, others []models.ClassSession) []models.SessionConflict {
+	conflicts := []models.SessionConflict{}
+	for i := range others {
+		other := &others[i]
+		date, clash := candidate.FirstClashWith(other, consts.TIMETABLE_CONFLICT_HORIZON)
+		if !clash {
+			continue
+		}
+		conflict := models.SessionConflict{
+			SessionID: other.ID,
+			CourseID:  other.CourseID,
+			Date:      date.Format(consts.DATE_LAYOUT),
+			StartTime: other.StartTime,
+			EndTime:   other.EndTime,
+		}
+		if other.ID == 0 {
+			conflict.UID = other.ExternalUID.String
+		}
+		if other.Course != nil {
+			conflict.CourseName = other.Course.CourseName
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
+
+// parseSessionTimes validates HH:MM (or HH:MM:SS) times and requires start < end
+func parseSessionTimes(startStr, endStr string) (string, string, error) {
+	parse := func(value string) (time.Time, error) {
+		parsed, err := time.Parse(consts.CLOCK_LAYOUT, value)
+		if err != nil {
+			return time.Parse(time.TimeOnly, value)
+		}
+		return parsed, nil
+	}
+
+	start, err := parse(startStr)
+	if err != nil {
+		return "", "", err
+	}
+	end, err := parse(endStr)
+	if err != nil {
+		return "", "", err
+	}
+	if !start.Before(end) {
+		return "", "", errMessage.ErrInvalidClassSessionTime
+	}
+	return start.Format(time.TimeOnly), end.Format(time.TimeOnly), nil
+}
+
+// parseSessionDates validates the series bounds. An empty end date means open-ended.
+func parseSessionDates(startStr, endStr string) (time.Time, sql.NullTime, error) {
+	start, err := time.Parse(consts.DATE_LAYOUT, startStr)
+	if err != nil {
+		return time.Time{}, sql.NullTime{}, err
+	}
+	if endStr == "" {
+		return start, sql.NullTime{}, nil
+	}
+
+	end, err := time.Parse(consts.DATE_LAYOUT, endStr)
+	if err != nil {
+		return time.Time{}, sql.NullTime{}, err
+	}
+	if end.Before(start) {
+		return time.Time{}, sql.NullTime{}, fmt.Errorf("end date %s is before start date %s", endStr, startStr)
+	}
+	return start, sql.NullTime{Time: end, Valid: true}, nil
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"crypto/rand"
+	"encoding/hex"
 	"math/big"
 )
 
@@ -11,3 +12,12 @@ func GenerateSixDigitOtp() int {
 	n, _ := rand.Int(rand.Reader, max)
 	return int(n.Int64()) + 100000
 }
+
+// GenerateToken returns n cryptographically random bytes, hex encoded
+func GenerateToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
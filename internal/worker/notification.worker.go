@@ -0,0 +1,45 @@
+// Package worker holds long-running background loops run by the worker process
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"go.uber.org/zap"
+)
+
+// NotificationWorker periodically ticks the reminder notifier
+type NotificationWorker struct {
+	notifier services.IReminderNotifier
+	interval time.Duration
+}
+
+func NewNotificationWorker(notifier services.IReminderNotifier, interval time.Duration) *NotificationWorker {
+	return &NotificationWorker{
+		notifier: notifier,
+		interval: interval,
+	}
+}
+
+// Run scans immediately and then on every interval until ctx is cancelled
+func (w *NotificationWorker) Run(ctx context.Context) {
+	global.Log.Info("Notification worker started", zap.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.notifier.Tick(ctx, time.Now().UTC()); err != nil {
+			global.Log.Error("Notification worker tick failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			global.Log.Info("Notification worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"go.uber.org/zap"
+)
+
+// UploadSweepWorker periodically removes presigned uploads that expired
+type UploadSweepWorker struct {
+	files    services.IFileService
+	interval time.Duration
+}
+
+func NewUploadSweepWorker(files services.IFileService, interval time.Duration) *UploadSweepWorker {
+	return &UploadSweepWorker{
+		files:    files,
+		interval: interval,
+	}
+}
+
+// Run sweeps immediately and then on every interval until ctx is cancelled
+func (w *UploadSweepWorker) Run(ctx context.Context) {
+	global.Log.Info("Upload sweep worker started", zap.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.files.SweepUploads(ctx, time.Now()); err != nil {
+			global.Log.Error("Upload sweep failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			global.Log.Info("Upload sweep worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Package ai talks to large language model providers: chat completions,
+// streaming, embeddings and token counting behind one interface
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnsupported is returned by providers that lack an operation, e.g. embeddings on Anthropic
+	ErrUnsupported = errors.New("operation not supported by provider")
+	ErrEmptyInput  = errors.New("empty input")
+)
+
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+type Message struct {
+	Role    Role
+	Content string
+}
+
+// ChatRequest is a provider-neutral completion request. System is sent the way
+// each provider expects it; Model falls back to the provider's default.
+type ChatRequest struct {
+	Model       string
+	System      string
+	Messages    []Message
+	MaxTokens   int      // zero uses the provider default
+	Temperature *float64 // nil uses the provider default
+	JSON        bool     // ask for a single JSON object as output
+}
+
+type ChatResponse struct {
+	Model        string
+	Content      string
+	FinishReason string // provider's own value, e.g. "stop", "length", "end_turn", "max_tokens"
+	Usage        Usage
+}
+
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+type EmbeddingRequest struct {
+	Model string
+	Input []string
+}
+
+type EmbeddingResponse struct {
+	Model   string
+	Vectors [][]float32 // one per input, in input order
+	Usage   Usage
+}
+
+// Provider is implemented by every LLM backend
+type Provider interface {
+	// Name identifies the provider in logs and stored results, e.g. "openai"
+	Name() string
+	Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error)
+	// Stream calls onDelta with each piece of generated text as it arrives and
+	// returns the complete response. An error from onDelta aborts the stream.
+	Stream(ctx context.Context, req ChatRequest, onDelta func(delta string) error) (*ChatResponse, error)
+	Embed(ctx context.Context, req EmbeddingRequest) (*EmbeddingResponse, error)
+	// CountTokens returns the number of input tokens the request would use
+	CountTokens(ctx context.Context, req ChatRequest) (int, error)
+}
+
+// APIError is a non-success response from a provider
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Type       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("%s: %d %s: %s", e.Provider, e.StatusCode, e.Type, e.Message)
+	}
+	return fmt.Sprintf("%s: %d: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// Retryable reports whether the request may succeed when sent again
+// (rate limits, overload and server errors)
+func (e *APIError) Retryable() bool {
+	return e.StatusCode == 408 || e.StatusCode == 409 || e.StatusCode == 429 || e.StatusCode >= 500
+}
+
+// WithEmbeddings returns a provider that chats through chat and embeds through
+// embedder, for chat providers without an embeddings API
+func WithEmbeddings(chat, embedder Provider) Provider {
+	return &splitProvider{Provider: chat, embedder: embedder}
+}
+
+type splitProvider struct {
+	Provider
+	embedder Provider
+}
+
+func (p *splitProvider) Embed(ctx context.Context, req EmbeddingRequest) (*EmbeddingResponse, error) {
+	return p.embedder.Embed(ctx, req)
+}
@@ -0,0 +1,232 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	anthropicVersion          = "2023-06-01"
+	anthropicDefaultMaxTokens = 4096
+	// Anthropic has no JSON mode, so JSON requests carry this instruction instead
+	anthropicJSONInstruction = "Respond with a single valid JSON object and nothing else: no prose, no code fences."
+)
+
+// AnthropicConfig configures the Anthropic Messages API or a compatible gateway
+type AnthropicConfig struct {
+	BaseURL   string // defaults to https://api.anthropic.com
+	APIKey    string
+	Version   string // anthropic-version header, defaults to 2023-06-01
+	ChatModel string
+	Options
+}
+
+type AnthropicProvider struct {
+	cfg    AnthropicConfig
+	client *client
+}
+
+func NewAnthropicProvider(cfg AnthropicConfig) (*AnthropicProvider, error) {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.anthropic.com"
+	}
+	if cfg.Version == "" {
+		cfg.Version = anthropicVersion
+	}
+	if cfg.ChatModel == "" {
+		return nil, errors.New("anthropic provider needs a chat model")
+	}
+
+	header := http.Header{}
+	header.Set("x-api-key", cfg.APIKey)
+	header.Set("anthropic-version", cfg.Version)
+
+	return &AnthropicProvider{
+		cfg: cfg,
+		client: &client{
+			baseURL:     strings.TrimRight(cfg.BaseURL, "/"),
+			header:      header,
+			opts:        cfg.Options.withDefaults(),
+			decodeError: decodeAnthropicError,
+		},
+	}, nil
+}
+
+func (p *AnthropicProvider) Name() string {
+	return "anthropic"
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	MaxTokens   int                `json:"max_tokens,omitempty"`
+	Temperature *float64           `json:"temperature,omitempty"`
+	Stream      bool               `json:"stream,omitempty"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+func (p *AnthropicProvider) request(req ChatRequest, stream bool) anthropicRequest {
+	body := anthropicRequest{
+		Model:       req.Model,
+		System:      req.System,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		Stream:      stream,
+	}
+	if body.Model == "" {
+		body.Model = p.cfg.ChatModel
+	}
+	if body.MaxTokens == 0 {
+		body.MaxTokens = anthropicDefaultMaxTokens
+	}
+	if req.JSON {
+		body.System = strings.TrimSpace(body.System + "\n\n" + anthropicJSONInstruction)
+	}
+	for _, message := range req.Messages {
+		body.Messages = append(body.Messages, anthropicMessage{Role: string(message.Role), Content: message.Content})
+	}
+	return body
+}
+
+func (p *AnthropicProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, ErrEmptyInput
+	}
+
+	var out struct {
+		Model   string `json:"model"`
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		StopReason string         `json:"stop_reason"`
+		Usage      anthropicUsage `json:"usage"`
+	}
+	if err := p.client.postJSON(ctx, "/v1/messages", p.request(req, false), &out); err != nil {
+		return nil, err
+	}
+
+	var content strings.Builder
+	for _, block := range out.Content {
+		if block.Type == "text" {
+			content.WriteString(block.Text)
+		}
+	}
+	return &ChatResponse{
+		Model:        out.Model,
+		Content:      content.String(),
+		FinishReason: out.StopReason,
+		Usage:        Usage{InputTokens: out.Usage.InputTokens, OutputTokens: out.Usage.OutputTokens},
+	}, nil
+}
+
+func (p *AnthropicProvider) Stream(ctx context.Context, req ChatRequest, onDelta func(delta string) error) (*ChatResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, ErrEmptyInput
+	}
+
+	resp, err := p.client.postStream(ctx, "/v1/messages", p.request(req, true))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := &ChatResponse{}
+	var content strings.Builder
+	err = readEvents(resp.Body, func(event sseEvent) error {
+		var payload struct {
+			Type    string `json:"type"`
+			Message struct {
+				Model string         `json:"model"`
+				Usage anthropicUsage `json:"usage"`
+			} `json:"message"`
+			Delta struct {
+				Type       string `json:"type"`
+				Text       string `json:"text"`
+				StopReason string `json:"stop_reason"`
+			} `json:"delta"`
+			Usage anthropicUsage `json:"usage"`
+			Error struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(event.Data), &payload); err != nil {
+			return err
+		}
+
+		switch payload.Type {
+		case "message_start":
+			result.Model = payload.Message.Model
+			result.Usage.InputTokens = payload.Message.Usage.InputTokens
+		case "content_block_delta":
+			if payload.Delta.Type == "text_delta" && payload.Delta.Text != "" {
+				content.WriteString(payload.Delta.Text)
+				return onDelta(payload.Delta.Text)
+			}
+		case "message_delta":
+			result.FinishReason = payload.Delta.StopReason
+			result.Usage.OutputTokens = payload.Usage.OutputTokens
+		case "error":
+			// Errors after the headers arrive come as events, e.g. overloaded_error
+			return &APIError{Provider: "anthropic", StatusCode: http.StatusOK, Type: payload.Error.Type, Message: payload.Error.Message}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Content = content.String()
+	return result, nil
+}
+
+// Embed is unsupported; combine with an embeddings provider through WithEmbeddings
+func (p *AnthropicProvider) Embed(ctx context.Context, req EmbeddingRequest) (*EmbeddingResponse, error) {
+	return nil, ErrUnsupported
+}
+
+// CountTokens asks the API for the exact count
+func (p *AnthropicProvider) CountTokens(ctx context.Context, req ChatRequest) (int, error) {
+	if len(req.Messages) == 0 {
+		return 0, ErrEmptyInput
+	}
+
+	body := p.request(req, false)
+	body.MaxTokens = 0
+
+	var out struct {
+		InputTokens int `json:"input_tokens"`
+	}
+	if err := p.client.postJSON(ctx, "/v1/messages/count_tokens", body, &out); err != nil {
+		return 0, err
+	}
+	return out.InputTokens, nil
+}
+
+func decodeAnthropicError(status int, body []byte) *APIError {
+	apiErr := &APIError{Provider: "anthropic", StatusCode: status, Message: http.StatusText(status)}
+	var out struct {
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &out) == nil && out.Error.Message != "" {
+		apiErr.Type, apiErr.Message = out.Error.Type, out.Error.Message
+	}
+	return apiErr
+}
@@ -0,0 +1,208 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Options tune the HTTP behaviour shared by all remote providers
+type Options struct {
+	Timeout     time.Duration // per attempt; for streams, until the response headers arrive
+	MaxRetries  int           // attempts after the first on rate limits, overload and network errors; negative disables
+	BackoffBase time.Duration // doubled on every retry, with jitter
+	BackoffMax  time.Duration
+	HTTPClient  *http.Client
+}
+
+const (
+	defaultTimeout     = 60 * time.Second
+	defaultMaxRetries  = 2
+	defaultBackoffBase = 500 * time.Millisecond
+	defaultBackoffMax  = 10 * time.Second
+	maxErrorBody       = 64 * 1024
+)
+
+func (o Options) withDefaults() Options {
+	if o.Timeout <= 0 {
+		o.Timeout = defaultTimeout
+	}
+	if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	} else if o.MaxRetries == 0 {
+		o.MaxRetries = defaultMaxRetries
+	}
+	if o.BackoffBase <= 0 {
+		o.BackoffBase = defaultBackoffBase
+	}
+	if o.BackoffMax <= 0 {
+		o.BackoffMax = defaultBackoffMax
+	}
+	if o.HTTPClient == nil {
+		o.HTTPClient = &http.Client{}
+	}
+	return o
+}
+
+// client sends JSON requests with per-attempt timeouts and retries
+type client struct {
+	baseURL string
+	header  http.Header
+	opts    Options
+	// decodeError turns an error body into an APIError
+	decodeError func(status int, body []byte) *APIError
+}
+
+// postJSON sends body to path and decodes a successful response into out
+func (c *client) postJSON(ctx context.Context, path string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	return c.retry(ctx, func() error {
+		attemptCtx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+		defer cancel()
+
+		resp, err := c.send(attemptCtx, path, payload)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return json.NewDecoder(resp.Body).Decode(out)
+	})
+}
+
+// postStream sends body to path and returns the open response. The timeout
+// only covers the wait for the response headers; the body can take longer.
+func (c *client) postStream(ctx context.Context, path string, body any) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *http.Response
+	err = c.retry(ctx, func() error {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		timer := time.AfterFunc(c.opts.Timeout, cancel)
+
+		r, err := c.send(attemptCtx, path, payload)
+		if !timer.Stop() || err != nil {
+			cancel()
+			if err == nil {
+				r.Body.Close()
+				err = context.DeadlineExceeded
+			}
+			return err
+		}
+		r.Body = &cancelOnClose{ReadCloser: r.Body, cancel: cancel}
+		resp = r
+		return nil
+	})
+	return resp, err
+}
+
+// send performs one attempt and converts error statuses to *APIError
+func (c *client) send(ctx context.Context, path string, payload []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range c.header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	apiErr := c.decodeError(resp.StatusCode, data)
+	return nil, &retryAfterError{APIError: apiErr, after: parseRetryAfter(resp.Header.Get("Retry-After"))}
+}
+
+// retry runs attempt until it succeeds, fails permanently or retries run out
+func (c *client) retry(ctx context.Context, attempt func() error) error {
+	var err error
+	for i := 0; ; i++ {
+		err = attempt()
+		var delayed *retryAfterError
+		if errors.As(err, &delayed) {
+			err = delayed.APIError
+		}
+		if err == nil || i >= c.opts.MaxRetries || !retryable(ctx, err) {
+			return err
+		}
+
+		wait := c.backoff(i)
+		if delayed != nil && delayed.after > 0 {
+			wait = min(delayed.after, c.opts.BackoffMax)
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (c *client) backoff(attempt int) time.Duration {
+	wait := c.opts.BackoffBase << attempt
+	if wait <= 0 || wait > c.opts.BackoffMax {
+		wait = c.opts.BackoffMax
+	}
+	// Full jitter in the upper half keeps concurrent clients apart
+	return wait/2 + rand.N(wait/2+1)
+}
+
+// retryable treats API errors by status and transport errors as transient,
+// unless the caller's own context has ended
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+	var syntaxErr *json.SyntaxError
+	return !errors.As(err, &syntaxErr)
+}
+
+type retryAfterError struct {
+	*APIError
+	after time.Duration
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,189 @@
+package ai
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	fakeModel          = "fake-1"
+	fakeDimensions     = 256
+	fakeEchoRunes      = 200
+	fakeEmbeddingModel = "fake-embedding-1"
+)
+
+// FakeProvider answers offline and deterministically, for tests and local
+// development. Replies are taken from Replies in order, then from Reply, and
+// otherwise echo the last user message. Embeddings hash each word into a
+// fixed-size vector, so texts sharing words have a high cosine similarity.
+type FakeProvider struct {
+	Replies    []string
+	Reply      func(req ChatRequest) (string, error)
+	Dimensions int // embedding size, defaults to 256
+
+	mu       sync.Mutex
+	requests []ChatRequest
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+// Requests returns every chat request received so far
+func (p *FakeProvider) Requests() []ChatRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]ChatRequest(nil), p.requests...)
+}
+
+func (p *FakeProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, ErrEmptyInput
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	content, err := p.reply(req)
+	if err != nil {
+		return nil, err
+	}
+	model := req.Model
+	if model == "" {
+		model = fakeModel
+	}
+	return &ChatResponse{
+		Model:        model,
+		Content:      content,
+		FinishReason: "stop",
+		Usage:        Usage{InputTokens: EstimateRequestTokens(req), OutputTokens: EstimateTokens(content)},
+	}, nil
+}
+
+// Stream delivers the reply word by word
+func (p *FakeProvider) Stream(ctx context.Context, req ChatRequest, onDelta func(delta string) error) (*ChatResponse, error) {
+	resp, err := p.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := resp.Content
+	for rest != "" {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// Each delta is a word plus the whitespace before the next one
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			end = len(rest)
+		}
+		for end < len(rest) {
+			r, size := utf8.DecodeRuneInString(rest[end:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			end += size
+		}
+		if err := onDelta(rest[:end]); err != nil {
+			return nil, err
+		}
+		rest = rest[end:]
+	}
+	return resp, nil
+}
+
+func (p *FakeProvider) Embed(ctx context.Context, req EmbeddingRequest) (*EmbeddingResponse, error) {
+	if len(req.Input) == 0 {
+		return nil, ErrEmptyInput
+	}
+	dimensions := p.Dimensions
+	if dimensions <= 0 {
+		dimensions = fakeDimensions
+	}
+
+	result := &EmbeddingResponse{Model: req.Model, Vectors: make([][]float32, len(req.Input))}
+	if result.Model == "" {
+		result.Model = fakeEmbeddingModel
+	}
+	for i, text := range req.Input {
+		result.Vectors[i] = hashEmbedding(text, dimensions)
+		result.Usage.InputTokens += EstimateTokens(text)
+	}
+	return result, nil
+}
+
+func (p *FakeProvider) CountTokens(ctx context.Context, req ChatRequest) (int, error) {
+	return EstimateRequestTokens(req), nil
+}
+
+func (p *FakeProvider) reply(req ChatRequest) (string, error) {
+	p.mu.Lock()
+	p.requests = append(p.requests, req)
+	if len(p.Replies) > 0 {
+		reply := p.Replies[0]
+		p.Replies = p.Replies[1:]
+		p.mu.Unlock()
+		return reply, nil
+	}
+	p.mu.Unlock()
+
+	if p.Reply != nil {
+		return p.Reply(req)
+	}
+	if req.JSON {
+		return "{}", nil
+	}
+
+	last := ""
+	for _, message := range req.Messages {
+		if message.Role == RoleUser {
+			last = message.Content
+		}
+	}
+	echo := []rune(strings.Join(strings.Fields(last), " "))
+	if len(echo) > fakeEchoRunes {
+		echo = echo[:fakeEchoRunes]
+	}
+	return "Fake reply: " + string(echo), nil
+}
+
+// hashEmbedding maps each lower-cased word to a signed dimension and
+// normalizes the sum to unit length
+func hashEmbedding(text string, dimensions int) []float32 {
+	vector := make([]float32, dimensions)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		h := fnv.New64a()
+		h.Write([]byte(word))
+		sum := h.Sum64()
+		sign := float32(1)
+		if sum>>63 == 1 {
+			sign = -1
+		}
+		vector[sum%uint64(dimensions)] += sign
+	}
+
+	var norm float64
+	for _, value := range vector {
+		norm += float64(value) * float64(value)
+	}
+	if norm == 0 {
+		return vector
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vector {
+		vector[i] *= scale
+	}
+	return vector
+}
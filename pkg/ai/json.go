@@ -0,0 +1,61 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidOutput is returned by ChatJSON when the model keeps answering
+// with something the caller cannot decode
+var ErrInvalidOutput = errors.New("model returned invalid structured output")
+
+const repairPrompt = "That reply was rejected: %v. Reply again with the corrected JSON object only."
+
+// ExtractJSON returns the JSON object in a model reply, tolerating code fences
+// and prose around it. Callers still have to unmarshal and validate it.
+func ExtractJSON(content string) (string, bool) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return "", false
+	}
+	return content[start : end+1], true
+}
+
+// ChatJSON sends req in JSON mode and hands the object in the reply to decode.
+// When decode rejects it, the reply and the reason are sent back so the model
+// can correct itself, up to attempts requests in total. The returned usage
+// covers every request made.
+func ChatJSON(ctx context.Context, provider Provider, req ChatRequest, attempts int, decode func(object string) error) (*ChatResponse, Usage, error) {
+	req.JSON = true
+	req.Messages = append([]Message(nil), req.Messages...)
+
+	var usage Usage
+	for attempt := 1; ; attempt++ {
+		resp, err := provider.Chat(ctx, req)
+		if err != nil {
+			return nil, usage, err
+		}
+		usage.InputTokens += resp.Usage.InputTokens
+		usage.OutputTokens += resp.Usage.OutputTokens
+
+		object, ok := ExtractJSON(resp.Content)
+		if !ok {
+			err = errors.New("no JSON object in reply")
+		} else {
+			err = decode(object)
+		}
+		if err == nil {
+			return resp, usage, nil
+		}
+		if attempt >= attempts {
+			return nil, usage, fmt.Errorf("%w: %w", ErrInvalidOutput, err)
+		}
+		req.Messages = append(req.Messages,
+			Message{Role: RoleAssistant, Content: resp.Content},
+			Message{Role: RoleUser, Content: fmt.Sprintf(repairPrompt, err)},
+		)
+	}
+}
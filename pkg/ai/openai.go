@@ -0,0 +1,252 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// OpenAIConfig configures an OpenAI-compatible API (OpenAI, Azure-style
+// gateways, vLLM, Ollama, LM Studio)
+type OpenAIConfig struct {
+	BaseURL        string // defaults to https://api.openai.com/v1
+	APIKey         string
+	Organization   string
+	ChatModel      string
+	EmbeddingModel string
+	Options
+}
+
+type OpenAIProvider struct {
+	cfg    OpenAIConfig
+	client *client
+}
+
+func NewOpenAIProvider(cfg OpenAIConfig) (*OpenAIProvider, error) {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.openai.com/v1"
+	}
+	if cfg.ChatModel == "" {
+		return nil, errors.New("openai provider needs a chat model")
+	}
+
+	header := http.Header{}
+	if cfg.APIKey != "" {
+		header.Set("Authorization", "Bearer "+cfg.APIKey)
+	}
+	if cfg.Organization != "" {
+		header.Set("OpenAI-Organization", cfg.Organization)
+	}
+
+	return &OpenAIProvider{
+		cfg: cfg,
+		client: &client{
+			baseURL:     strings.TrimRight(cfg.BaseURL, "/"),
+			header:      header,
+			opts:        cfg.Options.withDefaults(),
+			decodeError: decodeOpenAIError,
+		},
+	}, nil
+}
+
+func (p *OpenAIProvider) Name() string {
+	return "openai"
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIChatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []openAIMessage `json:"messages"`
+	MaxTokens      int             `json:"max_tokens,omitempty"`
+	Temperature    *float64        `json:"temperature,omitempty"`
+	ResponseFormat *struct {
+		Type string `json:"type"`
+	} `json:"response_format,omitempty"`
+	Stream        bool `json:"stream,omitempty"`
+	StreamOptions *struct {
+		IncludeUsage bool `json:"include_usage"`
+	} `json:"stream_options,omitempty"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type openAIChatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		Delta        openAIMessage `json:"delta"`
+		FinishReason *string       `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+func (p *OpenAIProvider) chatRequest(req ChatRequest, stream bool) openAIChatRequest {
+	body := openAIChatRequest{
+		Model:       req.Model,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		Stream:      stream,
+	}
+	if body.Model == "" {
+		body.Model = p.cfg.ChatModel
+	}
+	if req.System != "" {
+		body.Messages = append(body.Messages, openAIMessage{Role: "system", Content: req.System})
+	}
+	for _, message := range req.Messages {
+		body.Messages = append(body.Messages, openAIMessage{Role: string(message.Role), Content: message.Content})
+	}
+	if req.JSON {
+		body.ResponseFormat = &struct {
+			Type string `json:"type"`
+		}{Type: "json_object"}
+	}
+	if stream {
+		body.StreamOptions = &struct {
+			IncludeUsage bool `json:"include_usage"`
+		}{IncludeUsage: true}
+	}
+	return body
+}
+
+func (p *OpenAIProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, ErrEmptyInput
+	}
+
+	var out openAIChatResponse
+	if err := p.client.postJSON(ctx, "/chat/completions", p.chatRequest(req, false), &out); err != nil {
+		return nil, err
+	}
+	if len(out.Choices) == 0 {
+		return nil, &APIError{Provider: "openai", StatusCode: http.StatusOK, Message: "response has no choices"}
+	}
+
+	result := &ChatResponse{Model: out.Model, Content: out.Choices[0].Message.Content}
+	if out.Choices[0].FinishReason != nil {
+		result.FinishReason = *out.Choices[0].FinishReason
+	}
+	if out.Usage != nil {
+		result.Usage = Usage{InputTokens: out.Usage.PromptTokens, OutputTokens: out.Usage.CompletionTokens}
+	}
+	return result, nil
+}
+
+func (p *OpenAIProvider) Stream(ctx context.Context, req ChatRequest, onDelta func(delta string) error) (*ChatResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, ErrEmptyInput
+	}
+
+	resp, err := p.client.postStream(ctx, "/chat/completions", p.chatRequest(req, true))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := &ChatResponse{}
+	var content strings.Builder
+	err = readEvents(resp.Body, func(event sseEvent) error {
+		if event.Data == "[DONE]" {
+			return nil
+		}
+		var chunk openAIChatResponse
+		if err := json.Unmarshal([]byte(event.Data), &chunk); err != nil {
+			return err
+		}
+		if chunk.Model != "" {
+			result.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			result.Usage = Usage{InputTokens: chunk.Usage.PromptTokens, OutputTokens: chunk.Usage.CompletionTokens}
+		}
+		if len(chunk.Choices) == 0 {
+			return nil
+		}
+		if reason := chunk.Choices[0].FinishReason; reason != nil {
+			result.FinishReason = *reason
+		}
+		if delta := chunk.Choices[0].Delta.Content; delta != "" {
+			content.WriteString(delta)
+			return onDelta(delta)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Content = content.String()
+	return result, nil
+}
+
+func (p *OpenAIProvider) Embed(ctx context.Context, req EmbeddingRequest) (*EmbeddingResponse, error) {
+	if len(req.Input) == 0 {
+		return nil, ErrEmptyInput
+	}
+	model := req.Model
+	if model == "" {
+		model = p.cfg.EmbeddingModel
+	}
+	if model == "" {
+		return nil, ErrUnsupported
+	}
+
+	var out struct {
+		Model string `json:"model"`
+		Data  []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+		Usage openAIUsage `json:"usage"`
+	}
+	body := map[string]any{"model": model, "input": req.Input}
+	if err := p.client.postJSON(ctx, "/embeddings", body, &out); err != nil {
+		return nil, err
+	}
+
+	result := &EmbeddingResponse{
+		Model:   out.Model,
+		Vectors: make([][]float32, len(req.Input)),
+		Usage:   Usage{InputTokens: out.Usage.PromptTokens},
+	}
+	for _, item := range out.Data {
+		if item.Index < 0 || item.Index >= len(result.Vectors) {
+			return nil, &APIError{Provider: "openai", StatusCode: http.StatusOK, Message: "embedding index out of range"}
+		}
+		result.Vectors[item.Index] = item.Embedding
+	}
+	for _, vector := range result.Vectors {
+		if vector == nil {
+			return nil, &APIError{Provider: "openai", StatusCode: http.StatusOK, Message: "missing embedding in response"}
+		}
+	}
+	return result, nil
+}
+
+// CountTokens estimates locally; the OpenAI API has no counting endpoint
+func (p *OpenAIProvider) CountTokens(ctx context.Context, req ChatRequest) (int, error) {
+	return EstimateRequestTokens(req), nil
+}
+
+func decodeOpenAIError(status int, body []byte) *APIError {
+	apiErr := &APIError{Provider: "openai", StatusCode: status, Message: http.StatusText(status)}
+	var out struct {
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &out) == nil && out.Error.Message != "" {
+		apiErr.Type, apiErr.Message = out.Error.Type, out.Error.Message
+	}
+	return apiErr
+}
@@ -0,0 +1,55 @@
+package ai
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// sseEvent is one server-sent event
+type sseEvent struct {
+	Event string
+	Data  string
+}
+
+// readEvents calls fn for every event in an SSE stream until the stream
+// ends or fn returns an error
+func readEvents(r io.Reader, fn func(sseEvent) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var event sseEvent
+	var data []string
+	flush := func() error {
+		if len(data) == 0 {
+			event = sseEvent{}
+			return nil
+		}
+		event.Data = strings.Join(data, "\n")
+		err := fn(event)
+		event, data = sseEvent{}, nil
+		return err
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if err := flush(); err != nil {
+				return err
+			}
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
@@ -0,0 +1,35 @@
+package ai
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// RunesPerToken is the average text length of a token assumed by the estimates
+	RunesPerToken = 4
+	// messageOverhead approximates the tokens chat formats add around each message
+	messageOverhead = 4
+)
+
+// EstimateTokens approximates the token count of text for BPE tokenizers:
+// about four characters per token, and never fewer tokens than words
+func EstimateTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+	byChars := (utf8.RuneCountInString(text) + RunesPerToken - 1) / RunesPerToken
+	return max(byChars, len(strings.Fields(text)))
+}
+
+// EstimateRequestTokens approximates the input tokens of a whole request
+func EstimateRequestTokens(req ChatRequest) int {
+	total := 0
+	if req.System != "" {
+		total += EstimateTokens(req.System) + messageOverhead
+	}
+	for _, message := range req.Messages {
+		total += EstimateTokens(message.Content) + messageOverhead
+	}
+	return total
+}
@@ -0,0 +1,104 @@
+// Package billing sells subscriptions through a payment provider: hosted
+// checkout sessions and signed webhooks reporting subscription changes,
+// behind one interface
+package billing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrInvalidEvent     = errors.New("invalid webhook event")
+	ErrUnknownPlan      = errors.New("plan has no price at the provider")
+)
+
+// Subscription statuses reported by every provider. Provider-specific states
+// are mapped onto these; Status is empty for states that grant nothing yet,
+// e.g. a subscription whose first payment is still incomplete.
+const (
+	StatusTrialing = "trialing"
+	StatusActive   = "active"
+	StatusPastDue  = "past_due"
+	StatusCanceled = "canceled"
+)
+
+// CheckoutRequest describes the subscription a user is about to pay for
+type CheckoutRequest struct {
+	UserID     string
+	Email      string
+	CustomerID string // provider customer of a returning subscriber, empty for new ones
+	PlanCode   string
+	TrialDays  int // zero starts billing immediately
+	SuccessURL string
+	CancelURL  string
+}
+
+// CheckoutSession is a hosted payment page the user is redirected to
+type CheckoutSession struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Event is a verified webhook notification
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	// Subscription is the state after the event, nil for events that don't change one
+	Subscription *Subscription `json:"subscription,omitempty"`
+}
+
+// Subscription is the provider's view of a subscription
+type Subscription struct {
+	ID                 string     `json:"id"`
+	CustomerID         string     `json:"customer_id"`
+	UserID             string     `json:"user_id"` // from the checkout, empty if the subscription was made elsewhere
+	PlanCode           string     `json:"plan_code"`
+	Status             string     `json:"status"`
+	CurrentPeriodStart time.Time  `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time  `json:"current_period_end"`
+	TrialEnd           *time.Time `json:"trial_end,omitempty"`
+	CancelAtPeriodEnd  bool       `json:"cancel_at_period_end"`
+	CanceledAt         *time.Time `json:"canceled_at,omitempty"`
+}
+
+// Provider is implemented by every payment backend
+type Provider interface {
+	// Name identifies the provider in stored subscriptions and events, e.g. "stripe"
+	Name() string
+	CreateCheckoutSession(ctx context.Context, req CheckoutRequest) (*CheckoutSession, error)
+	// ParseEvent verifies the signature of a webhook request and decodes its body
+	ParseEvent(payload []byte, header http.Header) (*Event, error)
+}
+
+// APIError is a non-success response from a provider
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %d: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// sign returns the hex HMAC-SHA256 of message under secret
+func sign(secret string, message []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(message)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func validSignature(secret string, message []byte, signature string) bool {
+	expected := sign(secret, message)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
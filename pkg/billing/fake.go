@@ -0,0 +1,86 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeSignatureHeader carries the hex HMAC-SHA256 of a fake webhook body
+const FakeSignatureHeader = "X-Fake-Signature"
+
+// FakeProvider takes no payments, for tests and local development. Checkout
+// sessions point at BaseURL and webhook bodies are Event JSON signed with
+// Secret, so recorded fixtures can be replayed against the webhook endpoint.
+type FakeProvider struct {
+	Secret  string
+	BaseURL string // origin of checkout URLs, defaults to http://localhost
+
+	mu       sync.Mutex
+	sessions []CheckoutRequest
+}
+
+func NewFakeProvider(secret string) *FakeProvider {
+	return &FakeProvider{Secret: secret}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+// Sessions returns every checkout request received so far
+func (p *FakeProvider) Sessions() []CheckoutRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]CheckoutRequest(nil), p.sessions...)
+}
+
+func (p *FakeProvider) CreateCheckoutSession(ctx context.Context, req CheckoutRequest) (*CheckoutSession, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if req.PlanCode == "" {
+		return nil, ErrUnknownPlan
+	}
+
+	p.mu.Lock()
+	p.sessions = append(p.sessions, req)
+	id := fmt.Sprintf("cs_fake_%d", len(p.sessions))
+	p.mu.Unlock()
+
+	baseURL := strings.TrimRight(p.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = "http://localhost"
+	}
+	return &CheckoutSession{
+		ID:        id,
+		URL:       baseURL + "/checkout/" + id,
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+	}, nil
+}
+
+// Sign returns the signature header value of a webhook body
+func (p *FakeProvider) Sign(payload []byte) string {
+	return sign(p.Secret, payload)
+}
+
+// ParseEvent rejects every webhook while Secret is empty, so an unconfigured
+// fake can't be used to grant plans
+func (p *FakeProvider) ParseEvent(payload []byte, header http.Header) (*Event, error) {
+	if p.Secret == "" || !validSignature(p.Secret, payload, header.Get(FakeSignatureHeader)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	if event.ID == "" || event.Type == "" {
+		return nil, fmt.Errorf("%w: missing id or type", ErrInvalidEvent)
+	}
+	return &event, nil
+}
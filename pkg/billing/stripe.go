@@ -0,0 +1,271 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	stripeSignatureHeader  = "Stripe-Signature"
+	stripeDefaultTolerance = 5 * time.Minute
+	stripeDefaultTimeout   = 30 * time.Second
+)
+
+// StripeConfig configures the Stripe API
+type StripeConfig struct {
+	BaseURL       string            // defaults to https://api.stripe.com
+	SecretKey     string            // API key, sk_...
+	WebhookSecret string            // signing secret of the webhook endpoint, whsec_...
+	Prices        map[string]string // plan code -> recurring price ID, price_...
+	Tolerance     time.Duration     // maximum age of a webhook signature, defaults to 5 minutes
+	Timeout       time.Duration     // per request, defaults to 30 seconds
+}
+
+type StripeProvider struct {
+	cfg    StripeConfig
+	plans  map[string]string // price ID -> plan code
+	client *http.Client
+}
+
+func NewStripeProvider(cfg StripeConfig) (*StripeProvider, error) {
+	if cfg.SecretKey == "" || cfg.WebhookSecret == "" {
+		return nil, errors.New("stripe provider needs a secret key and a webhook secret")
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.stripe.com"
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.Tolerance <= 0 {
+		cfg.Tolerance = stripeDefaultTolerance
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = stripeDefaultTimeout
+	}
+
+	plans := make(map[string]string, len(cfg.Prices))
+	for plan, price := range cfg.Prices {
+		plans[price] = plan
+	}
+	return &StripeProvider{cfg: cfg, plans: plans, client: &http.Client{Timeout: cfg.Timeout}}, nil
+}
+
+func (p *StripeProvider) Name() string {
+	return "stripe"
+}
+
+func (p *StripeProvider) CreateCheckoutSession(ctx context.Context, req CheckoutRequest) (*CheckoutSession, error) {
+	price, ok := p.cfg.Prices[req.PlanCode]
+	if !ok {
+		return nil, ErrUnknownPlan
+	}
+
+	form := url.Values{}
+	form.Set("mode", "subscription")
+	form.Set("line_items[0][price]", price)
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("success_url", req.SuccessURL)
+	form.Set("cancel_url", req.CancelURL)
+	form.Set("client_reference_id", req.UserID)
+	if req.CustomerID != "" {
+		form.Set("customer", req.CustomerID)
+	} else if req.Email != "" {
+		form.Set("customer_email", req.Email)
+	}
+	// Webhooks identify the user and plan from the subscription's metadata
+	form.Set("subscription_data[metadata][user_id]", req.UserID)
+	form.Set("subscription_data[metadata][plan_code]", req.PlanCode)
+	if req.TrialDays > 0 {
+		form.Set("subscription_data[trial_period_days]", strconv.Itoa(req.TrialDays))
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.BaseURL+"/v1/checkout/sessions", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+p.cfg.SecretKey)
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, decodeStripeError(resp.StatusCode, body)
+	}
+
+	var out struct {
+		ID        string `json:"id"`
+		URL       string `json:"url"`
+		ExpiresAt int64  `json:"expires_at"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("decode stripe checkout session: %w", err)
+	}
+	return &CheckoutSession{ID: out.ID, URL: out.URL, ExpiresAt: time.Unix(out.ExpiresAt, 0).UTC()}, nil
+}
+
+func decodeStripeError(status int, body []byte) error {
+	var out struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	message := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &out) == nil && out.Error.Message != "" {
+		message = out.Error.Message
+	}
+	return &APIError{Provider: "stripe", StatusCode: status, Message: message}
+}
+
+type stripeEvent struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+type stripeSubscription struct {
+	ID                 string            `json:"id"`
+	Customer           string            `json:"customer"`
+	Status             string            `json:"status"`
+	CurrentPeriodStart int64             `json:"current_period_start"`
+	CurrentPeriodEnd   int64             `json:"current_period_end"`
+	TrialEnd           *int64            `json:"trial_end"`
+	CancelAtPeriodEnd  bool              `json:"cancel_at_period_end"`
+	CanceledAt         *int64            `json:"canceled_at"`
+	Metadata           map[string]string `json:"metadata"`
+	Items              struct {
+		Data []struct {
+			Price struct {
+				ID string `json:"id"`
+			} `json:"price"`
+			// Newer API versions report the period per item
+			CurrentPeriodStart int64 `json:"current_period_start"`
+			CurrentPeriodEnd   int64 `json:"current_period_end"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// stripeStatuses maps Stripe subscription statuses onto ours; incomplete
+// subscriptions are left out until their first payment succeeds
+var stripeStatuses = map[string]string{
+	"trialing":           StatusTrialing,
+	"active":             StatusActive,
+	"past_due":           StatusPastDue,
+	"unpaid":             StatusPastDue,
+	"canceled":           StatusCanceled,
+	"incomplete_expired": StatusCanceled,
+	"paused":             StatusCanceled,
+}
+
+func (p *StripeProvider) ParseEvent(payload []byte, header http.Header) (*Event, error) {
+	if err := p.verify(payload, header.Get(stripeSignatureHeader), time.Now()); err != nil {
+		return nil, err
+	}
+
+	var raw stripeEvent
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	if raw.ID == "" || raw.Type == "" {
+		return nil, fmt.Errorf("%w: missing id or type", ErrInvalidEvent)
+	}
+	event := &Event{ID: raw.ID, Type: raw.Type, CreatedAt: time.Unix(raw.Created, 0).UTC()}
+	if !strings.HasPrefix(raw.Type, "customer.subscription.") {
+		return event, nil
+	}
+
+	var sub stripeSubscription
+	if err := json.Unmarshal(raw.Data.Object, &sub); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	event.Subscription = p.subscription(sub)
+	return event, nil
+}
+
+func (p *StripeProvider) subscription(sub stripeSubscription) *Subscription {
+	result := &Subscription{
+		ID:                sub.ID,
+		CustomerID:        sub.Customer,
+		UserID:            sub.Metadata["user_id"],
+		PlanCode:          sub.Metadata["plan_code"],
+		Status:            stripeStatuses[sub.Status],
+		CancelAtPeriodEnd: sub.CancelAtPeriodEnd,
+	}
+
+	start, end := sub.CurrentPeriodStart, sub.CurrentPeriodEnd
+	if len(sub.Items.Data) > 0 {
+		item := sub.Items.Data[0]
+		// The price is authoritative: plan changes made in the billing portal keep the old metadata
+		if plan, ok := p.plans[item.Price.ID]; ok {
+			result.PlanCode = plan
+		}
+		if start == 0 && end == 0 {
+			start, end = item.CurrentPeriodStart, item.CurrentPeriodEnd
+		}
+	}
+	result.CurrentPeriodStart = time.Unix(start, 0).UTC()
+	result.CurrentPeriodEnd = time.Unix(end, 0).UTC()
+
+	if sub.TrialEnd != nil {
+		trialEnd := time.Unix(*sub.TrialEnd, 0).UTC()
+		result.TrialEnd = &trialEnd
+	}
+	if sub.CanceledAt != nil {
+		canceledAt := time.Unix(*sub.CanceledAt, 0).UTC()
+		result.CanceledAt = &canceledAt
+	}
+	return result
+}
+
+// verify checks a Stripe-Signature header ("t=<unix>,v1=<hex>,...") against the
+// body. Any v1 signature may match, which keeps webhooks working while the
+// endpoint secret is rolled.
+func (p *StripeProvider) verify(payload []byte, header string, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > p.cfg.Tolerance || age < -p.cfg.Tolerance {
+		return ErrInvalidSignature
+	}
+
+	message := append([]byte(timestamp+"."), payload...)
+	for _, signature := range signatures {
+		if validSignature(p.cfg.WebhookSecret, message, signature) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
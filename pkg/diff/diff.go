@@ -0,0 +1,124 @@
+// Package diff computes line-based differences between two texts
+package diff
+
+import "strings"
+
+// Op is the kind of change of a line
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// Line is a single line of a diff
+type Line struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// Lines returns the shortest edit script turning a into b, one entry per line
+// (Myers' algorithm, O((N+M)·D) time)
+func Lines(a, b string) []Line {
+	return compute(splitLines(a), splitLines(b))
+}
+
+func compute(a, b []string) []Line {
+	// Common prefix and suffix do not need the search
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var result []Line
+	for _, line := range a[:prefix] {
+		result = append(result, Line{Op: Equal, Text: line})
+	}
+	result = append(result, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		result = append(result, Line{Op: Equal, Text: line})
+	}
+	return result
+}
+
+func myers(a, b []string) []Line {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+
+	offset := max
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+search:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk the trace backwards to recover the edit script
+	var reversed []Line
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, Line{Op: Equal, Text: a[x]})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			y--
+			reversed = append(reversed, Line{Op: Insert, Text: b[y]})
+		} else {
+			x--
+			reversed = append(reversed, Line{Op: Delete, Text: a[x]})
+		}
+	}
+
+	result := make([]Line, len(reversed))
+	for i, line := range reversed {
+		result[len(reversed)-1-i] = line
+	}
+	return result
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,10 @@
+package errors
+
+import "errors"
+
+var (
+	ErrAssessmentNotFound       = errors.New("assessment not found")
+	ErrInvalidAssessmentWeights = errors.New("assessment weights do not add up to 100")
+	ErrInvalidAssessmentScore   = errors.New("assessment score exceeds max score")
+	ErrInvalidAssessmentDueDate = errors.New("invalid assessment due date")
+)
@@ -0,0 +1,8 @@
+package errors
+
+import "errors"
+
+var (
+	ErrConversationNotFound = errors.New("conversation not found")
+	ErrAssistantReplyFailed = errors.New("assistant could not answer")
+)
@@ -0,0 +1,10 @@
+package errors
+
+import "errors"
+
+var (
+	ErrPlanNotPurchasable    = errors.New("plan cannot be purchased")
+	ErrSubscriptionExists    = errors.New("user already has a subscription")
+	ErrInvalidWebhook        = errors.New("invalid billing webhook")
+	ErrBillingEventUnmatched = errors.New("billing event matches no subscription")
+)
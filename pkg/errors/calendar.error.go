@@ -0,0 +1,8 @@
+package errors
+
+import "errors"
+
+var (
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+	ErrInvalidTimezone      = errors.New("invalid timezone")
+)
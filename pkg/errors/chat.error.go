@@ -0,0 +1,8 @@
+package errors
+
+import "errors"
+
+var (
+	ErrChatMessageInvalid = errors.New("chat message empty or too long")
+	ErrChatFrameInvalid   = errors.New("chat frame invalid")
+)
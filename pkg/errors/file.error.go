@@ -0,0 +1,13 @@
+package errors
+
+import "errors"
+
+var (
+	ErrFileNotFound         = errors.New("file not found")
+	ErrFileTooLarge         = errors.New("file too large")
+	ErrFileQuotaExceeded    = errors.New("storage quota exceeded")
+	ErrFileTypeNotAllowed   = errors.New("file type not allowed")
+	ErrFileUploadIncomplete = errors.New("file upload incomplete")
+	ErrFileNotReady         = errors.New("file not ready")
+	ErrFileUploadNotFound   = errors.New("file upload not found")
+)
@@ -0,0 +1,12 @@
+package errors
+
+import "errors"
+
+var (
+	ErrFlashcardNotFound           = errors.New("flashcard not found")
+	ErrFlashcardGenerationNotFound = errors.New("flashcard generation not found")
+	ErrInvalidFlashcardSource      = errors.New("invalid flashcard source")
+	ErrFlashcardSourceEmpty        = errors.New("flashcard source has no text")
+	ErrInvalidFlashcardLanguage    = errors.New("invalid flashcard language")
+	ErrInvalidFlashcardOutput      = errors.New("generated flashcards are incomplete")
+)
@@ -0,0 +1,17 @@
+package errors
+
+import "errors"
+
+var (
+	ErrNoteNotFound         = errors.New("note not found")
+	ErrNoteRevisionNotFound = errors.New("note revision not found")
+	ErrInvalidNoteContent   = errors.New("invalid note content")
+	ErrNoteContentTooLarge  = errors.New("note content too large")
+	ErrInvalidNoteTags      = errors.New("invalid note tags")
+	ErrInvalidLectureDate   = errors.New("invalid lecture date")
+	ErrNoteVersionConflict  = errors.New("note changed since the given ETag")
+	ErrNoteCollabActive     = errors.New("note is open for collaborative editing")
+	ErrInvalidCollabOp      = errors.New("collaborative edit does not apply to the document")
+	ErrCollabRevisionGone   = errors.New("collaborative edit based on a revision before the snapshot")
+	ErrNoteCollabClosed     = errors.New("collaborative editing session closed")
+)
@@ -0,0 +1,9 @@
+package errors
+
+import "errors"
+
+var (
+	ErrPlanNotFound       = errors.New("plan not found")
+	ErrAIQuotaExceeded    = errors.New("ai usage quota exceeded")
+	ErrCourseLimitReached = errors.New("course limit reached")
+)
@@ -0,0 +1,13 @@
+package errors
+
+import "errors"
+
+var (
+	ErrStudyPlanNotFound      = errors.New("study plan not found")
+	ErrStudyPlanSuperseded    = errors.New("study plan was superseded")
+	ErrStudyBlockNotFound     = errors.New("study block not found")
+	ErrStudyBlockConflict     = errors.New("study block overlaps a class or another block")
+	ErrInvalidStudyBlockTime  = errors.New("invalid study block time")
+	ErrInvalidStudyWindow     = errors.New("invalid study window")
+	ErrStudyPlanNothingToPlan = errors.New("no upcoming exams or assignments to plan for")
+)
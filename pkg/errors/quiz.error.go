@@ -0,0 +1,17 @@
+package errors
+
+import "errors"
+
+var (
+	ErrQuizNotFound          = errors.New("quiz not found")
+	ErrInvalidQuizDifficulty = errors.New("invalid quiz difficulty")
+	ErrInvalidQuizType       = errors.New("invalid quiz question type")
+	ErrInvalidQuizLanguage   = errors.New("invalid quiz language")
+	ErrQuizNoNotes           = errors.New("course has no notes with text")
+	ErrQuizNotReady          = errors.New("quiz is not ready")
+	ErrQuizAttemptNotFound   = errors.New("quiz attempt not found")
+	ErrQuizAttemptSubmitted  = errors.New("quiz attempt already submitted")
+	ErrInvalidQuizAnswer     = errors.New("invalid quiz answer")
+	ErrInvalidQuizOutput     = errors.New("generated questions are malformed")
+	ErrInvalidGradingOutput  = errors.New("grading result is malformed")
+)
@@ -0,0 +1,18 @@
+package errors
+
+import "errors"
+
+var (
+	ErrReminderNotFound        = errors.New("reminder not found")
+	ErrInvalidReminderType     = errors.New("invalid reminder type")
+	ErrInvalidReminderStatus   = errors.New("invalid reminder status")
+	ErrInvalidReminderDueDate  = errors.New("invalid reminder due date")
+	ErrReminderExamFieldsOnly  = errors.New("location, weight and score are only allowed on exams")
+	ErrInvalidStatusTransition = errors.New("invalid reminder status transition")
+	ErrCourseNotFound          = errors.New("course not found")
+)
+
+var (
+	ErrNotificationSettingNotFound = errors.New("notification setting not found")
+	ErrInvalidNotificationOffsets  = errors.New("invalid notification offsets")
+)
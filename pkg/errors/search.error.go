@@ -0,0 +1,9 @@
+package errors
+
+import "errors"
+
+var (
+	ErrInvalidSearchQuery = errors.New("invalid search query")
+	ErrInvalidSearchDate  = errors.New("invalid search date")
+	ErrInvalidSearchType  = errors.New("invalid search type")
+)
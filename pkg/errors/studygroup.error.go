@@ -0,0 +1,18 @@
+package errors
+
+import "errors"
+
+var (
+	ErrStudyGroupNotFound      = errors.New("study group not found")
+	ErrStudyGroupForbidden     = errors.New("study group role does not allow this")
+	ErrInvitationInvalid       = errors.New("invitation invalid or expired")
+	ErrInvitationEmailMismatch = errors.New("invitation sent to another email")
+	ErrInvitationNotFound      = errors.New("invitation not found")
+	ErrAlreadyGroupMember      = errors.New("already a member of the study group")
+	ErrStudyGroupFull          = errors.New("study group is full")
+	ErrGroupOwnerCannotLeave   = errors.New("study group owner cannot leave")
+	ErrGroupMemberNotFound     = errors.New("study group member not found")
+	ErrGroupNoteNotFound       = errors.New("note not shared in the study group")
+	ErrGroupNoteCourseMismatch = errors.New("note belongs to another course")
+	ErrGroupNoteAlreadyShared  = errors.New("note already shared in the study group")
+)
@@ -0,0 +1,12 @@
+package errors
+
+import "errors"
+
+var (
+	ErrStudySessionNotFound    = errors.New("study session not found")
+	ErrStudySessionRunning     = errors.New("another study session is running")
+	ErrStudySessionNotRunning  = errors.New("study session already stopped")
+	ErrInvalidStudySessionTime = errors.New("invalid study session time")
+	ErrStudySessionOverlap     = errors.New("study session overlaps another session")
+	ErrInvalidStudyTimeRange   = errors.New("invalid study time range")
+)
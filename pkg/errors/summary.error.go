@@ -0,0 +1,10 @@
+package errors
+
+import "errors"
+
+var (
+	ErrSummaryNotFound        = errors.New("summary not found")
+	ErrInvalidSummaryLanguage = errors.New("invalid summary language")
+	ErrSummaryNoteEmpty       = errors.New("note has no text to summarize")
+	ErrInvalidSummaryOutput   = errors.New("summary has no bullet points")
+)
@@ -0,0 +1,13 @@
+package errors
+
+import "errors"
+
+var (
+	ErrClassSessionNotFound    = errors.New("class session not found")
+	ErrInvalidClassSessionTime = errors.New("class session must end after it starts")
+	ErrInvalidClassSessionDate = errors.New("invalid class session date range")
+	ErrClassSessionConflict    = errors.New("class session overlaps another session")
+	ErrInvalidSessionException = errors.New("date is not an occurrence of the class session")
+	ErrInvalidTimetableImport  = errors.New("invalid iCalendar file")
+	ErrSemesterNotFound        = errors.New("semester not found")
+)
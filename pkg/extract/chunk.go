@@ -0,0 +1,166 @@
+package extract
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Chunk is a window of text that may span several pages
+type Chunk struct {
+	Index     int
+	PageStart int
+	PageEnd   int
+	Text      string
+}
+
+// segment is a piece of a page that is never split across chunks
+type segment struct {
+	page int
+	text string
+}
+
+// Split packs the pages' lines into chunks of at most size runes. Consecutive
+// chunks share about overlap runes so that sentences at a boundary keep their
+// context. Lines longer than size are cut at sentence or word boundaries.
+func Split(pages []Page, size, overlap int) []Chunk {
+	if size <= 0 {
+		return nil
+	}
+	if overlap >= size/2 {
+		overlap = size / 2
+	}
+
+	var segments []segment
+	for _, page := range pages {
+		for _, line := range strings.Split(page.Text, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			for _, piece := range splitLong(line, size) {
+				segments = append(segments, segment{page: page.Number, text: piece})
+			}
+		}
+	}
+
+	var chunks []Chunk
+	var current []segment
+	length := 0
+	fresh := 0 // segments in current that are not overlap from the previous chunk
+
+	flush := func() {
+		if fresh == 0 {
+			return
+		}
+		texts := make([]string, len(current))
+		for i, seg := range current {
+			texts[i] = seg.text
+		}
+		chunks = append(chunks, Chunk{
+			Index:     len(chunks),
+			PageStart: current[0].page,
+			PageEnd:   current[len(current)-1].page,
+			Text:      strings.Join(texts, "\n"),
+		})
+	}
+
+	for _, seg := range segments {
+		segLength := utf8.RuneCountInString(seg.text)
+		if len(current) > 0 && length+1+segLength > size {
+			flush()
+			current = tail(current, min(overlap, size-1-segLength))
+			length = joinedLength(current)
+			fresh = 0
+		}
+		if len(current) > 0 {
+			length++
+		}
+		current = append(current, seg)
+		length += segLength
+		fresh++
+	}
+	flush()
+	return chunks
+}
+
+// tail returns the trailing segments that fit in limit runes, cutting the
+// last segment at a word boundary when it alone is longer
+func tail(segments []segment, limit int) []segment {
+	if limit <= 0 || len(segments) == 0 {
+		return nil
+	}
+
+	length := 0
+	start := len(segments)
+	for start > 0 {
+		next := utf8.RuneCountInString(segments[start-1].text)
+		if start < len(segments) {
+			next++
+		}
+		if length+next > limit {
+			break
+		}
+		length += next
+		start--
+	}
+	if start < len(segments) {
+		return append([]segment(nil), segments[start:]...)
+	}
+
+	last := segments[len(segments)-1]
+	runes := []rune(last.text)
+	cut := len(runes) - limit
+	for cut < len(runes) && runes[cut] != ' ' {
+		cut++
+	}
+	if text := strings.TrimSpace(string(runes[cut:])); text != "" {
+		return []segment{{page: last.page, text: text}}
+	}
+	return nil
+}
+
+func joinedLength(segments []segment) int {
+	length := 0
+	for i, seg := range segments {
+		if i > 0 {
+			length++
+		}
+		length += utf8.RuneCountInString(seg.text)
+	}
+	return length
+}
+
+// splitLong cuts a line into pieces of at most size runes, preferring the
+// end of a sentence, then a space, then a hard cut
+func splitLong(line string, size int) []string {
+	var pieces []string
+	runes := []rune(line)
+	for len(runes) > size {
+		cut := lastBoundary(runes[:size], func(r rune, next rune) bool {
+			return (r == '.' || r == '!' || r == '?' || r == ';') && next == ' '
+		})
+		if cut <= size/2 {
+			cut = lastBoundary(runes[:size], func(r rune, next rune) bool { return next == ' ' })
+		}
+		if cut <= 0 {
+			cut = size
+		}
+		pieces = append(pieces, strings.TrimSpace(string(runes[:cut])))
+		runes = []rune(strings.TrimSpace(string(runes[cut:])))
+	}
+	if len(runes) > 0 {
+		pieces = append(pieces, string(runes))
+	}
+	return pieces
+}
+
+// lastBoundary returns the position just after the last rune r for which
+// match(r, next) holds, or 0
+func lastBoundary(runes []rune, match func(r, next rune) bool) int {
+	for i := len(runes) - 2; i >= 0; i-- {
+		if match(runes[i], runes[i+1]) {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,111 @@
+package extract
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxDocumentXML bounds the decompressed size of word/document.xml (zip bombs)
+const maxDocumentXML = 64 << 20
+
+// DOCX extracts the body text of a Word document. Page boundaries come from
+// the page breaks Word records when it last laid out the document
+// (w:lastRenderedPageBreak) and from explicit page breaks; documents saved
+// by other editors often have neither and come out as a single page.
+func DOCX(r io.ReaderAt, size int64) ([]Page, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	var document *zip.File
+	for _, file := range archive.File {
+		if file.Name == "word/document.xml" {
+			document = file
+			break
+		}
+	}
+	if document == nil {
+		return nil, fmt.Errorf("%w: word/document.xml is missing", ErrMalformed)
+	}
+
+	body, err := document.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	defer body.Close()
+
+	return parseDocumentXML(io.LimitReader(body, maxDocumentXML))
+}
+
+func parseDocumentXML(r io.Reader) ([]Page, error) {
+	decoder := xml.NewDecoder(r)
+	var pages []Page
+	var text strings.Builder
+	inText := false
+
+	newPage := func() {
+		pages = append(pages, Page{Number: len(pages) + 1, Text: normalize(text.String())})
+		text.Reset()
+	}
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteString("\t")
+			case "cr":
+				text.WriteString("\n")
+			case "br":
+				if attr(element, "type") == "page" {
+					newPage()
+				} else {
+					text.WriteString("\n")
+				}
+			case "lastRenderedPageBreak":
+				// Word writes one at the start of every page but the first
+				newPage()
+			}
+		case xml.EndElement:
+			switch element.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteString("\n")
+			case "tc":
+				text.WriteString("\t")
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(element)
+			}
+		}
+	}
+
+	newPage()
+	return pages, nil
+}
+
+func attr(element xml.StartElement, name string) string {
+	for _, a := range element.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+// Package extract pulls plain text out of uploaded documents, page by page,
+// and splits it into chunks sized for search and language model prompts
+package extract
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrUnsupported = errors.New("text extraction is not supported for this file type")
+	ErrMalformed   = errors.New("malformed document")
+)
+
+// Page is the text of one page. Numbers start at 1.
+type Page struct {
+	Number int
+	Text   string
+}
+
+// Extract dispatches on the sniffed MIME type of the file
+func Extract(mimeType string, r io.ReaderAt, size int64) ([]Page, error) {
+	switch mimeType {
+	case "application/pdf":
+		return PDF(r, size)
+	case "application/vnd.openxmlformats-officedocument.wordprocessingml.document":
+		return DOCX(r, size)
+	case "text/plain", "text/markdown":
+		return Text(io.NewSectionReader(r, 0, size))
+	}
+	return nil, ErrUnsupported
+}
+
+// Text reads a plain text file as a single page
+func Text(r io.Reader) ([]Page, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return []Page{{Number: 1, Text: normalize(string(data))}}, nil
+}
+
+// normalize trims trailing spaces, collapses runs of blank lines and drops
+// control characters other than newlines and tabs
+func normalize(text string) string {
+	text = strings.ToValidUTF8(text, "")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var b strings.Builder
+	blank := 0
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRightFunc(strings.Map(func(r rune) rune {
+			if r == '\t' || !unicode.IsControl(r) {
+				return r
+			}
+			return -1
+		}, line), unicode.IsSpace)
+
+		if line == "" {
+			blank++
+			continue
+		}
+		if b.Len() > 0 {
+			if blank > 0 {
+				b.WriteString("\n\n")
+			} else {
+				b.WriteString("\n")
+			}
+		}
+		blank = 0
+		b.WriteString(line)
+	}
+	return b.String()
+}
@@ -0,0 +1,37 @@
+package extract
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// PDF extracts the text layer of every page. Scanned PDFs without a text
+// layer yield empty pages.
+func PDF(r io.ReaderAt, size int64) (pages []Page, err error) {
+	// The parser panics on some malformed files
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			pages, err = nil, fmt.Errorf("%w: %v", ErrMalformed, recovered)
+		}
+	}()
+
+	reader, err := pdf.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	for number := 1; number <= reader.NumPage(); number++ {
+		page := reader.Page(number)
+		if page.V.IsNull() {
+			continue
+		}
+		text, err := page.GetPlainText(nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: page %d: %v", ErrMalformed, number, err)
+		}
+		pages = append(pages, Page{Number: number, Text: normalize(text)})
+	}
+	return pages, nil
+}
@@ -0,0 +1,156 @@
+// Package filetype identifies uploaded files from their content rather than
+// the name or Content-Type the client claims
+package filetype
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// SniffLen is the number of leading bytes Detect looks at
+const SniffLen = 512
+
+var (
+	ErrExecutable   = errors.New("executable content is not allowed")
+	ErrUnsupported  = errors.New("unsupported file type")
+	ErrTypeMismatch = errors.New("file content does not match its extension")
+)
+
+const (
+	PDF  = "application/pdf"
+	DOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	PPTX = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	XLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	PNG  = "image/png"
+	JPEG = "image/jpeg"
+	GIF  = "image/gif"
+	WEBP = "image/webp"
+	TEXT = "text/plain"
+	MD   = "text/markdown"
+)
+
+// extensions lists the allowed extensions of every accepted type
+var extensions = map[string][]string{
+	PDF:  {".pdf"},
+	DOCX: {".docx"},
+	PPTX: {".pptx"},
+	XLSX: {".xlsx"},
+	PNG:  {".png"},
+	JPEG: {".jpg", ".jpeg"},
+	GIF:  {".gif"},
+	WEBP: {".webp"},
+	TEXT: {".txt"},
+	MD:   {".md", ".markdown"},
+}
+
+// executableMagic are the signatures of native executables and scripts
+var executableMagic = [][]byte{
+	[]byte("MZ"),             // Windows PE / DOS
+	[]byte("\x7fELF"),        // Linux ELF
+	{0xfe, 0xed, 0xfa, 0xce}, // Mach-O 32-bit
+	{0xfe, 0xed, 0xfa, 0xcf}, // Mach-O 64-bit
+	{0xce, 0xfa, 0xed, 0xfe}, // Mach-O 32-bit, little endian
+	{0xcf, 0xfa, 0xed, 0xfe}, // Mach-O 64-bit, little endian
+	{0xca, 0xfe, 0xba, 0xbe}, // Mach-O universal / Java class
+	[]byte("#!"),             // shebang scripts
+	[]byte("\x00asm"),        // WebAssembly
+	[]byte("dex\n"),          // Android DEX
+	{0xd0, 0xcf, 0x11, 0xe0, 0xa1, 0xb1, 0x1a, 0xe1}, // OLE2 (legacy Office, MSI), often macro carriers
+}
+
+// Detect returns the MIME type of a file from its first bytes (up to SniffLen)
+// and checks that the file name's extension belongs to that type
+func Detect(head []byte, filename string) (string, error) {
+	if len(head) > SniffLen {
+		head = head[:SniffLen]
+	}
+	for _, magic := range executableMagic {
+		if bytes.HasPrefix(head, magic) {
+			return "", ErrExecutable
+		}
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	mimeType := sniff(head, ext)
+	if mimeType == "" {
+		return "", ErrUnsupported
+	}
+	for _, allowed := range extensions[mimeType] {
+		if ext == allowed {
+			return mimeType, nil
+		}
+	}
+	return "", ErrTypeMismatch
+}
+
+func sniff(head []byte, ext string) string {
+	detected, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	switch detected {
+	case PDF, PNG, JPEG, GIF, WEBP:
+		return detected
+	case "application/zip":
+		return officeType(head, ext)
+	case "text/plain":
+		if looksLikeMarkup(head) {
+			return ""
+		}
+		if ext == ".md" || ext == ".markdown" {
+			return MD
+		}
+		return TEXT
+	}
+	return ""
+}
+
+// officeType recognises Office Open XML packages. They are zip files whose
+// entries name the document part, e.g. word/document.xml.
+func officeType(head []byte, ext string) string {
+	if !bytes.Contains(head, []byte("[Content_Types].xml")) && !bytes.Contains(head, []byte("_rels/")) && !bytes.Contains(head, []byte("docProps/")) {
+		return ""
+	}
+	switch {
+	case bytes.Contains(head, []byte("word/")):
+		return DOCX
+	case bytes.Contains(head, []byte("ppt/")):
+		return PPTX
+	case bytes.Contains(head, []byte("xl/")):
+		return XLSX
+	}
+	// The first entries do not always reveal the document kind; trust the
+	// extension as long as the package itself looks like Office Open XML
+	for _, mimeType := range []string{DOCX, PPTX, XLSX} {
+		if extensions[mimeType][0] == ext {
+			return mimeType
+		}
+	}
+	return ""
+}
+
+// looksLikeMarkup catches HTML/SVG/script payloads that DetectContentType
+// may report as text when the markup does not start the file
+func looksLikeMarkup(head []byte) bool {
+	lower := bytes.ToLower(head)
+	for _, marker := range []string{"<script", "<html", "<svg", "<?php", "<iframe"} {
+		if bytes.Contains(lower, []byte(marker)) {
+			return true
+		}
+	}
+	return false
+}
+
+// FromExtension returns the type an accepted file with this name must have.
+// It is used to announce uploads before any content is available.
+func FromExtension(filename string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	for mimeType, allowed := range extensions {
+		for _, candidate := range allowed {
+			if ext == candidate {
+				return mimeType, nil
+			}
+		}
+	}
+	return "", ErrUnsupported
+}
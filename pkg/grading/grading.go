@@ -0,0 +1,140 @@
+// Package grading converts percentage scores to the 4-point scale and
+// forecasts final course grades from partially graded assessments
+package grading
+
+import "math"
+
+const (
+	// Confidence is the probability a forecast's Low to High range holds the final grade
+	Confidence = 0.8
+	// z80 is the z-score of a two-sided 80% interval
+	z80 = 1.2816
+	// minSpread is the standard deviation assumed for future scores when the
+	// graded ones agree too well to tell, in percentage points
+	minSpread = 10.0
+)
+
+// Step is the lowest percentage that earns the points
+type Step struct {
+	Percent float64
+	Points  float64
+}
+
+// scale follows the common 10-point to 4-point conversion (A+ at 9.0, A at
+// 8.5 ... D at 4.0), expressed in percent
+var scale = []Step{
+	{90, 4.0},
+	{85, 3.7},
+	{80, 3.5},
+	{70, 3.0},
+	{65, 2.5},
+	{55, 2.0},
+	{50, 1.5},
+	{40, 1.0},
+}
+
+// Scale returns the steps of the 4-point scale, best first
+func Scale() []Step {
+	return append([]Step(nil), scale...)
+}
+
+// Points converts a percentage to the 4-point scale
+func Points(percent float64) float64 {
+	for _, step := range scale {
+		if percent >= step.Percent {
+			return step.Points
+		}
+	}
+	return 0
+}
+
+// Results summarizes the graded part of a course
+type Results struct {
+	Weight float64 // percent of the final grade already graded
+	Mean   float64 // weighted mean score of the graded part, in percent
+	StdDev float64 // weighted standard deviation of those scores
+	Count  int     // graded assessments
+}
+
+// Forecast is a projected final grade in percent. Low and High bound an 80%
+// interval assuming the remaining assessments go like the graded ones; Min
+// and Max are reached by scoring 0 or 100 on everything left.
+type Forecast struct {
+	Predicted float64
+	Low       float64
+	High      float64
+	Min       float64
+	Max       float64
+}
+
+// Project forecasts the final grade. The spread of future scores is the
+// spread of the graded ones, widened when there are few of them.
+func Project(results Results) Forecast {
+	graded := clamp(results.Weight, 0, 100)
+	remaining := 100 - graded
+	mean := clamp(results.Mean, 0, 100)
+	if results.Count == 0 || graded == 0 {
+		return Forecast{Low: 0, High: 100, Min: 0, Max: 100, Predicted: 50}
+	}
+
+	spread := math.Max(results.StdDev, minSpread) * math.Sqrt(1+1/float64(results.Count))
+	final := func(future float64) float64 {
+		return (mean*graded + clamp(future, 0, 100)*remaining) / 100
+	}
+	return Forecast{
+		Predicted: final(mean),
+		Low:       final(mean - z80*spread),
+		High:      final(mean + z80*spread),
+		Min:       final(0),
+		Max:       final(100),
+	}
+}
+
+// Component is one graded part of a course, such as a midterm
+type Component struct {
+	Weight float64  // percent of the final grade
+	Score  *float64 // percent obtained, nil until graded
+}
+
+// Standing is how far the grading of a course has come
+type Standing struct {
+	GradedWeight float64 // percent of the final grade already graded
+	Secured      float64 // percent of the final grade already earned
+	Current      float64 // weighted average of the graded components, 0 before any is graded
+}
+
+// Tally sums up the graded components of a course
+func Tally(components []Component) Standing {
+	var standing Standing
+	for _, component := range components {
+		if component.Score == nil {
+			continue
+		}
+		standing.GradedWeight += component.Weight
+		standing.Secured += component.Weight * *component.Score / 100
+	}
+	if standing.GradedWeight > 0 {
+		standing.Current = standing.Secured / standing.GradedWeight * 100
+	}
+	return standing
+}
+
+// Needed returns the average score in percent the ungraded weight needs
+// for the final grade to reach target, 0 when target is already secured.
+// It reports false when even 100 on everything left falls short.
+func Needed(standing Standing, target float64) (float64, bool) {
+	missing := target - standing.Secured
+	if missing <= 0 {
+		return 0, true
+	}
+	remaining := 100 - standing.GradedWeight
+	if remaining <= 0 {
+		return 0, false
+	}
+	needed := missing / remaining * 100
+	return needed, needed <= 100
+}
+
+func clamp(value, low, high float64) float64 {
+	return math.Min(math.Max(value, low), high)
+}
@@ -0,0 +1,240 @@
+// Package ical writes RFC 5545 iCalendar documents
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateTimeLayout = "20060102T150405"
+	maxLineOctets  = 75
+)
+
+// Calendar is a VCALENDAR with the events it contains
+type Calendar struct {
+	ProdID   string
+	Name     string         // X-WR-CALNAME shown by calendar apps
+	Location *time.Location // wall-clock times of local events are written in this zone
+	Events   []Event
+}
+
+// Event is a single VEVENT. Local events are written as wall-clock times in the
+// calendar's location; all other times are written in UTC. AllDay and
+// RecurrenceID are only filled in by Parse.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Categories  []string
+	Start       time.Time
+	End         time.Time
+	Local       bool
+	RRule       string      // recurrence rule without the RRULE: prefix
+	ExDates     []time.Time // cancelled occurrences, same form as Start
+	Stamp       time.Time
+
+	AllDay       bool
+	RecurrenceID time.Time // set on overrides of a single occurrence of a recurring event
+}
+
+// Weekday returns the two-letter BYDAY code of a weekday
+func Weekday(day time.Weekday) string {
+	return [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}[day]
+}
+
+// FormatUTC formats an instant as a UTC DATE-TIME, as required by RRULE UNTIL
+func FormatUTC(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout) + "Z"
+}
+
+// Encode renders the calendar with CRLF line endings and folded content lines
+func (c *Calendar) Encode() []byte {
+	w := &writer{}
+	loc := c.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + c.ProdID)
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		w.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	if loc != time.UTC {
+		w.line("X-WR-TIMEZONE:" + loc.String())
+		if from, to, ok := c.localYears(); ok {
+			writeTimezone(w, loc, from, to)
+		}
+	}
+
+	for i := range c.Events {
+		c.writeEvent(w, &c.Events[i], loc)
+	}
+
+	w.line("END:VCALENDAR")
+	return w.buf.Bytes()
+}
+
+func (c *Calendar) writeEvent(w *writer, event *Event, loc *time.Location) {
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + event.UID)
+	w.line("DTSTAMP:" + FormatUTC(event.Stamp))
+	w.line(dateTimeProperty("DTSTART", event.Start, event.Local, loc))
+	if !event.End.IsZero() {
+		w.line(dateTimeProperty("DTEND", event.End, event.Local, loc))
+	}
+	if event.RRule != "" {
+		w.line("RRULE:" + event.RRule)
+	}
+	for _, exDate := range event.ExDates {
+		w.line(dateTimeProperty("EXDATE", exDate, event.Local, loc))
+	}
+	w.line("SUMMARY:" + escapeText(event.Summary))
+	if event.Description != "" {
+		w.line("DESCRIPTION:" + escapeText(event.Description))
+	}
+	if event.Location != "" {
+		w.line("LOCATION:" + escapeText(event.Location))
+	}
+	if len(event.Categories) > 0 {
+		categories := make([]string, len(event.Categories))
+		for i, category := range event.Categories {
+			categories[i] = escapeText(category)
+		}
+		w.line("CATEGORIES:" + strings.Join(categories, ","))
+	}
+	w.line("END:VEVENT")
+}
+
+// localYears returns the span of years covered by local events
+func (c *Calendar) localYears() (int, int, bool) {
+	from, to, found := 0, 0, false
+	for _, event := range c.Events {
+		if !event.Local {
+			continue
+		}
+		year := event.Start.Year()
+		if !found || year < from {
+			from = year
+		}
+		if !found || year > to {
+			to = year
+		}
+		found = true
+	}
+	// Recurring events run past their first year; cover the following year too
+	return from, to + 1, found
+}
+
+func dateTimeProperty(name string, t time.Time, local bool, loc *time.Location) string {
+	if local && loc != time.UTC {
+		return fmt.Sprintf("%s;TZID=%s:%s", name, loc.String(), t.Format(dateTimeLayout))
+	}
+	if local {
+		return name + ":" + t.Format(dateTimeLayout) + "Z"
+	}
+	return name + ":" + FormatUTC(t)
+}
+
+// writeTimezone emits a VTIMEZONE with one observance per UTC offset change
+// between the start of fromYear and the end of toYear
+func writeTimezone(w *writer, loc *time.Location, fromYear, toYear int) {
+	start := time.Date(fromYear, 1, 1, 0, 0, 0, 0, loc)
+	end := time.Date(toYear+1, 1, 1, 0, 0, 0, 0, loc)
+
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + loc.String())
+
+	_, offset := start.Zone()
+	writeObservance(w, start, offset, offset)
+
+	// Scan day by day and binary search the exact instant of each change
+	for day := start; day.Before(end); {
+		next := day.Add(24 * time.Hour)
+		_, nextOffset := next.Zone()
+		if nextOffset != offset {
+			lo, hi := day, next
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if _, midOffset := mid.Zone(); midOffset == offset {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			writeObservance(w, hi, offset, nextOffset)
+			offset = nextOffset
+		}
+		day = next
+	}
+
+	w.line("END:VTIMEZONE")
+}
+
+func writeObservance(w *writer, onset time.Time, fromOffset, toOffset int) {
+	kind := "STANDARD"
+	if onset.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	name, _ := onset.Zone()
+
+	// DTSTART is the onset expressed in the local time that was in effect before it
+	local := onset.UTC().Add(time.Duration(fromOffset) * time.Second)
+
+	w.line("BEGIN:" + kind)
+	w.line("DTSTART:" + local.Format(dateTimeLayout))
+	w.line("TZOFFSETFROM:" + formatOffset(fromOffset))
+	w.line("TZOFFSETTO:" + formatOffset(toOffset))
+	w.line("TZNAME:" + name)
+	w.line("END:" + kind)
+}
+
+func formatOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign = '-'
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+// escapeText escapes a TEXT value (RFC 5545 section 3.3.11)
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+type writer struct {
+	buf bytes.Buffer
+}
+
+// line writes a content line folded at 75 octets without splitting UTF-8 characters
+func (w *writer) line(content string) {
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.buf.WriteString(content[:cut])
+		w.buf.WriteString("\r\n ")
+		content = content[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = maxLineOctets - 1
+	}
+	w.buf.WriteString(content)
+	w.buf.WriteString("\r\n")
+}
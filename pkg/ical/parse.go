@@ -0,0 +1,356 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "20060102"
+
+var ErrInvalidCalendar = errors.New("not an iCalendar document")
+
+// Rule is a parsed RRULE. Only the parts needed for timetables are kept.
+type Rule struct {
+	Freq     string // DAILY, WEEKLY, MONTHLY, ...
+	Interval int
+	ByDay    []time.Weekday
+	Until    time.Time // zero when unbounded
+	Count    int       // zero when unbounded
+}
+
+// Parse reads an iCalendar document. Floating times and unknown TZIDs are
+// interpreted in defaultLoc (or in the calendar's X-WR-TIMEZONE when present).
+func Parse(r io.Reader, defaultLoc *time.Location) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	if defaultLoc == nil {
+		defaultLoc = time.UTC
+	}
+
+	calendar := &Calendar{Location: defaultLoc}
+	var stack []string
+	var event *Event
+	var eventLine int
+	seenCalendar := false
+
+	for i, raw := range lines {
+		if raw == "" {
+			continue
+		}
+		prop, err := parseProperty(raw)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			component := strings.ToUpper(prop.value)
+			if len(stack) == 0 && component != "VCALENDAR" {
+				return nil, ErrInvalidCalendar
+			}
+			stack = append(stack, component)
+			seenCalendar = true
+			if component == "VEVENT" && len(stack) == 2 {
+				event, eventLine = &Event{}, i+1
+			}
+			continue
+		case "END":
+			if len(stack) == 0 {
+				return nil, ErrInvalidCalendar
+			}
+			if len(stack) == 2 && stack[1] == "VEVENT" && event != nil {
+				if event.Start.IsZero() {
+					return nil, fmt.Errorf("line %d: event %q has no DTSTART", eventLine, event.UID)
+				}
+				calendar.Events = append(calendar.Events, *event)
+				event = nil
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		switch {
+		case len(stack) == 1:
+			switch prop.name {
+			case "PRODID":
+				calendar.ProdID = prop.value
+			case "X-WR-CALNAME":
+				calendar.Name = unescapeText(prop.value)
+			case "X-WR-TIMEZONE":
+				if loc, err := time.LoadLocation(prop.value); err == nil {
+					calendar.Location, defaultLoc = loc, loc
+				}
+			}
+		case len(stack) == 2 && event != nil:
+			if err := event.setProperty(prop, defaultLoc); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+	}
+
+	if !seenCalendar || len(stack) != 0 {
+		return nil, ErrInvalidCalendar
+	}
+	return calendar, nil
+}
+
+func (e *Event) setProperty(prop property, loc *time.Location) error {
+	var err error
+	switch prop.name {
+	case "UID":
+		e.UID = prop.value
+	case "SUMMARY":
+		e.Summary = unescapeText(prop.value)
+	case "DESCRIPTION":
+		e.Description = unescapeText(prop.value)
+	case "LOCATION":
+		e.Location = unescapeText(prop.value)
+	case "CATEGORIES":
+		for _, category := range splitText(prop.value) {
+			e.Categories = append(e.Categories, unescapeText(category))
+		}
+	case "DTSTART":
+		e.Start, e.AllDay, err = parseDateTime(prop, prop.value, loc)
+	case "DTEND":
+		e.End, _, err = parseDateTime(prop, prop.value, loc)
+	case "DURATION":
+		var duration time.Duration
+		if duration, err = parseDuration(prop.value); err == nil && !e.Start.IsZero() && e.End.IsZero() {
+			e.End = e.Start.Add(duration)
+		}
+	case "RRULE":
+		e.RRule = prop.value
+	case "EXDATE":
+		for _, value := range strings.Split(prop.value, ",") {
+			var exDate time.Time
+			if exDate, _, err = parseDateTime(prop, value, loc); err != nil {
+				break
+			}
+			e.ExDates = append(e.ExDates, exDate)
+		}
+	case "RECURRENCE-ID":
+		e.RecurrenceID, _, err = parseDateTime(prop, prop.value, loc)
+	case "DTSTAMP":
+		e.Stamp, _, err = parseDateTime(prop, prop.value, time.UTC)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", prop.name, err)
+	}
+	return nil
+}
+
+// ParseRule parses an RRULE value such as FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE
+func ParseRule(value string, loc *time.Location) (*Rule, error) {
+	rule := &Rule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(val)
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", val)
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", val)
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, _, err := parseDateTime(property{}, val, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UNTIL %q", val)
+			}
+			rule.Until = until
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				// Ordinal prefixes (e.g. 1MO) only make sense for monthly rules; keep the weekday
+				day = strings.TrimLeft(strings.ToUpper(day), "+-0123456789")
+				weekday, ok := parseWeekday(day)
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY %q", val)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		}
+	}
+	if rule.Freq == "" {
+		return nil, errors.New("missing FREQ")
+	}
+	return rule, nil
+}
+
+func parseWeekday(code string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if Weekday(day) == code {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseProperty splits a content line into name, parameters and value,
+// honouring quoted parameter values that may contain ':' or ';'
+func parseProperty(line string) (property, error) {
+	prop := property{params: map[string]string{}}
+	inQuotes := false
+	start := 0
+	var segments []string
+
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ';':
+			if !inQuotes {
+				segments = append(segments, line[start:i])
+				start = i + 1
+			}
+		case ':':
+			if !inQuotes {
+				segments = append(segments, line[start:i])
+				prop.value = line[i+1:]
+				prop.name = strings.ToUpper(segments[0])
+				for _, param := range segments[1:] {
+					key, val, _ := strings.Cut(param, "=")
+					prop.params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+				}
+				return prop, nil
+			}
+		}
+	}
+	return prop, fmt.Errorf("malformed content line %q", line)
+}
+
+// parseDateTime parses DATE, UTC DATE-TIME, DATE-TIME with TZID and floating DATE-TIME values
+func parseDateTime(prop property, value string, loc *time.Location) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if prop.params["VALUE"] == "DATE" || len(value) == len(dateLayout) {
+		date, err := time.ParseInLocation(dateLayout, value, loc)
+		return date, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(dateTimeLayout, strings.TrimSuffix(value, "Z"))
+		return t, false, err
+	}
+
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if zone, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			loc = zone
+		}
+	}
+	t, err := time.ParseInLocation(dateTimeLayout, value, loc)
+	return t, false, err
+}
+
+// parseDuration parses RFC 5545 durations such as PT1H30M, P1D or -PT15M
+func parseDuration(value string) (time.Duration, error) {
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(value, "-"):
+		sign, value = -1, value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+	if !strings.HasPrefix(value, "P") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	units := map[byte]time.Duration{
+		'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour,
+		'H': time.Hour, 'M': time.Minute, 'S': time.Second,
+	}
+	var total time.Duration
+	number := 0
+	digits := false
+	for i := 1; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c >= '0' && c <= '9':
+			number = number*10 + int(c-'0')
+			digits = true
+		case c == 'T':
+		case units[c] != 0 && digits:
+			total += time.Duration(number) * units[c]
+			number, digits = 0, false
+		default:
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+	}
+	return sign * total, nil
+}
+
+func unescapeText(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			i++
+			switch value[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(value[i])
+			}
+			continue
+		}
+		b.WriteByte(value[i])
+	}
+	return b.String()
+}
+
+// splitText splits a multi-valued TEXT property on unescaped commas
+func splitText(value string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' {
+			i++
+			continue
+		}
+		if value[i] == ',' {
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// unfold joins folded content lines; continuation lines start with a space or tab
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
@@ -0,0 +1,217 @@
+// Package ot implements operational transformation over sequences, in the
+// retain/insert/delete model of ot.js. An operation walks a whole sequence
+// from start to end, so it only applies to sequences of its base length.
+package ot
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidOperation = errors.New("invalid operation")
+	// ErrLengthMismatch means the operation was made for a sequence of another length
+	ErrLengthMismatch = errors.New("operation does not match the sequence length")
+)
+
+// Component is one step of an operation; exactly one field is set
+type Component[T any] struct {
+	Retain int `json:"retain,omitempty"` // keep the next elements
+	Insert []T `json:"insert,omitempty"` // insert elements at the current position
+	Delete int `json:"delete,omitempty"` // remove the next elements
+}
+
+// Operation is a list of components applied left to right
+type Operation[T any] []Component[T]
+
+// Validate checks that every component does exactly one thing
+func (op Operation[T]) Validate() error {
+	for i, c := range op {
+		set := 0
+		if c.Retain != 0 {
+			set++
+		}
+		if len(c.Insert) > 0 {
+			set++
+		}
+		if c.Delete != 0 {
+			set++
+		}
+		if set != 1 || c.Retain < 0 || c.Delete < 0 {
+			return fmt.Errorf("%w: component %d must retain, insert or delete", ErrInvalidOperation, i)
+		}
+	}
+	return nil
+}
+
+// BaseLen is the length of the sequences the operation applies to
+func (op Operation[T]) BaseLen() int {
+	n := 0
+	for _, c := range op {
+		n += c.Retain + c.Delete
+	}
+	return n
+}
+
+// TargetLen is the length of the sequence the operation produces
+func (op Operation[T]) TargetLen() int {
+	n := 0
+	for _, c := range op {
+		n += c.Retain + len(c.Insert)
+	}
+	return n
+}
+
+// Apply returns the sequence after the operation. seq is not modified.
+func Apply[T any](seq []T, op Operation[T]) ([]T, error) {
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+	if op.BaseLen() != len(seq) {
+		return nil, fmt.Errorf("%w: operation spans %d elements, sequence has %d", ErrLengthMismatch, op.BaseLen(), len(seq))
+	}
+
+	result := make([]T, 0, op.TargetLen())
+	pos := 0
+	for _, c := range op {
+		switch {
+		case c.Retain > 0:
+			result = append(result, seq[pos:pos+c.Retain]...)
+			pos += c.Retain
+		case len(c.Insert) > 0:
+			result = append(result, c.Insert...)
+		default:
+			pos += c.Delete
+		}
+	}
+	return result, nil
+}
+
+// Transform takes two operations made concurrently on the same sequence and
+// returns a' and b' such that applying a then b' gives the same result as
+// applying b then a'. When both insert at the same position, a's elements
+// come first.
+func Transform[T any](a, b Operation[T]) (Operation[T], Operation[T], error) {
+	if err := a.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if err := b.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if a.BaseLen() != b.BaseLen() {
+		return nil, nil, fmt.Errorf("%w: operations span %d and %d elements", ErrLengthMismatch, a.BaseLen(), b.BaseLen())
+	}
+
+	var aPrime, bPrime builder[T]
+	ia, ib := newCursor(a), newCursor(b)
+	for !ia.done() || !ib.done() {
+		// Inserts do not consume the base sequence, so they go first
+		if items := ia.insert(); items != nil {
+			aPrime.insert(items)
+			bPrime.retain(len(items))
+			ia.next()
+			continue
+		}
+		if items := ib.insert(); items != nil {
+			aPrime.retain(len(items))
+			bPrime.insert(items)
+			ib.next()
+			continue
+		}
+
+		n := min(ia.remaining, ib.remaining)
+		switch {
+		case ia.retaining() && ib.retaining():
+			aPrime.retain(n)
+			bPrime.retain(n)
+		case ia.retaining():
+			// b deletes what a keeps
+			bPrime.delete(n)
+		case ib.retaining():
+			aPrime.delete(n)
+		}
+		// Both deleting the same elements leaves nothing to do
+		ia.consume(n)
+		ib.consume(n)
+	}
+	return aPrime.op, bPrime.op, nil
+}
+
+// cursor walks the components of an operation, splitting retains and
+// deletes as the other operation requires
+type cursor[T any] struct {
+	op        Operation[T]
+	index     int
+	remaining int // of the current retain or delete
+}
+
+func newCursor[T any](op Operation[T]) *cursor[T] {
+	c := &cursor[T]{op: op, index: -1}
+	c.next()
+	return c
+}
+
+func (c *cursor[T]) done() bool {
+	return c.index >= len(c.op)
+}
+
+func (c *cursor[T]) next() {
+	c.index++
+	if !c.done() {
+		c.remaining = c.op[c.index].Retain + c.op[c.index].Delete
+	}
+}
+
+func (c *cursor[T]) insert() []T {
+	if c.done() || len(c.op[c.index].Insert) == 0 {
+		return nil
+	}
+	return c.op[c.index].Insert
+}
+
+func (c *cursor[T]) retaining() bool {
+	return c.op[c.index].Retain > 0
+}
+
+func (c *cursor[T]) consume(n int) {
+	c.remaining -= n
+	if c.remaining == 0 {
+		c.next()
+	}
+}
+
+// builder appends components, merging neighbours of the same kind
+type builder[T any] struct {
+	op Operation[T]
+}
+
+func (b *builder[T]) last() *Component[T] {
+	if len(b.op) == 0 {
+		return nil
+	}
+	return &b.op[len(b.op)-1]
+}
+
+func (b *builder[T]) retain(n int) {
+	if last := b.last(); last != nil && last.Retain > 0 {
+		last.Retain += n
+		return
+	}
+	b.op = append(b.op, Component[T]{Retain: n})
+}
+
+func (b *builder[T]) insert(items []T) {
+	if last := b.last(); last != nil && len(last.Insert) > 0 {
+		last.Insert = append(append([]T{}, last.Insert...), items...)
+		return
+	}
+	b.op = append(b.op, Component[T]{Insert: items})
+}
+
+func (b *builder[T]) delete(n int) {
+	if last := b.last(); last != nil && last.Delete > 0 {
+		last.Delete += n
+		return
+	}
+	b.op = append(b.op, Component[T]{Delete: n})
+}
@@ -0,0 +1,264 @@
+// Package planner schedules study blocks for upcoming exams and assignments
+// into a user's free time. Each deadline needs an amount of study that grows
+// with the course's difficulty, credits and weight in the final grade and
+// shrinks with a good current grade; blocks go to the most urgent deadline
+// first and are spread over several days rather than crammed into one.
+package planner
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	examMinutes       = 8 * 60 // study a typical exam needs
+	assignmentMinutes = 4 * 60
+
+	// MaxBlocksPerDay caps the blocks one deadline gets per day, so study for it
+	// is spaced over several days
+	MaxBlocksPerDay = 2
+	// repeatPenalty lowers the urgency of the course studied in the previous
+	// block of the same day, which interleaves courses when others are close
+	repeatPenalty = 0.8
+)
+
+// Deadline is an exam or assignment to prepare for
+type Deadline struct {
+	ID         int
+	CourseID   int
+	Due        time.Time
+	Exam       bool
+	Difficulty int     // 1 (easy) to 5 (hard), 0 when unrated
+	Credits    int     // 0 when unknown
+	Grade      float64 // current course grade on the 4-point scale, 0 when unknown
+	Weight     float64 // percent of the final grade, 0 when unknown
+	Planned    int     // minutes already scheduled, e.g. blocks the user placed by hand
+}
+
+// Interval is a span of time in [Start, End)
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Window is a weekly span of free time, in minutes since local midnight
+type Window struct {
+	Weekday time.Weekday
+	Start   int
+	End     int
+}
+
+type Options struct {
+	Location        *time.Location // of the windows, defaults to UTC
+	BlockMinutes    int
+	BreakMinutes    int // between consecutive blocks of one window
+	MaxDailyMinutes int // zero is unlimited
+}
+
+// Block is one scheduled study block
+type Block struct {
+	DeadlineID int
+	CourseID   int
+	Start      time.Time
+	End        time.Time
+}
+
+// Shortfall is study a deadline needs that did not fit before it is due
+type Shortfall struct {
+	DeadlineID int
+	Minutes    int
+}
+
+type Schedule struct {
+	Blocks     []Block
+	Shortfalls []Shortfall
+}
+
+// Demand returns the minutes of study a deadline needs, rounded up to whole blocks
+func Demand(deadline Deadline, blockMinutes int) int {
+	minutes := float64(assignmentMinutes)
+	if deadline.Exam {
+		minutes = examMinutes
+	}
+
+	difficulty := deadline.Difficulty
+	if difficulty <= 0 {
+		difficulty = 3
+	}
+	minutes *= 0.6 + 0.2*float64(difficulty) // 0.8 to 1.6
+
+	if deadline.Credits > 0 {
+		minutes *= clamp(0.5+float64(deadline.Credits)/6, 0.75, 1.5)
+	}
+	if deadline.Grade > 0 {
+		minutes *= clamp(1.6-0.2*deadline.Grade, 0.8, 1.4)
+	}
+	if deadline.Weight > 0 {
+		minutes *= clamp(0.75+deadline.Weight/40, 0.75, 1.5)
+	}
+
+	if blockMinutes <= 0 {
+		return int(math.Ceil(minutes))
+	}
+	blocks := int(math.Ceil(minutes / float64(blockMinutes)))
+	return max(blocks, 1) * blockMinutes
+}
+
+// FreeSlots cuts the windows between from and to into blocks that avoid every
+// busy interval, in chronological order
+func FreeSlots(from, to time.Time, windows []Window, busy []Interval, opts Options) []Interval {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	if opts.BlockMinutes <= 0 || !from.Before(to) {
+		return nil
+	}
+	block := time.Duration(opts.BlockMinutes) * time.Minute
+	step := block + time.Duration(opts.BreakMinutes)*time.Minute
+
+	busy = append([]Interval(nil), busy...)
+	sort.Slice(busy, func(i, j int) bool { return busy[i].Start.Before(busy[j].Start) })
+
+	var slots []Interval
+	first := from.In(loc)
+	for day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		var free []Interval
+		for _, window := range windows {
+			if window.Weekday != day.Weekday() || window.End <= window.Start {
+				continue
+			}
+			span := Interval{Start: atMinute(day, window.Start, loc), End: atMinute(day, window.End, loc)}
+			if span.Start.Before(from) {
+				span.Start = from
+			}
+			if span.End.After(to) {
+				span.End = to
+			}
+			free = append(free, subtract(span, busy)...)
+		}
+		sort.Slice(free, func(i, j int) bool { return free[i].Start.Before(free[j].Start) })
+
+		for _, span := range free {
+			for start := span.Start; !start.Add(block).After(span.End); start = start.Add(step) {
+				slots = append(slots, Interval{Start: start, End: start.Add(block)})
+			}
+		}
+	}
+	return slots
+}
+
+// Plan assigns the free slots to deadlines. Every slot goes to the deadline
+// with the most study left per day remaining until it is due, so close and
+// demanding deadlines are served first without starving later ones.
+func Plan(from time.Time, deadlines []Deadline, windows []Window, busy []Interval, opts Options) Schedule {
+	var schedule Schedule
+	if len(deadlines) == 0 {
+		return schedule
+	}
+
+	deadlines = append([]Deadline(nil), deadlines...)
+	sort.Slice(deadlines, func(i, j int) bool {
+		if !deadlines[i].Due.Equal(deadlines[j].Due) {
+			return deadlines[i].Due.Before(deadlines[j].Due)
+		}
+		return deadlines[i].ID < deadlines[j].ID
+	})
+
+	remaining := make([]int, len(deadlines))
+	last := from
+	for i, deadline := range deadlines {
+		remaining[i] = max(Demand(deadline, opts.BlockMinutes)-deadline.Planned, 0)
+		if deadline.Due.After(last) {
+			last = deadline.Due
+		}
+	}
+
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	dayMinutes := map[string]int{}
+	type dayDeadline struct {
+		day   string
+		index int
+	}
+	dayBlocks := map[dayDeadline]int{}
+	previousCourse := map[string]int{}
+
+	for _, slot := range FreeSlots(from, last, windows, busy, opts) {
+		day := slot.Start.In(loc).Format(time.DateOnly)
+		if opts.MaxDailyMinutes > 0 && dayMinutes[day]+opts.BlockMinutes > opts.MaxDailyMinutes {
+			continue
+		}
+
+		best, bestUrgency := -1, 0.0
+		for i, deadline := range deadlines {
+			if remaining[i] <= 0 || slot.End.After(deadline.Due) || dayBlocks[dayDeadline{day, i}] >= MaxBlocksPerDay {
+				continue
+			}
+			days := math.Max(deadline.Due.Sub(slot.Start).Hours()/24, 0.5)
+			urgency := float64(remaining[i]) / days
+			if course, ok := previousCourse[day]; ok && course == deadline.CourseID {
+				urgency *= repeatPenalty
+			}
+			if urgency > bestUrgency {
+				best, bestUrgency = i, urgency
+			}
+		}
+		if best < 0 {
+			continue
+		}
+
+		deadline := deadlines[best]
+		schedule.Blocks = append(schedule.Blocks, Block{DeadlineID: deadline.ID, CourseID: deadline.CourseID, Start: slot.Start, End: slot.End})
+		remaining[best] -= opts.BlockMinutes
+		dayMinutes[day] += opts.BlockMinutes
+		dayBlocks[dayDeadline{day, best}]++
+		previousCourse[day] = deadline.CourseID
+	}
+
+	for i, deadline := range deadlines {
+		if remaining[i] > 0 {
+			schedule.Shortfalls = append(schedule.Shortfalls, Shortfall{DeadlineID: deadline.ID, Minutes: remaining[i]})
+		}
+	}
+	return schedule
+}
+
+// Overlaps reports whether two intervals share any time
+func Overlaps(a, b Interval) bool {
+	return a.Start.Before(b.End) && b.Start.Before(a.End)
+}
+
+// subtract removes the busy intervals (sorted by start) from span
+func subtract(span Interval, busy []Interval) []Interval {
+	var free []Interval
+	cursor := span.Start
+	for _, interval := range busy {
+		if !interval.End.After(cursor) || !interval.Start.Before(span.End) {
+			continue
+		}
+		if interval.Start.After(cursor) {
+			free = append(free, Interval{Start: cursor, End: interval.Start})
+		}
+		if interval.End.After(cursor) {
+			cursor = interval.End
+		}
+	}
+	if cursor.Before(span.End) {
+		free = append(free, Interval{Start: cursor, End: span.End})
+	}
+	return free
+}
+
+// atMinute places minutes since midnight on the date of day; wall-clock based,
+// so daylight saving changes keep the window's local times
+func atMinute(day time.Time, minutes int, loc *time.Location) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), minutes/60, minutes%60, 0, 0, loc)
+}
+
+func clamp(value, low, high float64) float64 {
+	return math.Min(math.Max(value, low), high)
+}
@@ -36,6 +36,140 @@ const (
 	CodeMailSendFailed       = 50001
 	CodeMailConfigMissing    = 50002
 	CodeMailConnectionFailed = 50003
+
+	// Course Errors (60000 - 60999)
+	CodeCourseNotFound = 60001
+
+	// Reminder Errors (61000 - 61999)
+	CodeReminderNotFound          = 61001
+	CodeReminderInvalidType       = 61002
+	CodeReminderInvalidStatus     = 61003
+	CodeReminderInvalidDueDate    = 61004
+	CodeReminderInvalidExamFields = 61005
+	CodeReminderInvalidTransition = 61006
+
+	// Notification Errors (62000 - 62999)
+	CodeNotificationInvalidOffsets = 62001
+
+	// Timetable Errors (63000 - 63999)
+	CodeClassSessionNotFound         = 63001
+	CodeClassSessionInvalidTime      = 63002
+	CodeClassSessionInvalidDate      = 63003
+	CodeClassSessionConflict         = 63004
+	CodeClassSessionInvalidException = 63005
+	CodeTimetableInvalidRange        = 63006
+	CodeTimetableImportInvalid       = 63007
+	CodeTimetableImportTooLarge      = 63008
+	CodeSemesterNotFound             = 63009
+	CodeTimetableImportNoSemester    = 63010
+
+	// Calendar Errors (64000 - 64999)
+	CodeCalendarFeedNotFound    = 64001
+	CodeCalendarInvalidTimezone = 64002
+
+	// Note Errors (65000 - 65999)
+	CodeNoteNotFound         = 65001
+	CodeNoteInvalidContent   = 65002
+	CodeNoteContentTooLarge  = 65003
+	CodeNoteInvalidTags      = 65004
+	CodeNoteInvalidDate      = 65005
+	CodeNoteRevisionNotFound = 65006
+	CodeNoteVersionConflict  = 65007
+	CodeNoteCollabActive     = 65008
+	CodeNoteCollabInvalidOp  = 65009
+	CodeNoteCollabResync     = 65010
+
+	// File Errors (66000 - 66999)
+	CodeFileNotFound         = 66001
+	CodeFileTooLarge         = 66002
+	CodeFileQuotaExceeded    = 66003
+	CodeFileTypeNotAllowed   = 66004
+	CodeFileUploadIncomplete = 66005
+	CodeFileNotReady         = 66006
+	CodeFileUploadNotFound   = 66007
+
+	// Search Errors (67000 - 67999)
+	CodeSearchInvalidQuery = 67001
+	CodeSearchInvalidDate  = 67002
+	CodeSearchInvalidType  = 67003
+
+	// Summary Errors (68000 - 68999)
+	CodeSummaryNotFound        = 68001
+	CodeSummaryInvalidLanguage = 68002
+	CodeSummaryNoteEmpty       = 68003
+
+	// Flashcard Errors (69000 - 69999)
+	CodeFlashcardNotFound           = 69001
+	CodeFlashcardGenerationNotFound = 69002
+	CodeFlashcardInvalidSource      = 69003
+	CodeFlashcardSourceEmpty        = 69004
+	CodeFlashcardInvalidLanguage    = 69005
+
+	// Quiz Errors (70000 - 70999)
+	CodeQuizNotFound          = 70001
+	CodeQuizInvalidDifficulty = 70002
+	CodeQuizInvalidType       = 70003
+	CodeQuizInvalidLanguage   = 70004
+	CodeQuizNoNotes           = 70005
+	CodeQuizNotReady          = 70006
+	CodeQuizAttemptNotFound   = 70007
+	CodeQuizAttemptSubmitted  = 70008
+	CodeQuizInvalidAnswer     = 70009
+
+	// Assistant Errors (71000 - 71999)
+	CodeConversationNotFound = 71001
+	CodeAssistantReplyFailed = 71002
+
+	// Plan Errors (72000 - 72999)
+	CodeAIQuotaExceeded    = 72001
+	CodeCourseLimitReached = 72002
+
+	// Billing Errors (73000 - 73999)
+	CodePlanNotPurchasable = 73001
+	CodeSubscriptionExists = 73002
+	CodeCheckoutFailed     = 73003
+	CodeInvalidWebhook     = 73004
+
+	// Planner Errors (74000 - 74999)
+	CodeStudyPlanNotFound      = 74001
+	CodeStudyPlanSuperseded    = 74002
+	CodeStudyBlockNotFound     = 74003
+	CodeStudyBlockConflict     = 74004
+	CodeInvalidStudyBlockTime  = 74005
+	CodeInvalidStudyWindow     = 74006
+	CodeStudyPlanNothingToPlan = 74007
+
+	// Study Session Errors (75000 - 75999)
+	CodeStudySessionNotFound    = 75001
+	CodeStudySessionRunning     = 75002
+	CodeStudySessionNotRunning  = 75003
+	CodeInvalidStudySessionTime = 75004
+	CodeStudySessionOverlap     = 75005
+	CodeInvalidStudyTimeRange   = 75006
+
+	// Assessment Errors (76000 - 76999)
+	CodeAssessmentNotFound       = 76001
+	CodeInvalidAssessmentWeights = 76002
+	CodeInvalidAssessmentScore   = 76003
+	CodeInvalidAssessmentDueDate = 76004
+
+	// Study Group Errors (77000 - 77999)
+	CodeStudyGroupNotFound      = 77001
+	CodeStudyGroupForbidden     = 77002
+	CodeInvitationInvalid       = 77003
+	CodeInvitationEmailMismatch = 77004
+	CodeInvitationNotFound      = 77005
+	CodeAlreadyGroupMember      = 77006
+	CodeStudyGroupFull          = 77007
+	CodeGroupOwnerCannotLeave   = 77008
+	CodeGroupMemberNotFound     = 77009
+	CodeGroupNoteNotFound       = 77010
+	CodeGroupNoteCourseMismatch = 77011
+	CodeGroupNoteAlreadyShared  = 77012
+
+	// Chat Errors (78000 - 78999)
+	CodeChatMessageInvalid = 78001
+	CodeChatFrameInvalid   = 78002
 )
 
 // msg maps error codes to user-friendly messages
@@ -75,6 +209,140 @@ var msg = map[int]string{
 	CodeMailSendFailed:       "Failed to send email",
 	CodeMailConfigMissing:    "Mail configuration is missing",
 	CodeMailConnectionFailed: "Failed to connect to mail service",
+
+	// Course
+	CodeCourseNotFound: "Course not found",
+
+	// Reminder
+	CodeReminderNotFound:          "Reminder not found",
+	CodeReminderInvalidType:       "Invalid reminder type",
+	CodeReminderInvalidStatus:     "Invalid reminder status",
+	CodeReminderInvalidDueDate:    "Invalid reminder due date or time",
+	CodeReminderInvalidExamFields: "Location, weight and score can only be set on exams",
+	CodeReminderInvalidTransition: "Reminder status cannot be changed this way",
+
+	// Notification
+	CodeNotificationInvalidOffsets: "Notification offsets must be unique and between 1 minute and 30 days",
+
+	// Timetable
+	CodeClassSessionNotFound:         "Class session not found",
+	CodeClassSessionInvalidTime:      "Class session must end after it starts",
+	CodeClassSessionInvalidDate:      "Invalid class session start or end date",
+	CodeClassSessionConflict:         "Class session overlaps another session",
+	CodeClassSessionInvalidException: "Date is not an occurrence of this class session",
+	CodeTimetableInvalidRange:        "Invalid timetable date range",
+	CodeTimetableImportInvalid:       "File is not a valid iCalendar document",
+	CodeTimetableImportTooLarge:      "Timetable file is too large",
+	CodeSemesterNotFound:             "Semester not found",
+	CodeTimetableImportNoSemester:    "No semester covers the imported dates, please choose one",
+
+	// Calendar
+	CodeCalendarFeedNotFound:    "Calendar feed not found",
+	CodeCalendarInvalidTimezone: "Timezone must be a valid IANA time zone name",
+
+	// Note
+	CodeNoteNotFound:         "Note not found",
+	CodeNoteInvalidContent:   "Note content must be a rich text document",
+	CodeNoteContentTooLarge:  "Note content is too large",
+	CodeNoteInvalidTags:      "Too many tags or tag name too long",
+	CodeNoteInvalidDate:      "Invalid lecture date",
+	CodeNoteRevisionNotFound: "Note revision not found",
+	CodeNoteVersionConflict:  "Note was changed by someone else; reload it and apply your changes again",
+	CodeNoteCollabActive:     "Note is being edited together; change its content through the editing session",
+	CodeNoteCollabInvalidOp:  "Edit does not apply to the current document",
+	CodeNoteCollabResync:     "Document moved on too far; reload it",
+
+	// File
+	CodeFileNotFound:         "File not found",
+	CodeFileTooLarge:         "File exceeds the maximum upload size",
+	CodeFileQuotaExceeded:    "Storage quota exceeded",
+	CodeFileTypeNotAllowed:   "File type is not allowed or does not match its extension",
+	CodeFileUploadIncomplete: "File has not been uploaded completely",
+	CodeFileNotReady:         "File upload has not been completed",
+	CodeFileUploadNotFound:   "Upload not found or already completed",
+
+	// Search
+	CodeSearchInvalidQuery: "Search query must contain at least one word",
+	CodeSearchInvalidDate:  "Invalid date, expected YYYY-MM-DD",
+	CodeSearchInvalidType:  "Unknown search type, expected note, file, course or reminder",
+
+	// Summary
+	CodeSummaryNotFound:        "Summary not found",
+	CodeSummaryInvalidLanguage: "Unsupported summary language",
+	CodeSummaryNoteEmpty:       "Note has no text to summarize",
+
+	// Flashcard
+	CodeFlashcardNotFound:           "Flashcard not found",
+	CodeFlashcardGenerationNotFound: "Flashcard generation not found",
+	CodeFlashcardInvalidSource:      "Specify exactly one of note_id and file_id",
+	CodeFlashcardSourceEmpty:        "Source has no extracted text yet",
+	CodeFlashcardInvalidLanguage:    "Unsupported flashcard language",
+
+	// Quiz
+	CodeQuizNotFound:          "Quiz not found",
+	CodeQuizInvalidDifficulty: "Unknown difficulty, expected easy, medium or hard",
+	CodeQuizInvalidType:       "Unknown question type, expected mcq, true_false or short_answer",
+	CodeQuizInvalidLanguage:   "Unsupported quiz language",
+	CodeQuizNoNotes:           "Course has no notes to generate questions from",
+	CodeQuizNotReady:          "Quiz questions have not been generated yet",
+	CodeQuizAttemptNotFound:   "Quiz attempt not found",
+	CodeQuizAttemptSubmitted:  "Quiz attempt was already submitted",
+	CodeQuizInvalidAnswer:     "Answer does not match a question of the quiz",
+
+	// Assistant
+	CodeConversationNotFound: "Conversation not found",
+	CodeAssistantReplyFailed: "The assistant could not answer, please try again",
+
+	// Plan
+	CodeAIQuotaExceeded:    "AI usage limit of your plan reached for this billing period",
+	CodeCourseLimitReached: "Course limit of your plan reached",
+
+	// Billing
+	CodePlanNotPurchasable: "Plan does not exist or cannot be purchased",
+	CodeSubscriptionExists: "You already have a subscription",
+	CodeCheckoutFailed:     "Could not start checkout, please try again",
+	CodeInvalidWebhook:     "Invalid webhook signature or payload",
+
+	// Planner
+	CodeStudyPlanNotFound:      "Study plan not found",
+	CodeStudyPlanSuperseded:    "Study plan was replaced by a newer one",
+	CodeStudyBlockNotFound:     "Study block not found",
+	CodeStudyBlockConflict:     "Study block overlaps a class or another study block",
+	CodeInvalidStudyBlockTime:  "Invalid study block time, expected a start before the end and a length between 15 and 180 minutes",
+	CodeInvalidStudyWindow:     "Invalid study window, expected HH:MM times with the start before the end",
+	CodeStudyPlanNothingToPlan: "No upcoming exams or assignments to plan for",
+
+	// Study Session
+	CodeStudySessionNotFound:    "Study session not found",
+	CodeStudySessionRunning:     "Another study session is still running",
+	CodeStudySessionNotRunning:  "Study session was already stopped",
+	CodeInvalidStudySessionTime: "Invalid study session time, expected a start before the end, not in the future and at most 12 hours long",
+	CodeStudySessionOverlap:     "Study session overlaps another study session",
+	CodeInvalidStudyTimeRange:   "Invalid date range, expected YYYY-MM-DD dates at most 366 days apart",
+
+	// Assessment
+	CodeAssessmentNotFound:       "Assessment not found",
+	CodeInvalidAssessmentWeights: "Assessment weights must add up to 100%",
+	CodeInvalidAssessmentScore:   "Obtained score cannot exceed the max score",
+	CodeInvalidAssessmentDueDate: "Invalid due date, expected YYYY-MM-DD",
+
+	// Study Group
+	CodeStudyGroupNotFound:      "Study group not found",
+	CodeStudyGroupForbidden:     "Your role in the study group does not allow this",
+	CodeInvitationInvalid:       "Invitation is invalid or has expired",
+	CodeInvitationEmailMismatch: "Invitation was sent to a different email",
+	CodeInvitationNotFound:      "Invitation not found",
+	CodeAlreadyGroupMember:      "You are already a member of this study group",
+	CodeStudyGroupFull:          "Study group is full",
+	CodeGroupOwnerCannotLeave:   "Transfer ownership or delete the group before leaving",
+	CodeGroupMemberNotFound:     "Study group member not found",
+	CodeGroupNoteNotFound:       "Note is not shared in this study group",
+	CodeGroupNoteCourseMismatch: "Only notes of the group's course can be shared",
+	CodeGroupNoteAlreadyShared:  "Note is already shared in this study group",
+
+	// Chat
+	CodeChatMessageInvalid: "Message must be between 1 and 4000 characters",
+	CodeChatFrameInvalid:   "Unrecognized chat frame",
 }
 
 // GetMsg retrieves the message for a given error code
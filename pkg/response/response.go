@@ -32,12 +32,18 @@ func SuccessResponse(c *gin.Context, code int, data interface{}) {
 }
 
 func ErrorResponse(c *gin.Context, code int, message string) {
+	ErrorResponseStatus(c, http.StatusOK, code, message)
+}
+
+// ErrorResponseStatus answers with an HTTP status other than 200, where
+// clients rely on its standard meaning (e.g. 409 for a failed If-Match)
+func ErrorResponseStatus(c *gin.Context, status, code int, message string) {
 	// If message is empty, use default message
 	if message == "" {
 		message = GetMessageByCode(code)
 	}
 
-	c.JSON(http.StatusOK, ResponseData{
+	c.JSON(status, ResponseData{
 		Code:    code,
 		Message: message,
 		Content: nil,
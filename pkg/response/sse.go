@@ -0,0 +1,137 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultSSEHeartbeat is how often StreamSSE writes a keep-alive comment when
+// no interval is given
+const DefaultSSEHeartbeat = 15 * time.Second
+
+// SSEEvent is one Server-Sent Event. String data is sent as is, anything
+// else as JSON.
+type SSEEvent struct {
+	ID    string // sent back by the browser as Last-Event-ID when it reconnects
+	Event string
+	Data  any
+}
+
+// SSEStream writes Server-Sent Events to a response. It is safe for
+// concurrent use.
+type SSEStream struct {
+	c   *gin.Context
+	ctx context.Context
+	mu  sync.Mutex
+}
+
+// StreamSSE switches the response to an event stream and calls fn with it.
+// A comment line is written every heartbeat so proxies keep the connection
+// open and a dead client is noticed. The context passed to fn is cancelled
+// once the client disconnects, and fn should return then.
+func StreamSSE(c *gin.Context, heartbeat time.Duration, fn func(ctx context.Context, stream *SSEStream) error) error {
+	if heartbeat <= 0 {
+		heartbeat = DefaultSSEHeartbeat
+	}
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no") // disable proxy buffering in nginx
+	c.Status(http.StatusOK)
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	stream := &SSEStream{c: c, ctx: ctx}
+	if err := stream.write(": connected\n\n"); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := stream.write(": heartbeat\n\n"); err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	err := fn(ctx, stream)
+	// The writer must not be used once the handler returns
+	cancel()
+	<-done
+	return err
+}
+
+// Send writes one event. It fails once the client has disconnected.
+func (s *SSEStream) Send(event SSEEvent) error {
+	var b strings.Builder
+	if event.ID != "" {
+		b.WriteString("id: " + singleLine(event.ID) + "\n")
+	}
+	if event.Event != "" {
+		b.WriteString("event: " + singleLine(event.Event) + "\n")
+	}
+
+	data, ok := event.Data.(string)
+	if !ok {
+		encoded, err := json.Marshal(event.Data)
+		if err != nil {
+			return err
+		}
+		data = string(encoded)
+	}
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	return s.write(b.String())
+}
+
+// Error writes an "error" event carrying the response code and its message,
+// for failures after the stream has started
+func (s *SSEStream) Error(code int) error {
+	return s.Send(SSEEvent{Event: "error", Data: ResponseData{Code: code, Message: GetMessageByCode(code)}})
+}
+
+func (s *SSEStream) write(chunk string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	if _, err := s.c.Writer.WriteString(chunk); err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+// LastEventID returns the ID of the last event a reconnecting client saw.
+// Browsers send the Last-Event-ID header; the lastEventId query parameter
+// serves clients that cannot set headers.
+func LastEventID(c *gin.Context) string {
+	if id := c.GetHeader("Last-Event-ID"); id != "" {
+		return id
+	}
+	return c.Query("lastEventId")
+}
+
+func singleLine(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
@@ -0,0 +1,187 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// DefaultWebSocketHeartbeat is how often ServeWebSocket pings the client
+	// when no interval is given
+	DefaultWebSocketHeartbeat = 25 * time.Second
+	// WebSocketReadLimit caps the size of one frame read from a client
+	WebSocketReadLimit = 64 << 10
+	webSocketWriteWait = 10 * time.Second
+)
+
+var (
+	// ErrWebSocketFrame is returned by Receive for a frame that is not valid JSON
+	ErrWebSocketFrame = errors.New("websocket frame is not valid JSON")
+	// ErrWebSocketSlow is returned by Send when the client does not keep up
+	ErrWebSocketSlow = errors.New("websocket client too slow")
+)
+
+// Clients authenticate with a token rather than cookies, so a cross-site page
+// gains nothing by opening a connection and any origin is accepted
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// WebSocketConn is an upgraded connection. Send is safe for concurrent use;
+// Receive must only be called from the handler passed to ServeWebSocket.
+type WebSocketConn struct {
+	conn   *websocket.Conn
+	ctx    context.Context
+	cancel context.CancelFunc
+	send   chan []byte
+
+	mu        sync.Mutex
+	closeCode int
+	closeText string
+}
+
+// ServeWebSocket upgrades the request and calls fn with the connection.
+// Writes go through a queue of buffer frames drained by one goroutine,
+// which also pings every heartbeat; a client that misses two pings is
+// dropped. The context passed to fn is cancelled once the connection is
+// closed from either side, and fn should return then.
+func ServeWebSocket(c *gin.Context, heartbeat time.Duration, buffer int, fn func(ctx context.Context, ws *WebSocketConn) error) error {
+	if heartbeat <= 0 {
+		heartbeat = DefaultWebSocketHeartbeat
+	}
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already answered with an HTTP error
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	ws := &WebSocketConn{
+		conn:      conn,
+		ctx:       ctx,
+		cancel:    cancel,
+		send:      make(chan []byte, buffer),
+		closeCode: websocket.CloseNormalClosure,
+	}
+
+	conn.SetReadLimit(WebSocketReadLimit)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ws.writeLoop(heartbeat)
+	}()
+
+	err = fn(ctx, ws)
+	cancel()
+	<-done
+	return err
+}
+
+// Send queues v to be written as JSON. It fails once the connection is
+// closed, and closes a connection whose queue is full.
+func (ws *WebSocketConn) Send(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-ws.ctx.Done():
+		return ws.ctx.Err()
+	default:
+	}
+	select {
+	case ws.send <- data:
+		return nil
+	default:
+		ws.Close(websocket.ClosePolicyViolation, "too slow")
+		return ErrWebSocketSlow
+	}
+}
+
+// Receive reads the next frame into v. A frame that is not valid JSON
+// returns ErrWebSocketFrame and the connection stays open; any other error
+// means the connection is gone.
+func (ws *WebSocketConn) Receive(v any) error {
+	_, data, err := ws.conn.ReadMessage()
+	if err != nil {
+		ws.cancel()
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrWebSocketFrame, err)
+	}
+	return nil
+}
+
+// Close ends the connection with the given close code once the queued frames are written
+func (ws *WebSocketConn) Close(code int, text string) {
+	ws.mu.Lock()
+	ws.closeCode, ws.closeText = code, text
+	ws.mu.Unlock()
+	ws.cancel()
+}
+
+func (ws *WebSocketConn) writeLoop(heartbeat time.Duration) {
+	// Closing the connection also unblocks the pending Receive
+	defer ws.conn.Close()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ws.ctx.Done():
+			ws.flush()
+			ws.mu.Lock()
+			message := websocket.FormatCloseMessage(ws.closeCode, ws.closeText)
+			ws.mu.Unlock()
+			_ = ws.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(webSocketWriteWait))
+			return
+		case data := <-ws.send:
+			if err := ws.write(websocket.TextMessage, data); err != nil {
+				ws.cancel()
+				return
+			}
+		case <-ticker.C:
+			if err := ws.write(websocket.PingMessage, nil); err != nil {
+				ws.cancel()
+				return
+			}
+		}
+	}
+}
+
+// flush writes what is still queued, e.g. the event explaining a close
+func (ws *WebSocketConn) flush() {
+	for {
+		select {
+		case data := <-ws.send:
+			if err := ws.write(websocket.TextMessage, data); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (ws *WebSocketConn) write(messageType int, data []byte) error {
+	_ = ws.conn.SetWriteDeadline(time.Now().Add(webSocketWriteWait))
+	return ws.conn.WriteMessage(messageType, data)
+}
@@ -0,0 +1,284 @@
+// Package richtext validates editor documents (ProseMirror/Tiptap JSON) and
+// renders them to sanitized HTML and plain text
+package richtext
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxDepth = 64
+	maxNodes = 100_000
+)
+
+var ErrInvalidDocument = errors.New("invalid rich text document")
+
+// Node is a document node. Text nodes carry Text and Marks, all others Content.
+type Node struct {
+	Type    string         `json:"type"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Content []Node         `json:"content,omitempty"`
+	Text    string         `json:"text,omitempty"`
+	Marks   []Mark         `json:"marks,omitempty"`
+}
+
+type Mark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+// blockTags maps block node types to the HTML element they render as
+var blockTags = map[string]string{
+	"paragraph":   "p",
+	"blockquote":  "blockquote",
+	"bulletList":  "ul",
+	"orderedList": "ol",
+	"listItem":    "li",
+	"taskList":    "ul",
+	"taskItem":    "li",
+	"table":       "table",
+	"tableRow":    "tr",
+	"tableCell":   "td",
+	"tableHeader": "th",
+}
+
+var markTags = map[string]string{
+	"bold":        "strong",
+	"italic":      "em",
+	"underline":   "u",
+	"strike":      "s",
+	"code":        "code",
+	"highlight":   "mark",
+	"subscript":   "sub",
+	"superscript": "sup",
+}
+
+// Parse decodes and validates a document whose root must be a "doc" node
+func Parse(data []byte) (*Node, error) {
+	var doc Node
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	if doc.Type != "doc" {
+		return nil, fmt.Errorf("%w: root node must be of type doc", ErrInvalidDocument)
+	}
+
+	count := 0
+	if err := doc.validate(0, &count); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (n *Node) validate(depth int, count *int) error {
+	*count++
+	if depth > maxDepth || *count > maxNodes {
+		return fmt.Errorf("%w: document is too large or too deeply nested", ErrInvalidDocument)
+	}
+	if n.Type == "" {
+		return fmt.Errorf("%w: node without type", ErrInvalidDocument)
+	}
+	if n.Type == "text" && len(n.Content) > 0 {
+		return fmt.Errorf("%w: text nodes cannot have content", ErrInvalidDocument)
+	}
+	for i := range n.Content {
+		if err := n.Content[i].validate(depth+1, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JSON returns the normalized encoding of the document, dropping unknown fields
+func (n *Node) JSON() ([]byte, error) {
+	return json.Marshal(n)
+}
+
+// HTML renders the document. Only allowlisted elements and attributes are
+// emitted and all text is escaped, so the output is safe to embed as-is.
+// Unknown node types render their children; unknown marks are dropped.
+func (n *Node) HTML() string {
+	var b strings.Builder
+	n.renderHTML(&b)
+	return b.String()
+}
+
+func (n *Node) renderHTML(b *strings.Builder) {
+	switch n.Type {
+	case "text":
+		renderText(b, n)
+		return
+	case "hardBreak":
+		b.WriteString("<br>")
+		return
+	case "horizontalRule":
+		b.WriteString("<hr>")
+		return
+	case "image":
+		if src, ok := safeURL(stringAttr(n.Attrs, "src"), true); ok {
+			fmt.Fprintf(b, `<img src="%s" alt="%s">`, html.EscapeString(src), html.EscapeString(stringAttr(n.Attrs, "alt")))
+		}
+		return
+	case "heading":
+		level := intAttr(n.Attrs, "level", 1)
+		if level < 1 || level > 6 {
+			level = 1
+		}
+		fmt.Fprintf(b, "<h%d>", level)
+		n.renderChildren(b)
+		fmt.Fprintf(b, "</h%d>", level)
+		return
+	case "codeBlock":
+		if language := stringAttr(n.Attrs, "language"); isIdentifier(language) {
+			fmt.Fprintf(b, `<pre><code class="language-%s">`, language)
+		} else {
+			b.WriteString("<pre><code>")
+		}
+		n.renderChildren(b)
+		b.WriteString("</code></pre>")
+		return
+	case "orderedList":
+		if start := intAttr(n.Attrs, "start", 1); start != 1 {
+			fmt.Fprintf(b, `<ol start="%d">`, start)
+			n.renderChildren(b)
+			b.WriteString("</ol>")
+			return
+		}
+	case "taskItem":
+		checked := ""
+		if checkedAttr, _ := n.Attrs["checked"].(bool); checkedAttr {
+			checked = " checked"
+		}
+		fmt.Fprintf(b, `<li><input type="checkbox" disabled%s>`, checked)
+		n.renderChildren(b)
+		b.WriteString("</li>")
+		return
+	}
+
+	tag, ok := blockTags[n.Type]
+	if !ok {
+		n.renderChildren(b)
+		return
+	}
+	b.WriteString("<" + tag + ">")
+	n.renderChildren(b)
+	b.WriteString("</" + tag + ">")
+}
+
+func (n *Node) renderChildren(b *strings.Builder) {
+	for i := range n.Content {
+		n.Content[i].renderHTML(b)
+	}
+}
+
+func renderText(b *strings.Builder, n *Node) {
+	var closing []string
+	for _, mark := range n.Marks {
+		if mark.Type == "link" {
+			if href, ok := safeURL(stringAttr(mark.Attrs, "href"), false); ok {
+				fmt.Fprintf(b, `<a href="%s" rel="noopener noreferrer nofollow" target="_blank">`, html.EscapeString(href))
+				closing = append(closing, "</a>")
+			}
+			continue
+		}
+		if tag, ok := markTags[mark.Type]; ok {
+			b.WriteString("<" + tag + ">")
+			closing = append(closing, "</"+tag+">")
+		}
+	}
+
+	b.WriteString(html.EscapeString(n.Text))
+	for i := len(closing) - 1; i >= 0; i-- {
+		b.WriteString(closing[i])
+	}
+}
+
+// PlainText flattens the document to text with one line per block
+func (n *Node) PlainText() string {
+	var b strings.Builder
+	n.renderText(&b)
+	return strings.TrimSpace(b.String())
+}
+
+func (n *Node) renderText(b *strings.Builder) {
+	switch n.Type {
+	case "text":
+		b.WriteString(n.Text)
+		return
+	case "hardBreak":
+		b.WriteString("\n")
+		return
+	case "image":
+		if alt := stringAttr(n.Attrs, "alt"); alt != "" {
+			b.WriteString(alt)
+		}
+	}
+
+	for i := range n.Content {
+		n.Content[i].renderText(b)
+	}
+	if n.Type != "doc" && n.Type != "text" && isBlock(n.Type) {
+		b.WriteString("\n")
+	}
+}
+
+func isBlock(nodeType string) bool {
+	switch nodeType {
+	case "paragraph", "heading", "codeBlock", "horizontalRule", "image", "tableRow":
+		return true
+	}
+	return false
+}
+
+// safeURL allows http(s) and mailto links, plus data:image URLs for images
+func safeURL(raw string, image bool) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	parsed, err := url.Parse(raw)
+	if err != nil || raw == "" {
+		return "", false
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https":
+		return raw, true
+	case "mailto":
+		return raw, !image
+	case "data":
+		return raw, image && strings.HasPrefix(strings.ToLower(parsed.Opaque), "image/")
+	}
+	return "", false
+}
+
+func stringAttr(attrs map[string]any, key string) string {
+	value, _ := attrs[key].(string)
+	return value
+}
+
+func intAttr(attrs map[string]any, key string, fallback int) int {
+	switch value := attrs[key].(type) {
+	case float64:
+		return int(value)
+	case string:
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+func isIdentifier(value string) bool {
+	if value == "" || len(value) > 32 {
+		return false
+	}
+	for _, r := range value {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '+') {
+			return false
+		}
+	}
+	return true
+}
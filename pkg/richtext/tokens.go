@@ -0,0 +1,122 @@
+package richtext
+
+import (
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+)
+
+// Token kinds
+const (
+	TokenOpen  = "open"  // start of a node with content
+	TokenClose = "close" // end of the innermost open node
+	TokenLeaf  = "leaf"  // a node that never has content
+	TokenChar  = "char"  // one character of text
+)
+
+// leafTypes are the node types that take a single position, like atoms in
+// the editor; every other node takes its start, its content and its end
+var leafTypes = map[string]bool{
+	"hardBreak":      true,
+	"horizontalRule": true,
+	"image":          true,
+}
+
+// Token is one position of a document in its linear form, which
+// collaborative editing works on. The positions count characters as code
+// points; the doc root itself is not part of the sequence.
+type Token struct {
+	Kind  string         `json:"kind"`
+	Type  string         `json:"type,omitempty"`  // node type of open and leaf tokens
+	Attrs map[string]any `json:"attrs,omitempty"` // node attributes of open and leaf tokens
+	Text  string         `json:"text,omitempty"`  // the character of a char token
+	Marks []Mark         `json:"marks,omitempty"` // marks of a char token
+}
+
+// Tokens returns the linear form of the node's content
+func (n *Node) Tokens() []Token {
+	var tokens []Token
+	for i := range n.Content {
+		tokens = n.Content[i].appendTokens(tokens)
+	}
+	return tokens
+}
+
+func (n *Node) appendTokens(tokens []Token) []Token {
+	switch {
+	case n.Type == "text":
+		for _, r := range n.Text {
+			tokens = append(tokens, Token{Kind: TokenChar, Text: string(r), Marks: n.Marks})
+		}
+	case leafTypes[n.Type] && len(n.Content) == 0:
+		tokens = append(tokens, Token{Kind: TokenLeaf, Type: n.Type, Attrs: n.Attrs})
+	default:
+		tokens = append(tokens, Token{Kind: TokenOpen, Type: n.Type, Attrs: n.Attrs})
+		for i := range n.Content {
+			tokens = n.Content[i].appendTokens(tokens)
+		}
+		tokens = append(tokens, Token{Kind: TokenClose})
+	}
+	return tokens
+}
+
+// FromTokens rebuilds a document from its linear form. Characters with the
+// same marks next to each other join into one text node. The result is
+// validated like a parsed document.
+func FromTokens(tokens []Token) (*Node, error) {
+	stack := []*Node{{Type: "doc"}}
+	for i, token := range tokens {
+		parent := stack[len(stack)-1]
+		switch token.Kind {
+		case TokenOpen, TokenLeaf:
+			if token.Type == "" || token.Type == "text" || token.Type == "doc" {
+				return nil, fmt.Errorf("%w: token %d has node type %q", ErrInvalidDocument, i, token.Type)
+			}
+			parent.Content = append(parent.Content, Node{Type: token.Type, Attrs: token.Attrs})
+			if token.Kind == TokenOpen {
+				stack = append(stack, &parent.Content[len(parent.Content)-1])
+			}
+		case TokenClose:
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("%w: token %d closes no node", ErrInvalidDocument, i)
+			}
+			stack = stack[:len(stack)-1]
+		case TokenChar:
+			if utf8.RuneCountInString(token.Text) != 1 {
+				return nil, fmt.Errorf("%w: token %d must hold one character", ErrInvalidDocument, i)
+			}
+			if last := len(parent.Content) - 1; last >= 0 && parent.Content[last].Type == "text" && sameMarks(parent.Content[last].Marks, token.Marks) {
+				parent.Content[last].Text += token.Text
+				continue
+			}
+			parent.Content = append(parent.Content, Node{Type: "text", Text: token.Text, Marks: token.Marks})
+		default:
+			return nil, fmt.Errorf("%w: token %d has unknown kind %q", ErrInvalidDocument, i, token.Kind)
+		}
+	}
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("%w: %d nodes left open", ErrInvalidDocument, len(stack)-1)
+	}
+
+	doc := stack[0]
+	count := 0
+	if err := doc.validate(0, &count); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func sameMarks(a, b []Mark) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type || len(a[i].Attrs) != len(b[i].Attrs) {
+			return false
+		}
+		if len(a[i].Attrs) > 0 && !reflect.DeepEqual(a[i].Attrs, b[i].Attrs) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Highlight returns an HTML-escaped excerpt of about width runes around the
+// first match, with every word starting with a term wrapped in <mark>
+func Highlight(text string, terms []string, width int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	lower := []rune(strings.ToLower(string(runes)))
+	if len(lower) != len(runes) {
+		// Lower-casing changed the length (rare scripts); match case-sensitively
+		lower = runes
+	}
+
+	start, end := 0, len(runes)
+	if width > 0 && len(runes) > width {
+		first := firstMatch(lower, terms)
+		start = max(0, first-width/3)
+		// Begin and end on word boundaries
+		for start > 0 && !unicode.IsSpace(runes[start-1]) {
+			start--
+		}
+		end = min(len(runes), start+width)
+		for end < len(runes) && !unicode.IsSpace(runes[end]) {
+			end++
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("… ")
+	}
+	i := start
+	for i < end {
+		if isWordStart(runes, i) {
+			if length := matchLength(lower, i, terms); length > 0 {
+				// Mark the whole word, not only the matched prefix
+				wordEnd := i + length
+				for wordEnd < end && isWordRune(runes[wordEnd]) {
+					wordEnd++
+				}
+				b.WriteString("<mark>" + html.EscapeString(string(runes[i:wordEnd])) + "</mark>")
+				i = wordEnd
+				continue
+			}
+		}
+		b.WriteString(html.EscapeString(string(runes[i])))
+		i++
+	}
+	if end < len(runes) {
+		b.WriteString(" …")
+	}
+	return b.String()
+}
+
+func firstMatch(lower []rune, terms []string) int {
+	for i := range lower {
+		if isWordStart(lower, i) && matchLength(lower, i, terms) > 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+// matchLength returns the length of the longest term that starts at i
+func matchLength(lower []rune, i int, terms []string) int {
+	best := 0
+	for _, term := range terms {
+		termRunes := []rune(term)
+		if len(termRunes) > best && i+len(termRunes) <= len(lower) && string(lower[i:i+len(termRunes)]) == term {
+			best = len(termRunes)
+		}
+	}
+	return best
+}
+
+func isWordStart(runes []rune, i int) bool {
+	return isWordRune(runes[i]) && (i == 0 || !isWordRune(runes[i-1]))
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
@@ -0,0 +1,54 @@
+// Package search parses user search queries for MySQL FULLTEXT boolean mode
+// and highlights matches in result snippets
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	maxTerms     = 10
+	minTermRunes = 3 // InnoDB's default innodb_ft_min_token_size
+)
+
+// Terms splits a query into lower-cased words, dropping FULLTEXT operators and
+// duplicates. Words shorter than the index's minimum token size are dropped
+// unless nothing else is left, since InnoDB ignores them.
+func Terms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+
+	seen := map[string]bool{}
+	var terms, short []string
+	for _, word := range words {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		if utf8.RuneCountInString(word) < minTermRunes {
+			short = append(short, word)
+			continue
+		}
+		terms = append(terms, word)
+	}
+	if len(terms) == 0 {
+		terms = short
+	}
+	if len(terms) > maxTerms {
+		terms = terms[:maxTerms]
+	}
+	return terms
+}
+
+// BooleanQuery requires every term and matches it as a prefix, so "algo sort"
+// finds "algorithms for sorting"
+func BooleanQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = "+" + term + "*"
+	}
+	return strings.Join(parts, " ")
+}
@@ -7,6 +7,14 @@ type Config struct {
 	Log      LogSetting      `mapstructure:"log"`
 	Redis    RedisSetting    `mapstructure:"redis"`
 	Resend   ResendSetting   `mapstructure:"resend"`
+
+	Notification NotificationSetting `mapstructure:"notification"`
+	Queue        QueueSetting        `mapstructure:"queue"`
+	Calendar     CalendarSetting     `mapstructure:"calendar"`
+	Storage      StorageSetting      `mapstructure:"storage"`
+	AI           AISetting           `mapstructure:"ai"`
+	Billing      BillingSetting      `mapstructure:"billing"`
+	StudyGroup   StudyGroupSetting   `mapstructure:"study_group"`
 }
 
 // ServerSetting holds server configuration
@@ -46,3 +54,106 @@ type RedisSetting struct {
 	Password string `mapstructure:"password"`
 	Database int    `mapstructure:"database"`
 }
+
+// NotificationSetting holds reminder notification worker configuration
+type NotificationSetting struct {
+	Enabled        bool  `mapstructure:"enabled"`
+	ScanInterval   int   `mapstructure:"scan_interval"`   // seconds between scans
+	DefaultOffsets []int `mapstructure:"default_offsets"` // minutes before the deadline
+	MaxAttempts    int   `mapstructure:"max_attempts"`
+	ClaimTimeout   int   `mapstructure:"claim_timeout"` // seconds before an unfinished send is retried
+	BatchSize      int   `mapstructure:"batch_size"`
+}
+
+// QueueSetting holds background job queue configuration
+type QueueSetting struct {
+	Queues            map[string]int `mapstructure:"queues"`             // queue name -> worker concurrency
+	VisibilityTimeout int            `mapstructure:"visibility_timeout"` // seconds before an unacknowledged job is redelivered
+	PollInterval      int            `mapstructure:"poll_interval"`      // seconds between polls of an empty queue
+	BackoffBase       int            `mapstructure:"backoff_base"`       // seconds, doubled on every retry
+	BackoffMax        int            `mapstructure:"backoff_max"`        // seconds
+}
+
+// CalendarSetting holds iCalendar export configuration
+type CalendarSetting struct {
+	FeedBaseURL string `mapstructure:"feed_base_url"` // public origin of subscription URLs, e.g. https://api.example.com
+}
+
+// StudyGroupSetting holds study group invitation configuration
+type StudyGroupSetting struct {
+	JoinBaseURL string `mapstructure:"join_base_url"` // frontend page accepting invitations, e.g. https://app.example.com/groups/join; the token is appended
+}
+
+// StorageSetting holds file upload storage configuration
+type StorageSetting struct {
+	Backend       string           `mapstructure:"backend"`         // "local" (default) or "s3"
+	LocalRoot     string           `mapstructure:"local_root"`      // directory of the local backend
+	PublicBaseURL string           `mapstructure:"public_base_url"` // public origin of local presigned URLs, e.g. https://api.example.com
+	SigningSecret string           `mapstructure:"signing_secret"`  // HMAC key of local presigned URLs
+	PresignExpiry int              `mapstructure:"presign_expiry"`  // seconds a presigned URL stays valid
+	MaxFileSize   int64            `mapstructure:"max_file_size"`   // bytes
+	Quotas        map[string]int64 `mapstructure:"quotas"`          // user tier -> total bytes
+	S3            S3Setting        `mapstructure:"s3"`
+}
+
+// S3Setting holds S3-compatible object store configuration (AWS S3, Cloudflare R2, MinIO).
+//
+// Presigned uploads land under the "staging/" prefix of the bucket. The worker
+// sweeps expired ones, but the bucket should also carry a lifecycle rule that
+// expires objects under "staging/" after a day, so nothing outlives a worker
+// outage. With the AWS CLI:
+//
+//	aws s3api put-bucket-lifecycle-configuration --bucket <bucket> --lifecycle-configuration \
+//	  '{"Rules":[{"ID":"expire-staging","Filter":{"Prefix":"staging/"},"Status":"Enabled","Expiration":{"Days":1}}]}'
+type S3Setting struct {
+	Endpoint  string `mapstructure:"endpoint"`
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	PathStyle bool   `mapstructure:"path_style"`
+}
+
+// AISetting holds LLM provider configuration
+type AISetting struct {
+	Provider          string           `mapstructure:"provider"`           // "openai", "anthropic" or "fake" (default)
+	EmbeddingProvider string           `mapstructure:"embedding_provider"` // defaults to provider; anthropic has no embeddings API
+	Timeout           int              `mapstructure:"timeout"`            // seconds per attempt
+	MaxRetries        int              `mapstructure:"max_retries"`        // retries on rate limits and server errors, -1 disables
+	OpenAI            OpenAISetting    `mapstructure:"openai"`
+	Anthropic         AnthropicSetting `mapstructure:"anthropic"`
+}
+
+// OpenAISetting holds OpenAI-compatible API configuration
+type OpenAISetting struct {
+	BaseURL        string `mapstructure:"base_url"` // defaults to https://api.openai.com/v1
+	APIKey         string `mapstructure:"api_key"`
+	Organization   string `mapstructure:"organization"`
+	ChatModel      string `mapstructure:"chat_model"`
+	EmbeddingModel string `mapstructure:"embedding_model"`
+}
+
+// AnthropicSetting holds Anthropic Messages API configuration
+type AnthropicSetting struct {
+	BaseURL   string `mapstructure:"base_url"` // defaults to https://api.anthropic.com
+	APIKey    string `mapstructure:"api_key"`
+	ChatModel string `mapstructure:"chat_model"`
+}
+
+// BillingSetting holds payment provider configuration
+type BillingSetting struct {
+	Provider      string        `mapstructure:"provider"`       // "stripe" or "fake" (default)
+	WebhookSecret string        `mapstructure:"webhook_secret"` // signing secret of webhooks from the provider
+	SuccessURL    string        `mapstructure:"success_url"`    // where checkout returns after payment
+	CancelURL     string        `mapstructure:"cancel_url"`     // where checkout returns when abandoned
+	TrialDays     int           `mapstructure:"trial_days"`     // free trial of first-time subscribers, 0 disables
+	GracePeriod   int           `mapstructure:"grace_period"`   // days a past-due subscription keeps its plan
+	Stripe        StripeSetting `mapstructure:"stripe"`
+}
+
+// StripeSetting holds Stripe API configuration
+type StripeSetting struct {
+	BaseURL   string            `mapstructure:"base_url"` // defaults to https://api.stripe.com
+	SecretKey string            `mapstructure:"secret_key"`
+	Prices    map[string]string `mapstructure:"prices"` // plan code -> recurring price ID
+}
@@ -0,0 +1,66 @@
+// Package srs schedules spaced-repetition reviews with the SM-2 algorithm
+package srs
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	DefaultEaseFactor = 2.5
+	MinEaseFactor     = 1.3
+	MaxGrade          = 5
+	PassingGrade      = 3 // grades below this count as a lapse
+)
+
+var ErrInvalidGrade = errors.New("grade must be between 0 and 5")
+
+// State is the scheduling state of one card
+type State struct {
+	EaseFactor  float64
+	Interval    int // days until the next review
+	Repetitions int // successful reviews in a row
+}
+
+// New returns the state of a card that has never been reviewed
+func New() State {
+	return State{EaseFactor: DefaultEaseFactor}
+}
+
+// Review applies a grade from 0 (blackout) to 5 (perfect recall).
+// Passing grades grow the interval to 1, 6, then interval × ease factor days;
+// failing grades restart the card at one day. The ease factor moves with
+// every grade and never drops below 1.3.
+func Review(state State, grade int) (State, error) {
+	if grade < 0 || grade > MaxGrade {
+		return state, ErrInvalidGrade
+	}
+	if state.EaseFactor < MinEaseFactor {
+		state.EaseFactor = DefaultEaseFactor
+	}
+
+	next := state
+	if grade >= PassingGrade {
+		switch state.Repetitions {
+		case 0:
+			next.Interval = 1
+		case 1:
+			next.Interval = 6
+		default:
+			next.Interval = int(math.Round(float64(max(state.Interval, 1)) * state.EaseFactor))
+		}
+		next.Repetitions = state.Repetitions + 1
+	} else {
+		next.Interval = 1
+		next.Repetitions = 0
+	}
+
+	miss := float64(MaxGrade - grade)
+	next.EaseFactor = state.EaseFactor + 0.1 - miss*(0.08+miss*0.02)
+	if next.EaseFactor < MinEaseFactor {
+		next.EaseFactor = MinEaseFactor
+	}
+	// Keep the stored value free of float noise
+	next.EaseFactor = math.Round(next.EaseFactor*1000) / 1000
+	return next, nil
+}
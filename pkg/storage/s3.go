@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Config configures an S3-compatible backend (AWS S3, Cloudflare R2, MinIO)
+type S3Config struct {
+	Endpoint  string // e.g. https://s3.eu-central-1.amazonaws.com or http://localhost:9000
+	Region    string // "auto" for R2
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool // bucket in the path instead of the host name; required by MinIO
+
+	HTTPClient *http.Client
+	Now        func() time.Time // clock used for signing; defaults to time.Now
+}
+
+// S3Storage talks to the S3 REST API directly with Signature Version 4
+type S3Storage struct {
+	cfg      S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+func NewS3Storage(cfg S3Config) (*S3Storage, error) {
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("S3 storage needs a bucket and credentials")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+
+	s := &S3Storage{cfg: cfg, endpoint: endpoint, client: cfg.HTTPClient, now: cfg.Now}
+	if s.client == nil {
+		s.client = &http.Client{Timeout: 5 * time.Minute}
+	}
+	if s.now == nil {
+		s.now = time.Now
+	}
+	return s, nil
+}
+
+// objectURL returns the URL of an object in path or virtual-hosted style
+func (s *S3Storage) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	if s.cfg.PathStyle {
+		u.Path = u.Path + "/" + s.cfg.Bucket + "/" + key
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path = u.Path + "/" + key
+	}
+	u.RawPath = escapeKey(u.Path)
+	return &u
+}
+
+func (s *S3Storage) do(ctx context.Context, method, key string, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	if !validKey(key) {
+		return nil, ErrInvalidKey
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	s.signer().signRequest(req, s.now())
+	return s.client.Do(req)
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(ctx, http.MethodPut, key, body, size, header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp)
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Storage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+	size, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("stat %s: missing content length", key)
+	}
+	return &ObjectInfo{Key: key, Size: size}, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// Copy uses CopyObject, which reports some failures in the body of a 200 response
+func (s *S3Storage) Copy(ctx context.Context, srcKey, dstKey string) error {
+	if !validKey(srcKey) {
+		return ErrInvalidKey
+	}
+	header := http.Header{}
+	header.Set("X-Amz-Copy-Source", escapeKey("/"+s.cfg.Bucket+"/"+srcKey))
+	resp, err := s.do(ctx, http.MethodPut, dstKey, nil, 0, header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	detail, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return err
+	}
+	if bytes.Contains(detail, []byte("<Error>")) {
+		return fmt.Errorf("s3: copy %s: %s", srcKey, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// PresignPut signs the content type too, so the client must send the same Content-Type header
+func (s *S3Storage) PresignPut(ctx context.Context, key, contentType string, expires time.Duration) (string, error) {
+	if !validKey(key) {
+		return "", ErrInvalidKey
+	}
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return s.signer().presign(http.MethodPut, s.objectURL(key), header, s.now(), expires), nil
+}
+
+func (s *S3Storage) PresignGet(ctx context.Context, key string, opts GetOptions, expires time.Duration) (string, error) {
+	if !validKey(key) {
+		return "", ErrInvalidKey
+	}
+	u := s.objectURL(key)
+	query := url.Values{}
+	if opts.Filename != "" {
+		query.Set("response-content-disposition", ContentDisposition("attachment", opts.Filename))
+	}
+	if opts.ContentType != "" {
+		query.Set("response-content-type", opts.ContentType)
+	}
+	u.RawQuery = query.Encode()
+	return s.signer().presign(http.MethodGet, u, nil, s.now(), expires), nil
+}
+
+func (s *S3Storage) signer() *sigV4 {
+	return &sigV4{accessKey: s.cfg.AccessKey, secretKey: s.cfg.SecretKey, region: s.cfg.Region, service: "s3"}
+}
+
+func checkResponse(resp *http.Response) error {
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3: %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+}
+
+// ContentDisposition builds a header value that keeps non-ASCII file names intact (RFC 6266)
+func ContentDisposition(disposition, filename string) string {
+	fallback := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, filename)
+	return fmt.Sprintf(`%s; filename="%s"; filename*=UTF-8''%s`, disposition, fallback, escapeRFC3986(filename, false))
+}
@@ -0,0 +1,64 @@
+// Package storage stores uploaded files in an object store and issues
+// short-lived presigned URLs for direct uploads and downloads
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	ErrNotFound         = errors.New("object not found")
+	ErrInvalidKey       = errors.New("invalid object key")
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+)
+
+// Storage is implemented by every backend. Keys are slash-separated paths
+// such as users/<id>/<name>; they never start with a slash.
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+	// Copy duplicates the object at srcKey to dstKey without passing it through the caller
+	Copy(ctx context.Context, srcKey, dstKey string) error
+
+	// PresignPut returns a URL the client can PUT the object body to
+	PresignPut(ctx context.Context, key, contentType string, expires time.Duration) (string, error)
+	// PresignGet returns a URL that downloads the object under the given name
+	PresignGet(ctx context.Context, key string, opts GetOptions, expires time.Duration) (string, error)
+}
+
+type ObjectInfo struct {
+	Key  string
+	Size int64
+}
+
+// GetOptions override the headers of a presigned download
+type GetOptions struct {
+	Filename    string // sent as Content-Disposition: attachment
+	ContentType string
+}
+
+// validKey rejects keys that could escape the bucket or root directory
+func validKey(key string) bool {
+	if key == "" || key[0] == '/' || len(key) > 1024 {
+		return false
+	}
+	start := 0
+	for i := 0; i <= len(key); i++ {
+		if i == len(key) || key[i] == '/' {
+			if segment := key[start:i]; segment == "" || segment == "." || segment == ".." {
+				return false
+			}
+			start = i + 1
+			continue
+		}
+		if key[i] == '\\' || key[i] < 0x20 {
+			return false
+		}
+	}
+	return true
+}
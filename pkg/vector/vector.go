@@ -0,0 +1,134 @@
+// Package vector holds the math behind embedding search: normalization,
+// cosine similarity, a compact binary encoding and top-k selection
+package vector
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+var ErrDimensionMismatch = errors.New("vectors have different dimensions")
+
+// Normalize returns v scaled to unit length, so that the cosine similarity
+// of two normalized vectors is their dot product. The zero vector is returned as is.
+func Normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	out := make([]float32, len(v))
+	if sum == 0 {
+		copy(out, v)
+		return out
+	}
+	norm := math.Sqrt(sum)
+	for i, x := range v {
+		out[i] = float32(float64(x) / norm)
+	}
+	return out
+}
+
+// Dot returns the dot product of a and b
+func Dot(a, b []float32) (float64, error) {
+	if len(a) != len(b) {
+		return 0, ErrDimensionMismatch
+	}
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum, nil
+}
+
+// Cosine returns the cosine similarity of a and b, 0 when either is the zero vector
+func Cosine(a, b []float32) (float64, error) {
+	dot, err := Dot(a, b)
+	if err != nil {
+		return 0, err
+	}
+	na, _ := Dot(a, a)
+	nb, _ := Dot(b, b)
+	if na == 0 || nb == 0 {
+		return 0, nil
+	}
+	return dot / math.Sqrt(na*nb), nil
+}
+
+// Encode packs v as little-endian float32s, 4 bytes per dimension
+func Encode(v []float32) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(x))
+	}
+	return buf
+}
+
+// Decode unpacks a vector written by Encode
+func Decode(buf []byte) ([]float32, error) {
+	if len(buf)%4 != 0 {
+		return nil, errors.New("vector encoding length is not a multiple of 4")
+	}
+	v := make([]float32, len(buf)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return v, nil
+}
+
+// TopK keeps the k highest scored items pushed into it
+type TopK[T any] struct {
+	k     int
+	items scored[T]
+}
+
+func NewTopK[T any](k int) *TopK[T] {
+	return &TopK[T]{k: k}
+}
+
+// Push offers an item; it is dropped when k better ones are already kept
+func (t *TopK[T]) Push(item T, score float64) {
+	if t.k <= 0 {
+		return
+	}
+	if len(t.items) < t.k {
+		heap.Push(&t.items, scoredItem[T]{item: item, score: score})
+		return
+	}
+	if score > t.items[0].score {
+		t.items[0] = scoredItem[T]{item: item, score: score}
+		heap.Fix(&t.items, 0)
+	}
+}
+
+// Results returns the kept items with their scores, best first
+func (t *TopK[T]) Results() ([]T, []float64) {
+	sorted := append(scored[T](nil), t.items...)
+	items := make([]T, len(sorted))
+	scores := make([]float64, len(sorted))
+	for i := len(sorted) - 1; i >= 0; i-- {
+		best := heap.Pop(&sorted).(scoredItem[T])
+		items[i], scores[i] = best.item, best.score
+	}
+	return items, scores
+}
+
+type scoredItem[T any] struct {
+	item  T
+	score float64
+}
+
+// scored is a min-heap on score, so the worst kept item is at the root
+type scored[T any] []scoredItem[T]
+
+func (s scored[T]) Len() int           { return len(s) }
+func (s scored[T]) Less(i, j int) bool { return s[i].score < s[j].score }
+func (s scored[T]) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s *scored[T]) Push(x any)        { *s = append(*s, x.(scoredItem[T])) }
+func (s *scored[T]) Pop() any {
+	old := *s
+	item := old[len(old)-1]
+	*s = old[:len(old)-1]
+	return item
+}
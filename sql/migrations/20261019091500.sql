@@ -0,0 +1,24 @@
+-- Create "reminders" table
+CREATE TABLE `reminders` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `title` text NOT NULL,
+  `description` text NOT NULL,
+  `due_date` date NOT NULL,
+  `due_time` time NOT NULL,
+  `user_id` char(36) NOT NULL,
+  `course_id` bigint NULL,
+  `type` tinyint NOT NULL DEFAULT 0,
+  `status` tinyint NOT NULL DEFAULT 0,
+  `location` varchar(255) NULL,
+  `weight` double NULL,
+  `completed_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_reminders_course_id` (`course_id`),
+  INDEX `idx_reminders_due_date` (`due_date`),
+  INDEX `idx_reminders_status` (`status`),
+  INDEX `idx_reminders_user_id` (`user_id`),
+  CONSTRAINT `fk_reminders_course` FOREIGN KEY (`course_id`) REFERENCES `courses` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `fk_users_reminders` FOREIGN KEY (`user_id`) REFERENCES `users` (`user_id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
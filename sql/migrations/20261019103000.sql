@@ -0,0 +1,30 @@
+-- Create "notification_settings" table
+CREATE TABLE `notification_settings` (
+  `user_id` char(36) NOT NULL,
+  `offsets` varchar(255) NOT NULL,
+  `email_enabled` tinyint NOT NULL DEFAULT 1,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`user_id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "reminder_notifications" table
+CREATE TABLE `reminder_notifications` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `reminder_id` bigint NOT NULL,
+  `user_id` char(36) NOT NULL,
+  `offset_minutes` bigint NOT NULL,
+  `scheduled_at` datetime(3) NOT NULL,
+  `status` tinyint NOT NULL DEFAULT 0,
+  `attempts` bigint NOT NULL DEFAULT 0,
+  `claimed_at` datetime(3) NULL,
+  `sent_at` datetime(3) NULL,
+  `last_error` text NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_reminder_notifications_scheduled_at` (`scheduled_at`),
+  INDEX `idx_reminder_notifications_status` (`status`),
+  INDEX `idx_reminder_notifications_user_id` (`user_id`),
+  UNIQUE INDEX `idx_reminder_notifications_reminder_offset` (`reminder_id`, `offset_minutes`),
+  CONSTRAINT `fk_reminder_notifications_reminder` FOREIGN KEY (`reminder_id`) REFERENCES `reminders` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
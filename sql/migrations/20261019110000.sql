@@ -0,0 +1,31 @@
+-- Create "class_sessions" table
+CREATE TABLE `class_sessions` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` char(36) NOT NULL,
+  `course_id` bigint NOT NULL,
+  `weekday` tinyint NOT NULL,
+  `start_time` time NOT NULL,
+  `end_time` time NOT NULL,
+  `location` varchar(255) NULL,
+  `recurrence` tinyint NOT NULL DEFAULT 0,
+  `start_date` date NOT NULL,
+  `end_date` date NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_class_sessions_course_id` (`course_id`),
+  INDEX `idx_class_sessions_user_id` (`user_id`),
+  CONSTRAINT `fk_class_sessions_course` FOREIGN KEY (`course_id`) REFERENCES `courses` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "class_session_exceptions" table
+CREATE TABLE `class_session_exceptions` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `session_id` bigint NOT NULL,
+  `date` date NOT NULL,
+  `reason` varchar(255) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_class_session_exceptions_session_date` (`session_id`, `date`),
+  CONSTRAINT `fk_class_sessions_exceptions` FOREIGN KEY (`session_id`) REFERENCES `class_sessions` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
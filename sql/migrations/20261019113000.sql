@@ -0,0 +1,11 @@
+-- Modify "users" table
+ALTER TABLE `users` ADD COLUMN `timezone` varchar(64) NOT NULL DEFAULT "UTC" AFTER `is_phone_verified`;
+-- Create "calendar_feeds" table
+CREATE TABLE `calendar_feeds` (
+  `user_id` char(36) NOT NULL,
+  `token` varchar(64) NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`user_id`),
+  UNIQUE INDEX `idx_calendar_feeds_token` (`token`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
@@ -0,0 +1,2 @@
+-- Modify "class_sessions" table
+ALTER TABLE `class_sessions` ADD COLUMN `external_uid` varchar(255) NULL AFTER `end_date`, ADD UNIQUE INDEX `idx_class_sessions_user_external_uid` (`user_id`, `external_uid`);
@@ -0,0 +1,22 @@
+-- Modify "users" table
+ALTER TABLE `users` ADD COLUMN `tier` varchar(20) NOT NULL DEFAULT "free" AFTER `timezone`;
+-- Create "files" table
+CREATE TABLE `files` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` char(36) NOT NULL,
+  `course_id` bigint NULL,
+  `storage_key` varchar(512) NOT NULL,
+  `filename` varchar(255) NOT NULL,
+  `mime_type` varchar(127) NOT NULL,
+  `size` bigint NOT NULL,
+  `checksum` char(64) NULL,
+  `status` tinyint NOT NULL DEFAULT 0,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_files_course_id` (`course_id`),
+  INDEX `idx_files_status` (`status`),
+  UNIQUE INDEX `idx_files_storage_key` (`storage_key`),
+  INDEX `idx_files_user_id` (`user_id`),
+  CONSTRAINT `fk_files_course` FOREIGN KEY (`course_id`) REFERENCES `courses` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
@@ -0,0 +1,21 @@
+-- Modify "files" table
+ALTER TABLE `files` ADD COLUMN `extraction_status` tinyint NOT NULL DEFAULT 0 AFTER `status`, ADD COLUMN `extraction_error` varchar(1000) NULL AFTER `extraction_status`, ADD COLUMN `page_count` bigint NOT NULL DEFAULT 0 AFTER `extraction_error`, ADD COLUMN `extracted_at` datetime(3) NULL AFTER `page_count`;
+-- Create "file_chunks" table
+CREATE TABLE `file_chunks` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `file_id` bigint NOT NULL,
+  `user_id` char(36) NOT NULL,
+  `course_id` bigint NULL,
+  `chunk_index` bigint NOT NULL,
+  `page_start` bigint NOT NULL,
+  `page_end` bigint NOT NULL,
+  `content` text NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_file_chunks_course_id` (`course_id`),
+  UNIQUE INDEX `idx_file_chunks_file_index` (`file_id`, `chunk_index`),
+  INDEX `idx_file_chunks_user_id` (`user_id`),
+  CONSTRAINT `fk_file_chunks_course` FOREIGN KEY (`course_id`) REFERENCES `courses` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `fk_files_chunks` FOREIGN KEY (`file_id`) REFERENCES `files` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
@@ -0,0 +1,8 @@
+-- Modify "courses" table
+ALTER TABLE `courses` ADD FULLTEXT INDEX `idx_courses_fulltext` (`course_name`, `lecturers`);
+-- Modify "file_chunks" table
+ALTER TABLE `file_chunks` ADD FULLTEXT INDEX `idx_file_chunks_fulltext` (`content`);
+-- Modify "notes" table
+ALTER TABLE `notes` ADD FULLTEXT INDEX `idx_notes_fulltext` (`title`, `content_text`);
+-- Modify "reminders" table
+ALTER TABLE `reminders` ADD FULLTEXT INDEX `idx_reminders_fulltext` (`title`, `description`);
@@ -0,0 +1,26 @@
+-- Create "note_summaries" table
+CREATE TABLE `note_summaries` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `note_id` bigint NOT NULL,
+  `user_id` char(36) NOT NULL,
+  `version` bigint NOT NULL,
+  `note_version` bigint NOT NULL,
+  `language` varchar(16) NOT NULL,
+  `status` tinyint NOT NULL DEFAULT 0,
+  `error` varchar(1000) NULL,
+  `bullets` json NULL,
+  `key_concepts` json NULL,
+  `provider` varchar(32) NOT NULL DEFAULT "",
+  `model` varchar(128) NOT NULL DEFAULT "",
+  `prompt_version` varchar(32) NOT NULL,
+  `chunk_count` bigint NOT NULL DEFAULT 0,
+  `input_tokens` bigint NOT NULL DEFAULT 0,
+  `output_tokens` bigint NOT NULL DEFAULT 0,
+  `completed_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_note_summaries_note_version` (`note_id`, `version`),
+  INDEX `idx_note_summaries_user_id` (`user_id`),
+  CONSTRAINT `fk_notes_summaries` FOREIGN KEY (`note_id`) REFERENCES `notes` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
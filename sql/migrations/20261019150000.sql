@@ -0,0 +1,74 @@
+-- Create "flashcard_generations" table
+CREATE TABLE `flashcard_generations` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` char(36) NOT NULL,
+  `note_id` bigint NULL,
+  `file_id` bigint NULL,
+  `language` varchar(16) NOT NULL,
+  `count` bigint NOT NULL,
+  `created_count` bigint NOT NULL DEFAULT 0,
+  `status` tinyint NOT NULL DEFAULT 0,
+  `error` varchar(1000) NULL,
+  `provider` varchar(32) NOT NULL DEFAULT "",
+  `model` varchar(128) NOT NULL DEFAULT "",
+  `prompt_version` varchar(32) NOT NULL,
+  `input_tokens` bigint NOT NULL DEFAULT 0,
+  `output_tokens` bigint NOT NULL DEFAULT 0,
+  `completed_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_flashcard_generations_file_id` (`file_id`),
+  INDEX `idx_flashcard_generations_note_id` (`note_id`),
+  INDEX `idx_flashcard_generations_user_id` (`user_id`),
+  CONSTRAINT `fk_flashcard_generations_file` FOREIGN KEY (`file_id`) REFERENCES `files` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `fk_flashcard_generations_note` FOREIGN KEY (`note_id`) REFERENCES `notes` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "flashcards" table
+CREATE TABLE `flashcards` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` char(36) NOT NULL,
+  `course_id` bigint NULL,
+  `generation_id` bigint NULL,
+  `note_id` bigint NULL,
+  `file_id` bigint NULL,
+  `chunk_index` bigint NULL,
+  `page_start` bigint NULL,
+  `page_end` bigint NULL,
+  `front` text NOT NULL,
+  `back` text NOT NULL,
+  `ease_factor` double NOT NULL DEFAULT 2.5,
+  `interval_days` bigint NOT NULL DEFAULT 0,
+  `repetitions` bigint NOT NULL DEFAULT 0,
+  `lapses` bigint NOT NULL DEFAULT 0,
+  `due_date` date NOT NULL,
+  `last_reviewed_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_flashcards_course_id` (`course_id`),
+  INDEX `idx_flashcards_file_id` (`file_id`),
+  INDEX `idx_flashcards_generation_id` (`generation_id`),
+  INDEX `idx_flashcards_note_id` (`note_id`),
+  INDEX `idx_flashcards_user_due` (`user_id`, `due_date`),
+  CONSTRAINT `fk_flashcards_course` FOREIGN KEY (`course_id`) REFERENCES `courses` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `fk_flashcards_file` FOREIGN KEY (`file_id`) REFERENCES `files` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `fk_flashcards_generation` FOREIGN KEY (`generation_id`) REFERENCES `flashcard_generations` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `fk_flashcards_note` FOREIGN KEY (`note_id`) REFERENCES `notes` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "flashcard_reviews" table
+CREATE TABLE `flashcard_reviews` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `flashcard_id` bigint NOT NULL,
+  `user_id` char(36) NOT NULL,
+  `grade` tinyint NOT NULL,
+  `ease_factor` double NOT NULL,
+  `interval_days` bigint NOT NULL,
+  `due_date` date NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_flashcard_reviews_flashcard_id` (`flashcard_id`),
+  INDEX `idx_flashcard_reviews_user_id` (`user_id`),
+  CONSTRAINT `fk_flashcards_reviews` FOREIGN KEY (`flashcard_id`) REFERENCES `flashcards` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
@@ -0,0 +1,86 @@
+-- Create "quizzes" table
+CREATE TABLE `quizzes` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` char(36) NOT NULL,
+  `course_id` bigint NOT NULL,
+  `title` varchar(255) NOT NULL,
+  `difficulty` varchar(16) NOT NULL,
+  `types` varchar(64) NOT NULL,
+  `language` varchar(16) NOT NULL,
+  `count` bigint NOT NULL,
+  `status` tinyint NOT NULL DEFAULT 0,
+  `error` varchar(1000) NULL,
+  `provider` varchar(32) NOT NULL DEFAULT "",
+  `model` varchar(128) NOT NULL DEFAULT "",
+  `prompt_version` varchar(32) NOT NULL,
+  `input_tokens` bigint NOT NULL DEFAULT 0,
+  `output_tokens` bigint NOT NULL DEFAULT 0,
+  `completed_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_quizzes_course_id` (`course_id`),
+  INDEX `idx_quizzes_user_id` (`user_id`),
+  CONSTRAINT `fk_quizzes_course` FOREIGN KEY (`course_id`) REFERENCES `courses` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "quiz_questions" table
+CREATE TABLE `quiz_questions` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `quiz_id` bigint NOT NULL,
+  `position` bigint NOT NULL,
+  `type` varchar(16) NOT NULL,
+  `difficulty` varchar(16) NOT NULL,
+  `prompt` text NOT NULL,
+  `options` json NULL,
+  `answer_index` bigint NULL,
+  `answer_bool` bool NULL,
+  `reference_answer` text NOT NULL,
+  `rubric` json NULL,
+  `explanation` text NOT NULL,
+  `note_id` bigint NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_quiz_questions_note_id` (`note_id`),
+  UNIQUE INDEX `idx_quiz_questions_quiz_position` (`quiz_id`, `position`),
+  CONSTRAINT `fk_quiz_questions_note` FOREIGN KEY (`note_id`) REFERENCES `notes` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `fk_quizzes_questions` FOREIGN KEY (`quiz_id`) REFERENCES `quizzes` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "quiz_attempts" table
+CREATE TABLE `quiz_attempts` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `quiz_id` bigint NOT NULL,
+  `user_id` char(36) NOT NULL,
+  `status` tinyint NOT NULL DEFAULT 0,
+  `score` double NOT NULL DEFAULT 0,
+  `max_score` bigint NOT NULL DEFAULT 0,
+  `error` varchar(1000) NULL,
+  `submitted_at` datetime(3) NULL,
+  `graded_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_quiz_attempts_quiz_id` (`quiz_id`),
+  INDEX `idx_quiz_attempts_user_id` (`user_id`),
+  CONSTRAINT `fk_quizzes_attempts` FOREIGN KEY (`quiz_id`) REFERENCES `quizzes` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "quiz_answers" table
+CREATE TABLE `quiz_answers` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `attempt_id` bigint NOT NULL,
+  `question_id` bigint NOT NULL,
+  `user_id` char(36) NOT NULL,
+  `response` text NOT NULL,
+  `is_correct` bool NOT NULL DEFAULT 0,
+  `score` double NULL,
+  `feedback` text NOT NULL,
+  `graded_by` varchar(16) NOT NULL DEFAULT "",
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_quiz_answers_attempt_question` (`attempt_id`, `question_id`),
+  INDEX `idx_quiz_answers_question_id` (`question_id`),
+  INDEX `idx_quiz_answers_user_id` (`user_id`),
+  CONSTRAINT `fk_quiz_answers_question` FOREIGN KEY (`question_id`) REFERENCES `quiz_questions` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT `fk_quiz_attempts_answers` FOREIGN KEY (`attempt_id`) REFERENCES `quiz_attempts` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
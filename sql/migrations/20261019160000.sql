@@ -0,0 +1,57 @@
+-- Create "assistant_conversations" table
+CREATE TABLE `assistant_conversations` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` char(36) NOT NULL,
+  `course_id` bigint NULL,
+  `title` varchar(255) NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_assistant_conversations_course_id` (`course_id`),
+  INDEX `idx_assistant_conversations_user_id` (`user_id`),
+  CONSTRAINT `fk_assistant_conversations_course` FOREIGN KEY (`course_id`) REFERENCES `courses` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "assistant_messages" table
+CREATE TABLE `assistant_messages` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `conversation_id` bigint NOT NULL,
+  `user_id` char(36) NOT NULL,
+  `role` varchar(16) NOT NULL,
+  `content` longtext NOT NULL,
+  `citations` json NULL,
+  `provider` varchar(32) NOT NULL DEFAULT "",
+  `model` varchar(128) NOT NULL DEFAULT "",
+  `input_tokens` bigint NOT NULL DEFAULT 0,
+  `output_tokens` bigint NOT NULL DEFAULT 0,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_assistant_messages_conversation_id` (`conversation_id`),
+  INDEX `idx_assistant_messages_user_id` (`user_id`),
+  CONSTRAINT `fk_assistant_conversations_messages` FOREIGN KEY (`conversation_id`) REFERENCES `assistant_conversations` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "embeddings" table
+CREATE TABLE `embeddings` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` char(36) NOT NULL,
+  `course_id` bigint NULL,
+  `note_id` bigint NULL,
+  `file_id` bigint NULL,
+  `chunk_index` bigint NOT NULL,
+  `page_start` bigint NULL,
+  `page_end` bigint NULL,
+  `content` text NOT NULL,
+  `model` varchar(128) NOT NULL,
+  `dimensions` bigint NOT NULL,
+  `vector` blob NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_embeddings_course_id` (`course_id`),
+  INDEX `idx_embeddings_file_id` (`file_id`),
+  INDEX `idx_embeddings_note_id` (`note_id`),
+  INDEX `idx_embeddings_user_model` (`user_id`, `model`),
+  CONSTRAINT `fk_embeddings_course` FOREIGN KEY (`course_id`) REFERENCES `courses` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `fk_embeddings_file` FOREIGN KEY (`file_id`) REFERENCES `files` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT `fk_embeddings_note` FOREIGN KEY (`note_id`) REFERENCES `notes` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
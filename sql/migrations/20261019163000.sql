@@ -0,0 +1,50 @@
+-- Create "plans" table
+CREATE TABLE `plans` (
+  `code` varchar(20) NOT NULL,
+  `name` varchar(64) NOT NULL,
+  `price_cents` bigint NOT NULL DEFAULT 0,
+  `currency` varchar(3) NOT NULL DEFAULT "USD",
+  `max_courses` bigint NULL,
+  `ai_operations` bigint NULL,
+  `ai_tokens` bigint NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`code`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Seed "plans" table
+INSERT INTO `plans` (`code`, `name`, `price_cents`, `currency`, `max_courses`, `ai_operations`, `ai_tokens`, `created_at`, `updated_at`) VALUES
+  ("free", "Free", 0, "USD", 3, 10, 200000, NOW(3), NOW(3)),
+  ("student", "Student", 699, "USD", NULL, NULL, NULL, NOW(3), NOW(3)),
+  ("premium", "Premium", 1299, "USD", NULL, NULL, NULL, NOW(3), NOW(3));
+-- Create "subscriptions" table
+CREATE TABLE `subscriptions` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` char(36) NOT NULL,
+  `plan_code` varchar(20) NOT NULL,
+  `status` varchar(20) NOT NULL,
+  `current_period_start` datetime(3) NOT NULL,
+  `current_period_end` datetime(3) NOT NULL,
+  `canceled_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_subscriptions_plan_code` (`plan_code`),
+  UNIQUE INDEX `idx_subscriptions_user_id` (`user_id`),
+  CONSTRAINT `fk_subscriptions_plan` FOREIGN KEY (`plan_code`) REFERENCES `plans` (`code`) ON UPDATE NO ACTION ON DELETE RESTRICT
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "usage_records" table
+CREATE TABLE `usage_records` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` char(36) NOT NULL,
+  `operation` varchar(32) NOT NULL,
+  `reference` varchar(64) NOT NULL DEFAULT "",
+  `provider` varchar(32) NOT NULL DEFAULT "",
+  `model` varchar(128) NOT NULL DEFAULT "",
+  `input_tokens` bigint NOT NULL DEFAULT 0,
+  `output_tokens` bigint NOT NULL DEFAULT 0,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_usage_records_user_created` (`user_id`, `created_at`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Move "pro" users to the "student" plan
+UPDATE `users` SET `tier` = "student" WHERE `tier` = "pro";
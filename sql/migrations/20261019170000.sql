@@ -0,0 +1,15 @@
+-- Create "billing_events" table
+CREATE TABLE `billing_events` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `provider` varchar(16) NOT NULL,
+  `event_id` varchar(128) NOT NULL,
+  `type` varchar(64) NOT NULL,
+  `user_id` char(36) NULL,
+  `payload` json NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_billing_events_provider_event` (`provider`, `event_id`),
+  INDEX `idx_billing_events_user_id` (`user_id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Modify "subscriptions" table
+ALTER TABLE `subscriptions` ADD COLUMN `provider` varchar(16) NOT NULL DEFAULT "" AFTER `canceled_at`, ADD COLUMN `provider_subscription_id` varchar(64) NULL AFTER `provider`, ADD COLUMN `provider_customer_id` varchar(64) NOT NULL DEFAULT "" AFTER `provider_subscription_id`, ADD COLUMN `trial_ends_at` datetime(3) NULL AFTER `provider_customer_id`, ADD COLUMN `cancel_at_period_end` bool NOT NULL DEFAULT 0 AFTER `trial_ends_at`, ADD COLUMN `grace_ends_at` datetime(3) NULL AFTER `cancel_at_period_end`, ADD COLUMN `last_event_at` datetime(3) NULL AFTER `grace_ends_at`, ADD UNIQUE INDEX `idx_subscriptions_provider_subscription` (`provider`, `provider_subscription_id`);
@@ -0,0 +1,49 @@
+-- Modify "courses" table
+ALTER TABLE `courses` ADD COLUMN `difficulty` tinyint NULL AFTER `gpa`;
+-- Create "study_plans" table
+CREATE TABLE `study_plans` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` char(36) NOT NULL,
+  `status` tinyint NOT NULL DEFAULT 0,
+  `starts_at` datetime(3) NOT NULL,
+  `ends_at` datetime(3) NOT NULL,
+  `input_hash` char(64) NOT NULL,
+  `shortfalls` json NULL,
+  `accepted_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_study_plans_user_id` (`user_id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "study_blocks" table
+CREATE TABLE `study_blocks` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `plan_id` bigint NOT NULL,
+  `user_id` char(36) NOT NULL,
+  `course_id` bigint NOT NULL,
+  `reminder_id` bigint NULL,
+  `start_at` datetime(3) NOT NULL,
+  `end_at` datetime(3) NOT NULL,
+  `adjusted` bool NOT NULL DEFAULT 0,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_study_blocks_course_id` (`course_id`),
+  INDEX `idx_study_blocks_plan_id` (`plan_id`),
+  INDEX `idx_study_blocks_reminder_id` (`reminder_id`),
+  INDEX `idx_study_blocks_user_id` (`user_id`),
+  CONSTRAINT `fk_study_blocks_course` FOREIGN KEY (`course_id`) REFERENCES `courses` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT `fk_study_blocks_reminder` FOREIGN KEY (`reminder_id`) REFERENCES `reminders` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `fk_study_plans_blocks` FOREIGN KEY (`plan_id`) REFERENCES `study_plans` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "study_preferences" table
+CREATE TABLE `study_preferences` (
+  `user_id` char(36) NOT NULL,
+  `windows` json NOT NULL,
+  `block_minutes` bigint NOT NULL DEFAULT 50,
+  `break_minutes` bigint NOT NULL DEFAULT 10,
+  `max_daily_minutes` bigint NOT NULL DEFAULT 240,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`user_id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
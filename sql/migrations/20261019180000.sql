@@ -0,0 +1,19 @@
+-- Create "study_sessions" table
+CREATE TABLE `study_sessions` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` char(36) NOT NULL,
+  `course_id` bigint NOT NULL,
+  `note_id` bigint NULL,
+  `started_at` datetime(3) NOT NULL,
+  `ended_at` datetime(3) NULL,
+  `duration_seconds` bigint NOT NULL DEFAULT 0,
+  `pomodoros` bigint NOT NULL DEFAULT 0,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_study_sessions_course_id` (`course_id`),
+  INDEX `idx_study_sessions_note_id` (`note_id`),
+  INDEX `idx_study_sessions_user_started` (`user_id`, `started_at`),
+  CONSTRAINT `fk_study_sessions_course` FOREIGN KEY (`course_id`) REFERENCES `courses` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT `fk_study_sessions_note` FOREIGN KEY (`note_id`) REFERENCES `notes` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
@@ -0,0 +1,4 @@
+-- Modify "reminders" table
+ALTER TABLE `reminders` ADD COLUMN `score` double NULL AFTER `weight`;
+-- Modify "users" table
+ALTER TABLE `users` ADD COLUMN `required_credits` bigint NULL AFTER `tier`;
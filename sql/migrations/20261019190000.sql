@@ -0,0 +1,17 @@
+-- Create "assessments" table
+CREATE TABLE `assessments` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `course_id` bigint NOT NULL,
+  `user_id` char(36) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `weight` double NOT NULL,
+  `max_score` double NOT NULL,
+  `score` double NULL,
+  `due_date` date NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_assessments_course_id` (`course_id`),
+  INDEX `idx_assessments_user_id` (`user_id`),
+  CONSTRAINT `fk_assessments_course` FOREIGN KEY (`course_id`) REFERENCES `courses` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
@@ -0,0 +1,53 @@
+-- Create "study_groups" table
+CREATE TABLE `study_groups` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  `description` text NOT NULL,
+  `course_code` varchar(255) NOT NULL,
+  `course_name` varchar(255) NOT NULL,
+  `owner_id` char(36) NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_study_groups_course_code` (`course_code`),
+  INDEX `idx_study_groups_owner_id` (`owner_id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "study_group_members" table
+CREATE TABLE `study_group_members` (
+  `group_id` bigint NOT NULL,
+  `user_id` char(36) NOT NULL,
+  `role` tinyint NOT NULL DEFAULT 0,
+  `joined_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`group_id`, `user_id`),
+  INDEX `idx_study_group_members_user_id` (`user_id`),
+  CONSTRAINT `fk_study_group_members_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`user_id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT `fk_study_groups_members` FOREIGN KEY (`group_id`) REFERENCES `study_groups` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "study_group_invitations" table
+CREATE TABLE `study_group_invitations` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `group_id` bigint NOT NULL,
+  `email` varchar(255) NULL,
+  `token` char(64) NOT NULL,
+  `invited_by` char(36) NOT NULL,
+  `expires_at` datetime(3) NOT NULL,
+  `accepted_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_study_group_invitations_group_id` (`group_id`),
+  UNIQUE INDEX `idx_study_group_invitations_token` (`token`),
+  CONSTRAINT `fk_study_group_invitations_group` FOREIGN KEY (`group_id`) REFERENCES `study_groups` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "study_group_notes" table
+CREATE TABLE `study_group_notes` (
+  `group_id` bigint NOT NULL,
+  `note_id` bigint NOT NULL,
+  `shared_by` char(36) NOT NULL,
+  `shared_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`group_id`, `note_id`),
+  INDEX `idx_study_group_notes_note_id` (`note_id`),
+  INDEX `idx_study_group_notes_shared_by` (`shared_by`),
+  CONSTRAINT `fk_study_group_notes_group` FOREIGN KEY (`group_id`) REFERENCES `study_groups` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT `fk_study_group_notes_note` FOREIGN KEY (`note_id`) REFERENCES `notes` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
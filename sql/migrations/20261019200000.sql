@@ -0,0 +1,14 @@
+-- Create "study_group_messages" table
+CREATE TABLE `study_group_messages` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `group_id` bigint NOT NULL,
+  `user_id` char(36) NOT NULL,
+  `body` text NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_study_group_messages_group_id` (`group_id`),
+  INDEX `idx_study_group_messages_user_id` (`user_id`),
+  CONSTRAINT `fk_study_group_messages_group` FOREIGN KEY (`group_id`) REFERENCES `study_groups` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT `fk_study_group_messages_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`user_id`) ON UPDATE NO ACTION ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
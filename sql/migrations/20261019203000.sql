@@ -0,0 +1,16 @@
+-- Modify "reminder_notifications" table
+ALTER TABLE `reminder_notifications` ADD COLUMN `deadline` datetime(3) NULL AFTER `offset_minutes`;
+-- Pending and failed notifications are rebuilt by the next scan with their deadline set
+DELETE FROM `reminder_notifications` WHERE `status` IN (0, 3);
+-- Backfill the deadline of the remaining notifications from their reminder. scheduled_at
+-- cannot be used as it moves forward on retries. CONVERT_TZ needs the time zone tables;
+-- without them the user's wall clock is kept as is.
+UPDATE `reminder_notifications` AS `n`
+  JOIN `reminders` AS `r` ON `r`.`id` = `n`.`reminder_id`
+  JOIN `users` AS `u` ON `u`.`user_id` = `r`.`user_id`
+SET `n`.`deadline` = COALESCE(
+  CONVERT_TZ(TIMESTAMP(`r`.`due_date`, `r`.`due_time`), `u`.`timezone`, '+00:00'),
+  TIMESTAMP(`r`.`due_date`, `r`.`due_time`)
+);
+-- Modify "reminder_notifications" table
+ALTER TABLE `reminder_notifications` MODIFY COLUMN `deadline` datetime(3) NOT NULL, DROP INDEX `idx_reminder_notifications_reminder_offset`, ADD UNIQUE INDEX `idx_reminder_notifications_reminder_offset_deadline` (`reminder_id`, `offset_minutes`, `deadline`);
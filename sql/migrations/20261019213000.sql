@@ -0,0 +1,2 @@
+-- Modify "class_sessions" table
+ALTER TABLE `class_sessions` ADD COLUMN `import_source` varchar(255) NOT NULL DEFAULT '' AFTER `external_uid`, DROP INDEX `idx_class_sessions_user_external_uid`, ADD UNIQUE INDEX `idx_class_sessions_user_source_external_uid` (`user_id`, `import_source`, `external_uid`);
@@ -0,0 +1,2 @@
+-- Modify "usage_records" table
+ALTER TABLE `usage_records` ADD COLUMN `reserved` bool NOT NULL DEFAULT 0 AFTER `output_tokens`;
@@ -0,0 +1,4 @@
+-- Modify "files" table
+ALTER TABLE `files` ADD COLUMN `upload_id` char(32) NULL AFTER `status`, ADD COLUMN `upload_expires_at` datetime(3) NULL AFTER `upload_id`, ADD UNIQUE INDEX `idx_files_upload_id` (`upload_id`), ADD INDEX `idx_files_upload_expires_at` (`upload_expires_at`);
+-- Uploads announced without an upload ID can no longer be completed; hand them to the sweep
+UPDATE `files` SET `upload_expires_at` = `created_at` WHERE `status` = 0;
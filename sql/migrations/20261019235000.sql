@@ -0,0 +1,2 @@
+-- Modify "flashcards" table
+ALTER TABLE `flashcards` ADD FULLTEXT INDEX `idx_flashcards_fulltext` (`front`, `back`);
@@ -0,0 +1,227 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/pkg/ai"
+)
+
+var fastRetries = ai.Options{Timeout: 2 * time.Second, MaxRetries: 2, BackoffBase: time.Millisecond, BackoffMax: 5 * time.Millisecond}
+
+func TestOpenAIChatRetriesRateLimits(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"error":{"type":"rate_limit_exceeded","message":"slow down"}}`)
+			return
+		}
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer sk-test" {
+			t.Errorf("unexpected request %s %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		messages := body["messages"].([]any)
+		if len(messages) != 2 || messages[0].(map[string]any)["role"] != "system" || body["model"] != "gpt-test" {
+			t.Errorf("unexpected body %v", body)
+		}
+		if format, _ := body["response_format"].(map[string]any); format["type"] != "json_object" {
+			t.Errorf("JSON mode not requested: %v", body["response_format"])
+		}
+		io.WriteString(w, `{"model":"gpt-test","choices":[{"message":{"role":"assistant","content":"{\"ok\":true}"},"finish_reason":"stop"}],"usage":{"prompt_tokens":12,"completion_tokens":4}}`)
+	}))
+	defer server.Close()
+
+	provider, err := ai.NewOpenAIProvider(ai.OpenAIConfig{BaseURL: server.URL + "/v1", APIKey: "sk-test", ChatModel: "gpt-test", Options: fastRetries})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := provider.Chat(context.Background(), ai.ChatRequest{
+		System:   "be brief",
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "hi"}},
+		JSON:     true,
+	})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if calls.Load() != 2 || resp.Content != `{"ok":true}` || resp.FinishReason != "stop" || resp.Usage.InputTokens != 12 {
+		t.Fatalf("calls = %d, resp = %+v", calls.Load(), resp)
+	}
+}
+
+func TestOpenAIChatDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"error":{"type":"invalid_request_error","message":"context too long"}}`)
+	}))
+	defer server.Close()
+
+	provider, _ := ai.NewOpenAIProvider(ai.OpenAIConfig{BaseURL: server.URL, ChatModel: "gpt-test", Options: fastRetries})
+	_, err := provider.Chat(context.Background(), ai.ChatRequest{Messages: []ai.Message{{Role: ai.RoleUser, Content: "hi"}}})
+
+	var apiErr *ai.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "context too long" {
+		t.Fatalf("err = %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestOpenAIStreamAndEmbeddings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/chat/completions":
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, chunk := range []string{
+				`{"model":"gpt-test","choices":[{"delta":{"role":"assistant","content":"Hel"}}]}`,
+				`{"choices":[{"delta":{"content":"lo"},"finish_reason":"stop"}]}`,
+				`{"choices":[],"usage":{"prompt_tokens":3,"completion_tokens":2}}`,
+				`[DONE]`,
+			} {
+				fmt.Fprintf(w, "data: %s\n\n", chunk)
+			}
+		case "/embeddings":
+			// Out of order on purpose
+			io.WriteString(w, `{"model":"emb","data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}],"usage":{"prompt_tokens":2}}`)
+		}
+	}))
+	defer server.Close()
+
+	provider, _ := ai.NewOpenAIProvider(ai.OpenAIConfig{BaseURL: server.URL, ChatModel: "gpt-test", EmbeddingModel: "emb", Options: fastRetries})
+
+	var deltas []string
+	resp, err := provider.Stream(context.Background(), ai.ChatRequest{Messages: []ai.Message{{Role: ai.RoleUser, Content: "hi"}}}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	if strings.Join(deltas, "|") != "Hel|lo" || resp.Content != "Hello" || resp.FinishReason != "stop" || resp.Usage.OutputTokens != 2 {
+		t.Fatalf("deltas = %v, resp = %+v", deltas, resp)
+	}
+
+	embeddings, err := provider.Embed(context.Background(), ai.EmbeddingRequest{Input: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if embeddings.Vectors[0][0] != 1 || embeddings.Vectors[1][1] != 1 {
+		t.Fatalf("vectors not in input order: %v", embeddings.Vectors)
+	}
+}
+
+func TestAnthropicStreamAndErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "key" || r.Header.Get("anthropic-version") == "" {
+			t.Errorf("missing auth headers")
+		}
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+
+		switch {
+		case r.URL.Path == "/v1/messages/count_tokens":
+			io.WriteString(w, `{"input_tokens":42}`)
+		case body["system"] == "fail":
+			io.WriteString(w, "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"model\":\"claude-test\",\"usage\":{\"input_tokens\":5}}}\n\n")
+			io.WriteString(w, "event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n")
+		default:
+			if body["max_tokens"] == nil {
+				t.Errorf("max_tokens is required by the API")
+			}
+			for _, event := range []string{
+				`{"type":"message_start","message":{"model":"claude-test","usage":{"input_tokens":5}}}`,
+				`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+				`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hi "}}`,
+				`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"there"}}`,
+				`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":2}}`,
+				`{"type":"message_stop"}`,
+			} {
+				fmt.Fprintf(w, "event: x\ndata: %s\n\n", event)
+			}
+		}
+	}))
+	defer server.Close()
+
+	provider, _ := ai.NewAnthropicProvider(ai.AnthropicConfig{BaseURL: server.URL, APIKey: "key", ChatModel: "claude-test", Options: fastRetries})
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "hello"}}
+
+	resp, err := provider.Stream(context.Background(), ai.ChatRequest{Messages: messages}, func(string) error { return nil })
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	if resp.Content != "Hi there" || resp.FinishReason != "end_turn" || resp.Usage != (ai.Usage{InputTokens: 5, OutputTokens: 2}) {
+		t.Fatalf("resp = %+v", resp)
+	}
+
+	_, err = provider.Stream(context.Background(), ai.ChatRequest{System: "fail", Messages: messages}, func(string) error { return nil })
+	var apiErr *ai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != "overloaded_error" {
+		t.Fatalf("err = %v, want overloaded_error", err)
+	}
+
+	if count, err := provider.CountTokens(context.Background(), ai.ChatRequest{Messages: messages}); err != nil || count != 42 {
+		t.Fatalf("CountTokens = %d, %v", count, err)
+	}
+	if _, err := provider.Embed(context.Background(), ai.EmbeddingRequest{Input: []string{"x"}}); !errors.Is(err, ai.ErrUnsupported) {
+		t.Fatalf("Embed err = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestFakeProviderIsDeterministic(t *testing.T) {
+	fake := ai.NewFakeProvider()
+	fake.Replies = []string{"scripted answer"}
+	req := ai.ChatRequest{Messages: []ai.Message{{Role: ai.RoleUser, Content: "What is  a heap?"}}}
+
+	first, _ := fake.Chat(context.Background(), req)
+	var streamed strings.Builder
+	second, err := fake.Stream(context.Background(), req, func(delta string) error {
+		streamed.WriteString(delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Content != "scripted answer" || second.Content != "Fake reply: What is a heap?" || streamed.String() != second.Content {
+		t.Fatalf("first = %q, second = %q, streamed = %q", first.Content, second.Content, streamed.String())
+	}
+	if len(fake.Requests()) != 2 {
+		t.Fatalf("requests = %d", len(fake.Requests()))
+	}
+
+	embeddings, _ := fake.Embed(context.Background(), ai.EmbeddingRequest{Input: []string{
+		"binary heap priority queue",
+		"a priority queue backed by a binary heap",
+		"photosynthesis in plant cells",
+	}})
+	again, _ := fake.Embed(context.Background(), ai.EmbeddingRequest{Input: []string{"binary heap priority queue"}})
+	if cosine(embeddings.Vectors[0], again.Vectors[0]) < 0.9999 {
+		t.Fatal("embeddings are not deterministic")
+	}
+	if related, unrelated := cosine(embeddings.Vectors[0], embeddings.Vectors[1]), cosine(embeddings.Vectors[0], embeddings.Vectors[2]); related <= unrelated {
+		t.Fatalf("related = %f, unrelated = %f", related, unrelated)
+	}
+}
+
+func cosine(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	return dot / math.Sqrt(na*nb)
+}
@@ -0,0 +1,165 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// memoryAssessmentRepository keeps the assessments of a single course
+type memoryAssessmentRepository struct {
+	repositories.IAssessmentRepository
+	assessments []models.Assessment
+	nextID      int
+}
+
+func (r *memoryAssessmentRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return fn(nil)
+}
+
+func (r *memoryAssessmentRepository) WithTx(tx *gorm.DB) repositories.IAssessmentRepository {
+	return r
+}
+
+func (r *memoryAssessmentRepository) ListAssessments(ctx context.Context, courseID int, userID string) ([]models.Assessment, error) {
+	return append([]models.Assessment(nil), r.assessments...), nil
+}
+
+func (r *memoryAssessmentRepository) GetAssessmentByID(ctx context.Context, id, courseID int, userID string) (*models.Assessment, error) {
+	for _, assessment := range r.assessments {
+		if assessment.ID == id {
+			return &assessment, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryAssessmentRepository) CreateAssessments(ctx context.Context, assessments []models.Assessment) error {
+	for _, assessment := range assessments {
+		r.nextID++
+		assessment.ID = r.nextID
+		r.assessments = append(r.assessments, assessment)
+	}
+	return nil
+}
+
+func (r *memoryAssessmentRepository) UpdateAssessment(ctx context.Context, id int, userID string, updates map[string]any) error {
+	for i := range r.assessments {
+		if r.assessments[i].ID != id {
+			continue
+		}
+		if score, ok := updates["score"]; ok {
+			r.assessments[i].Score = score.(sql.NullFloat64)
+		}
+		if weight, ok := updates["weight"]; ok {
+			r.assessments[i].Weight = weight.(float64)
+		}
+	}
+	return nil
+}
+
+func (r *memoryAssessmentRepository) DeleteAssessments(ctx context.Context, courseID int, userID string, keepIDs []int) error {
+	kept := r.assessments[:0]
+	for _, assessment := range r.assessments {
+		for _, id := range keepIDs {
+			if assessment.ID == id {
+				kept = append(kept, assessment)
+				break
+			}
+		}
+	}
+	r.assessments = kept
+	return nil
+}
+
+// gradedCourseRepository holds one course and records GPA updates
+type gradedCourseRepository struct {
+	repositories.ICourseRepository
+	course models.Course
+}
+
+func (r *gradedCourseRepository) WithTx(tx *gorm.DB) repositories.ICourseRepository {
+	return r
+}
+
+func (r *gradedCourseRepository) GetCourseByID(ctx context.Context, id int, userID string) (*models.Course, error) {
+	if id != r.course.ID || userID != r.course.UserID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	course := r.course
+	return &course, nil
+}
+
+func (r *gradedCourseRepository) UpdateCourse(ctx context.Context, id int, userID string, updates map[string]any) error {
+	if gpa, ok := updates["gpa"]; ok {
+		r.course.GPA = float32(gpa.(float64))
+	}
+	return nil
+}
+
+func TestAssessmentsComputeGradeAndFeedCourseGPA(t *testing.T) {
+	global.Log = zap.NewNop()
+	ctx := context.Background()
+	courses := &gradedCourseRepository{course: models.Course{ID: 7, UserID: "u1", GPA: 3.0}}
+	assessments := &memoryAssessmentRepository{}
+	service := services.NewAssessmentService(assessments, courses)
+
+	score := func(v float64) *float64 { return &v }
+	req := &models.ReplaceAssessmentsRequest{Assessments: []models.AssessmentInput{
+		{Name: "Midterm", Weight: 30, MaxScore: 10, Score: score(8)},
+		{Name: "Assignment", Weight: 20, MaxScore: 50, Score: score(45)},
+		{Name: "Final", Weight: 40, MaxScore: 100},
+	}}
+	if _, code := service.ReplaceAssessments(ctx, "u1", 7, req); code != response.CodeInvalidAssessmentWeights {
+		t.Fatalf("weights adding to 90: code = %d, want %d", code, response.CodeInvalidAssessmentWeights)
+	}
+
+	req.Assessments[2].Weight = 50
+	summary, code := service.ReplaceAssessments(ctx, "u1", 7, req)
+	if code != response.CodeSuccess {
+		t.Fatalf("ReplaceAssessments code = %d", code)
+	}
+	// 30% at 80 and 20% at 90 secure 42 of the final grade
+	if summary.GradedWeight != 50 || summary.SecuredGrade != 42 || summary.CurrentGrade == nil || *summary.CurrentGrade != 84 {
+		t.Errorf("summary = graded %v, secured %v, current %v; want 50, 42, 84", summary.GradedWeight, summary.SecuredGrade, summary.CurrentGrade)
+	}
+	if summary.FinalGrade != nil || courses.course.GPA != 0 {
+		t.Errorf("partly graded course: final %v, GPA %v; want none and 0", summary.FinalGrade, courses.course.GPA)
+	}
+	for _, target := range summary.Targets {
+		switch target.GPA {
+		case 4.0: // 48 more points over a 50% final
+			if target.Needed == nil || *target.Needed != 96 {
+				t.Errorf("needed for 4.0 = %v, want 96", target.Needed)
+			}
+		case 1.0:
+			if !target.Secured {
+				t.Errorf("1.0 with 42 secured should be secured")
+			}
+		}
+	}
+
+	final := assessments.assessments[2].ID
+	if _, code := service.RecordScore(ctx, "u1", 7, final, &models.RecordAssessmentScoreRequest{Score: score(101)}); code != response.CodeInvalidAssessmentScore {
+		t.Errorf("score above max: code = %d, want %d", code, response.CodeInvalidAssessmentScore)
+	}
+	summary, code = service.RecordScore(ctx, "u1", 7, final, &models.RecordAssessmentScoreRequest{Score: score(90)})
+	if code != response.CodeSuccess {
+		t.Fatalf("RecordScore code = %d", code)
+	}
+	if summary.FinalGrade == nil || *summary.FinalGrade != 87 || courses.course.GPA != 3.7 {
+		t.Errorf("final %v, GPA %v; want 87 and 3.7", summary.FinalGrade, courses.course.GPA)
+	}
+
+	if _, code := service.RecordScore(ctx, "u2", 7, final, &models.RecordAssessmentScoreRequest{}); code != response.CodeCourseNotFound {
+		t.Errorf("other user's course: code = %d, want %d", code, response.CodeCourseNotFound)
+	}
+}
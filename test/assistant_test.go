@@ -0,0 +1,129 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/ai"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"github.com/nas03/scholar-ai/backend/pkg/vector"
+	"go.uber.org/zap"
+)
+
+func TestVectorTopKKeepsBestMatches(t *testing.T) {
+	query := vector.Normalize([]float32{1, 0})
+	top := vector.NewTopK[string](2)
+	for name, v := range map[string][]float32{"same": {2, 0}, "close": {1, 1}, "far": {0, 1}, "opposite": {-1, 0}} {
+		score, err := vector.Dot(query, vector.Normalize(v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		top.Push(name, score)
+	}
+	names, scores := top.Results()
+	if len(names) != 2 || names[0] != "same" || names[1] != "close" || scores[0] < 0.9999 {
+		t.Fatalf("names = %v, scores = %v", names, scores)
+	}
+
+	decoded, err := vector.Decode(vector.Encode([]float32{0.5, -1.25}))
+	if err != nil || decoded[0] != 0.5 || decoded[1] != -1.25 {
+		t.Fatalf("decoded = %v, err = %v", decoded, err)
+	}
+	if _, err := vector.Dot([]float32{1}, []float32{1, 2}); err == nil {
+		t.Fatal("expected a dimension mismatch")
+	}
+}
+
+// memoryAssistantRepository keeps one conversation and its messages
+type memoryAssistantRepository struct {
+	repositories.IAssistantRepository
+	conversation *models.AssistantConversation
+	messages     []models.AssistantMessage
+}
+
+func (r *memoryAssistantRepository) GetConversationByID(ctx context.Context, id int, userID string) (*models.AssistantConversation, error) {
+	conversation := *r.conversation
+	return &conversation, nil
+}
+
+func (r *memoryAssistantRepository) UpdateConversation(ctx context.Context, id int, userID string, updates map[string]any) error {
+	if title, ok := updates["title"]; ok {
+		r.conversation.Title = title.(string)
+	}
+	return nil
+}
+
+func (r *memoryAssistantRepository) CreateMessage(ctx context.Context, message *models.AssistantMessage) error {
+	message.ID = len(r.messages) + 1
+	r.messages = append(r.messages, *message)
+	return nil
+}
+
+func (r *memoryAssistantRepository) ListMessages(ctx context.Context, conversationID int, limit int) ([]models.AssistantMessage, error) {
+	return r.messages, nil
+}
+
+// memoryVectorStore returns fixed matches and records the last query
+type memoryVectorStore struct {
+	repositories.IVectorStore
+	matches []models.VectorMatch
+	query   models.VectorQuery
+}
+
+func (s *memoryVectorStore) Search(ctx context.Context, query models.VectorQuery) ([]models.VectorMatch, error) {
+	s.query = query
+	return s.matches, nil
+}
+
+func TestSendMessageStreamsAndCitesSources(t *testing.T) {
+	global.Log = zap.NewNop()
+
+	courseID, noteID, page := 3, 11, 4
+	conversations := &memoryAssistantRepository{conversation: &models.AssistantConversation{ID: 5, UserID: "u1", CourseID: &courseID}}
+	store := &memoryVectorStore{matches: []models.VectorMatch{
+		{Embedding: models.Embedding{NoteID: &noteID, Content: "Quicksort partitions around a pivot."}, Score: 0.8, Title: "Sorting"},
+		{Embedding: models.Embedding{NoteID: &noteID, ChunkIndex: 1, PageStart: &page, Content: "Merge sort splits the input in half."}, Score: 0.5, Title: "Sorting"},
+	}}
+	fake := ai.NewFakeProvider()
+	fake.Replies = []string{"Quicksort picks a pivot [1] unlike merge sort [2] [1] [9]."}
+
+	service := services.NewAssistantService(conversations, store, nil, nil, &unlimitedQuotaService{}, fake)
+	var streamed strings.Builder
+	reply, code := service.SendMessage(context.Background(), "u1", 5, &models.SendMessageRequest{Content: "  How does quicksort work?  "}, func(delta string) error {
+		streamed.WriteString(delta)
+		return nil
+	})
+	if code != response.CodeSuccess {
+		t.Fatalf("code = %d", code)
+	}
+	if streamed.String() != reply.Answer.Content {
+		t.Fatalf("streamed = %q, answer = %q", streamed.String(), reply.Answer.Content)
+	}
+	if store.query.CourseID == nil || *store.query.CourseID != courseID || store.query.UserID != "u1" || len(store.query.Vector) == 0 {
+		t.Fatalf("query = %+v", store.query)
+	}
+	if len(reply.Citations) != 2 || reply.Citations[0].Number != 1 || reply.Citations[1].PageStart == nil {
+		t.Fatalf("citations = %+v", reply.Citations)
+	}
+
+	if len(conversations.messages) != 2 || conversations.messages[0].Role != consts.AssistantRole.USER || conversations.messages[0].Content != "How does quicksort work?" {
+		t.Fatalf("messages = %+v", conversations.messages)
+	}
+	var stored []models.Citation
+	if err := json.Unmarshal(conversations.messages[1].Citations, &stored); err != nil || len(stored) != 2 {
+		t.Fatalf("stored citations = %s", conversations.messages[1].Citations)
+	}
+	if conversations.conversation.Title != "How does quicksort work?" {
+		t.Fatalf("title = %q", conversations.conversation.Title)
+	}
+	if system := fake.Requests()[0].System; !strings.Contains(system, "[2] Sorting (page 4)") {
+		t.Fatalf("system prompt = %q", system)
+	}
+}
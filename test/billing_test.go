@@ -0,0 +1,290 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/queue"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/billing"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// memoryBillingRepository enforces the unique event ID like the table does
+type memoryBillingRepository struct {
+	events        []models.BillingEvent
+	subscriptions []*models.Subscription
+}
+
+func (r *memoryBillingRepository) CreateEvent(ctx context.Context, event *models.BillingEvent) error {
+	for _, existing := range r.events {
+		if existing.Provider == event.Provider && existing.EventID == event.EventID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *memoryBillingRepository) GetSubscriptionByUserID(ctx context.Context, userID string) (*models.Subscription, error) {
+	for _, subscription := range r.subscriptions {
+		if subscription.UserID == userID {
+			return subscription, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryBillingRepository) GetSubscriptionByProviderID(ctx context.Context, provider, providerSubscriptionID string) (*models.Subscription, error) {
+	for _, subscription := range r.subscriptions {
+		if subscription.Provider == provider && subscription.ProviderSubscriptionID != nil && *subscription.ProviderSubscriptionID == providerSubscriptionID {
+			return subscription, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryBillingRepository) SaveSubscription(ctx context.Context, subscription *models.Subscription) error {
+	if subscription.ID == 0 {
+		subscription.ID = len(r.subscriptions) + 1
+		r.subscriptions = append(r.subscriptions, subscription)
+	}
+	return nil
+}
+
+func (r *memoryBillingRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return fn(nil)
+}
+
+func (r *memoryBillingRepository) WithTx(tx *gorm.DB) repositories.IBillingRepository {
+	return r
+}
+
+// tierUserRepository keeps one user whose tier the billing service updates
+type tierUserRepository struct {
+	repositories.IUserRepository
+	user *models.User
+}
+
+func (r *tierUserRepository) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
+	if r.user == nil || r.user.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.user, nil
+}
+
+func (r *tierUserRepository) UpdateUser(ctx context.Context, userID string, updates map[string]any) error {
+	if tier, ok := updates["tier"].(string); ok && r.user != nil && r.user.UserID == userID {
+		r.user.Tier = tier
+	}
+	return nil
+}
+
+func (r *tierUserRepository) WithTx(tx *gorm.DB) repositories.IUserRepository {
+	return r
+}
+
+type recordingJobQueue struct {
+	types    []string
+	payloads []any
+}
+
+func (q *recordingJobQueue) Enqueue(ctx context.Context, jobType string, payload any, opts ...queue.EnqueueOption) (*queue.Job, error) {
+	q.types = append(q.types, jobType)
+	q.payloads = append(q.payloads, payload)
+	return &queue.Job{Type: jobType}, nil
+}
+
+func billingPlans() *memoryPlanRepository {
+	return &memoryPlanRepository{plans: map[string]models.Plan{
+		"free":    {Code: "free"},
+		"student": {Code: "student", PriceCents: 699},
+	}}
+}
+
+func TestBillingWebhookFixturesDriveSubscriptionState(t *testing.T) {
+	global.Log = zap.NewNop()
+	gin.SetMode(gin.TestMode)
+	// Long enough that the past-due fixture is still in its grace period today
+	previous := global.Config.Billing
+	global.Config.Billing.GracePeriod = 100 * 365
+	t.Cleanup(func() { global.Config.Billing = previous })
+
+	provider := billing.NewFakeProvider("whsec_test")
+	billingRepo := &memoryBillingRepository{}
+	users := &tierUserRepository{user: &models.User{UserID: "user-1", Tier: consts.UserTier.FREE}}
+	jobs := &recordingJobQueue{}
+	billingService := services.NewBillingService(billingRepo, billingPlans(), users, provider, jobs)
+
+	router := gin.New()
+	router.POST("/billing/webhook", controllers.NewBillingController(billingService).HandleWebhook)
+	replay := func(fixture string, signature string) int {
+		t.Helper()
+		payload, err := os.ReadFile(filepath.Join("testdata", "billing", fixture))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signature == "" {
+			signature = provider.Sign(payload)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/billing/webhook", bytes.NewReader(payload))
+		req.Header.Set(billing.FakeSignatureHeader, signature)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+	subscription := func() *models.Subscription {
+		t.Helper()
+		if len(billingRepo.subscriptions) != 1 {
+			t.Fatalf("got %d subscriptions, want 1", len(billingRepo.subscriptions))
+		}
+		return billingRepo.subscriptions[0]
+	}
+
+	if status := replay("01_subscription_created.json", "deadbeef"); status != http.StatusBadRequest {
+		t.Fatalf("forged signature: status %d, want 400", status)
+	}
+	if status := replay("01_subscription_created.json", ""); status != http.StatusOK {
+		t.Fatalf("created: status %d", status)
+	}
+	if sub := subscription(); sub.Status != consts.SubscriptionStatus.TRIALING || sub.TrialEndsAt == nil || users.user.Tier != "student" {
+		t.Fatalf("after created: status %q, tier %q", sub.Status, users.user.Tier)
+	}
+
+	// Redelivery is acknowledged without being applied twice
+	for range 2 {
+		if status := replay("02_subscription_active.json", ""); status != http.StatusOK {
+			t.Fatalf("active: status %d", status)
+		}
+	}
+	if len(billingRepo.events) != 2 || subscription().Status != consts.SubscriptionStatus.ACTIVE {
+		t.Fatalf("after active: %d events, status %q", len(billingRepo.events), subscription().Status)
+	}
+
+	// A failed payment keeps the plan for the grace period and schedules the downgrade
+	replay("03_subscription_past_due.json", "")
+	sub := subscription()
+	if sub.Status != consts.SubscriptionStatus.PAST_DUE || sub.GraceEndsAt == nil || users.user.Tier != "student" {
+		t.Fatalf("after past due: status %q, grace %v, tier %q", sub.Status, sub.GraceEndsAt, users.user.Tier)
+	}
+	if len(jobs.types) != 1 || jobs.types[0] != consts.JobType.BILLING_DOWNGRADE {
+		t.Fatalf("jobs = %v, want one downgrade", jobs.types)
+	}
+	payload := jobs.payloads[0].(models.BillingDowngradePayload)
+	if err := billingService.DowngradeExpired(context.Background(), payload); err != nil || users.user.Tier != "student" {
+		t.Fatalf("downgrade during grace: err %v, tier %q", err, users.user.Tier)
+	}
+
+	// An event delivered late must not undo a newer one
+	replay("04_subscription_active_late.json", "")
+	if sub := subscription(); sub.Status != consts.SubscriptionStatus.PAST_DUE || sub.CancelAtPeriodEnd {
+		t.Fatalf("late event applied: status %q", sub.Status)
+	}
+
+	// Canceled while past due: the unpaid period ended long ago, so the plan is gone
+	replay("05_subscription_canceled.json", "")
+	sub = subscription()
+	wantGrace := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	if sub.Status != consts.SubscriptionStatus.CANCELED || sub.GraceEndsAt == nil || !sub.GraceEndsAt.Equal(wantGrace) {
+		t.Fatalf("after canceled: status %q, grace %v", sub.Status, sub.GraceEndsAt)
+	}
+	if users.user.Tier != consts.UserTier.FREE {
+		t.Fatalf("tier = %q, want free", users.user.Tier)
+	}
+	if len(billingRepo.events) != 5 {
+		t.Fatalf("got %d events, want 5", len(billingRepo.events))
+	}
+}
+
+func TestBillingCheckout(t *testing.T) {
+	global.Log = zap.NewNop()
+	previous := global.Config.Billing
+	global.Config.Billing.TrialDays = 14
+	global.Config.Billing.SuccessURL = "https://app.example.com/billing/success"
+	t.Cleanup(func() { global.Config.Billing = previous })
+
+	provider := billing.NewFakeProvider("whsec_test")
+	plans := billingPlans()
+	users := &tierUserRepository{user: &models.User{UserID: "user-1", Email: "ada@example.com", Tier: consts.UserTier.FREE}}
+	billingService := services.NewBillingService(&memoryBillingRepository{}, plans, users, provider, &recordingJobQueue{})
+	ctx := context.Background()
+
+	if _, code := billingService.CreateCheckout(ctx, "user-1", &models.CreateCheckoutRequest{PlanCode: "free"}); code != response.CodePlanNotPurchasable {
+		t.Fatalf("free plan: code %d", code)
+	}
+
+	session, code := billingService.CreateCheckout(ctx, "user-1", &models.CreateCheckoutRequest{PlanCode: "student"})
+	if code != response.CodeSuccess || session.URL == "" {
+		t.Fatalf("student plan: code %d, session %+v", code, session)
+	}
+	sessions := provider.Sessions()
+	if len(sessions) != 1 || sessions[0].TrialDays != 14 || sessions[0].Email != "ada@example.com" || sessions[0].SuccessURL != global.Config.Billing.SuccessURL {
+		t.Fatalf("checkout request = %+v", sessions)
+	}
+
+	// Returning subscribers reuse their customer and get no second trial
+	plans.subscription = &models.Subscription{UserID: "user-1", Status: consts.SubscriptionStatus.CANCELED, ProviderCustomerID: "cus_001"}
+	billingService.CreateCheckout(ctx, "user-1", &models.CreateCheckoutRequest{PlanCode: "student"})
+	if last := provider.Sessions()[1]; last.TrialDays != 0 || last.CustomerID != "cus_001" {
+		t.Fatalf("returning checkout = %+v", last)
+	}
+
+	plans.subscription.Status = consts.SubscriptionStatus.ACTIVE
+	if _, code := billingService.CreateCheckout(ctx, "user-1", &models.CreateCheckoutRequest{PlanCode: "student"}); code != response.CodeSubscriptionExists {
+		t.Fatalf("active subscription: code %d", code)
+	}
+}
+
+func TestStripeWebhookSignature(t *testing.T) {
+	provider, err := billing.NewStripeProvider(billing.StripeConfig{
+		SecretKey:     "sk_test",
+		WebhookSecret: "whsec_test",
+		Prices:        map[string]string{"premium": "price_premium"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := []byte(`{"id":"evt_1","type":"customer.subscription.updated","created":1735689600,"data":{"object":{
+		"id":"sub_1","customer":"cus_1","status":"unpaid","metadata":{"user_id":"user-1","plan_code":"student"},
+		"items":{"data":[{"price":{"id":"price_premium"},"current_period_start":1735689600,"current_period_end":1738368000}]}}}}`)
+	sign := func(timestamp int64) http.Header {
+		mac := hmac.New(sha256.New, []byte("whsec_test"))
+		fmt.Fprintf(mac, "%d.%s", timestamp, payload)
+		header := http.Header{}
+		header.Set("Stripe-Signature", fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil))))
+		return header
+	}
+
+	event, err := provider.ParseEvent(payload, sign(time.Now().Unix()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub := event.Subscription
+	// The price wins over stale metadata, and unpaid counts as past due
+	if sub == nil || sub.PlanCode != "premium" || sub.Status != billing.StatusPastDue || sub.UserID != "user-1" || sub.CurrentPeriodEnd.Unix() != 1738368000 {
+		t.Fatalf("subscription = %+v", sub)
+	}
+
+	if _, err := provider.ParseEvent(payload, sign(time.Now().Add(-time.Hour).Unix())); err != billing.ErrInvalidSignature {
+		t.Fatalf("replayed signature: err %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type memoryChatRepository struct {
+	mu       sync.Mutex
+	messages []models.StudyGroupMessage
+}
+
+func (r *memoryChatRepository) CreateMessage(ctx context.Context, message *models.StudyGroupMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	message.ID = len(r.messages) + 1
+	message.CreatedAt = time.Now()
+	r.messages = append(r.messages, *message)
+	return nil
+}
+
+func (r *memoryChatRepository) ListMessages(ctx context.Context, groupID, before, limit int) ([]models.ChatMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var messages []models.ChatMessage
+	for i := len(r.messages) - 1; i >= 0 && len(messages) < limit; i-- {
+		m := r.messages[i]
+		if m.GroupID == groupID && (before == 0 || m.ID < before) {
+			messages = append(messages, models.ChatMessage{ID: m.ID, GroupID: m.GroupID, UserID: m.UserID, Username: m.UserID, Body: m.Body})
+		}
+	}
+	return messages, nil
+}
+
+// chatServer stands in for one API instance: its own chat service, sharing
+// the database and Redis with the others
+func chatServer(t *testing.T, groups repositories.IStudyGroupRepository, chats repositories.IChatRepository, broker repositories.IChatBroker) (services.IChatService, string) {
+	service := services.NewChatService(groups, chats, &emailUserRepository{}, broker)
+	controller := controllers.NewChatController(service)
+
+	engine := gin.New()
+	engine.GET("/study-groups/:id/chat", func(ctx *gin.Context) {
+		ctx.Set(consts.UserIDContextKey, ctx.Query("user"))
+	}, controller.Connect)
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+	return service, "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func dialChat(t *testing.T, url, userID string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(url+"/study-groups/1/chat?user="+userID, nil)
+	if err != nil {
+		t.Fatalf("dial as %s: %v", userID, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// nextEvent reads events until one matches
+func nextEvent(t *testing.T, conn *websocket.Conn, match func(models.ChatEvent) bool) models.ChatEvent {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	for {
+		var event models.ChatEvent
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		if match(event) {
+			return event
+		}
+	}
+}
+
+func TestGroupChatAcrossInstances(t *testing.T) {
+	global.Log = zap.NewNop()
+	gin.SetMode(gin.TestMode)
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	broker := repositories.NewRedisChatBroker(rdb)
+
+	groups := newMemoryStudyGroupRepository()
+	_ = groups.CreateGroup(context.Background(), &models.StudyGroup{Members: []models.StudyGroupMember{
+		{UserID: "alice", Role: consts.StudyGroupRole.OWNER},
+		{UserID: "bob", Role: consts.StudyGroupRole.MEMBER},
+	}})
+	chats := &memoryChatRepository{}
+	serviceA, urlA := chatServer(t, groups, chats, broker)
+	_, urlB := chatServer(t, groups, chats, broker)
+
+	if _, _, err := websocket.DefaultDialer.Dial(urlA+"/study-groups/1/chat?user=mallory", nil); err == nil {
+		t.Fatal("non-member opened the chat")
+	}
+
+	bob := dialChat(t, urlA, "bob")
+	nextEvent(t, bob, func(e models.ChatEvent) bool { return e.Type == consts.ChatEventType.PRESENCE })
+	alice := dialChat(t, urlB, "alice")
+	nextEvent(t, bob, func(e models.ChatEvent) bool {
+		return e.Type == consts.ChatEventType.PRESENCE && strings.Join(e.Online, ",") == "alice,bob"
+	})
+
+	// A message sent on one instance reaches the members on the other
+	_ = alice.WriteJSON(models.ChatClientFrame{Type: consts.ChatEventType.MESSAGE, Body: "  hello  ", ClientID: "c1"})
+	got := nextEvent(t, bob, func(e models.ChatEvent) bool { return e.Type == consts.ChatEventType.MESSAGE })
+	if got.Message.Body != "hello" || got.Message.UserID != "alice" || got.ClientID != "c1" {
+		t.Fatalf("message event = %+v %+v", got, got.Message)
+	}
+	nextEvent(t, alice, func(e models.ChatEvent) bool { return e.Type == consts.ChatEventType.MESSAGE && e.ClientID == "c1" })
+
+	_ = alice.WriteJSON(models.ChatClientFrame{Type: consts.ChatEventType.TYPING, Typing: true})
+	typing := nextEvent(t, bob, func(e models.ChatEvent) bool { return e.Type == consts.ChatEventType.TYPING })
+	if typing.UserID != "alice" || typing.Typing == nil || !*typing.Typing {
+		t.Fatalf("typing event = %+v", typing)
+	}
+
+	_ = bob.WriteMessage(websocket.TextMessage, []byte("not json"))
+	if e := nextEvent(t, bob, func(e models.ChatEvent) bool { return e.Type == consts.ChatEventType.ERROR }); e.Code != response.CodeChatFrameInvalid {
+		t.Fatalf("error code = %d", e.Code)
+	}
+	_ = bob.WriteJSON(models.ChatClientFrame{Type: consts.ChatEventType.MESSAGE, Body: "   ", ClientID: "c2"})
+	if e := nextEvent(t, bob, func(e models.ChatEvent) bool { return e.Type == consts.ChatEventType.ERROR }); e.Code != response.CodeChatMessageInvalid || e.ClientID != "c2" {
+		t.Fatalf("error event = %+v", e)
+	}
+
+	page, code := serviceA.ListMessages(context.Background(), "bob", 1, &models.ChatMessageQuery{Limit: 1})
+	if code != response.CodeSuccess || len(page.Messages) != 1 || page.NextBefore == nil || *page.NextBefore != 1 {
+		t.Fatalf("page = %+v, code %d", page, code)
+	}
+
+	// Removing bob on any instance closes his connection
+	studyGroups := services.NewStudyGroupService(groups, &courseCodeRepository{}, &groupNoteRepository{groups: groups}, &emailUserRepository{}, &missingMailRepository{}, &recordingMailHelper{}, broker)
+	if code := studyGroups.RemoveMember(context.Background(), "alice", 1, "bob"); code != response.CodeSuccess {
+		t.Fatalf("remove member code = %d", code)
+	}
+	nextEvent(t, bob, func(e models.ChatEvent) bool {
+		return e.Type == consts.ChatEventType.MEMBER_REMOVED && e.UserID == "bob"
+	})
+	_ = bob.SetReadDeadline(time.Now().Add(3 * time.Second))
+	for {
+		_, _, err := bob.ReadMessage()
+		var closeErr *websocket.CloseError
+		if errors.As(err, &closeErr) {
+			if closeErr.Code != consts.CHAT_CLOSE_REMOVED {
+				t.Fatalf("close code = %d", closeErr.Code)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+	}
+
+	// Alice stays connected and sees bob go offline
+	nextEvent(t, alice, func(e models.ChatEvent) bool {
+		return e.Type == consts.ChatEventType.PRESENCE && strings.Join(e.Online, ",") == "alice"
+	})
+}
+
+// droppingChatBroker hands out subscriptions the test can end, as when the
+// Redis connection drops
+type droppingChatBroker struct {
+	repositories.IChatBroker
+
+	mu   sync.Mutex
+	subs []chan models.ChatEvent
+}
+
+func (b *droppingChatBroker) Subscribe(ctx context.Context, groupID int) (<-chan models.ChatEvent, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	events := make(chan models.ChatEvent, 4)
+	b.subs = append(b.subs, events)
+	return events, nil
+}
+
+func (b *droppingChatBroker) sub(i int) chan models.ChatEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if i >= len(b.subs) {
+		return nil
+	}
+	return b.subs[i]
+}
+
+func (b *droppingChatBroker) Publish(ctx context.Context, groupID int, event models.ChatEvent) error {
+	return nil
+}
+
+func (b *droppingChatBroker) SetOnline(ctx context.Context, groupID int, userID, connID string, until time.Time) error {
+	return nil
+}
+
+func (b *droppingChatBroker) SetOffline(ctx context.Context, groupID int, userID, connID string) error {
+	return nil
+}
+
+func (b *droppingChatBroker) Online(ctx context.Context, groupID int, now time.Time) ([]string, error) {
+	return nil, nil
+}
+
+func TestGroupChatResubscribesAfterLostRelay(t *testing.T) {
+	global.Log = zap.NewNop()
+	groups := newMemoryStudyGroupRepository()
+	_ = groups.CreateGroup(context.Background(), &models.StudyGroup{Members: []models.StudyGroupMember{
+		{UserID: "alice", Role: consts.StudyGroupRole.OWNER},
+		{UserID: "bob", Role: consts.StudyGroupRole.MEMBER},
+	}})
+	broker := &droppingChatBroker{}
+	service := services.NewChatService(groups, &memoryChatRepository{}, &emailUserRepository{}, broker)
+
+	kicked := make(chan int, 1)
+	bob := &services.ChatClient{GroupID: 1, UserID: "bob", Send: func(models.ChatEvent) error { return nil }, Kick: func(code int, reason string) { kicked <- code }}
+	leaveBob, code := service.Connect(context.Background(), bob)
+	if code != response.CodeSuccess {
+		t.Fatalf("Connect code = %d", code)
+	}
+
+	close(broker.sub(0))
+	select {
+	case code := <-kicked:
+		if code != consts.CHAT_CLOSE_UNAVAILABLE {
+			t.Errorf("kick code = %d, want %d", code, consts.CHAT_CLOSE_UNAVAILABLE)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("bob was not kicked after the relay was lost")
+	}
+
+	// Alice reconnects before bob's connection has cleaned up
+	received := make(chan models.ChatEvent, 1)
+	alice := &services.ChatClient{GroupID: 1, UserID: "alice", Send: func(e models.ChatEvent) error { received <- e; return nil }, Kick: func(int, string) {}}
+	leaveAlice, code := service.Connect(context.Background(), alice)
+	if code != response.CodeSuccess {
+		t.Fatalf("reconnect code = %d", code)
+	}
+	defer leaveAlice()
+	leaveBob()
+
+	events := broker.sub(1)
+	if events == nil {
+		t.Fatal("reconnecting client joined the room without a subscription")
+	}
+	events <- models.ChatEvent{Type: consts.ChatEventType.TYPING, GroupID: 1, UserID: "bob"}
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reconnected client received nothing")
+	}
+}
@@ -0,0 +1,138 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nas03/scholar-ai/backend/pkg/extract"
+)
+
+// buildPDF writes a minimal uncompressed PDF with one Helvetica text line per page
+func buildPDF(pages ...string) []byte {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	for i, text := range pages {
+		content := fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 5+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
+
+func buildDOCX(t *testing.T, body string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	writer, err := archive.Create("word/document.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintf(writer, `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>%s</w:body></w:document>`, body)
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractPDFPages(t *testing.T) {
+	data := buildPDF("Sorting algorithms", "Graph traversal")
+
+	pages, err := extract.Extract("application/pdf", bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if len(pages) != 2 || pages[0].Number != 1 || pages[1].Number != 2 {
+		t.Fatalf("unexpected pages %+v", pages)
+	}
+	if !strings.Contains(pages[0].Text, "Sorting algorithms") || !strings.Contains(pages[1].Text, "Graph traversal") {
+		t.Errorf("unexpected text %+v", pages)
+	}
+
+	if _, err := extract.PDF(bytes.NewReader([]byte("%PDF-1.4 garbage")), 16); err == nil {
+		t.Error("expected an error for a malformed PDF")
+	}
+}
+
+func TestExtractDOCXPageBreaks(t *testing.T) {
+	data := buildDOCX(t, `<w:p><w:r><w:t>Lecture 1</w:t></w:r></w:p>`+
+		`<w:p><w:r><w:t xml:space="preserve">Big-O </w:t></w:r><w:r><w:t>notation</w:t></w:r></w:p>`+
+		`<w:p><w:r><w:lastRenderedPageBreak/><w:t>Lecture 2</w:t></w:r></w:p>`+
+		`<w:p><w:r><w:br w:type="page"/><w:t>Appendix</w:t></w:r></w:p>`)
+
+	pages, err := extract.Extract("application/vnd.openxmlformats-officedocument.wordprocessingml.document", bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+
+	want := []string{"Lecture 1\nBig-O notation", "Lecture 2", "Appendix"}
+	if len(pages) != len(want) {
+		t.Fatalf("got %d pages: %+v", len(pages), pages)
+	}
+	for i, text := range want {
+		if pages[i].Number != i+1 || pages[i].Text != text {
+			t.Errorf("page %d = %+v, want %q", i+1, pages[i], text)
+		}
+	}
+
+	if _, err := extract.Extract("image/png", bytes.NewReader(nil), 0); err != extract.ErrUnsupported {
+		t.Errorf("images: got %v, want ErrUnsupported", err)
+	}
+}
+
+func TestSplitChunksArePageAware(t *testing.T) {
+	pages := []extract.Page{
+		{Number: 1, Text: "alpha beta gamma\ndelta epsilon"},
+		{Number: 2, Text: "zeta eta theta\niota kappa lambda\nmu nu xi omicron pi rho sigma tau upsilon phi chi psi omega"},
+	}
+
+	chunks := extract.Split(pages, 40, 15)
+	if len(chunks) < 3 {
+		t.Fatalf("expected several chunks, got %+v", chunks)
+	}
+	for i, chunk := range chunks {
+		if chunk.Index != i {
+			t.Errorf("chunk %d has index %d", i, chunk.Index)
+		}
+		if n := len([]rune(chunk.Text)); n > 40 {
+			t.Errorf("chunk %d has %d runes: %q", i, n, chunk.Text)
+		}
+		if chunk.PageStart > chunk.PageEnd {
+			t.Errorf("chunk %d has pages %d-%d", i, chunk.PageStart, chunk.PageEnd)
+		}
+	}
+
+	if first := chunks[0]; first.PageStart != 1 || first.Text != "alpha beta gamma\ndelta epsilon" {
+		t.Errorf("first chunk = %+v", first)
+	}
+	last := chunks[len(chunks)-1]
+	if last.PageStart != 2 || last.PageEnd != 2 || !strings.HasSuffix(last.Text, "psi omega") {
+		t.Errorf("last chunk = %+v", last)
+	}
+	// The second chunk repeats the end of the first and so starts on page 1
+	if second := chunks[1]; !strings.HasPrefix(second.Text, "delta epsilon\nzeta") || second.PageStart != 1 || second.PageEnd != 2 {
+		t.Errorf("second chunk does not carry the overlap: %+v", second)
+	}
+}
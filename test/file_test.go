@@ -0,0 +1,242 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/controllers"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/filetype"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"github.com/nas03/scholar-ai/backend/pkg/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// memoryFileRepository keeps file rows and applies the columns the file service sets
+type memoryFileRepository struct {
+	repositories.IFileRepository
+	files map[int]*models.File
+}
+
+func (r *memoryFileRepository) CreateFile(ctx context.Context, file *models.File) error {
+	file.ID = len(r.files) + 1
+	stored := *file
+	r.files[file.ID] = &stored
+	return nil
+}
+
+func (r *memoryFileRepository) GetFileByID(ctx context.Context, id int, userID string) (*models.File, error) {
+	file, ok := r.files[id]
+	if !ok || file.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *file
+	return &found, nil
+}
+
+func (r *memoryFileRepository) UpdateFile(ctx context.Context, id int, userID string, updates map[string]any) error {
+	file := r.files[id]
+	for column, value := range updates {
+		switch column {
+		case "mime_type":
+			file.MimeType = value.(string)
+		case "size":
+			file.Size = value.(int64)
+		case "checksum":
+			file.Checksum = sql.NullString{String: value.(string), Valid: true}
+		case "status":
+			file.Status = value.(int8)
+		case "upload_id":
+			file.UploadID = value.(sql.NullString)
+		case "upload_expires_at":
+			file.UploadExpiresAt = value.(sql.NullTime)
+		}
+	}
+	return nil
+}
+
+func (r *memoryFileRepository) DeleteFile(ctx context.Context, id int, userID string) error {
+	if file, ok := r.files[id]; !ok || file.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.files, id)
+	return nil
+}
+
+func (r *memoryFileRepository) ListExpiredUploads(ctx context.Context, before time.Time, limit int) ([]models.File, error) {
+	var files []models.File
+	for _, file := range r.files {
+		if file.UploadExpiresAt.Valid && file.UploadExpiresAt.Time.Before(before) {
+			files = append(files, *file)
+		}
+	}
+	slices.SortFunc(files, func(a, b models.File) int { return a.UploadExpiresAt.Time.Compare(b.UploadExpiresAt.Time) })
+	if len(files) > limit {
+		files = files[:limit]
+	}
+	return files, nil
+}
+
+func (r *memoryFileRepository) UsedBytes(ctx context.Context, userID string, pendingSince time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestPresignedUploadGoesThroughStaging(t *testing.T) {
+	global.Log = zap.NewNop()
+	gin.SetMode(gin.TestMode)
+
+	store, err := storage.NewLocalStorage(t.TempDir(), "http://api.test"+consts.STORAGE_BLOB_PATH, "secret")
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	files := &memoryFileRepository{files: map[int]*models.File{}}
+	users := &memoryUserRepository{user: &models.User{UserID: "user-1", Tier: consts.UserTier.FREE}}
+	fileService := services.NewFileService(files, users, nil, store, &recordingJobQueue{})
+
+	engine := gin.New()
+	engine.PUT(consts.STORAGE_BLOB_PATH+"/*key", controllers.NewStorageController(store, services.MaxFileSize()).PutObject)
+	put := func(target, content string) int {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, target, strings.NewReader(content))
+		req.Header.Set("Content-Type", filetype.PDF)
+		engine.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	ctx := context.Background()
+	upload, code := fileService.CreateUpload(ctx, "user-1", &models.CreateFileUploadRequest{Filename: "notes.pdf", Size: 13})
+	if code != response.CodeSuccess {
+		t.Fatalf("CreateUpload: code %d", code)
+	}
+	key := files.files[upload.File.ID].StorageKey
+	staged := consts.STORAGE_STAGING_PREFIX + upload.UploadID
+	signed, _ := url.Parse(upload.UploadURL)
+	if signed.Path != consts.STORAGE_BLOB_PATH+"/"+staged {
+		t.Fatalf("upload URL %s does not target the staging key of upload %s", upload.UploadURL, upload.UploadID)
+	}
+	if status := put(upload.UploadURL, "%PDF-1.7 test"); status != http.StatusOK {
+		t.Fatalf("PUT to the presigned URL: status %d", status)
+	}
+	if _, err := store.Stat(ctx, key); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("upload reached the file's key before completion: %v", err)
+	}
+
+	if _, code := fileService.CompleteUpload(ctx, "user-1", upload.File.ID, strings.Repeat("0", 32)); code != response.CodeFileUploadNotFound {
+		t.Fatalf("CompleteUpload with an unknown upload ID: code %d, want %d", code, response.CodeFileUploadNotFound)
+	}
+	file, code := fileService.CompleteUpload(ctx, "user-1", upload.File.ID, upload.UploadID)
+	if code != response.CodeSuccess || file.Status != consts.FileStatus.READY {
+		t.Fatalf("CompleteUpload = %+v, code %d", file, code)
+	}
+	if info, err := store.Stat(ctx, key); err != nil || info.Size != 13 {
+		t.Fatalf("stored file = %+v, %v", info, err)
+	}
+	if _, err := store.Stat(ctx, staged); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("staging object left behind: %v", err)
+	}
+
+	// The presigned URL is still valid but the upload ID is spent
+	put(upload.UploadURL, "%PDF-1.7 evil")
+	if _, code := fileService.CompleteUpload(ctx, "user-1", upload.File.ID, upload.UploadID); code != response.CodeFileUploadNotFound {
+		t.Fatalf("CompleteUpload again: code %d, want %d", code, response.CodeFileUploadNotFound)
+	}
+	object, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	content, _ := io.ReadAll(object)
+	object.Close()
+	if string(content) != "%PDF-1.7 test" {
+		t.Errorf("stored file changed to %q", content)
+	}
+
+	// A URL signed for the file's own key is refused as well
+	direct, _ := store.PresignPut(ctx, key, filetype.PDF, time.Minute)
+	if status := put(direct, "%PDF-1.7 evil"); status != http.StatusForbidden {
+		t.Errorf("PUT to the file's key: status %d, want %d", status, http.StatusForbidden)
+	}
+}
+
+func TestSweepRemovesExpiredUploads(t *testing.T) {
+	global.Log = zap.NewNop()
+	gin.SetMode(gin.TestMode)
+
+	store, err := storage.NewLocalStorage(t.TempDir(), "http://api.test"+consts.STORAGE_BLOB_PATH, "secret")
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	files := &memoryFileRepository{files: map[int]*models.File{}}
+	users := &memoryUserRepository{user: &models.User{UserID: "user-1", Tier: consts.UserTier.FREE}}
+	fileService := services.NewFileService(files, users, nil, store, &recordingJobQueue{})
+
+	engine := gin.New()
+	engine.PUT(consts.STORAGE_BLOB_PATH+"/*key", controllers.NewStorageController(store, services.MaxFileSize()).PutObject)
+	put := func(target, content string) {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, target, strings.NewReader(content))
+		req.Header.Set("Content-Type", filetype.PDF)
+		engine.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("PUT %s: status %d", target, recorder.Code)
+		}
+	}
+	exists := func(key string) bool {
+		_, err := store.Stat(context.Background(), key)
+		return err == nil
+	}
+
+	ctx := context.Background()
+	abandoned, _ := fileService.CreateUpload(ctx, "user-1", &models.CreateFileUploadRequest{Filename: "draft.pdf", Size: 13})
+	put(abandoned.UploadURL, "%PDF-1.7 test")
+	completed, _ := fileService.CreateUpload(ctx, "user-1", &models.CreateFileUploadRequest{Filename: "notes.pdf", Size: 13})
+	put(completed.UploadURL, "%PDF-1.7 test")
+	if _, code := fileService.CompleteUpload(ctx, "user-1", completed.File.ID, completed.UploadID); code != response.CodeSuccess {
+		t.Fatalf("CompleteUpload: code %d", code)
+	}
+	// The URL is used again after completion
+	put(completed.UploadURL, "%PDF-1.7 again")
+
+	// Nothing is touched while the URLs may still be in use
+	if err := fileService.SweepUploads(ctx, completed.ExpiresAt.Add(consts.STORAGE_UPLOAD_GRACE/2)); err != nil {
+		t.Fatalf("SweepUploads: %v", err)
+	}
+	if len(files.files) != 2 || !exists(consts.STORAGE_STAGING_PREFIX+abandoned.UploadID) {
+		t.Fatalf("sweep within the grace period removed an upload")
+	}
+
+	if err := fileService.SweepUploads(ctx, completed.ExpiresAt.Add(consts.STORAGE_UPLOAD_GRACE+time.Minute)); err != nil {
+		t.Fatalf("SweepUploads: %v", err)
+	}
+	if _, ok := files.files[abandoned.File.ID]; ok {
+		t.Error("abandoned upload still has a row")
+	}
+	if exists(consts.STORAGE_STAGING_PREFIX + abandoned.UploadID) {
+		t.Error("abandoned upload left its staging object")
+	}
+
+	file, ok := files.files[completed.File.ID]
+	if !ok || file.Status != consts.FileStatus.READY || !exists(file.StorageKey) {
+		t.Fatalf("completed file was swept: %+v", file)
+	}
+	if exists(consts.STORAGE_STAGING_PREFIX + completed.UploadID) {
+		t.Error("object uploaded after completion left behind")
+	}
+	if file.UploadID.Valid || file.UploadExpiresAt.Valid {
+		t.Errorf("upload of the completed file still recorded: %+v", file)
+	}
+}
@@ -0,0 +1,144 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/ai"
+	"github.com/nas03/scholar-ai/backend/pkg/srs"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestSM2Intervals(t *testing.T) {
+	state := srs.New()
+	var intervals []int
+	for range 4 {
+		var err error
+		if state, err = srs.Review(state, 4); err != nil {
+			t.Fatal(err)
+		}
+		intervals = append(intervals, state.Interval)
+	}
+	// Grade 4 leaves the ease factor at 2.5
+	if fmt.Sprint(intervals) != "[1 6 15 38]" || state.EaseFactor != 2.5 || state.Repetitions != 4 {
+		t.Fatalf("intervals = %v, state = %+v", intervals, state)
+	}
+
+	lapsed, _ := srs.Review(state, 2)
+	if lapsed.Interval != 1 || lapsed.Repetitions != 0 || lapsed.EaseFactor != 2.18 {
+		t.Fatalf("lapse = %+v", lapsed)
+	}
+
+	for range 10 {
+		lapsed, _ = srs.Review(lapsed, 0)
+	}
+	if lapsed.EaseFactor != srs.MinEaseFactor {
+		t.Fatalf("ease factor = %v, want floor %v", lapsed.EaseFactor, srs.MinEaseFactor)
+	}
+
+	if _, err := srs.Review(state, 6); err != srs.ErrInvalidGrade {
+		t.Fatalf("grade 6: err = %v", err)
+	}
+}
+
+// memoryFlashcardRepository keeps one generation and the cards created for it
+type memoryFlashcardRepository struct {
+	repositories.IFlashcardRepository
+	generation *models.FlashcardGeneration
+	updates    map[string]any
+	cards      []models.Flashcard
+}
+
+func (r *memoryFlashcardRepository) GetGenerationByID(ctx context.Context, id int, userID string) (*models.FlashcardGeneration, error) {
+	generation := *r.generation
+	return &generation, nil
+}
+
+func (r *memoryFlashcardRepository) UpdateGeneration(ctx context.Context, id int, updates map[string]any) error {
+	for column, value := range updates {
+		r.updates[column] = value
+	}
+	return nil
+}
+
+func (r *memoryFlashcardRepository) CreateFlashcards(ctx context.Context, cards []models.Flashcard) error {
+	r.cards = append(r.cards, cards...)
+	return nil
+}
+
+func (r *memoryFlashcardRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return fn(nil)
+}
+
+func (r *memoryFlashcardRepository) WithTx(tx *gorm.DB) repositories.IFlashcardRepository {
+	return r
+}
+
+type memoryUserRepository struct {
+	repositories.IUserRepository
+	user *models.User
+}
+
+func (r *memoryUserRepository) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
+	return r.user, nil
+}
+
+func TestGenerateFlashcardsFromNote(t *testing.T) {
+	global.Log = zap.NewNop()
+
+	paragraph := strings.Repeat("A heap keeps its smallest element at the root. ", 40)
+	text := strings.Repeat(paragraph+"\n\n", 10)
+
+	noteID := 7
+	flashcards := &memoryFlashcardRepository{
+		generation: &models.FlashcardGeneration{ID: 3, UserID: "u1", NoteID: &noteID, Language: "en", Count: 5},
+		updates:    map[string]any{},
+	}
+	notes := &memoryNoteRepository{note: &models.Note{ID: 7, UserID: "u1", CourseID: 2, Title: "Heaps", ContentText: text}}
+	users := &memoryUserRepository{user: &models.User{UserID: "u1", Timezone: "Asia/Ho_Chi_Minh"}}
+
+	fake := ai.NewFakeProvider()
+	fake.Replies = []string{`{"cards": [{"front": "", "back": "missing front"}]}`} // rejected once, then repaired
+	fake.Reply = func(req ai.ChatRequest) (string, error) {
+		// Cite a chunk that is not in the batch, and return one card too many
+		return `{"cards": [
+			{"front": "What is at the root of a min-heap?", "back": "The smallest element.", "chunk": 999},
+			{"front": "Heap insert cost?", "back": "O(log n)", "chunk": 0},
+			{"front": "Extra", "back": "Extra", "chunk": 0},
+			{"front": "Extra", "back": "Extra", "chunk": 0},
+			{"front": "Extra", "back": "Extra", "chunk": 0},
+			{"front": "Extra", "back": "Extra", "chunk": 0}
+		]}`, nil
+	}
+
+	service := services.NewFlashcardGenerationService(flashcards, notes, nil, users, fake, nil, nil)
+	if err := service.GenerateFlashcards(context.Background(), models.FlashcardGeneratePayload{GenerationID: 3, UserID: "u1"}); err != nil {
+		t.Fatalf("GenerateFlashcards: %v", err)
+	}
+
+	if flashcards.updates["status"] != consts.FlashcardGenerationStatus.DONE || flashcards.updates["created_count"] != 5 {
+		t.Fatalf("updates = %v", flashcards.updates)
+	}
+	if len(flashcards.cards) != 5 {
+		t.Fatalf("created %d cards, want exactly the 5 requested", len(flashcards.cards))
+	}
+	for _, card := range flashcards.cards {
+		if card.CourseID == nil || *card.CourseID != 2 || card.NoteID == nil || card.ChunkIndex == nil || card.EaseFactor != srs.DefaultEaseFactor {
+			t.Fatalf("card = %+v", card)
+		}
+	}
+	if first := flashcards.cards[0]; *first.ChunkIndex != 0 || first.PageStart != nil {
+		t.Fatalf("unknown chunk should fall back to the batch's first: %+v", first)
+	}
+	if retried := fake.Requests()[1]; len(retried.Messages) != 3 {
+		t.Fatalf("second request should repair the first: %+v", retried.Messages)
+	}
+}
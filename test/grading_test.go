@@ -0,0 +1,43 @@
+package test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nas03/scholar-ai/backend/pkg/grading"
+)
+
+func TestGradingPointsFollowScale(t *testing.T) {
+	cases := map[float64]float64{100: 4.0, 90: 4.0, 89.9: 3.7, 82: 3.5, 70: 3.0, 54.9: 1.5, 40: 1.0, 39.9: 0}
+	for percent, want := range cases {
+		if got := grading.Points(percent); got != want {
+			t.Errorf("Points(%v) = %v, want %v", percent, got, want)
+		}
+	}
+}
+
+func TestGradingProjectBoundsForecast(t *testing.T) {
+	forecast := grading.Project(grading.Results{Weight: 40, Mean: 80, StdDev: 5, Count: 2})
+
+	if math.Abs(forecast.Predicted-80) > 1e-9 {
+		t.Errorf("Predicted = %v, want 80", forecast.Predicted)
+	}
+	if math.Abs(forecast.Min-32) > 1e-9 || math.Abs(forecast.Max-92) > 1e-9 {
+		t.Errorf("Min/Max = %v/%v, want 32/92", forecast.Min, forecast.Max)
+	}
+	if !(forecast.Min < forecast.Low && forecast.Low < forecast.Predicted && forecast.Predicted < forecast.High && forecast.High < forecast.Max) {
+		t.Errorf("range out of order: %+v", forecast)
+	}
+
+	// More graded results narrow the range
+	sure := grading.Project(grading.Results{Weight: 40, Mean: 80, StdDev: 5, Count: 8})
+	if sure.High-sure.Low >= forecast.High-forecast.Low {
+		t.Errorf("range with 8 results %v not narrower than with 2 %v", sure.High-sure.Low, forecast.High-forecast.Low)
+	}
+
+	// A fully graded course has nothing left to forecast
+	done := grading.Project(grading.Results{Weight: 100, Mean: 72, StdDev: 12, Count: 4})
+	if done.Low != 72 || done.High != 72 || done.Min != 72 || done.Max != 72 {
+		t.Errorf("fully graded forecast = %+v, want 72 throughout", done)
+	}
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/pkg/ical"
+)
+
+const universityTimetable = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//University//Timetable//EN\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:Europe/Berlin\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:lecture-42@uni.example\r\n" +
+	"SUMMARY:CS 101 - Algorithms\\, Lecture\r\n" +
+	"LOCATION:Hall A\r\n" +
+	"DESCRIPTION:Weekly lecture with a long description that is folded over\r\n" +
+	"  two lines\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20261005T101500\r\n" +
+	"DURATION:PT1H30M\r\n" +
+	"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;UNTIL=20270131T225959Z\r\n" +
+	"EXDATE;TZID=Europe/Berlin:20261102T101500,20261116T101500\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"DESCRIPTION:ignored\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseUniversityTimetable(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("zoneinfo unavailable: %v", err)
+	}
+
+	calendar, err := ical.Parse(strings.NewReader(universityTimetable), time.UTC)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(calendar.Events) != 1 {
+		t.Fatalf("got %d events, want 1", len(calendar.Events))
+	}
+
+	event := calendar.Events[0]
+	if event.Summary != "CS 101 - Algorithms, Lecture" {
+		t.Errorf("summary = %q", event.Summary)
+	}
+	if event.Description != "Weekly lecture with a long description that is folded over two lines" {
+		t.Errorf("description not unfolded: %q", event.Description)
+	}
+	if want := time.Date(2026, 10, 5, 10, 15, 0, 0, berlin); !event.Start.Equal(want) {
+		t.Errorf("start = %s, want %s", event.Start, want)
+	}
+	if event.End.Sub(event.Start) != 90*time.Minute {
+		t.Errorf("duration = %s, want 1h30m", event.End.Sub(event.Start))
+	}
+	if len(event.ExDates) != 2 {
+		t.Errorf("got %d EXDATEs, want 2", len(event.ExDates))
+	}
+
+	rule, err := ical.ParseRule(event.RRule, berlin)
+	if err != nil {
+		t.Fatalf("parse rule: %v", err)
+	}
+	if rule.Freq != "WEEKLY" || rule.Interval != 2 || len(rule.ByDay) != 2 || rule.ByDay[1] != time.Thursday {
+		t.Errorf("unexpected rule %+v", rule)
+	}
+	if rule.Until.In(berlin).Format("2006-01-02") != "2027-01-31" {
+		t.Errorf("until = %s", rule.Until.In(berlin))
+	}
+}
+
+func TestParseRejectsNonCalendar(t *testing.T) {
+	if _, err := ical.Parse(strings.NewReader("hello world\n"), time.UTC); err == nil {
+		t.Error("expected an error for a non-iCalendar file")
+	}
+	if _, err := ical.Parse(strings.NewReader("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:x\r\n"), time.UTC); err == nil {
+		t.Error("expected an error for an unterminated calendar")
+	}
+}
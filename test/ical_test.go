@@ -0,0 +1,69 @@
+package test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+)
+
+func TestBuildCalendarTimezones(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("zoneinfo unavailable: %v", err)
+	}
+
+	sessions := []models.ClassSession{{
+		ID:         7,
+		Weekday:    1,
+		StartTime:  "09:00:00",
+		EndTime:    "10:30:00",
+		Recurrence: consts.ClassSessionRecurrence.BIWEEKLY,
+		StartDate:  mustDate(t, "2026-09-07"),
+		EndDate:    sql.NullTime{Time: mustDate(t, "2026-11-30"), Valid: true},
+		Location:   sql.NullString{String: "Room 1.01, Main building", Valid: true},
+		Course:     &models.Course{CourseID: "CS101", CourseName: "Algorithms"},
+		Exceptions: []models.ClassSessionException{{Date: mustDate(t, "2026-10-05")}},
+	}}
+	reminders := []models.Reminder{{
+		ID:      3,
+		Title:   "Final exam",
+		Type:    consts.ReminderType.EXAM,
+		DueDate: mustDate(t, "2026-12-15"),
+		DueTime: "08:00:00",
+	}}
+
+	stamp := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	body := string(services.BuildCalendar(berlin, sessions, reminders, stamp).Encode())
+	unfolded := strings.ReplaceAll(body, "\r\n ", "")
+
+	for _, want := range []string{
+		"UID:class-session-7@scholar-ai",
+		"DTSTART;TZID=Europe/Berlin:20260907T090000",
+		"DTEND;TZID=Europe/Berlin:20260907T103000",
+		// 09:00 CET on the last day is 08:00 UTC, after the October DST change
+		"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO;UNTIL=20261130T080000Z",
+		"EXDATE;TZID=Europe/Berlin:20261005T090000",
+		`LOCATION:Room 1.01\, Main building`,
+		"SUMMARY:CS101 Algorithms",
+		"TZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100",
+		"UID:reminder-3@scholar-ai",
+		// the exam is due at 08:00 Berlin time, 07:00 UTC in winter
+		"DTSTART:20261215T070000Z",
+		"SUMMARY:Exam: Final exam",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("calendar is missing %q", want)
+		}
+	}
+
+	for _, line := range strings.Split(body, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line exceeds 75 octets: %q", line)
+		}
+	}
+}
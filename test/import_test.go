@@ -0,0 +1,360 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// importCourseRepository keeps the courses an import creates
+type importCourseRepository struct {
+	repositories.ICourseRepository
+	courses []models.Course
+}
+
+func (r *importCourseRepository) ListCourses(ctx context.Context, userID string) ([]models.Course, error) {
+	return slices.Clone(r.courses), nil
+}
+
+func (r *importCourseRepository) CreateCourse(ctx context.Context, course *models.Course) error {
+	course.ID = len(r.courses) + 1
+	r.courses = append(r.courses, *course)
+	return nil
+}
+
+func (r *importCourseRepository) WithTx(tx *gorm.DB) repositories.ICourseRepository {
+	return r
+}
+
+type fixedSemesterRepository struct {
+	repositories.ISemesterRepository
+}
+
+func (r *fixedSemesterRepository) GetSemesterByID(ctx context.Context, id int) (*models.Semester, error) {
+	return &models.Semester{ID: id}, nil
+}
+
+func (r *fixedSemesterRepository) FindSemesterByDate(ctx context.Context, date time.Time) (*models.Semester, error) {
+	return &models.Semester{ID: 1}, nil
+}
+
+// memorySessionRepository stores class sessions and preloads their course like the SQL repository
+type memorySessionRepository struct {
+	repositories.IClassSessionRepository
+	courses  *importCourseRepository
+	nextID   int
+	sessions []models.ClassSession
+}
+
+func (r *memorySessionRepository) ListSessions(ctx context.Context, userID string, courseID *int) ([]models.ClassSession, error) {
+	sessions := make([]models.ClassSession, 0, len(r.sessions))
+	for _, session := range r.sessions {
+		for _, course := range r.courses.courses {
+			if course.ID == session.CourseID {
+				session.Course = &course
+			}
+		}
+		session.Exceptions = slices.Clone(session.Exceptions)
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (r *memorySessionRepository) CreateSession(ctx context.Context, session *models.ClassSession) error {
+	r.nextID++
+	session.ID = r.nextID
+	r.sessions = append(r.sessions, *session)
+	return nil
+}
+
+func (r *memorySessionRepository) UpdateSession(ctx context.Context, id int, userID string, updates map[string]any) error {
+	for i := range r.sessions {
+		if r.sessions[i].ID != id {
+			continue
+		}
+		session := &r.sessions[i]
+		session.ExternalUID = updates["external_uid"].(sql.NullString)
+		session.ImportSource = updates["import_source"].(string)
+		session.CourseID = updates["course_id"].(int)
+		session.Weekday = updates["weekday"].(int8)
+		session.StartTime = updates["start_time"].(string)
+		session.EndTime = updates["end_time"].(string)
+		session.Location = updates["location"].(sql.NullString)
+		session.Recurrence = updates["recurrence"].(int8)
+		session.StartDate = updates["start_date"].(time.Time)
+		session.EndDate = updates["end_date"].(sql.NullTime)
+		return nil
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memorySessionRepository) DeleteSession(ctx context.Context, id int, userID string) error {
+	r.sessions = slices.DeleteFunc(r.sessions, func(session models.ClassSession) bool { return session.ID == id })
+	return nil
+}
+
+func (r *memorySessionRepository) ReplaceExceptions(ctx context.Context, sessionID int, exceptions []models.ClassSessionException) error {
+	for i := range r.sessions {
+		if r.sessions[i].ID == sessionID {
+			r.sessions[i].Exceptions = exceptions
+		}
+	}
+	return nil
+}
+
+func (r *memorySessionRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return fn(nil)
+}
+
+func (r *memorySessionRepository) WithTx(tx *gorm.DB) repositories.IClassSessionRepository {
+	return r
+}
+
+type unlimitedQuotaService struct {
+	services.IQuotaService
+}
+
+func (s *unlimitedQuotaService) CheckCourses(ctx context.Context, userID string, count int) int {
+	return response.CodeSuccess
+}
+
+func (s *unlimitedQuotaService) ReserveAIOperation(ctx context.Context, userID, operation string) (int, int) {
+	return 0, response.CodeSuccess
+}
+
+func (s *unlimitedQuotaService) ReleaseAIOperation(ctx context.Context, reservationID int) {}
+
+func newImportService() (services.ITimetableImportService, *memorySessionRepository) {
+	global.Log = zap.NewNop()
+
+	courses := &importCourseRepository{}
+	sessions := &memorySessionRepository{courses: courses}
+	users := &memoryUserRepository{user: &models.User{UserID: "user-1", Timezone: "UTC"}}
+	return services.NewTimetableImportService(users, courses, &fixedSemesterRepository{}, sessions, &unlimitedQuotaService{}), sessions
+}
+
+// lectureFeed builds a calendar with one weekly CS101 lecture on the given days
+// (2026-09-07 is a Monday) and optionally a tutorial event
+func lectureFeed(byDay string, tutorial bool) string {
+	var feed strings.Builder
+	feed.WriteString("BEGIN:VCALENDAR\r\nVERSION:2.0\r\n")
+	feed.WriteString("BEGIN:VEVENT\r\nUID:lecture-1\r\nSUMMARY:CS101 Algorithms\r\n")
+	feed.WriteString("DTSTART:20260907T090000Z\r\nDTEND:20260907T103000Z\r\n")
+	fmt.Fprintf(&feed, "RRULE:FREQ=WEEKLY;BYDAY=%s;UNTIL=20261218T235959Z\r\nEND:VEVENT\r\n", byDay)
+	if tutorial {
+		feed.WriteString("BEGIN:VEVENT\r\nUID:tutorial-1\r\nSUMMARY:CS101 Algorithms\r\n")
+		feed.WriteString("DTSTART:20260908T140000Z\r\nDTEND:20260908T150000Z\r\n")
+		feed.WriteString("RRULE:FREQ=WEEKLY;UNTIL=20261218T235959Z\r\nEND:VEVENT\r\n")
+	}
+	feed.WriteString("END:VCALENDAR\r\n")
+	return feed.String()
+}
+
+// labFeed builds a separately published calendar with a weekly CS102 lab on Fridays
+func labFeed() string {
+	var feed strings.Builder
+	feed.WriteString("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nX-WR-CALNAME:CS102 Labs\r\n")
+	feed.WriteString("BEGIN:VEVENT\r\nUID:lab-1\r\nSUMMARY:CS102 Lab\r\n")
+	feed.WriteString("DTSTART:20260911T130000Z\r\nDTEND:20260911T150000Z\r\n")
+	feed.WriteString("RRULE:FREQ=WEEKLY;UNTIL=20261218T235959Z\r\nEND:VEVENT\r\n")
+	feed.WriteString("END:VCALENDAR\r\n")
+	return feed.String()
+}
+
+func importActions(result *models.TimetableImportResult) map[string]string {
+	actions := map[string]string{}
+	for _, session := range result.Sessions {
+		actions[session.UID] = session.Action
+	}
+	return actions
+}
+
+func sessionUIDs(repo *memorySessionRepository) map[string]int8 {
+	uids := map[string]int8{}
+	for _, session := range repo.sessions {
+		uids[session.ExternalUID.String] = session.Weekday
+	}
+	return uids
+}
+
+func TestTimetableReimportIsUnchanged(t *testing.T) {
+	importer, sessions := newImportService()
+	ctx := context.Background()
+
+	if _, code := importer.ImportTimetable(ctx, "user-1", strings.NewReader(lectureFeed("MO,WE", true)), "", nil, true, false); code != response.CodeSuccess {
+		t.Fatalf("first import: code %d", code)
+	}
+	if len(sessions.sessions) != 3 {
+		t.Fatalf("first import saved %d sessions, want 3", len(sessions.sessions))
+	}
+	var ids []int
+	for _, session := range sessions.sessions {
+		ids = append(ids, session.ID)
+	}
+
+	result, code := importer.ImportTimetable(ctx, "user-1", strings.NewReader(lectureFeed("MO,WE", true)), "", nil, true, false)
+	if code != response.CodeSuccess {
+		t.Fatalf("re-import: code %d", code)
+	}
+	for uid, action := range importActions(result) {
+		if action != consts.TimetableImportAction.UNCHANGED {
+			t.Errorf("re-import %s: action %q, want unchanged", uid, action)
+		}
+	}
+	if len(result.Courses) != 1 || result.Courses[0].Action != consts.TimetableImportAction.EXISTING {
+		t.Errorf("re-import courses = %+v, want the existing CS101", result.Courses)
+	}
+	for i, session := range sessions.sessions {
+		if session.ID != ids[i] {
+			t.Errorf("session %d was recreated as %d", ids[i], session.ID)
+		}
+	}
+}
+
+func TestTimetableReimportFollowsChangedWeekdays(t *testing.T) {
+	importer, sessions := newImportService()
+	ctx := context.Background()
+
+	if _, code := importer.ImportTimetable(ctx, "user-1", strings.NewReader(lectureFeed("MO,WE", false)), "", nil, true, false); code != response.CodeSuccess {
+		t.Fatalf("first import: code %d", code)
+	}
+	var wednesdayID int
+	for _, session := range sessions.sessions {
+		if session.ExternalUID.String == "lecture-1#WE" {
+			wednesdayID = session.ID
+		}
+	}
+	if wednesdayID == 0 {
+		t.Fatalf("first import saved %v, want lecture-1#MO and lecture-1#WE", sessionUIDs(sessions))
+	}
+
+	// The lecture moves from Wednesday to Thursday upstream
+	result, code := importer.ImportTimetable(ctx, "user-1", strings.NewReader(lectureFeed("MO,TH", false)), "", nil, true, false)
+	if code != response.CodeSuccess {
+		t.Fatalf("re-import: code %d", code)
+	}
+	actions := importActions(result)
+	if actions["lecture-1#MO"] != consts.TimetableImportAction.UNCHANGED || actions["lecture-1#TH"] != consts.TimetableImportAction.UPDATE {
+		t.Errorf("re-import actions = %v, want MO unchanged and TH updated", actions)
+	}
+
+	if len(sessions.sessions) != 2 {
+		t.Fatalf("re-import left %d sessions, want 2: %v", len(sessions.sessions), sessionUIDs(sessions))
+	}
+	for _, session := range sessions.sessions {
+		if session.ExternalUID.String == "lecture-1#TH" && (session.ID != wednesdayID || session.Weekday != 4) {
+			t.Errorf("thursday session = id %d weekday %d, want the wednesday row %d moved to weekday 4", session.ID, session.Weekday, wednesdayID)
+		}
+	}
+}
+
+func TestTimetableReimportRemovesVanishedEvents(t *testing.T) {
+	importer, sessions := newImportService()
+	ctx := context.Background()
+
+	if _, code := importer.ImportTimetable(ctx, "user-1", strings.NewReader(lectureFeed("MO", true)), "", nil, true, false); code != response.CodeSuccess {
+		t.Fatalf("first import: code %d", code)
+	}
+	// Sessions the user created by hand are not part of the feed and must survive
+	sessions.CreateSession(ctx, &models.ClassSession{
+		UserID: "user-1", CourseID: 1, Weekday: 5, StartTime: "16:00:00", EndTime: "17:00:00",
+		Recurrence: consts.ClassSessionRecurrence.WEEKLY, StartDate: mustDate(t, "2026-09-07"),
+	})
+
+	preview, code := importer.ImportTimetable(ctx, "user-1", strings.NewReader(lectureFeed("MO", false)), "", nil, false, false)
+	if code != response.CodeSuccess {
+		t.Fatalf("preview: code %d", code)
+	}
+	if action := importActions(preview)["tutorial-1"]; action != consts.TimetableImportAction.DELETE {
+		t.Errorf("preview tutorial action = %q, want delete", action)
+	}
+	if len(sessions.sessions) != 3 {
+		t.Fatalf("preview changed the timetable to %v", sessionUIDs(sessions))
+	}
+
+	if _, code := importer.ImportTimetable(ctx, "user-1", strings.NewReader(lectureFeed("MO", false)), "", nil, true, false); code != response.CodeSuccess {
+		t.Fatalf("re-import: code %d", code)
+	}
+	uids := sessionUIDs(sessions)
+	if _, ok := uids["tutorial-1"]; ok || len(uids) != 2 {
+		t.Errorf("sessions after re-import = %v, want the lecture and the manual session", uids)
+	}
+}
+
+func TestTimetableImportRejectsClashes(t *testing.T) {
+	importer, sessions := newImportService()
+	ctx := context.Background()
+
+	// A manual Monday session overlapping the imported lecture
+	sessions.CreateSession(ctx, &models.ClassSession{
+		UserID: "user-1", CourseID: 9, Weekday: 1, StartTime: "10:00:00", EndTime: "11:00:00",
+		Recurrence: consts.ClassSessionRecurrence.WEEKLY, StartDate: mustDate(t, "2026-09-07"),
+	})
+
+	result, code := importer.ImportTimetable(ctx, "user-1", strings.NewReader(lectureFeed("MO", false)), "", nil, true, false)
+	if code != response.CodeClassSessionConflict {
+		t.Fatalf("clashing import: code %d, want %d", code, response.CodeClassSessionConflict)
+	}
+	if len(sessions.sessions) != 1 {
+		t.Fatalf("clashing import saved sessions: %v", sessionUIDs(sessions))
+	}
+	if len(result.Sessions) != 1 || len(result.Sessions[0].Conflicts) != 1 || result.Sessions[0].Conflicts[0].SessionID != 1 {
+		t.Fatalf("clashing import result = %+v, want a conflict with session 1", result.Sessions)
+	}
+
+	if _, code := importer.ImportTimetable(ctx, "user-1", strings.NewReader(lectureFeed("MO", false)), "", nil, true, true); code != response.CodeSuccess {
+		t.Fatalf("import allowing conflicts: code %d", code)
+	}
+	if len(sessions.sessions) != 2 {
+		t.Errorf("import allowing conflicts left %v", sessionUIDs(sessions))
+	}
+}
+
+func TestTimetableImportKeepsOtherSources(t *testing.T) {
+	importer, sessions := newImportService()
+	ctx := context.Background()
+
+	if _, code := importer.ImportTimetable(ctx, "user-1", strings.NewReader(lectureFeed("MO", true)), "", nil, true, false); code != response.CodeSuccess {
+		t.Fatalf("lecture import: code %d", code)
+	}
+	result, code := importer.ImportTimetable(ctx, "user-1", strings.NewReader(labFeed()), "", nil, true, false)
+	if code != response.CodeSuccess {
+		t.Fatalf("lab import: code %d", code)
+	}
+	if result.Source != "CS102 Labs" {
+		t.Errorf("lab import source = %q, want the calendar's name", result.Source)
+	}
+	if actions := importActions(result); len(actions) != 1 || actions["lab-1"] != consts.TimetableImportAction.CREATE {
+		t.Errorf("lab import actions = %v, want only lab-1 created", actions)
+	}
+	if uids := sessionUIDs(sessions); len(uids) != 3 {
+		t.Fatalf("sessions after the lab import = %v, want the lectures and the lab", uids)
+	}
+
+	// Re-importing the lectures without the tutorial only touches their own sessions
+	if _, code := importer.ImportTimetable(ctx, "user-1", strings.NewReader(lectureFeed("MO", false)), "", nil, true, false); code != response.CodeSuccess {
+		t.Fatalf("lecture re-import: code %d", code)
+	}
+	uids := sessionUIDs(sessions)
+	if _, ok := uids["tutorial-1"]; ok || len(uids) != 2 {
+		t.Errorf("sessions after the lecture re-import = %v, want the lecture and the lab", uids)
+	}
+	for _, session := range sessions.sessions {
+		if session.ExternalUID.String == "lab-1" && session.ImportSource != "CS102 Labs" {
+			t.Errorf("lab session source = %q", session.ImportSource)
+		}
+	}
+}
@@ -0,0 +1,388 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/queue"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/ot"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"github.com/nas03/scholar-ai/backend/pkg/richtext"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// collabNoteRepository keeps notes in memory; transactions run one at a
+// time, standing in for the row lock
+type collabNoteRepository struct {
+	repositories.INoteRepository
+
+	tx        sync.Mutex
+	mu        sync.Mutex
+	notes     map[int]models.Note
+	revisions []models.NoteRevision
+}
+
+func (r *collabNoteRepository) GetNoteByID(ctx context.Context, id int, userID string) (*models.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	note, ok := r.notes[id]
+	if !ok || note.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &note, nil
+}
+
+func (r *collabNoteRepository) LockNote(ctx context.Context, id int, userID string) (*models.Note, error) {
+	return r.GetNoteByID(ctx, id, userID)
+}
+
+func (r *collabNoteRepository) UpdateNote(ctx context.Context, id int, userID string, updates map[string]any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	note := r.notes[id]
+	for column, value := range updates {
+		switch column {
+		case "title":
+			note.Title = value.(string)
+		case "content":
+			note.Content = value.(json.RawMessage)
+		case "content_html":
+			note.ContentHTML = value.(string)
+		case "content_text":
+			note.ContentText = value.(string)
+		case "version":
+			note.Version = value.(int)
+		}
+	}
+	note.UpdatedAt = note.UpdatedAt.Add(time.Millisecond)
+	r.notes[id] = note
+	return nil
+}
+
+func (r *collabNoteRepository) CreateRevision(ctx context.Context, revision *models.NoteRevision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.revisions = append(r.revisions, *revision)
+	return nil
+}
+
+func (r *collabNoteRepository) PruneRevisions(ctx context.Context, noteID, keep int) error {
+	return nil
+}
+
+func (r *collabNoteRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	r.tx.Lock()
+	defer r.tx.Unlock()
+	return fn(nil)
+}
+
+func (r *collabNoteRepository) WithTx(tx *gorm.DB) repositories.INoteRepository {
+	return r
+}
+
+func (r *collabNoteRepository) note(id int) models.Note {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.notes[id]
+}
+
+// sharedNoteRepository shares every note with the given users
+type sharedNoteRepository struct {
+	repositories.IStudyGroupRepository
+	notes   *collabNoteRepository
+	members map[string]bool
+}
+
+func (r *sharedNoteRepository) FindSharedNote(ctx context.Context, noteID int, userID string) (*models.Note, error) {
+	note := r.notes.note(noteID)
+	if note.ID == 0 || !r.members[userID] {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &note, nil
+}
+
+type lockedJobQueue struct {
+	mu   sync.Mutex
+	jobs []string
+}
+
+func (q *lockedJobQueue) Enqueue(ctx context.Context, jobType string, payload any, opts ...queue.EnqueueOption) (*queue.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.jobs = append(q.jobs, jobType)
+	return &queue.Job{Type: jobType}, nil
+}
+
+// collabClient records what one editing connection receives
+type collabClient struct {
+	*services.NoteCollabClient
+	events chan models.NoteCollabEvent
+	kicked chan int
+}
+
+func connectCollab(t *testing.T, service services.INoteCollabService, noteID int, userID string) (*collabClient, func()) {
+	t.Helper()
+	client := &collabClient{events: make(chan models.NoteCollabEvent, 16), kicked: make(chan int, 1)}
+	client.NoteCollabClient = &services.NoteCollabClient{
+		NoteID: noteID,
+		UserID: userID,
+		Send: func(event models.NoteCollabEvent) error {
+			client.events <- event
+			return nil
+		},
+		Kick: func(code int, reason string) { client.kicked <- code },
+	}
+	leave, code := service.Connect(context.Background(), client.NoteCollabClient)
+	if code != response.CodeSuccess {
+		t.Fatalf("Connect(%s) code = %d", userID, code)
+	}
+	return client, leave
+}
+
+func (c *collabClient) next(t *testing.T) models.NoteCollabEvent {
+	t.Helper()
+	select {
+	case event := <-c.events:
+		return event
+	case code := <-c.kicked:
+		t.Fatalf("%s kicked with %d", c.UserID, code)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s received no event", c.UserID)
+	}
+	return models.NoteCollabEvent{}
+}
+
+func charTokens(text string) []richtext.Token {
+	var tokens []richtext.Token
+	for _, r := range text {
+		tokens = append(tokens, richtext.Token{Kind: richtext.TokenChar, Text: string(r)})
+	}
+	return tokens
+}
+
+func TestOTTransformConverges(t *testing.T) {
+	base := []rune("abcdef")
+	cases := []struct {
+		name string
+		a, b ot.Operation[rune]
+	}{
+		{"inserts at the same place", ot.Operation[rune]{{Retain: 2}, {Insert: []rune("X")}, {Retain: 4}}, ot.Operation[rune]{{Retain: 2}, {Insert: []rune("Y")}, {Retain: 4}}},
+		{"insert inside a deletion", ot.Operation[rune]{{Retain: 3}, {Insert: []rune("XY")}, {Retain: 3}}, ot.Operation[rune]{{Retain: 1}, {Delete: 4}, {Retain: 1}}},
+		{"overlapping deletions", ot.Operation[rune]{{Delete: 3}, {Retain: 3}}, ot.Operation[rune]{{Retain: 2}, {Delete: 3}, {Retain: 1}}},
+		{"replace against append", ot.Operation[rune]{{Delete: 6}, {Insert: []rune("new")}}, ot.Operation[rune]{{Retain: 6}, {Insert: []rune("!")}}},
+	}
+	for _, tc := range cases {
+		aPrime, bPrime, err := ot.Transform(tc.a, tc.b)
+		if err != nil {
+			t.Fatalf("%s: Transform: %v", tc.name, err)
+		}
+		viaA, err := ot.Apply(mustApply(t, base, tc.a), bPrime)
+		if err != nil {
+			t.Fatalf("%s: apply b': %v", tc.name, err)
+		}
+		viaB, err := ot.Apply(mustApply(t, base, tc.b), aPrime)
+		if err != nil {
+			t.Fatalf("%s: apply a': %v", tc.name, err)
+		}
+		if string(viaA) != string(viaB) {
+			t.Errorf("%s: a,b' = %q but b,a' = %q", tc.name, string(viaA), string(viaB))
+		}
+	}
+
+	if _, err := ot.Apply(base, ot.Operation[rune]{{Retain: 5}}); err == nil {
+		t.Error("Apply accepted an operation shorter than the sequence")
+	}
+}
+
+func mustApply(t *testing.T, seq []rune, op ot.Operation[rune]) []rune {
+	t.Helper()
+	result, err := ot.Apply(seq, op)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	return result
+}
+
+func TestRichTextTokensRoundTrip(t *testing.T) {
+	doc, err := richtext.Parse([]byte(`{"type":"doc","content":[
+		{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Week 3"}]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"ab"},
+			{"type":"text","text":"c","marks":[{"type":"bold"}]},
+			{"type":"hardBreak"},
+			{"type":"text","text":"é"}
+		]},
+		{"type":"horizontalRule"}
+	]}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	tokens := doc.Tokens()
+	// heading: 1 + 6 + 1, paragraph: 1 + 3 + 1 + 1 + 1, rule: 1
+	if len(tokens) != 16 {
+		t.Fatalf("len(Tokens) = %d, want 16", len(tokens))
+	}
+	rebuilt, err := richtext.FromTokens(tokens)
+	if err != nil {
+		t.Fatalf("FromTokens: %v", err)
+	}
+	want, _ := doc.JSON()
+	got, _ := rebuilt.JSON()
+	if string(got) != string(want) {
+		t.Errorf("round trip:\n got %s\nwant %s", got, want)
+	}
+
+	// Deleting a paragraph's end leaves the document unbalanced
+	if _, err := richtext.FromTokens(tokens[:len(tokens)-2]); err == nil {
+		t.Error("FromTokens accepted an unclosed node")
+	}
+}
+
+func TestNoteCollabMergesConcurrentEditsAcrossInstances(t *testing.T) {
+	global.Log = zap.NewNop()
+	mr := miniredis.RunT(t)
+	content := json.RawMessage(`{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"ab"}]}]}`)
+	notes := &collabNoteRepository{notes: map[int]models.Note{
+		7: {ID: 7, UserID: "ann", Title: "Week 3", Content: content, ContentText: "ab", Version: 1},
+	}}
+	groups := &sharedNoteRepository{notes: notes, members: map[string]bool{"ben": true}}
+	jobs := &lockedJobQueue{}
+	instance := func() services.INoteCollabService {
+		store := repositories.NewRedisNoteCollabStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+		return services.NewNoteCollabService(notes, groups, store, jobs)
+	}
+	first, second := instance(), instance()
+
+	if _, code := first.CheckAccess(context.Background(), "eve", 7); code != response.CodeNoteNotFound {
+		t.Errorf("stranger access code = %d, want %d", code, response.CodeNoteNotFound)
+	}
+
+	ann, leaveAnn := connectCollab(t, first, 7, "ann")
+	ben, leaveBen := connectCollab(t, second, 7, "ben")
+	annInit, benInit := ann.next(t), ben.next(t)
+	if annInit.Type != consts.NoteCollabEventType.INIT || annInit.Rev != 0 || benInit.Rev != 0 {
+		t.Fatalf("init events = %+v, %+v", annInit, benInit)
+	}
+
+	// Both type after "a" without having seen the other's edit
+	var wg sync.WaitGroup
+	for _, edit := range []struct {
+		service services.INoteCollabService
+		client  *collabClient
+		text    string
+	}{{first, ann, "X"}, {second, ben, "Y"}} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			op := models.NoteOperation{{Retain: 2}, {Insert: charTokens(edit.text)}, {Retain: 2}}
+			if code := edit.service.Submit(context.Background(), edit.client.NoteCollabClient, 0, op); code != response.CodeSuccess {
+				t.Errorf("%s Submit code = %d", edit.client.UserID, code)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every client sees both edits in revision order and its own as an ack
+	for _, c := range []struct {
+		client *collabClient
+		connID string
+	}{{ann, annInit.ConnID}, {ben, benInit.ConnID}} {
+		acks := 0
+		for rev := 1; rev <= 2; rev++ {
+			event := c.client.next(t)
+			if event.Type != consts.NoteCollabEventType.OP || event.Rev != rev {
+				t.Fatalf("%s event = %+v, want op at rev %d", c.client.UserID, event, rev)
+			}
+			if event.ConnID == c.connID {
+				acks++
+			}
+		}
+		if acks != 1 {
+			t.Errorf("%s got %d acks, want 1", c.client.UserID, acks)
+		}
+	}
+
+	// Edits cannot claim a revision the session has not reached
+	if code := first.Submit(context.Background(), ann.NoteCollabClient, 5, models.NoteOperation{{Retain: 6}}); code != response.CodeNoteCollabInvalidOp {
+		t.Errorf("Submit ahead of the session code = %d, want %d", code, response.CodeNoteCollabInvalidOp)
+	}
+
+	leaveAnn()
+	if !mr.Exists(fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_STATE, 7)) {
+		t.Fatal("session closed while ben still had it open")
+	}
+	leaveBen()
+
+	note := notes.note(7)
+	if note.ContentText != "aXYb" && note.ContentText != "aYXb" {
+		t.Errorf("saved text = %q, want both edits", note.ContentText)
+	}
+	if note.Version != 2 || len(notes.revisions) != 1 {
+		t.Errorf("version = %d with %d revisions, want one snapshot", note.Version, len(notes.revisions))
+	}
+	if !reflect.DeepEqual(jobs.jobs, []string{consts.JobType.EMBEDDING_INDEX}) {
+		t.Errorf("jobs = %v, want one re-index", jobs.jobs)
+	}
+	if mr.Exists(fmt.Sprintf(consts.REDIS_KEY_NOTE_COLLAB_STATE, 7)) {
+		t.Error("session still open after everyone left")
+	}
+}
+
+func TestNoteUpdateChecksIfMatch(t *testing.T) {
+	global.Log = zap.NewNop()
+	mr := miniredis.RunT(t)
+	store := repositories.NewRedisNoteCollabStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	content := json.RawMessage(`{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"ab"}]}]}`)
+	notes := &collabNoteRepository{notes: map[int]models.Note{
+		7: {ID: 7, UserID: "ann", Title: "Week 3", Content: content, Version: 3, TableCommon: models.TableCommon{UpdatedAt: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)}},
+	}}
+	service := services.NewNoteService(notes, nil, nil, store, &lockedJobQueue{})
+	ctx := context.Background()
+
+	current := notes.note(7)
+	etag := current.ETag()
+	title := "Week 3: recursion"
+	stale := `"2-0"`
+	if _, code := service.UpdateNote(ctx, "ann", 7, stale, &models.UpdateNoteRequest{Title: &title}); code != response.CodeNoteVersionConflict {
+		t.Fatalf("stale If-Match code = %d, want %d", code, response.CodeNoteVersionConflict)
+	}
+
+	updated, code := service.UpdateNote(ctx, "ann", 7, stale+", "+etag, &models.UpdateNoteRequest{Title: &title})
+	if code != response.CodeSuccess {
+		t.Fatalf("matching If-Match code = %d", code)
+	}
+	if updated.Version != 4 || updated.ETag() == etag {
+		t.Errorf("updated version %d etag %s, want a new version", updated.Version, updated.ETag())
+	}
+	// The ETag the first writer used is stale for the second
+	if _, code := service.UpdateNote(ctx, "ann", 7, etag, &models.UpdateNoteRequest{Title: &title}); code != response.CodeNoteVersionConflict {
+		t.Errorf("reused If-Match code = %d, want %d", code, response.CodeNoteVersionConflict)
+	}
+
+	// Content belongs to the editing session while one is open
+	if err := store.SetOnline(ctx, 7, "conn", time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	edited := json.RawMessage(`{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"abc"}]}]}`)
+	if _, code := service.UpdateNote(ctx, "ann", 7, "*", &models.UpdateNoteRequest{Content: edited}); code != response.CodeNoteCollabActive {
+		t.Errorf("content update during session code = %d, want %d", code, response.CodeNoteCollabActive)
+	}
+	renamed := "Week 3: recursion and induction"
+	if _, code := service.UpdateNote(ctx, "ann", 7, "", &models.UpdateNoteRequest{Title: &renamed}); code != response.CodeSuccess {
+		t.Errorf("title update during session code = %d", code)
+	}
+}
@@ -0,0 +1,507 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// memoryNotificationRepository keeps scheduled notifications in memory. Its mutex
+// plays the row locks: a notification claimed by one worker is sending, so the
+// others pass over it like SKIP LOCKED does until the claim goes stale.
+type memoryNotificationRepository struct {
+	repositories.INotificationRepository
+	mu            sync.Mutex
+	nextID        int
+	notifications map[int]*models.ReminderNotification
+}
+
+func newMemoryNotificationRepository() *memoryNotificationRepository {
+	return &memoryNotificationRepository{notifications: map[int]*models.ReminderNotification{}}
+}
+
+func (r *memoryNotificationRepository) GetSettings(ctx context.Context, userIDs []string) ([]models.NotificationSetting, error) {
+	return nil, nil
+}
+
+func (r *memoryNotificationRepository) EnqueueNotifications(ctx context.Context, notifications []models.ReminderNotification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, notification := range notifications {
+		if r.find(notification.ReminderID, notification.OffsetMinutes, notification.Deadline) != nil {
+			continue
+		}
+		r.nextID++
+		notification.ID = r.nextID
+		r.notifications[notification.ID] = &notification
+	}
+	return nil
+}
+
+func (r *memoryNotificationRepository) ClaimDueNotifications(ctx context.Context, now, staleBefore time.Time, limit int) ([]models.ReminderNotification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*models.ReminderNotification
+	for _, notification := range r.notifications {
+		pending := notification.Status == consts.NotificationStatus.PENDING && !notification.ScheduledAt.After(now)
+		stale := notification.Status == consts.NotificationStatus.SENDING && notification.ClaimedAt.Time.Before(staleBefore)
+		if pending || stale {
+			due = append(due, notification)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ScheduledAt.Before(due[j].ScheduledAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]models.ReminderNotification, 0, len(due))
+	for _, notification := range due {
+		notification.Status = consts.NotificationStatus.SENDING
+		notification.ClaimedAt.Time, notification.ClaimedAt.Valid = now, true
+		notification.Attempts++
+		claimed = append(claimed, *notification)
+	}
+	return claimed, nil
+}
+
+func (r *memoryNotificationRepository) MarkNotificationSent(ctx context.Context, id int, sentAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifications[id].Status = consts.NotificationStatus.SENT
+	r.notifications[id].SentAt.Time, r.notifications[id].SentAt.Valid = sentAt, true
+	return nil
+}
+
+func (r *memoryNotificationRepository) MarkNotificationSkipped(ctx context.Context, id int, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifications[id].Status = consts.NotificationStatus.SKIPPED
+	return nil
+}
+
+func (r *memoryNotificationRepository) MarkNotificationFailed(ctx context.Context, id int, reason string, retryAt *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	notification := r.notifications[id]
+	notification.Status = consts.NotificationStatus.FAILED
+	if retryAt != nil {
+		notification.Status = consts.NotificationStatus.PENDING
+		notification.ScheduledAt = *retryAt
+	}
+	return nil
+}
+
+func (r *memoryNotificationRepository) DeleteByReminder(ctx context.Context, reminderID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, notification := range r.notifications {
+		if notification.ReminderID == reminderID && (notification.Status == consts.NotificationStatus.PENDING || notification.Status == consts.NotificationStatus.FAILED) {
+			delete(r.notifications, id)
+		}
+	}
+	return nil
+}
+
+func (r *memoryNotificationRepository) DeleteUnsentByUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, notification := range r.notifications {
+		if notification.UserID == userID && (notification.Status == consts.NotificationStatus.PENDING || notification.Status == consts.NotificationStatus.FAILED) {
+			delete(r.notifications, id)
+		}
+	}
+	return nil
+}
+
+func (r *memoryNotificationRepository) WithTx(tx *gorm.DB) repositories.INotificationRepository {
+	return r
+}
+
+func (r *memoryNotificationRepository) find(reminderID, offset int, deadline time.Time) *models.ReminderNotification {
+	for _, notification := range r.notifications {
+		if notification.ReminderID == reminderID && notification.OffsetMinutes == offset && notification.Deadline.Equal(deadline) {
+			return notification
+		}
+	}
+	return nil
+}
+
+// statuses counts the notifications by status
+func (r *memoryNotificationRepository) statuses() map[int8]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := map[int8]int{}
+	for _, notification := range r.notifications {
+		counts[notification.Status]++
+	}
+	return counts
+}
+
+// notifierUserRepository serves every user in one timezone, UTC unless it was updated
+type notifierUserRepository struct {
+	repositories.IUserRepository
+	mu       sync.Mutex
+	timezone string
+}
+
+func (r *notifierUserRepository) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	timezone := r.timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	return &models.User{UserID: userID, Username: userID, Email: userID + "@example.com", Timezone: timezone}, nil
+}
+
+func (r *notifierUserRepository) UpdateUser(ctx context.Context, userID string, updates map[string]any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timezone = updates["timezone"].(string)
+	return nil
+}
+
+func (r *notifierUserRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return fn(nil)
+}
+
+func (r *notifierUserRepository) WithTx(tx *gorm.DB) repositories.IUserRepository {
+	return r
+}
+
+func (r *notifierUserRepository) GetUsersByIDs(ctx context.Context, userIDs []string) ([]models.User, error) {
+	users := make([]models.User, 0, len(userIDs))
+	for _, userID := range userIDs {
+		user, _ := r.GetUserByID(ctx, userID)
+		users = append(users, *user)
+	}
+	return users, nil
+}
+
+type failingMailHelper struct {
+	recordingMailHelper
+}
+
+func (h *failingMailHelper) SendMail(ctx context.Context, to, subject, body string) (string, error) {
+	return "", errors.New("smtp unavailable")
+}
+
+// notifierFixture wires a notifier over in-memory repositories with the default
+// offsets of one week, three days and one day
+type notifierFixture struct {
+	reminders     *memoryReminderRepository
+	notifications *memoryNotificationRepository
+	users         *notifierUserRepository
+	mail          *recordingMailHelper
+	notifier      services.IReminderNotifier
+}
+
+func newNotifierFixture(t *testing.T) *notifierFixture {
+	t.Helper()
+	global.Log = zap.NewNop()
+	notification := global.Config.Notification
+	t.Cleanup(func() { global.Config.Notification = notification })
+	global.Config.Notification.DefaultOffsets = nil
+
+	f := &notifierFixture{
+		reminders:     newMemoryReminderRepository(time.UTC),
+		notifications: newMemoryNotificationRepository(),
+		users:         &notifierUserRepository{},
+		mail:          &recordingMailHelper{},
+	}
+	f.notifier = services.NewReminderNotifier(f.reminders, f.notifications, f.users, nil, &missingMailRepository{}, f.mail)
+	return f
+}
+
+// addReminder stores a pending reminder due at deadline, created at createdAt
+func (f *notifierFixture) addReminder(t *testing.T, deadline, createdAt time.Time) *models.Reminder {
+	t.Helper()
+	reminder := &models.Reminder{
+		Title:       "Essay",
+		UserID:      "user-1",
+		Status:      consts.ReminderStatus.PENDING,
+		TableCommon: models.TableCommon{CreatedAt: createdAt},
+	}
+	setDeadline(reminder, deadline)
+	if err := f.reminders.CreateReminder(context.Background(), reminder); err != nil {
+		t.Fatal(err)
+	}
+	return reminder
+}
+
+func setDeadline(reminder *models.Reminder, deadline time.Time) {
+	reminder.DueDate = time.Date(deadline.Year(), deadline.Month(), deadline.Day(), 0, 0, 0, 0, time.UTC)
+	reminder.DueTime = deadline.Format(time.TimeOnly)
+}
+
+func TestNotifierEnqueuesOffsetsOnce(t *testing.T) {
+	f := newNotifierFixture(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	// Created an hour ago and due in two days: the week and three day offsets
+	// would have fired before the reminder existed
+	f.addReminder(t, now.Add(48*time.Hour), now.Add(-time.Hour))
+	for range 3 {
+		if err := f.notifier.Tick(ctx, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(f.notifications.notifications) != 1 {
+		t.Fatalf("notifications = %d, want only the one day offset", len(f.notifications.notifications))
+	}
+	for _, notification := range f.notifications.notifications {
+		if notification.OffsetMinutes != 24*60 || !notification.ScheduledAt.Equal(now.Add(24*time.Hour)) {
+			t.Errorf("notification offset %d at %v, want one day before the deadline", notification.OffsetMinutes, notification.ScheduledAt)
+		}
+	}
+	if len(f.mail.sent) != 0 {
+		t.Errorf("mails sent before any offset was due: %d", len(f.mail.sent))
+	}
+}
+
+func TestNotifierDoesNotResendOffsetForSameDeadline(t *testing.T) {
+	f := newNotifierFixture(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	original := now.Add(23 * time.Hour)
+	reminder := f.addReminder(t, original, now.Add(-30*24*time.Hour))
+
+	// All three offsets are due at once; only the closest one is mailed
+	if err := f.notifier.Tick(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if counts := f.notifications.statuses(); len(f.mail.sent) != 1 || counts[consts.NotificationStatus.SENT] != 1 || counts[consts.NotificationStatus.SKIPPED] != 2 {
+		t.Fatalf("first tick: %d mails, statuses %v", len(f.mail.sent), counts)
+	}
+
+	// Moving the deadline a day out schedules its offsets again
+	moveDeadline := func(deadline time.Time) {
+		setDeadline(f.reminders.reminders[reminder.ID], deadline)
+		if err := f.notifications.DeleteByReminder(ctx, reminder.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	moveDeadline(original.Add(24 * time.Hour))
+	if err := f.notifier.Tick(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.mail.sent) != 2 {
+		t.Fatalf("mails after moving the deadline = %d, want 2", len(f.mail.sent))
+	}
+
+	// Moving it back must not mail the offsets already sent for the original deadline
+	moveDeadline(original)
+	if err := f.notifier.Tick(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.mail.sent) != 2 {
+		t.Errorf("mails after moving the deadline back = %d, want still 2", len(f.mail.sent))
+	}
+	if counts := f.notifications.statuses(); counts[consts.NotificationStatus.PENDING] != 0 {
+		t.Errorf("pending notifications for the restored deadline: %v", counts)
+	}
+}
+
+func TestNotifierClaimsEachNotificationOnce(t *testing.T) {
+	f := newNotifierFixture(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 20 {
+		f.addReminder(t, now.Add(23*time.Hour+time.Duration(i)*time.Minute), now.Add(-30*24*time.Hour))
+	}
+
+	// Workers running side by side never mail the same notification twice
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := f.notifier.Tick(ctx, now); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := map[string]int{}
+	for _, body := range f.mail.bodies {
+		seen[body]++
+	}
+	if len(f.mail.sent) != 20 || len(seen) != 20 {
+		t.Errorf("mails = %d (%d distinct), want one per reminder", len(f.mail.sent), len(seen))
+	}
+}
+
+func TestNotifierReclaimsStaleClaims(t *testing.T) {
+	f := newNotifierFixture(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	// One claim was abandoned by a crashed worker, the other is still being sent
+	for i, claimedAt := range []time.Time{now.Add(-10 * time.Minute), now.Add(-time.Minute)} {
+		deadline := now.Add(time.Duration(20+i) * time.Hour)
+		reminder := f.addReminder(t, deadline, now.Add(-time.Hour))
+		notification := models.ReminderNotification{
+			ReminderID:    reminder.ID,
+			UserID:        reminder.UserID,
+			OffsetMinutes: 24 * 60,
+			Deadline:      deadline,
+			ScheduledAt:   deadline.Add(-24 * time.Hour),
+			Status:        consts.NotificationStatus.SENDING,
+			Attempts:      1,
+		}
+		notification.ClaimedAt.Time, notification.ClaimedAt.Valid = claimedAt, true
+		if err := f.notifications.EnqueueNotifications(ctx, []models.ReminderNotification{notification}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := f.notifier.Tick(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	stale, inFlight := f.notifications.notifications[1], f.notifications.notifications[2]
+	if stale.Status != consts.NotificationStatus.SENT || stale.Attempts != 2 {
+		t.Errorf("stale claim: status %d after %d attempts, want sent on the second", stale.Status, stale.Attempts)
+	}
+	if inFlight.Status != consts.NotificationStatus.SENDING || inFlight.Attempts != 1 {
+		t.Errorf("fresh claim: status %d after %d attempts, want it left to its worker", inFlight.Status, inFlight.Attempts)
+	}
+	if len(f.mail.sent) != 1 {
+		t.Errorf("mails = %d, want 1", len(f.mail.sent))
+	}
+}
+
+func TestNotifierRetriesWithBackoff(t *testing.T) {
+	f := newNotifierFixture(t)
+	global.Config.Notification.MaxAttempts = 3
+	notifier := services.NewReminderNotifier(f.reminders, f.notifications, &notifierUserRepository{}, nil, &missingMailRepository{}, &failingMailHelper{})
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	f.addReminder(t, now.Add(12*time.Hour), now.Add(-48*time.Hour))
+
+	// Each failure waits attempts² minutes, the last one gives up
+	at := now
+	for attempt, wait := range []time.Duration{time.Minute, 4 * time.Minute} {
+		if err := notifier.Tick(ctx, at); err != nil {
+			t.Fatal(err)
+		}
+		if len(f.notifications.notifications) != 1 {
+			t.Fatalf("notifications = %d, want the one day offset", len(f.notifications.notifications))
+		}
+		notification := f.notifications.notifications[1]
+		if notification.Status != consts.NotificationStatus.PENDING || !notification.ScheduledAt.Equal(at.Add(wait)) {
+			t.Fatalf("after attempt %d: status %d, retry at %v, want pending at %v", attempt+1, notification.Status, notification.ScheduledAt, at.Add(wait))
+		}
+
+		// Nothing is retried before the backoff ends
+		if err := notifier.Tick(ctx, at.Add(wait-time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		if notification.Attempts != attempt+1 {
+			t.Fatalf("attempts = %d before the backoff ended, want %d", notification.Attempts, attempt+1)
+		}
+		at = at.Add(wait)
+	}
+
+	if err := notifier.Tick(ctx, at); err != nil {
+		t.Fatal(err)
+	}
+	if notification := f.notifications.notifications[1]; notification.Status != consts.NotificationStatus.FAILED || notification.Attempts != 3 {
+		t.Errorf("after max attempts: status %d, attempts %d, want failed after 3", notification.Status, notification.Attempts)
+	}
+}
+
+// staticCalendarRepository returns the same feed for every user
+type staticCalendarRepository struct {
+	repositories.ICalendarRepository
+}
+
+func (r *staticCalendarRepository) GetFeedByUserID(ctx context.Context, userID string) (*models.CalendarFeed, error) {
+	return &models.CalendarFeed{UserID: userID, Token: "token"}, nil
+}
+
+func TestNotifierMailsOnceAfterTimezoneChange(t *testing.T) {
+	f := newNotifierFixture(t)
+	calendar := services.NewCalendarService(&staticCalendarRepository{}, f.users, nil, f.reminders, f.notifications)
+	ctx := context.Background()
+	// UpdateTimezone reads the real clock
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Due tomorrow, and two hours ago, on the user's wall clock
+	essay := f.addReminder(t, now.Add(26*time.Hour), now.Add(-time.Hour))
+	quiz := f.addReminder(t, now.Add(-2*time.Hour), now.Add(-time.Hour))
+	if err := f.notifier.Tick(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if counts := f.notifications.statuses(); counts[consts.NotificationStatus.PENDING] != 1 || f.reminders.reminders[quiz.ID].Status != consts.ReminderStatus.OVERDUE {
+		t.Fatalf("before the change: notifications %v, quiz status %d", counts, f.reminders.reminders[quiz.ID].Status)
+	}
+
+	// Four hours behind UTC, both deadlines move four hours later
+	if _, code := calendar.UpdateTimezone(ctx, "user-1", "Etc/GMT+4"); code != response.CodeSuccess {
+		t.Fatalf("UpdateTimezone: code %d", code)
+	}
+	if len(f.notifications.notifications) != 0 {
+		t.Errorf("notifications for the old deadline survived: %v", f.notifications.statuses())
+	}
+	if status := f.reminders.reminders[quiz.ID].Status; status != consts.ReminderStatus.PENDING {
+		t.Errorf("quiz now due in two hours has status %d, want pending", status)
+	}
+
+	// The day-before mail was due at +2h for the old deadline and is now due at +6h
+	for _, at := range []time.Duration{3 * time.Hour, 7 * time.Hour, 8 * time.Hour} {
+		if err := f.notifier.Tick(ctx, now.Add(at)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(f.mail.sent) != 1 {
+		t.Fatalf("mails = %d, want 1", len(f.mail.sent))
+	}
+	for _, notification := range f.notifications.notifications {
+		if notification.ReminderID == essay.ID && !notification.Deadline.Equal(now.Add(30*time.Hour)) {
+			t.Errorf("notification for deadline %v, want %v", notification.Deadline, now.Add(30*time.Hour))
+		}
+	}
+}
+
+func TestNotifierSkipsNotificationOfMovedDeadline(t *testing.T) {
+	f := newNotifierFixture(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	// Enqueued for a deadline the reminder no longer has
+	reminder := f.addReminder(t, now.Add(20*time.Hour), now.Add(-time.Hour))
+	stale := models.ReminderNotification{
+		ReminderID:    reminder.ID,
+		UserID:        reminder.UserID,
+		OffsetMinutes: 24 * 60,
+		Deadline:      now.Add(10 * time.Hour),
+		ScheduledAt:   now.Add(-14 * time.Hour),
+		Status:        consts.NotificationStatus.PENDING,
+	}
+	if err := f.notifications.EnqueueNotifications(ctx, []models.ReminderNotification{stale}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.notifier.Tick(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if counts := f.notifications.statuses(); len(f.mail.sent) != 0 || counts[consts.NotificationStatus.SKIPPED] != 1 {
+		t.Errorf("mails = %d, statuses %v, want the stale notification skipped", len(f.mail.sent), counts)
+	}
+}
@@ -0,0 +1,229 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/middleware"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// memoryPlanRepository holds the plans and at most one subscription
+type memoryPlanRepository struct {
+	repositories.IPlanRepository
+	plans        map[string]models.Plan
+	subscription *models.Subscription
+}
+
+func (r *memoryPlanRepository) GetPlan(ctx context.Context, code string) (*models.Plan, error) {
+	plan, ok := r.plans[code]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &plan, nil
+}
+
+func (r *memoryPlanRepository) GetSubscriptionByUserID(ctx context.Context, userID string) (*models.Subscription, error) {
+	if r.subscription == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.subscription, nil
+}
+
+// memoryUsageRepository sums its records the way the SQL query does. Its lock
+// stands in for the row lock ReserveRecord takes.
+type memoryUsageRepository struct {
+	mu      sync.Mutex
+	nextID  int
+	records []models.UsageRecord
+}
+
+func (r *memoryUsageRepository) CreateRecord(ctx context.Context, record *models.UsageRecord) error {
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+	r.nextID++
+	record.ID = r.nextID
+	r.records = append(r.records, *record)
+	return nil
+}
+
+func (r *memoryUsageRepository) ReserveRecord(ctx context.Context, record *models.UsageRecord, from, to time.Time, limit *int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var used int64
+	for _, existing := range r.records {
+		if existing.UserID == record.UserID && !existing.CreatedAt.Before(from) && existing.CreatedAt.Before(to) &&
+			!slices.Contains(consts.USAGE_BACKGROUND_OPERATIONS, existing.Operation) {
+			used++
+		}
+	}
+	if limit != nil && used >= *limit {
+		return false, nil
+	}
+	record.Reserved = true
+	return true, r.CreateRecord(ctx, record)
+}
+
+func (r *memoryUsageRepository) CompleteRecord(ctx context.Context, record *models.UsageRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.records {
+		if record.ID != 0 && r.records[i].ID == record.ID && r.records[i].Reserved {
+			record.CreatedAt = r.records[i].CreatedAt
+			record.Reserved = false
+			r.records[i] = *record
+			return nil
+		}
+	}
+	record.Reserved = false
+	return r.CreateRecord(ctx, record)
+}
+
+func (r *memoryUsageRepository) DeleteReservation(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = slices.DeleteFunc(r.records, func(record models.UsageRecord) bool { return record.ID == id && record.Reserved })
+	return nil
+}
+
+func (r *memoryUsageRepository) SumUsage(ctx context.Context, userID string, from, to time.Time) ([]models.OperationUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var usage []models.OperationUsage
+	for _, record := range r.records {
+		if record.UserID != userID || record.CreatedAt.Before(from) || !record.CreatedAt.Before(to) {
+			continue
+		}
+		usage = append(usage, models.OperationUsage{Operation: record.Operation, Count: 1, InputTokens: int64(record.InputTokens), OutputTokens: int64(record.OutputTokens)})
+	}
+	return usage, nil
+}
+
+type countingCourseRepository struct {
+	repositories.ICourseRepository
+	count int64
+}
+
+func (r *countingCourseRepository) CountCourses(ctx context.Context, userID string) (int64, error) {
+	return r.count, nil
+}
+
+func TestQuotaMetersAIOperationsPerBillingPeriod(t *testing.T) {
+	global.Log = zap.NewNop()
+	gin.SetMode(gin.TestMode)
+
+	two, three := 2, 3
+	plans := &memoryPlanRepository{plans: map[string]models.Plan{
+		"free":    {Code: "free", MaxCourses: &three, AIOperations: &two},
+		"student": {Code: "student"},
+	}}
+	user := &models.User{UserID: "u1", Tier: consts.UserTier.FREE}
+	usage := &memoryUsageRepository{}
+	courses := &countingCourseRepository{count: 2}
+	quotas := services.NewQuotaService(plans, usage, &memoryUserRepository{user: user}, courses, nil)
+
+	engine := gin.New()
+	engine.POST("/ai", func(ctx *gin.Context) { ctx.Set(consts.UserIDContextKey, "u1") }, middleware.NewQuotaMiddleware(quotas).AIOperation(), func(ctx *gin.Context) {
+		response.SuccessResponse(ctx, response.CodeSuccess, nil)
+	})
+	call := func() string {
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/ai", nil))
+		return recorder.Body.String()
+	}
+
+	// Last month's operation does not count
+	usage.CreateRecord(context.Background(), &models.UsageRecord{UserID: "u1", Operation: "note.summarize", CreatedAt: time.Now().AddDate(0, -1, -1)})
+	usage.CreateRecord(context.Background(), &models.UsageRecord{UserID: "u1", Operation: "note.summarize", InputTokens: 100})
+	if body := call(); !strings.Contains(body, `"code":20000`) {
+		t.Fatalf("first call = %s", body)
+	}
+	usage.CreateRecord(context.Background(), &models.UsageRecord{UserID: "u1", Operation: "quiz.generate"})
+	if body := call(); !strings.Contains(body, `"code":72001`) {
+		t.Fatalf("over quota = %s", body)
+	}
+
+	if code := quotas.CheckCourses(context.Background(), "u1", 1); code != response.CodeSuccess {
+		t.Fatalf("third course: code = %d", code)
+	}
+	if code := quotas.CheckCourses(context.Background(), "u1", 2); code != response.CodeCourseLimitReached {
+		t.Fatalf("fourth course: code = %d", code)
+	}
+
+	// A subscriber is metered over the subscription period and has no limits
+	user.Tier = consts.UserTier.STUDENT
+	plans.subscription = &models.Subscription{UserID: "u1", PlanCode: "student", CurrentPeriodStart: time.Now().Add(-time.Hour), CurrentPeriodEnd: time.Now().AddDate(0, 1, 0)}
+	if code := quotas.CheckAIOperation(context.Background(), "u1"); code != response.CodeSuccess {
+		t.Fatalf("student: code = %d", code)
+	}
+	if code := quotas.CheckCourses(context.Background(), "u1", 50); code != response.CodeSuccess {
+		t.Fatalf("student courses: code = %d", code)
+	}
+}
+
+func TestQuotaReservationsHoldUnderConcurrentRequests(t *testing.T) {
+	global.Log = zap.NewNop()
+
+	three := 3
+	plans := &memoryPlanRepository{plans: map[string]models.Plan{"free": {Code: "free", AIOperations: &three}}}
+	usage := &memoryUsageRepository{}
+	quotas := services.NewQuotaService(plans, usage, &memoryUserRepository{user: &models.User{UserID: "u1", Tier: consts.UserTier.FREE}}, nil, nil)
+	ctx := context.Background()
+
+	// Indexing runs in the background and only counts towards tokens
+	usage.CreateRecord(ctx, &models.UsageRecord{UserID: "u1", Operation: consts.UsageOperation.EMBEDDING_INDEX, InputTokens: 50})
+
+	var wg sync.WaitGroup
+	codes := make([]int, 20)
+	reservations := make([]int, len(codes))
+	for i := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reservations[i], codes[i] = quotas.ReserveAIOperation(ctx, "u1", consts.UsageOperation.NOTE_SUMMARY)
+		}()
+	}
+	wg.Wait()
+
+	var granted []int
+	for i, code := range codes {
+		switch code {
+		case response.CodeSuccess:
+			granted = append(granted, reservations[i])
+		case response.CodeAIQuotaExceeded:
+		default:
+			t.Fatalf("reservation %d: code = %d", i, code)
+		}
+	}
+	if len(granted) != three {
+		t.Fatalf("%d concurrent reservations granted, want %d", len(granted), three)
+	}
+
+	// A failed operation gives its reservation back
+	quotas.ReleaseAIOperation(ctx, granted[0])
+	if _, code := quotas.ReserveAIOperation(ctx, "u1", consts.UsageOperation.QUIZ_GENERATE); code != response.CodeSuccess {
+		t.Fatalf("reservation after a release: code = %d", code)
+	}
+	if _, code := quotas.ReserveAIOperation(ctx, "u1", consts.UsageOperation.QUIZ_GENERATE); code != response.CodeAIQuotaExceeded {
+		t.Fatalf("reservation over the limit: code = %d", code)
+	}
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/pkg/planner"
+)
+
+func everyEvening() []planner.Window {
+	var windows []planner.Window
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		windows = append(windows, planner.Window{Weekday: weekday, Start: 18 * 60, End: 22 * 60})
+	}
+	return windows
+}
+
+func TestStudyDemandWeighsDifficultyAndGrade(t *testing.T) {
+	base := planner.Deadline{Exam: true, Difficulty: 3, Credits: 3, Grade: 3}
+	hard := base
+	hard.Difficulty = 5
+	struggling := base
+	struggling.Grade = 1.5
+
+	if planner.Demand(hard, 50) <= planner.Demand(base, 50) {
+		t.Errorf("harder course should need more study")
+	}
+	if planner.Demand(struggling, 50) <= planner.Demand(base, 50) {
+		t.Errorf("lower grade should need more study")
+	}
+	if planner.Demand(base, 50)%50 != 0 {
+		t.Errorf("demand %d is not whole blocks", planner.Demand(base, 50))
+	}
+}
+
+func TestStudyPlanAvoidsClassesAndSpreadsStudy(t *testing.T) {
+	// 2026-09-07 is a Monday
+	from := mustDate(t, "2026-09-07").Add(12 * time.Hour)
+	exam := planner.Deadline{ID: 1, CourseID: 10, Due: mustDate(t, "2026-09-14").Add(9 * time.Hour), Exam: true, Difficulty: 3}
+	class := planner.Interval{Start: mustDate(t, "2026-09-08").Add(18 * time.Hour), End: mustDate(t, "2026-09-08").Add(20 * time.Hour)}
+	opts := planner.Options{BlockMinutes: 50, BreakMinutes: 10, MaxDailyMinutes: 240}
+
+	schedule := planner.Plan(from, []planner.Deadline{exam}, everyEvening(), []planner.Interval{class}, opts)
+	if len(schedule.Shortfalls) != 0 {
+		t.Fatalf("unexpected shortfalls %+v", schedule.Shortfalls)
+	}
+
+	perDay := map[string]int{}
+	minutes := 0
+	for _, block := range schedule.Blocks {
+		if planner.Overlaps(planner.Interval{Start: block.Start, End: block.End}, class) {
+			t.Errorf("block %s overlaps the class", block.Start)
+		}
+		if block.End.After(exam.Due) {
+			t.Errorf("block %s ends after the exam", block.Start)
+		}
+		perDay[block.Start.Format(time.DateOnly)]++
+		minutes += int(block.End.Sub(block.Start).Minutes())
+	}
+	if minutes != planner.Demand(exam, 50) {
+		t.Errorf("scheduled %d minutes, want %d", minutes, planner.Demand(exam, 50))
+	}
+	for day, blocks := range perDay {
+		if blocks > planner.MaxBlocksPerDay {
+			t.Errorf("%d blocks on %s, want at most %d", blocks, day, planner.MaxBlocksPerDay)
+		}
+	}
+}
+
+func TestStudyPlanReportsShortfall(t *testing.T) {
+	from := mustDate(t, "2026-09-07").Add(12 * time.Hour)
+	exam := planner.Deadline{ID: 1, CourseID: 10, Due: mustDate(t, "2026-09-08").Add(9 * time.Hour), Exam: true}
+	opts := planner.Options{BlockMinutes: 50, BreakMinutes: 10}
+
+	schedule := planner.Plan(from, []planner.Deadline{exam}, everyEvening(), nil, opts)
+	if len(schedule.Blocks) != planner.MaxBlocksPerDay {
+		t.Fatalf("got %d blocks, want %d", len(schedule.Blocks), planner.MaxBlocksPerDay)
+	}
+	if len(schedule.Shortfalls) != 1 || schedule.Shortfalls[0].Minutes != planner.Demand(exam, 50)-2*50 {
+		t.Errorf("shortfalls = %+v", schedule.Shortfalls)
+	}
+}
@@ -0,0 +1,270 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/queue"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type greetPayload struct {
+	Name string `json:"name"`
+}
+
+// newTestQueue starts a miniredis server and a queue client with a controllable clock
+func newTestQueue(t *testing.T) (*queue.Client, *time.Time) {
+	t.Helper()
+	global.Log = zap.NewNop()
+
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	now := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	client := queue.NewClient(rdb, queue.Options{
+		VisibilityTimeout: time.Minute,
+		BackoffBase:       10 * time.Second,
+		BackoffMax:        time.Hour,
+	})
+	client.SetClock(func() time.Time { return now })
+	return client, &now
+}
+
+func TestQueueTypedHandlerAck(t *testing.T) {
+	client, _ := newTestQueue(t)
+	ctx := context.Background()
+
+	var got string
+	mux := queue.NewMux()
+	queue.Register(mux, "greet", func(ctx context.Context, job *queue.Job, payload greetPayload) error {
+		got = payload.Name
+		return nil
+	})
+
+	if _, err := client.Enqueue(ctx, "greet", greetPayload{Name: "ada"}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	worker := queue.NewWorker(client, mux, queue.DefaultQueue, 1, time.Millisecond)
+	processed, err := worker.ProcessOne(ctx)
+	if err != nil || !processed {
+		t.Fatalf("expected a processed job, got processed=%v err=%v", processed, err)
+	}
+	if got != "ada" {
+		t.Errorf("handler received %q, want %q", got, "ada")
+	}
+
+	stats, _ := client.Stats(ctx, queue.DefaultQueue)
+	if *stats != (queue.Stats{}) {
+		t.Errorf("queue should be empty after ack, got %+v", *stats)
+	}
+}
+
+func TestQueueDelayedJob(t *testing.T) {
+	client, now := newTestQueue(t)
+	ctx := context.Background()
+
+	if _, err := client.Enqueue(ctx, "greet", greetPayload{}, queue.WithDelay(30*time.Second)); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	if job, _ := client.Dequeue(ctx, queue.DefaultQueue); job != nil {
+		t.Fatal("delayed job was delivered too early")
+	}
+
+	*now = now.Add(31 * time.Second)
+	job, err := client.Dequeue(ctx, queue.DefaultQueue)
+	if err != nil || job == nil {
+		t.Fatalf("expected delayed job after delay, got job=%v err=%v", job, err)
+	}
+	if job.Attempts != 1 {
+		t.Errorf("attempts = %d, want 1", job.Attempts)
+	}
+}
+
+func TestQueueRetryThenDeadLetter(t *testing.T) {
+	client, now := newTestQueue(t)
+	ctx := context.Background()
+
+	mux := queue.NewMux()
+	mux.Handle("flaky", func(ctx context.Context, job *queue.Job) error {
+		return errors.New("boom")
+	})
+	worker := queue.NewWorker(client, mux, queue.DefaultQueue, 1, time.Millisecond)
+
+	if _, err := client.Enqueue(ctx, "flaky", nil, queue.WithMaxAttempts(2)); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	// First attempt fails and is scheduled after the 10s backoff
+	if processed, _ := worker.ProcessOne(ctx); !processed {
+		t.Fatal("expected first attempt to run")
+	}
+	stats, _ := client.Stats(ctx, queue.DefaultQueue)
+	if stats.Delayed != 1 {
+		t.Fatalf("expected job to be delayed for retry, got %+v", *stats)
+	}
+	if processed, _ := worker.ProcessOne(ctx); processed {
+		t.Fatal("retry ran before its backoff elapsed")
+	}
+
+	// Second attempt exhausts the budget and lands in the dead-letter list
+	*now = now.Add(11 * time.Second)
+	if processed, _ := worker.ProcessOne(ctx); !processed {
+		t.Fatal("expected retry to run after backoff")
+	}
+	dead, err := client.DeadJobs(ctx, queue.DefaultQueue, 10)
+	if err != nil || len(dead) != 1 {
+		t.Fatalf("expected one dead job, got %d (err=%v)", len(dead), err)
+	}
+	if dead[0].LastError != "boom" || dead[0].Attempts != 2 {
+		t.Errorf("unexpected dead job %+v", dead[0])
+	}
+
+	// Retrying a dead job makes it ready again with a fresh budget
+	if err := client.RetryDead(ctx, queue.DefaultQueue, dead[0].ID); err != nil {
+		t.Fatalf("retry dead: %v", err)
+	}
+	job, _ := client.Dequeue(ctx, queue.DefaultQueue)
+	if job == nil || job.Attempts != 1 {
+		t.Fatalf("expected revived job with attempts=1, got %+v", job)
+	}
+}
+
+func TestQueueVisibilityTimeoutRedelivers(t *testing.T) {
+	client, now := newTestQueue(t)
+	ctx := context.Background()
+
+	if _, err := client.Enqueue(ctx, "greet", greetPayload{}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	// Simulate a worker that dequeues and then crashes without ack
+	first, _ := client.Dequeue(ctx, queue.DefaultQueue)
+	if first == nil {
+		t.Fatal("expected a job")
+	}
+	if again, _ := client.Dequeue(ctx, queue.DefaultQueue); again != nil {
+		t.Fatal("in-flight job was delivered twice")
+	}
+
+	*now = now.Add(2 * time.Minute)
+	redelivered, _ := client.Dequeue(ctx, queue.DefaultQueue)
+	if redelivered == nil || redelivered.ID != first.ID {
+		t.Fatalf("expected job %s to be redelivered, got %+v", first.ID, redelivered)
+	}
+	if redelivered.Attempts != 2 {
+		t.Errorf("attempts = %d, want 2", redelivered.Attempts)
+	}
+}
+
+func TestQueueExpiredLeasesDeadLetterAfterMaxAttempts(t *testing.T) {
+	client, now := newTestQueue(t)
+	ctx := context.Background()
+
+	job, err := client.Enqueue(ctx, "greet", greetPayload{}, queue.WithMaxAttempts(3))
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	// Every delivery crashes its worker and lets the lease expire
+	for attempt := 1; attempt <= 3; attempt++ {
+		delivered, err := client.Dequeue(ctx, queue.DefaultQueue)
+		if err != nil || delivered == nil || delivered.ID != job.ID {
+			t.Fatalf("delivery %d = %+v, %v", attempt, delivered, err)
+		}
+		if delivered.Attempts != attempt {
+			t.Errorf("delivery %d: attempts = %d", attempt, delivered.Attempts)
+		}
+		*now = now.Add(2 * time.Minute)
+	}
+
+	if again, err := client.Dequeue(ctx, queue.DefaultQueue); err != nil || again != nil {
+		t.Fatalf("job redelivered after its last attempt expired: %+v, %v", again, err)
+	}
+	stats, _ := client.Stats(ctx, queue.DefaultQueue)
+	if stats.Ready != 0 || stats.InFlight != 0 || stats.Dead != 1 {
+		t.Errorf("stats = %+v, want the job dead-lettered", stats)
+	}
+	dead, _ := client.DeadJobs(ctx, queue.DefaultQueue, 10)
+	if len(dead) != 1 || dead[0].ID != job.ID || dead[0].LastError == "" {
+		t.Errorf("dead jobs = %+v, want job %s with an error", dead, job.ID)
+	}
+}
+
+func TestQueueUnknownTypeIsDeadLettered(t *testing.T) {
+	client, _ := newTestQueue(t)
+	ctx := context.Background()
+
+	if _, err := client.Enqueue(ctx, "missing", nil); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	worker := queue.NewWorker(client, queue.NewMux(), queue.DefaultQueue, 1, time.Millisecond)
+	if processed, _ := worker.ProcessOne(ctx); !processed {
+		t.Fatal("expected job to be processed")
+	}
+
+	stats, _ := client.Stats(ctx, queue.DefaultQueue)
+	if stats.Dead != 1 || stats.Delayed != 0 {
+		t.Errorf("unknown job type should be dead-lettered without retry, got %+v", *stats)
+	}
+}
+
+func TestQueueLateWorkerCannotFinishRedeliveredJob(t *testing.T) {
+	global.Log = zap.NewNop()
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	now := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	client := queue.NewClient(rdb, queue.Options{VisibilityTimeout: time.Minute})
+	client.SetClock(func() time.Time { return now })
+	ctx := context.Background()
+
+	if _, err := client.Enqueue(ctx, "greet", greetPayload{}, queue.OnQueue("mail")); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	slow, _ := client.Dequeue(ctx, "mail")
+	now = now.Add(2 * time.Minute)
+	current, _ := client.Dequeue(ctx, "mail")
+	if slow == nil || current == nil || current.ID != slow.ID {
+		t.Fatalf("expected the job to be redelivered, got %+v and %+v", slow, current)
+	}
+
+	// The first worker lost its lease and must not touch the new delivery
+	if err := client.Ack(ctx, slow); !errors.Is(err, queue.ErrLeaseLost) {
+		t.Errorf("late ack: err = %v, want ErrLeaseLost", err)
+	}
+	if _, err := client.Fail(ctx, slow, errors.New("boom")); !errors.Is(err, queue.ErrLeaseLost) {
+		t.Errorf("late fail: err = %v, want ErrLeaseLost", err)
+	}
+	if err := client.Extend(ctx, slow, time.Hour); !errors.Is(err, queue.ErrLeaseLost) {
+		t.Errorf("late extend: err = %v, want ErrLeaseLost", err)
+	}
+	stats, _ := client.Stats(ctx, "mail")
+	if stats.InFlight != 1 || stats.Delayed != 0 || stats.Dead != 0 {
+		t.Fatalf("late worker changed the queue: %+v", *stats)
+	}
+
+	// All keys of a queue share its hash tag, so its scripts run on one cluster slot
+	for _, key := range server.Keys() {
+		if !strings.HasPrefix(key, "queue:{mail}:") {
+			t.Errorf("key %q is outside the queue's hash slot", key)
+		}
+	}
+
+	if err := client.Ack(ctx, current); err != nil {
+		t.Fatalf("ack: %v", err)
+	}
+	if stats, _ := client.Stats(ctx, "mail"); *stats != (queue.Stats{}) {
+		t.Errorf("queue should be empty after ack, got %+v", *stats)
+	}
+}
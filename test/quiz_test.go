@@ -0,0 +1,212 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/queue"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/ai"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// memoryQuizRepository keeps one quiz with its questions, attempt and answers
+type memoryQuizRepository struct {
+	repositories.IQuizRepository
+	quiz      *models.Quiz
+	questions []models.QuizQuestion
+	attempt   *models.QuizAttempt
+	answers   []models.QuizAnswer
+	jobs      []string
+}
+
+func (r *memoryQuizRepository) GetQuizByID(ctx context.Context, id int, userID string) (*models.Quiz, error) {
+	quiz := *r.quiz
+	return &quiz, nil
+}
+
+func (r *memoryQuizRepository) UpdateQuiz(ctx context.Context, id int, updates map[string]any) error {
+	if status, ok := updates["status"]; ok {
+		r.quiz.Status = status.(int8)
+	}
+	return nil
+}
+
+func (r *memoryQuizRepository) CreateQuestions(ctx context.Context, questions []models.QuizQuestion) error {
+	for i := range questions {
+		questions[i].ID = len(r.questions) + 1
+		r.questions = append(r.questions, questions[i])
+	}
+	return nil
+}
+
+func (r *memoryQuizRepository) ListQuestions(ctx context.Context, quizID int) ([]models.QuizQuestion, error) {
+	return r.questions, nil
+}
+
+func (r *memoryQuizRepository) GetAttemptByID(ctx context.Context, id int, userID string) (*models.QuizAttempt, error) {
+	if r.attempt == nil || r.attempt.ID != id {
+		return nil, gorm.ErrRecordNotFound
+	}
+	attempt := *r.attempt
+	return &attempt, nil
+}
+
+func (r *memoryQuizRepository) UpdateAttempt(ctx context.Context, id int, updates map[string]any) error {
+	if status, ok := updates["status"]; ok {
+		r.attempt.Status = status.(int8)
+	}
+	if score, ok := updates["score"]; ok {
+		r.attempt.Score = score.(float64)
+	}
+	return nil
+}
+
+func (r *memoryQuizRepository) CreateAnswers(ctx context.Context, answers []models.QuizAnswer) error {
+	for i := range answers {
+		answers[i].ID = len(r.answers) + 1
+		r.answers = append(r.answers, answers[i])
+	}
+	return nil
+}
+
+func (r *memoryQuizRepository) ListAnswers(ctx context.Context, attemptID int) ([]models.QuizAnswer, error) {
+	return append([]models.QuizAnswer(nil), r.answers...), nil
+}
+
+func (r *memoryQuizRepository) UpdateAnswer(ctx context.Context, id int, updates map[string]any) error {
+	answer := &r.answers[id-1]
+	answer.Feedback = updates["feedback"].(string)
+	answer.GradedBy = updates["graded_by"].(string)
+	return nil
+}
+
+func (r *memoryQuizRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return fn(nil)
+}
+
+func (r *memoryQuizRepository) WithTx(tx *gorm.DB) repositories.IQuizRepository {
+	return r
+}
+
+// Enqueue records jobs so the repository doubles as the service's queue
+func (r *memoryQuizRepository) Enqueue(ctx context.Context, jobType string, payload any, opts ...queue.EnqueueOption) (*queue.Job, error) {
+	r.jobs = append(r.jobs, jobType)
+	return &queue.Job{Type: jobType}, nil
+}
+
+func TestGenerateQuizRejectsMalformedQuestions(t *testing.T) {
+	global.Log = zap.NewNop()
+
+	quizzes := &memoryQuizRepository{
+		quiz: &models.Quiz{ID: 4, UserID: "u1", CourseID: 2, Difficulty: "hard", Types: "mcq,true_false", Language: "en", Count: 2},
+	}
+	notes := &memoryNoteRepository{note: &models.Note{ID: 7, UserID: "u1", CourseID: 2, ContentText: "Quicksort partitions around a pivot."}}
+	lister := &listingNoteRepository{memoryNoteRepository: notes}
+
+	fake := ai.NewFakeProvider()
+	fake.Replies = []string{
+		// The answer index points past the options
+		`{"questions": [{"type": "mcq", "prompt": "Quicksort picks a?", "options": ["pivot", "heap"], "answer_index": 2}]}`,
+		`{"questions": [
+			{"type": "mcq", "prompt": "Quicksort picks a?", "options": ["pivot", "heap"], "answer_index": 0, "explanation": "It partitions around it.", "chunk": 0},
+			{"type": "true_false", "prompt": "Quicksort is stable.", "answer": false, "chunk": 0}
+		]}`,
+	}
+
+	service := services.NewQuizGenerationService(quizzes, lister, fake, nil, nil)
+	if err := service.GenerateQuiz(context.Background(), models.QuizGeneratePayload{QuizID: 4, UserID: "u1"}); err != nil {
+		t.Fatalf("GenerateQuiz: %v", err)
+	}
+	if quizzes.quiz.Status != consts.QuizStatus.DONE || len(quizzes.questions) != 2 {
+		t.Fatalf("status = %d, questions = %+v", quizzes.quiz.Status, quizzes.questions)
+	}
+	if retried := fake.Requests()[1].Messages; len(retried) != 3 || !strings.Contains(retried[2].Content, "question 1") {
+		t.Fatalf("repair prompt should name the bad question: %+v", retried)
+	}
+	first := quizzes.questions[0]
+	if first.Position != 1 || first.Difficulty != "hard" || first.NoteID == nil || *first.NoteID != 7 || *first.AnswerIndex != 0 {
+		t.Fatalf("first question = %+v", first)
+	}
+}
+
+// listingNoteRepository serves its single note from ListNotes as well
+type listingNoteRepository struct {
+	*memoryNoteRepository
+}
+
+func (r *listingNoteRepository) ListNotes(ctx context.Context, filter models.NoteFilter) ([]models.Note, error) {
+	return []models.Note{*r.note}, nil
+}
+
+func TestSubmitQuizAttemptGradesAutomaticallyAndByRubric(t *testing.T) {
+	global.Log = zap.NewNop()
+
+	answerIndex, answerBool := 1, true
+	rubric, _ := json.Marshal([]models.RubricCriterion{{Criterion: "Mentions the pivot", Points: 2}, {Criterion: "Explains partitioning", Points: 2}})
+	quizzes := &memoryQuizRepository{
+		quiz: &models.Quiz{ID: 4, UserID: "u1", Language: "en", Status: consts.QuizStatus.DONE},
+		questions: []models.QuizQuestion{
+			{ID: 1, Type: consts.QuizQuestionType.MCQ, Options: json.RawMessage(`["a","b"]`), AnswerIndex: &answerIndex},
+			{ID: 2, Type: consts.QuizQuestionType.TRUE_FALSE, AnswerBool: &answerBool},
+			{ID: 3, Type: consts.QuizQuestionType.SHORT_ANSWER, Prompt: "How does quicksort work?", ReferenceAnswer: "Partition around a pivot", Rubric: rubric},
+		},
+		attempt: &models.QuizAttempt{ID: 9, QuizID: 4, UserID: "u1"},
+	}
+
+	one := 1
+	plans := &memoryPlanRepository{plans: map[string]models.Plan{"free": {Code: "free", AIOperations: &one}}}
+	usage := &memoryUsageRepository{}
+	quotas := services.NewQuotaService(plans, usage, &memoryUserRepository{user: &models.User{UserID: "u1", Tier: consts.UserTier.FREE}}, nil, nil)
+
+	service := services.NewQuizService(quizzes, nil, nil, quotas, quizzes)
+	result, code := service.SubmitAttempt(context.Background(), "u1", 4, 9, &models.SubmitQuizAttemptRequest{Answers: []models.QuizAnswerInput{
+		{QuestionID: 1, Response: "1"},
+		{QuestionID: 2, Response: "False"},
+		{QuestionID: 3, Response: "It picks a pivot and recurses."},
+	}})
+	if code != response.CodeSuccess {
+		t.Fatalf("code = %d", code)
+	}
+	if result.Status != consts.QuizAttemptStatus.GRADING || result.Score != 1 || len(quizzes.jobs) != 1 {
+		t.Fatalf("attempt = %+v, jobs = %v", result.QuizAttempt, quizzes.jobs)
+	}
+	if result.Items[0].Solution != nil || quizzes.answers[1].Response != "false" {
+		t.Fatalf("solutions must stay hidden until graded; answers = %+v", quizzes.answers)
+	}
+	// AI grading takes the plan's only operation while it runs
+	if len(usage.records) != 1 || !usage.records[0].Reserved || usage.records[0].Operation != consts.UsageOperation.QUIZ_GRADE {
+		t.Fatalf("usage after submit = %+v", usage.records)
+	}
+	if code := quotas.CheckAIOperation(context.Background(), "u1"); code != response.CodeAIQuotaExceeded {
+		t.Fatalf("quota while grading: code = %d", code)
+	}
+
+	if _, code := service.SubmitAttempt(context.Background(), "u1", 4, 9, &models.SubmitQuizAttemptRequest{}); code != response.CodeQuizAttemptSubmitted {
+		t.Fatalf("second submit: code = %d", code)
+	}
+
+	fake := ai.NewFakeProvider()
+	fake.Replies = []string{`{"criteria": [{"criterion": "Mentions the pivot", "awarded": 2}, {"criterion": "Explains partitioning", "awarded": 1}], "feedback": "Say how partitioning works."}`}
+	grader := services.NewQuizGradingService(quizzes, fake, usage)
+	if err := grader.GradeAttempt(context.Background(), models.QuizGradePayload{AttemptID: 9, UserID: "u1", ReservationID: usage.records[0].ID}); err != nil {
+		t.Fatalf("GradeAttempt: %v", err)
+	}
+	if quizzes.attempt.Status != consts.QuizAttemptStatus.GRADED || quizzes.attempt.Score != 1.75 {
+		t.Fatalf("attempt = %+v", quizzes.attempt)
+	}
+	if quizzes.answers[2].GradedBy != consts.QuizGrader.AI {
+		t.Fatalf("short answer = %+v", quizzes.answers[2])
+	}
+	if len(usage.records) != 1 || usage.records[0].Reserved || usage.records[0].Reference != "quiz_attempt:9" || usage.records[0].InputTokens == 0 {
+		t.Fatalf("usage after grading = %+v", usage.records)
+	}
+}
@@ -0,0 +1,375 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// memoryReminderRepository keeps the reminders of a single user whose timezone is loc
+type memoryReminderRepository struct {
+	repositories.IReminderRepository
+	mu        sync.Mutex
+	loc       *time.Location
+	nextID    int
+	reminders map[int]*models.Reminder
+}
+
+func newMemoryReminderRepository(loc *time.Location) *memoryReminderRepository {
+	return &memoryReminderRepository{loc: loc, reminders: map[int]*models.Reminder{}}
+}
+
+func (r *memoryReminderRepository) CreateReminder(ctx context.Context, reminder *models.Reminder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	reminder.ID = r.nextID
+	stored := *reminder
+	r.reminders[reminder.ID] = &stored
+	return nil
+}
+
+func (r *memoryReminderRepository) GetReminderByID(ctx context.Context, id int, userID string) (*models.Reminder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reminder, ok := r.reminders[id]
+	if !ok || reminder.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *reminder
+	return &found, nil
+}
+
+func (r *memoryReminderRepository) ListPendingRemindersDueBetween(ctx context.Context, from, to time.Time) ([]models.Reminder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var reminders []models.Reminder
+	for _, reminder := range r.reminders {
+		deadline := reminder.Deadline(r.loc)
+		if reminder.Status == consts.ReminderStatus.PENDING && deadline.After(from) && !deadline.After(to) {
+			reminders = append(reminders, *reminder)
+		}
+	}
+	return reminders, nil
+}
+
+func (r *memoryReminderRepository) MarkOverdueReminders(ctx context.Context, userID string, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var marked int64
+	for _, reminder := range r.reminders {
+		if reminder.Status == consts.ReminderStatus.PENDING && reminder.Deadline(r.loc).Before(now) {
+			reminder.Status = consts.ReminderStatus.OVERDUE
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+func (r *memoryReminderRepository) RefreshReminderStatuses(ctx context.Context, userID string, loc *time.Location, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loc = loc
+	for _, reminder := range r.reminders {
+		overdue := reminder.Deadline(loc).Before(now)
+		switch {
+		case reminder.Status == consts.ReminderStatus.PENDING && overdue:
+			reminder.Status = consts.ReminderStatus.OVERDUE
+		case reminder.Status == consts.ReminderStatus.OVERDUE && !overdue:
+			reminder.Status = consts.ReminderStatus.PENDING
+		}
+	}
+	return nil
+}
+
+func (r *memoryReminderRepository) WithTx(tx *gorm.DB) repositories.IReminderRepository {
+	return r
+}
+
+func (r *memoryReminderRepository) ListReminders(ctx context.Context, filter models.ReminderFilter) ([]models.Reminder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var reminders []models.Reminder
+	for id := 1; id <= r.nextID; id++ {
+		reminder, ok := r.reminders[id]
+		if !ok || reminder.UserID != filter.UserID || (filter.Status != nil && reminder.Status != *filter.Status) || (filter.Type != nil && reminder.Type != *filter.Type) {
+			continue
+		}
+		reminders = append(reminders, *reminder)
+	}
+	return reminders, nil
+}
+
+func (r *memoryReminderRepository) UpdateReminder(ctx context.Context, id int, userID string, updates map[string]any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reminder, ok := r.reminders[id]
+	if !ok || reminder.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	for column, value := range updates {
+		switch column {
+		case "title":
+			reminder.Title = value.(string)
+		case "description":
+			reminder.Description = value.(string)
+		case "course_id":
+			courseID := value.(int)
+			reminder.CourseID = &courseID
+		case "type":
+			reminder.Type = value.(int8)
+		case "status":
+			reminder.Status = value.(int8)
+		case "due_date":
+			reminder.DueDate = value.(time.Time)
+		case "due_time":
+			reminder.DueTime = value.(string)
+		case "location":
+			reminder.Location = value.(sql.NullString)
+		case "weight":
+			reminder.Weight = value.(sql.NullFloat64)
+		case "score":
+			reminder.Score = value.(sql.NullFloat64)
+		case "completed_at":
+			reminder.CompletedAt = value.(sql.NullTime)
+		}
+	}
+	return nil
+}
+
+func (r *memoryReminderRepository) DeleteReminder(ctx context.Context, id int, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reminder, ok := r.reminders[id]
+	if !ok || reminder.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.reminders, id)
+	return nil
+}
+
+func newReminderService(t *testing.T, timezone string) (services.IReminderService, *memoryReminderRepository) {
+	t.Helper()
+	global.Log = zap.NewNop()
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		t.Skipf("zoneinfo unavailable: %v", err)
+	}
+	reminderRepo := newMemoryReminderRepository(loc)
+	userRepo := &memoryUserRepository{user: &models.User{UserID: "user-1", Timezone: timezone}}
+	courseRepo := &gradedCourseRepository{course: models.Course{ID: 4, UserID: "user-1"}}
+	return services.NewReminderService(reminderRepo, courseRepo, newMemoryNotificationRepository(), userRepo), reminderRepo
+}
+
+func TestReminderDeadlineUsesUserTimezone(t *testing.T) {
+	// Kiritimati is UTC+14, so an hour from now on the UTC clock passed hours ago there
+	service, _ := newReminderService(t, "Pacific/Kiritimati")
+	due := time.Now().UTC().Add(time.Hour)
+
+	reminder, code := service.CreateReminder(context.Background(), "user-1", &models.CreateReminderRequest{
+		Title:   "Essay",
+		DueDate: due.Format(consts.DATE_LAYOUT),
+		DueTime: due.Format(consts.CLOCK_LAYOUT),
+		Type:    consts.ReminderType.ASSIGNMENT,
+	})
+	if code != response.CodeSuccess {
+		t.Fatalf("CreateReminder code = %d", code)
+	}
+	if reminder.Status != consts.ReminderStatus.OVERDUE {
+		t.Errorf("status = %d, want overdue", reminder.Status)
+	}
+
+	// The same wall clock west of UTC is still ahead
+	service, _ = newReminderService(t, "America/Los_Angeles")
+	reminder, code = service.CreateReminder(context.Background(), "user-1", &models.CreateReminderRequest{
+		Title:   "Essay",
+		DueDate: due.Format(consts.DATE_LAYOUT),
+		DueTime: due.Format(consts.CLOCK_LAYOUT),
+		Type:    consts.ReminderType.ASSIGNMENT,
+	})
+	if code != response.CodeSuccess {
+		t.Fatalf("CreateReminder code = %d", code)
+	}
+	if reminder.Status != consts.ReminderStatus.PENDING {
+		t.Errorf("status = %d, want pending", reminder.Status)
+	}
+}
+
+func TestReminderCRUD(t *testing.T) {
+	service, _ := newReminderService(t, "UTC")
+	ctx := context.Background()
+	due := time.Now().UTC().AddDate(0, 0, 7)
+	courseID := 4
+
+	reminder, code := service.CreateReminder(ctx, "user-1", &models.CreateReminderRequest{
+		Title:    "  Problem set 3 ",
+		DueDate:  due.Format(consts.DATE_LAYOUT),
+		DueTime:  "23:59",
+		CourseID: &courseID,
+		Type:     consts.ReminderType.ASSIGNMENT,
+	})
+	if code != response.CodeSuccess {
+		t.Fatalf("CreateReminder code = %d", code)
+	}
+	if reminder.Title != "Problem set 3" || reminder.DueTime != "23:59:00" || reminder.Status != consts.ReminderStatus.PENDING {
+		t.Errorf("created %q due %s with status %d", reminder.Title, reminder.DueTime, reminder.Status)
+	}
+
+	// Invalid input is refused before anything is stored
+	weight := 40.0
+	otherCourse := 5
+	for name, req := range map[string]*models.CreateReminderRequest{
+		"bad date":         {Title: "x", DueDate: "2026-02-30", DueTime: "10:00"},
+		"exam fields":      {Title: "x", DueDate: "2026-12-01", DueTime: "10:00", Type: consts.ReminderType.ASSIGNMENT, Weight: &weight},
+		"someone's course": {Title: "x", DueDate: "2026-12-01", DueTime: "10:00", CourseID: &otherCourse},
+	} {
+		if _, code := service.CreateReminder(ctx, "user-1", req); code == response.CodeSuccess {
+			t.Errorf("%s: CreateReminder succeeded", name)
+		}
+	}
+
+	if got, code := service.GetReminder(ctx, "user-1", reminder.ID); code != response.CodeSuccess || got.Title != "Problem set 3" {
+		t.Fatalf("GetReminder: code %d", code)
+	}
+	if _, code := service.GetReminder(ctx, "user-2", reminder.ID); code != response.CodeReminderNotFound {
+		t.Errorf("GetReminder as another user: code = %d, want %d", code, response.CodeReminderNotFound)
+	}
+
+	// Turning it into an exam keeps the exam details; turning it back drops them
+	exam := consts.ReminderType.EXAM
+	location := "Hall B"
+	updated, code := service.UpdateReminder(ctx, "user-1", reminder.ID, &models.UpdateReminderRequest{Type: &exam, Location: &location, Weight: &weight})
+	if code != response.CodeSuccess || updated.Location.String != location || updated.Weight.Float64 != weight {
+		t.Fatalf("UpdateReminder to exam: code %d, location %v, weight %v", code, updated.Location, updated.Weight)
+	}
+	assignment := consts.ReminderType.ASSIGNMENT
+	updated, code = service.UpdateReminder(ctx, "user-1", reminder.ID, &models.UpdateReminderRequest{Type: &assignment})
+	if code != response.CodeSuccess || updated.Location.Valid || updated.Weight.Valid {
+		t.Errorf("UpdateReminder to assignment: code %d, exam details %v %v", code, updated.Location, updated.Weight)
+	}
+
+	list, code := service.ListReminders(ctx, "user-1", &models.ReminderQuery{Type: &assignment})
+	if code != response.CodeSuccess || len(list) != 1 {
+		t.Errorf("ListReminders: code %d, %d reminders", code, len(list))
+	}
+	if _, code := service.ListReminders(ctx, "user-1", &models.ReminderQuery{From: "2026-12-01", To: "2026-11-01"}); code != response.CodeInvalidParams {
+		t.Errorf("ListReminders with an inverted range: code = %d", code)
+	}
+
+	if code := service.DeleteReminder(ctx, "user-1", reminder.ID); code != response.CodeSuccess {
+		t.Fatalf("DeleteReminder code = %d", code)
+	}
+	if code := service.DeleteReminder(ctx, "user-1", reminder.ID); code != response.CodeReminderNotFound {
+		t.Errorf("second DeleteReminder: code = %d, want %d", code, response.CodeReminderNotFound)
+	}
+}
+
+func TestReminderStatusTransitions(t *testing.T) {
+	service, reminders := newReminderService(t, "UTC")
+	ctx := context.Background()
+	due := time.Now().UTC().AddDate(0, 0, 1)
+
+	reminder, code := service.CreateReminder(ctx, "user-1", &models.CreateReminderRequest{
+		Title:   "Read chapter 4",
+		DueDate: due.Format(consts.DATE_LAYOUT),
+		DueTime: due.Format(consts.CLOCK_LAYOUT),
+	})
+	if code != response.CodeSuccess {
+		t.Fatalf("CreateReminder code = %d", code)
+	}
+
+	steps := []struct {
+		name   string
+		status int8
+		code   int
+		want   int8
+	}{
+		{"reopen a pending reminder", consts.ReminderStatus.PENDING, response.CodeReminderInvalidTransition, consts.ReminderStatus.PENDING},
+		{"complete", consts.ReminderStatus.COMPLETED, response.CodeSuccess, consts.ReminderStatus.COMPLETED},
+		{"complete twice", consts.ReminderStatus.COMPLETED, response.CodeReminderInvalidTransition, consts.ReminderStatus.COMPLETED},
+		{"mark overdue by hand", consts.ReminderStatus.OVERDUE, response.CodeReminderInvalidStatus, consts.ReminderStatus.COMPLETED},
+		{"reopen", consts.ReminderStatus.PENDING, response.CodeSuccess, consts.ReminderStatus.PENDING},
+	}
+	for _, step := range steps {
+		if _, code := service.UpdateReminderStatus(ctx, "user-1", reminder.ID, step.status); code != step.code {
+			t.Errorf("%s: code = %d, want %d", step.name, code, step.code)
+		}
+		stored := reminders.reminders[reminder.ID]
+		if stored.Status != step.want {
+			t.Errorf("%s: status = %d, want %d", step.name, stored.Status, step.want)
+		}
+		if completed := stored.Status == consts.ReminderStatus.COMPLETED; stored.CompletedAt.Valid != completed {
+			t.Errorf("%s: completed_at set = %v with status %d", step.name, stored.CompletedAt.Valid, stored.Status)
+		}
+	}
+
+	// Reopening once the deadline passed lands the reminder in overdue
+	if _, code := service.UpdateReminderStatus(ctx, "user-1", reminder.ID, consts.ReminderStatus.COMPLETED); code != response.CodeSuccess {
+		t.Fatalf("complete: code = %d", code)
+	}
+	reminders.reminders[reminder.ID].DueDate = due.AddDate(0, 0, -3)
+	reopened, code := service.UpdateReminderStatus(ctx, "user-1", reminder.ID, consts.ReminderStatus.PENDING)
+	if code != response.CodeSuccess || reopened.Status != consts.ReminderStatus.OVERDUE {
+		t.Errorf("reopen past deadline: code %d, status %d, want overdue", code, reopened.Status)
+	}
+}
+
+func TestReminderOverdueMarking(t *testing.T) {
+	service, reminders := newReminderService(t, "UTC")
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	create := func(title string, due time.Time) *models.Reminder {
+		reminder, code := service.CreateReminder(ctx, "user-1", &models.CreateReminderRequest{
+			Title:   title,
+			DueDate: due.Format(consts.DATE_LAYOUT),
+			DueTime: due.Format(consts.CLOCK_LAYOUT),
+		})
+		if code != response.CodeSuccess {
+			t.Fatalf("CreateReminder %s: code = %d", title, code)
+		}
+		return reminder
+	}
+	past := create("Lab report", now.Add(-2*time.Hour))
+	passing := create("Quiz prep", now.Add(2*time.Hour))
+	done := create("Essay draft", now.Add(2*time.Hour))
+	if past.Status != consts.ReminderStatus.OVERDUE || passing.Status != consts.ReminderStatus.PENDING {
+		t.Fatalf("created with statuses %d and %d", past.Status, passing.Status)
+	}
+	if _, code := service.UpdateReminderStatus(ctx, "user-1", done.ID, consts.ReminderStatus.COMPLETED); code != response.CodeSuccess {
+		t.Fatalf("complete: code = %d", code)
+	}
+
+	// Reads flip pending reminders whose deadline has since passed, never completed ones
+	yesterday := now.AddDate(0, 0, -1).Format(consts.DATE_LAYOUT)
+	for _, id := range []int{passing.ID, done.ID} {
+		reminders.reminders[id].DueDate, _ = time.Parse(consts.DATE_LAYOUT, yesterday)
+	}
+	overdue := consts.ReminderStatus.OVERDUE
+	list, code := service.ListReminders(ctx, "user-1", &models.ReminderQuery{Status: &overdue})
+	if code != response.CodeSuccess || len(list) != 2 {
+		t.Fatalf("overdue reminders: code %d, %d reminders, want 2", code, len(list))
+	}
+	if reminders.reminders[done.ID].Status != consts.ReminderStatus.COMPLETED {
+		t.Errorf("completed reminder became %d", reminders.reminders[done.ID].Status)
+	}
+
+	// Moving the deadline forward makes an overdue reminder pending again
+	tomorrow := now.AddDate(0, 0, 1).Format(consts.DATE_LAYOUT)
+	moved, code := service.UpdateReminder(ctx, "user-1", passing.ID, &models.UpdateReminderRequest{DueDate: &tomorrow})
+	if code != response.CodeSuccess || moved.Status != consts.ReminderStatus.PENDING {
+		t.Errorf("moved reminder: code %d, status %d, want pending", code, moved.Status)
+	}
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nas03/scholar-ai/backend/pkg/diff"
+	"github.com/nas03/scholar-ai/backend/pkg/richtext"
+)
+
+func TestRichTextRendersSanitizedHTML(t *testing.T) {
+	doc, err := richtext.Parse([]byte(`{"type":"doc","content":[
+		{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Week 3 <script>"}]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"see "},
+			{"type":"text","text":"slides","marks":[{"type":"bold"},{"type":"link","attrs":{"href":"javascript:alert(1)"}}]},
+			{"type":"text","text":" and "},
+			{"type":"text","text":"notes","marks":[{"type":"link","attrs":{"href":"https://example.com/?a=1&b=\"2\""}}]}
+		]},
+		{"type":"image","attrs":{"src":"javascript:alert(1)","alt":"diagram"}},
+		{"type":"iframe","content":[{"type":"text","text":"kept"}]}
+	]}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := `<h2>Week 3 &lt;script&gt;</h2>` +
+		`<p>see <strong>slides</strong> and <a href="https://example.com/?a=1&amp;b=&#34;2&#34;" rel="noopener noreferrer nofollow" target="_blank">notes</a></p>` +
+		`kept`
+	if got := doc.HTML(); got != want {
+		t.Errorf("HTML:\n got %s\nwant %s", got, want)
+	}
+
+	if got := doc.PlainText(); got != "Week 3 <script>\nsee slides and notes\ndiagram\nkept" {
+		t.Errorf("PlainText = %q", got)
+	}
+}
+
+func TestRichTextRejectsInvalidDocuments(t *testing.T) {
+	deep := strings.Repeat(`{"type":"blockquote","content":[`, 100) + `{"type":"text","text":"x"}` + strings.Repeat(`]}`, 100)
+
+	for name, input := range map[string]string{
+		"not json":       `<p>hello</p>`,
+		"wrong root":     `{"type":"paragraph"}`,
+		"missing type":   `{"type":"doc","content":[{"text":"x"}]}`,
+		"text with kids": `{"type":"doc","content":[{"type":"text","content":[{"type":"text"}]}]}`,
+		"too deep":       `{"type":"doc","content":[` + deep + `]}`,
+	} {
+		if _, err := richtext.Parse([]byte(input)); !errors.Is(err, richtext.ErrInvalidDocument) {
+			t.Errorf("%s: expected ErrInvalidDocument, got %v", name, err)
+		}
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	lines := diff.Lines("intro\nsorting\nsearching\nsummary", "intro\nsorting\nhashing\nsearching\nrecap")
+
+	want := []diff.Line{
+		{Op: diff.Equal, Text: "intro"},
+		{Op: diff.Equal, Text: "sorting"},
+		{Op: diff.Insert, Text: "hashing"},
+		{Op: diff.Equal, Text: "searching"},
+		{Op: diff.Delete, Text: "summary"},
+		{Op: diff.Insert, Text: "recap"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d: %+v", len(lines), len(want), lines)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, lines[i], want[i])
+		}
+	}
+
+	if got := diff.Lines("", ""); len(got) != 0 {
+		t.Errorf("empty diff = %+v", got)
+	}
+}
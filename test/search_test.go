@@ -0,0 +1,194 @@
+package test
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"github.com/nas03/scholar-ai/backend/pkg/search"
+	"go.uber.org/zap"
+)
+
+func TestSearchTermsDropOperatorsAndShortWords(t *testing.T) {
+	terms := search.Terms(`+Sorting -"algo*" of an ALGO (heap)`)
+	want := []string{"sorting", "algo", "heap"}
+	if !reflect.DeepEqual(terms, want) {
+		t.Fatalf("Terms = %v, want %v", terms, want)
+	}
+	if got := search.BooleanQuery(terms); got != "+sorting* +algo* +heap*" {
+		t.Fatalf("BooleanQuery = %q", got)
+	}
+
+	// Short words are kept when nothing else is left
+	if got := search.Terms("AI ml"); !reflect.DeepEqual(got, []string{"ai", "ml"}) {
+		t.Fatalf("Terms(short) = %v", got)
+	}
+	if got := search.Terms(`"*" + -`); len(got) != 0 {
+		t.Fatalf("Terms(operators) = %v, want none", got)
+	}
+}
+
+func TestSearchHighlightMarksWholeWords(t *testing.T) {
+	got := search.Highlight("Quicksort <b>beats</b> sorting\nnetworks; resort later", []string{"sort"}, 0)
+	want := "Quicksort &lt;b&gt;beats&lt;/b&gt; <mark>sorting</mark> networks; resort later"
+	if got != want {
+		t.Fatalf("Highlight = %q, want %q", got, want)
+	}
+}
+
+func TestSearchHighlightExcerptsAroundFirstMatch(t *testing.T) {
+	text := strings.Repeat("filler ", 100) + "the master theorem solves recurrences " + strings.Repeat("tail ", 100)
+	got := search.Highlight(text, []string{"theorem"}, 60)
+
+	if !strings.HasPrefix(got, "… ") || !strings.HasSuffix(got, " …") {
+		t.Fatalf("excerpt should be elided on both sides: %q", got)
+	}
+	if !strings.Contains(got, "master <mark>theorem</mark> solves") {
+		t.Fatalf("excerpt should contain the match: %q", got)
+	}
+	for _, word := range strings.Fields(strings.Trim(got, "… ")) {
+		if strings.HasSuffix(word, "ler") && word != "filler" || strings.HasPrefix(word, "ta") && word != "tail" {
+			t.Fatalf("excerpt should not cut words: %q", got)
+		}
+	}
+}
+
+// searchDocument is one searchable row; files have one per extracted chunk
+type searchDocument struct {
+	userID string
+	row    models.SearchRow
+}
+
+// memorySearchRepository ranks documents by their given score, returning each
+// file once with its best chunk, the way the MySQL engine does
+type memorySearchRepository struct {
+	documents []searchDocument
+	searches  int
+}
+
+func (r *memorySearchRepository) hits(filter models.SearchFilter) []models.SearchRow {
+	var hits []models.SearchRow
+	for _, doc := range r.documents {
+		if doc.userID != filter.UserID || len(filter.Types) > 0 && !slices.Contains(filter.Types, doc.row.Type) {
+			continue
+		}
+		text := strings.ToLower(doc.row.Title + " " + doc.row.Body)
+		if slices.ContainsFunc(filter.Terms, func(term string) bool { return !strings.Contains(text, term) }) {
+			continue
+		}
+		if i := slices.IndexFunc(hits, func(hit models.SearchRow) bool { return hit.Type == doc.row.Type && hit.ID == doc.row.ID }); i >= 0 {
+			if doc.row.Score > hits[i].Score {
+				hits[i] = doc.row
+			}
+			continue
+		}
+		hits = append(hits, doc.row)
+	}
+
+	slices.SortFunc(hits, func(a, b models.SearchRow) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), b.Date.Time.Compare(a.Date.Time), cmp.Compare(b.ID, a.ID))
+	})
+	return hits
+}
+
+func (r *memorySearchRepository) Search(ctx context.Context, filter models.SearchFilter) ([]models.SearchRow, error) {
+	r.searches++
+	hits := r.hits(filter)
+	return hits[min(filter.Offset, len(hits)):min(filter.Offset+filter.Limit, len(hits))], nil
+}
+
+func (r *memorySearchRepository) Count(ctx context.Context, filter models.SearchFilter) (map[string]int64, error) {
+	counts := map[string]int64{}
+	for _, hit := range r.hits(filter) {
+		counts[hit.Type]++
+	}
+	return counts, nil
+}
+
+func TestSearchRanksAndPagesUserContent(t *testing.T) {
+	global.Log = zap.NewNop()
+
+	created := sql.NullTime{Time: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	chunk := func(fileID int, score float64, pages int64, body string) searchDocument {
+		return searchDocument{userID: "user-1", row: models.SearchRow{
+			Type: consts.SearchType.FILE, ID: fileID, Title: "algorithms.pdf", Body: body, Score: score, Date: created,
+			PageStart: sql.NullInt64{Int64: pages, Valid: true}, PageEnd: sql.NullInt64{Int64: pages + 1, Valid: true},
+		}}
+	}
+	repo := &memorySearchRepository{documents: []searchDocument{
+		{userID: "user-1", row: models.SearchRow{Type: consts.SearchType.NOTE, ID: 1, Title: "Heap sort", Body: "Lecture on heap sort", Score: 3, Date: created}},
+		{userID: "user-1", row: models.SearchRow{Type: consts.SearchType.FLASHCARD, ID: 1, Title: "What is a heap?", Body: "What is a heap? · A complete binary tree", Score: 2, Date: created}},
+		{userID: "user-1", row: models.SearchRow{Type: consts.SearchType.NOTE, ID: 2, Title: "Graphs", Body: "Dijkstra", Score: 5, Date: created}},
+		chunk(7, 1.5, 1, "Introduction: the heap"),
+		chunk(7, 4, 9, "Binary heap operations"),
+		chunk(7, 0.5, 20, "Exercises on heap"),
+		chunk(8, 1, 3, "A heap of notes"),
+		{userID: "user-2", row: models.SearchRow{Type: consts.SearchType.NOTE, ID: 3, Title: "Heap", Body: "heap heap heap", Score: 10, Date: created}},
+	}}
+	searchService := services.NewSearchService(repo)
+
+	ctx := context.Background()
+	first, code := searchService.Search(ctx, "user-1", &models.SearchQuery{Q: "heap", PageSize: 2})
+	if code != response.CodeSuccess {
+		t.Fatalf("Search: code %d", code)
+	}
+	want := map[string]int64{consts.SearchType.NOTE: 1, consts.SearchType.FILE: 2, consts.SearchType.FLASHCARD: 1}
+	if first.Total != 4 || !reflect.DeepEqual(first.Counts, want) {
+		t.Fatalf("Total = %d, Counts = %v; want 4, %v", first.Total, first.Counts, want)
+	}
+	if len(first.Hits) != 2 {
+		t.Fatalf("page 1 has %d hits, want 2", len(first.Hits))
+	}
+
+	// The long file ranks by its best chunk and shows only that one
+	file := first.Hits[0]
+	if file.Type != consts.SearchType.FILE || file.ID != 7 || file.Score != 4 {
+		t.Fatalf("first hit = %+v, want file 7 with score 4", file)
+	}
+	if file.Snippet != "Binary <mark>heap</mark> operations" || *file.PageStart != 9 || *file.PageEnd != 10 {
+		t.Errorf("file hit = %q pages %d-%d, want its best chunk", file.Snippet, *file.PageStart, *file.PageEnd)
+	}
+	if note := first.Hits[1]; note.Type != consts.SearchType.NOTE || note.ID != 1 {
+		t.Errorf("second hit = %+v, want note 1", note)
+	}
+
+	second, _ := searchService.Search(ctx, "user-1", &models.SearchQuery{Q: "heap", Page: 2, PageSize: 2})
+	var got []string
+	for _, hit := range second.Hits {
+		got = append(got, hit.Type)
+	}
+	if !reflect.DeepEqual(got, []string{consts.SearchType.FLASHCARD, consts.SearchType.FILE}) || second.Hits[1].ID != 8 {
+		t.Errorf("page 2 = %+v, want flashcard 1 then file 8", second.Hits)
+	}
+
+	// Past the last page the engine is not asked for rows
+	searches := repo.searches
+	third, _ := searchService.Search(ctx, "user-1", &models.SearchQuery{Q: "heap", Page: 3, PageSize: 2})
+	if len(third.Hits) != 0 || third.Total != 4 || repo.searches != searches {
+		t.Errorf("page 3 = %+v after %d searches", third, repo.searches-searches)
+	}
+
+	filtered, _ := searchService.Search(ctx, "user-1", &models.SearchQuery{Q: "heap", Types: "Flashcard, note"})
+	if filtered.Total != 2 || len(filtered.Hits) != 2 || filtered.Hits[0].Type != consts.SearchType.NOTE {
+		t.Errorf("notes and flashcards = %+v", filtered)
+	}
+
+	// Another user's content is never returned
+	other, _ := searchService.Search(ctx, "user-2", &models.SearchQuery{Q: "heap"})
+	if other.Total != 1 || len(other.Hits) != 1 || other.Hits[0].ID != 3 {
+		t.Errorf("user-2 results = %+v", other)
+	}
+	if none, _ := searchService.Search(ctx, "user-3", &models.SearchQuery{Q: "heap"}); none.Total != 0 || len(none.Hits) != 0 {
+		t.Errorf("user-3 results = %+v", none)
+	}
+}
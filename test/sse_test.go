@@ -0,0 +1,130 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func TestStreamSSEHeartbeatsAndStopsOnDisconnect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	stopped := make(chan error, 1)
+	engine := gin.New()
+	engine.GET("/events", func(ctx *gin.Context) {
+		stopped <- response.StreamSSE(ctx, 10*time.Millisecond, func(streamCtx context.Context, stream *response.SSEStream) error {
+			if err := stream.Send(response.SSEEvent{ID: "7", Event: "note", Data: "line one\nline two"}); err != nil {
+				return err
+			}
+			if err := stream.Send(response.SSEEvent{Event: "resume", Data: gin.H{"after": response.LastEventID(ctx)}}); err != nil {
+				return err
+			}
+			<-streamCtx.Done()
+			return streamCtx.Err()
+		})
+	})
+	server := httptest.NewServer(engine)
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/events", nil)
+	req.Header.Set("Last-Event-ID", "6")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("content type = %q", resp.Header.Get("Content-Type"))
+	}
+
+	var lines []string
+	reader := bufio.NewReader(resp.Body)
+	for !containsLine(lines, ": heartbeat") {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read: %v, lines = %q", err, lines)
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	stream := strings.Join(lines, "\n")
+	if lines[0] != ": connected" || !strings.Contains(stream, "id: 7\nevent: note\ndata: line one\ndata: line two\n\n") || !strings.Contains(stream, `data: {"after":"6"}`) {
+		t.Fatalf("stream = %q", stream)
+	}
+
+	resp.Body.Close()
+	select {
+	case err := <-stopped:
+		if err == nil {
+			t.Fatal("expected the stream context to be cancelled")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler did not notice the disconnect")
+	}
+}
+
+func containsLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFollowJobProgressResumesAfterLastEvent(t *testing.T) {
+	global.Log = zap.NewNop()
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer rdb.Close()
+
+	store := repositories.NewRedisProgressStore(rdb)
+	ctx := context.Background()
+	for _, event := range []models.JobProgress{
+		{Status: 1},
+		{Status: 1, Stage: "summarize", Done: 1, Total: 2},
+		{Status: 2, Final: true},
+	} {
+		if err := store.Publish(ctx, "summary:4", event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if server.TTL("job:summary:4:progress") <= 0 {
+		t.Fatal("progress stream must expire")
+	}
+
+	service := services.NewProgressService(store)
+	var all []models.JobProgress
+	collect := func(events *[]models.JobProgress) func(models.JobProgress) error {
+		return func(event models.JobProgress) error {
+			*events = append(*events, event)
+			return nil
+		}
+	}
+	if code := service.Follow(ctx, "summary", 4, models.JobProgress{Status: 1}, "", collect(&all)); code != response.CodeSuccess || len(all) != 3 {
+		t.Fatalf("code = %d, events = %+v", code, all)
+	}
+
+	var resumed []models.JobProgress
+	service.Follow(ctx, "summary", 4, models.JobProgress{Status: 1}, all[0].ID, collect(&resumed))
+	if len(resumed) != 2 || resumed[0].ID != all[1].ID || resumed[0].Done != 1 || !resumed[1].Final {
+		t.Fatalf("resumed = %+v", resumed)
+	}
+
+	var finished []models.JobProgress
+	service.Follow(ctx, "summary", 4, models.JobProgress{Status: 3, Error: "boom", Final: true}, "", collect(&finished))
+	if len(finished) != 1 || finished[0].Error != "boom" {
+		t.Fatalf("finished = %+v", finished)
+	}
+}
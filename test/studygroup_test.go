@@ -0,0 +1,352 @@
+package test
+
+import (
+	"context"
+	"html"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/helper"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// memoryStudyGroupRepository keeps groups, members, invitations and shared notes in memory
+type memoryStudyGroupRepository struct {
+	repositories.IStudyGroupRepository
+	groups      map[int]*models.StudyGroup
+	members     map[int]map[string]*models.StudyGroupMember
+	invitations []*models.StudyGroupInvitation
+	shared      []models.StudyGroupNote
+	notes       map[int]models.Note
+}
+
+func newMemoryStudyGroupRepository() *memoryStudyGroupRepository {
+	return &memoryStudyGroupRepository{
+		groups:  map[int]*models.StudyGroup{},
+		members: map[int]map[string]*models.StudyGroupMember{},
+		notes:   map[int]models.Note{},
+	}
+}
+
+func (r *memoryStudyGroupRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return fn(nil)
+}
+
+func (r *memoryStudyGroupRepository) WithTx(tx *gorm.DB) repositories.IStudyGroupRepository {
+	return r
+}
+
+func (r *memoryStudyGroupRepository) CreateGroup(ctx context.Context, group *models.StudyGroup) error {
+	group.ID = len(r.groups) + 1
+	r.groups[group.ID] = group
+	r.members[group.ID] = map[string]*models.StudyGroupMember{}
+	for _, member := range group.Members {
+		member.GroupID = group.ID
+		r.members[group.ID][member.UserID] = &member
+	}
+	return nil
+}
+
+func (r *memoryStudyGroupRepository) GetGroupByID(ctx context.Context, id int) (*models.StudyGroup, error) {
+	group, ok := r.groups[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return group, nil
+}
+
+func (r *memoryStudyGroupRepository) UpdateGroup(ctx context.Context, id int, updates map[string]any) error {
+	if owner, ok := updates["owner_id"]; ok {
+		r.groups[id].OwnerID = owner.(string)
+	}
+	return nil
+}
+
+func (r *memoryStudyGroupRepository) GetMember(ctx context.Context, groupID int, userID string) (*models.StudyGroupMember, error) {
+	member, ok := r.members[groupID][userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *member
+	return &copied, nil
+}
+
+func (r *memoryStudyGroupRepository) ListMembers(ctx context.Context, groupID int) ([]models.StudyGroupMemberView, error) {
+	var members []models.StudyGroupMemberView
+	for _, member := range r.members[groupID] {
+		members = append(members, models.StudyGroupMemberView{UserID: member.UserID, Role: member.Role})
+	}
+	return members, nil
+}
+
+func (r *memoryStudyGroupRepository) CountMembers(ctx context.Context, groupID int) (int64, error) {
+	return int64(len(r.members[groupID])), nil
+}
+
+func (r *memoryStudyGroupRepository) AddMember(ctx context.Context, member *models.StudyGroupMember) error {
+	r.members[member.GroupID][member.UserID] = member
+	return nil
+}
+
+func (r *memoryStudyGroupRepository) UpdateMemberRole(ctx context.Context, groupID int, userID string, role int8) error {
+	r.members[groupID][userID].Role = role
+	return nil
+}
+
+func (r *memoryStudyGroupRepository) RemoveMember(ctx context.Context, groupID int, userID string) error {
+	delete(r.members[groupID], userID)
+	return nil
+}
+
+func (r *memoryStudyGroupRepository) CreateInvitation(ctx context.Context, invitation *models.StudyGroupInvitation) error {
+	invitation.ID = len(r.invitations) + 1
+	r.invitations = append(r.invitations, invitation)
+	return nil
+}
+
+func (r *memoryStudyGroupRepository) GetInvitationByToken(ctx context.Context, token string) (*models.StudyGroupInvitation, error) {
+	for _, invitation := range r.invitations {
+		if invitation.Token == token {
+			copied := *invitation
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryStudyGroupRepository) UpdateInvitation(ctx context.Context, id int, updates map[string]any) error {
+	if acceptedAt, ok := updates["accepted_at"]; ok {
+		r.invitations[id-1].AcceptedAt.Time = acceptedAt.(time.Time)
+		r.invitations[id-1].AcceptedAt.Valid = true
+	}
+	return nil
+}
+
+func (r *memoryStudyGroupRepository) ShareNote(ctx context.Context, shared *models.StudyGroupNote) error {
+	r.shared = append(r.shared, *shared)
+	return nil
+}
+
+func (r *memoryStudyGroupRepository) GetSharedNote(ctx context.Context, groupID, noteID int) (*models.StudyGroupNote, error) {
+	for _, shared := range r.shared {
+		if shared.GroupID == groupID && shared.NoteID == noteID {
+			note := r.notes[noteID]
+			shared.Note = &note
+			return &shared, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryStudyGroupRepository) ListSharedNotes(ctx context.Context, groupID int) ([]models.SharedNote, error) {
+	var notes []models.SharedNote
+	for _, shared := range r.shared {
+		if shared.GroupID == groupID {
+			notes = append(notes, models.SharedNote{NoteID: shared.NoteID, AuthorID: shared.SharedBy})
+		}
+	}
+	return notes, nil
+}
+
+func (r *memoryStudyGroupRepository) UnshareNotesBy(ctx context.Context, groupID int, userID string) error {
+	kept := r.shared[:0]
+	for _, shared := range r.shared {
+		if shared.GroupID != groupID || shared.SharedBy != userID {
+			kept = append(kept, shared)
+		}
+	}
+	r.shared = kept
+	return nil
+}
+
+// courseCodeRepository gives every user the same course code
+type courseCodeRepository struct {
+	repositories.ICourseRepository
+}
+
+func (r *courseCodeRepository) GetCourseByID(ctx context.Context, id int, userID string) (*models.Course, error) {
+	return &models.Course{ID: id, UserID: userID, CourseID: "cs 101", CourseName: "Intro to CS"}, nil
+}
+
+// groupNoteRepository looks notes up in the study group repository's note table
+type groupNoteRepository struct {
+	repositories.INoteRepository
+	groups *memoryStudyGroupRepository
+}
+
+func (r *groupNoteRepository) GetNoteByID(ctx context.Context, id int, userID string) (*models.Note, error) {
+	note, ok := r.groups.notes[id]
+	if !ok || note.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &note, nil
+}
+
+type emailUserRepository struct {
+	repositories.IUserRepository
+}
+
+func (r *emailUserRepository) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
+	return &models.User{UserID: userID, Username: userID, Email: userID + "@example.com"}, nil
+}
+
+type missingMailRepository struct {
+	repositories.IMailRepository
+}
+
+func (r *missingMailRepository) GetMailTemplate(ctx context.Context, id int) (*models.Mail, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+// recordingMailHelper keeps the addresses and bodies of the mail sent, filling
+// templates like the real helper
+type recordingMailHelper struct {
+	mu     sync.Mutex
+	sent   []string
+	bodies []string
+}
+
+func (h *recordingMailHelper) SendMail(ctx context.Context, to, subject, body string) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sent = append(h.sent, to)
+	h.bodies = append(h.bodies, body)
+	return "mail", nil
+}
+
+func (h *recordingMailHelper) ReplaceParameters(ctx context.Context, html string, data any) string {
+	return (&helper.MailHelper{}).ReplaceParameters(ctx, html, data)
+}
+
+func TestStudyGroupMembershipAndSharing(t *testing.T) {
+	global.Log = zap.NewNop()
+	ctx := context.Background()
+	groups := newMemoryStudyGroupRepository()
+	groups.notes[1] = models.Note{ID: 1, UserID: "bob", Course: &models.Course{CourseID: "CS-101"}}
+	groups.notes[2] = models.Note{ID: 2, UserID: "bob", Course: &models.Course{CourseID: "MATH201"}}
+	mail := &recordingMailHelper{}
+	service := services.NewStudyGroupService(groups, &courseCodeRepository{}, &groupNoteRepository{groups: groups}, &emailUserRepository{}, &missingMailRepository{}, mail, nil)
+
+	group, code := service.CreateGroup(ctx, "alice", &models.CreateStudyGroupRequest{CourseID: 3, Name: "Algorithms crew"})
+	if code != response.CodeSuccess {
+		t.Fatalf("CreateGroup code = %d", code)
+	}
+	if group.CourseCode != "CS101" || group.Role != consts.StudyGroupRole.OWNER {
+		t.Fatalf("group course %q, role %d; want CS101 and owner", group.CourseCode, group.Role)
+	}
+
+	// An email invitation only works for its address, once
+	email := "Bob@Example.com"
+	invite, code := service.CreateInvitation(ctx, "alice", group.ID, &models.CreateInvitationRequest{Email: &email})
+	if code != response.CodeSuccess || !invite.EmailSent || len(mail.sent) != 1 || mail.sent[0] != "bob@example.com" {
+		t.Fatalf("email invitation: code %d, sent %v", code, mail.sent)
+	}
+	if _, code := service.Join(ctx, "carol", &models.JoinStudyGroupRequest{Token: invite.Token}); code != response.CodeInvitationEmailMismatch {
+		t.Errorf("join with bob's invitation as carol: code = %d, want %d", code, response.CodeInvitationEmailMismatch)
+	}
+	if _, code := service.Join(ctx, "bob", &models.JoinStudyGroupRequest{Token: invite.Token}); code != response.CodeSuccess {
+		t.Fatalf("bob joining: code = %d", code)
+	}
+	if _, code := service.Join(ctx, "bob", &models.JoinStudyGroupRequest{Token: invite.Token}); code != response.CodeInvitationInvalid {
+		t.Errorf("reusing an email invitation: code = %d, want %d", code, response.CodeInvitationInvalid)
+	}
+
+	// Members cannot invite; join links work for anyone until they expire
+	if _, code := service.CreateInvitation(ctx, "bob", group.ID, &models.CreateInvitationRequest{}); code != response.CodeStudyGroupForbidden {
+		t.Errorf("member inviting: code = %d, want %d", code, response.CodeStudyGroupForbidden)
+	}
+	link, _ := service.CreateInvitation(ctx, "alice", group.ID, &models.CreateInvitationRequest{})
+	if _, code := service.Join(ctx, "carol", &models.JoinStudyGroupRequest{Token: link.Token}); code != response.CodeSuccess {
+		t.Fatalf("carol joining by link: code = %d", code)
+	}
+	groups.invitations[link.ID-1].ExpiresAt = time.Now().Add(-time.Minute)
+	if _, code := service.Join(ctx, "dave", &models.JoinStudyGroupRequest{Token: link.Token}); code != response.CodeInvitationInvalid {
+		t.Errorf("expired link: code = %d, want %d", code, response.CodeInvitationInvalid)
+	}
+
+	// Only notes of the group's course can be shared, and only members see them
+	if _, code := service.ShareNote(ctx, "bob", group.ID, &models.ShareNoteRequest{NoteID: 2}); code != response.CodeGroupNoteCourseMismatch {
+		t.Errorf("sharing a note of another course: code = %d, want %d", code, response.CodeGroupNoteCourseMismatch)
+	}
+	if notes, code := service.ShareNote(ctx, "bob", group.ID, &models.ShareNoteRequest{NoteID: 1}); code != response.CodeSuccess || len(notes) != 1 {
+		t.Fatalf("sharing: code %d, notes %v", code, notes)
+	}
+	if _, code := service.GetSharedNote(ctx, "carol", group.ID, 1); code != response.CodeSuccess {
+		t.Errorf("member reading a shared note: code = %d", code)
+	}
+	if _, code := service.GetSharedNote(ctx, "dave", group.ID, 1); code != response.CodeStudyGroupNotFound {
+		t.Errorf("non-member reading a shared note: code = %d, want %d", code, response.CodeStudyGroupNotFound)
+	}
+
+	// Admins kick members but not each other; a kicked member's notes leave with them
+	role := consts.StudyGroupRole.ADMIN
+	if _, code := service.UpdateMemberRole(ctx, "alice", group.ID, "carol", &models.UpdateMemberRoleRequest{Role: &role}); code != response.CodeSuccess {
+		t.Fatalf("promoting carol: code = %d", code)
+	}
+	if code := service.RemoveMember(ctx, "carol", group.ID, "alice"); code != response.CodeStudyGroupForbidden {
+		t.Errorf("admin kicking the owner: code = %d, want %d", code, response.CodeStudyGroupForbidden)
+	}
+	if code := service.RemoveMember(ctx, "carol", group.ID, "bob"); code != response.CodeSuccess {
+		t.Fatalf("admin kicking a member: code = %d", code)
+	}
+	if notes, _ := service.ListSharedNotes(ctx, "carol", group.ID); len(notes) != 0 {
+		t.Errorf("kicked member's notes still shared: %v", notes)
+	}
+
+	// The owner hands the group over before leaving
+	if code := service.Leave(ctx, "alice", group.ID); code != response.CodeGroupOwnerCannotLeave {
+		t.Errorf("owner leaving: code = %d, want %d", code, response.CodeGroupOwnerCannotLeave)
+	}
+	owner := consts.StudyGroupRole.OWNER
+	if _, code := service.UpdateMemberRole(ctx, "alice", group.ID, "carol", &models.UpdateMemberRoleRequest{Role: &owner}); code != response.CodeSuccess {
+		t.Fatalf("handing over: code = %d", code)
+	}
+	if groups.groups[group.ID].OwnerID != "carol" || groups.members[group.ID]["alice"].Role != consts.StudyGroupRole.ADMIN {
+		t.Errorf("after handover owner %q, alice role %d", groups.groups[group.ID].OwnerID, groups.members[group.ID]["alice"].Role)
+	}
+	if code := service.Leave(ctx, "alice", group.ID); code != response.CodeSuccess {
+		t.Errorf("former owner leaving: code = %d", code)
+	}
+}
+
+func TestStudyGroupInvitationMailEscapesUserInput(t *testing.T) {
+	global.Log = zap.NewNop()
+	previous := global.Config.StudyGroup
+	global.Config.StudyGroup.JoinBaseURL = "https://app.example.com/join"
+	t.Cleanup(func() { global.Config.StudyGroup = previous })
+
+	ctx := context.Background()
+	groups := newMemoryStudyGroupRepository()
+	mail := &recordingMailHelper{}
+	service := services.NewStudyGroupService(groups, &courseCodeRepository{}, &groupNoteRepository{groups: groups}, &emailUserRepository{}, &missingMailRepository{}, mail, nil)
+
+	name := `<a href="https://evil.example">Claim your prize</a>`
+	group, code := service.CreateGroup(ctx, "alice", &models.CreateStudyGroupRequest{CourseID: 3, Name: name})
+	if code != response.CodeSuccess {
+		t.Fatalf("CreateGroup code = %d", code)
+	}
+	email := "bob@example.com"
+	if _, code := service.CreateInvitation(ctx, "alice", group.ID, &models.CreateInvitationRequest{Email: &email}); code != response.CodeSuccess || len(mail.bodies) != 1 {
+		t.Fatalf("CreateInvitation code = %d, %d mails", code, len(mail.bodies))
+	}
+
+	body := mail.bodies[0]
+	if strings.Contains(body, "evil.example\">") || strings.Contains(body, "<a href=\"https://evil") {
+		t.Errorf("group name reached the mail as markup:\n%s", body)
+	}
+	if !strings.Contains(body, html.EscapeString(name)) {
+		t.Errorf("mail does not show the escaped group name:\n%s", body)
+	}
+	if !strings.Contains(body, `<a href="https://app.example.com/join/`) {
+		t.Errorf("join link missing from the mail:\n%s", body)
+	}
+}
@@ -0,0 +1,184 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// memoryStudySessionRepository keeps sessions in memory and returns canned
+// analytics rows, which MySQL aggregates in production
+type memoryStudySessionRepository struct {
+	repositories.IStudySessionRepository
+	sessions    []models.StudySession
+	streaks     []models.StudyStreak
+	correlation models.StudyGradeCorrelation
+}
+
+func (r *memoryStudySessionRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return fn(nil)
+}
+
+func (r *memoryStudySessionRepository) WithTx(tx *gorm.DB) repositories.IStudySessionRepository {
+	return r
+}
+
+func (r *memoryStudySessionRepository) CreateSession(ctx context.Context, session *models.StudySession) error {
+	session.ID = len(r.sessions) + 1
+	r.sessions = append(r.sessions, *session)
+	return nil
+}
+
+func (r *memoryStudySessionRepository) GetSessionByID(ctx context.Context, id int, userID string) (*models.StudySession, error) {
+	for i := range r.sessions {
+		if r.sessions[i].ID == id && r.sessions[i].UserID == userID {
+			session := r.sessions[i]
+			return &session, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryStudySessionRepository) GetRunningSession(ctx context.Context, userID string) (*models.StudySession, error) {
+	for i := range r.sessions {
+		if r.sessions[i].UserID == userID && !r.sessions[i].EndedAt.Valid {
+			return &r.sessions[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryStudySessionRepository) CountOverlapping(ctx context.Context, userID string, from, to time.Time) (int64, error) {
+	var count int64
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.StartedAt.Before(to) && (!session.EndedAt.Valid || session.EndedAt.Time.After(from)) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryStudySessionRepository) UpdateSession(ctx context.Context, id int, userID string, updates map[string]any) error {
+	for i := range r.sessions {
+		if r.sessions[i].ID == id {
+			r.sessions[i].EndedAt = updates["ended_at"].(sql.NullTime)
+			r.sessions[i].DurationSeconds = updates["duration_seconds"].(int)
+		}
+	}
+	return nil
+}
+
+func (r *memoryStudySessionRepository) WeeklyStudyTime(ctx context.Context, userID, offset string, from, to time.Time) ([]models.CourseWeekStudyTime, error) {
+	return []models.CourseWeekStudyTime{{CourseID: 1, StudyMinutes: 90, PlannedMinutes: 120}, {CourseID: 2, StudyMinutes: 30}}, nil
+}
+
+func (r *memoryStudySessionRepository) StudyStreaks(ctx context.Context, userID, offset string) ([]models.StudyStreak, error) {
+	return r.streaks, nil
+}
+
+func (r *memoryStudySessionRepository) CourseGradeStudyTime(ctx context.Context, userID string, from, to time.Time) ([]models.CourseGradeStudyTime, error) {
+	return nil, nil
+}
+
+func (r *memoryStudySessionRepository) StudyGradeCorrelation(ctx context.Context, userID string, from, to time.Time) (*models.StudyGradeCorrelation, error) {
+	correlation := r.correlation
+	return &correlation, nil
+}
+
+type ownedCourseRepository struct {
+	repositories.ICourseRepository
+	userID string
+}
+
+func (r *ownedCourseRepository) GetCourseByID(ctx context.Context, id int, userID string) (*models.Course, error) {
+	if userID != r.userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &models.Course{ID: id, UserID: userID}, nil
+}
+
+func TestStudySessionLifecycle(t *testing.T) {
+	global.Log = zap.NewNop()
+
+	sessions := &memoryStudySessionRepository{}
+	service := services.NewStudySessionService(sessions, &ownedCourseRepository{userID: "u1"}, nil, &memoryUserRepository{user: &models.User{UserID: "u1"}})
+	ctx := context.Background()
+
+	running, code := service.StartSession(ctx, "u1", &models.StartStudySessionRequest{CourseID: 1})
+	if code != response.CodeSuccess {
+		t.Fatalf("start code = %d", code)
+	}
+	if _, code := service.StartSession(ctx, "u1", &models.StartStudySessionRequest{CourseID: 2}); code != response.CodeStudySessionRunning {
+		t.Errorf("second start code = %d, want %d", code, response.CodeStudySessionRunning)
+	}
+	if _, code := service.StartSession(ctx, "u2", &models.StartStudySessionRequest{CourseID: 1}); code != response.CodeCourseNotFound {
+		t.Errorf("start on another user's course code = %d, want %d", code, response.CodeCourseNotFound)
+	}
+
+	// A session left running overnight is capped
+	sessions.sessions[0].StartedAt = time.Now().UTC().Add(-20 * time.Hour)
+	pomodoros := 4
+	stopped, code := service.StopSession(ctx, "u1", running.ID, &models.StopStudySessionRequest{Pomodoros: &pomodoros})
+	if code != response.CodeSuccess {
+		t.Fatalf("stop code = %d", code)
+	}
+	if stopped.DurationSeconds != 12*60*60 || stopped.Pomodoros != 4 {
+		t.Errorf("stopped = %d seconds, %d pomodoros", stopped.DurationSeconds, stopped.Pomodoros)
+	}
+	if _, code := service.StopSession(ctx, "u1", running.ID, &models.StopStudySessionRequest{}); code != response.CodeStudySessionNotRunning {
+		t.Errorf("second stop code = %d, want %d", code, response.CodeStudySessionNotRunning)
+	}
+
+	start := sessions.sessions[0].StartedAt.Add(time.Hour)
+	overlapping := &models.LogStudySessionRequest{CourseID: 1, StartedAt: start, EndedAt: start.Add(time.Hour)}
+	if _, code := service.LogSession(ctx, "u1", overlapping); code != response.CodeStudySessionOverlap {
+		t.Errorf("overlapping log code = %d, want %d", code, response.CodeStudySessionOverlap)
+	}
+	future := &models.LogStudySessionRequest{CourseID: 1, StartedAt: time.Now().Add(time.Hour), EndedAt: time.Now().Add(2 * time.Hour)}
+	if _, code := service.LogSession(ctx, "u1", future); code != response.CodeInvalidStudySessionTime {
+		t.Errorf("future log code = %d, want %d", code, response.CodeInvalidStudySessionTime)
+	}
+}
+
+func TestStudyTimeAnalyticsStreaks(t *testing.T) {
+	global.Log = zap.NewNop()
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	coefficient := 0.8
+	sessions := &memoryStudySessionRepository{
+		streaks: []models.StudyStreak{
+			{StartDate: today.AddDate(0, 0, -3), EndDate: today.AddDate(0, 0, -1), Days: 3},
+			{StartDate: today.AddDate(0, 0, -20), EndDate: today.AddDate(0, 0, -14), Days: 7},
+		},
+		correlation: models.StudyGradeCorrelation{Courses: 2, Coefficient: &coefficient},
+	}
+	service := services.NewStudySessionService(sessions, &ownedCourseRepository{userID: "u1"}, nil, &memoryUserRepository{user: &models.User{UserID: "u1"}})
+
+	analytics, code := service.GetStudyTime(context.Background(), "u1", &models.StudyTimeQuery{})
+	if code != response.CodeSuccess {
+		t.Fatalf("code = %d", code)
+	}
+	if analytics.Streak.Current != 3 || analytics.Streak.Longest == nil || analytics.Streak.Longest.Days != 7 {
+		t.Errorf("streak = %+v", analytics.Streak)
+	}
+	if analytics.StudyMinutes != 120 || analytics.PlannedMinutes != 120 {
+		t.Errorf("totals = %d studied, %d planned", analytics.StudyMinutes, analytics.PlannedMinutes)
+	}
+	if analytics.Correlation.Coefficient != nil {
+		t.Errorf("correlation over two courses should be omitted")
+	}
+
+	if _, code := service.GetStudyTime(context.Background(), "u1", &models.StudyTimeQuery{From: "2026-01-01", To: "2025-01-01"}); code != response.CodeInvalidStudyTimeRange {
+		t.Errorf("reversed range code = %d, want %d", code, response.CodeInvalidStudyTimeRange)
+	}
+}
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nas03/scholar-ai/backend/global"
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+	"github.com/nas03/scholar-ai/backend/internal/repositories"
+	"github.com/nas03/scholar-ai/backend/internal/services"
+	"github.com/nas03/scholar-ai/backend/pkg/ai"
+	"go.uber.org/zap"
+)
+
+// memorySummaryRepository keeps one summary row in memory
+type memorySummaryRepository struct {
+	repositories.ISummaryRepository
+	summary *models.NoteSummary
+	updates map[string]any
+}
+
+func (r *memorySummaryRepository) GetSummaryByID(ctx context.Context, id int, userID string) (*models.NoteSummary, error) {
+	summary := *r.summary
+	return &summary, nil
+}
+
+func (r *memorySummaryRepository) UpdateSummary(ctx context.Context, id int, updates map[string]any) error {
+	for column, value := range updates {
+		r.updates[column] = value
+	}
+	return nil
+}
+
+type memoryNoteRepository struct {
+	repositories.INoteRepository
+	note *models.Note
+}
+
+func (r *memoryNoteRepository) GetNoteByID(ctx context.Context, id int, userID string) (*models.Note, error) {
+	return r.note, nil
+}
+
+func TestSummarizeLongNoteMapReduce(t *testing.T) {
+	global.Log = zap.NewNop()
+
+	// Three parts at the configured chunk size
+	paragraph := strings.Repeat("Dynamic programming stores the answers of overlapping subproblems. ", 40)
+	text := strings.Repeat(paragraph+"\n\n", 12)
+
+	summaries := &memorySummaryRepository{
+		summary: &models.NoteSummary{ID: 1, NoteID: 7, UserID: "u1", Version: 1, Language: "vi"},
+		updates: map[string]any{},
+	}
+	notes := &memoryNoteRepository{note: &models.Note{ID: 7, UserID: "u1", Title: "Algorithms", ContentText: text, Version: 3}}
+
+	fake := ai.NewFakeProvider()
+	fake.Replies = []string{"Sure! Here is the summary."} // invalid once, then repaired
+	maps := 0
+	fake.Reply = func(req ai.ChatRequest) (string, error) {
+		if !strings.Contains(req.System, "Vietnamese") || !req.JSON {
+			t.Errorf("unexpected request system prompt %q", req.System)
+		}
+		if strings.Contains(req.System, "merge partial summaries") {
+			return "```json\n{\"bullets\": [\"- merged\"], \"key_concepts\": [{\"term\": \"DP\", \"explanation\": \"memoized recursion\"}]}\n```", nil
+		}
+		maps++
+		return `{"bullets": ["part point"], "key_concepts": []}`, nil
+	}
+
+	usage := &memoryUsageRepository{}
+	service := services.NewSummarizationService(summaries, notes, fake, nil, usage)
+	if err := service.SummarizeNote(context.Background(), models.NoteSummarizePayload{SummaryID: 1, UserID: "u1"}); err != nil {
+		t.Fatalf("SummarizeNote: %v", err)
+	}
+
+	if summaries.updates["status"] != consts.SummaryStatus.DONE || summaries.updates["note_version"] != 3 {
+		t.Fatalf("updates = %v", summaries.updates)
+	}
+	chunks := summaries.updates["chunk_count"].(int)
+	if chunks < 2 || maps != chunks {
+		t.Fatalf("chunk_count = %d, map calls = %d", chunks, maps)
+	}
+
+	var bullets []string
+	json.Unmarshal(summaries.updates["bullets"].([]byte), &bullets)
+	if len(bullets) != 1 || bullets[0] != "merged" {
+		t.Fatalf("bullets = %v", bullets)
+	}
+	if summaries.updates["provider"] != "fake" || summaries.updates["model"] == "" {
+		t.Fatalf("provenance not recorded: %v", summaries.updates)
+	}
+	if len(usage.records) != 1 || usage.records[0].Reference != "summary:1" || usage.records[0].InputTokens != summaries.updates["input_tokens"] {
+		t.Fatalf("usage = %+v", usage.records)
+	}
+
+	// The invalid first reply was answered with a correction in the same conversation
+	if retried := fake.Requests()[1]; len(retried.Messages) != 3 || retried.Messages[1].Role != ai.RoleAssistant {
+		t.Fatalf("second request should repair the first: %+v", retried.Messages)
+	}
+}
+
+func TestSummarizeRecordsInvalidOutputWithoutRetry(t *testing.T) {
+	global.Log = zap.NewNop()
+
+	summaries := &memorySummaryRepository{
+		summary: &models.NoteSummary{ID: 1, NoteID: 7, UserID: "u1", Version: 1, Language: "en"},
+		updates: map[string]any{},
+	}
+	notes := &memoryNoteRepository{note: &models.Note{ID: 7, UserID: "u1", Title: "Short", ContentText: "A short note.", Version: 1}}
+
+	// The fake answers JSON requests with an empty object, which has no bullets
+	service := services.NewSummarizationService(summaries, notes, ai.NewFakeProvider(), nil, nil)
+	if err := service.SummarizeNote(context.Background(), models.NoteSummarizePayload{SummaryID: 1, UserID: "u1"}); err != nil {
+		t.Fatalf("invalid output should not be retried by the queue: %v", err)
+	}
+	if summaries.updates["status"] != consts.SummaryStatus.FAILED {
+		t.Fatalf("updates = %v", summaries.updates)
+	}
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/nas03/scholar-ai/backend/internal/consts"
+	"github.com/nas03/scholar-ai/backend/internal/models"
+)
+
+func mustDate(t *testing.T, value string) time.Time {
+	t.Helper()
+	date, err := time.Parse(consts.DATE_LAYOUT, value)
+	if err != nil {
+		t.Fatalf("parse %q: %v", value, err)
+	}
+	return date
+}
+
+func TestClassSessionOccurrences(t *testing.T) {
+	// 2026-09-07 is a Monday
+	session := &models.ClassSession{
+		Weekday:    1,
+		StartTime:  "09:00:00",
+		EndTime:    "10:30:00",
+		Recurrence: consts.ClassSessionRecurrence.BIWEEKLY,
+		StartDate:  mustDate(t, "2026-09-05"),
+		EndDate:    sql.NullTime{Time: mustDate(t, "2026-10-31"), Valid: true},
+		Exceptions: []models.ClassSessionException{{Date: mustDate(t, "2026-10-05")}},
+	}
+
+	got := session.Occurrences(mustDate(t, "2026-09-01"), mustDate(t, "2026-12-31"))
+	want := []string{"2026-09-07", "2026-09-21", "2026-10-19"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d occurrences %v, want %v", len(got), got, want)
+	}
+	for i, date := range got {
+		if date.Format(consts.DATE_LAYOUT) != want[i] {
+			t.Errorf("occurrence %d = %s, want %s", i, date.Format(consts.DATE_LAYOUT), want[i])
+		}
+	}
+}
+
+func TestClassSessionClash(t *testing.T) {
+	weekly := &models.ClassSession{
+		Weekday:   2,
+		StartTime: "13:00:00",
+		EndTime:   "15:00:00",
+		StartDate: mustDate(t, "2026-09-01"),
+	}
+	tests := []struct {
+		name    string
+		other   models.ClassSession
+		clashOn string
+	}{
+		{
+			name:  "back to back sessions do not clash",
+			other: models.ClassSession{Weekday: 2, StartTime: "15:00:00", EndTime: "16:00:00", StartDate: mustDate(t, "2026-09-01")},
+		},
+		{
+			name:    "overlap starting later clashes on its first week",
+			other:   models.ClassSession{Weekday: 2, StartTime: "14:00:00", EndTime: "16:00:00", StartDate: mustDate(t, "2026-10-01")},
+			clashOn: "2026-10-06",
+		},
+		{
+			name:  "series that ends before the other starts",
+			other: models.ClassSession{Weekday: 2, StartTime: "14:00:00", EndTime: "16:00:00", StartDate: mustDate(t, "2026-01-01"), EndDate: sql.NullTime{Time: mustDate(t, "2026-08-31"), Valid: true}},
+		},
+		{
+			name: "only cancelled dates overlap",
+			other: models.ClassSession{
+				Weekday: 2, StartTime: "14:00:00", EndTime: "16:00:00",
+				StartDate:  mustDate(t, "2026-09-01"),
+				EndDate:    sql.NullTime{Time: mustDate(t, "2026-09-08"), Valid: true},
+				Exceptions: []models.ClassSessionException{{Date: mustDate(t, "2026-09-01")}, {Date: mustDate(t, "2026-09-08")}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, clash := weekly.FirstClashWith(&tt.other, consts.TIMETABLE_CONFLICT_HORIZON)
+			if tt.clashOn == "" {
+				if clash {
+					t.Errorf("unexpected clash on %s", date.Format(consts.DATE_LAYOUT))
+				}
+				return
+			}
+			if !clash || date.Format(consts.DATE_LAYOUT) != tt.clashOn {
+				t.Errorf("clash = %v on %s, want %s", clash, date.Format(consts.DATE_LAYOUT), tt.clashOn)
+			}
+		})
+	}
+}